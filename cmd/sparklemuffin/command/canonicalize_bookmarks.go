@@ -0,0 +1,86 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package command
+
+import (
+	"context"
+
+	"github.com/earthboundkid/versioninfo/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCanonicalizeBookmarksCommand initializes and returns a new CLI command to canonicalize
+// the URLs of existing bookmarks.
+func NewCanonicalizeBookmarksCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "canonicalize-bookmarks",
+		Short: "Canonicalize the URLs of existing bookmarks and report duplicates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			log.Info().
+				Str("log_level", logLevelValue).
+				Str("version", versioninfo.Short()).
+				Bool("dry_run", dryRun).
+				Msg("bookmarks: canonicalizing URLs")
+
+			users, err := userService.All(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("bookmarks: failed to retrieve users")
+				return err
+			}
+
+			var canonicalized, duplicates int
+
+			for _, u := range users {
+				report, err := bookmarkService.CanonicalizeURLs(ctx, u.UUID, dryRun)
+				if err != nil {
+					log.Error().Err(err).Str("user_uuid", u.UUID).Msg("bookmarks: failed to canonicalize URLs")
+					return err
+				}
+
+				for _, duplicate := range report.Duplicates {
+					uids := make([]string, len(duplicate.Bookmarks))
+					urls := make([]string, len(duplicate.Bookmarks))
+
+					for i, b := range duplicate.Bookmarks {
+						uids[i] = b.UID
+						urls[i] = b.URL
+					}
+
+					log.Warn().
+						Str("user_uuid", u.UUID).
+						Str("nick_name", u.NickName).
+						Str("canonical_url", duplicate.CanonicalURL).
+						Strs("uids", uids).
+						Strs("urls", urls).
+						Msg("bookmarks: duplicate URLs left unchanged")
+				}
+
+				canonicalized += report.Canonicalized
+				duplicates += len(report.Duplicates)
+			}
+
+			log.Info().
+				Bool("dry_run", dryRun).
+				Int("canonicalized", canonicalized).
+				Int("duplicates", duplicates).
+				Msg("bookmarks: URLs canonicalized")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Report changes without updating bookmarks",
+	)
+
+	return cmd
+}
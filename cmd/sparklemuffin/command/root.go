@@ -25,24 +25,55 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/virtualtam/sparklemuffin/cmd/sparklemuffin/config"
+	"github.com/virtualtam/sparklemuffin/internal/blobstore"
+	"github.com/virtualtam/sparklemuffin/internal/hash"
 	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgaudit"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbookmark"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgfeed"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pginstance"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pglockout"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgpasskey"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgpasswordreset"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgquota"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgregistration"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgsavedsearch"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgsession"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgsso"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgtwofactor"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgwebhook"
 	"github.com/virtualtam/sparklemuffin/internal/version"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
 	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
 	bookmarkimporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/importing"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	bookmarksuggesting "github.com/virtualtam/sparklemuffin/pkg/bookmark/suggesting"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	feeddigesting "github.com/virtualtam/sparklemuffin/pkg/feed/digesting"
 	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedfetching "github.com/virtualtam/sparklemuffin/pkg/feed/fetching"
 	feedimporting "github.com/virtualtam/sparklemuffin/pkg/feed/importing"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
 	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	userexporting "github.com/virtualtam/sparklemuffin/pkg/user/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 const (
@@ -68,19 +99,51 @@ var (
 
 	pgxPool *pgxpool.Pool
 
+	// Secret key for HMAC token hashing. Populated by the root command.
+	hmacKey string
+
+	// Public address of the instance. Populated by the root command.
+	publicURL *url.URL
+
+	// Email notifier. Populated by the root command if an SMTP server is configured.
+	notifier notification.Notifier
+
+	auditService *audit.Service
+
 	bookmarkService          *bookmark.Service
+	bookmarkCheckingService  *bookmarkchecking.Service
 	bookmarkExportingService *bookmarkexporting.Service
 	bookmarkImportingService *bookmarkimporting.Service
 	bookmarkQueryingService  *bookmarkquerying.Service
 
-	feedService              *feed.Service
-	feedExportingService     *feedexporting.Service
-	feedImportingService     *feedimporting.Service
-	feedQueryingService      *feedquerying.Service
-	feedSynchronizingService *feedsynchronizing.Service
+	bookmarkSuggestingService *bookmarksuggesting.Service
+
+	bookmarkArchivingService *bookmarkarchiving.Service
 
-	sessionService *session.Service
-	userService    *user.Service
+	feedService               *feed.Service
+	feedAdministratingService *feedadministrating.Service
+	feedDigestingService      *feeddigesting.Service
+	feedExportingService      *feedexporting.Service
+	feedImportingService      *feedimporting.Service
+	feedQueryingService       *feedquerying.Service
+	feedSynchronizingService  *feedsynchronizing.Service
+
+	instanceService *instance.Service
+
+	lockoutService       *lockout.Service
+	passkeyService       *passkey.Service
+	passwordResetService *passwordreset.Service
+	quotaService         *quota.Service
+	registrationService  *registration.Service
+	sessionService       *session.Service
+	ssoService           *sso.Service
+	twoFactorService     *twofactor.Service
+	userService          *user.Service
+	userExportingService *userexporting.Service
+
+	savedSearchService *savedsearch.Service
+
+	webhookService *webhook.Service
 )
 
 // NewRootCommand initializes the main CLI entrypoint and common command flags.
@@ -90,12 +153,29 @@ func NewRootCommand() *cobra.Command {
 		defaultDatabaseSSLMode string = "disable"
 		defaultDatabaseName    string = "sparklemuffin"
 		defaultDatabaseUser    string = "sparklemuffin"
+		defaultPublicWebAddr   string = "http://localhost:8080"
 	)
 
 	var (
 		ctx = context.Background()
 
-		hmacKey string
+		publicWebAddr string
+
+		smtpConfig notification.SMTPConfig
+
+		passwordHasherConfig  = hash.DefaultPasswordHasherConfig()
+		passwordHashAlgorithm string
+
+		quotaLimits               quota.Limits
+		quotaMaxImportFileSizeMiB int64
+
+		bookmarkArchiveDir        string
+		bookmarkArchiveMaxSizeMiB int64
+
+		registrationModeValue         string
+		registrationEmailConfirmation bool
+
+		ssoConfig sso.Config
 	)
 
 	cmd := &cobra.Command{
@@ -179,6 +259,12 @@ func NewRootCommand() *cobra.Command {
 				Str("database_name", databaseName).
 				Msg("database: successfully created connection pool")
 
+			publicURL, err = url.Parse(publicWebAddr)
+			if err != nil {
+				log.Error().Err(err).Str("public_addr", publicWebAddr).Msg("global: failed to parse public HTTP address")
+				return err
+			}
+
 			// HTTP client used to perform requests
 			//
 			// Guarded against Server-Side Request Forgery by default: it
@@ -191,31 +277,113 @@ func NewRootCommand() *cobra.Command {
 			// is operating this instance.
 			userAgent := fmt.Sprintf("%s/%s", rootCmdName, versionDetails.Short)
 
+			bookmarkArchiveMaxSize := bookmarkArchiveMaxSizeMiB * 1024 * 1024
+
+			var bookmarkArchiveClient *bookmarkarchiving.Client
+			var bookmarkLinkClient *bookmarkchecking.Client
+			var bookmarkMetadataClient *bookmarksuggesting.Client
 			var feedClient *feedfetching.Client
+			var webhookClient *webhook.Client
 			if httpsafe.ProxyConfigured() {
 				log.Warn().Msg("feeds: HTTP(S) proxy detected in the environment, using a proxy-aware HTTP client with no built-in SSRF protection")
+				bookmarkArchiveClient = bookmarkarchiving.NewClient(&http.Client{Timeout: 60 * time.Second}, userAgent, bookmarkArchiveMaxSize)
+				bookmarkLinkClient = bookmarkchecking.NewClient(&http.Client{Timeout: 15 * time.Second}, userAgent)
+				bookmarkMetadataClient = bookmarksuggesting.NewClient(&http.Client{Timeout: 10 * time.Second}, userAgent)
 				feedClient = feedfetching.NewClient(&http.Client{Timeout: 30 * time.Second}, userAgent)
+				webhookClient = webhook.NewClient(&http.Client{Timeout: 10 * time.Second}, userAgent)
 			} else {
+				bookmarkArchiveClient, err = bookmarkarchiving.NewSafeClient(userAgent, 60*time.Second, bookmarkArchiveMaxSize)
+				if err != nil {
+					log.Error().Err(err).Msg("bookmarks: failed to create HTTP client")
+					return err
+				}
+
+				bookmarkLinkClient, err = bookmarkchecking.NewSafeClient(userAgent, 15*time.Second)
+				if err != nil {
+					log.Error().Err(err).Msg("bookmarks: failed to create HTTP client")
+					return err
+				}
+
+				bookmarkMetadataClient, err = bookmarksuggesting.NewSafeClient(userAgent, 10*time.Second)
+				if err != nil {
+					log.Error().Err(err).Msg("bookmarks: failed to create HTTP client")
+					return err
+				}
+
 				feedClient, err = feedfetching.NewSafeClient(userAgent, 30*time.Second)
 				if err != nil {
 					log.Error().Err(err).Msg("feeds: failed to create HTTP client")
 					return err
 				}
+
+				webhookClient, err = webhook.NewSafeClient(userAgent, 10*time.Second)
+				if err != nil {
+					log.Error().Err(err).Msg("webhooks: failed to create HTTP client")
+					return err
+				}
+			}
+
+			// Email notifications
+			if smtpConfig.Addr != "" {
+				notifier, err = notification.NewSMTPNotifier(smtpConfig)
+				if err != nil {
+					log.Error().Err(err).Msg("notification: failed to create SMTP notifier")
+					return err
+				}
+
+				log.Info().Str("smtp_addr", smtpConfig.Addr).Msg("notification: email notifications enabled")
+			} else {
+				log.Info().Msg("notification: no SMTP server configured, email notifications disabled")
 			}
 
 			// SparkleMuffin services
+			auditRepository := pgaudit.NewRepository(pgxPool)
+			auditService = audit.NewService(auditRepository)
+
+			// Storage quotas are unlimited by default; administrators may set per-user limits.
+			quotaLimits.MaxImportFileSize = quotaMaxImportFileSizeMiB * 1024 * 1024
+
+			quotaRepository := pgquota.NewRepository(pgxPool)
+			quotaService, err = quota.NewService(quotaRepository, quotaLimits)
+			if err != nil {
+				log.Error().Err(err).Msg("quota: failed to create quota service")
+				return err
+			}
+
 			bookmarkRepository := pgbookmark.NewRepository(pgxPool)
-			bookmarkService = bookmark.NewService(bookmarkRepository)
+			bookmarkService = bookmark.NewService(bookmarkRepository, quotaService)
+			bookmarkCheckingService = bookmarkchecking.NewService(bookmarkRepository, bookmarkService, bookmarkLinkClient)
 			bookmarkExportingService = bookmarkexporting.NewService(bookmarkRepository)
-			bookmarkImportingService = bookmarkimporting.NewService(bookmarkRepository)
+			bookmarkImportingService = bookmarkimporting.NewService(bookmarkRepository, quotaService)
 			bookmarkQueryingService = bookmarkquerying.NewService(bookmarkRepository)
+			bookmarkSuggestingService = bookmarksuggesting.NewService(bookmarkQueryingService, bookmarkMetadataClient)
+
+			// Page snapshots are stored in the database, unless a directory is configured.
+			var bookmarkArchiveBlobStore bookmarkarchiving.BlobStore = pgbookmark.NewArchiveBlobStore(pgxPool)
+			if bookmarkArchiveDir != "" {
+				bookmarkArchiveBlobStore, err = blobstore.NewFileSystem(bookmarkArchiveDir)
+				if err != nil {
+					log.Error().Err(err).Str("archive_dir", bookmarkArchiveDir).Msg("bookmarks: failed to create archive storage")
+					return err
+				}
+			}
+
+			bookmarkArchivingService = bookmarkarchiving.NewService(bookmarkRepository, bookmarkArchiveBlobStore, bookmarkService, bookmarkArchiveClient)
 
 			feedRepository := pgfeed.NewRepository(pgxPool)
-			feedService = feed.NewService(feedRepository, feedClient, httpsafe.ValidateURL)
+			feedService = feed.NewService(feedRepository, feedClient, httpsafe.ValidateURL, quotaService)
 			feedExportingService = feedexporting.NewService(feedRepository)
 			feedQueryingService = feedquerying.NewService(feedRepository)
-			feedImportingService = feedimporting.NewService(feedService)
+			feedImportingService = feedimporting.NewService(feedService, quotaService)
 			feedSynchronizingService = feedsynchronizing.NewService(feedRepository, feedClient, rootCmdName)
+			feedAdministratingService = feedadministrating.NewService(feedRepository, feedSynchronizingService, notifier)
+
+			instanceRepository := pginstance.NewRepository(pgxPool)
+			instanceService = instance.NewService(instanceRepository)
+
+			if notifier != nil {
+				feedDigestingService = feeddigesting.NewService(feedRepository, notifier)
+			}
 
 			sessionRepository := pgsession.NewRepository(ctx, pgxPool, quartz.NewReal())
 			sessionService, err = session.NewService(sessionRepository, hmacKey)
@@ -224,8 +392,91 @@ func NewRootCommand() *cobra.Command {
 				return err
 			}
 
+			twoFactorRepository := pgtwofactor.NewRepository(pgxPool)
+			twoFactorService, err = twofactor.NewService(twoFactorRepository, hmacKey)
+			if err != nil {
+				log.Error().Err(err).Msg("two-factor: failed to create two-factor authentication service")
+				return err
+			}
+
+			// New passwords are hashed with the configured algorithm; existing hashes are
+			// upgraded when their owner logs in.
+			passwordHasherConfig.Algorithm = hash.PasswordAlgorithm(passwordHashAlgorithm)
+
+			passwordHasher, err := hash.NewPasswordHasher(passwordHasherConfig)
+			if err != nil {
+				log.Error().Err(err).Msg("user: failed to create password hasher")
+				return err
+			}
+
 			userRepository := pguser.NewRepository(pgxPool)
-			userService = user.NewService(userRepository)
+			userService = user.NewService(userRepository, passwordHasher)
+			userExportingService = userexporting.NewService(bookmarkExportingService, feedExportingService, feedService)
+
+			lockoutRepository := pglockout.NewRepository(pgxPool)
+			lockoutService = lockout.NewService(lockoutRepository, userService, notifier)
+
+			if notifier != nil {
+				passwordResetRepository := pgpasswordreset.NewRepository(pgxPool)
+				passwordResetService, err = passwordreset.NewService(passwordResetRepository, userService, notifier, hmacKey)
+				if err != nil {
+					log.Error().Err(err).Msg("password-reset: failed to create password reset service")
+					return err
+				}
+			}
+
+			// Passkeys are bound to the public address of the instance.
+			passkeyRepository := pgpasskey.NewRepository(pgxPool)
+			passkeyService, err = passkey.NewService(passkeyRepository, userService, publicURL, hmacKey)
+			if err != nil {
+				log.Error().Err(err).Msg("passkey: failed to create passkey service")
+				return err
+			}
+
+			// Self-service registration is closed by default; email confirmation requires
+			// an SMTP server.
+			registrationMode, err := registration.ParseMode(registrationModeValue)
+			if err != nil {
+				log.Error().Err(err).Msg("registration: invalid registration mode")
+				return err
+			}
+
+			registrationConfig := registration.Config{
+				Mode:              registrationMode,
+				EmailConfirmation: registrationEmailConfirmation,
+			}
+
+			registrationRepository := pgregistration.NewRepository(pgxPool)
+			registrationService, err = registration.NewService(registrationRepository, userService, notifier, registrationConfig, hmacKey)
+			if err != nil {
+				log.Error().Err(err).Msg("registration: failed to create registration service")
+				return err
+			}
+
+			log.Info().
+				Str("mode", string(registrationConfig.Mode)).
+				Bool("email_confirmation", registrationConfig.EmailConfirmation).
+				Msg("registration: self-service registration configured")
+
+			// Single sign-on is optional, and redirects users back to the public address.
+			if ssoConfig.Enabled() {
+				ssoRepository := pgsso.NewRepository(pgxPool)
+				ssoRedirectURL := publicURL.JoinPath("/login/sso/callback")
+
+				ssoService, err = sso.NewService(ctx, ssoRepository, userService, ssoConfig, ssoRedirectURL, hmacKey)
+				if err != nil {
+					log.Error().Err(err).Msg("sso: failed to create single sign-on service")
+					return err
+				}
+
+				log.Info().Str("issuer_url", ssoConfig.IssuerURL).Msg("sso: OpenID Connect single sign-on enabled")
+			}
+
+			savedSearchRepository := pgsavedsearch.NewRepository(pgxPool)
+			savedSearchService = savedsearch.NewService(savedSearchRepository)
+
+			webhookRepository := pgwebhook.NewRepository(pgxPool)
+			webhookService = webhook.NewService(webhookRepository, webhookClient, httpsafe.ValidateURL)
 
 			return nil
 		},
@@ -285,5 +536,168 @@ func NewRootCommand() *cobra.Command {
 		"Secret key for HMAC session token hashing",
 	)
 
+	cmd.PersistentFlags().StringVar(
+		&publicWebAddr,
+		"public-addr",
+		defaultPublicWebAddr,
+		"Public HTTP address (if behind a proxy)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&smtpConfig.Addr,
+		"smtp-addr",
+		"",
+		"SMTP server address (host:port); email notifications are disabled if empty",
+	)
+	cmd.PersistentFlags().StringVar(
+		&smtpConfig.Username,
+		"smtp-username",
+		"",
+		"SMTP username",
+	)
+	cmd.PersistentFlags().StringVar(
+		&smtpConfig.Password,
+		"smtp-password",
+		"",
+		"SMTP password",
+	)
+	cmd.PersistentFlags().StringVar(
+		&smtpConfig.From,
+		"smtp-from",
+		"",
+		"Sender address for email notifications",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&smtpConfig.ImplicitTLS,
+		"smtp-implicit-tls",
+		false,
+		"Connect to the SMTP server over TLS instead of using STARTTLS",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&passwordHashAlgorithm,
+		"password-hash-algorithm",
+		string(hash.PasswordAlgorithmArgon2id),
+		"Algorithm used to hash new passwords (argon2id, bcrypt)",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&passwordHasherConfig.Argon2id.Memory,
+		"password-argon2id-memory",
+		passwordHasherConfig.Argon2id.Memory,
+		"Memory used to hash a password with Argon2id, in KiB",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&passwordHasherConfig.Argon2id.Iterations,
+		"password-argon2id-iterations",
+		passwordHasherConfig.Argon2id.Iterations,
+		"Number of Argon2id iterations",
+	)
+	cmd.PersistentFlags().Uint8Var(
+		&passwordHasherConfig.Argon2id.Parallelism,
+		"password-argon2id-parallelism",
+		passwordHasherConfig.Argon2id.Parallelism,
+		"Number of Argon2id threads",
+	)
+	cmd.PersistentFlags().IntVar(
+		&passwordHasherConfig.BcryptCost,
+		"password-bcrypt-cost",
+		passwordHasherConfig.BcryptCost,
+		"bcrypt cost, when using the bcrypt algorithm",
+	)
+
+	cmd.PersistentFlags().Int64Var(
+		&quotaLimits.MaxBookmarks,
+		"quota-max-bookmarks",
+		0,
+		"Maximum number of bookmarks per user (0: unlimited)",
+	)
+	cmd.PersistentFlags().Int64Var(
+		&quotaLimits.MaxSubscriptions,
+		"quota-max-subscriptions",
+		0,
+		"Maximum number of feed subscriptions per user (0: unlimited)",
+	)
+	cmd.PersistentFlags().Int64Var(
+		&quotaMaxImportFileSizeMiB,
+		"quota-max-import-file-size",
+		0,
+		"Maximum size of bookmark and feed import files, in MiB (0: unlimited)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&bookmarkArchiveDir,
+		"bookmark-archive-dir",
+		"",
+		"Directory where bookmarked page snapshots are stored; snapshots are stored in the database if empty",
+	)
+	cmd.PersistentFlags().Int64Var(
+		&bookmarkArchiveMaxSizeMiB,
+		"bookmark-archive-max-size",
+		bookmarkarchiving.DefaultMaxSnapshotBytes/(1024*1024),
+		"Maximum size of a bookmarked page snapshot, including images and stylesheets, in MiB",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&registrationModeValue,
+		"registration-mode",
+		string(registration.ModeClosed),
+		"Who may create an account: closed (administrators only), invite-only or open",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&registrationEmailConfirmation,
+		"registration-email-confirmation",
+		false,
+		"Require new users to confirm their email address before their account is created (requires an SMTP server)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.IssuerURL,
+		"oidc-issuer-url",
+		"",
+		"OpenID Connect issuer URL; enables single sign-on when set",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.ClientID,
+		"oidc-client-id",
+		"",
+		"OpenID Connect client ID",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.ClientSecret,
+		"oidc-client-secret",
+		"",
+		"OpenID Connect client secret",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&ssoConfig.Scopes,
+		"oidc-scopes",
+		sso.DefaultScopes,
+		"OpenID Connect scopes to request",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.ProviderName,
+		"oidc-provider-name",
+		sso.DefaultProviderName,
+		"Identity provider name, displayed on the login page",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&ssoConfig.Provisioning,
+		"oidc-provisioning",
+		false,
+		"Create accounts for unknown users on their first single sign-on login",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.AdminGroup,
+		"oidc-admin-group",
+		"",
+		"Group granting administration privileges; if set, privileges are synchronized on every login",
+	)
+	cmd.PersistentFlags().StringVar(
+		&ssoConfig.GroupsClaim,
+		"oidc-groups-claim",
+		sso.DefaultGroupsClaim,
+		"ID token claim listing the groups a user belongs to",
+	)
+
 	return cmd
 }
@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"sync"
 	"time"
 
@@ -17,20 +16,22 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/http/monitoring"
 	"github.com/virtualtam/sparklemuffin/internal/http/www"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
+	feeddigesting "github.com/virtualtam/sparklemuffin/pkg/feed/digesting"
 	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 // NewRunCommand initializes a CLI command to start the HTTP servers.
 func NewRunCommand() *cobra.Command {
 	const (
 		defaultWebListenAddr        string = "0.0.0.0:8080"
-		defaultPublicWebAddr        string = "http://localhost:8080"
 		defaultMonitoringListenAddr string = "0.0.0.0:8090"
 	)
 
 	var (
 		webListenAddr        string
-		publicWebAddr        string
 		monitoringListenAddr string
 
 		clientIpHeader string
@@ -46,6 +47,20 @@ func NewRunCommand() *cobra.Command {
 				Msg("global: setting up services")
 
 			// Periodic tasks
+			var bookmarkArchivingLocker sync.Mutex
+			bookmarkArchivingScheduler := bookmarkarchiving.NewScheduler(
+				bookmarkArchivingService,
+				&bookmarkArchivingLocker,
+			)
+			go bookmarkArchivingScheduler.Run(context.Background())
+
+			var bookmarkCheckingLocker sync.Mutex
+			bookmarkCheckingScheduler := bookmarkchecking.NewScheduler(
+				bookmarkCheckingService,
+				&bookmarkCheckingLocker,
+			)
+			go bookmarkCheckingScheduler.Run(context.Background())
+
 			var feedSynchronizingLocker sync.Mutex
 			feedSynchronizingScheduler := feedsynchronizing.NewScheduler(
 				feedSynchronizingService,
@@ -53,6 +68,22 @@ func NewRunCommand() *cobra.Command {
 			)
 			go feedSynchronizingScheduler.Run(context.Background())
 
+			var webhookDispatchLocker sync.Mutex
+			webhookScheduler := webhook.NewScheduler(
+				webhookService,
+				&webhookDispatchLocker,
+			)
+			go webhookScheduler.Run(context.Background())
+
+			if feedDigestingService != nil {
+				var feedDigestingLocker sync.Mutex
+				feedDigestingScheduler := feeddigesting.NewScheduler(
+					feedDigestingService,
+					&feedDigestingLocker,
+				)
+				go feedDigestingScheduler.Run(context.Background())
+			}
+
 			// HTTP - Monitoring server
 			monitoringServer, metricsRegistry := monitoring.NewServer(rootCmdName, monitoringListenAddr, versionDetails)
 			metricsRegistry.MustRegister(feedSynchronizingService.Collector())
@@ -66,29 +97,41 @@ func NewRunCommand() *cobra.Command {
 			}()
 
 			// HTTP - SparkleMuffin server
-			publicURL, err := url.Parse(publicWebAddr)
-			if err != nil {
-				return fmt.Errorf("%s: failed to parse public HTTP address: %w", rootCmdName, err)
-			}
-
 			server, err := www.NewServer(
 				www.WithMetricsRegistry(rootCmdName, metricsRegistry),
 				www.WithPublicURL(publicURL),
 				www.WithClientIpHeader(clientIpHeader),
+				www.WithAuditService(auditService),
 				www.WithBookmarkServices(
 					bookmarkService,
 					bookmarkExportingService,
 					bookmarkImportingService,
 					bookmarkQueryingService,
 				),
+				www.WithBookmarkCheckingService(bookmarkCheckingService),
+				www.WithBookmarkSuggestingService(bookmarkSuggestingService),
+				www.WithBookmarkArchivingService(bookmarkArchivingService),
 				www.WithFeedServices(
 					feedService,
 					feedExportingService,
 					feedImportingService,
 					feedQueryingService,
 				),
+				www.WithFeedAdministratingService(feedAdministratingService),
+				www.WithFeedSynchronizingCollector(feedSynchronizingService.Collector()),
+				www.WithInstanceService(instanceService),
+				www.WithLockoutService(lockoutService),
+				www.WithPasskeyService(passkeyService),
+				www.WithPasswordResetService(passwordResetService),
+				www.WithQuotaService(quotaService),
+				www.WithRegistrationService(registrationService),
+				www.WithSavedSearchService(savedSearchService),
 				www.WithSessionService(sessionService),
+				www.WithSSOService(ssoService),
+				www.WithTwoFactorService(twoFactorService),
 				www.WithUserService(userService),
+				www.WithUserExportingService(userExportingService),
+				www.WithWebhookService(webhookService),
 			)
 			if err != nil {
 				return fmt.Errorf("%s: failed to create server: %w", rootCmdName, err)
@@ -120,13 +163,6 @@ func NewRunCommand() *cobra.Command {
 		"Listen to this address for monitoring (host:port)",
 	)
 
-	cmd.Flags().StringVar(
-		&publicWebAddr,
-		"public-addr",
-		defaultPublicWebAddr,
-		"Public HTTP address (if behind a proxy)",
-	)
-
 	cmd.Flags().StringVar(
 		&clientIpHeader,
 		"client-ip-header",
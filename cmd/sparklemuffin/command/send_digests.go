@@ -0,0 +1,35 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/earthboundkid/versioninfo/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewSendDigestsCommand initializes and returns a new CLI command to send feed digests that are due.
+func NewSendDigestsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send-digests",
+		Short: "Send email digests of unread feed entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if feedDigestingService == nil {
+				return errors.New("digests: an SMTP server must be configured to send digests")
+			}
+
+			log.Info().
+				Str("log_level", logLevelValue).
+				Str("version", versioninfo.Short()).
+				Msg("digests: sending")
+
+			return feedDigestingService.Send(context.Background(), "cli")
+		},
+	}
+
+	return cmd
+}
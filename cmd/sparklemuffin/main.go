@@ -13,9 +13,11 @@ func main() {
 	rootCommand := command.NewRootCommand()
 
 	commands := []*cobra.Command{
+		command.NewCanonicalizeBookmarksCommand(),
 		command.NewCreateAdminUserCommand(),
 		command.NewMigrateCommand(),
 		command.NewRunCommand(),
+		command.NewSendDigestsCommand(),
 		command.NewSyncFeedsCommand(),
 		command.NewVersionCommand(),
 	}
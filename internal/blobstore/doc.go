@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package blobstore provides content-addressed storage for binary objects on the local filesystem.
+package blobstore
@@ -0,0 +1,11 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package blobstore
+
+import "errors"
+
+var (
+	ErrBlobNotFound    = errors.New("blobstore: blob not found")
+	ErrBlobHashInvalid = errors.New("blobstore: invalid blob hash")
+)
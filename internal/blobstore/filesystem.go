@@ -0,0 +1,161 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package blobstore
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	dirPermissions  fs.FileMode = 0o750
+	filePermissions fs.FileMode = 0o640
+)
+
+// A FileSystem stores blobs as files in a directory, named after their hexadecimal hash.
+//
+// Files are sharded in sub-directories named after the first two characters of their hash.
+type FileSystem struct {
+	root string
+}
+
+// NewFileSystem initializes and returns a FileSystem storing blobs in a given directory,
+// which is created if it does not exist.
+func NewFileSystem(root string) (*FileSystem, error) {
+	if err := os.MkdirAll(root, dirPermissions); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create directory: %w", err)
+	}
+
+	return &FileSystem{root: root}, nil
+}
+
+// BlobDelete deletes the blob with a given hash.
+//
+// Deleting a blob that does not exist is a no-op.
+func (f *FileSystem) BlobDelete(_ context.Context, hash string) error {
+	path, err := f.path(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blobstore: failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// BlobGet returns the content of the blob with a given hash.
+func (f *FileSystem) BlobGet(_ context.Context, hash string) ([]byte, error) {
+	path, err := f.path(hash)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []byte{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return []byte{}, fmt.Errorf("blobstore: failed to read blob: %w", err)
+	}
+
+	return content, nil
+}
+
+// BlobHashes returns the hashes of all stored blobs.
+//
+// Temporary files left by an interrupted save are ignored.
+func (f *FileSystem) BlobHashes(_ context.Context) ([]string, error) {
+	var hashes []string
+
+	err := filepath.WalkDir(f.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		hash := d.Name()
+
+		if expected, err := f.path(hash); err != nil || expected != path {
+			return nil
+		}
+
+		hashes = append(hashes, hash)
+		return nil
+	})
+	if err != nil {
+		return []string{}, fmt.Errorf("blobstore: failed to list blobs: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// BlobSave stores the content of a blob under a given hash.
+//
+// As blobs are content-addressed, saving a blob that already exists is a no-op.
+func (f *FileSystem) BlobSave(_ context.Context, hash string, content []byte) error {
+	path, err := f.path(hash)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("blobstore: failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file first, so that a partially written blob is never exposed.
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+hash)
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("blobstore: failed to write blob: %w", err)
+	}
+
+	if err := tmpFile.Chmod(filePermissions); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("blobstore: failed to set blob permissions: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("blobstore: failed to write blob: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("blobstore: failed to save blob: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the path of the file storing the blob with a given hash.
+func (f *FileSystem) path(hash string) (string, error) {
+	if len(hash) < 2 {
+		return "", ErrBlobHashInvalid
+	}
+
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", ErrBlobHashInvalid
+	}
+
+	return filepath.Join(f.root, hash[:2], hash), nil
+}
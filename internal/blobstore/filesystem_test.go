@@ -0,0 +1,100 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package blobstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFileSystem(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+
+	store, err := NewFileSystem(root)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	const hash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	content := []byte("hello")
+
+	t.Run("get missing blob", func(t *testing.T) {
+		_, err := store.BlobGet(t.Context(), hash)
+		if !errors.Is(err, ErrBlobNotFound) {
+			t.Fatalf("want error %q, got %q", ErrBlobNotFound, err)
+		}
+	})
+
+	t.Run("save and get blob", func(t *testing.T) {
+		if err := store.BlobSave(t.Context(), hash, content); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		// Saving the same blob twice is a no-op.
+		if err := store.BlobSave(t.Context(), hash, content); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := store.BlobGet(t.Context(), hash)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if string(got) != string(content) {
+			t.Errorf("want content %q, got %q", content, got)
+		}
+
+		if _, err := os.Stat(filepath.Join(root, hash[:2], hash)); err != nil {
+			t.Errorf("want blob to be stored in a sharded directory, got %q", err)
+		}
+	})
+
+	t.Run("list blobs", func(t *testing.T) {
+		// Leftovers from an interrupted save are not blobs.
+		if err := os.WriteFile(filepath.Join(root, hash[:2], ".tmp-"+hash+"123"), content, filePermissions); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := store.BlobHashes(t.Context())
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if !slices.Equal(got, []string{hash}) {
+			t.Errorf("want hashes %q, got %q", []string{hash}, got)
+		}
+	})
+
+	t.Run("delete blob", func(t *testing.T) {
+		if err := store.BlobDelete(t.Context(), hash); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		// Deleting a missing blob is a no-op.
+		if err := store.BlobDelete(t.Context(), hash); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if _, err := store.BlobGet(t.Context(), hash); !errors.Is(err, ErrBlobNotFound) {
+			t.Fatalf("want error %q, got %q", ErrBlobNotFound, err)
+		}
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		for _, invalidHash := range []string{"", "a", "../../etc/passwd", "not-hexadecimal"} {
+			if _, err := store.BlobGet(t.Context(), invalidHash); !errors.Is(err, ErrBlobHashInvalid) {
+				t.Errorf("BlobGet(%q): want error %q, got %q", invalidHash, ErrBlobHashInvalid, err)
+			}
+			if err := store.BlobSave(t.Context(), invalidHash, content); !errors.Is(err, ErrBlobHashInvalid) {
+				t.Errorf("BlobSave(%q): want error %q, got %q", invalidHash, ErrBlobHashInvalid, err)
+			}
+			if err := store.BlobDelete(t.Context(), invalidHash); !errors.Is(err, ErrBlobHashInvalid) {
+				t.Errorf("BlobDelete(%q): want error %q, got %q", invalidHash, ErrBlobHashInvalid, err)
+			}
+		}
+	})
+}
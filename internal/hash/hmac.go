@@ -1,8 +1,8 @@
 // Copyright VirtualTam 2022, 2026
 // SPDX-License-Identifier: MIT
 
-// Package hash provides hashing helpers to generate application tokens that can
-// be used in a web application.
+// Package hash provides hashing helpers to generate application tokens and
+// password hashes that can be used in a web application.
 package hash
 
 import (
@@ -0,0 +1,328 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordAlgorithm identifies a password hashing algorithm.
+type PasswordAlgorithm string
+
+const (
+	PasswordAlgorithmArgon2id PasswordAlgorithm = "argon2id"
+	PasswordAlgorithmBcrypt   PasswordAlgorithm = "bcrypt"
+)
+
+const (
+	argon2idSaltLength uint32 = 16
+	argon2idKeyLength  uint32 = 32
+)
+
+var (
+	ErrPasswordAlgorithmUnsupported = errors.New("password hasher: unsupported algorithm")
+	ErrPasswordHashInvalid          = errors.New("password hasher: invalid password hash")
+	ErrPasswordHasherConfigInvalid  = errors.New("password hasher: invalid configuration")
+	ErrPasswordMismatch             = errors.New("password hasher: password does not match hash")
+)
+
+// Argon2idParams holds the cost parameters of the Argon2id algorithm.
+type Argon2idParams struct {
+	// Memory is the amount of memory used by the algorithm, in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// PasswordHasherConfig holds the algorithm used to hash new passwords, and its parameters.
+type PasswordHasherConfig struct {
+	Algorithm  PasswordAlgorithm
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+// DefaultPasswordHasherConfig returns the default password hashing configuration,
+// using Argon2id with the parameters recommended by the OWASP Password Storage Cheat Sheet.
+func DefaultPasswordHasherConfig() PasswordHasherConfig {
+	return PasswordHasherConfig{
+		Algorithm: PasswordAlgorithmArgon2id,
+		Argon2id: Argon2idParams{
+			Memory:      19 * 1024,
+			Iterations:  2,
+			Parallelism: 1,
+		},
+		BcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+// Validate ensures the parameters of the configured algorithm are within accepted bounds.
+func (c PasswordHasherConfig) Validate() error {
+	switch c.Algorithm {
+	case PasswordAlgorithmArgon2id:
+		if c.Argon2id.Iterations < 1 || c.Argon2id.Parallelism < 1 {
+			return fmt.Errorf("%w: argon2id iterations and parallelism must be at least 1", ErrPasswordHasherConfigInvalid)
+		}
+		if c.Argon2id.Memory < 8*uint32(c.Argon2id.Parallelism) {
+			return fmt.Errorf("%w: argon2id memory must be at least 8 KiB per thread", ErrPasswordHasherConfigInvalid)
+		}
+
+	case PasswordAlgorithmBcrypt:
+		if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf(
+				"%w: bcrypt cost must be between %d and %d",
+				ErrPasswordHasherConfigInvalid,
+				bcrypt.MinCost,
+				bcrypt.MaxCost,
+			)
+		}
+
+	default:
+		return fmt.Errorf("%w: %q", ErrPasswordAlgorithmUnsupported, c.Algorithm)
+	}
+
+	return nil
+}
+
+// passwordScheme hashes and verifies passwords with a given algorithm.
+//
+// Hashes are self-describing: they embed the algorithm and the parameters they were
+// computed with, so they can still be verified after the configuration changes.
+type passwordScheme interface {
+	algorithm() PasswordAlgorithm
+	compare(passwordHash string, password string) error
+	hash(password string) (string, error)
+	identifies(passwordHash string) bool
+	outdated(passwordHash string) bool
+}
+
+// PasswordHasher hashes new passwords with the configured algorithm, and verifies
+// passwords against hashes computed with any supported algorithm.
+type PasswordHasher struct {
+	current passwordScheme
+	schemes []passwordScheme
+}
+
+// NewPasswordHasher initializes and returns a PasswordHasher.
+func NewPasswordHasher(config PasswordHasherConfig) (*PasswordHasher, error) {
+	if err := config.Validate(); err != nil {
+		return &PasswordHasher{}, err
+	}
+
+	h := &PasswordHasher{
+		schemes: []passwordScheme{
+			&argon2idScheme{params: config.Argon2id},
+			&bcryptScheme{cost: config.BcryptCost},
+		},
+	}
+
+	for _, scheme := range h.schemes {
+		if scheme.algorithm() == config.Algorithm {
+			h.current = scheme
+		}
+	}
+
+	return h, nil
+}
+
+// Algorithm returns the algorithm used to hash new passwords.
+func (h *PasswordHasher) Algorithm() PasswordAlgorithm {
+	return h.current.algorithm()
+}
+
+// Hash returns the hash of a clear-text password.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	return h.current.hash(password)
+}
+
+// Compare checks a clear-text password against a hash, and returns ErrPasswordMismatch
+// if they do not match.
+func (h *PasswordHasher) Compare(passwordHash string, password string) error {
+	scheme, err := h.schemeFor(passwordHash)
+	if err != nil {
+		return err
+	}
+
+	return scheme.compare(passwordHash, password)
+}
+
+// NeedsRehash returns whether a hash was computed with another algorithm, or other
+// parameters, than the ones currently configured.
+func (h *PasswordHasher) NeedsRehash(passwordHash string) bool {
+	scheme, err := h.schemeFor(passwordHash)
+	if err != nil {
+		return false
+	}
+
+	if scheme != h.current {
+		return true
+	}
+
+	return scheme.outdated(passwordHash)
+}
+
+func (h *PasswordHasher) schemeFor(passwordHash string) (passwordScheme, error) {
+	for _, scheme := range h.schemes {
+		if scheme.identifies(passwordHash) {
+			return scheme, nil
+		}
+	}
+
+	return nil, ErrPasswordHashInvalid
+}
+
+var _ passwordScheme = &argon2idScheme{}
+
+// argon2idScheme hashes passwords with Argon2id, encoded in the PHC string format:
+//
+//	$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type argon2idScheme struct {
+	params Argon2idParams
+}
+
+func (s *argon2idScheme) algorithm() PasswordAlgorithm {
+	return PasswordAlgorithmArgon2id
+}
+
+func (s *argon2idScheme) compare(passwordHash string, password string) error {
+	params, salt, key, err := s.decode(passwordHash)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+func (s *argon2idScheme) hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, s.params.Iterations, s.params.Memory, s.params.Parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		s.params.Memory,
+		s.params.Iterations,
+		s.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (s *argon2idScheme) identifies(passwordHash string) bool {
+	return strings.HasPrefix(passwordHash, "$argon2id$")
+}
+
+func (s *argon2idScheme) outdated(passwordHash string) bool {
+	params, _, key, err := s.decode(passwordHash)
+	if err != nil {
+		return false
+	}
+
+	return params != s.params || uint32(len(key)) != argon2idKeyLength
+}
+
+func (s *argon2idScheme) decode(passwordHash string) (Argon2idParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(passwordHash, "$")
+	if len(parts) != 6 {
+		return Argon2idParams{}, nil, nil, ErrPasswordHashInvalid
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrPasswordHashInvalid, err)
+	}
+	if version != argon2.Version {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: unsupported argon2 version %d", ErrPasswordHashInvalid, version)
+	}
+
+	var params Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrPasswordHashInvalid, err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrPasswordHashInvalid, err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrPasswordHashInvalid, err)
+	}
+	if len(key) == 0 {
+		return Argon2idParams{}, nil, nil, ErrPasswordHashInvalid
+	}
+
+	return params, salt, key, nil
+}
+
+var _ passwordScheme = &bcryptScheme{}
+
+// bcryptScheme hashes passwords with bcrypt, using the Modular Crypt Format:
+//
+//	$2a$<cost>$<salt and key>
+type bcryptScheme struct {
+	cost int
+}
+
+func (s *bcryptScheme) algorithm() PasswordAlgorithm {
+	return PasswordAlgorithmBcrypt
+}
+
+func (s *bcryptScheme) compare(passwordHash string, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	} else if err != nil {
+		return fmt.Errorf("%w: %w", ErrPasswordHashInvalid, err)
+	}
+
+	return nil
+}
+
+func (s *bcryptScheme) hash(password string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(h), nil
+}
+
+func (s *bcryptScheme) identifies(passwordHash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(passwordHash, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *bcryptScheme) outdated(passwordHash string) bool {
+	cost, err := bcrypt.Cost([]byte(passwordHash))
+	if err != nil {
+		return false
+	}
+
+	return cost != s.cost
+}
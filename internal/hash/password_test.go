@@ -0,0 +1,271 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package hash
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2idParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+}
+
+func newTestPasswordHasher(t *testing.T, algorithm PasswordAlgorithm) *PasswordHasher {
+	t.Helper()
+
+	h, err := NewPasswordHasher(PasswordHasherConfig{
+		Algorithm:  algorithm,
+		Argon2id:   testArgon2idParams,
+		BcryptCost: bcrypt.MinCost,
+	})
+	if err != nil {
+		t.Fatalf("failed to create password hasher: %q", err)
+	}
+
+	return h
+}
+
+func TestPasswordHasherConfigValidate(t *testing.T) {
+	cases := []struct {
+		tname   string
+		config  PasswordHasherConfig
+		wantErr error
+	}{
+		{
+			tname:  "default",
+			config: DefaultPasswordHasherConfig(),
+		},
+		{
+			tname: "bcrypt",
+			config: PasswordHasherConfig{
+				Algorithm:  PasswordAlgorithmBcrypt,
+				BcryptCost: bcrypt.DefaultCost,
+			},
+		},
+		{
+			tname: "unsupported algorithm",
+			config: PasswordHasherConfig{
+				Algorithm: "md5",
+			},
+			wantErr: ErrPasswordAlgorithmUnsupported,
+		},
+		{
+			tname: "argon2id: no iterations",
+			config: PasswordHasherConfig{
+				Algorithm: PasswordAlgorithmArgon2id,
+				Argon2id:  Argon2idParams{Memory: 1024, Parallelism: 1},
+			},
+			wantErr: ErrPasswordHasherConfigInvalid,
+		},
+		{
+			tname: "argon2id: not enough memory",
+			config: PasswordHasherConfig{
+				Algorithm: PasswordAlgorithmArgon2id,
+				Argon2id:  Argon2idParams{Memory: 16, Iterations: 1, Parallelism: 4},
+			},
+			wantErr: ErrPasswordHasherConfigInvalid,
+		},
+		{
+			tname: "bcrypt: cost too high",
+			config: PasswordHasherConfig{
+				Algorithm:  PasswordAlgorithmBcrypt,
+				BcryptCost: bcrypt.MaxCost + 1,
+			},
+			wantErr: ErrPasswordHasherConfigInvalid,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			err := tc.config.Validate()
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("want no error, got %q", err)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherHash(t *testing.T) {
+	cases := []struct {
+		tname      string
+		algorithm  PasswordAlgorithm
+		wantPrefix string
+	}{
+		{
+			tname:      "argon2id",
+			algorithm:  PasswordAlgorithmArgon2id,
+			wantPrefix: "$argon2id$v=19$m=64,t=1,p=1$",
+		},
+		{
+			tname:      "bcrypt",
+			algorithm:  PasswordAlgorithmBcrypt,
+			wantPrefix: "$2a$04$",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			h := newTestPasswordHasher(t, tc.algorithm)
+
+			passwordHash, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if !strings.HasPrefix(passwordHash, tc.wantPrefix) {
+				t.Errorf("want hash prefixed with %q, got %q", tc.wantPrefix, passwordHash)
+			}
+
+			if err := h.Compare(passwordHash, "correct horse battery staple"); err != nil {
+				t.Errorf("want password to match, got %q", err)
+			}
+
+			if err := h.Compare(passwordHash, "incorrect horse"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("want error %q, got %q", ErrPasswordMismatch, err)
+			}
+
+			if h.NeedsRehash(passwordHash) {
+				t.Error("want a fresh hash not to need rehashing")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherCompare(t *testing.T) {
+	argon2idHasher := newTestPasswordHasher(t, PasswordAlgorithmArgon2id)
+	bcryptHasher := newTestPasswordHasher(t, PasswordAlgorithmBcrypt)
+
+	bcryptHash, err := bcryptHasher.Hash("hunter22")
+	if err != nil {
+		t.Fatalf("failed to hash password: %q", err)
+	}
+
+	t.Run("legacy bcrypt hash", func(t *testing.T) {
+		if err := argon2idHasher.Compare(bcryptHash, "hunter22"); err != nil {
+			t.Errorf("want password to match, got %q", err)
+		}
+	})
+
+	cases := []struct {
+		tname        string
+		passwordHash string
+	}{
+		{
+			tname:        "empty",
+			passwordHash: "",
+		},
+		{
+			tname:        "unknown algorithm",
+			passwordHash: "$1$salt$hash",
+		},
+		{
+			tname:        "truncated argon2id hash",
+			passwordHash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		},
+		{
+			tname:        "unsupported argon2 version",
+			passwordHash: "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		},
+		{
+			tname:        "invalid argon2id parameters",
+			passwordHash: "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			err := argon2idHasher.Compare(tc.passwordHash, "hunter22")
+			if !errors.Is(err, ErrPasswordHashInvalid) {
+				t.Errorf("want error %q, got %q", ErrPasswordHashInvalid, err)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	argon2idHasher := newTestPasswordHasher(t, PasswordAlgorithmArgon2id)
+	bcryptHasher := newTestPasswordHasher(t, PasswordAlgorithmBcrypt)
+
+	argon2idHash, err := argon2idHasher.Hash("hunter22")
+	if err != nil {
+		t.Fatalf("failed to hash password: %q", err)
+	}
+
+	bcryptHash, err := bcryptHasher.Hash("hunter22")
+	if err != nil {
+		t.Fatalf("failed to hash password: %q", err)
+	}
+
+	strongerArgon2idHasher, err := NewPasswordHasher(PasswordHasherConfig{
+		Algorithm: PasswordAlgorithmArgon2id,
+		Argon2id: Argon2idParams{
+			Memory:      128,
+			Iterations:  2,
+			Parallelism: 1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create password hasher: %q", err)
+	}
+
+	cases := []struct {
+		tname        string
+		hasher       *PasswordHasher
+		passwordHash string
+		want         bool
+	}{
+		{
+			tname:        "argon2id hash, argon2id hasher",
+			hasher:       argon2idHasher,
+			passwordHash: argon2idHash,
+			want:         false,
+		},
+		{
+			tname:        "bcrypt hash, argon2id hasher",
+			hasher:       argon2idHasher,
+			passwordHash: bcryptHash,
+			want:         true,
+		},
+		{
+			tname:        "argon2id hash, bcrypt hasher",
+			hasher:       bcryptHasher,
+			passwordHash: argon2idHash,
+			want:         true,
+		},
+		{
+			tname:        "argon2id hash, argon2id hasher with other parameters",
+			hasher:       strongerArgon2idHasher,
+			passwordHash: argon2idHash,
+			want:         true,
+		},
+		{
+			tname:        "invalid hash",
+			hasher:       argon2idHasher,
+			passwordHash: "not-a-hash",
+			want:         false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			if got := tc.hasher.NeedsRehash(tc.passwordHash); got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
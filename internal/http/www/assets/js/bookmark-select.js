@@ -0,0 +1,45 @@
+/**
+ * Bookmark Selection
+ *
+ * Toggles every bookmark checkbox of the bookmark list page when the
+ * "select all" checkbox of the bulk edit form is toggled, and keeps the
+ * latter in sync when bookmarks are selected one by one.
+ *
+ * Listens on the document rather than on the checkboxes themselves, as the
+ * bookmark list is replaced by htmx when searching or changing pages.
+ *
+ * Usage:
+ *   <input type="checkbox" id="bookmark-select-all">
+ *   <input type="checkbox" name="uids" value="..." form="bookmark-bulk-form">
+ *
+ * Copyright VirtualTam 2022, 2026
+ * SPDX-License-Identifier: MIT
+ */
+const selectAllId = "bookmark-select-all";
+const checkboxSelector = 'input[type="checkbox"][name="uids"]';
+
+document.addEventListener("change", (event) => {
+    const target = event.target;
+
+    if (target.id === selectAllId) {
+        document.querySelectorAll(checkboxSelector).forEach((checkbox) => {
+            checkbox.checked = target.checked;
+        });
+        return;
+    }
+
+    if (!target.matches(checkboxSelector)) {
+        return;
+    }
+
+    const selectAll = document.getElementById(selectAllId);
+    if (!selectAll) {
+        return;
+    }
+
+    const checkboxes = Array.from(document.querySelectorAll(checkboxSelector));
+    const checked = checkboxes.filter((checkbox) => checkbox.checked).length;
+
+    selectAll.checked = checked === checkboxes.length;
+    selectAll.indeterminate = checked > 0 && checked < checkboxes.length;
+});
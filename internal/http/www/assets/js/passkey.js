@@ -0,0 +1,176 @@
+/**
+ * Passkeys
+ *
+ * Drives the WebAuthn ceremonies for passkey registration (account passkeys
+ * page, form#passkey-register) and passkey login (login page,
+ * button#passkey-login). Each ceremony is a pair of JSON requests: "begin"
+ * returns the options passed to the browser's WebAuthn API, "finish" sends
+ * back the authenticator's response for verification. The server keeps the
+ * ceremony state, referenced by an HttpOnly cookie.
+ *
+ * WebAuthn deals with binary buffers, which the server encodes as unpadded
+ * base64url strings, hence the conversion helpers below.
+ *
+ * Elements are only wired up when present, so this script is a no-op on
+ * pages without them, and when the browser does not support WebAuthn.
+ *
+ * Copyright VirtualTam 2022, 2026
+ * SPDX-License-Identifier: MIT
+ */
+
+function base64URLToBuffer(value) {
+    const base64 = value.replace(/-/g, "+").replace(/_/g, "/");
+    const padded = base64.padEnd(base64.length + ((4 - (base64.length % 4)) % 4), "=");
+    const binary = atob(padded);
+
+    return Uint8Array.from(binary, (c) => c.charCodeAt(0)).buffer;
+}
+
+function bufferToBase64URL(buffer) {
+    const binary = String.fromCharCode(...new Uint8Array(buffer));
+
+    return btoa(binary).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+}
+
+function decodeCredentialDescriptors(descriptors) {
+    return (descriptors || []).map((descriptor) => ({
+        ...descriptor,
+        id: base64URLToBuffer(descriptor.id),
+    }));
+}
+
+function decodeCreationOptions(options) {
+    const publicKey = options.publicKey;
+
+    return {
+        publicKey: {
+            ...publicKey,
+            challenge: base64URLToBuffer(publicKey.challenge),
+            user: {
+                ...publicKey.user,
+                id: base64URLToBuffer(publicKey.user.id),
+            },
+            excludeCredentials: decodeCredentialDescriptors(publicKey.excludeCredentials),
+        },
+    };
+}
+
+function decodeRequestOptions(options) {
+    const publicKey = options.publicKey;
+
+    return {
+        publicKey: {
+            ...publicKey,
+            challenge: base64URLToBuffer(publicKey.challenge),
+            allowCredentials: decodeCredentialDescriptors(publicKey.allowCredentials),
+        },
+    };
+}
+
+function encodeCredential(credential) {
+    const response = {
+        clientDataJSON: bufferToBase64URL(credential.response.clientDataJSON),
+    };
+
+    if (credential.response.attestationObject) {
+        response.attestationObject = bufferToBase64URL(credential.response.attestationObject);
+        if (credential.response.getTransports) {
+            response.transports = credential.response.getTransports();
+        }
+    } else {
+        response.authenticatorData = bufferToBase64URL(credential.response.authenticatorData);
+        response.signature = bufferToBase64URL(credential.response.signature);
+        if (credential.response.userHandle) {
+            response.userHandle = bufferToBase64URL(credential.response.userHandle);
+        }
+    }
+
+    return {
+        id: credential.id,
+        rawId: bufferToBase64URL(credential.rawId),
+        type: credential.type,
+        authenticatorAttachment: credential.authenticatorAttachment,
+        clientExtensionResults: credential.getClientExtensionResults(),
+        response: response,
+    };
+}
+
+async function postJSON(url, body) {
+    const response = await fetch(url, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: body === undefined ? undefined : JSON.stringify(body),
+        credentials: "same-origin",
+    });
+
+    if (!response.ok) {
+        const message = (await response.text()).trim();
+        throw new Error(message || response.statusText);
+    }
+
+    return response;
+}
+
+function showError(error) {
+    const alert = document.getElementById("passkey-error");
+    if (!alert) {
+        return;
+    }
+
+    // The user dismissed the browser prompt, or it timed out.
+    if (error.name === "NotAllowedError" || error.name === "AbortError") {
+        alert.textContent = "The passkey request was cancelled.";
+    } else {
+        alert.textContent = error.message;
+    }
+
+    alert.classList.remove("d-none");
+}
+
+async function registerPasskey(form) {
+    const beginResponse = await postJSON("/account/passkeys/register/begin");
+    const options = decodeCreationOptions(await beginResponse.json());
+
+    const credential = await navigator.credentials.create(options);
+
+    await postJSON("/account/passkeys/register/finish", {
+        name: form.elements["name"].value,
+        credential: encodeCredential(credential),
+    });
+
+    window.location.reload();
+}
+
+async function loginWithPasskey() {
+    const beginResponse = await postJSON("/login/passkey/begin");
+    const options = decodeRequestOptions(await beginResponse.json());
+
+    const credential = await navigator.credentials.get(options);
+
+    await postJSON("/login/passkey/finish", encodeCredential(credential));
+
+    window.location.assign("/bookmarks");
+}
+
+function initPasskeys() {
+    if (!window.PublicKeyCredential) {
+        return;
+    }
+
+    const registerForm = document.getElementById("passkey-register");
+    if (registerForm) {
+        registerForm.addEventListener("submit", (event) => {
+            event.preventDefault();
+            registerPasskey(registerForm).catch(showError);
+        });
+    }
+
+    const loginButton = document.getElementById("passkey-login");
+    if (loginButton) {
+        loginButton.addEventListener("click", () => {
+            loginWithPasskey().catch(showError);
+        });
+    }
+}
+
+document.addEventListener("DOMContentLoaded", initPasskeys);
@@ -270,9 +270,11 @@ var (
 	// jsBuildOptions configure how JavaScript files are processed by esbuild.
 	jsBuildOptions = api.BuildOptions{
 		EntryPoints: []string{
+			"js/bookmark-select.js",
 			"js/bootstrap-modal-bridge.js",
 			"js/complete-tags.js",
 			"js/easymde-init.js",
+			"js/passkey.js",
 			"js/theme-toggle.js",
 		},
 		Outdir:            "../static",
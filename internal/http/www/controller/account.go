@@ -13,26 +13,66 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	userexporting "github.com/virtualtam/sparklemuffin/pkg/user/exporting"
 )
 
 // RegisterAccountHandlers registers handlers for user account management..
+//
+// Security history handlers are only registered if auditService is not nil.
+//
+// Passkey management handlers are only registered if passkeyService is not nil.
+//
+// Storage usage handlers are only registered if quotaService is not nil.
 func RegisterAccountHandlers(
 	r *chi.Mux,
+	secure bool,
+	archivingService *bookmarkarchiving.Service,
+	auditService *audit.Service,
 	feedService *feed.Service,
+	passkeyService *passkey.Service,
+	quotaService *quota.Service,
 	sessionService *session.Service,
+	ssoService *sso.Service,
+	twoFactorService *twofactor.Service,
 	userService *user.Service,
+	userExportingService *userexporting.Service,
 ) {
 	ac := accountController{
-		feedService:    feedService,
-		sessionService: sessionService,
-		userService:    userService,
-
-		accountInfoView:        view.New("account/info.gohtml"),
-		accountPasswordView:    view.New("account/password.gohtml"),
-		accountPreferencesView: view.New("account/preferences.gohtml"),
+		secure: secure,
+
+		archivingService:     archivingService,
+		auditService:         auditService,
+		feedService:          feedService,
+		passkeyService:       passkeyService,
+		quotaService:         quotaService,
+		sessionService:       sessionService,
+		ssoService:           ssoService,
+		twoFactorService:     twoFactorService,
+		userService:          userService,
+		userExportingService: userExportingService,
+
+		accountAuditView:                  view.New("account/audit.gohtml"),
+		accountDataView:                   view.New("account/data.gohtml"),
+		accountInfoView:                   view.New("account/info.gohtml"),
+		accountPasskeyDeleteView:          view.New("account/passkey_delete.gohtml"),
+		accountPasskeyEditView:            view.New("account/passkey_edit.gohtml"),
+		accountPasskeyListView:            view.New("account/passkey_list.gohtml"),
+		accountPasswordView:               view.New("account/password.gohtml"),
+		accountPreferencesView:            view.New("account/preferences.gohtml"),
+		accountSessionListView:            view.New("account/session_list.gohtml"),
+		accountTwoFactorView:              view.New("account/two_factor.gohtml"),
+		accountTwoFactorRecoveryCodesView: view.New("account/two_factor_recovery_codes.gohtml"),
+		accountTwoFactorSetupView:         view.New("account/two_factor_setup.gohtml"),
+		accountUsageView:                  view.New("account/usage.gohtml", "account/quota_usage.gohtml"),
 	}
 
 	// user account
@@ -41,23 +81,73 @@ func RegisterAccountHandlers(
 			return middleware.AuthenticatedUser(h.ServeHTTP)
 		})
 
+		r.Get("/data", ac.handleDataView())
+		r.Post("/data/export", ac.handleDataExport())
+		r.Post("/delete", ac.handleAccountDelete())
 		r.Get("/info", ac.handleInfoView())
 		r.Post("/info", ac.handleInfoUpdate())
 		r.Get("/password", ac.handlePasswordView())
 		r.Post("/password", ac.handlePasswordUpdate())
 		r.Get("/preferences", ac.handlePreferencesView())
 		r.Post("/preferences", ac.handlePreferencesUpdate())
+		r.Get("/sessions", ac.handleSessionListView())
+		r.Post("/sessions/delete-others", ac.handleSessionDeleteOthers())
+		r.Post("/sessions/{uuid}/delete", ac.handleSessionDelete())
+
+		if auditService != nil {
+			r.Get("/audit", ac.handleAuditView())
+		}
+
+		if passkeyService != nil {
+			r.Get("/passkeys", ac.handlePasskeyListView())
+			r.Post("/passkeys/register/begin", ac.handlePasskeyRegistrationBegin())
+			r.Post("/passkeys/register/finish", ac.handlePasskeyRegistrationFinish())
+			r.Get("/passkeys/{uuid}/delete", ac.handlePasskeyDeleteView())
+			r.Post("/passkeys/{uuid}/delete", ac.handlePasskeyDelete())
+			r.Get("/passkeys/{uuid}/edit", ac.handlePasskeyEditView())
+			r.Post("/passkeys/{uuid}/edit", ac.handlePasskeyEdit())
+		}
+
+		if quotaService != nil {
+			r.Get("/usage", ac.handleUsageView())
+		}
+
+		r.Get("/two-factor", ac.handleTwoFactorView())
+		r.Post("/two-factor/setup", ac.handleTwoFactorSetup())
+		r.Get("/two-factor/setup", ac.handleTwoFactorSetupView())
+		r.Get("/two-factor/setup/qr-code.png", ac.handleTwoFactorQRCode())
+		r.Post("/two-factor/confirm", ac.handleTwoFactorConfirm())
+		r.Post("/two-factor/disable", ac.handleTwoFactorDisable())
 	})
 }
 
 type accountController struct {
-	feedService    *feed.Service
-	sessionService *session.Service
-	userService    *user.Service
-
-	accountInfoView        *view.View
-	accountPasswordView    *view.View
-	accountPreferencesView *view.View
+	secure bool
+
+	archivingService     *bookmarkarchiving.Service
+	auditService         *audit.Service
+	feedService          *feed.Service
+	passkeyService       *passkey.Service
+	quotaService         *quota.Service
+	sessionService       *session.Service
+	ssoService           *sso.Service
+	twoFactorService     *twofactor.Service
+	userService          *user.Service
+	userExportingService *userexporting.Service
+
+	accountAuditView                  *view.View
+	accountDataView                   *view.View
+	accountInfoView                   *view.View
+	accountPasskeyDeleteView          *view.View
+	accountPasskeyEditView            *view.View
+	accountPasskeyListView            *view.View
+	accountPasswordView               *view.View
+	accountPreferencesView            *view.View
+	accountSessionListView            *view.View
+	accountTwoFactorView              *view.View
+	accountTwoFactorRecoveryCodesView *view.View
+	accountTwoFactorSetupView         *view.View
+	accountUsageView                  *view.View
 }
 
 // handleInfoUpdate processes the account information update form.
@@ -94,6 +184,8 @@ func (ac *accountController) handleInfoUpdate() func(w http.ResponseWriter, r *h
 			return
 		}
 
+		ac.recordAccountEvent(r, audit.EventAccountInfoUpdated, "")
+
 		view.PutFlashSuccess(w, "Your account information has been successfully updated")
 		http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
 	}
@@ -147,6 +239,8 @@ func (ac *accountController) handlePasswordUpdate() func(w http.ResponseWriter,
 			return
 		}
 
+		ac.recordAccountEvent(r, audit.EventAccountPasswordUpdated, "")
+
 		// a changed password must invalidate any remember-me token stolen before the change
 		if err := ac.sessionService.DeleteByUserUUID(ctx, ctxUser.UUID); err != nil {
 			log.Error().Err(err).Msg("failed to revoke user sessions")
@@ -177,6 +271,8 @@ func (ac *accountController) handlePreferencesUpdate() func(w http.ResponseWrite
 	type preferencesUpdateForm struct {
 		ShowEntries        string `schema:"feed_show_entries"`
 		ShowEntrySummaries bool   `schema:"feed_show_entry_summaries"`
+		DigestFrequency    string `schema:"feed_digest_frequency"`
+		DigestMarkAsRead   bool   `schema:"feed_digest_mark_as_read"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -195,6 +291,8 @@ func (ac *accountController) handlePreferencesUpdate() func(w http.ResponseWrite
 			UserUUID:           ctxUser.UUID,
 			ShowEntries:        feed.EntryVisibility(form.ShowEntries),
 			ShowEntrySummaries: form.ShowEntrySummaries,
+			DigestFrequency:    feed.DigestFrequency(form.DigestFrequency),
+			DigestMarkAsRead:   form.DigestMarkAsRead,
 		}
 
 		if err := ac.feedService.UpdatePreferences(ctx, feedPreferences); err != nil {
@@ -230,3 +328,15 @@ func (ac *accountController) handlePreferencesView() func(w http.ResponseWriter,
 		ac.accountPreferencesView.Render(w, r, viewData)
 	}
 }
+
+// recordAccountEvent records an action performed by the current user on their own account.
+func (ac *accountController) recordAccountEvent(r *http.Request, eventType audit.EventType, details string) {
+	ctxUser := httpcontext.UserValue(r.Context())
+
+	recordAuditEvent(r, ac.auditService, audit.Event{
+		Type:       eventType,
+		ActorUUID:  ctxUser.UUID,
+		TargetUUID: ctxUser.UUID,
+		Details:    details,
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+)
+
+// handleAuditView renders the security history of the user's account.
+func (ac *accountController) handleAuditView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		pageNumber, pageNumberStr, err := paginate.GetPageNumber(r.URL.Query())
+		if err != nil {
+			log.Warn().Err(err).Str("page_number", pageNumberStr).Msg("invalid page number")
+			view.RedirectOnError(w, r, "/account/audit", fmt.Sprintf("invalid page number: %q", pageNumberStr))
+			return
+		}
+
+		eventPage, err := ac.auditService.ByUserUUID(ctx, ctxUser.UUID, pageNumber)
+		if errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			msg := fmt.Sprintf("invalid page number: %d", pageNumber)
+			log.Error().Err(err).Msg(msg)
+			view.RedirectOnError(w, r, "/account/audit", msg)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve security history")
+			view.PutFlashError(w, "failed to retrieve security history")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Security history",
+			Content: eventPage,
+		}
+
+		ac.accountAuditView.Render(w, r, viewData)
+	}
+}
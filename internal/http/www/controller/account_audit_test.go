@@ -0,0 +1,109 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestHandleAccountAuditView(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	now := time.Now().UTC()
+
+	auditRepo := &audit.FakeRepository{
+		Events: []audit.Event{
+			{
+				UUID:       "0c5e0d3b-7d62-4f3e-9d4a-2b1c8e7f6a50",
+				Type:       audit.EventLoginSucceeded,
+				ActorUUID:  u.UUID,
+				TargetUUID: u.UUID,
+				ClientIP:   "192.0.2.1",
+				Details:    "password",
+				CreatedAt:  now.Add(-2 * time.Hour),
+			},
+			{
+				UUID:       "1d6f1e4c-8e73-4a4f-8e5b-3c2d9f8a7b61",
+				Type:       audit.EventAdminUserUpdated,
+				ActorUUID:  "0695b57a-1ab9-401d-b2db-a4430b7059ec",
+				TargetUUID: u.UUID,
+				ClientIP:   "198.51.100.7",
+				CreatedAt:  now.Add(-1 * time.Hour),
+			},
+			{
+				UUID:      "2e7a2f5d-9f84-4b5a-9f6c-4d3e0a9b8c72",
+				Type:      audit.EventLoginFailed,
+				ClientIP:  "203.0.113.42",
+				Details:   "someone.else@example.org",
+				CreatedAt: now,
+			},
+		},
+	}
+
+	ac := &accountController{
+		auditService:     audit.NewService(auditRepo),
+		accountAuditView: view.New("account/audit.gohtml"),
+	}
+
+	w := httptest.NewRecorder()
+	ac.handleAuditView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/audit", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{"Login succeeded", "192.0.2.1", "User updated", "198.51.100.7"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be listed", want)
+		}
+	}
+	if strings.Contains(body, "someone.else@example.org") {
+		t.Error("want events unrelated to the user not to be listed")
+	}
+}
+
+func TestHandleInfoUpdateIsAudited(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	auditRepo := &audit.FakeRepository{}
+
+	ac := &accountController{
+		auditService: audit.NewService(auditRepo),
+		userService:  user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
+	}
+
+	form := url.Values{
+		"email":        {u.Email},
+		"nick_name":    {u.NickName},
+		"display_name": {"Jane Q. Doe"},
+	}
+
+	w := httptest.NewRecorder()
+	ac.handleInfoUpdate()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/info", u, form))
+
+	if got := decodedFlashLevel(t, w); got != "success" {
+		t.Fatalf("want flash level %q, got %q", "success", got)
+	}
+
+	if len(auditRepo.Events) != 1 {
+		t.Fatalf("want 1 event, got %d", len(auditRepo.Events))
+	}
+
+	got := auditRepo.Events[0]
+	if got.Type != audit.EventAccountInfoUpdated {
+		t.Errorf("want event type %q, got %q", audit.EventAccountInfoUpdated, got.Type)
+	}
+	if got.ActorUUID != u.UUID || got.TargetUUID != u.UUID {
+		t.Errorf("want the user to be both actor and target, got %q and %q", got.ActorUUID, got.TargetUUID)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// handleDataView renders the personal data page, to export or delete the user's account.
+func (ac *accountController) handleDataView() func(w http.ResponseWriter, r *http.Request) {
+	type dataViewContent struct {
+		NickName                      string
+		PasswordOptional              bool
+		ReauthenticationWindowMinutes int
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctxUser := httpcontext.UserValue(r.Context())
+
+		viewData := view.Data{
+			Title: "Personal Data",
+			Content: dataViewContent{
+				NickName:                      ctxUser.NickName,
+				PasswordOptional:              ac.passwordOptional(r, ctxUser.UUID),
+				ReauthenticationWindowMinutes: int(user.AccountDeletionReauthenticationWindow.Minutes()),
+			},
+		}
+
+		ac.accountDataView.Render(w, r, viewData)
+	}
+}
+
+// handleDataExport sends a Zip archive containing all of the user's personal data.
+func (ac *accountController) handleDataExport() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		archive, err := ac.userExportingService.ExportAsZipArchive(ctx, *ctxUser)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to export personal data")
+			view.PutFlashError(w, "failed to export personal data")
+			http.Redirect(w, r, "/account/data", http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventAccountDataExported, "")
+
+		filename := fmt.Sprintf("sparklemuffin-%s-%s.zip", ctxUser.NickName, time.Now().UTC().Format("20060102"))
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		w.Header().Set("Content-Type", "application/zip")
+
+		if _, err := w.Write(archive); err != nil {
+			log.Error().Err(err).Msg("failed to send personal data archive")
+		}
+	}
+}
+
+// handleAccountDelete deletes the user's own account, once confirmed with their current
+// password and nickname.
+//
+// Users linked to an identity provider may not know their password, and can confirm with
+// their nickname only if they have logged in recently.
+//
+// All data related to the account, including active sessions, is deleted along with it,
+// as well as the page snapshots that are no longer referenced by any other account.
+func (ac *accountController) handleAccountDelete() func(w http.ResponseWriter, r *http.Request) {
+	type accountDeleteForm struct {
+		CurrentPassword      string `schema:"current_password"`
+		NickNameConfirmation string `schema:"nick_name_confirmation"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form accountDeleteForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse account deletion form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, "/account/data", http.StatusSeeOther)
+			return
+		}
+
+		deletion := user.AccountDeletion{
+			UserUUID:             ctxUser.UUID,
+			CurrentPassword:      form.CurrentPassword,
+			NickNameConfirmation: form.NickNameConfirmation,
+			PasswordOptional:     ac.passwordOptional(r, ctxUser.UUID),
+		}
+
+		if ctxSession := httpcontext.SessionValue(ctx); ctxSession != nil {
+			deletion.AuthenticatedAt = ctxSession.CreatedAt
+		}
+
+		// Archives are deleted along with the account, list their snapshots beforehand.
+		var archiveHashes []string
+		if ac.archivingService != nil {
+			var err error
+
+			archiveHashes, err = ac.archivingService.ContentHashesByUserUUID(ctx, ctxUser.UUID)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to list page snapshots")
+				view.PutFlashError(w, "failed to delete account")
+				http.Redirect(w, r, "/account/data", http.StatusSeeOther)
+				return
+			}
+		}
+
+		if err := ac.userService.DeleteAccount(ctx, deletion); err != nil {
+			log.Error().Err(err).Msg("failed to delete account")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/data", http.StatusSeeOther)
+			return
+		}
+
+		if len(archiveHashes) > 0 {
+			ac.archivingService.RequestBlobDeletion(archiveHashes)
+		}
+
+		ac.recordAccountEvent(r, audit.EventAccountDeleted, ctxUser.NickName)
+
+		cookie := http.Cookie{
+			Name:     UserRememberTokenCookieName,
+			Value:    "",
+			Path:     "/",
+			Expires:  time.Unix(0, 1),
+			HttpOnly: true,
+			Secure:   ac.secure,
+			SameSite: http.SameSiteLaxMode,
+		}
+		http.SetCookie(w, &cookie)
+
+		view.PutFlashSuccess(w, "Your account and all related data have been deleted")
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// passwordOptional returns whether a user may not know their password, i.e. their account
+// is linked to an identity provider.
+func (ac *accountController) passwordOptional(r *http.Request, userUUID string) bool {
+	if ac.ssoService == nil {
+		return false
+	}
+
+	linked, err := ac.ssoService.IsLinked(r.Context(), userUUID)
+	if err != nil {
+		log.Error().Err(err).Str("user_uuid", userUUID).Msg("failed to check single sign-on identity")
+		return false
+	}
+
+	return linked
+}
@@ -0,0 +1,314 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/test/ssotest"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestHandleDataView(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	ac := accountController{
+		accountDataView: view.New("account/data.gohtml"),
+	}
+
+	w := httptest.NewRecorder()
+	ac.handleDataView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/data", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{`action="/account/data/export"`, `action="/account/delete"`, "Type <strong>jane</strong> to confirm"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be rendered", want)
+		}
+	}
+}
+
+func TestHandleAccountDelete(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	cases := []struct {
+		tname                string
+		nickNameConfirmation string
+		currentPassword      string
+		wantLocation         string
+		wantFlashLevel       string
+		wantFlashMessage     string
+		wantDeleted          bool
+	}{
+		{
+			tname:                "account deleted",
+			nickNameConfirmation: u.NickName,
+			currentPassword:      testTwoFactorPassword,
+			wantLocation:         "/",
+			wantFlashLevel:       "success",
+			wantFlashMessage:     "Your account and all related data have been deleted",
+			wantDeleted:          true,
+		},
+		{
+			tname:                "nickname confirmation mismatch",
+			nickNameConfirmation: "john",
+			currentPassword:      testTwoFactorPassword,
+			wantLocation:         "/account/data",
+			wantFlashLevel:       "danger",
+			wantFlashMessage:     "Error: The nickname does not match your account's nickname.",
+		},
+		{
+			tname:                "incorrect password",
+			nickNameConfirmation: u.NickName,
+			currentPassword:      "not-my-password",
+			wantLocation:         "/account/data",
+			wantFlashLevel:       "danger",
+			wantFlashMessage:     "Error: Your current password is incorrect.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			userRepo := &user.FakeRepository{
+				Users: []user.User{u},
+			}
+
+			ac := accountController{
+				userService: user.NewService(userRepo, user.FakePasswordHasher(t)),
+			}
+
+			form := url.Values{}
+			form.Set("nick_name_confirmation", tc.nickNameConfirmation)
+			form.Set("current_password", tc.currentPassword)
+
+			w := httptest.NewRecorder()
+			ac.handleAccountDelete()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/delete", u, form))
+
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Fatalf("want redirect to %q, got %q", tc.wantLocation, got)
+			}
+			if got := decodedFlashLevel(t, w); got != tc.wantFlashLevel {
+				t.Errorf("want flash level %q, got %q", tc.wantFlashLevel, got)
+			}
+			if got := decodedFlashMessage(t, w); got != tc.wantFlashMessage {
+				t.Errorf("want flash message %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			gotDeleted := len(userRepo.Users) == 0
+			if gotDeleted != tc.wantDeleted {
+				t.Fatalf("want user deleted %t, got %t", tc.wantDeleted, gotDeleted)
+			}
+
+			if !tc.wantDeleted {
+				return
+			}
+
+			var clearedCookie bool
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == UserRememberTokenCookieName && cookie.Value == "" && cookie.Expires.Before(time.Now()) {
+					clearedCookie = true
+				}
+			}
+
+			if !clearedCookie {
+				t.Error("want the session cookie to be cleared")
+			}
+		})
+	}
+}
+
+func TestHandleAccountDeleteSingleSignOn(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	cases := []struct {
+		tname            string
+		sessionCreatedAt time.Time
+		wantLocation     string
+		wantFlashMessage string
+		wantDeleted      bool
+	}{
+		{
+			tname:            "recent login",
+			sessionCreatedAt: time.Now().UTC().Add(-time.Minute),
+			wantLocation:     "/",
+			wantFlashMessage: "Your account and all related data have been deleted",
+			wantDeleted:      true,
+		},
+		{
+			tname:            "login too old",
+			sessionCreatedAt: time.Now().UTC().Add(-time.Hour),
+			wantLocation:     "/account/data",
+			wantFlashMessage: "Error: Please log in again, then confirm within 10 minutes.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			userRepo := &user.FakeRepository{Users: []user.User{u}}
+			userService := user.NewService(userRepo, user.FakePasswordHasher(t))
+
+			provider := ssotest.NewProvider(t, "sparklemuffin", "client-secret")
+			redirectURL, err := url.Parse(testPasskeyOrigin + ssoCallbackPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ssoRepo := &sso.FakeRepository{
+				Identities: []sso.Identity{{Issuer: provider.IssuerURL(), Subject: "jane-subject", UserUUID: u.UUID}},
+			}
+			config := sso.Config{
+				IssuerURL:    provider.IssuerURL(),
+				ClientID:     provider.ClientID,
+				ClientSecret: provider.ClientSecret,
+			}
+
+			ssoService, err := sso.NewService(t.Context(), ssoRepo, userService, config, redirectURL, "hmac-key")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ac := accountController{
+				ssoService:  ssoService,
+				userService: userService,
+			}
+
+			form := url.Values{}
+			form.Set("nick_name_confirmation", u.NickName)
+
+			r := newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/delete", u, form)
+			r = r.WithContext(httpcontext.WithSession(r.Context(), session.Session{UserUUID: u.UUID, CreatedAt: tc.sessionCreatedAt}))
+
+			w := httptest.NewRecorder()
+			ac.handleAccountDelete()(w, r)
+
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Fatalf("want redirect to %q, got %q", tc.wantLocation, got)
+			}
+			if got := decodedFlashMessage(t, w); got != tc.wantFlashMessage {
+				t.Errorf("want flash message %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			if gotDeleted := len(userRepo.Users) == 0; gotDeleted != tc.wantDeleted {
+				t.Errorf("want user deleted %t, got %t", tc.wantDeleted, gotDeleted)
+			}
+		})
+	}
+}
+
+// cascadingUserRepository deletes a user's archives along with their account, as the
+// database does.
+type cascadingUserRepository struct {
+	*user.FakeRepository
+
+	archiveRepository *bookmarkarchiving.FakeRepository
+}
+
+func (r *cascadingUserRepository) UserDeleteByUUID(ctx context.Context, userUUID string) error {
+	if err := r.FakeRepository.UserDeleteByUUID(ctx, userUUID); err != nil {
+		return err
+	}
+
+	r.archiveRepository.Archives = slices.DeleteFunc(r.archiveRepository.Archives, func(archive bookmarkarchiving.Archive) bool {
+		return archive.UserUUID == userUUID
+	})
+
+	return nil
+}
+
+func TestHandleAccountDeleteSnapshots(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	const (
+		otherUserUUID = "0b4d7d4c-5b8e-4d4f-9e34-0a0c1d7c5a51"
+		ownHash       = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		sharedHash    = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+	)
+
+	archiveRepo := &bookmarkarchiving.FakeRepository{
+		Archives: []bookmarkarchiving.Archive{
+			{
+				BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID:    u.UUID,
+				URL:         "https://example.org/own",
+				Status:      bookmarkarchiving.ArchiveStatusArchived,
+				ContentHash: ownHash,
+			},
+			{
+				BookmarkUID: "2Cz5ReZFgCXtVCgHPzC6zsGDjFH",
+				UserUUID:    u.UUID,
+				URL:         "https://example.org/shared",
+				Status:      bookmarkarchiving.ArchiveStatusArchived,
+				ContentHash: sharedHash,
+			},
+			{
+				BookmarkUID: "2Cz5SXbCRyr6Mh5QQnx2DcLnzPj",
+				UserUUID:    otherUserUUID,
+				URL:         "https://example.org/shared",
+				Status:      bookmarkarchiving.ArchiveStatusArchived,
+				ContentHash: sharedHash,
+			},
+		},
+	}
+	blobStore := &bookmarkarchiving.FakeBlobStore{
+		Blobs: map[string][]byte{
+			ownHash:    []byte("hello"),
+			sharedHash: []byte("world"),
+		},
+	}
+
+	userRepo := &cascadingUserRepository{
+		FakeRepository:    &user.FakeRepository{Users: []user.User{u}},
+		archiveRepository: archiveRepo,
+	}
+
+	archivingService := bookmarkarchiving.NewService(archiveRepo, blobStore, nil, nil)
+
+	ac := accountController{
+		archivingService: archivingService,
+		userService:      user.NewService(userRepo, user.FakePasswordHasher(t)),
+	}
+
+	form := url.Values{}
+	form.Set("nick_name_confirmation", u.NickName)
+	form.Set("current_password", testTwoFactorPassword)
+
+	w := httptest.NewRecorder()
+	ac.handleAccountDelete()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/delete", u, form))
+
+	if got := w.Header().Get("Location"); got != "/" {
+		t.Fatalf("want redirect to %q, got %q", "/", got)
+	}
+
+	// snapshots are deleted by the archiving scheduler
+	if len(blobStore.Blobs) != 2 {
+		t.Fatalf("want snapshots to be kept until the scheduler runs, got %d", len(blobStore.Blobs))
+	}
+
+	if err := archivingService.DeleteRequestedBlobs(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if _, ok := blobStore.Blobs[ownHash]; ok {
+		t.Error("want the snapshot only referenced by the deleted account to be deleted")
+	}
+	if _, ok := blobStore.Blobs[sharedHash]; !ok {
+		t.Error("want the snapshot still referenced by another account to be kept")
+	}
+}
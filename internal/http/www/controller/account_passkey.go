@@ -0,0 +1,207 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+)
+
+// handlePasskeyListView renders the list of the user's passkeys.
+func (ac *accountController) handlePasskeyListView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		passkeys, err := ac.passkeyService.ByUserUUID(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve passkeys")
+			view.PutFlashError(w, "failed to retrieve passkeys")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Passkeys",
+			Content: passkeys,
+		}
+
+		ac.accountPasskeyListView.Render(w, r, viewData)
+	}
+}
+
+// handlePasskeyRegistrationBegin starts the registration of a new passkey, and returns the
+// options to pass to the browser's WebAuthn API.
+func (ac *accountController) handlePasskeyRegistrationBegin() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		creation, token, err := ac.passkeyService.BeginRegistration(ctx, *ctxUser)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to begin passkey registration")
+			http.Error(w, userFacingError(err), http.StatusInternalServerError)
+			return
+		}
+
+		setPasskeyCeremony(w, passkeyRegistrationPath, token, ac.secure)
+		writePasskeyOptions(w, creation)
+	}
+}
+
+// handlePasskeyRegistrationFinish verifies the authenticator's response to a registration
+// ceremony, and saves the new passkey.
+func (ac *accountController) handlePasskeyRegistrationFinish() func(w http.ResponseWriter, r *http.Request) {
+	type passkeyRegistration struct {
+		Name       string          `json:"name"`
+		Credential json.RawMessage `json:"credential"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		token := passkeyCeremonyToken(r)
+		clearPasskeyCeremony(w, passkeyRegistrationPath, ac.secure)
+
+		var registration passkeyRegistration
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, passkeyResponseMaxBytes)).Decode(&registration); err != nil {
+			log.Error().Err(err).Msg("failed to decode passkey registration")
+			http.Error(w, "There was an error processing the request", http.StatusBadRequest)
+			return
+		}
+
+		p, err := ac.passkeyService.FinishRegistration(ctx, *ctxUser, token, registration.Name, bytes.NewReader(registration.Credential))
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to register passkey")
+			http.Error(w, userFacingError(err), http.StatusBadRequest)
+			return
+		}
+
+		log.Info().
+			Str("user_uuid", ctxUser.UUID).
+			Str("passkey_uuid", p.UUID).
+			Msg("passkey registered")
+
+		ac.recordAccountEvent(r, audit.EventPasskeyRegistered, p.Name)
+
+		view.PutFlashSuccess(w, "Your passkey has been registered")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handlePasskeyDelete processes the passkey revocation form.
+func (ac *accountController) handlePasskeyDelete() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		passkeyUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if err := ac.passkeyService.Delete(ctx, ctxUser.UUID, passkeyUUID); err != nil {
+			log.Error().Err(err).Msg("failed to delete passkey")
+			view.PutFlashError(w, "failed to delete passkey")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventPasskeyDeleted, "")
+
+		view.PutFlashSuccess(w, "The passkey has been revoked")
+		http.Redirect(w, r, "/account/passkeys", http.StatusSeeOther)
+	}
+}
+
+// handlePasskeyDeleteView renders the passkey revocation form.
+func (ac *accountController) handlePasskeyDeleteView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		passkeyUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		p, err := ac.passkeyService.ByUUID(ctx, ctxUser.UUID, passkeyUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve passkey")
+			view.PutFlashError(w, "failed to retrieve passkey")
+			http.Redirect(w, r, "/account/passkeys", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Revoke passkey",
+			Content: p,
+		}
+
+		ac.accountPasskeyDeleteView.Render(w, r, viewData)
+	}
+}
+
+// handlePasskeyEdit processes the passkey renaming form.
+func (ac *accountController) handlePasskeyEdit() func(w http.ResponseWriter, r *http.Request) {
+	type passkeyEditForm struct {
+		Name string `schema:"name"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		passkeyUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form passkeyEditForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse passkey edition form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		update := passkey.NameUpdate{
+			UUID:     passkeyUUID,
+			UserUUID: ctxUser.UUID,
+			Name:     form.Name,
+		}
+
+		if err := ac.passkeyService.Rename(ctx, update); err != nil {
+			log.Error().Err(err).Msg("failed to rename passkey")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The passkey has been renamed")
+		http.Redirect(w, r, "/account/passkeys", http.StatusSeeOther)
+	}
+}
+
+// handlePasskeyEditView renders the passkey renaming form.
+func (ac *accountController) handlePasskeyEditView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		passkeyUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		p, err := ac.passkeyService.ByUUID(ctx, ctxUser.UUID, passkeyUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve passkey")
+			view.PutFlashError(w, "failed to retrieve passkey")
+			http.Redirect(w, r, "/account/passkeys", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Rename passkey",
+			Content: p,
+		}
+
+		ac.accountPasskeyEditView.Render(w, r, viewData)
+	}
+}
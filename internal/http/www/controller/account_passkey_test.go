@@ -0,0 +1,179 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/test/webauthntest"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testPasskeyOrigin = "https://sparklemuffin.test"
+)
+
+func newTestPasskeyService(t *testing.T, repo *passkey.FakeRepository, users ...user.User) *passkey.Service {
+	t.Helper()
+
+	publicURL, err := url.Parse(testPasskeyOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userService := user.NewService(&user.FakeRepository{Users: users}, user.FakePasswordHasher(t))
+
+	passkeyService, err := passkey.NewService(repo, userService, publicURL, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return passkeyService
+}
+
+func TestHandlePasskeyRegistration(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	passkeyRepo := &passkey.FakeRepository{}
+	authenticator := webauthntest.NewAuthenticator(testPasskeyOrigin)
+
+	ac := accountController{
+		secure:         true,
+		passkeyService: newTestPasskeyService(t, passkeyRepo, u),
+
+		accountPasskeyDeleteView: view.New("account/passkey_delete.gohtml"),
+		accountPasskeyEditView:   view.New("account/passkey_edit.gohtml"),
+		accountPasskeyListView:   view.New("account/passkey_list.gohtml"),
+	}
+
+	w := httptest.NewRecorder()
+	ac.handlePasskeyRegistrationBegin()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/passkeys/register/begin", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("want Content-Type %q, got %q", "application/json", got)
+	}
+
+	ceremonyCookie := responseCookie(w, PasskeyCeremonyCookieName)
+	if ceremonyCookie == nil {
+		t.Fatal("want a passkey ceremony cookie")
+	}
+	if !ceremonyCookie.HttpOnly || !ceremonyCookie.Secure {
+		t.Error("want the passkey ceremony cookie to be HttpOnly and Secure")
+	}
+	if ceremonyCookie.Path != passkeyRegistrationPath {
+		t.Errorf("want cookie path %q, got %q", passkeyRegistrationPath, ceremonyCookie.Path)
+	}
+
+	credential := authenticator.Create(t, w.Body.Bytes())
+
+	body, err := json.Marshal(map[string]any{
+		"name":       "  Laptop  ",
+		"credential": json.RawMessage(credential),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	finish := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/account/passkeys/register/finish", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.AddCookie(ceremonyCookie)
+		r = r.WithContext(httpcontext.WithUser(r.Context(), u))
+
+		w := httptest.NewRecorder()
+		ac.handlePasskeyRegistrationFinish()(w, r)
+
+		return w
+	}
+
+	w = finish()
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("want status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(passkeyRepo.Passkeys) != 1 {
+		t.Fatalf("want 1 passkey, got %d", len(passkeyRepo.Passkeys))
+	}
+
+	p := passkeyRepo.Passkeys[0]
+	if p.Name != "Laptop" {
+		t.Errorf("want name %q, got %q", "Laptop", p.Name)
+	}
+
+	t.Run("ceremony cannot be replayed", func(t *testing.T) {
+		w := finish()
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("want status 400, got %d", w.Code)
+		}
+		if len(passkeyRepo.Passkeys) != 1 {
+			t.Errorf("want 1 passkey, got %d", len(passkeyRepo.Passkeys))
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ac.handlePasskeyListView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/passkeys", u, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Laptop") {
+			t.Error("want the passkey to be listed")
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		target := "/account/passkeys/" + p.UUID + "/edit"
+		r := newTestTwoFactorAccountRequest(t, http.MethodPost, target, u, url.Values{"name": {"Work laptop"}})
+		r = withURLParam(r, "uuid", p.UUID)
+
+		w := httptest.NewRecorder()
+		ac.handlePasskeyEdit()(w, r)
+
+		if got := w.Header().Get("Location"); got != "/account/passkeys" {
+			t.Fatalf("want redirect to %q, got %q", "/account/passkeys", got)
+		}
+		if got := passkeyRepo.Passkeys[0].Name; got != "Work laptop" {
+			t.Errorf("want name %q, got %q", "Work laptop", got)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		target := "/account/passkeys/" + p.UUID + "/delete"
+		r := newTestTwoFactorAccountRequest(t, http.MethodPost, target, u, url.Values{})
+		r = withURLParam(r, "uuid", p.UUID)
+
+		w := httptest.NewRecorder()
+		ac.handlePasskeyDelete()(w, r)
+
+		if got := w.Header().Get("Location"); got != "/account/passkeys" {
+			t.Fatalf("want redirect to %q, got %q", "/account/passkeys", got)
+		}
+		if len(passkeyRepo.Passkeys) != 0 {
+			t.Errorf("want the passkey to be revoked, %d passkey(s) remain", len(passkeyRepo.Passkeys))
+		}
+	})
+}
+
+func withURLParam(r *http.Request, key string, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,114 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+)
+
+// handleSessionListView renders the list of the user's active sessions.
+func (ac *accountController) handleSessionListView() func(w http.ResponseWriter, r *http.Request) {
+	type sessionListViewContent struct {
+		CurrentSessionUUID string
+		Sessions           []session.Session
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		sessions, err := ac.sessionService.ByUserUUID(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve sessions")
+			view.PutFlashError(w, "failed to retrieve sessions")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		content := sessionListViewContent{
+			Sessions: sessions,
+		}
+
+		if ctxSession := httpcontext.SessionValue(ctx); ctxSession != nil {
+			content.CurrentSessionUUID = ctxSession.UUID
+		}
+
+		viewData := view.Data{
+			Title:   "Sessions",
+			Content: content,
+		}
+
+		ac.accountSessionListView.Render(w, r, viewData)
+	}
+}
+
+// handleSessionDelete revokes one of the user's sessions.
+func (ac *accountController) handleSessionDelete() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sessionUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if _, err := uuid.Parse(sessionUUID); err != nil {
+			view.PutFlashError(w, userFacingError(session.ErrNotFound))
+			http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+			return
+		}
+
+		err := ac.sessionService.DeleteByUUID(ctx, ctxUser.UUID, sessionUUID)
+		if errors.Is(err, session.ErrNotFound) {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to revoke session")
+			view.PutFlashError(w, "failed to revoke session")
+			http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventSessionRevoked, "")
+
+		view.PutFlashSuccess(w, "The session has been revoked")
+		http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+	}
+}
+
+// handleSessionDeleteOthers revokes all of the user's sessions, except the current session.
+func (ac *accountController) handleSessionDeleteOthers() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+		ctxSession := httpcontext.SessionValue(ctx)
+
+		if ctxSession == nil {
+			log.Error().Msg("failed to revoke other sessions: no current session")
+			view.PutFlashError(w, "failed to revoke other sessions")
+			http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.sessionService.DeleteOthers(ctx, ctxUser.UUID, ctxSession.UUID); err != nil {
+			log.Error().Err(err).Msg("failed to revoke other sessions")
+			view.PutFlashError(w, "failed to revoke other sessions")
+			http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventSessionRevoked, "all other sessions")
+
+		view.PutFlashSuccess(w, "All other sessions have been revoked")
+		http.Redirect(w, r, "/account/sessions", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+)
+
+func TestHandleSessions(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	now := time.Now().UTC()
+
+	currentSession := session.Session{
+		UUID:                   "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c",
+		UserUUID:               u.UUID,
+		RememberTokenHash:      "current-hash",
+		RememberTokenExpiresAt: now.Add(1 * time.Hour),
+		ClientIP:               "192.0.2.1",
+		UserAgent:              "Mozilla/5.0 (X11; Linux x86_64; rv:140.0) Gecko/20100101 Firefox/140.0",
+		CreatedAt:              now.Add(-1 * time.Hour),
+		LastSeenAt:             now,
+	}
+	laptopSession := session.Session{
+		UUID:                   "7a1e3c5d-8b9f-4a2c-b3d4-e5f6a7b8c9d0",
+		UserUUID:               u.UUID,
+		RememberTokenHash:      "laptop-hash",
+		RememberTokenExpiresAt: now.Add(1 * time.Hour),
+		ClientIP:               "198.51.100.7",
+		UserAgent:              "Laptop browser",
+		CreatedAt:              now.Add(-2 * time.Hour),
+		LastSeenAt:             now.Add(-2 * time.Hour),
+	}
+	phoneSession := session.Session{
+		UUID:                   "9c2f4e6a-0b1d-4c3e-a5f7-b8c9d0e1f2a3",
+		UserUUID:               u.UUID,
+		RememberTokenHash:      "phone-hash",
+		RememberTokenExpiresAt: now.Add(1 * time.Hour),
+		ClientIP:               "203.0.113.42",
+		UserAgent:              "Phone browser",
+		CreatedAt:              now.Add(-3 * time.Hour),
+		LastSeenAt:             now.Add(-3 * time.Hour),
+	}
+	otherUserSession := session.Session{
+		UUID:                   "b4d6f8a0-2c3e-4e5f-a7b9-c0d1e2f3a4b5",
+		UserUUID:               "0695b57a-1ab9-401d-b2db-a4430b7059ec",
+		RememberTokenHash:      "other-user-hash",
+		RememberTokenExpiresAt: now.Add(1 * time.Hour),
+		ClientIP:               "192.0.2.99",
+		UserAgent:              "Other user browser",
+	}
+
+	newController := func(t *testing.T) (*accountController, *session.FakeRepository) {
+		t.Helper()
+
+		sessionRepo := &session.FakeRepository{
+			Sessions: []session.Session{currentSession, laptopSession, phoneSession, otherUserSession},
+		}
+		sessionService, err := session.NewService(sessionRepo, "hmac-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ac := &accountController{
+			sessionService:         sessionService,
+			accountSessionListView: view.New("account/session_list.gohtml"),
+		}
+
+		return ac, sessionRepo
+	}
+
+	newRequest := func(t *testing.T, method string, target string) *http.Request {
+		t.Helper()
+
+		var form url.Values
+		if method == http.MethodPost {
+			form = url.Values{}
+		}
+
+		r := newTestTwoFactorAccountRequest(t, method, target, u, form)
+
+		return r.WithContext(httpcontext.WithSession(r.Context(), currentSession))
+	}
+
+	t.Run("list", func(t *testing.T) {
+		ac, _ := newController(t)
+
+		w := httptest.NewRecorder()
+		ac.handleSessionListView()(w, newRequest(t, http.MethodGet, "/account/sessions"))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		body := w.Body.String()
+
+		for _, want := range []string{"Firefox/140.0", "192.0.2.1", "Laptop browser", "198.51.100.7", "Phone browser", "Current session"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("want %q to be listed", want)
+			}
+		}
+		if strings.Contains(body, "Other user browser") {
+			t.Error("want other users' sessions not to be listed")
+		}
+		if strings.Contains(body, "/account/sessions/"+currentSession.UUID+"/delete") {
+			t.Error("want the current session not to be revocable from the list")
+		}
+	})
+
+	t.Run("revoke session", func(t *testing.T) {
+		ac, sessionRepo := newController(t)
+
+		target := "/account/sessions/" + laptopSession.UUID + "/delete"
+		r := withURLParam(newRequest(t, http.MethodPost, target), "uuid", laptopSession.UUID)
+
+		w := httptest.NewRecorder()
+		ac.handleSessionDelete()(w, r)
+
+		if got := w.Header().Get("Location"); got != "/account/sessions" {
+			t.Fatalf("want redirect to %q, got %q", "/account/sessions", got)
+		}
+		if got := decodedFlashLevel(t, w); got != "success" {
+			t.Errorf("want flash level %q, got %q", "success", got)
+		}
+		if len(sessionRepo.Sessions) != 3 {
+			t.Errorf("want 3 remaining sessions, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("revoke another user's session", func(t *testing.T) {
+		ac, sessionRepo := newController(t)
+
+		target := "/account/sessions/" + otherUserSession.UUID + "/delete"
+		r := withURLParam(newRequest(t, http.MethodPost, target), "uuid", otherUserSession.UUID)
+
+		w := httptest.NewRecorder()
+		ac.handleSessionDelete()(w, r)
+
+		if got := decodedFlashLevel(t, w); got != "danger" {
+			t.Errorf("want flash level %q, got %q", "danger", got)
+		}
+		if len(sessionRepo.Sessions) != 4 {
+			t.Errorf("want 4 remaining sessions, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("revoke session with malformed UUID", func(t *testing.T) {
+		ac, sessionRepo := newController(t)
+
+		target := "/account/sessions/not-a-uuid/delete"
+		r := withURLParam(newRequest(t, http.MethodPost, target), "uuid", "not-a-uuid")
+
+		w := httptest.NewRecorder()
+		ac.handleSessionDelete()(w, r)
+
+		if got := w.Header().Get("Location"); got != "/account/sessions" {
+			t.Fatalf("want redirect to %q, got %q", "/account/sessions", got)
+		}
+		if got := decodedFlashMessage(t, w); got != "Error: This session could not be found." {
+			t.Errorf("want flash message %q, got %q", "Error: This session could not be found.", got)
+		}
+		if len(sessionRepo.Sessions) != 4 {
+			t.Errorf("want 4 remaining sessions, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("revoke all other sessions", func(t *testing.T) {
+		ac, sessionRepo := newController(t)
+
+		w := httptest.NewRecorder()
+		ac.handleSessionDeleteOthers()(w, newRequest(t, http.MethodPost, "/account/sessions/delete-others"))
+
+		if got := w.Header().Get("Location"); got != "/account/sessions" {
+			t.Fatalf("want redirect to %q, got %q", "/account/sessions", got)
+		}
+
+		var remainingUUIDs []string
+		for _, s := range sessionRepo.Sessions {
+			remainingUUIDs = append(remainingUUIDs, s.UUID)
+		}
+
+		wantUUIDs := []string{currentSession.UUID, otherUserSession.UUID}
+		if strings.Join(remainingUUIDs, ",") != strings.Join(wantUUIDs, ",") {
+			t.Errorf("want remaining sessions %v, got %v", wantUUIDs, remainingUUIDs)
+		}
+	})
+}
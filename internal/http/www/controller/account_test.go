@@ -114,7 +114,7 @@ func TestHandlePasswordUpdate(t *testing.T) {
 
 	t.Run("successful update revokes all of the user's sessions", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		newUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -179,7 +179,7 @@ func TestHandlePasswordUpdate(t *testing.T) {
 
 	t.Run("session revocation failure still redirects but flashes a warning instead of success", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		newUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -234,7 +234,7 @@ func TestHandlePasswordUpdate(t *testing.T) {
 
 	t.Run("incorrect current password flashes a user-friendly message", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		newUser := user.User{
 			UUID:        fake.UUID().V4(),
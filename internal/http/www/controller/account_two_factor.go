@@ -0,0 +1,227 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"image/png"
+	"net/http"
+
+	"github.com/pquerna/otp"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+)
+
+const (
+	twoFactorQRCodeSize int = 256
+)
+
+// handleTwoFactorView renders the two-factor authentication settings page.
+func (ac *accountController) handleTwoFactorView() func(w http.ResponseWriter, r *http.Request) {
+	type twoFactorViewContent struct {
+		Enabled                bool
+		RecoveryCodesRemaining int
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		enabled, err := ac.twoFactorService.IsEnabled(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve two-factor authentication status")
+			view.PutFlashError(w, "There was an error retrieving your two-factor authentication settings")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		content := twoFactorViewContent{
+			Enabled: enabled,
+		}
+
+		if enabled {
+			content.RecoveryCodesRemaining, err = ac.twoFactorService.RecoveryCodesRemaining(ctx, ctxUser.UUID)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to count recovery codes")
+			}
+		}
+
+		viewData := view.Data{
+			Title:   "Two-factor authentication",
+			Content: content,
+		}
+
+		ac.accountTwoFactorView.Render(w, r, viewData)
+	}
+}
+
+// handleTwoFactorSetup starts the TOTP enrollment of the current user.
+func (ac *accountController) handleTwoFactorSetup() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if _, err := ac.twoFactorService.Setup(ctx, *ctxUser); err != nil {
+			log.Error().Err(err).Msg("failed to set up two-factor authentication")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+			return
+		}
+
+		http.Redirect(w, r, "/account/two-factor/setup", http.StatusSeeOther)
+	}
+}
+
+// handleTwoFactorSetupView renders the TOTP provisioning QR code and confirmation form.
+func (ac *accountController) handleTwoFactorSetupView() func(w http.ResponseWriter, r *http.Request) {
+	type twoFactorSetupViewContent struct {
+		Secret          string
+		ProvisioningURI string
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		enrollment, err := ac.twoFactorService.ByUserUUID(ctx, ctxUser.UUID)
+		if err != nil || enrollment.Enabled {
+			http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+			return
+		}
+
+		// the page displays a secret; make sure it is not kept by the browser or intermediate caches
+		w.Header().Set("Cache-Control", "no-store")
+
+		viewData := view.Data{
+			Title: "Set up two-factor authentication",
+			Content: twoFactorSetupViewContent{
+				Secret:          enrollment.Secret,
+				ProvisioningURI: enrollment.ProvisioningURI(ctxUser.Email),
+			},
+		}
+
+		ac.accountTwoFactorSetupView.Render(w, r, viewData)
+	}
+}
+
+// handleTwoFactorQRCode renders the TOTP provisioning URI of the current user as a QR code.
+//
+// The image is served from the same origin rather than inlined as a data: URI, to comply
+// with the Content Security Policy.
+func (ac *accountController) handleTwoFactorQRCode() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		enrollment, err := ac.twoFactorService.ByUserUUID(ctx, ctxUser.UUID)
+		if err != nil || enrollment.Enabled {
+			http.NotFound(w, r)
+			return
+		}
+
+		key, err := otp.NewKeyFromURL(enrollment.ProvisioningURI(ctxUser.Email))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to parse TOTP provisioning URI")
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		img, err := key.Image(twoFactorQRCodeSize, twoFactorQRCodeSize)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to generate QR code")
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if err := png.Encode(w, img); err != nil {
+			log.Error().Err(err).Msg("failed to encode QR code")
+		}
+	}
+}
+
+// handleTwoFactorConfirm enables two-factor authentication for the current user, and displays
+// their recovery codes.
+func (ac *accountController) handleTwoFactorConfirm() func(w http.ResponseWriter, r *http.Request) {
+	type twoFactorConfirmForm struct {
+		Code string `schema:"code"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form twoFactorConfirmForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse two-factor authentication confirmation form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, "/account/two-factor/setup", http.StatusSeeOther)
+			return
+		}
+
+		recoveryCodes, err := ac.twoFactorService.Confirm(ctx, ctxUser.UUID, form.Code)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to confirm two-factor authentication")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/two-factor/setup", http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventTwoFactorEnabled, "")
+
+		// recovery codes are only displayed once, and must not be cached
+		w.Header().Set("Cache-Control", "no-store")
+
+		viewData := view.Data{
+			Title:   "Recovery codes",
+			Content: recoveryCodes,
+		}
+
+		ac.accountTwoFactorRecoveryCodesView.Render(w, r, viewData)
+	}
+}
+
+// handleTwoFactorDisable disables two-factor authentication for the current user, provided
+// they have entered their current password.
+func (ac *accountController) handleTwoFactorDisable() func(w http.ResponseWriter, r *http.Request) {
+	type twoFactorDisableForm struct {
+		CurrentPassword string `schema:"current_password"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form twoFactorDisableForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse two-factor authentication disable form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+			return
+		}
+
+		if _, err := ac.userService.Authenticate(ctx, ctxUser.Email, form.CurrentPassword); err != nil {
+			log.Error().Err(err).Msg("failed to authenticate user")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.twoFactorService.Disable(ctx, ctxUser.UUID); err != nil {
+			log.Error().Err(err).Msg("failed to disable two-factor authentication")
+			view.PutFlashError(w, "There was an error disabling two-factor authentication")
+			http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAccountEvent(r, audit.EventTwoFactorDisabled, "")
+
+		view.PutFlashSuccess(w, "Two-factor authentication has been disabled")
+		http.Redirect(w, r, "/account/two-factor", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testTwoFactorPassword = "correct horse battery staple"
+	testTwoFactorSecret   = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+)
+
+func newTestTwoFactorService(t *testing.T, repo *twofactor.FakeRepository) *twofactor.Service {
+	t.Helper()
+
+	twoFactorService, err := twofactor.NewService(repo, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return twoFactorService
+}
+
+func newTestTwoFactorUser(t *testing.T) user.User {
+	t.Helper()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(testTwoFactorPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user.User{
+		UUID:         "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:        "jane.doe@example.org",
+		NickName:     "jane",
+		DisplayName:  "Jane Doe",
+		PasswordHash: string(passwordHash),
+	}
+}
+
+func testTwoFactorCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := totp.GenerateCode(testTwoFactorSecret, time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func newTestTwoFactorAccountRequest(t *testing.T, method string, target string, ctxUser user.User, form url.Values) *http.Request {
+	t.Helper()
+
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	}
+
+	return r.WithContext(httpcontext.WithUser(r.Context(), ctxUser))
+}
+
+func TestHandleTwoFactorSetupAndConfirm(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	twoFactorRepo := &twofactor.FakeRepository{}
+
+	ac := accountController{
+		twoFactorService: newTestTwoFactorService(t, twoFactorRepo),
+
+		accountTwoFactorView:              view.New("account/two_factor.gohtml"),
+		accountTwoFactorRecoveryCodesView: view.New("account/two_factor_recovery_codes.gohtml"),
+		accountTwoFactorSetupView:         view.New("account/two_factor_setup.gohtml"),
+	}
+
+	w := httptest.NewRecorder()
+	ac.handleTwoFactorSetup()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/two-factor/setup", u, url.Values{}))
+
+	if got := w.Header().Get("Location"); got != "/account/two-factor/setup" {
+		t.Fatalf("want redirect to %q, got %q", "/account/two-factor/setup", got)
+	}
+	if len(twoFactorRepo.TOTPs) != 1 {
+		t.Fatalf("want 1 TOTP enrollment, got %d", len(twoFactorRepo.TOTPs))
+	}
+
+	secret := twoFactorRepo.TOTPs[0].Secret
+
+	w = httptest.NewRecorder()
+	ac.handleTwoFactorSetupView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/two-factor/setup", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), secret) {
+		t.Error("want the secret to be displayed for manual entry")
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("want Cache-Control %q, got %q", "no-store", got)
+	}
+
+	w = httptest.NewRecorder()
+	ac.handleTwoFactorQRCode()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/two-factor/setup/qr-code.png", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("want Content-Type %q, got %q", "image/png", got)
+	}
+
+	code, err := totp.GenerateCode(secret, time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	ac.handleTwoFactorConfirm()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/two-factor/confirm", u, url.Values{"code": {code}}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+	if strings.Count(w.Body.String(), "<li>") < twofactor.RecoveryCodeCount {
+		t.Errorf("want %d recovery codes to be displayed, got:\n%s", twofactor.RecoveryCodeCount, w.Body.String())
+	}
+	if !twoFactorRepo.TOTPs[0].Enabled {
+		t.Error("want two-factor authentication to be enabled")
+	}
+
+	// the QR code is no longer available once enrollment is confirmed
+	w = httptest.NewRecorder()
+	ac.handleTwoFactorQRCode()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/two-factor/setup/qr-code.png", u, nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("want status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandleTwoFactorDisable(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	cases := []struct {
+		tname       string
+		password    string
+		wantEnabled bool
+	}{
+		{tname: "incorrect password", password: "incorrect password", wantEnabled: true},
+		{tname: "correct password", password: testTwoFactorPassword, wantEnabled: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			twoFactorRepo := &twofactor.FakeRepository{
+				TOTPs: []twofactor.TOTP{
+					{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true},
+				},
+			}
+
+			ac := accountController{
+				twoFactorService: newTestTwoFactorService(t, twoFactorRepo),
+				userService:      user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
+			}
+
+			form := url.Values{"current_password": {tc.password}}
+			w := httptest.NewRecorder()
+			ac.handleTwoFactorDisable()(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/two-factor/disable", u, form))
+
+			if w.Code != http.StatusSeeOther {
+				t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+			}
+
+			gotEnabled := len(twoFactorRepo.TOTPs) == 1
+			if gotEnabled != tc.wantEnabled {
+				t.Errorf("want two-factor authentication enabled: %t, got %t", tc.wantEnabled, gotEnabled)
+			}
+		})
+	}
+}
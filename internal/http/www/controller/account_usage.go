@@ -0,0 +1,36 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+)
+
+// handleUsageView renders the user's current storage usage, along with the limits that apply
+// to their account.
+func (ac *accountController) handleUsageView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		viewData := view.Data{Title: "Storage Usage"}
+
+		summary, err := ac.quotaService.SummaryByUserUUID(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve storage usage")
+			view.PutFlashError(w, "failed to retrieve storage usage")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		viewData.Content = newQuotaSummaryContent(summary)
+
+		ac.accountUsageView.Render(w, r, viewData)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+)
+
+func TestHandleUsageView(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	maxSubscriptions := int64(0)
+	quotaRepository := &quota.FakeRepository{
+		Overrides: map[string]quota.Override{
+			u.UUID: {UserUUID: u.UUID, MaxSubscriptions: &maxSubscriptions},
+		},
+		Usages: map[string]quota.Usage{
+			u.UUID: {Bookmarks: 75, Subscriptions: 12},
+		},
+	}
+
+	quotaService, err := quota.NewService(quotaRepository, quota.Limits{
+		MaxBookmarks:      100,
+		MaxSubscriptions:  50,
+		MaxImportFileSize: 2 * bytesPerMiB,
+	})
+	if err != nil {
+		t.Fatalf("failed to create quota service: %q", err)
+	}
+
+	ac := accountController{
+		quotaService:     quotaService,
+		accountUsageView: view.New("account/usage.gohtml", "account/quota_usage.gohtml"),
+	}
+
+	w := httptest.NewRecorder()
+	ac.handleUsageView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/usage", u, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{
+		`aria-label="Bookmarks usage"`,
+		`aria-valuenow="75"`,
+		"<td>100</td>",
+		"<td>unlimited</td>",
+		"<td>2 MiB</td>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be rendered, got:\n%s", want, body)
+		}
+	}
+
+	if strings.Contains(body, `aria-label="Feed subscriptions usage"`) {
+		t.Error("want no progress bar for unlimited feed subscriptions")
+	}
+}
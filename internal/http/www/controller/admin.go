@@ -11,26 +11,68 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/virtualtam/sparklemuffin/internal/http/www/htmx"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	"github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 // RegisterAdminHandlers registers handlers for administration operations.
+//
+// Audit log handlers are only registered if auditService is not nil.
+//
+// Feed administration handlers are only registered if feedAdministratingService
+// is not nil.
+//
+// Account unlock handlers are only registered if lockoutService is not nil.
+//
+// Storage quota handlers are only registered if quotaService is not nil.
+//
+// Feed synchronization statistics are only displayed on the dashboard if
+// feedSynchronizingCollector is not nil.
 func RegisterAdminHandlers(
 	r *chi.Mux,
+	auditService *audit.Service,
+	feedAdministratingService *feedadministrating.Service,
+	feedSynchronizingCollector *synchronizing.Collector,
+	instanceService *instance.Service,
+	lockoutService *lockout.Service,
+	quotaService *quota.Service,
 	sessionService *session.Service,
+	twoFactorService *twofactor.Service,
 	userService *user.Service,
 ) {
 	ac := adminController{
-		sessionService: sessionService,
-		userService:    userService,
-
-		adminUserAddView:    view.New("admin/user_add.gohtml"),
-		adminUserDeleteView: view.New("admin/user_delete.gohtml"),
-		adminUserEditView:   view.New("admin/user_edit.gohtml"),
-		adminUserListView:   view.New("admin/user_list.gohtml"),
+		auditService:               auditService,
+		feedAdministratingService:  feedAdministratingService,
+		feedSynchronizingCollector: feedSynchronizingCollector,
+		instanceService:            instanceService,
+		lockoutService:             lockoutService,
+		quotaService:               quotaService,
+		sessionService:             sessionService,
+		twoFactorService:           twoFactorService,
+		userService:                userService,
+
+		adminAuditView:              view.New("admin/audit.gohtml"),
+		adminDashboardView:          view.New("admin/dashboard.gohtml"),
+		adminFeedDeleteView:         view.New("admin/feed_delete.gohtml"),
+		adminFeedListView:           view.New("admin/feed_list.gohtml"),
+		adminFeedView:               view.New("admin/feed.gohtml"),
+		adminUserAddView:            view.New("admin/user_add.gohtml"),
+		adminUserDeleteView:         view.New("admin/user_delete.gohtml"),
+		adminUserEditView:           view.New("admin/user_edit.gohtml"),
+		adminUserListView:           view.New("admin/user_list.gohtml"),
+		adminUserQuotaView:          view.New("admin/user_quota.gohtml", "account/quota_usage.gohtml"),
+		adminUserTwoFactorResetView: view.New("admin/user_two_factor_reset.gohtml"),
+		adminUserUnlockView:         view.New("admin/user_unlock.gohtml"),
 	}
 
 	// administration
@@ -39,6 +81,7 @@ func RegisterAdminHandlers(
 			return middleware.AdminUser(h.ServeHTTP)
 		})
 
+		r.Get("/", ac.handleDashboardView())
 		r.Get("/users", ac.handleUserListView())
 		r.Get("/users/add", ac.handleUserAddView())
 		r.Post("/users", ac.handleUserAdd())
@@ -46,17 +89,59 @@ func RegisterAdminHandlers(
 		r.Post("/users/{uuid}", ac.handleUserEdit())
 		r.Get("/users/{uuid}/delete", ac.handleUserDeleteView())
 		r.Post("/users/{uuid}/delete", ac.handleUserDelete())
+		r.Get("/users/{uuid}/two-factor/reset", ac.handleUserTwoFactorResetView())
+		r.Post("/users/{uuid}/two-factor/reset", ac.handleUserTwoFactorReset())
+
+		if lockoutService != nil {
+			r.Get("/users/{uuid}/unlock", ac.handleUserUnlockView())
+			r.Post("/users/{uuid}/unlock", ac.handleUserUnlock())
+		}
+
+		if quotaService != nil {
+			r.Get("/users/{uuid}/quota", ac.handleUserQuotaView())
+			r.Post("/users/{uuid}/quota", ac.handleUserQuotaUpdate())
+		}
+
+		if feedAdministratingService != nil {
+			r.Get("/feeds", ac.handleFeedListView())
+			r.Get("/feeds/{uuid}", ac.handleFeedView())
+			r.Post("/feeds/{uuid}/refresh", ac.handleFeedRefresh())
+			r.Post("/feeds/{uuid}/url", ac.handleFeedURLUpdate())
+			r.Post("/feeds/{uuid}/merge", ac.handleFeedMerge())
+			r.Get("/feeds/{uuid}/delete", ac.handleFeedDeleteView())
+			r.Post("/feeds/{uuid}/delete", ac.handleFeedDelete())
+		}
+
+		if auditService != nil {
+			r.Get("/audit", ac.handleAuditView())
+			r.Get("/audit.csv", ac.handleAuditExport())
+		}
 	})
 }
 
 type adminController struct {
-	sessionService *session.Service
-	userService    *user.Service
-
-	adminUserAddView    *view.View
-	adminUserDeleteView *view.View
-	adminUserEditView   *view.View
-	adminUserListView   *view.View
+	auditService               *audit.Service
+	feedAdministratingService  *feedadministrating.Service
+	feedSynchronizingCollector *synchronizing.Collector
+	instanceService            *instance.Service
+	lockoutService             *lockout.Service
+	quotaService               *quota.Service
+	sessionService             *session.Service
+	twoFactorService           *twofactor.Service
+	userService                *user.Service
+
+	adminAuditView              *view.View
+	adminDashboardView          *view.View
+	adminFeedDeleteView         *view.View
+	adminFeedListView           *view.View
+	adminFeedView               *view.View
+	adminUserAddView            *view.View
+	adminUserDeleteView         *view.View
+	adminUserEditView           *view.View
+	adminUserListView           *view.View
+	adminUserQuotaView          *view.View
+	adminUserTwoFactorResetView *view.View
+	adminUserUnlockView         *view.View
 }
 
 // handleUserListView renders the users list view.
@@ -123,6 +208,12 @@ func (ac *adminController) handleUserAdd() func(w http.ResponseWriter, r *http.R
 			return
 		}
 
+		var newUserUUID string
+		if createdUser, err := ac.userService.ByEmail(ctx, newUser.Email); err == nil {
+			newUserUUID = createdUser.UUID
+		}
+		ac.recordAdminEvent(r, audit.EventAdminUserCreated, newUserUUID, newUser.Email)
+
 		view.PutFlashSuccess(w, fmt.Sprintf("user %q has been successfully created", newUser.Email))
 		http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
 	}
@@ -191,6 +282,8 @@ func (ac *adminController) handleUserDelete() func(w http.ResponseWriter, r *htt
 			return
 		}
 
+		ac.recordAdminEvent(r, audit.EventAdminUserDeleted, userUUID, userToDelete.Email)
+
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
 			w.Header().Set(htmx.HeaderRetarget, "#user-row-"+userUUID)
 			w.Header().Set(htmx.HeaderReswap, "outerHTML")
@@ -282,6 +375,8 @@ func (ac *adminController) handleUserEdit() func(w http.ResponseWriter, r *http.
 			return
 		}
 
+		ac.recordAdminEvent(r, audit.EventAdminUserUpdated, userUUID, editedUser.Email)
+
 		// an admin-initiated edit always rewrites the password hash, so revoke the edited user's sessions too
 		if err := ac.sessionService.DeleteByUserUUID(ctx, editedUser.UUID); err != nil {
 			log.Error().Err(err).Msg("failed to revoke user sessions")
@@ -305,3 +400,83 @@ func (ac *adminController) handleUserEdit() func(w http.ResponseWriter, r *http.
 		http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
 	}
 }
+
+// handleUserTwoFactorResetView renders the two-factor authentication reset form.
+func (ac *adminController) handleUserTwoFactorResetView() func(w http.ResponseWriter, r *http.Request) {
+	type userTwoFactorResetViewContent struct {
+		User             user.User
+		TwoFactorEnabled bool
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		userToReset, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		twoFactorEnabled, err := ac.twoFactorService.IsEnabled(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve two-factor authentication status")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: fmt.Sprintf("Reset two-factor authentication: %s", userToReset.NickName),
+			Content: userTwoFactorResetViewContent{
+				User:             userToReset,
+				TwoFactorEnabled: twoFactorEnabled,
+			},
+		}
+
+		ac.adminUserTwoFactorResetView.Render(w, r, viewData)
+	}
+}
+
+// handleUserTwoFactorReset disables two-factor authentication for a user, e.g. when they have
+// lost access to both their authenticator application and their recovery codes.
+func (ac *adminController) handleUserTwoFactorReset() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		userToReset, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.twoFactorService.Disable(ctx, userUUID); err != nil {
+			log.Error().Err(err).Msg("failed to reset two-factor authentication")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(r, audit.EventAdminTwoFactorReset, userUUID, userToReset.Email)
+
+		view.PutFlashSuccess(w, fmt.Sprintf("two-factor authentication has been reset for user %q", userToReset.Email))
+		http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+	}
+}
+
+// recordAdminEvent records an action performed by the current administrator on a user account.
+func (ac *adminController) recordAdminEvent(r *http.Request, eventType audit.EventType, targetUUID string, details string) {
+	ctxUser := httpcontext.UserValue(r.Context())
+
+	recordAuditEvent(r, ac.auditService, audit.Event{
+		Type:       eventType,
+		ActorUUID:  ctxUser.UUID,
+		TargetUUID: targetUUID,
+		Details:    details,
+	})
+}
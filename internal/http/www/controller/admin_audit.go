@@ -0,0 +1,168 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	auditFilterDateLayout = "2006-01-02"
+)
+
+var errAuditFilterDateInvalid = errors.New("audit filter: invalid date")
+
+// auditFilterForm holds the audit log filters submitted through URL query parameters.
+type auditFilterForm struct {
+	Type     string
+	UserUUID string
+	Since    string
+	Until    string
+}
+
+// newAuditFilterForm reads audit log filters from URL query parameters.
+func newAuditFilterForm(query url.Values) auditFilterForm {
+	return auditFilterForm{
+		Type:     query.Get("type"),
+		UserUUID: query.Get("user"),
+		Since:    query.Get("since"),
+		Until:    query.Get("until"),
+	}
+}
+
+// filter returns the audit.Filter corresponding to the form.
+//
+// Dates are interpreted in UTC; the end date is inclusive.
+func (f auditFilterForm) filter() (audit.Filter, error) {
+	filter := audit.Filter{
+		Type:     audit.EventType(f.Type),
+		UserUUID: f.UserUUID,
+	}
+
+	if f.Since != "" {
+		since, err := time.Parse(auditFilterDateLayout, f.Since)
+		if err != nil {
+			return audit.Filter{}, fmt.Errorf("%w: %q", errAuditFilterDateInvalid, f.Since)
+		}
+
+		filter.Since = since
+	}
+
+	if f.Until != "" {
+		until, err := time.Parse(auditFilterDateLayout, f.Until)
+		if err != nil {
+			return audit.Filter{}, fmt.Errorf("%w: %q", errAuditFilterDateInvalid, f.Until)
+		}
+
+		filter.Until = until.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return audit.Filter{}, err
+	}
+
+	return filter, nil
+}
+
+// handleAuditView renders the security audit log, filtered by event type, user and date.
+func (ac *adminController) handleAuditView() func(w http.ResponseWriter, r *http.Request) {
+	type auditViewContent struct {
+		audit.EventPage
+
+		Filter     auditFilterForm
+		EventTypes []audit.EventType
+		Users      []user.User
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		urlQuery := r.URL.Query()
+
+		pageNumber, pageNumberStr, err := paginate.GetPageNumber(urlQuery)
+		if err != nil {
+			log.Warn().Err(err).Str("page_number", pageNumberStr).Msg("invalid page number")
+			view.RedirectOnError(w, r, "/admin/audit", fmt.Sprintf("invalid page number: %q", pageNumberStr))
+			return
+		}
+
+		form := newAuditFilterForm(urlQuery)
+
+		filter, err := form.filter()
+		if err != nil {
+			log.Warn().Err(err).Msg("invalid audit log filter")
+			view.RedirectOnError(w, r, "/admin/audit", userFacingError(err))
+			return
+		}
+
+		eventPage, err := ac.auditService.ByPage(ctx, filter, pageNumber)
+		if errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			msg := fmt.Sprintf("invalid page number: %d", pageNumber)
+			log.Error().Err(err).Msg(msg)
+			view.RedirectOnError(w, r, "/admin/audit", msg)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve audit events")
+			view.PutFlashError(w, "failed to retrieve audit events")
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		users, err := ac.userService.All(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve users")
+			view.PutFlashError(w, "failed to retrieve users")
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Audit log",
+			Content: auditViewContent{
+				EventPage:  eventPage,
+				Filter:     form,
+				EventTypes: audit.EventTypes,
+				Users:      users,
+			},
+		}
+
+		ac.adminAuditView.Render(w, r, viewData)
+	}
+}
+
+// handleAuditExport exports the security audit log as CSV, with the same filters as the
+// audit log view.
+func (ac *adminController) handleAuditExport() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form := newAuditFilterForm(r.URL.Query())
+
+		filter, err := form.filter()
+		if err != nil {
+			log.Warn().Err(err).Msg("invalid audit log filter")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/admin/audit", http.StatusSeeOther)
+			return
+		}
+
+		filename := fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("2006-01-02_15-04-05"))
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+		if err := ac.auditService.ExportCSV(r.Context(), w, filter); err != nil {
+			// the response has already been partially written, and cannot be replaced
+			log.Error().Err(err).Msg("failed to export audit events")
+		}
+	}
+}
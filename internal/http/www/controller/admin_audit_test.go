@@ -0,0 +1,138 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestHandleAdminAudit(t *testing.T) {
+	admin := user.User{
+		UUID:     "0695b57a-1ab9-401d-b2db-a4430b7059ec",
+		Email:    "admin@example.org",
+		NickName: "admin",
+		IsAdmin:  true,
+	}
+	member := newTestTwoFactorUser(t)
+
+	events := []audit.Event{
+		{
+			UUID:       "0c5e0d3b-7d62-4f3e-9d4a-2b1c8e7f6a50",
+			Type:       audit.EventLoginSucceeded,
+			ActorUUID:  member.UUID,
+			TargetUUID: member.UUID,
+			ClientIP:   "192.0.2.1",
+			CreatedAt:  time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			UUID:       "1d6f1e4c-8e73-4a4f-8e5b-3c2d9f8a7b61",
+			Type:       audit.EventAdminUserDeleted,
+			ActorUUID:  admin.UUID,
+			TargetUUID: "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c",
+			ClientIP:   "198.51.100.7",
+			Details:    "deleted.user@example.org",
+			CreatedAt:  time.Date(2026, time.March, 2, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			UUID:      "2e7a2f5d-9f84-4b5a-9f6c-4d3e0a9b8c72",
+			Type:      audit.EventLoginFailed,
+			ClientIP:  "203.0.113.42",
+			Details:   "unknown@example.org",
+			CreatedAt: time.Date(2026, time.March, 3, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ac := &adminController{
+		auditService:   audit.NewService(&audit.FakeRepository{Events: events}),
+		userService:    user.NewService(&user.FakeRepository{Users: []user.User{admin, member}}, user.FakePasswordHasher(t)),
+		adminAuditView: view.New("admin/audit.gohtml"),
+	}
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ac.handleAuditView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/admin/audit", admin, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		body := w.Body.String()
+		for _, want := range []string{"192.0.2.1", "deleted.user@example.org", "unknown@example.org"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("want %q to be listed", want)
+			}
+		}
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		target := "/admin/audit?type=" + string(audit.EventLoginFailed)
+		ac.handleAuditView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, target, admin, nil))
+
+		body := w.Body.String()
+		if !strings.Contains(body, "unknown@example.org") {
+			t.Error("want matching events to be listed")
+		}
+		if strings.Contains(body, "deleted.user@example.org") {
+			t.Error("want other events to be filtered out")
+		}
+	})
+
+	t.Run("filter by date, with an inclusive end date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		target := "/admin/audit?since=2026-03-02&until=2026-03-02"
+		ac.handleAuditView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, target, admin, nil))
+
+		body := w.Body.String()
+		if !strings.Contains(body, "deleted.user@example.org") {
+			t.Error("want events from the end date to be listed")
+		}
+		if strings.Contains(body, "unknown@example.org") || strings.Contains(body, "192.0.2.1") {
+			t.Error("want events outside the date range to be filtered out")
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ac.handleAuditView()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/admin/audit?since=yesterday", admin, nil))
+
+		if got := w.Header().Get("Location"); got != "/admin/audit" {
+			t.Fatalf("want redirect to %q, got %q", "/admin/audit", got)
+		}
+		if got, want := decodedFlashMessage(t, w), "Error: "+userFacingError(errAuditFilterDateInvalid); got != want {
+			t.Errorf("want flash message %q, got %q", want, got)
+		}
+	})
+
+	t.Run("CSV export", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		target := "/admin/audit.csv?user=" + member.UUID
+		ac.handleAuditExport()(w, newTestTwoFactorAccountRequest(t, http.MethodGet, target, admin, nil))
+
+		if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+			t.Errorf("want content type %q, got %q", "text/csv; charset=utf-8", got)
+		}
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to parse CSV export: %q", err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("want a header and 1 event, got %d records", len(records))
+		}
+		if got := records[1][1]; got != string(audit.EventLoginSucceeded) {
+			t.Errorf("want event type %q, got %q", audit.EventLoginSucceeded, got)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+)
+
+// tableSizeRow holds the disk space used by a database table, for display purposes.
+type tableSizeRow struct {
+	Name string
+	Size string
+}
+
+// synchronizationRunRow holds a feed synchronization run, for display purposes.
+type synchronizationRunRow struct {
+	JobID     string
+	StartedAt time.Time
+	Duration  time.Duration
+	Feeds     int
+	Error     string
+}
+
+// synchronizationContent holds feed synchronization statistics, for display purposes.
+type synchronizationContent struct {
+	Tasks          uint64
+	Duration       time.Duration
+	UpdatedFeeds   uint64
+	SkippedFeeds   uint64
+	Entries        uint64
+	EntriesPerTask uint64
+	Errors         uint64
+	Fetched        string
+	Throughput     string
+	RecentRuns     []synchronizationRunRow
+}
+
+func newSynchronizationContent(statistics synchronizing.Statistics) *synchronizationContent {
+	content := &synchronizationContent{
+		Tasks:          statistics.Tasks,
+		Duration:       statistics.Duration.Round(time.Second),
+		UpdatedFeeds:   statistics.UpdatedFeeds,
+		SkippedFeeds:   statistics.SkippedFeeds,
+		Entries:        statistics.Entries,
+		EntriesPerTask: statistics.EntriesPerTask(),
+		Errors:         statistics.Errors,
+		Fetched:        formatByteSize(int64(statistics.Bytes)),
+		Throughput:     formatByteSize(int64(statistics.BytesPerSecond())) + "/s",
+	}
+
+	for _, run := range statistics.RecentRuns {
+		row := synchronizationRunRow{
+			JobID:     run.JobID,
+			StartedAt: run.StartedAt,
+			Duration:  run.Duration.Round(time.Millisecond),
+			Feeds:     run.Feeds,
+		}
+
+		if run.Err != nil {
+			row.Error = run.Err.Error()
+		}
+
+		content.RecentRuns = append(content.RecentRuns, row)
+	}
+
+	return content
+}
+
+// formatByteSize returns a human-readable size, using binary prefixes.
+func formatByteSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// handleDashboardView renders usage statistics for the whole instance.
+func (ac *adminController) handleDashboardView() func(w http.ResponseWriter, r *http.Request) {
+	type dashboardViewContent struct {
+		Counts       instance.Counts
+		DatabaseSize string
+		TableSizes   []tableSizeRow
+		FeedErrors   []instance.FeedError
+
+		// Synchronization is nil when feed synchronization metrics are not available.
+		Synchronization *synchronizationContent
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewData := view.Data{Title: "Dashboard"}
+
+		overview, err := ac.instanceService.Overview(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve instance statistics")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		content := dashboardViewContent{
+			Counts:       overview.Counts,
+			DatabaseSize: formatByteSize(overview.DatabaseSize()),
+			FeedErrors:   overview.FeedErrors,
+		}
+
+		for _, tableSize := range overview.TableSizes {
+			content.TableSizes = append(content.TableSizes, tableSizeRow{
+				Name: tableSize.Name,
+				Size: formatByteSize(tableSize.SizeBytes),
+			})
+		}
+
+		if ac.feedSynchronizingCollector != nil {
+			content.Synchronization = newSynchronizationContent(ac.feedSynchronizingCollector.Statistics())
+		}
+
+		viewData.Content = content
+
+		ac.adminDashboardView.Render(w, r, viewData)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestFormatByteSize(t *testing.T) {
+	cases := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KiB"},
+		{size: 1536, want: "1.5 KiB"},
+		{size: 8 * 1024 * 1024, want: "8.0 MiB"},
+		{size: 3 * 1024 * 1024 * 1024, want: "3.0 GiB"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.want, func(t *testing.T) {
+			got := formatByteSize(tc.size)
+
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleDashboardView(t *testing.T) {
+	ctxUser := user.User{UUID: "a2f3e4d5-6b7c-4d8e-9f0a-1b2c3d4e5f60", IsAdmin: true}
+
+	instanceRepository := &instance.FakeRepository{
+		Counts: instance.Counts{
+			Users:          3,
+			Bookmarks:      1234,
+			Feeds:          42,
+			FeedEntries:    5678,
+			Subscriptions:  51,
+			ActiveSessions: 2,
+			FeedsInError:   1,
+		},
+		FeedErrors: []instance.FeedError{
+			{
+				FeedURL:  "https://failing.example.org/feed.xml",
+				Title:    "Failing Feed",
+				Error:    "connection refused",
+				FailedAt: time.Date(2026, 10, 17, 8, 30, 0, 0, time.UTC),
+			},
+		},
+		TableSizes: []instance.TableSize{
+			{Name: "feed_entries", SizeBytes: 2 * 1024 * 1024},
+			{Name: "bookmarks", SizeBytes: 512 * 1024},
+		},
+	}
+
+	cases := []struct {
+		tname     string
+		collector *synchronizing.Collector
+		want      []string
+	}{
+		{
+			tname: "without synchronization statistics",
+			want: []string{
+				"Feed synchronization statistics are not available.",
+			},
+		},
+		{
+			tname:     "with synchronization statistics",
+			collector: synchronizing.NewCollector("test"),
+			want: []string{
+				"No synchronization has run since the service was started.",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			ac := adminController{
+				feedSynchronizingCollector: tc.collector,
+				instanceService:            instance.NewService(instanceRepository),
+				adminDashboardView:         view.New("admin/dashboard.gohtml"),
+			}
+
+			r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin", nil)
+			r = r.WithContext(httpcontext.WithUser(r.Context(), ctxUser))
+
+			w := httptest.NewRecorder()
+			ac.handleDashboardView()(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d", w.Code)
+			}
+
+			body := w.Body.String()
+
+			want := append([]string{
+				`<div class="fs-4">1234</div>`,
+				`<div class="fs-4">5678</div>`,
+				`<div class="fs-4">2.5 MiB</div>`,
+				"<code>feed_entries</code>",
+				"<td>512.0 KiB</td>",
+				"Failing Feed",
+				"https://failing.example.org/feed.xml",
+				"connection refused",
+				"2026-10-17 08:30:00",
+			}, tc.want...)
+
+			for _, s := range want {
+				if !strings.Contains(body, s) {
+					t.Errorf("want %q to be rendered, got:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+)
+
+// handleFeedListView renders the list of all feeds registered on this instance.
+func (ac *adminController) handleFeedListView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		urlQuery := r.URL.Query()
+
+		pageNumber, pageNumberStr, err := paginate.GetPageNumber(urlQuery)
+		if err != nil {
+			log.Warn().Err(err).Str("page_number", pageNumberStr).Msg("invalid page number")
+			view.RedirectOnError(w, r, "/admin/feeds", fmt.Sprintf("invalid page number: %q", pageNumberStr))
+			return
+		}
+
+		feedPage, err := ac.feedAdministratingService.ByPage(ctx, urlQuery.Get("search"), pageNumber)
+		if errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			msg := fmt.Sprintf("invalid page number: %d", pageNumber)
+			log.Error().Err(err).Msg(msg)
+			view.RedirectOnError(w, r, "/admin/feeds", msg)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve feeds")
+			view.PutFlashError(w, "failed to retrieve feeds")
+			http.Redirect(w, r, "/admin", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Feeds",
+			Content: feedPage,
+		}
+
+		ac.adminFeedListView.Render(w, r, viewData)
+	}
+}
+
+// handleFeedView renders the synchronization state of a feed, and the forms to
+// edit its URL or merge it into another feed.
+func (ac *adminController) handleFeedView() func(w http.ResponseWriter, r *http.Request) {
+	type feedViewContent struct {
+		Feed       feedadministrating.Feed
+		Duplicates []feedadministrating.Feed
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+
+		f, err := ac.feedAdministratingService.ByUUID(ctx, feedUUID)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to retrieve feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/admin/feeds", http.StatusSeeOther)
+			return
+		}
+
+		duplicates, err := ac.feedAdministratingService.Duplicates(ctx, f)
+		if err != nil {
+			// duplicates are only offered as merge suggestions
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to retrieve duplicate feeds")
+		}
+
+		viewData := view.Data{
+			Title: fmt.Sprintf("Feed: %s", f.Title),
+			Content: feedViewContent{
+				Feed:       f,
+				Duplicates: duplicates,
+			},
+		}
+
+		ac.adminFeedView.Render(w, r, viewData)
+	}
+}
+
+// handleFeedRefresh forces the synchronization of a feed, ignoring the
+// cached ETag, Last-Modified and content hash.
+func (ac *adminController) handleFeedRefresh() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+		redirectURL := fmt.Sprintf("/admin/feeds/%s", feedUUID)
+
+		if err := ac.feedAdministratingService.Refresh(ctx, feedUUID); err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to refresh feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "the feed has been refreshed")
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+	}
+}
+
+// handleFeedURLUpdate processes the feed URL edition form.
+func (ac *adminController) handleFeedURLUpdate() func(w http.ResponseWriter, r *http.Request) {
+	type feedURLForm struct {
+		URL string `schema:"url"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+		redirectURL := fmt.Sprintf("/admin/feeds/%s", feedUUID)
+
+		var form feedURLForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse feed URL form")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.feedAdministratingService.UpdateURL(ctx, feedUUID, form.URL); err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to update feed URL")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(r, audit.EventAdminFeedURLUpdated, "", form.URL)
+
+		view.PutFlashSuccess(w, "the feed URL has been updated")
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+	}
+}
+
+// handleFeedMerge processes the feed merge form.
+//
+// Subscriptions and read statuses are moved to the target feed, and the
+// source feed is deleted.
+func (ac *adminController) handleFeedMerge() func(w http.ResponseWriter, r *http.Request) {
+	type feedMergeForm struct {
+		TargetURL string `schema:"target_url"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+		redirectURL := fmt.Sprintf("/admin/feeds/%s", feedUUID)
+
+		var form feedMergeForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse feed merge form")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		source, err := ac.feedAdministratingService.ByUUID(ctx, feedUUID)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to retrieve feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/admin/feeds", http.StatusSeeOther)
+			return
+		}
+
+		target, err := ac.feedAdministratingService.Merge(ctx, feedUUID, form.TargetURL)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to merge feeds")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(
+			r,
+			audit.EventAdminFeedMerged,
+			"",
+			fmt.Sprintf("%s -> %s", source.FeedURL, target.FeedURL),
+		)
+
+		view.PutFlashSuccess(w, fmt.Sprintf("feed %q has been merged into %q", source.FeedURL, target.FeedURL))
+		http.Redirect(w, r, fmt.Sprintf("/admin/feeds/%s", target.UUID), http.StatusSeeOther)
+	}
+}
+
+// handleFeedDeleteView renders the feed deletion form.
+func (ac *adminController) handleFeedDeleteView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+
+		f, err := ac.feedAdministratingService.ByUUID(ctx, feedUUID)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to retrieve feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/admin/feeds", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   fmt.Sprintf("Delete feed: %s", f.Title),
+			Content: f,
+		}
+
+		ac.adminFeedDeleteView.Render(w, r, viewData)
+	}
+}
+
+// handleFeedDelete processes the feed deletion form, and notifies subscribers.
+func (ac *adminController) handleFeedDelete() func(w http.ResponseWriter, r *http.Request) {
+	type feedDeleteForm struct {
+		Reason string `schema:"reason"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		feedUUID := chi.URLParam(r, "uuid")
+
+		var form feedDeleteForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse feed deletion form")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		f, err := ac.feedAdministratingService.ByUUID(ctx, feedUUID)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to retrieve feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/admin/feeds", http.StatusSeeOther)
+			return
+		}
+
+		notified, err := ac.feedAdministratingService.Delete(ctx, feedUUID, form.Reason)
+		if err != nil {
+			log.Error().Err(err).Str("feed_uuid", feedUUID).Msg("failed to delete feed")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(r, audit.EventAdminFeedDeleted, "", f.FeedURL)
+
+		view.PutFlashSuccess(
+			w,
+			fmt.Sprintf("feed %q has been deleted; %d subscriber(s) notified", f.FeedURL, notified),
+		)
+		http.Redirect(w, r, "/admin/feeds", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testAdminHTTPFeed = feedadministrating.Feed{
+		Feed: feed.Feed{
+			UUID:    "0b2c4f6e-1a3d-4e5f-8a9b-0c1d2e3f4a5b",
+			FeedURL: "http://example.org/feed.xml",
+			Title:   "Example (HTTP)",
+			ETag:    `W/"abc"`,
+		},
+		Subscribers: 1,
+		Entries:     12,
+		FetchError:  "unexpected status code: 404",
+	}
+	testAdminHTTPSFeed = feedadministrating.Feed{
+		Feed: feed.Feed{
+			UUID:    "7d8e9f0a-1b2c-4d3e-9f4a-5b6c7d8e9f0a",
+			FeedURL: "https://example.org/feed.xml",
+			Title:   "Example",
+		},
+		Subscribers: 1,
+	}
+)
+
+// newAdminFeedRequest builds a request against /admin/feeds/{uuid}/<action>.
+func newAdminFeedRequest(t *testing.T, method string, feedUUID string, action string, form url.Values) *http.Request {
+	t.Helper()
+
+	ctxUser := user.User{UUID: "a2f3e4d5-6b7c-4d8e-9f0a-1b2c3d4e5f60", IsAdmin: true}
+	target := "/admin/feeds/" + feedUUID + action
+
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("uuid", feedUUID)
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return r.WithContext(ctx)
+}
+
+func newTestAdminControllerForFeeds(feedRepository *feedadministrating.FakeRepository, notifier notification.Notifier) adminController {
+	return adminController{
+		feedAdministratingService: feedadministrating.NewService(feedRepository, nil, notifier),
+		adminFeedDeleteView:       view.New("admin/feed_delete.gohtml"),
+		adminFeedListView:         view.New("admin/feed_list.gohtml"),
+		adminFeedView:             view.New("admin/feed.gohtml"),
+	}
+}
+
+func newTestAdminFeedRepository() *feedadministrating.FakeRepository {
+	return &feedadministrating.FakeRepository{
+		Feeds: []feedadministrating.Feed{testAdminHTTPFeed, testAdminHTTPSFeed},
+		Subscribers: map[string][]feedadministrating.Subscriber{
+			testAdminHTTPFeed.UUID: {
+				{UserUUID: "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b", NickName: "jane", Email: "jane.doe@example.org"},
+			},
+		},
+	}
+}
+
+func TestHandleAdminFeedListView(t *testing.T) {
+	ac := newTestAdminControllerForFeeds(newTestAdminFeedRepository(), nil)
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin/feeds", nil)
+	r = r.WithContext(httpcontext.WithUser(r.Context(), user.User{IsAdmin: true}))
+
+	w := httptest.NewRecorder()
+	ac.handleFeedListView()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{
+		testAdminHTTPFeed.FeedURL,
+		testAdminHTTPSFeed.FeedURL,
+		`title="unexpected status code: 404"`,
+		`<span class="badge text-bg-secondary">ETag</span>`,
+		`action="/admin/feeds/` + testAdminHTTPFeed.UUID + `/refresh"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be rendered, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandleAdminFeedView(t *testing.T) {
+	ac := newTestAdminControllerForFeeds(newTestAdminFeedRepository(), nil)
+
+	w := httptest.NewRecorder()
+	ac.handleFeedView()(w, newAdminFeedRequest(t, http.MethodGet, testAdminHTTPFeed.UUID, "", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{
+		`value="` + testAdminHTTPFeed.FeedURL + `"`,
+		`<option value="` + testAdminHTTPSFeed.FeedURL + `">`,
+		testAdminHTTPFeed.FetchError,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be rendered, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandleAdminFeedURLUpdate(t *testing.T) {
+	cases := []struct {
+		tname            string
+		url              string
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantURL          string
+	}{
+		{
+			tname:            "new URL",
+			url:              "https://feeds.example.org/feed.xml",
+			wantFlashLevel:   "success",
+			wantFlashMessage: "the feed URL has been updated",
+			wantURL:          "https://feeds.example.org/feed.xml",
+		},
+		{
+			tname:            "URL registered for another feed",
+			url:              testAdminHTTPSFeed.FeedURL,
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(feedadministrating.ErrFeedURLAlreadyRegistered),
+			wantURL:          testAdminHTTPFeed.FeedURL,
+		},
+		{
+			tname:            "unsupported scheme",
+			url:              "ftp://example.org/feed.xml",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(feed.ErrFeedURLUnsupportedScheme),
+			wantURL:          testAdminHTTPFeed.FeedURL,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			feedRepository := newTestAdminFeedRepository()
+			ac := newTestAdminControllerForFeeds(feedRepository, nil)
+
+			form := url.Values{"url": {tc.url}}
+
+			w := httptest.NewRecorder()
+			ac.handleFeedURLUpdate()(w, newAdminFeedRequest(t, http.MethodPost, testAdminHTTPFeed.UUID, "/url", form))
+
+			if w.Code != http.StatusSeeOther {
+				t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+			}
+
+			if got := decodedFlashLevel(t, w); got != tc.wantFlashLevel {
+				t.Errorf("want flash level %q, got %q", tc.wantFlashLevel, got)
+			}
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, tc.wantFlashMessage) {
+				t.Errorf("want flash message containing %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			if got := feedRepository.Feeds[0].FeedURL; got != tc.wantURL {
+				t.Errorf("want feed URL %q, got %q", tc.wantURL, got)
+			}
+		})
+	}
+}
+
+func TestHandleAdminFeedMerge(t *testing.T) {
+	cases := []struct {
+		tname            string
+		targetURL        string
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantLocation     string
+		wantFeeds        int
+	}{
+		{
+			tname:            "merge into duplicate",
+			targetURL:        testAdminHTTPSFeed.FeedURL,
+			wantFlashLevel:   "success",
+			wantFlashMessage: "has been merged into",
+			wantLocation:     "/admin/feeds/" + testAdminHTTPSFeed.UUID,
+			wantFeeds:        1,
+		},
+		{
+			tname:            "merge into itself",
+			targetURL:        testAdminHTTPFeed.FeedURL,
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(feedadministrating.ErrFeedMergeSameFeed),
+			wantLocation:     "/admin/feeds/" + testAdminHTTPFeed.UUID,
+			wantFeeds:        2,
+		},
+		{
+			tname:            "unknown target",
+			targetURL:        "https://unknown.example.org/feed.xml",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(feed.ErrFeedNotFound),
+			wantLocation:     "/admin/feeds/" + testAdminHTTPFeed.UUID,
+			wantFeeds:        2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			feedRepository := newTestAdminFeedRepository()
+			ac := newTestAdminControllerForFeeds(feedRepository, nil)
+
+			form := url.Values{"target_url": {tc.targetURL}}
+
+			w := httptest.NewRecorder()
+			ac.handleFeedMerge()(w, newAdminFeedRequest(t, http.MethodPost, testAdminHTTPFeed.UUID, "/merge", form))
+
+			if w.Code != http.StatusSeeOther {
+				t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("want location %q, got %q", tc.wantLocation, got)
+			}
+
+			if got := decodedFlashLevel(t, w); got != tc.wantFlashLevel {
+				t.Errorf("want flash level %q, got %q", tc.wantFlashLevel, got)
+			}
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, tc.wantFlashMessage) {
+				t.Errorf("want flash message containing %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			if got := len(feedRepository.Feeds); got != tc.wantFeeds {
+				t.Errorf("want %d feeds, got %d", tc.wantFeeds, got)
+			}
+		})
+	}
+}
+
+func TestHandleAdminFeedDelete(t *testing.T) {
+	feedRepository := newTestAdminFeedRepository()
+	notifier := &notification.FakeNotifier{}
+	ac := newTestAdminControllerForFeeds(feedRepository, notifier)
+
+	form := url.Values{"reason": {"The feed is no longer published."}}
+
+	w := httptest.NewRecorder()
+	ac.handleFeedDelete()(w, newAdminFeedRequest(t, http.MethodPost, testAdminHTTPFeed.UUID, "/delete", form))
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	if got := w.Header().Get("Location"); got != "/admin/feeds" {
+		t.Errorf("want location %q, got %q", "/admin/feeds", got)
+	}
+
+	if got := decodedFlashLevel(t, w); got != "success" {
+		t.Errorf("want flash level %q, got %q", "success", got)
+	}
+	if got := decodedFlashMessage(t, w); !strings.Contains(got, "1 subscriber(s) notified") {
+		t.Errorf("want flash message reporting notified subscribers, got %q", got)
+	}
+
+	if got := len(feedRepository.Feeds); got != 1 {
+		t.Errorf("want 1 feed, got %d", got)
+	}
+
+	if got := len(notifier.Messages); got != 1 {
+		t.Fatalf("want 1 notice, got %d", got)
+	}
+	if !strings.Contains(notifier.Messages[0].Body, "The feed is no longer published.") {
+		t.Errorf("want the notice to include the reason, got %q", notifier.Messages[0].Body)
+	}
+}
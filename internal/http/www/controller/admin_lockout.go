@@ -0,0 +1,96 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// handleUserUnlockView renders the recent failed login attempts for a user, and the
+// form to unlock their account.
+func (ac *adminController) handleUserUnlockView() func(w http.ResponseWriter, r *http.Request) {
+	type userUnlockViewContent struct {
+		User     user.User
+		Summary  lockout.Summary
+		Throttle lockout.Throttle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		userToUnlock, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		summary, err := ac.lockoutService.Status(ctx, userToUnlock.Email)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve failed login attempts")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		throttle, err := ac.lockoutService.Throttle(ctx, userToUnlock.Email)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve login throttling status")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: fmt.Sprintf("Unlock account: %s", userToUnlock.NickName),
+			Content: userUnlockViewContent{
+				User:     userToUnlock,
+				Summary:  summary,
+				Throttle: throttle,
+			},
+		}
+
+		ac.adminUserUnlockView.Render(w, r, viewData)
+	}
+}
+
+// handleUserUnlock clears the failed login attempts recorded for a user, lifting any
+// delay or lock applied to their account.
+func (ac *adminController) handleUserUnlock() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		userToUnlock, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.lockoutService.Unlock(ctx, userToUnlock.Email); err != nil {
+			log.Error().Err(err).Msg("failed to unlock user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(r, audit.EventAdminUserUnlocked, userUUID, userToUnlock.Email)
+
+		view.PutFlashSuccess(w, fmt.Sprintf("user %q has been unlocked", userToUnlock.Email))
+		http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+	}
+}
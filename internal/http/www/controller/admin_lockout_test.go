@@ -0,0 +1,127 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// newUserUnlockRequest builds a request against /admin/users/{uuid}/unlock.
+func newUserUnlockRequest(t *testing.T, method string, userUUID string) *http.Request {
+	t.Helper()
+
+	ctxUser := user.User{UUID: "a2f3e4d5-6b7c-4d8e-9f0a-1b2c3d4e5f60", IsAdmin: true}
+
+	r := httptest.NewRequestWithContext(t.Context(), method, "/admin/users/"+userUUID+"/unlock", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("uuid", userUUID)
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return r.WithContext(ctx)
+}
+
+func newTestAdminControllerForUnlock(t *testing.T, u user.User, lockoutRepository *lockout.FakeRepository) adminController {
+	t.Helper()
+
+	userService := user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t))
+
+	return adminController{
+		lockoutService:      lockout.NewService(lockoutRepository, userService, nil),
+		userService:         userService,
+		adminUserUnlockView: view.New("admin/user_unlock.gohtml"),
+	}
+}
+
+func newTestLockedOutRepository(u user.User) *lockout.FakeRepository {
+	lastFailureAt := time.Now().UTC().Add(-time.Minute)
+
+	failures := make([]lockout.Failure, lockout.AccountLockoutThreshold)
+	for i := range failures {
+		failures[i] = lockout.Failure{Email: u.Email, ClientIP: "198.51.100.7", CreatedAt: lastFailureAt}
+	}
+
+	return &lockout.FakeRepository{Failures: failures}
+}
+
+func TestHandleUserUnlockView(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	t.Run("locked account", func(t *testing.T) {
+		ac := newTestAdminControllerForUnlock(t, u, newTestLockedOutRepository(u))
+
+		w := httptest.NewRecorder()
+		ac.handleUserUnlockView()(w, newUserUnlockRequest(t, http.MethodGet, u.UUID))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		body := w.Body.String()
+
+		for _, want := range []string{
+			"is locked until",
+			"<code>198.51.100.7</code>",
+			`action="/admin/users/` + u.UUID + `/unlock"`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("want %q to be rendered, got:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("no failed attempts", func(t *testing.T) {
+		ac := newTestAdminControllerForUnlock(t, u, &lockout.FakeRepository{})
+
+		w := httptest.NewRecorder()
+		ac.handleUserUnlockView()(w, newUserUnlockRequest(t, http.MethodGet, u.UUID))
+
+		body := w.Body.String()
+
+		if !strings.Contains(body, "There were no recent failed login attempts") {
+			t.Errorf("want no failed attempts to be reported, got:\n%s", body)
+		}
+		if strings.Contains(body, `action="/admin/users/`+u.UUID+`/unlock"`) {
+			t.Errorf("want no unlock form, got:\n%s", body)
+		}
+	})
+}
+
+func TestHandleUserUnlock(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	lockoutRepository := newTestLockedOutRepository(u)
+	ac := newTestAdminControllerForUnlock(t, u, lockoutRepository)
+
+	w := httptest.NewRecorder()
+	ac.handleUserUnlock()(w, newUserUnlockRequest(t, http.MethodPost, u.UUID))
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	if got := w.Header().Get("Location"); got != "/admin/users" {
+		t.Errorf("want location %q, got %q", "/admin/users", got)
+	}
+
+	if got := decodedFlashLevel(t, w); got != "success" {
+		t.Errorf("want flash level %q, got %q", "success", got)
+	}
+
+	if got := len(lockoutRepository.Failures); got != 0 {
+		t.Errorf("want failures to be cleared, %d remain", got)
+	}
+}
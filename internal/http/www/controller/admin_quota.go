@@ -0,0 +1,108 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// handleUserQuotaView renders a user's storage usage, and the form to set per-user limits.
+func (ac *adminController) handleUserQuotaView() func(w http.ResponseWriter, r *http.Request) {
+	type userQuotaViewContent struct {
+		User    user.User
+		Summary quotaSummaryContent
+		Form    quotaOverrideForm
+
+		Defaults                 quota.Limits
+		DefaultMaxImportFileSize string
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		quotaUser, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		summary, err := ac.quotaService.SummaryByUserUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve storage usage")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		defaults := ac.quotaService.Defaults()
+
+		viewData := view.Data{
+			Title: fmt.Sprintf("Storage quota: %s", quotaUser.NickName),
+			Content: userQuotaViewContent{
+				User:                     quotaUser,
+				Summary:                  newQuotaSummaryContent(summary),
+				Form:                     newQuotaOverrideForm(summary.Override),
+				Defaults:                 defaults,
+				DefaultMaxImportFileSize: formatImportFileSize(defaults.MaxImportFileSize),
+			},
+		}
+
+		ac.adminUserQuotaView.Render(w, r, viewData)
+	}
+}
+
+// handleUserQuotaUpdate processes the per-user limits form.
+func (ac *adminController) handleUserQuotaUpdate() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userUUID := chi.URLParam(r, "uuid")
+
+		quotaUser, err := ac.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve user")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+
+		var form quotaOverrideForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse quota form")
+			view.PutFlashError(w, err.Error())
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		override, err := form.override(userUUID)
+		if err != nil {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if err := ac.quotaService.UpdateOverride(ctx, override); err != nil {
+			log.Error().Err(err).Msg("failed to update quota")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		ac.recordAdminEvent(r, audit.EventAdminQuotaUpdated, userUUID, quotaUser.Email)
+
+		view.PutFlashSuccess(w, fmt.Sprintf("storage quota has been updated for user %q", quotaUser.Email))
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// newUserQuotaRequest builds a request against /admin/users/{uuid}/quota.
+func newUserQuotaRequest(t *testing.T, method string, ctxUser user.User, userUUID string, form url.Values) *http.Request {
+	t.Helper()
+
+	target := "/admin/users/" + userUUID + "/quota"
+
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("uuid", userUUID)
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return r.WithContext(ctx)
+}
+
+func newTestAdminControllerForQuota(t *testing.T, u user.User, quotaRepository *quota.FakeRepository) adminController {
+	t.Helper()
+
+	quotaService, err := quota.NewService(quotaRepository, quota.Limits{MaxBookmarks: 100})
+	if err != nil {
+		t.Fatalf("failed to create quota service: %q", err)
+	}
+
+	return adminController{
+		quotaService:       quotaService,
+		userService:        user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
+		adminUserQuotaView: view.New("admin/user_quota.gohtml", "account/quota_usage.gohtml"),
+	}
+}
+
+func TestHandleUserQuotaView(t *testing.T) {
+	ctxUser := user.User{UUID: "a2f3e4d5-6b7c-4d8e-9f0a-1b2c3d4e5f60", IsAdmin: true}
+	u := newTestTwoFactorUser(t)
+
+	maxSubscriptions := int64(10)
+	quotaRepository := &quota.FakeRepository{
+		Overrides: map[string]quota.Override{
+			u.UUID: {UserUUID: u.UUID, MaxSubscriptions: &maxSubscriptions},
+		},
+		Usages: map[string]quota.Usage{
+			u.UUID: {Bookmarks: 42, Subscriptions: 3},
+		},
+	}
+	ac := newTestAdminControllerForQuota(t, u, quotaRepository)
+
+	w := httptest.NewRecorder()
+	ac.handleUserQuotaView()(w, newUserQuotaRequest(t, http.MethodGet, ctxUser, u.UUID, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+
+	for _, want := range []string{
+		u.Email,
+		`action="/admin/users/` + u.UUID + `/quota"`,
+		`name="max_bookmarks"` + "\n" + `            value=""` + "\n" + `            placeholder="100"`,
+		`name="max_subscriptions"` + "\n" + `            value="10"`,
+		`aria-valuenow="42"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("want %q to be rendered, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandleUserQuotaUpdate(t *testing.T) {
+	ctxUser := user.User{UUID: "a2f3e4d5-6b7c-4d8e-9f0a-1b2c3d4e5f60", IsAdmin: true}
+	u := newTestTwoFactorUser(t)
+
+	maxBookmarks := int64(500)
+	maxImportFileSize := int64(0)
+
+	cases := []struct {
+		tname            string
+		form             url.Values
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantOverride     *quota.Override
+	}{
+		{
+			tname: "set per-user limits",
+			form: url.Values{
+				"max_bookmarks":            {"500"},
+				"max_subscriptions":        {""},
+				"max_import_file_size_mib": {"0"},
+			},
+			wantFlashLevel:   "success",
+			wantFlashMessage: "storage quota has been updated",
+			wantOverride: &quota.Override{
+				UserUUID:          u.UUID,
+				MaxBookmarks:      &maxBookmarks,
+				MaxImportFileSize: &maxImportFileSize,
+			},
+		},
+		{
+			tname: "clear per-user limits",
+			form: url.Values{
+				"max_bookmarks":            {""},
+				"max_subscriptions":        {""},
+				"max_import_file_size_mib": {""},
+			},
+			wantFlashLevel:   "success",
+			wantFlashMessage: "storage quota has been updated",
+		},
+		{
+			tname: "invalid limit",
+			form: url.Values{
+				"max_bookmarks": {"many"},
+			},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: "Limits must be whole numbers.",
+		},
+		{
+			tname: "negative limit",
+			form: url.Values{
+				"max_bookmarks": {"-1"},
+			},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: "Limits must be positive or zero.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			quotaRepository := &quota.FakeRepository{}
+			ac := newTestAdminControllerForQuota(t, u, quotaRepository)
+
+			w := httptest.NewRecorder()
+			ac.handleUserQuotaUpdate()(w, newUserQuotaRequest(t, http.MethodPost, ctxUser, u.UUID, tc.form))
+
+			if w.Code != http.StatusSeeOther {
+				t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+			}
+
+			if got := decodedFlashLevel(t, w); got != tc.wantFlashLevel {
+				t.Errorf("want flash level %q, got %q", tc.wantFlashLevel, got)
+			}
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, tc.wantFlashMessage) {
+				t.Errorf("want flash message containing %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			got, ok := quotaRepository.Overrides[u.UUID]
+
+			if tc.wantOverride == nil {
+				if ok {
+					t.Errorf("want no override, got %+v", got)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("want an override to be saved")
+			}
+
+			assertLimitEquals(t, "MaxBookmarks", got.MaxBookmarks, tc.wantOverride.MaxBookmarks)
+			assertLimitEquals(t, "MaxSubscriptions", got.MaxSubscriptions, tc.wantOverride.MaxSubscriptions)
+			assertLimitEquals(t, "MaxImportFileSize", got.MaxImportFileSize, tc.wantOverride.MaxImportFileSize)
+		})
+	}
+}
+
+func assertLimitEquals(t *testing.T, name string, got, want *int64) {
+	t.Helper()
+
+	switch {
+	case got == nil && want == nil:
+		return
+	case got == nil || want == nil:
+		t.Errorf("want %s %v, got %v", name, want, got)
+	case *got != *want:
+		t.Errorf("want %s %d, got %d", name, *want, *got)
+	}
+}
@@ -18,16 +18,19 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 // newTestAdminControllerForUserDelete wires an adminController against the
 // given user, for exercising the user delete handlers.
-func newTestAdminControllerForUserDelete(u user.User) adminController {
+func newTestAdminControllerForUserDelete(t *testing.T, u user.User) adminController {
+	t.Helper()
+
 	repo := &user.FakeRepository{Users: []user.User{u}}
 
 	return adminController{
-		userService:         user.NewService(repo),
+		userService:         user.NewService(repo, user.FakePasswordHasher(t)),
 		adminUserDeleteView: view.New("admin/user_delete.gohtml"),
 	}
 }
@@ -125,7 +128,7 @@ func TestHandleUserEditView(t *testing.T) {
 
 	newTestController := func(u user.User) adminController {
 		return adminController{
-			userService:       user.NewService(&user.FakeRepository{Users: []user.User{u}}),
+			userService:       user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
 			adminUserEditView: view.New("admin/user_edit.gohtml"),
 		}
 	}
@@ -208,7 +211,7 @@ func TestHandleUserAdd(t *testing.T) {
 			},
 		}
 		ac := adminController{
-			userService: user.NewService(userRepo),
+			userService: user.NewService(userRepo, user.FakePasswordHasher(t)),
 		}
 
 		form := url.Values{}
@@ -242,7 +245,7 @@ func TestHandleUserEdit(t *testing.T) {
 
 	t.Run("editing a user revokes their existing sessions", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		targetUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -311,7 +314,7 @@ func TestHandleUserEdit(t *testing.T) {
 
 	t.Run("session revocation failure still redirects but flashes a warning instead of success", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		targetUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -377,7 +380,7 @@ func TestHandleUserEdit(t *testing.T) {
 			},
 		}
 		ac := adminController{
-			userService: user.NewService(userRepo),
+			userService: user.NewService(userRepo, user.FakePasswordHasher(t)),
 		}
 
 		form := url.Values{}
@@ -415,7 +418,7 @@ func TestHandleUserEdit(t *testing.T) {
 			},
 		}
 		ac := adminController{
-			userService: user.NewService(userRepo),
+			userService: user.NewService(userRepo, user.FakePasswordHasher(t)),
 		}
 
 		form := url.Values{}
@@ -442,7 +445,7 @@ func TestHandleUserEdit(t *testing.T) {
 
 	t.Run("htmx request re-renders the user's row and closes the modal", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		targetUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -509,7 +512,7 @@ func TestHandleUserEdit(t *testing.T) {
 
 	t.Run("session revocation failure, htmx request uses HX-Redirect", func(t *testing.T) {
 		userRepo := &user.FakeRepository{}
-		userService := user.NewService(userRepo)
+		userService := user.NewService(userRepo, user.FakePasswordHasher(t))
 
 		targetUser := user.User{
 			UUID:        fake.UUID().V4(),
@@ -575,7 +578,7 @@ func TestHandleUserDeleteView(t *testing.T) {
 
 	t.Run("plain browser request renders the full page", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		r := newUserDeleteViewRequest(t, ctxUser, u.UUID, false)
 		w := httptest.NewRecorder()
 
@@ -599,7 +602,7 @@ func TestHandleUserDeleteView(t *testing.T) {
 
 	t.Run("htmx request renders only the form fragment", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		r := newUserDeleteViewRequest(t, ctxUser, u.UUID, true)
 		w := httptest.NewRecorder()
 
@@ -623,7 +626,7 @@ func TestHandleUserDeleteView(t *testing.T) {
 
 	t.Run("unknown user, htmx request uses HX-Redirect", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		unknownUUID := fake.UUID().V4()
 		r := newUserDeleteViewRequest(t, ctxUser, unknownUUID, true)
 		w := httptest.NewRecorder()
@@ -647,7 +650,7 @@ func TestHandleUserDelete(t *testing.T) {
 
 	t.Run("plain browser request deletes and redirects", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		r := newUserDeletePostRequest(t, ctxUser, u.UUID, false)
 		w := httptest.NewRecorder()
 
@@ -663,7 +666,7 @@ func TestHandleUserDelete(t *testing.T) {
 
 	t.Run("htmx request retargets an empty response into the user's row and closes the modal", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		r := newUserDeletePostRequest(t, ctxUser, u.UUID, true)
 		w := httptest.NewRecorder()
 
@@ -688,7 +691,7 @@ func TestHandleUserDelete(t *testing.T) {
 
 	t.Run("unknown user, htmx request uses HX-Redirect", func(t *testing.T) {
 		u := newFixture()
-		ac := newTestAdminControllerForUserDelete(u)
+		ac := newTestAdminControllerForUserDelete(t, u)
 		unknownUUID := fake.UUID().V4()
 		r := newUserDeletePostRequest(t, ctxUser, unknownUUID, true)
 		w := httptest.NewRecorder()
@@ -698,3 +701,41 @@ func TestHandleUserDelete(t *testing.T) {
 		assertHXRedirectOnError(t, w, "/admin/users/"+unknownUUID+"/delete")
 	})
 }
+
+func TestHandleUserTwoFactorReset(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	adminUser := user.User{UUID: "a0000000-0000-4000-8000-000000000001", IsAdmin: true}
+
+	twoFactorRepo := &twofactor.FakeRepository{
+		TOTPs: []twofactor.TOTP{
+			{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true},
+		},
+		RecoveryCodes: []twofactor.RecoveryCode{
+			{UserUUID: u.UUID, CodeHash: "recovery-code-hash"},
+		},
+	}
+
+	ac := adminController{
+		twoFactorService: newTestTwoFactorService(t, twoFactorRepo),
+		userService:      user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
+	}
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/admin/users/"+u.UUID+"/two-factor/reset", nil)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("uuid", u.UUID)
+	ctx := httpcontext.WithUser(r.Context(), adminUser)
+	r = r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, routeCtx))
+	w := httptest.NewRecorder()
+
+	ac.handleUserTwoFactorReset()(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	if len(twoFactorRepo.TOTPs) != 0 {
+		t.Errorf("want no TOTP enrollment, got %d", len(twoFactorRepo.TOTPs))
+	}
+	if len(twoFactorRepo.RecoveryCodes) != 0 {
+		t.Errorf("want no recovery codes, got %d", len(twoFactorRepo.RecoveryCodes))
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+)
+
+// recordAuditEvent appends an event to the security audit log, with the client IP address of
+// the request.
+//
+// Failing to record an event is logged, and does not prevent the action from completing.
+// Events are not recorded if auditService is nil.
+func recordAuditEvent(r *http.Request, auditService *audit.Service, event audit.Event) {
+	if auditService == nil {
+		return
+	}
+
+	ctx := r.Context()
+	event.ClientIP = chimiddleware.GetClientIP(ctx)
+
+	if err := auditService.Record(ctx, event); err != nil {
+		log.Error().
+			Err(err).
+			Str("event_type", string(event.Type)).
+			Msg("failed to record audit event")
+	}
+}
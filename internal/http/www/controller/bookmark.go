@@ -6,6 +6,7 @@ package controller
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -24,36 +25,59 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
 	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
 	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
 	bookmarkimporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/importing"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	bookmarksuggesting "github.com/virtualtam/sparklemuffin/pkg/bookmark/suggesting"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 // RegisterBookmarkHandlers registers handlers to manage and display bookmarks.
 func RegisterBookmarkHandlers(
 	r *chi.Mux,
 	publicURL *url.URL,
+	archivingService *bookmarkarchiving.Service,
+	auditService *audit.Service,
 	bookmarkService *bookmark.Service,
+	checkingService *bookmarkchecking.Service,
 	exportingService *bookmarkexporting.Service,
 	importingService *bookmarkimporting.Service,
 	queryingService *bookmarkquerying.Service,
+	suggestingService *bookmarksuggesting.Service,
 	userService *user.Service,
+	webhookService *webhook.Service,
 ) {
 	bc := bookmarkController{
 		publicURL: publicURL,
 
-		bookmarkService:  bookmarkService,
-		exportingService: exportingService,
-		importingService: importingService,
-		queryingService:  queryingService,
-		userService:      userService,
+		archivingService:  archivingService,
+		auditService:      auditService,
+		bookmarkService:   bookmarkService,
+		checkingService:   checkingService,
+		exportingService:  exportingService,
+		importingService:  importingService,
+		queryingService:   queryingService,
+		suggestingService: suggestingService,
+		userService:       userService,
+		webhookService:    webhookService,
 
-		bookmarkAddView:    view.New("bookmark/bookmark_add.gohtml"),
-		bookmarkDeleteView: view.New("bookmark/bookmark_delete.gohtml"),
-		bookmarkEditView:   view.New("bookmark/bookmark_edit.gohtml"),
-		bookmarkListView:   view.New("bookmark/bookmark_list.gohtml", "bookmark/bookmark_row.gohtml"),
+		bookmarkAddView:        view.New("bookmark/bookmark_add.gohtml"),
+		bookmarkBulkDeleteView: view.New("bookmark/bookmark_bulk_delete.gohtml"),
+		bookmarkDeleteView:     view.New("bookmark/bookmark_delete.gohtml"),
+		bookmarkEditView:       view.New("bookmark/bookmark_edit.gohtml"),
+		bookmarkListView:       view.New("bookmark/bookmark_list.gohtml", "bookmark/bookmark_row.gohtml"),
+
+		bookmarkArchiveView: view.New("bookmark/bookmark_archive.gohtml"),
+
+		bookmarkLinkDeleteView: view.New("bookmark/link_delete.gohtml"),
+		bookmarkLinkUpdateView: view.New("bookmark/link_update.gohtml"),
 
 		bookmarkExportView: view.New("bookmark/bookmark_export.gohtml"),
 		bookmarkImportView: view.New("bookmark/bookmark_import.gohtml"),
@@ -63,6 +87,7 @@ func RegisterBookmarkHandlers(
 		tagDeleteView: view.New("bookmark/tag_delete.gohtml"),
 		tagEditView:   view.New("bookmark/tag_edit.gohtml"),
 		tagListView:   view.New("bookmark/tag_list.gohtml"),
+		tagManageView: view.New("bookmark/tag_manage.gohtml"),
 	}
 
 	// bookmarks
@@ -74,6 +99,12 @@ func RegisterBookmarkHandlers(
 		r.Get("/", bc.handleBookmarkListView())
 		r.Get("/add", bc.handleBookmarkAddView())
 		r.Post("/add", bc.handleBookmarkAdd())
+		r.Get("/add/suggest", bc.handleBookmarkAddSuggest())
+		r.Post("/bulk", bc.handleBookmarkBulkEdit())
+		r.Post("/bulk/export", bc.handleBookmarkBulkExport())
+		r.Get("/{uid}/archive", bc.handleBookmarkArchive())
+		r.Post("/{uid}/archive", bc.handleBookmarkArchiveRequest())
+		r.Get("/{uid}/archive/status", bc.handleBookmarkArchiveStatusView())
 		r.Get("/{uid}/delete", bc.handleBookmarkDeleteView())
 		r.Post("/{uid}/delete", bc.handleBookmarkDelete())
 		r.Get("/{uid}/edit", bc.handleBookmarkEditView())
@@ -84,8 +115,21 @@ func RegisterBookmarkHandlers(
 		r.Get("/import", bc.handleBookmarkImportView())
 		r.Post("/import", bc.handleBookmarkImport())
 
+		r.Route("/links", func(sr chi.Router) {
+			sr.Get("/{status}", bc.handleBookmarkLinkListView())
+			sr.Get("/dead/delete", bc.handleBookmarkLinkDeleteView())
+			sr.Post("/dead/delete", bc.handleBookmarkLinkDelete())
+			sr.Get("/redirected/update", bc.handleBookmarkLinkUpdateView())
+			sr.Post("/redirected/update", bc.handleBookmarkLinkUpdate())
+		})
+
 		r.Route("/tags", func(sr chi.Router) {
 			sr.Get("/", bc.handleTagListView())
+			sr.Get("/manage", bc.handleTagManageView())
+			sr.Post("/merge", bc.handleTagMerge())
+			sr.Post("/aliases", bc.handleTagAliasAdd())
+			sr.Post("/aliases/delete", bc.handleTagAliasDelete())
+			sr.Post("/preferences", bc.handleTagPreferencesUpdate())
 			sr.Get("/{name}/delete", bc.handleTagDeleteView())
 			sr.Post("/{name}/delete", bc.handleTagDelete())
 			sr.Get("/{name}/edit", bc.handleTagEditView())
@@ -104,16 +148,27 @@ func RegisterBookmarkHandlers(
 type bookmarkController struct {
 	publicURL *url.URL
 
-	bookmarkService  *bookmark.Service
-	exportingService *bookmarkexporting.Service
-	importingService *bookmarkimporting.Service
-	queryingService  *bookmarkquerying.Service
-	userService      *user.Service
+	archivingService  *bookmarkarchiving.Service
+	auditService      *audit.Service
+	bookmarkService   *bookmark.Service
+	checkingService   *bookmarkchecking.Service
+	exportingService  *bookmarkexporting.Service
+	importingService  *bookmarkimporting.Service
+	queryingService   *bookmarkquerying.Service
+	suggestingService *bookmarksuggesting.Service
+	userService       *user.Service
+	webhookService    *webhook.Service
 
-	bookmarkAddView    *view.View
-	bookmarkDeleteView *view.View
-	bookmarkEditView   *view.View
-	bookmarkListView   *view.View
+	bookmarkAddView        *view.View
+	bookmarkBulkDeleteView *view.View
+	bookmarkDeleteView     *view.View
+	bookmarkEditView       *view.View
+	bookmarkListView       *view.View
+
+	bookmarkArchiveView *view.View
+
+	bookmarkLinkDeleteView *view.View
+	bookmarkLinkUpdateView *view.View
 
 	bookmarkExportView *view.View
 	bookmarkImportView *view.View
@@ -123,12 +178,14 @@ type bookmarkController struct {
 	tagDeleteView *view.View
 	tagEditView   *view.View
 	tagListView   *view.View
+	tagManageView *view.View
 }
 
 type bookmarkFormContent struct {
-	Bookmark *bookmark.Bookmark
-	Tags     []string
-	Conflict *bookmarkFormConflict
+	Bookmark   *bookmark.Bookmark
+	Tags       []string
+	Conflict   *bookmarkFormConflict
+	Suggestion *bookmarkFormSuggestion
 }
 
 // bookmarkFormConflict is set when a bookmark add attempt collided with an
@@ -143,6 +200,15 @@ type bookmarkFormConflict struct {
 	NewDescriptionRows int
 }
 
+// tagListContent holds the tags displayed on the tag list page.
+type tagListContent struct {
+	bookmarkquerying.TagPage
+
+	// Tree holds all tags arranged as a hierarchy; it is only set when tags are neither
+	// filtered by name nor browsed page by page.
+	Tree []bookmarkquerying.TagNode
+}
+
 // handleBookmarkAddView renders the bookmark addition form.
 func (bc *bookmarkController) handleBookmarkAddView() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -159,7 +225,8 @@ func (bc *bookmarkController) handleBookmarkAddView() func(w http.ResponseWriter
 
 		viewData := view.Data{
 			Content: bookmarkFormContent{
-				Tags: tags,
+				Bookmark: &bookmark.Bookmark{},
+				Tags:     tags,
 			},
 			Title: "Add bookmark",
 		}
@@ -175,6 +242,7 @@ func (bc *bookmarkController) handleBookmarkAdd() func(w http.ResponseWriter, r
 		Description string `schema:"description"`
 		Private     bool   `schema:"private"`
 		Tags        string `schema:"tags"`
+		Archive     bool   `schema:"archive"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -204,16 +272,42 @@ func (bc *bookmarkController) handleBookmarkAdd() func(w http.ResponseWriter, r
 				return
 			}
 
+			if errors.Is(err, quota.ErrBookmarkLimitReached) {
+				view.PutFlashError(w, userFacingError(err))
+				http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+				return
+			}
+
 			log.Error().Err(err).Msg("failed to add bookmark")
 			view.PutFlashError(w, "failed to add bookmark")
 			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 			return
 		}
 
+		if added, err := bc.bookmarkService.ByURL(ctx, ctxUser.UUID, newBookmark.URL); err != nil {
+			log.Error().Err(err).Msg("failed to retrieve added bookmark")
+		} else {
+			bc.notifyWebhooks(ctx, webhook.EventBookmarkAdded, added)
+
+			if form.Archive {
+				bc.requestArchive(ctx, w, ctxUser.UUID, added)
+			}
+		}
+
 		http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
 	}
 }
 
+// requestArchive queues a newly added bookmark's page to be archived.
+//
+// The bookmark has already been saved, so a failure is only reported to the user.
+func (bc *bookmarkController) requestArchive(ctx context.Context, w http.ResponseWriter, userUUID string, b bookmark.Bookmark) {
+	if err := bc.archivingService.Request(ctx, userUUID, b.UID); err != nil {
+		log.Error().Err(err).Str("bookmark_uid", b.UID).Msg("failed to request page archiving")
+		view.PutFlashError(w, "The bookmark was saved, but the page could not be queued for archiving")
+	}
+}
+
 // renderBookmarkAddConflict renders the existing bookmark's edition form when
 // a bookmark add attempt collided with its URL, pre-filled with a merge of
 // the existing bookmark's data and what was just submitted, so the user's
@@ -243,6 +337,14 @@ func (bc *bookmarkController) renderBookmarkAddConflict(w http.ResponseWriter, r
 		return
 	}
 
+	tagPolicy, err := bc.bookmarkService.TagPolicyByUserUUID(ctx, ctxUser.UUID)
+	if err != nil {
+		log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tag policy")
+		view.PutFlashError(w, "failed to add bookmark")
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+		return
+	}
+
 	mergedBookmark := bookmark.Bookmark{
 		UserUUID:    ctxUser.UUID,
 		UID:         existingBookmark.UID,
@@ -252,7 +354,7 @@ func (bc *bookmarkController) renderBookmarkAddConflict(w http.ResponseWriter, r
 		Private:     existingBookmark.Private,
 		Tags:        append(append([]string{}, existingBookmark.Tags...), submitted.Tags...),
 	}
-	mergedBookmark.Normalize()
+	mergedBookmark.Normalize(tagPolicy)
 
 	viewData := view.Data{
 		Content: bookmarkFormContent{
@@ -321,12 +423,21 @@ func (bc *bookmarkController) handleBookmarkDelete() func(w http.ResponseWriter,
 		ctx := r.Context()
 		ctxUser := httpcontext.UserValue(ctx)
 
+		deleted, err := bc.bookmarkService.ByUID(ctx, ctxUser.UUID, bookmarkUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve bookmark")
+			view.RedirectOnError(w, r, r.URL.Path, "failed to delete bookmark")
+			return
+		}
+
 		if err := bc.bookmarkService.Delete(ctx, ctxUser.UUID, bookmarkUID); err != nil {
 			log.Error().Err(err).Msg("failed to delete bookmark")
 			view.RedirectOnError(w, r, r.URL.Path, "failed to delete bookmark")
 			return
 		}
 
+		bc.notifyWebhooks(ctx, webhook.EventBookmarkDeleted, deleted)
+
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
 			w.Header().Set(htmx.HeaderRetarget, "#bookmark-row-"+bookmarkUID)
 			w.Header().Set(htmx.HeaderReswap, "outerHTML")
@@ -431,14 +542,16 @@ func (bc *bookmarkController) handleBookmarkEdit() func(w http.ResponseWriter, r
 			return
 		}
 
-		if r.Header.Get(htmx.HeaderRequest) == "true" {
-			updated, err := bc.bookmarkService.ByUID(ctx, ctxUser.UUID, bookmarkUID)
-			if err != nil {
-				log.Error().Err(err).Msg("failed to retrieve bookmark")
-				view.RedirectOnError(w, r, "/bookmarks", "failed to retrieve bookmark")
-				return
-			}
+		updated, err := bc.bookmarkService.ByUID(ctx, ctxUser.UUID, bookmarkUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve bookmark")
+			view.RedirectOnError(w, r, "/bookmarks", "failed to retrieve bookmark")
+			return
+		}
 
+		bc.notifyWebhooks(ctx, webhook.EventBookmarkUpdated, updated)
+
+		if r.Header.Get(htmx.HeaderRequest) == "true" {
 			owner := bookmarkquerying.Owner{UUID: ctxUser.UUID, NickName: ctxUser.NickName, DisplayName: ctxUser.DisplayName}
 
 			w.Header().Set(htmx.HeaderRetarget, "#bookmark-row-"+bookmarkUID)
@@ -490,6 +603,10 @@ func (bc *bookmarkController) handleBookmarkListView() func(w http.ResponseWrite
 				log.Error().Err(err).Msg(msg)
 				view.RedirectOnError(w, r, "/bookmarks", msg)
 				return
+			} else if errors.Is(err, search.ErrQueryInvalid) {
+				log.Warn().Err(err).Str("search", searchTermsParam).Msg("invalid search query")
+				view.RedirectOnError(w, r, "/bookmarks", searchQueryErrorMessage(err))
+				return
 			} else if err != nil {
 				log.Error().Err(err).Msg("failed to retrieve bookmarks")
 				view.RedirectOnError(w, r, "/bookmarks", "failed to retrieve bookmarks")
@@ -613,6 +730,13 @@ func (bc *bookmarkController) handleBookmarkExport() func(w http.ResponseWriter,
 			return
 		}
 
+		recordAuditEvent(r, bc.auditService, audit.Event{
+			Type:       audit.EventBookmarksExported,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    fmt.Sprintf("format: %s, visibility: %s", form.Format, form.Visibility),
+		})
+
 		filename := fmt.Sprintf("bookmarks-%s.%s", form.Visibility, fileExtension)
 
 		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
@@ -638,6 +762,17 @@ func (bc *bookmarkController) handleBookmarkImportView() func(w http.ResponseWri
 // handleBookmarkImport processes data submitted through the bookmark import form.
 func (bc *bookmarkController) handleBookmarkImport() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		maxImportFileSize, err := bc.importingService.MaxImportFileSize(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve import file size limit")
+			view.PutFlashError(w, "failed to process import form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
 		multipartReader, err := r.MultipartReader()
 		if err != nil {
 			log.Error().Err(err).Msg("failed to access multipart reader")
@@ -672,11 +807,11 @@ func (bc *bookmarkController) handleBookmarkImport() func(w http.ResponseWriter,
 
 			switch part.FormName() {
 			case "importfile":
-				_, err = io.Copy(importFileWriter, part)
+				_, err = io.Copy(importFileWriter, limitImportFileReader(part, maxImportFileSize))
 			case "on-conflict":
-				_, err = io.Copy(onConflictStrategyWriter, part)
+				_, err = io.Copy(onConflictStrategyWriter, io.LimitReader(part, importFormValueMaxSize))
 			case "visibility":
-				_, err = io.Copy(visibilityWriter, part)
+				_, err = io.Copy(visibilityWriter, io.LimitReader(part, importFormValueMaxSize))
 			default:
 				err = fmt.Errorf("unexpected multipart form field: %q", part.FormName())
 			}
@@ -689,8 +824,11 @@ func (bc *bookmarkController) handleBookmarkImport() func(w http.ResponseWriter,
 			}
 		}
 
-		ctx := r.Context()
-		ctxUser := httpcontext.UserValue(ctx)
+		if err := bc.importingService.ValidateImportFileSize(ctx, ctxUser.UUID, int64(importFileBuffer.Len())); err != nil {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
 
 		document, err := netscape.Unmarshal(importFileBuffer.Bytes())
 		if err != nil {
@@ -704,13 +842,24 @@ func (bc *bookmarkController) handleBookmarkImport() func(w http.ResponseWriter,
 		visibility := bookmarkimporting.Visibility(visibilityBuffer.String())
 
 		importStatus, err := bc.importingService.ImportFromNetscapeDocument(ctx, ctxUser.UUID, document, visibility, overwrite)
-		if err != nil {
+		if errors.Is(err, quota.ErrBookmarkLimitReached) {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		} else if err != nil {
 			log.Error().Err(err).Msg("failed to save imported bookmarks")
 			view.PutFlashError(w, "failed to save imported bookmarks")
 			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 			return
 		}
 
+		recordAuditEvent(r, bc.auditService, audit.Event{
+			Type:       audit.EventBookmarksImported,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    importStatus.Summary(),
+		})
+
 		view.PutFlashSuccess(w, fmt.Sprintf("Import status: %s", importStatus.Summary()))
 		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 	}
@@ -759,6 +908,11 @@ func (bc *bookmarkController) handlePublicBookmarkListView() func(w http.Respons
 				view.PutFlashError(w, msg)
 				http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 				return
+			} else if errors.Is(err, search.ErrQueryInvalid) {
+				log.Warn().Err(err).Str("search", searchTermsParam).Msg("invalid search query")
+				view.PutFlashError(w, searchQueryErrorMessage(err))
+				http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+				return
 			} else if err != nil {
 				log.Error().Err(err).Msg("failed to retrieve bookmarks")
 				view.PutFlashError(w, "failed to retrieve bookmarks")
@@ -899,7 +1053,7 @@ func (bc *bookmarkController) handleTagDeleteView() func(w http.ResponseWriter,
 		tag := bookmarkquerying.NewTag(name, 0)
 
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
-			formData := map[string]any{"Tag": tag, "InModal": true}
+			formData := map[string]any{"Tag": tag, "InModal": true, "Tree": r.URL.Query().Get("tree") == "true"}
 			if err := bc.tagDeleteView.RenderTemplate(w, "tagDeleteForm", formData); err != nil {
 				log.Error().Err(err).Msg("failed to render tag delete form fragment")
 				http.Error(w, "Something went wrong", http.StatusInternalServerError)
@@ -921,13 +1075,27 @@ func (bc *bookmarkController) handleTagDeleteView() func(w http.ResponseWriter,
 // On success:
 //   - htmx request: retargets/reswaps an empty response into the tag's row
 //     (outerHTML), removing it, and fires a "modal:close" client-side event
-//     so the tag list page's delete modal closes.
+//     so the tag list page's delete modal closes. When the tag tree is
+//     displayed, or descendants are deleted as well, the page is refreshed
+//     instead, as several rows may be affected.
 //   - plain request: flash + redirect to the tag list, as before.
 //
 // On error, it falls back to the same flash+redirect (or HX-Redirect, for
 // htmx requests) behavior used throughout this file.
 func (bc *bookmarkController) handleTagDelete() func(w http.ResponseWriter, r *http.Request) {
+	type tagDeleteForm struct {
+		Cascade bool `schema:"cascade"`
+		Tree    bool `schema:"tree"`
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
+		var form tagDeleteForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse tag deletion form")
+			view.RedirectOnError(w, r, r.URL.Path, "failed to process form")
+			return
+		}
+
 		nameBase64 := chi.URLParam(r, "name")
 
 		nameBytes, err := base64.URLEncoding.DecodeString(nameBase64)
@@ -945,6 +1113,7 @@ func (bc *bookmarkController) handleTagDelete() func(w http.ResponseWriter, r *h
 		tagDelete := bookmark.TagDeleteQuery{
 			UserUUID: ctxUser.UUID,
 			Name:     name,
+			Cascade:  form.Cascade,
 		}
 
 		updated, err := bc.bookmarkService.DeleteTag(ctx, tagDelete)
@@ -954,6 +1123,13 @@ func (bc *bookmarkController) handleTagDelete() func(w http.ResponseWriter, r *h
 			return
 		}
 
+		if r.Header.Get(htmx.HeaderRequest) == "true" && (form.Tree || form.Cascade) {
+			view.PutFlashSuccess(w, fmt.Sprintf("Tag deleted from %d bookmarks", updated))
+			w.Header().Set(htmx.HeaderRefresh, "true")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
 			w.Header().Set(htmx.HeaderRetarget, fmt.Sprintf("[id='tag-row-%s']", nameBase64))
 			w.Header().Set(htmx.HeaderReswap, "outerHTML")
@@ -986,7 +1162,7 @@ func (bc *bookmarkController) handleTagEditView() func(w http.ResponseWriter, r
 		tag := bookmarkquerying.NewTag(name, 0)
 
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
-			formData := map[string]any{"Tag": tag, "InModal": true}
+			formData := map[string]any{"Tag": tag, "InModal": true, "Tree": r.URL.Query().Get("tree") == "true"}
 			if err := bc.tagEditView.RenderTemplate(w, "tagEditForm", formData); err != nil {
 				log.Error().Err(err).Msg("failed to render tag edit form fragment")
 				http.Error(w, "Something went wrong", http.StatusInternalServerError)
@@ -1008,14 +1184,18 @@ func (bc *bookmarkController) handleTagEditView() func(w http.ResponseWriter, r
 // On success:
 //   - htmx request: re-renders the tag's row and retargets/reswaps the
 //     response into it (outerHTML), and fires a "modal:close" client-side
-//     event so the tag list page's edit modal closes.
+//     event so the tag list page's edit modal closes. When the tag tree is
+//     displayed, or descendants are renamed as well, the page is refreshed
+//     instead, as several rows may be affected.
 //   - plain request: flash + redirect to the tag list, as before.
 //
 // On error, it falls back to the same flash+redirect (or HX-Redirect, for
 // htmx requests) behavior used throughout this file.
 func (bc *bookmarkController) handleTagEdit() func(w http.ResponseWriter, r *http.Request) {
 	type tagEditForm struct {
-		Name string `schema:"name"`
+		Name    string `schema:"name"`
+		Cascade bool   `schema:"cascade"`
+		Tree    bool   `schema:"tree"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -1044,6 +1224,7 @@ func (bc *bookmarkController) handleTagEdit() func(w http.ResponseWriter, r *htt
 			UserUUID:    ctxUser.UUID,
 			CurrentName: name,
 			NewName:     form.Name,
+			Cascade:     form.Cascade,
 		}
 
 		updated, err := bc.bookmarkService.UpdateTag(ctx, tagNameUpdate)
@@ -1053,6 +1234,13 @@ func (bc *bookmarkController) handleTagEdit() func(w http.ResponseWriter, r *htt
 			return
 		}
 
+		if r.Header.Get(htmx.HeaderRequest) == "true" && (form.Tree || form.Cascade) {
+			view.PutFlashSuccess(w, fmt.Sprintf("Tag updated for %d bookmarks", updated))
+			w.Header().Set(htmx.HeaderRefresh, "true")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
 			updatedTag := bookmarkquerying.NewTag(form.Name, uint(updated))
 
@@ -1076,6 +1264,9 @@ func (bc *bookmarkController) handleTagEdit() func(w http.ResponseWriter, r *htt
 
 // handleTagListView renders the tag list for the current authenticated user.
 //
+// Tags are displayed as a tree following their hierarchy, unless they are filtered
+// by name or browsed page by page, in which case they are displayed as a flat list.
+//
 // On an htmx request, it responds with only the list content fragment
 // (search form, tags, pagination), so that searching or paginating swaps the
 // list in place instead of reloading the full page. On a plain request, it
@@ -1095,7 +1286,21 @@ func (bc *bookmarkController) handleTagListView() func(w http.ResponseWriter, r
 		}
 
 		searchTermsParam := r.URL.Query().Get("search")
-		if searchTermsParam != "" {
+		if searchTermsParam == "" && !r.URL.Query().Has("page") {
+			tags, err := bc.queryingService.Tags(ctx, ctxUser.UUID, bookmarkquerying.VisibilityAll)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to retrieve tags")
+				view.RedirectOnError(w, r, "/bookmarks", "failed to retrieve tags")
+				return
+			}
+
+			viewData.Title = "Tags"
+			viewData.Content = tagListContent{
+				TagPage: bookmarkquerying.NewTagPage(1, 1, uint(len(tags)), tags),
+				Tree:    bookmarkquerying.NewTagTree(tags),
+			}
+
+		} else if searchTermsParam != "" {
 			tagSearchPage, err := bc.queryingService.TagsBySearchQueryAndPage(
 				ctx,
 				ctxUser.UUID,
@@ -1116,7 +1321,7 @@ func (bc *bookmarkController) handleTagListView() func(w http.ResponseWriter, r
 			}
 
 			viewData.Title = fmt.Sprintf("Tag search: %s", searchTermsParam)
-			viewData.Content = tagSearchPage
+			viewData.Content = tagListContent{TagPage: tagSearchPage}
 
 		} else {
 			tagPage, err := bc.queryingService.TagsByPage(
@@ -1138,7 +1343,7 @@ func (bc *bookmarkController) handleTagListView() func(w http.ResponseWriter, r
 			}
 
 			viewData.Title = "Tags"
-			viewData.Content = tagPage
+			viewData.Content = tagListContent{TagPage: tagPage}
 		}
 
 		if r.Header.Get(htmx.HeaderRequest) == "true" {
@@ -1152,3 +1357,21 @@ func (bc *bookmarkController) handleTagListView() func(w http.ResponseWriter, r
 		bc.tagListView.Render(w, r, viewData)
 	}
 }
+
+// notifyWebhooks queues webhook deliveries for a bookmark event.
+//
+// Failing to queue deliveries must not fail the bookmark operation that
+// triggered them, so errors are only logged.
+func (bc *bookmarkController) notifyWebhooks(ctx context.Context, event webhook.Event, b bookmark.Bookmark) {
+	if bc.webhookService == nil {
+		return
+	}
+
+	if err := bc.webhookService.NotifyBookmark(ctx, event, b); err != nil {
+		log.Error().
+			Err(err).
+			Str("event", string(event)).
+			Str("bookmark_uid", b.UID).
+			Msg("failed to queue webhook deliveries")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+)
+
+// archiveContentSecurityPolicy restricts archived pages to inline styles and
+// embedded resources, and runs them in a sandbox with an opaque origin, so
+// that a snapshot can neither run scripts nor act on behalf of the user.
+const archiveContentSecurityPolicy = "sandbox; default-src 'none'; img-src data:; style-src 'unsafe-inline'; font-src data:; form-action 'none'; frame-ancestors 'none'; base-uri 'none'"
+
+type bookmarkArchiveContent struct {
+	Bookmark bookmark.Bookmark
+	Archive  *bookmarkarchiving.Archive
+}
+
+// handleBookmarkArchive serves the archived snapshot of a bookmarked page.
+//
+// When no snapshot is available yet, it redirects to the archive status page.
+func (bc *bookmarkController) handleBookmarkArchive() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bookmarkUID := chi.URLParam(r, "uid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		_, content, err := bc.archivingService.Content(ctx, ctxUser.UUID, bookmarkUID)
+		if errors.Is(err, bookmarkarchiving.ErrArchiveNotFound) || errors.Is(err, bookmarkarchiving.ErrArchiveNotAvailable) {
+			http.Redirect(w, r, fmt.Sprintf("/bookmarks/%s/archive/status", bookmarkUID), http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("bookmark_uid", bookmarkUID).Msg("failed to retrieve page archive")
+			view.PutFlashError(w, "failed to retrieve page archive")
+			http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+			return
+		}
+
+		w.Header().Set("Content-Security-Policy", archiveContentSecurityPolicy)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Cache-Control", "private, no-cache")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if _, err := w.Write(content); err != nil {
+			log.Error().Err(err).Str("bookmark_uid", bookmarkUID).Msg("failed to write page archive")
+		}
+	}
+}
+
+// handleBookmarkArchiveStatusView renders the archiving status for a bookmarked page.
+func (bc *bookmarkController) handleBookmarkArchiveStatusView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bookmarkUID := chi.URLParam(r, "uid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		b, err := bc.bookmarkService.ByUID(ctx, ctxUser.UUID, bookmarkUID)
+		if err != nil {
+			log.Error().Err(err).Str("bookmark_uid", bookmarkUID).Msg("failed to retrieve bookmark")
+			view.PutFlashError(w, "failed to retrieve bookmark")
+			http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+			return
+		}
+
+		content := bookmarkArchiveContent{
+			Bookmark: b,
+		}
+
+		archive, err := bc.archivingService.ByBookmarkUID(ctx, ctxUser.UUID, bookmarkUID)
+		if err != nil && !errors.Is(err, bookmarkarchiving.ErrArchiveNotFound) {
+			log.Error().Err(err).Str("bookmark_uid", bookmarkUID).Msg("failed to retrieve page archive")
+			view.PutFlashError(w, "failed to retrieve page archive")
+			http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+			return
+		}
+		if err == nil {
+			content.Archive = &archive
+		}
+
+		viewData := view.Data{
+			Content: content,
+			Title:   fmt.Sprintf("Archive: %s", b.Title),
+		}
+
+		bc.bookmarkArchiveView.Render(w, r, viewData)
+	}
+}
+
+// handleBookmarkArchiveRequest queues a bookmarked page to be archived.
+func (bc *bookmarkController) handleBookmarkArchiveRequest() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bookmarkUID := chi.URLParam(r, "uid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if err := bc.archivingService.Request(ctx, ctxUser.UUID, bookmarkUID); err != nil {
+			log.Error().Err(err).Str("bookmark_uid", bookmarkUID).Msg("failed to request page archiving")
+			view.PutFlashError(w, "failed to request page archiving")
+			http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The page will be archived shortly")
+		http.Redirect(w, r, fmt.Sprintf("/bookmarks/%s/archive/status", bookmarkUID), http.StatusSeeOther)
+	}
+}
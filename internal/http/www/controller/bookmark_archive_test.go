@@ -0,0 +1,174 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const testBookmarkArchiveContent = `<!DOCTYPE html><html><head><meta charset="utf-8"></head><body><p>Snapshot</p></body></html>`
+
+// newTestBookmarkControllerForArchive wires a bookmarkController against
+// in-memory archives and blobs, for a single bookmark.
+func newTestBookmarkControllerForArchive(b bookmark.Bookmark, archives []bookmarkarchiving.Archive, blobs map[string][]byte) (bookmarkController, *bookmarkarchiving.FakeRepository) {
+	bookmarkService := bookmark.NewService(&bookmark.FakeRepository{Bookmarks: []bookmark.Bookmark{b}}, nil)
+	archiveRepo := &bookmarkarchiving.FakeRepository{Archives: archives}
+
+	bc := bookmarkController{
+		bookmarkService: bookmarkService,
+		archivingService: bookmarkarchiving.NewService(
+			archiveRepo,
+			&bookmarkarchiving.FakeBlobStore{Blobs: blobs},
+			bookmarkService,
+			nil,
+		),
+		bookmarkArchiveView: view.New("bookmark/bookmark_archive.gohtml"),
+	}
+
+	return bc, archiveRepo
+}
+
+// newBookmarkArchiveRequest builds a request against /bookmarks/{uid}/archive,
+// with the given user set in context.
+func newBookmarkArchiveRequest(t *testing.T, ctxUser user.User, method string, target string, bookmarkUID string) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), method, target, nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("uid", bookmarkUID)
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return r.WithContext(ctx)
+}
+
+func TestHandleBookmarkArchive(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+	b := testBookmarkDeadEntry
+	target := "/bookmarks/" + b.UID + "/archive"
+
+	t.Run("snapshot is served in a sandbox", func(t *testing.T) {
+		archives := []bookmarkarchiving.Archive{
+			{
+				BookmarkUID: b.UID,
+				UserUUID:    b.UserUUID,
+				URL:         b.URL,
+				Status:      bookmarkarchiving.ArchiveStatusArchived,
+				ContentHash: "cafe",
+			},
+		}
+		blobs := map[string][]byte{"cafe": []byte(testBookmarkArchiveContent)}
+
+		bc, _ := newTestBookmarkControllerForArchive(b, archives, blobs)
+		r := newBookmarkArchiveRequest(t, ctxUser, http.MethodGet, target, b.UID)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkArchive()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+		if got := w.Body.String(); got != testBookmarkArchiveContent {
+			t.Errorf("want snapshot content, got:\n%s", got)
+		}
+
+		csp := w.Header().Get("Content-Security-Policy")
+		if !strings.HasPrefix(csp, "sandbox;") {
+			t.Errorf("want a sandboxed Content-Security-Policy, got %q", csp)
+		}
+		if !strings.Contains(csp, "default-src 'none'") {
+			t.Errorf("want all resources blocked by default, got %q", csp)
+		}
+		if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("want X-Content-Type-Options nosniff, got %q", got)
+		}
+	})
+
+	t.Run("pending archive redirects to the status page", func(t *testing.T) {
+		archives := []bookmarkarchiving.Archive{
+			{
+				BookmarkUID: b.UID,
+				UserUUID:    b.UserUUID,
+				URL:         b.URL,
+				Status:      bookmarkarchiving.ArchiveStatusPending,
+			},
+		}
+
+		bc, _ := newTestBookmarkControllerForArchive(b, archives, nil)
+		r := newBookmarkArchiveRequest(t, ctxUser, http.MethodGet, target, b.UID)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkArchive()(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status 303, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+		if got, want := w.Header().Get("Location"), target+"/status"; got != want {
+			t.Errorf("want redirect to %q, got %q", want, got)
+		}
+	})
+}
+
+func TestHandleBookmarkArchiveStatusView(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+	b := testBookmarkDeadEntry
+
+	bc, _ := newTestBookmarkControllerForArchive(b, nil, nil)
+	r := newBookmarkArchiveRequest(t, ctxUser, http.MethodGet, "/bookmarks/"+b.UID+"/archive/status", b.UID)
+	w := httptest.NewRecorder()
+
+	bc.handleBookmarkArchiveStatusView()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "This page has not been archived") {
+		t.Errorf("want the missing archive status rendered, got:\n%s", body)
+	}
+	if !strings.Contains(body, `action="/bookmarks/`+b.UID+`/archive"`) {
+		t.Errorf("want the archive request form rendered, got:\n%s", body)
+	}
+}
+
+func TestHandleBookmarkArchiveRequest(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+	b := testBookmarkDeadEntry
+	target := "/bookmarks/" + b.UID + "/archive"
+
+	bc, archiveRepo := newTestBookmarkControllerForArchive(b, nil, nil)
+	r := newBookmarkArchiveRequest(t, ctxUser, http.MethodPost, target, b.UID)
+	w := httptest.NewRecorder()
+
+	bc.handleBookmarkArchiveRequest()(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status 303, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+	if got, want := w.Header().Get("Location"), target+"/status"; got != want {
+		t.Errorf("want redirect to %q, got %q", want, got)
+	}
+
+	if len(archiveRepo.Archives) != 1 {
+		t.Fatalf("want 1 archive, got %d", len(archiveRepo.Archives))
+	}
+	if got := archiveRepo.Archives[0].Status; got != bookmarkarchiving.ArchiveStatusPending {
+		t.Errorf("want status %q, got %q", bookmarkarchiving.ArchiveStatusPending, got)
+	}
+}
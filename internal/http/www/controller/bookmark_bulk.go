@@ -0,0 +1,228 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/virtualtam/netscape-go/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+// bookmarkBulkDeleteContent holds the selection displayed on the bulk deletion
+// confirmation page.
+type bookmarkBulkDeleteContent struct {
+	UIDs []string
+}
+
+// bulkEditRedirectPath returns the page to go back to after a bulk edit, so
+// that users keep their current search and page.
+//
+// The Referer is supplied by the client, and is only used when it points to a
+// local path; otherwise users are redirected to the bookmark list.
+func bulkEditRedirectPath(r *http.Request) string {
+	const defaultPath = "/bookmarks"
+
+	referer, err := url.Parse(r.Referer())
+	if err != nil {
+		return defaultPath
+	}
+
+	if referer.Scheme != "" && referer.Scheme != "http" && referer.Scheme != "https" {
+		return defaultPath
+	}
+
+	if referer.Host != "" && referer.Host != r.Host {
+		return defaultPath
+	}
+
+	if !strings.HasPrefix(referer.Path, "/") || strings.HasPrefix(referer.Path, "//") {
+		return defaultPath
+	}
+
+	redirectURL := url.URL{
+		Path:     referer.Path,
+		RawPath:  referer.RawPath,
+		RawQuery: referer.RawQuery,
+	}
+
+	return redirectURL.String()
+}
+
+// handleBookmarkBulkEdit processes the bulk edit form of the bookmark list page.
+//
+// Deleting bookmarks requires a confirmation: the first submission renders a
+// confirmation page holding the selection, which is submitted again with the
+// confirmed field set.
+func (bc *bookmarkController) handleBookmarkBulkEdit() func(w http.ResponseWriter, r *http.Request) {
+	type bulkEditForm struct {
+		UIDs      []string `schema:"uids"`
+		Action    string   `schema:"action"`
+		Tags      string   `schema:"tags"`
+		Confirmed bool     `schema:"confirmed"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form bulkEditForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse bookmark bulk edit form")
+			view.RedirectOnError(w, r, bulkEditRedirectPath(r), "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		action := bookmark.BulkEditAction(form.Action)
+
+		if action == bookmark.BulkEditActionDelete && !form.Confirmed {
+			if len(form.UIDs) == 0 {
+				view.RedirectOnError(w, r, bulkEditRedirectPath(r), userFacingError(bookmark.ErrSelectionRequired))
+				return
+			}
+
+			viewData := view.Data{
+				Content: bookmarkBulkDeleteContent{UIDs: form.UIDs},
+				Title:   "Delete selected bookmarks",
+			}
+
+			bc.bookmarkBulkDeleteView.Render(w, r, viewData)
+			return
+		}
+
+		redirectPath := bulkEditRedirectPath(r)
+		if action == bookmark.BulkEditActionDelete {
+			// the confirmation page cannot be reloaded
+			redirectPath = "/bookmarks"
+		}
+
+		bulkEditQuery := bookmark.BulkEditQuery{
+			UserUUID: ctxUser.UUID,
+			UIDs:     form.UIDs,
+			Action:   action,
+			Tags:     strings.Fields(form.Tags),
+		}
+
+		summary, err := bc.bookmarkService.BulkEdit(ctx, bulkEditQuery)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Str("action", form.Action).Msg("failed to edit bookmarks")
+			view.RedirectOnError(w, r, redirectPath, userFacingError(err))
+			return
+		}
+
+		if action == bookmark.BulkEditActionDelete {
+			for _, b := range summary.Bookmarks {
+				bc.notifyWebhooks(ctx, webhook.EventBookmarkDeleted, b)
+			}
+
+			view.PutFlashSuccess(w, fmt.Sprintf("%d bookmark(s) deleted", summary.Deleted))
+			http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+			return
+		}
+
+		for _, b := range summary.Bookmarks {
+			bc.notifyWebhooks(ctx, webhook.EventBookmarkUpdated, b)
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("%d bookmark(s) updated, %d unchanged", summary.Updated, summary.Unchanged()))
+		http.Redirect(w, r, redirectPath, http.StatusSeeOther)
+	}
+}
+
+// handleBookmarkBulkExport exports the bookmarks selected on the bookmark list
+// page, and sends the corresponding file to the user.
+func (bc *bookmarkController) handleBookmarkBulkExport() func(w http.ResponseWriter, r *http.Request) {
+	type bulkExportForm struct {
+		UIDs   []string                 `schema:"uids"`
+		Format bookmarkexporting.Format `schema:"format"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form bulkExportForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse bookmark bulk export form")
+			view.RedirectOnError(w, r, bulkEditRedirectPath(r), "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var marshaled []byte
+		var fileExtension string
+		var exported int
+
+		switch form.Format {
+		case bookmarkexporting.FormatJSON:
+			fileExtension = "json"
+
+			jsonDocument, err := bc.exportingService.ExportSelectionAsJSONDocument(ctx, ctxUser.UUID, form.UIDs)
+			if err != nil {
+				log.Error().Err(err).Msg("bookmark: failed to retrieve selected bookmarks")
+				view.RedirectOnError(w, r, bulkEditRedirectPath(r), userFacingError(err))
+				return
+			}
+
+			exported = len(jsonDocument.Bookmarks)
+
+			marshaled, err = json.MarshalIndent(jsonDocument, "", "  ")
+			if err != nil {
+				log.Error().Err(err).Msg("bookmark: failed to marshal JSON document")
+				view.RedirectOnError(w, r, bulkEditRedirectPath(r), "failed to export bookmarks")
+				return
+			}
+
+		case bookmarkexporting.FormatNetscape:
+			fileExtension = "htm"
+
+			netscapeDocument, err := bc.exportingService.ExportSelectionAsNetscapeDocument(ctx, ctxUser.UUID, form.UIDs)
+			if err != nil {
+				log.Error().Err(err).Msg("bookmark: failed to retrieve selected bookmarks")
+				view.RedirectOnError(w, r, bulkEditRedirectPath(r), userFacingError(err))
+				return
+			}
+
+			exported = len(netscapeDocument.Root.Bookmarks)
+
+			marshaled, err = netscape.Marshal(netscapeDocument)
+			if err != nil {
+				log.Error().Err(err).Msg("bookmark: failed to marshal Netscape document")
+				view.RedirectOnError(w, r, bulkEditRedirectPath(r), "failed to export bookmarks")
+				return
+			}
+
+		default:
+			log.Error().Str("format", string(form.Format)).Msg("bookmark: invalid export format")
+			view.RedirectOnError(w, r, bulkEditRedirectPath(r), "failed to export bookmarks")
+			return
+		}
+
+		recordAuditEvent(r, bc.auditService, audit.Event{
+			Type:       audit.EventBookmarksExported,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    fmt.Sprintf("format: %s, selection: %d bookmarks", form.Format, exported),
+		})
+
+		filename := fmt.Sprintf("bookmarks-selection.%s", fileExtension)
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		w.Header().Set("Content-Type", "application/octet-stream")
+
+		if _, err := w.Write(marshaled); err != nil {
+			log.Error().Err(err).Str("format", string(form.Format)).Msg("bookmark: failed to send marshaled export")
+		}
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testBookmarkBulkEntries = []bookmark.Bookmark{
+		{
+			UID:       "27L4DoEZaRASKhQKygRCrvVAwkr",
+			UserUUID:  testBookmarkCtxUser.UUID,
+			URL:       "https://go.dev",
+			Title:     "The Go Programming Language",
+			Tags:      []string{"go"},
+			CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:       "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+			UserUUID:  testBookmarkCtxUser.UUID,
+			URL:       "https://www.rust-lang.org",
+			Title:     "Rust Programming Language",
+			Tags:      []string{"rust"},
+			Private:   true,
+			CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+)
+
+// newTestBookmarkControllerForBulkEdit wires a bookmarkController against fake
+// repositories seeded with testBookmarkBulkEntries, owned by testBookmarkCtxUser.
+func newTestBookmarkControllerForBulkEdit() (bookmarkController, *bookmark.FakeRepository) {
+	// tags are updated in place, clone them so that test cases do not share state
+	repoBookmarks := make([]bookmark.Bookmark, len(testBookmarkBulkEntries))
+	for i, b := range testBookmarkBulkEntries {
+		b.Tags = slices.Clone(b.Tags)
+		repoBookmarks[i] = b
+	}
+
+	repo := &bookmark.FakeRepository{
+		Bookmarks: repoBookmarks,
+	}
+
+	exportingRepo := &bookmarkexporting.FakeRepository{
+		Bookmarks: testBookmarkBulkEntries,
+	}
+
+	bc := bookmarkController{
+		bookmarkService:        bookmark.NewService(repo, nil),
+		exportingService:       bookmarkexporting.NewService(exportingRepo),
+		bookmarkBulkDeleteView: view.New("bookmark/bookmark_bulk_delete.gohtml"),
+	}
+
+	return bc, repo
+}
+
+// newBookmarkBulkPostRequest builds a POST request against a /bookmarks/bulk route,
+// with the given user set in context, as submitted from the bookmark list page.
+func newBookmarkBulkPostRequest(t *testing.T, ctxUser user.User, target string, form url.Values) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Referer", "/bookmarks?page=2")
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	return r.WithContext(ctx)
+}
+
+func TestBulkEditRedirectPath(t *testing.T) {
+	cases := []struct {
+		tname   string
+		referer string
+		want    string
+	}{
+		{
+			tname: "no referer",
+			want:  "/bookmarks",
+		},
+		{
+			tname:   "relative path",
+			referer: "/bookmarks?page=2",
+			want:    "/bookmarks?page=2",
+		},
+		{
+			tname:   "same origin",
+			referer: "http://example.com/bookmarks/search?terms=go&page=3",
+			want:    "/bookmarks/search?terms=go&page=3",
+		},
+		{
+			tname:   "other origin",
+			referer: "https://attacker.example/bookmarks",
+			want:    "/bookmarks",
+		},
+		{
+			tname:   "protocol-relative URL",
+			referer: "//attacker.example/bookmarks",
+			want:    "/bookmarks",
+		},
+		{
+			tname:   "path starting with two slashes",
+			referer: "http://example.com//attacker.example/bookmarks",
+			want:    "/bookmarks",
+		},
+		{
+			tname:   "unsupported scheme",
+			referer: "javascript:alert(1)",
+			want:    "/bookmarks",
+		},
+		{
+			tname:   "relative path without leading slash",
+			referer: "bookmarks?page=2",
+			want:    "/bookmarks",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/bookmarks/bulk", nil)
+			r.Header.Set("Referer", tc.referer)
+
+			got := bulkEditRedirectPath(r)
+
+			if got != tc.want {
+				t.Errorf("want redirect path %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleBookmarkBulkEdit(t *testing.T) {
+	cases := []struct {
+		tname            string
+		form             url.Values
+		wantLocation     string
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantBookmarks    []bookmark.Bookmark
+	}{
+		{
+			tname: "no bookmarks selected",
+			form: url.Values{
+				"action": {"set_public"},
+			},
+			wantLocation:     "/bookmarks?page=2",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: "Select at least one bookmark.",
+			wantBookmarks:    testBookmarkBulkEntries,
+		},
+		{
+			tname: "unknown action",
+			form: url.Values{
+				"uids":   {"27L4DoEZaRASKhQKygRCrvVAwkr"},
+				"action": {"archive"},
+			},
+			wantLocation:     "/bookmarks?page=2",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: "This action is not supported.",
+			wantBookmarks:    testBookmarkBulkEntries,
+		},
+		{
+			tname: "add tags",
+			form: url.Values{
+				"uids":   {"27L4DoEZaRASKhQKygRCrvVAwkr", "2Ecp9vyGeHJ9rLMNxMn6cAYrTun"},
+				"action": {"add_tags"},
+				"tags":   {"go dev"},
+			},
+			wantLocation:     "/bookmarks?page=2",
+			wantFlashLevel:   "success",
+			wantFlashMessage: "2 bookmark(s) updated, 0 unchanged",
+			wantBookmarks: []bookmark.Bookmark{
+				{
+					URL:   "https://go.dev",
+					Title: "The Go Programming Language",
+					Tags:  []string{"dev", "go"},
+				},
+				{
+					URL:     "https://www.rust-lang.org",
+					Title:   "Rust Programming Language",
+					Tags:    []string{"dev", "go", "rust"},
+					Private: true,
+				},
+			},
+		},
+		{
+			tname: "set private",
+			form: url.Values{
+				"uids":   {"27L4DoEZaRASKhQKygRCrvVAwkr", "2Ecp9vyGeHJ9rLMNxMn6cAYrTun"},
+				"action": {"set_private"},
+			},
+			wantLocation:     "/bookmarks?page=2",
+			wantFlashLevel:   "success",
+			wantFlashMessage: "1 bookmark(s) updated, 1 unchanged",
+			wantBookmarks: []bookmark.Bookmark{
+				{
+					URL:     "https://go.dev",
+					Title:   "The Go Programming Language",
+					Tags:    []string{"go"},
+					Private: true,
+				},
+				{
+					URL:     "https://www.rust-lang.org",
+					Title:   "Rust Programming Language",
+					Tags:    []string{"rust"},
+					Private: true,
+				},
+			},
+		},
+		{
+			tname: "delete once confirmed",
+			form: url.Values{
+				"uids":      {"2Ecp9vyGeHJ9rLMNxMn6cAYrTun"},
+				"action":    {"delete"},
+				"confirmed": {"true"},
+			},
+			wantLocation:     "/bookmarks",
+			wantFlashLevel:   "success",
+			wantFlashMessage: "1 bookmark(s) deleted",
+			wantBookmarks: []bookmark.Bookmark{
+				{
+					URL:   "https://go.dev",
+					Title: "The Go Programming Language",
+					Tags:  []string{"go"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			bc, repo := newTestBookmarkControllerForBulkEdit()
+
+			r := newBookmarkBulkPostRequest(t, testBookmarkCtxUser, "/bookmarks/bulk", tc.form)
+			w := httptest.NewRecorder()
+
+			bc.handleBookmarkBulkEdit()(w, r)
+
+			if w.Code != http.StatusSeeOther {
+				t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("want location %q, got %q", tc.wantLocation, got)
+			}
+
+			if got := decodedFlashLevel(t, w); got != tc.wantFlashLevel {
+				t.Errorf("want flash level %q, got %q", tc.wantFlashLevel, got)
+			}
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, tc.wantFlashMessage) {
+				t.Errorf("want flash message containing %q, got %q", tc.wantFlashMessage, got)
+			}
+
+			if len(repo.Bookmarks) != len(tc.wantBookmarks) {
+				t.Fatalf("want %d bookmarks, got %d", len(tc.wantBookmarks), len(repo.Bookmarks))
+			}
+
+			for i, b := range repo.Bookmarks {
+				bookmark.AssertBookmarkEquals(t, b, tc.wantBookmarks[i])
+			}
+		})
+	}
+}
+
+func TestHandleBookmarkBulkEditDeleteConfirmation(t *testing.T) {
+	bc, repo := newTestBookmarkControllerForBulkEdit()
+
+	form := url.Values{
+		"uids":   {"27L4DoEZaRASKhQKygRCrvVAwkr", "2Ecp9vyGeHJ9rLMNxMn6cAYrTun"},
+		"action": {"delete"},
+	}
+
+	r := newBookmarkBulkPostRequest(t, testBookmarkCtxUser, "/bookmarks/bulk", form)
+	w := httptest.NewRecorder()
+
+	bc.handleBookmarkBulkEdit()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+
+	if !strings.Contains(body, `<input type="hidden" name="confirmed" value="true">`) {
+		t.Errorf("want a confirmation form, got:\n%s", body)
+	}
+	for _, uid := range form["uids"] {
+		if !strings.Contains(body, `<input type="hidden" name="uids" value="`+uid+`">`) {
+			t.Errorf("want the selected bookmark %q in the confirmation form, got:\n%s", uid, body)
+		}
+	}
+
+	if len(repo.Bookmarks) != len(testBookmarkBulkEntries) {
+		t.Errorf("want no bookmarks deleted before confirmation, got %d bookmarks", len(repo.Bookmarks))
+	}
+}
+
+func TestHandleBookmarkBulkExport(t *testing.T) {
+	t.Run("export selection as JSON", func(t *testing.T) {
+		bc, _ := newTestBookmarkControllerForBulkEdit()
+
+		form := url.Values{
+			"uids":   {"2Ecp9vyGeHJ9rLMNxMn6cAYrTun"},
+			"format": {"json"},
+		}
+
+		r := newBookmarkBulkPostRequest(t, testBookmarkCtxUser, "/bookmarks/bulk/export", form)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkBulkExport()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		wantDisposition := "attachment; filename=bookmarks-selection.json"
+		if got := w.Header().Get("Content-Disposition"); got != wantDisposition {
+			t.Errorf("want Content-Disposition %q, got %q", wantDisposition, got)
+		}
+
+		var document bookmarkexporting.JsonDocument
+		if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+			t.Fatalf("failed to unmarshal exported document: %q", err)
+		}
+
+		if len(document.Bookmarks) != 1 {
+			t.Fatalf("want 1 exported bookmark, got %d", len(document.Bookmarks))
+		}
+		if document.Bookmarks[0].URL != "https://www.rust-lang.org" {
+			t.Errorf("want exported bookmark URL %q, got %q", "https://www.rust-lang.org", document.Bookmarks[0].URL)
+		}
+	})
+
+	t.Run("no bookmarks selected", func(t *testing.T) {
+		bc, _ := newTestBookmarkControllerForBulkEdit()
+
+		form := url.Values{
+			"format": {"netscape"},
+		}
+
+		r := newBookmarkBulkPostRequest(t, testBookmarkCtxUser, "/bookmarks/bulk/export", form)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkBulkExport()(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "Select at least one bookmark.") {
+			t.Errorf("want flash message containing %q, got %q", "Select at least one bookmark.", got)
+		}
+	})
+}
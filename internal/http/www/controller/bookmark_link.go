@@ -0,0 +1,157 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/htmx"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+// linkStatusTitles holds the page titles for the link statuses bookmarks can be filtered by.
+var linkStatusTitles = map[bookmark.LinkStatus]string{
+	bookmark.LinkStatusUnchecked:   "Bookmarks: unchecked links",
+	bookmark.LinkStatusRedirected:  "Bookmarks: redirected links",
+	bookmark.LinkStatusUnreachable: "Bookmarks: unreachable links",
+	bookmark.LinkStatusForbidden:   "Bookmarks: forbidden links",
+	bookmark.LinkStatusDead:        "Bookmarks: dead links",
+}
+
+// handleBookmarkLinkListView renders the list of bookmarks with a given link status.
+func (bc *bookmarkController) handleBookmarkLinkListView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		status, err := bookmark.ParseLinkStatus(chi.URLParam(r, "status"))
+		if err != nil {
+			view.RedirectOnError(w, r, "/bookmarks", userFacingError(err))
+			return
+		}
+
+		title, ok := linkStatusTitles[status]
+		if !ok {
+			view.RedirectOnError(w, r, "/bookmarks", userFacingError(bookmark.ErrLinkStatusInvalid))
+			return
+		}
+
+		pageNumber, pageNumberStr, err := paginate.GetPageNumber(r.URL.Query())
+		if err != nil {
+			log.Warn().Err(err).Str("page_number", pageNumberStr).Msg("invalid page number")
+			view.RedirectOnError(w, r, r.URL.Path, fmt.Sprintf("invalid page number: %q", pageNumberStr))
+			return
+		}
+
+		bookmarksPage, err := bc.queryingService.BookmarksByLinkStatusAndPage(ctx, ctxUser.UUID, status, pageNumber)
+		if errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			msg := fmt.Sprintf("invalid page number: %d", pageNumber)
+			log.Error().Err(err).Msg(msg)
+			view.RedirectOnError(w, r, r.URL.Path, msg)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve bookmarks")
+			view.RedirectOnError(w, r, "/bookmarks", "failed to retrieve bookmarks")
+			return
+		}
+
+		viewData := view.Data{
+			Title:   title,
+			Content: bookmarksPage,
+		}
+
+		if r.Header.Get(htmx.HeaderRequest) == "true" {
+			if err := bc.bookmarkListView.RenderTemplate(w, "content", viewData.Content); err != nil {
+				log.Error().Err(err).Msg("failed to render bookmark list fragment")
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		bc.bookmarkListView.Render(w, r, viewData)
+	}
+}
+
+// handleBookmarkLinkUpdateView renders the confirmation form to update redirected bookmarks.
+func (bc *bookmarkController) handleBookmarkLinkUpdateView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewData := view.Data{
+			Title: "Update redirected bookmarks",
+		}
+
+		bc.bookmarkLinkUpdateView.Render(w, r, viewData)
+	}
+}
+
+// handleBookmarkLinkUpdate updates the URL of all redirected bookmarks to their redirect URL.
+func (bc *bookmarkController) handleBookmarkLinkUpdate() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		result, err := bc.checkingService.UpdateRedirected(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to update redirected bookmarks")
+			view.PutFlashError(w, "failed to update redirected bookmarks")
+			http.Redirect(w, r, "/bookmarks/links/redirected", http.StatusSeeOther)
+			return
+		}
+
+		for _, updated := range result.Updated {
+			bc.notifyWebhooks(ctx, webhook.EventBookmarkUpdated, updated)
+		}
+
+		msg := fmt.Sprintf("%d bookmark(s) updated", len(result.Updated))
+		if result.Conflicts > 0 {
+			msg += fmt.Sprintf("; %d bookmark(s) left unchanged, as their redirect URL is already bookmarked", result.Conflicts)
+		}
+
+		view.PutFlashSuccess(w, msg)
+		http.Redirect(w, r, "/bookmarks/links/redirected", http.StatusSeeOther)
+	}
+}
+
+// handleBookmarkLinkDeleteView renders the confirmation form to delete dead bookmarks.
+func (bc *bookmarkController) handleBookmarkLinkDeleteView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewData := view.Data{
+			Title: "Delete dead bookmarks",
+		}
+
+		bc.bookmarkLinkDeleteView.Render(w, r, viewData)
+	}
+}
+
+// handleBookmarkLinkDelete permanently deletes all bookmarks with a dead link.
+func (bc *bookmarkController) handleBookmarkLinkDelete() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		deleted, err := bc.checkingService.DeleteDead(ctx, ctxUser.UUID)
+
+		for _, b := range deleted {
+			bc.notifyWebhooks(ctx, webhook.EventBookmarkDeleted, b)
+		}
+
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to delete dead bookmarks")
+			view.PutFlashError(w, "failed to delete dead bookmarks")
+			http.Redirect(w, r, "/bookmarks/links/dead", http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("%d bookmark(s) deleted", len(deleted)))
+		http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+	}
+}
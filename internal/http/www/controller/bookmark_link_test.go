@@ -0,0 +1,148 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/segmentio/ksuid"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testBookmarkDeadEntry = bookmark.Bookmark{
+		UID:      ksuid.New().String(),
+		UserUUID: testBookmarkCtxUser.UUID,
+		URL:      "https://example.com/gone",
+		Title:    "Gone for good",
+		Link: bookmark.LinkCheck{
+			Status:     bookmark.LinkStatusDead,
+			StatusCode: http.StatusGone,
+			CheckedAt:  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+// newBookmarkLinkListRequest builds a GET request against
+// /bookmarks/links/{status}, with the given user set in context.
+func newBookmarkLinkListRequest(t *testing.T, ctxUser user.User, status string, hxRequest bool) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/bookmarks/links/"+status, nil)
+	if hxRequest {
+		r.Header.Set("HX-Request", "true")
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("status", status)
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return r.WithContext(ctx)
+}
+
+func TestHandleBookmarkLinkListView(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+
+	t.Run("dead links are listed with a badge", func(t *testing.T) {
+		bc := newTestBookmarkController([]bookmark.Bookmark{testBookmarkEntry, testBookmarkDeadEntry})
+		r := newBookmarkLinkListRequest(t, ctxUser, "dead", false)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkLinkListView()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, testBookmarkDeadEntry.Title) {
+			t.Errorf("want the dead bookmark rendered, got:\n%s", body)
+		}
+		if strings.Contains(body, testBookmarkEntry.Title) {
+			t.Errorf("want the unchecked bookmark excluded, got:\n%s", body)
+		}
+		if !strings.Contains(body, "Dead link") {
+			t.Errorf("want the dead link badge rendered, got:\n%s", body)
+		}
+		if !strings.Contains(body, `href="/bookmarks/links/dead/delete"`) {
+			t.Errorf("want the bulk delete action rendered, got:\n%s", body)
+		}
+	})
+
+	t.Run("htmx request renders only the fragment", func(t *testing.T) {
+		bc := newTestBookmarkController([]bookmark.Bookmark{testBookmarkDeadEntry})
+		r := newBookmarkLinkListRequest(t, ctxUser, "dead", true)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkLinkListView()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "<!DOCTYPE html>") {
+			t.Errorf("want a fragment with no layout, got:\n%s", w.Body.String())
+		}
+	})
+
+	for _, status := range []string{"unknown", "alive", "skipped"} {
+		t.Run("unsupported status "+status+", htmx request uses HX-Redirect", func(t *testing.T) {
+			bc := newTestBookmarkController([]bookmark.Bookmark{testBookmarkDeadEntry})
+			r := newBookmarkLinkListRequest(t, ctxUser, status, true)
+			w := httptest.NewRecorder()
+
+			bc.handleBookmarkLinkListView()(w, r)
+
+			assertHXRedirectOnError(t, w, "/bookmarks")
+		})
+	}
+}
+
+func TestHandleBookmarkLinkDelete(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+
+	repo := &bookmarkchecking.FakeRepository{
+		FakeRepository: &bookmark.FakeRepository{
+			Bookmarks: []bookmark.Bookmark{testBookmarkEntry, testBookmarkDeadEntry},
+		},
+	}
+	bookmarkService := bookmark.NewService(repo, nil)
+
+	bc := bookmarkController{
+		bookmarkService: bookmarkService,
+		checkingService: bookmarkchecking.NewService(repo, bookmarkService, nil),
+	}
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/bookmarks/links/dead/delete", nil)
+	r = r.WithContext(httpcontext.WithUser(r.Context(), ctxUser))
+	w := httptest.NewRecorder()
+
+	bc.handleBookmarkLinkDelete()(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status 303, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Location"); got != "/bookmarks" {
+		t.Errorf("want redirect to /bookmarks, got %q", got)
+	}
+
+	if len(repo.Bookmarks) != 1 {
+		t.Fatalf("want 1 remaining bookmark, got %d", len(repo.Bookmarks))
+	}
+	if repo.Bookmarks[0].UID != testBookmarkEntry.UID {
+		t.Errorf("want bookmark %q to remain, got %q", testBookmarkEntry.UID, repo.Bookmarks[0].UID)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+// bookmarkFormSuggestion is set when the bookmark addition form has been pre-filled
+// with metadata retrieved from the bookmarked page.
+type bookmarkFormSuggestion struct {
+	SiteName string
+	Error    string
+}
+
+// handleBookmarkAddSuggest retrieves metadata for the URL entered in the bookmark addition form,
+// and renders the form fields pre-filled with a suggested title, description and tags.
+//
+// Fields that have already been filled by the user are left unchanged.
+func (bc *bookmarkController) handleBookmarkAddSuggest() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		query := r.URL.Query()
+
+		b := bookmark.Bookmark{
+			URL:         query.Get("url"),
+			Title:       query.Get("title"),
+			Description: query.Get("description"),
+			Tags:        strings.Fields(query.Get("tags")),
+		}
+
+		content := bookmarkFormContent{
+			Bookmark:   &b,
+			Suggestion: &bookmarkFormSuggestion{},
+		}
+
+		tags, err := bc.queryingService.TagNamesByCount(ctx, ctxUser.UUID, bookmarkquerying.VisibilityAll)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tags")
+		}
+		content.Tags = tags
+
+		suggestion, err := bc.suggestingService.Suggest(ctx, ctxUser.UUID, b.URL)
+		if err != nil {
+			log.Warn().Err(err).Str("user_uuid", ctxUser.UUID).Str("url", b.URL).Msg("failed to suggest bookmark metadata")
+			content.Suggestion.Error = "Could not retrieve information for this page."
+		} else {
+			b.URL = suggestion.URL
+			content.Suggestion.SiteName = suggestion.SiteName
+
+			if b.Title == "" {
+				b.Title = suggestion.Title
+			}
+			if b.Description == "" {
+				b.Description = suggestion.Description
+			}
+			if len(b.Tags) == 0 {
+				b.Tags = suggestion.Tags
+			}
+		}
+
+		if err := bc.bookmarkAddView.RenderTemplate(w, "bookmarkAddFields", content); err != nil {
+			log.Error().Err(err).Msg("failed to render bookmark addition fields")
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+	}
+}
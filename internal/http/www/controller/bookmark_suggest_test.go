@@ -0,0 +1,147 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	bookmarksuggesting "github.com/virtualtam/sparklemuffin/pkg/bookmark/suggesting"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const testBookmarkSuggestPage = `<!DOCTYPE html>
+<html>
+<head>
+<title>Suggested title</title>
+<meta property="og:description" content="Suggested description">
+<meta property="og:site_name" content="Suggested Site">
+</head>
+<body><p>Bookmarks and bookmarking: a bookmark saves a page.</p></body>
+</html>`
+
+// newTestBookmarkControllerForBookmarkSuggest wires a bookmarkController against a suggesting
+// service retrieving pages from a local test server.
+func newTestBookmarkControllerForBookmarkSuggest(t *testing.T) (bookmarkController, *httptest.Server) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(testBookmarkSuggestPage))
+	}))
+	t.Cleanup(server.Close)
+
+	queryingService := bookmarkquerying.NewService(&bookmarkquerying.FakeRepository{
+		Bookmarks: []bookmark.Bookmark{testBookmarkEntry},
+		Users:     []user.User{testBookmarkCtxUser},
+	})
+
+	bc := bookmarkController{
+		queryingService:   queryingService,
+		suggestingService: bookmarksuggesting.NewService(queryingService, bookmarksuggesting.NewClient(server.Client(), "test")),
+		bookmarkAddView:   view.New("bookmark/bookmark_add.gohtml"),
+	}
+
+	return bc, server
+}
+
+// newBookmarkAddSuggestRequest builds a GET request against /bookmarks/add/suggest.
+func newBookmarkAddSuggestRequest(t *testing.T, ctxUser user.User, query url.Values) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/bookmarks/add/suggest?"+query.Encode(), nil)
+	r.Header.Set("HX-Request", "true")
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	return r.WithContext(ctx)
+}
+
+func TestHandleBookmarkAddSuggest(t *testing.T) {
+	ctxUser := testBookmarkCtxUser
+
+	t.Run("empty fields are pre-filled", func(t *testing.T) {
+		bc, server := newTestBookmarkControllerForBookmarkSuggest(t)
+		r := newBookmarkAddSuggestRequest(t, ctxUser, url.Values{"url": {server.URL + "/page"}})
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkAddSuggest()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if strings.Contains(body, "<!DOCTYPE html>") {
+			t.Errorf("want a fragment with no layout, got:\n%s", body)
+		}
+		for _, want := range []string{
+			`id="bookmark-add-fields"`,
+			`value="Suggested title"`,
+			">Suggested description</textarea>",
+			"Suggested Site",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("want %q in the fragment, got:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("fields filled by the user are left unchanged", func(t *testing.T) {
+		bc, server := newTestBookmarkControllerForBookmarkSuggest(t)
+		r := newBookmarkAddSuggestRequest(t, ctxUser, url.Values{
+			"url":   {server.URL + "/page"},
+			"title": {"My own title"},
+			"tags":  {"mine"},
+		})
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkAddSuggest()(w, r)
+
+		body := w.Body.String()
+		if !strings.Contains(body, `value="My own title"`) {
+			t.Errorf("want the user title kept, got:\n%s", body)
+		}
+		if !strings.Contains(body, `value="mine"`) {
+			t.Errorf("want the user tags kept, got:\n%s", body)
+		}
+		if !strings.Contains(body, ">Suggested description</textarea>") {
+			t.Errorf("want the empty description pre-filled, got:\n%s", body)
+		}
+	})
+
+	t.Run("unavailable page renders the fields with a warning", func(t *testing.T) {
+		bc, server := newTestBookmarkControllerForBookmarkSuggest(t)
+		r := newBookmarkAddSuggestRequest(t, ctxUser, url.Values{
+			"url":   {server.URL + "/missing"},
+			"title": {"My own title"},
+		})
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkAddSuggest()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, "Could not retrieve information for this page.") {
+			t.Errorf("want a warning, got:\n%s", body)
+		}
+		if !strings.Contains(body, `value="My own title"`) {
+			t.Errorf("want the user title kept, got:\n%s", body)
+		}
+	})
+}
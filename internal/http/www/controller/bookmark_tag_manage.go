@@ -0,0 +1,215 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+const tagManagePath = "/bookmarks/tags/manage"
+
+// tagManageContent holds the data displayed on the tag management page.
+type tagManageContent struct {
+	Suggestions []bookmarkquerying.TagMergeSuggestion
+	Aliases     []bookmark.TagAlias
+	Preferences bookmark.TagPreferences
+
+	// Tags holds existing tag names, for autocompletion.
+	Tags []string
+}
+
+// handleTagManageView renders the tag management page, where users can merge tags,
+// define aliases and choose how the case of tags is normalized.
+func (bc *bookmarkController) handleTagManageView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		suggestions, err := bc.queryingService.TagMergeSuggestions(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tag merge suggestions")
+			view.RedirectOnError(w, r, "/bookmarks/tags", "failed to retrieve tags")
+			return
+		}
+
+		tags, err := bc.queryingService.TagNamesByCount(ctx, ctxUser.UUID, bookmarkquerying.VisibilityAll)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tags")
+			view.RedirectOnError(w, r, "/bookmarks/tags", "failed to retrieve tags")
+			return
+		}
+
+		aliases, err := bc.bookmarkService.TagAliases(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tag aliases")
+			view.RedirectOnError(w, r, "/bookmarks/tags", "failed to retrieve tag aliases")
+			return
+		}
+
+		preferences, err := bc.bookmarkService.TagPreferencesByUserUUID(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to retrieve tag preferences")
+			view.RedirectOnError(w, r, "/bookmarks/tags", "failed to retrieve tag preferences")
+			return
+		}
+
+		viewData := view.Data{
+			Content: tagManageContent{
+				Suggestions: suggestions,
+				Aliases:     aliases,
+				Preferences: preferences,
+				Tags:        tags,
+			},
+			Title: "Manage tags",
+		}
+
+		bc.tagManageView.Render(w, r, viewData)
+	}
+}
+
+// handleTagMerge processes the tag merge form.
+//
+// Bookmarks tagged with any of the source tags are tagged with the target tag instead;
+// the source tags may be kept as aliases of the target tag.
+func (bc *bookmarkController) handleTagMerge() func(w http.ResponseWriter, r *http.Request) {
+	type tagMergeForm struct {
+		Sources     string `schema:"sources"`
+		Target      string `schema:"target"`
+		KeepAliases bool   `schema:"keep_aliases"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form tagMergeForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse tag merge form")
+			view.RedirectOnError(w, r, tagManagePath, "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		mergeQuery := bookmark.TagMergeQuery{
+			UserUUID:    ctxUser.UUID,
+			Sources:     strings.Fields(form.Sources),
+			Target:      form.Target,
+			KeepAliases: form.KeepAliases,
+		}
+
+		updated, err := bc.bookmarkService.MergeTags(ctx, mergeQuery)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to merge tags")
+			view.RedirectOnError(w, r, tagManagePath, userFacingError(err))
+			return
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("Tags merged into %q for %d bookmarks", bookmark.NormalizeTag(mergeQuery.Target), updated))
+		http.Redirect(w, r, tagManagePath, http.StatusSeeOther)
+	}
+}
+
+// handleTagAliasAdd processes the tag alias addition form.
+func (bc *bookmarkController) handleTagAliasAdd() func(w http.ResponseWriter, r *http.Request) {
+	type tagAliasForm struct {
+		Alias string `schema:"alias"`
+		Tag   string `schema:"tag"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form tagAliasForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse tag alias form")
+			view.RedirectOnError(w, r, tagManagePath, "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		alias := bookmark.TagAlias{
+			UserUUID: ctxUser.UUID,
+			Alias:    form.Alias,
+			Tag:      form.Tag,
+		}
+
+		if err := bc.bookmarkService.AddTagAlias(ctx, alias); err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to add tag alias")
+			view.RedirectOnError(w, r, tagManagePath, userFacingError(err))
+			return
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("Tag alias %q added", bookmark.NormalizeTag(form.Alias)))
+		http.Redirect(w, r, tagManagePath, http.StatusSeeOther)
+	}
+}
+
+// handleTagAliasDelete processes the tag alias deletion form.
+func (bc *bookmarkController) handleTagAliasDelete() func(w http.ResponseWriter, r *http.Request) {
+	type tagAliasDeleteForm struct {
+		Alias string `schema:"alias"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form tagAliasDeleteForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse tag alias deletion form")
+			view.RedirectOnError(w, r, tagManagePath, "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if err := bc.bookmarkService.DeleteTagAlias(ctx, ctxUser.UUID, form.Alias); err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to delete tag alias")
+			view.RedirectOnError(w, r, tagManagePath, userFacingError(err))
+			return
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("Tag alias %q deleted", form.Alias))
+		http.Redirect(w, r, tagManagePath, http.StatusSeeOther)
+	}
+}
+
+// handleTagPreferencesUpdate processes the tag preferences form.
+func (bc *bookmarkController) handleTagPreferencesUpdate() func(w http.ResponseWriter, r *http.Request) {
+	type tagPreferencesForm struct {
+		CaseFolding string `schema:"case_folding"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var form tagPreferencesForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse tag preferences form")
+			view.RedirectOnError(w, r, tagManagePath, "failed to process form")
+			return
+		}
+
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		preferences := bookmark.TagPreferences{
+			UserUUID:    ctxUser.UUID,
+			CaseFolding: bookmark.TagCaseFolding(form.CaseFolding),
+		}
+
+		if err := bc.bookmarkService.UpdateTagPreferences(ctx, preferences); err != nil {
+			log.Error().Err(err).Str("user_uuid", ctxUser.UUID).Msg("failed to update tag preferences")
+			view.RedirectOnError(w, r, tagManagePath, userFacingError(err))
+			return
+		}
+
+		view.PutFlashSuccess(w, "Tag preferences updated")
+		http.Redirect(w, r, tagManagePath, http.StatusSeeOther)
+	}
+}
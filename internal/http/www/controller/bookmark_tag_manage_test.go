@@ -0,0 +1,343 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testBookmarkGolangEntry = bookmark.Bookmark{
+		UID:       "bookmark-golang",
+		UserUUID:  testBookmarkCtxUser.UUID,
+		URL:       "https://go.dev",
+		Title:     "The Go Programming Language",
+		Tags:      []string{"golang"},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	testBookmarkGoEntry = bookmark.Bookmark{
+		UID:       "bookmark-go",
+		UserUUID:  testBookmarkCtxUser.UUID,
+		URL:       "https://pkg.go.dev",
+		Title:     "Go Packages",
+		Tags:      []string{"go", "Go"},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+// newTestBookmarkControllerForTagManage wires a bookmarkController against fake
+// repositories seeded with the given bookmarks and tag aliases, owned by
+// testBookmarkCtxUser.
+func newTestBookmarkControllerForTagManage(bookmarks []bookmark.Bookmark, aliases []bookmark.TagAlias) (bookmarkController, *bookmark.FakeRepository) {
+	// tags are updated in place, clone them so that test cases do not share state
+	repoBookmarks := make([]bookmark.Bookmark, len(bookmarks))
+	for i, b := range bookmarks {
+		b.Tags = slices.Clone(b.Tags)
+		repoBookmarks[i] = b
+	}
+
+	repo := &bookmark.FakeRepository{
+		Bookmarks:  repoBookmarks,
+		TagAliases: aliases,
+	}
+
+	queryingRepo := &bookmarkquerying.FakeRepository{
+		Bookmarks: bookmarks,
+		Users:     []user.User{testBookmarkCtxUser},
+	}
+
+	bc := bookmarkController{
+		bookmarkService: bookmark.NewService(repo, nil),
+		queryingService: bookmarkquerying.NewService(queryingRepo),
+		tagManageView:   view.New("bookmark/tag_manage.gohtml"),
+	}
+
+	return bc, repo
+}
+
+// newTagManagePostRequest builds a POST request against a /bookmarks/tags route,
+// with the given user set in context.
+func newTagManagePostRequest(t *testing.T, ctxUser user.User, target string, form url.Values) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	return r.WithContext(ctx)
+}
+
+// assertTagManageRedirect checks that a tag management form redirects back to the
+// tag management page, with the expected flash message.
+func assertTagManageRedirect(t *testing.T, w *httptest.ResponseRecorder, wantFlashLevel string, wantFlashMessage string) {
+	t.Helper()
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	if got := w.Header().Get("Location"); got != tagManagePath {
+		t.Errorf("want location %q, got %q", tagManagePath, got)
+	}
+
+	if got := decodedFlashLevel(t, w); got != wantFlashLevel {
+		t.Errorf("want flash level %q, got %q", wantFlashLevel, got)
+	}
+	if got := decodedFlashMessage(t, w); !strings.Contains(got, wantFlashMessage) {
+		t.Errorf("want flash message containing %q, got %q", wantFlashMessage, got)
+	}
+}
+
+func TestHandleTagManageView(t *testing.T) {
+	bc, _ := newTestBookmarkControllerForTagManage(
+		[]bookmark.Bookmark{testBookmarkGoEntry, testBookmarkGolangEntry},
+		[]bookmark.TagAlias{{UserUUID: testBookmarkCtxUser.UUID, Alias: "golang", Tag: "go"}},
+	)
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, tagManagePath, nil)
+	r = r.WithContext(httpcontext.WithUser(r.Context(), testBookmarkCtxUser))
+	w := httptest.NewRecorder()
+
+	bc.handleTagManageView()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+
+	if !strings.Contains(body, `<input type="hidden" name="sources" value="go ">`) {
+		t.Errorf("want a suggestion to merge tags only differing by case, got:\n%s", body)
+	}
+	if !strings.Contains(body, `<td><code>golang</code></td>`) {
+		t.Errorf("want the golang alias rendered, got:\n%s", body)
+	}
+	if !strings.Contains(body, `id="case_preserve" value="PRESERVE" checked`) {
+		t.Errorf("want tags to be saved as typed by default, got:\n%s", body)
+	}
+}
+
+func TestHandleTagMerge(t *testing.T) {
+	cases := []struct {
+		tname            string
+		form             url.Values
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantTags         []string
+		wantAliases      int
+	}{
+		{
+			tname: "merge and keep aliases",
+			form: url.Values{
+				"sources":      {"golang Go"},
+				"target":       {"go"},
+				"keep_aliases": {"true"},
+			},
+			wantFlashLevel:   "success",
+			wantFlashMessage: `Tags merged into "go" for 2 bookmarks`,
+			wantTags:         []string{"go"},
+			wantAliases:      2,
+		},
+		{
+			tname: "merge without aliases",
+			form: url.Values{
+				"sources": {"golang"},
+				"target":  {"go"},
+			},
+			wantFlashLevel:   "success",
+			wantFlashMessage: `Tags merged into "go" for 1 bookmarks`,
+			wantTags:         []string{"go"},
+			wantAliases:      0,
+		},
+		{
+			tname: "no tags to merge",
+			form: url.Values{
+				"sources": {"go"},
+				"target":  {"go"},
+			},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagMergeSourcesRequired),
+			wantTags:         []string{"golang"},
+			wantAliases:      0,
+		},
+		{
+			tname: "target required",
+			form: url.Values{
+				"sources": {"golang"},
+			},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagNameRequired),
+			wantTags:         []string{"golang"},
+			wantAliases:      0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			bc, repo := newTestBookmarkControllerForTagManage(
+				[]bookmark.Bookmark{testBookmarkGolangEntry, testBookmarkGoEntry},
+				[]bookmark.TagAlias{},
+			)
+
+			w := httptest.NewRecorder()
+			bc.handleTagMerge()(w, newTagManagePostRequest(t, testBookmarkCtxUser, "/bookmarks/tags/merge", tc.form))
+
+			assertTagManageRedirect(t, w, tc.wantFlashLevel, tc.wantFlashMessage)
+
+			gotTags := repo.Bookmarks[0].Tags
+			if strings.Join(gotTags, " ") != strings.Join(tc.wantTags, " ") {
+				t.Errorf("want tags %q, got %q", tc.wantTags, gotTags)
+			}
+
+			if got := len(repo.TagAliases); got != tc.wantAliases {
+				t.Errorf("want %d aliases, got %d", tc.wantAliases, got)
+			}
+		})
+	}
+}
+
+func TestHandleTagAliasAdd(t *testing.T) {
+	cases := []struct {
+		tname            string
+		form             url.Values
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantAliases      int
+	}{
+		{
+			tname:            "new alias",
+			form:             url.Values{"alias": {" golang "}, "tag": {"go"}},
+			wantFlashLevel:   "success",
+			wantFlashMessage: `Tag alias "golang" added`,
+			wantAliases:      1,
+		},
+		{
+			tname:            "alias applying to its own tag",
+			form:             url.Values{"alias": {"dev"}, "tag": {"dev/go"}},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagAliasSameAsTag),
+			wantAliases:      0,
+		},
+		{
+			tname:            "alias containing whitespace",
+			form:             url.Values{"alias": {"go lang"}, "tag": {"go"}},
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagNameContainsWhitespace),
+			wantAliases:      0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			bc, repo := newTestBookmarkControllerForTagManage([]bookmark.Bookmark{}, []bookmark.TagAlias{})
+
+			w := httptest.NewRecorder()
+			bc.handleTagAliasAdd()(w, newTagManagePostRequest(t, testBookmarkCtxUser, "/bookmarks/tags/aliases", tc.form))
+
+			assertTagManageRedirect(t, w, tc.wantFlashLevel, tc.wantFlashMessage)
+
+			if got := len(repo.TagAliases); got != tc.wantAliases {
+				t.Errorf("want %d aliases, got %d", tc.wantAliases, got)
+			}
+		})
+	}
+}
+
+func TestHandleTagAliasDelete(t *testing.T) {
+	cases := []struct {
+		tname            string
+		alias            string
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantAliases      int
+	}{
+		{
+			tname:            "existing alias",
+			alias:            "golang",
+			wantFlashLevel:   "success",
+			wantFlashMessage: `Tag alias "golang" deleted`,
+			wantAliases:      0,
+		},
+		{
+			tname:            "unknown alias",
+			alias:            "rustlang",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagAliasNotFound),
+			wantAliases:      1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			bc, repo := newTestBookmarkControllerForTagManage(
+				[]bookmark.Bookmark{},
+				[]bookmark.TagAlias{{UserUUID: testBookmarkCtxUser.UUID, Alias: "golang", Tag: "go"}},
+			)
+
+			form := url.Values{"alias": {tc.alias}}
+
+			w := httptest.NewRecorder()
+			bc.handleTagAliasDelete()(w, newTagManagePostRequest(t, testBookmarkCtxUser, "/bookmarks/tags/aliases/delete", form))
+
+			assertTagManageRedirect(t, w, tc.wantFlashLevel, tc.wantFlashMessage)
+
+			if got := len(repo.TagAliases); got != tc.wantAliases {
+				t.Errorf("want %d aliases, got %d", tc.wantAliases, got)
+			}
+		})
+	}
+}
+
+func TestHandleTagPreferencesUpdate(t *testing.T) {
+	cases := []struct {
+		tname            string
+		caseFolding      string
+		wantFlashLevel   string
+		wantFlashMessage string
+		wantPreferences  int
+	}{
+		{
+			tname:            "lowercase",
+			caseFolding:      "LOWERCASE",
+			wantFlashLevel:   "success",
+			wantFlashMessage: "Tag preferences updated",
+			wantPreferences:  1,
+		},
+		{
+			tname:            "unknown case folding",
+			caseFolding:      "UPPERCASE",
+			wantFlashLevel:   "danger",
+			wantFlashMessage: userFacingError(bookmark.ErrTagCaseFoldingUnknown),
+			wantPreferences:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			bc, repo := newTestBookmarkControllerForTagManage([]bookmark.Bookmark{}, []bookmark.TagAlias{})
+
+			form := url.Values{"case_folding": {tc.caseFolding}}
+
+			w := httptest.NewRecorder()
+			bc.handleTagPreferencesUpdate()(w, newTagManagePostRequest(t, testBookmarkCtxUser, "/bookmarks/tags/preferences", form))
+
+			assertTagManageRedirect(t, w, tc.wantFlashLevel, tc.wantFlashMessage)
+
+			if got := len(repo.TagPreferences); got != tc.wantPreferences {
+				t.Errorf("want %d tag preferences, got %d", tc.wantPreferences, got)
+			}
+		})
+	}
+}
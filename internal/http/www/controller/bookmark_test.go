@@ -7,9 +7,11 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -95,6 +97,12 @@ func TestHandleBookmarkListView(t *testing.T) {
 		if !strings.Contains(body, testBookmarkEntry.Title) {
 			t.Errorf("want the bookmark title rendered, got:\n%s", body)
 		}
+		if !strings.Contains(body, `id="bookmark-bulk-form"`) {
+			t.Errorf("want the bulk edit form rendered, got:\n%s", body)
+		}
+		if !strings.Contains(body, fmt.Sprintf(`name="uids" value="%s"`, testBookmarkEntry.UID)) {
+			t.Errorf("want the bookmark selection checkbox rendered, got:\n%s", body)
+		}
 	})
 
 	t.Run("htmx request renders only the fragment", func(t *testing.T) {
@@ -147,6 +155,16 @@ func TestHandleBookmarkListView(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid search query, htmx request uses HX-Redirect", func(t *testing.T) {
+		bc := newTestBookmarkController([]bookmark.Bookmark{testBookmarkEntry})
+		r := newBookmarkListRequest(t, ctxUser, "search=before%3Ayesterday", true)
+		w := httptest.NewRecorder()
+
+		bc.handleBookmarkListView()(w, r)
+
+		assertHXRedirectOnError(t, w, "/bookmarks")
+	})
+
 	t.Run("invalid page number, plain request falls back to a real redirect", func(t *testing.T) {
 		bc := newTestBookmarkController([]bookmark.Bookmark{testBookmarkEntry})
 		r := newBookmarkListRequest(t, ctxUser, "page=notanumber", false)
@@ -344,6 +362,57 @@ func TestHandleTagListView(t *testing.T) {
 
 		assertHXRedirectOnError(t, w, "/bookmarks/tags")
 	})
+
+	t.Run("plain browser request renders the tag tree", func(t *testing.T) {
+		hierarchical := bookmark.Bookmark{
+			UID:       "bookmark-2",
+			UserUUID:  ctxUser.UUID,
+			URL:       "https://go.dev/doc/tutorial/add-a-test",
+			Title:     "Add a test",
+			Tags:      []string{"dev/go", "dev/go/testing"},
+			CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		}
+		bc := newTestBookmarkControllerForTagList([]bookmark.Bookmark{testBookmarkEntry, hierarchical})
+		r := newTagListRequest(t, ctxUser, "", false)
+		w := httptest.NewRecorder()
+
+		bc.handleTagListView()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, `class="tag-tree`) {
+			t.Errorf("want the tag tree rendered, got:\n%s", body)
+		}
+		if !strings.Contains(body, `title="0 bookmarks tagged dev, 2 including descendants">2</span>`) {
+			t.Errorf("want the parent tag rendered with aggregated counts, got:\n%s", body)
+		}
+		if !strings.Contains(body, `href="/bookmarks?search=tag:dev%2fgo%2ftesting"`) {
+			t.Errorf("want nested tags linked to a tag search, got:\n%s", body)
+		}
+	})
+
+	t.Run("page parameter renders the flat list", func(t *testing.T) {
+		bc := newTestBookmarkControllerForTagList([]bookmark.Bookmark{testBookmarkEntry})
+		r := newTagListRequest(t, ctxUser, "page=1", true)
+		w := httptest.NewRecorder()
+
+		bc.handleTagListView()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if strings.Contains(body, `class="tag-tree`) {
+			t.Errorf("want no tag tree, got:\n%s", body)
+		}
+		if !strings.Contains(body, `id="tag-row-`+base64.URLEncoding.EncodeToString([]byte("example"))+`"`) {
+			t.Errorf("want the tag row rendered, got:\n%s", body)
+		}
+	})
 }
 
 // newTestBookmarkControllerForTagEdit wires a bookmarkController against a
@@ -353,7 +422,7 @@ func newTestBookmarkControllerForTagEdit(bookmarks []bookmark.Bookmark) bookmark
 	repo := &bookmark.FakeRepository{Bookmarks: bookmarks}
 
 	return bookmarkController{
-		bookmarkService: bookmark.NewService(repo),
+		bookmarkService: bookmark.NewService(repo, nil),
 		tagListView:     view.New("bookmark/tag_list.gohtml"),
 		tagEditView:     view.New("bookmark/tag_edit.gohtml"),
 		tagDeleteView:   view.New("bookmark/tag_delete.gohtml"),
@@ -565,6 +634,35 @@ func TestHandleTagEdit(t *testing.T) {
 			t.Errorf("want HX-Trigger modal:close, got %q", got)
 		}
 	})
+
+	t.Run("htmx request renaming descendants refreshes the page", func(t *testing.T) {
+		b := testBookmarkEntry
+		b.Tags = []string{"example", "example/nested"}
+		repo := &bookmark.FakeRepository{Bookmarks: []bookmark.Bookmark{b}}
+		bc := newTestBookmarkControllerForTagEdit(nil)
+		bc.bookmarkService = bookmark.NewService(repo, nil)
+
+		form := url.Values{"name": {"renamed"}, "cascade": {"true"}}
+		r := newTagEditPostRequest(t, ctxUser, form, true)
+		w := httptest.NewRecorder()
+
+		bc.handleTagEdit()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("HX-Refresh"); got != "true" {
+			t.Errorf("want HX-Refresh true, got %q", got)
+		}
+		if got := decodedFlashMessage(t, w); got != "Tag updated for 1 bookmarks" {
+			t.Errorf("want a success message, got %q", got)
+		}
+
+		wantTags := []string{"renamed", "renamed/nested"}
+		if !slices.Equal(repo.Bookmarks[0].Tags, wantTags) {
+			t.Errorf("want tags %q, got %q", wantTags, repo.Bookmarks[0].Tags)
+		}
+	})
 }
 
 // newTagDeleteViewRequest builds a GET request against
@@ -768,6 +866,32 @@ func TestHandleTagDelete(t *testing.T) {
 
 		assertHXRedirectOnError(t, w, "/bookmarks/tags/not-valid-base64!!/delete")
 	})
+
+	t.Run("htmx request deleting descendants refreshes the page", func(t *testing.T) {
+		b := testBookmarkEntry
+		b.Tags = []string{"example", "example/nested", "other"}
+		repo := &bookmark.FakeRepository{Bookmarks: []bookmark.Bookmark{b}}
+		bc := newTestBookmarkControllerForTagEdit(nil)
+		bc.bookmarkService = bookmark.NewService(repo, nil)
+
+		r := newTagDeletePostRequest(t, ctxUser, "example", true)
+		r.Body = io.NopCloser(strings.NewReader(url.Values{"cascade": {"true"}}.Encode()))
+		w := httptest.NewRecorder()
+
+		bc.handleTagDelete()(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("HX-Refresh"); got != "true" {
+			t.Errorf("want HX-Refresh true, got %q", got)
+		}
+
+		wantTags := []string{"other"}
+		if !slices.Equal(repo.Bookmarks[0].Tags, wantTags) {
+			t.Errorf("want tags %q, got %q", wantTags, repo.Bookmarks[0].Tags)
+		}
+	})
 }
 
 // newTestBookmarkControllerForBookmarkAdd wires a bookmarkController against
@@ -782,7 +906,7 @@ func newTestBookmarkControllerForBookmarkAdd(ctxUser user.User, existingBookmark
 	}
 
 	return bookmarkController{
-		bookmarkService:  bookmark.NewService(repo),
+		bookmarkService:  bookmark.NewService(repo, nil),
 		queryingService:  bookmarkquerying.NewService(queryingRepo),
 		bookmarkAddView:  view.New("bookmark/bookmark_add.gohtml"),
 		bookmarkEditView: view.New("bookmark/bookmark_edit.gohtml"),
@@ -983,7 +1107,7 @@ func newTestBookmarkControllerForBookmarkEdit(ctxUser user.User, b bookmark.Book
 	}
 
 	return bookmarkController{
-		bookmarkService:  bookmark.NewService(repo),
+		bookmarkService:  bookmark.NewService(repo, nil),
 		queryingService:  bookmarkquerying.NewService(queryingRepo),
 		bookmarkListView: view.New("bookmark/bookmark_list.gohtml", "bookmark/bookmark_row.gohtml"),
 		bookmarkEditView: view.New("bookmark/bookmark_edit.gohtml"),
@@ -1185,7 +1309,7 @@ func newTestBookmarkControllerForBookmarkDelete(b bookmark.Bookmark) bookmarkCon
 	repo := &bookmark.FakeRepository{Bookmarks: []bookmark.Bookmark{b}}
 
 	return bookmarkController{
-		bookmarkService:    bookmark.NewService(repo),
+		bookmarkService:    bookmark.NewService(repo, nil),
 		bookmarkDeleteView: view.New("bookmark/bookmark_delete.gohtml"),
 	}
 }
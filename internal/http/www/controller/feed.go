@@ -22,16 +22,20 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
 	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
 	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedimporting "github.com/virtualtam/sparklemuffin/pkg/feed/importing"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 // RegisterFeedHandlers registers HTTP handlers for syndication feed operations.
 func RegisterFeedHandlers(
 	r *chi.Mux,
+	auditService *audit.Service,
 	feedService *feed.Service,
 	exportingService *feedexporting.Service,
 	importingService *feedimporting.Service,
@@ -39,6 +43,7 @@ func RegisterFeedHandlers(
 	userService *user.Service,
 ) {
 	fc := feedController{
+		auditService:     auditService,
 		feedService:      feedService,
 		exportingService: exportingService,
 		importingService: importingService,
@@ -113,6 +118,7 @@ func RegisterFeedHandlers(
 }
 
 type feedController struct {
+	auditService     *audit.Service
 	feedService      *feed.Service
 	exportingService *feedexporting.Service
 	importingService *feedimporting.Service
@@ -191,6 +197,10 @@ func (fc *feedController) handleFeedListView(
 				log.Warn().Err(err).Msg(msg)
 				view.RedirectOnError(w, r, r.URL.Path, msg)
 				return
+			} else if errors.Is(err, search.ErrQueryInvalid) {
+				log.Warn().Err(err).Str("search", searchQuery).Msg("invalid search query")
+				view.RedirectOnError(w, r, r.URL.Path, searchQueryErrorMessage(err))
+				return
 			} else if err != nil {
 				log.Error().Err(err).Msg("failed to retrieve feeds")
 				view.RedirectOnError(w, r, r.URL.Path, "failed to retrieve feeds")
@@ -880,7 +890,11 @@ func (fc *feedController) handleFeedSubscriptionAdd() func(w http.ResponseWriter
 			return
 		}
 
-		if err := fc.feedService.Subscribe(ctx, ctxUser.UUID, form.CategoryUUID, form.URL); err != nil {
+		if err := fc.feedService.Subscribe(ctx, ctxUser.UUID, form.CategoryUUID, form.URL); errors.Is(err, quota.ErrSubscriptionLimitReached) {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		} else if err != nil {
 			log.Error().Err(err).Msg("failed to subscribe to feed")
 			view.PutFlashError(w, "failed to subscribe to feed")
 			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
@@ -1268,6 +1282,12 @@ func (fc *feedController) handleFeedExport() func(w http.ResponseWriter, r *http
 			return
 		}
 
+		recordAuditEvent(r, fc.auditService, audit.Event{
+			Type:       audit.EventFeedsExported,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+		})
+
 		w.Header().Set("Content-Disposition", "attachment; filename=feeds.opml")
 		w.Header().Set("Content-Type", "application/xml")
 
@@ -1291,6 +1311,17 @@ func (fc *feedController) handleFeedImportView() func(w http.ResponseWriter, r *
 // handleFeedImport processes data submitted through the feed subscription import form.
 func (fc *feedController) handleFeedImport() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		maxImportFileSize, err := fc.importingService.MaxImportFileSize(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve import file size limit")
+			view.PutFlashError(w, "failed to process import form")
+			http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
+			return
+		}
+
 		multipartReader, err := r.MultipartReader()
 		if err != nil {
 			log.Error().Err(err).Msg("failed to access multipart reader")
@@ -1319,7 +1350,7 @@ func (fc *feedController) handleFeedImport() func(w http.ResponseWriter, r *http
 
 			switch part.FormName() {
 			case "importfile":
-				_, err = io.Copy(importFileWriter, part)
+				_, err = io.Copy(importFileWriter, limitImportFileReader(part, maxImportFileSize))
 			default:
 				err = fmt.Errorf("unexpected multipart form field: %q", part.FormName())
 			}
@@ -1332,8 +1363,11 @@ func (fc *feedController) handleFeedImport() func(w http.ResponseWriter, r *http
 			}
 		}
 
-		ctx := r.Context()
-		ctxUser := httpcontext.UserValue(ctx)
+		if err := fc.importingService.ValidateImportFileSize(ctx, ctxUser.UUID, int64(importFileBuffer.Len())); err != nil {
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
+			return
+		}
 
 		document, err := opml.Unmarshal(importFileBuffer.Bytes())
 		if err != nil {
@@ -1344,13 +1378,32 @@ func (fc *feedController) handleFeedImport() func(w http.ResponseWriter, r *http
 		}
 
 		importStatus, err := fc.importingService.ImportFromOPMLDocument(ctx, ctxUser.UUID, document)
-		if err != nil {
+		if errors.Is(err, quota.ErrSubscriptionLimitReached) {
+			// subscriptions imported before reaching the quota are kept
+			recordAuditEvent(r, fc.auditService, audit.Event{
+				Type:       audit.EventFeedsImported,
+				ActorUUID:  ctxUser.UUID,
+				TargetUUID: ctxUser.UUID,
+				Details:    importStatus.UserSummary(),
+			})
+
+			view.PutFlashWarning(w, fmt.Sprintf("Import status: %s. %s", importStatus.UserSummary(), userFacingError(err)))
+			http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
+			return
+		} else if err != nil {
 			log.Error().Err(err).Msg("failed to save imported feed subscriptions")
 			view.PutFlashError(w, "failed to save imported feed subscriptions")
 			http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
 			return
 		}
 
+		recordAuditEvent(r, fc.auditService, audit.Event{
+			Type:       audit.EventFeedsImported,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    importStatus.UserSummary(),
+		})
+
 		view.PutFlashSuccess(w, fmt.Sprintf("Import status: %s", importStatus.UserSummary()))
 		http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
 	}
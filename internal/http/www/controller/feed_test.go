@@ -58,7 +58,7 @@ func newTestFeedController(preferences feed.Preferences, entriesMetadata []feed.
 	}
 
 	return feedController{
-		feedService:              feed.NewService(feedRepo, nil, nil),
+		feedService:              feed.NewService(feedRepo, nil, nil, nil),
 		queryingService:          feedquerying.NewService(queryingRepo),
 		feedListView:             view.New("feed/feed_list.gohtml"),
 		feedSubscriptionListView: view.New("feed/subscription_list.gohtml"),
@@ -779,7 +779,7 @@ func newTestFeedControllerForCategoryEdit(category feed.Category) feedController
 	}
 
 	return feedController{
-		feedService:              feed.NewService(feedRepo, nil, nil),
+		feedService:              feed.NewService(feedRepo, nil, nil, nil),
 		queryingService:          feedquerying.NewService(queryingRepo),
 		feedSubscriptionListView: view.New("feed/subscription_list.gohtml"),
 		feedCategoryEditView:     view.New("feed/category_edit.gohtml"),
@@ -975,7 +975,7 @@ func newTestFeedControllerForSubscriptionEdit(subscription feed.Subscription, su
 	}
 
 	return feedController{
-		feedService:              feed.NewService(feedRepo, nil, nil),
+		feedService:              feed.NewService(feedRepo, nil, nil, nil),
 		queryingService:          feedquerying.NewService(queryingRepo),
 		feedSubscriptionListView: view.New("feed/subscription_list.gohtml"),
 		feedSubscriptionEditView: view.New("feed/subscription_edit.gohtml"),
@@ -1212,7 +1212,7 @@ func newTestFeedControllerForCategoryDelete(category feed.Category) feedControll
 	}
 
 	return feedController{
-		feedService:            feed.NewService(feedRepo, nil, nil),
+		feedService:            feed.NewService(feedRepo, nil, nil, nil),
 		feedCategoryDeleteView: view.New("feed/category_delete.gohtml"),
 	}
 }
@@ -1388,7 +1388,7 @@ func newTestFeedControllerForSubscriptionDelete(subscription feed.Subscription,
 	}
 
 	return feedController{
-		feedService:                feed.NewService(feedRepo, nil, nil),
+		feedService:                feed.NewService(feedRepo, nil, nil, nil),
 		queryingService:            feedquerying.NewService(queryingRepo),
 		feedSubscriptionDeleteView: view.New("feed/subscription_delete.gohtml"),
 	}
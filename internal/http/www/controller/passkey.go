@@ -0,0 +1,78 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+)
+
+const (
+	// PasskeyCeremonyCookieName is the name of the cookie identifying a pending passkey
+	// registration or login ceremony.
+	PasskeyCeremonyCookieName string = "passkey_ceremony"
+
+	passkeyLoginPath        string = "/login/passkey"
+	passkeyRegistrationPath string = "/account/passkeys/register"
+
+	// Authenticator responses are a few kilobytes at most.
+	passkeyResponseMaxBytes int64 = 64 * 1024
+)
+
+// setPasskeyCeremony sets a short-lived cookie identifying a pending passkey ceremony.
+//
+// The cookie is scoped to the path of the endpoints completing the ceremony.
+func setPasskeyCeremony(w http.ResponseWriter, path string, token string, secure bool) {
+	cookie := http.Cookie{
+		Name:     PasskeyCeremonyCookieName,
+		Value:    token,
+		Expires:  time.Now().UTC().Add(passkey.CeremonyTTL),
+		Path:     path,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, &cookie)
+}
+
+// clearPasskeyCeremony clears the passkey ceremony cookie.
+func clearPasskeyCeremony(w http.ResponseWriter, path string, secure bool) {
+	cookie := http.Cookie{
+		Name:     PasskeyCeremonyCookieName,
+		Value:    "",
+		Path:     path,
+		Expires:  time.Unix(0, 1),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, &cookie)
+}
+
+// passkeyCeremonyToken returns the token identifying the pending passkey ceremony, if any.
+func passkeyCeremonyToken(r *http.Request) string {
+	cookie, err := r.Cookie(PasskeyCeremonyCookieName)
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// writePasskeyOptions encodes the options passed to the browser's WebAuthn API as JSON.
+func writePasskeyOptions(w http.ResponseWriter, options any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		log.Error().Err(err).Msg("failed to encode passkey options")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+)
+
+const (
+	passwordResetRequestedMessage = "If an account is registered with this email address, you will receive a link to reset your password shortly."
+	passwordResetInvalidMessage   = "This password reset link is invalid or has expired; please request a new one."
+)
+
+// registerPasswordResetHandlers registers handlers for resetting a forgotten password.
+func registerPasswordResetHandlers(
+	r *chi.Mux,
+	publicURL *url.URL,
+	auditService *audit.Service,
+	passwordResetService *passwordreset.Service,
+	sessionService *session.Service,
+) {
+	pc := passwordResetController{
+		publicURL:            publicURL,
+		auditService:         auditService,
+		passwordResetService: passwordResetService,
+		sessionService:       sessionService,
+
+		passwordResetRequestView: view.New("session/password_reset_request.gohtml"),
+		passwordResetView:        view.New("session/password_reset.gohtml"),
+	}
+
+	r.Get("/password-reset", pc.passwordResetRequestView.Handle)
+	r.With(middleware.RateLimitPasswordReset).Post("/password-reset", pc.handlePasswordResetRequest())
+
+	r.Route("/password-reset/{token}", func(sr chi.Router) {
+		// the URL contains a secret token that must not leak to third parties
+		sr.Use(middleware.NoReferrer)
+
+		sr.Get("/", pc.handlePasswordResetView())
+		sr.With(middleware.RateLimitPasswordReset).Post("/", pc.handlePasswordReset())
+	})
+}
+
+type passwordResetController struct {
+	publicURL *url.URL
+
+	auditService         *audit.Service
+	passwordResetService *passwordreset.Service
+	sessionService       *session.Service
+
+	passwordResetRequestView *view.View
+	passwordResetView        *view.View
+}
+
+// handlePasswordResetRequest processes data submitted through the password reset request form.
+func (pc *passwordResetController) handlePasswordResetRequest() func(w http.ResponseWriter, r *http.Request) {
+	type passwordResetRequestForm struct {
+		Email string `schema:"email"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var form passwordResetRequestForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse password reset request form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if err := pc.passwordResetService.Request(ctx, form.Email, pc.resetURL); err != nil {
+			log.Error().
+				Err(err).
+				Str("client_ip", chimiddleware.GetClientIP(ctx)).
+				Str("email", form.Email).
+				Msg("failed to process password reset request")
+		}
+
+		// the event is recorded whether or not the address is registered
+		recordAuditEvent(r, pc.auditService, audit.Event{
+			Type:    audit.EventPasswordResetRequested,
+			Details: form.Email,
+		})
+
+		// the same message is displayed whether or not the address is registered
+		view.PutFlashInfo(w, passwordResetRequestedMessage)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// handlePasswordResetView renders the password reset form.
+func (pc *passwordResetController) handlePasswordResetView() func(w http.ResponseWriter, r *http.Request) {
+	type passwordResetViewContent struct {
+		Token string
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := chi.URLParam(r, "token")
+
+		if _, err := pc.passwordResetService.ByToken(r.Context(), token); err != nil {
+			if !errors.Is(err, passwordreset.ErrNotFound) {
+				log.Error().Err(err).Msg("failed to retrieve password reset token")
+			}
+			view.PutFlashError(w, passwordResetInvalidMessage)
+			http.Redirect(w, r, "/password-reset", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Reset Password",
+			Content: passwordResetViewContent{
+				Token: token,
+			},
+		}
+
+		pc.passwordResetView.Render(w, r, viewData)
+	}
+}
+
+// handlePasswordReset processes data submitted through the password reset form.
+func (pc *passwordResetController) handlePasswordReset() func(w http.ResponseWriter, r *http.Request) {
+	type passwordResetForm struct {
+		NewPassword             string `schema:"new_password"`
+		NewPasswordConfirmation string `schema:"new_password_confirmation"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var form passwordResetForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse password reset form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		reset := passwordreset.Reset{
+			Token:                   chi.URLParam(r, "token"),
+			NewPassword:             form.NewPassword,
+			NewPasswordConfirmation: form.NewPasswordConfirmation,
+		}
+
+		userUUID, err := pc.passwordResetService.Reset(ctx, reset)
+		if errors.Is(err, passwordreset.ErrNotFound) || errors.Is(err, passwordreset.ErrTokenRequired) {
+			view.PutFlashError(w, passwordResetInvalidMessage)
+			http.Redirect(w, r, "/password-reset", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to reset password")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		recordAuditEvent(r, pc.auditService, audit.Event{
+			Type:       audit.EventPasswordResetCompleted,
+			ActorUUID:  userUUID,
+			TargetUUID: userUUID,
+		})
+
+		// a reset password must invalidate any remember-me token issued before the reset
+		if err := pc.sessionService.DeleteByUserUUID(ctx, userUUID); err != nil {
+			log.Error().Err(err).Msg("failed to revoke user sessions")
+			view.PutFlashWarning(w, "Your password was reset, but active sessions could not be revoked; please log out of other devices manually")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "Your password has been successfully reset; you can now log in")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// resetURL returns the absolute URL of the password reset form for a given token.
+func (pc *passwordResetController) resetURL(token string) string {
+	return pc.publicURL.JoinPath("password-reset", token).String()
+}
@@ -0,0 +1,224 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testPasswordResetURLRegex = regexp.MustCompile(`https://sparklemuffin\.test/password-reset/([A-Za-z0-9_=-]+)`)
+)
+
+func newTestPasswordResetMux(t *testing.T, users ...user.User) (*chi.Mux, *passwordreset.Service, *user.FakeRepository, *session.FakeRepository, *notification.FakeNotifier) {
+	t.Helper()
+
+	userRepo := &user.FakeRepository{Users: users}
+	userService := user.NewService(userRepo, user.FakePasswordHasher(t))
+
+	notifier := &notification.FakeNotifier{}
+	passwordResetService, err := passwordreset.NewService(
+		&passwordreset.FakeRepository{UserRepository: userRepo},
+		userService,
+		notifier,
+		"hmac-key",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionRepo := &session.FakeRepository{}
+	sessionService, err := session.NewService(sessionRepo, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicURL, err := url.Parse("https://sparklemuffin.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := chi.NewMux()
+	RegisterSessionHandlers(mux, publicURL, true, nil, nil, nil, passwordResetService, nil, sessionService, nil, newTestTwoFactorService(t, &twofactor.FakeRepository{}), userService)
+
+	return mux, passwordResetService, userRepo, sessionRepo, notifier
+}
+
+func postPasswordResetForm(t *testing.T, h http.Handler, target string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandleUserLoginView_PasswordResetLink(t *testing.T) {
+	cases := []struct {
+		tname   string
+		enabled bool
+	}{
+		{tname: "password reset enabled", enabled: true},
+		{tname: "password reset disabled", enabled: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			var mux *chi.Mux
+
+			if tc.enabled {
+				mux, _, _, _, _ = newTestPasswordResetMux(t)
+			} else {
+				sessionService, err := session.NewService(&session.FakeRepository{}, "hmac-key")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				mux = chi.NewMux()
+				RegisterSessionHandlers(mux, nil, true, nil, nil, nil, nil, nil, sessionService, nil, newTestTwoFactorService(t, &twofactor.FakeRepository{}), user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t)))
+			}
+
+			r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/login", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d", w.Code)
+			}
+
+			got := strings.Contains(w.Body.String(), `href="/password-reset"`)
+			if got != tc.enabled {
+				t.Errorf("want password reset link displayed: %t, got %t", tc.enabled, got)
+			}
+		})
+	}
+}
+
+func TestHandlePasswordReset(t *testing.T) {
+	testUser := user.User{
+		UUID:         "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:        "jane.doe@example.org",
+		DisplayName:  "Jane Doe",
+		PasswordHash: "old-password-hash",
+	}
+
+	t.Run("unknown email address", func(t *testing.T) {
+		mux, passwordResetService, _, _, notifier := newTestPasswordResetMux(t, testUser)
+
+		w := postPasswordResetForm(t, mux, "/password-reset", url.Values{"email": {"john.doe@example.org"}})
+		passwordResetService.Wait()
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := decodedFlashMessage(t, w); got != passwordResetRequestedMessage {
+			t.Errorf("want the generic confirmation message, got %q", got)
+		}
+		if len(notifier.Messages) != 0 {
+			t.Errorf("want no message sent, got %d", len(notifier.Messages))
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mux, _, _, _, _ := newTestPasswordResetMux(t, testUser)
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/password-reset/invalid-token", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/password-reset" {
+			t.Errorf("want redirect to %q, got %q", "/password-reset", got)
+		}
+		if got := w.Header().Get("Referrer-Policy"); got != "no-referrer" {
+			t.Errorf("want Referrer-Policy %q, got %q", "no-referrer", got)
+		}
+	})
+
+	t.Run("reset password and revoke sessions", func(t *testing.T) {
+		mux, passwordResetService, userRepo, sessionRepo, notifier := newTestPasswordResetMux(t, testUser)
+
+		sessionRepo.Sessions = []session.Session{
+			{
+				UserUUID:               testUser.UUID,
+				RememberTokenHash:      "remember-token-hash",
+				RememberTokenExpiresAt: time.Now().Add(time.Hour),
+			},
+		}
+
+		w := postPasswordResetForm(t, mux, "/password-reset", url.Values{"email": {testUser.Email}})
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+
+		passwordResetService.Wait()
+
+		if len(notifier.Messages) != 1 {
+			t.Fatalf("want 1 message sent, got %d", len(notifier.Messages))
+		}
+
+		matches := testPasswordResetURLRegex.FindStringSubmatch(notifier.Messages[0].Body)
+		if len(matches) != 2 {
+			t.Fatalf("want a reset link in the message body, got:\n%s", notifier.Messages[0].Body)
+		}
+		resetPath := "/password-reset/" + matches[1]
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, resetPath, nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		w = postPasswordResetForm(t, mux, resetPath, url.Values{
+			"new_password":              {"the-new-password"},
+			"new_password_confirmation": {"the-new-password"},
+		})
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Errorf("want redirect to %q, got %q", "/login", got)
+		}
+		if userRepo.Users[0].PasswordHash == testUser.PasswordHash {
+			t.Error("want the password hash to be updated")
+		}
+		if len(sessionRepo.Sessions) != 0 {
+			t.Errorf("want all sessions revoked, got %d", len(sessionRepo.Sessions))
+		}
+
+		// the token is single-use
+		w = postPasswordResetForm(t, mux, resetPath, url.Values{
+			"new_password":              {"another-password"},
+			"new_password_confirmation": {"another-password"},
+		})
+
+		if got := w.Header().Get("Location"); got != "/password-reset" {
+			t.Errorf("want redirect to %q, got %q", "/password-reset", got)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+)
+
+const (
+	bytesPerMiB = 1024 * 1024
+
+	// importFormValueMaxSize is the maximum size of the import form values
+	// accompanying an import file, in bytes.
+	importFormValueMaxSize = 1024
+)
+
+var errQuotaFormLimitInvalid = errors.New("quota form: limits must be whole numbers")
+
+// quotaUsageRow holds the usage of a given resource, for display purposes.
+type quotaUsageRow struct {
+	Label string
+	Used  int64
+	Limit int64
+
+	// Percent is the share of the limit currently used, capped to 100.
+	Percent int64
+}
+
+// Unlimited returns whether the resource is unlimited.
+func (q quotaUsageRow) Unlimited() bool {
+	return q.Limit == 0
+}
+
+// quotaSummaryContent holds a user's storage usage and limits, for display purposes.
+type quotaSummaryContent struct {
+	Rows              []quotaUsageRow
+	MaxImportFileSize string
+}
+
+func newQuotaUsageRow(label string, used, limit int64) quotaUsageRow {
+	row := quotaUsageRow{
+		Label: label,
+		Used:  used,
+		Limit: limit,
+	}
+
+	if limit > 0 {
+		row.Percent = min(used*100/limit, 100)
+	}
+
+	return row
+}
+
+func newQuotaSummaryContent(summary quota.Summary) quotaSummaryContent {
+	return quotaSummaryContent{
+		Rows: []quotaUsageRow{
+			newQuotaUsageRow("Bookmarks", summary.Usage.Bookmarks, summary.Limits.MaxBookmarks),
+			newQuotaUsageRow("Feed subscriptions", summary.Usage.Subscriptions, summary.Limits.MaxSubscriptions),
+		},
+		MaxImportFileSize: formatImportFileSize(summary.Limits.MaxImportFileSize),
+	}
+}
+
+// formatImportFileSize returns a human-readable import file size limit.
+func formatImportFileSize(size int64) string {
+	if size == 0 {
+		return "unlimited"
+	}
+
+	if size%bytesPerMiB == 0 {
+		return fmt.Sprintf("%d MiB", size/bytesPerMiB)
+	}
+
+	return fmt.Sprintf("%.1f MiB", float64(size)/bytesPerMiB)
+}
+
+// limitImportFileReader returns a reader that stops one byte past the maximum size of
+// an import file, so that larger files can be rejected without buffering them entirely.
+//
+// A zero maxSize means the size of import files is not limited.
+func limitImportFileReader(r io.Reader, maxSize int64) io.Reader {
+	if maxSize == 0 {
+		return r
+	}
+
+	return io.LimitReader(r, maxSize+1)
+}
+
+// quotaOverrideForm holds the per-user limits submitted by an administrator.
+//
+// An empty field inherits the instance-wide limit, and 0 lifts the limit.
+type quotaOverrideForm struct {
+	MaxBookmarks         string `schema:"max_bookmarks"`
+	MaxSubscriptions     string `schema:"max_subscriptions"`
+	MaxImportFileSizeMiB string `schema:"max_import_file_size_mib"`
+}
+
+// newQuotaOverrideForm returns the form values corresponding to a quota.Override.
+func newQuotaOverrideForm(override quota.Override) quotaOverrideForm {
+	var form quotaOverrideForm
+
+	if override.MaxBookmarks != nil {
+		form.MaxBookmarks = strconv.FormatInt(*override.MaxBookmarks, 10)
+	}
+	if override.MaxSubscriptions != nil {
+		form.MaxSubscriptions = strconv.FormatInt(*override.MaxSubscriptions, 10)
+	}
+	if override.MaxImportFileSize != nil {
+		form.MaxImportFileSizeMiB = strconv.FormatInt(*override.MaxImportFileSize/bytesPerMiB, 10)
+	}
+
+	return form
+}
+
+// override returns the quota.Override corresponding to the form values.
+func (f quotaOverrideForm) override(userUUID string) (quota.Override, error) {
+	maxBookmarks, err := parseQuotaLimit(f.MaxBookmarks)
+	if err != nil {
+		return quota.Override{}, err
+	}
+
+	maxSubscriptions, err := parseQuotaLimit(f.MaxSubscriptions)
+	if err != nil {
+		return quota.Override{}, err
+	}
+
+	maxImportFileSize, err := parseQuotaLimit(f.MaxImportFileSizeMiB)
+	if err != nil {
+		return quota.Override{}, err
+	}
+	if maxImportFileSize != nil {
+		*maxImportFileSize *= bytesPerMiB
+	}
+
+	return quota.Override{
+		UserUUID:          userUUID,
+		MaxBookmarks:      maxBookmarks,
+		MaxSubscriptions:  maxSubscriptions,
+		MaxImportFileSize: maxImportFileSize,
+	}, nil
+}
+
+// parseQuotaLimit parses an optional limit; an empty value returns nil.
+func parseQuotaLimit(value string) (*int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", errQuotaFormLimitInvalid, value)
+	}
+
+	return &limit, nil
+}
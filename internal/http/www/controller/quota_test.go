@@ -0,0 +1,50 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLimitImportFileReader(t *testing.T) {
+	cases := []struct {
+		tname   string
+		content string
+		maxSize int64
+		want    string
+	}{
+		{
+			tname:   "unlimited",
+			content: "0123456789",
+			want:    "0123456789",
+		},
+		{
+			tname:   "below limit",
+			content: "0123",
+			maxSize: 8,
+			want:    "0123",
+		},
+		{
+			tname:   "above limit",
+			content: "0123456789",
+			maxSize: 4,
+			want:    "01234",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got, err := io.ReadAll(limitImportFileReader(strings.NewReader(tc.content), tc.maxSize))
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if string(got) != tc.want {
+				t.Errorf("want %q, got %q", tc.want, string(got))
+			}
+		})
+	}
+}
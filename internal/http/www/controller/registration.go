@@ -0,0 +1,368 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+)
+
+const (
+	invitationDefaultMaxUses      = 1
+	invitationDefaultValidityDays = 7
+
+	invitationInvalidMessage   = "This invitation link is invalid, has expired or has already been used."
+	confirmationInvalidMessage = "This confirmation link is invalid or has expired; please sign up again."
+	confirmationSentMessage    = "Almost there! Open the link we emailed you within 24 hours to confirm your address and create your account."
+)
+
+// registerRegistrationHandlers registers handlers for self-service registration and invitations.
+//
+// Registration handlers are only registered if registration is enabled on the instance.
+func registerRegistrationHandlers(
+	r *chi.Mux,
+	publicURL *url.URL,
+	auditService *audit.Service,
+	registrationService *registration.Service,
+) {
+	rc := registrationController{
+		publicURL:           publicURL,
+		auditService:        auditService,
+		registrationService: registrationService,
+
+		invitationListView:  view.New("account/invitation_list.gohtml"),
+		registerView:        view.New("session/register.gohtml"),
+		registerConfirmView: view.New("session/register_confirm.gohtml"),
+	}
+
+	if registrationService.Enabled() {
+		r.Get("/register", rc.handleRegisterView())
+		r.With(middleware.RateLimitRegistration).Post("/register", rc.handleRegister())
+
+		r.Route("/register/confirm/{token}", func(sr chi.Router) {
+			// the URL contains a secret token that must not leak to third parties
+			sr.Use(middleware.NoReferrer)
+
+			sr.Get("/", rc.handleRegisterConfirmView())
+			sr.With(middleware.RateLimitRegistration).Post("/", rc.handleRegisterConfirm())
+		})
+	}
+
+	r.Route("/account/invitations", func(sr chi.Router) {
+		sr.Use(func(h http.Handler) http.Handler {
+			return middleware.AuthenticatedUser(h.ServeHTTP)
+		})
+
+		sr.Get("/", rc.handleInvitationListView())
+		sr.Post("/", rc.handleInvitationAdd())
+		sr.Post("/{uuid}/delete", rc.handleInvitationDelete())
+	})
+}
+
+type registrationController struct {
+	publicURL *url.URL
+
+	auditService        *audit.Service
+	registrationService *registration.Service
+
+	invitationListView  *view.View
+	registerView        *view.View
+	registerConfirmView *view.View
+}
+
+// handleRegisterView renders the registration form.
+func (rc *registrationController) handleRegisterView() func(w http.ResponseWriter, r *http.Request) {
+	type registerViewContent struct {
+		InvitationToken   string
+		EmailConfirmation bool
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if httpcontext.UserValue(r.Context()) != nil {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		invitationToken := r.URL.Query().Get("invitation")
+
+		if rc.registrationService.InvitationsEnabled() {
+			if _, err := rc.registrationService.InvitationByToken(r.Context(), invitationToken); err != nil {
+				if !errors.Is(err, registration.ErrInvitationInvalid) && !errors.Is(err, registration.ErrTokenRequired) {
+					log.Error().Err(err).Msg("failed to retrieve invitation")
+				}
+				view.PutFlashError(w, invitationInvalidMessage)
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+		}
+
+		viewData := view.Data{
+			Title: "Sign up",
+			Content: registerViewContent{
+				InvitationToken:   invitationToken,
+				EmailConfirmation: rc.registrationService.EmailConfirmationRequired(),
+			},
+		}
+
+		rc.registerView.Render(w, r, viewData)
+	}
+}
+
+// handleRegister processes data submitted through the registration form.
+func (rc *registrationController) handleRegister() func(w http.ResponseWriter, r *http.Request) {
+	type registerForm struct {
+		InvitationToken      string `schema:"invitation"`
+		Email                string `schema:"email"`
+		NickName             string `schema:"nick_name"`
+		DisplayName          string `schema:"display_name"`
+		Password             string `schema:"password"`
+		PasswordConfirmation string `schema:"password_confirmation"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var form registerForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse registration form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		signup := registration.Signup{
+			InvitationToken:      form.InvitationToken,
+			Email:                form.Email,
+			NickName:             form.NickName,
+			DisplayName:          form.DisplayName,
+			Password:             form.Password,
+			PasswordConfirmation: form.PasswordConfirmation,
+		}
+
+		userUUID, err := rc.registrationService.Register(ctx, signup, rc.confirmURL)
+		if errors.Is(err, registration.ErrInvitationInvalid) || errors.Is(err, registration.ErrInvitationRequired) {
+			view.PutFlashError(w, invitationInvalidMessage)
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to register user")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, rc.registerPath(form.InvitationToken), http.StatusSeeOther)
+			return
+		}
+
+		if rc.registrationService.EmailConfirmationRequired() {
+			view.PutFlashInfo(w, confirmationSentMessage)
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		rc.recordAccountRegistered(r, userUUID)
+
+		view.PutFlashSuccess(w, "Your account has been created; you can now log in")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// handleRegisterConfirmView renders the email address confirmation form.
+func (rc *registrationController) handleRegisterConfirmView() func(w http.ResponseWriter, r *http.Request) {
+	type registerConfirmViewContent struct {
+		Token string
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewData := view.Data{
+			Title: "Confirm your email address",
+			Content: registerConfirmViewContent{
+				Token: chi.URLParam(r, "token"),
+			},
+		}
+
+		rc.registerConfirmView.Render(w, r, viewData)
+	}
+}
+
+// handleRegisterConfirm confirms a pending registration and creates the corresponding account.
+func (rc *registrationController) handleRegisterConfirm() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userUUID, err := rc.registrationService.Confirm(r.Context(), chi.URLParam(r, "token"))
+		if errors.Is(err, registration.ErrNotFound) || errors.Is(err, registration.ErrTokenRequired) {
+			view.PutFlashError(w, confirmationInvalidMessage)
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to confirm registration")
+			view.PutFlashError(w, "There was an error creating your account")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		rc.recordAccountRegistered(r, userUUID)
+
+		view.PutFlashSuccess(w, "Your email address has been confirmed and your account created; you can now log in")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
+
+// handleInvitationListView renders the list of invitations issued by the current user.
+func (rc *registrationController) handleInvitationListView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc.renderInvitationList(w, r, "")
+	}
+}
+
+// handleInvitationAdd issues a new invitation, and renders its link.
+//
+// The link is only displayed once, as the invitation token is not stored in clear text.
+func (rc *registrationController) handleInvitationAdd() func(w http.ResponseWriter, r *http.Request) {
+	type invitationForm struct {
+		MaxUses      int `schema:"max_uses"`
+		ValidityDays int `schema:"validity_days"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form invitationForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse invitation form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, "/account/invitations", http.StatusSeeOther)
+			return
+		}
+
+		invitation, token, err := rc.registrationService.InvitationAdd(ctx, ctxUser.UUID, form.MaxUses, form.ValidityDays)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to add invitation")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/account/invitations", http.StatusSeeOther)
+			return
+		}
+
+		recordAuditEvent(r, rc.auditService, audit.Event{
+			Type:       audit.EventInvitationCreated,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    fmt.Sprintf("%s (max uses: %d)", invitation.UUID, invitation.MaxUses),
+		})
+
+		rc.renderInvitationList(w, r, rc.invitationURL(token))
+	}
+}
+
+// handleInvitationDelete revokes an invitation issued by the current user.
+func (rc *registrationController) handleInvitationDelete() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		invitationUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if err := rc.registrationService.InvitationDelete(ctx, ctxUser.UUID, invitationUUID); err != nil {
+			log.Error().Err(err).Msg("failed to delete invitation")
+			view.PutFlashError(w, "failed to delete invitation")
+			http.Redirect(w, r, "/account/invitations", http.StatusSeeOther)
+			return
+		}
+
+		recordAuditEvent(r, rc.auditService, audit.Event{
+			Type:       audit.EventInvitationDeleted,
+			ActorUUID:  ctxUser.UUID,
+			TargetUUID: ctxUser.UUID,
+			Details:    invitationUUID,
+		})
+
+		view.PutFlashSuccess(w, "The invitation has been revoked")
+		http.Redirect(w, r, "/account/invitations", http.StatusSeeOther)
+	}
+}
+
+// renderInvitationList renders the list of invitations issued by the current user, and the
+// link of a newly created invitation, if any.
+func (rc *registrationController) renderInvitationList(w http.ResponseWriter, r *http.Request, newInvitationURL string) {
+	type invitationListViewContent struct {
+		Enabled             bool
+		Invitations         []registration.Invitation
+		NewInvitationURL    string
+		DefaultMaxUses      int
+		DefaultValidityDays int
+		MaxUsesLimit        int
+		ValidityDaysLimit   int
+	}
+
+	ctx := r.Context()
+	ctxUser := httpcontext.UserValue(ctx)
+
+	content := invitationListViewContent{
+		Enabled:             rc.registrationService.InvitationsEnabled(),
+		NewInvitationURL:    newInvitationURL,
+		DefaultMaxUses:      invitationDefaultMaxUses,
+		DefaultValidityDays: invitationDefaultValidityDays,
+		MaxUsesLimit:        registration.InvitationMaxUsesLimit,
+		ValidityDaysLimit:   registration.InvitationValidityDaysLimit,
+	}
+
+	if content.Enabled {
+		invitations, err := rc.registrationService.InvitationsByUserUUID(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve invitations")
+			view.PutFlashError(w, "failed to retrieve invitations")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		content.Invitations = invitations
+	}
+
+	viewData := view.Data{
+		Title:   "Invitations",
+		Content: content,
+	}
+
+	rc.invitationListView.Render(w, r, viewData)
+}
+
+// recordAccountRegistered records the creation of an account by its owner.
+func (rc *registrationController) recordAccountRegistered(r *http.Request, userUUID string) {
+	recordAuditEvent(r, rc.auditService, audit.Event{
+		Type:       audit.EventAccountRegistered,
+		ActorUUID:  userUUID,
+		TargetUUID: userUUID,
+	})
+}
+
+// confirmURL returns the absolute URL of the email address confirmation form for a given token.
+func (rc *registrationController) confirmURL(token string) string {
+	return rc.publicURL.JoinPath("register", "confirm", token).String()
+}
+
+// invitationURL returns the absolute URL of the registration form for a given invitation token.
+func (rc *registrationController) invitationURL(token string) string {
+	u := rc.publicURL.JoinPath("register")
+	u.RawQuery = url.Values{"invitation": {token}}.Encode()
+
+	return u.String()
+}
+
+// registerPath returns the path of the registration form, for a given invitation token, if any.
+func (rc *registrationController) registerPath(invitationToken string) string {
+	if invitationToken == "" {
+		return "/register"
+	}
+
+	return "/register?" + url.Values{"invitation": {invitationToken}}.Encode()
+}
@@ -0,0 +1,342 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var (
+	testInvitationURLRegex   = regexp.MustCompile(`https://sparklemuffin\.test/register\?invitation=([A-Za-z0-9_%-]+)`)
+	testConfirmationURLRegex = regexp.MustCompile(`https://sparklemuffin\.test/register/confirm/([A-Za-z0-9_=-]+)`)
+)
+
+type testRegistrationMux struct {
+	*chi.Mux
+
+	auditRepo    *audit.FakeRepository
+	userRepo     *user.FakeRepository
+	notifier     *notification.FakeNotifier
+	registration *registration.FakeRepository
+}
+
+func newTestRegistrationMux(t *testing.T, config registration.Config, users ...user.User) testRegistrationMux {
+	t.Helper()
+
+	userRepo := &user.FakeRepository{Users: users}
+	userService := user.NewService(userRepo, user.FakePasswordHasher(t))
+
+	notifier := &notification.FakeNotifier{}
+	registrationRepo := &registration.FakeRepository{UserRepository: userRepo}
+
+	registrationService, err := registration.NewService(registrationRepo, userService, notifier, config, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService, err := session.NewService(&session.FakeRepository{}, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicURL, err := url.Parse("https://sparklemuffin.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditRepo := &audit.FakeRepository{}
+
+	mux := chi.NewMux()
+	RegisterSessionHandlers(mux, publicURL, true, audit.NewService(auditRepo), nil, nil, nil, registrationService, sessionService, nil, newTestTwoFactorService(t, &twofactor.FakeRepository{}), userService)
+
+	return testRegistrationMux{
+		Mux:          mux,
+		auditRepo:    auditRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+		registration: registrationRepo,
+	}
+}
+
+func newTestSignupForm(invitationToken string) url.Values {
+	form := url.Values{
+		"email":                 {"john.doe@example.org"},
+		"nick_name":             {"john"},
+		"display_name":          {"John Doe"},
+		"password":              {"correct horse battery staple"},
+		"password_confirmation": {"correct horse battery staple"},
+	}
+
+	if invitationToken != "" {
+		form.Set("invitation", invitationToken)
+	}
+
+	return form
+}
+
+func getRequest(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandleUserLoginView_RegistrationLink(t *testing.T) {
+	cases := []struct {
+		mode registration.Mode
+		want bool
+	}{
+		{mode: registration.ModeClosed, want: false},
+		{mode: registration.ModeInviteOnly, want: false},
+		{mode: registration.ModeOpen, want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			mux := newTestRegistrationMux(t, registration.Config{Mode: tc.mode})
+
+			w := getRequest(t, mux, "/login")
+			if w.Code != http.StatusOK {
+				t.Fatalf("want status 200, got %d", w.Code)
+			}
+
+			got := strings.Contains(w.Body.String(), `href="/register"`)
+			if got != tc.want {
+				t.Errorf("want registration link displayed: %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleRegister(t *testing.T) {
+	t.Run("closed", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeClosed})
+
+		if w := getRequest(t, mux, "/register"); w.Code != http.StatusNotFound {
+			t.Errorf("want status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if w := postPasswordResetForm(t, mux, "/register", newTestSignupForm("")); w.Code != http.StatusMethodNotAllowed && w.Code != http.StatusNotFound {
+			t.Errorf("want the form to be rejected, got %d", w.Code)
+		}
+		if len(mux.userRepo.Users) != 0 {
+			t.Errorf("want no user, got %d", len(mux.userRepo.Users))
+		}
+	})
+
+	t.Run("open", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeOpen})
+
+		if w := getRequest(t, mux, "/register"); w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		w := postPasswordResetForm(t, mux, "/register", newTestSignupForm(""))
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Errorf("want redirection to /login, got %q", got)
+		}
+		if got := decodedFlashLevel(t, w); got != "success" {
+			t.Errorf("want a success flash, got %q", got)
+		}
+
+		if len(mux.userRepo.Users) != 1 {
+			t.Fatalf("want 1 user, got %d", len(mux.userRepo.Users))
+		}
+		if mux.userRepo.Users[0].IsAdmin {
+			t.Error("want a user without administration privileges")
+		}
+
+		if len(mux.auditRepo.Events) != 1 || mux.auditRepo.Events[0].Type != audit.EventAccountRegistered {
+			t.Errorf("want an %q event, got %v", audit.EventAccountRegistered, mux.auditRepo.Events)
+		}
+	})
+
+	t.Run("open with invalid information", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeOpen})
+
+		form := newTestSignupForm("")
+		form.Set("password_confirmation", "incorrect horse battery staple")
+
+		w := postPasswordResetForm(t, mux, "/register", form)
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != "/register" {
+			t.Errorf("want redirection to /register, got %q", got)
+		}
+		if got := decodedFlashMessage(t, w); got != "Error: "+userFacingError(user.ErrPasswordConfirmationMismatch) {
+			t.Errorf("want the password confirmation error, got %q", got)
+		}
+		if len(mux.userRepo.Users) != 0 {
+			t.Errorf("want no user, got %d", len(mux.userRepo.Users))
+		}
+	})
+
+	t.Run("invite-only without invitation", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeInviteOnly})
+
+		w := getRequest(t, mux, "/register")
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := decodedFlashMessage(t, w); got != "Error: "+invitationInvalidMessage {
+			t.Errorf("want the invalid invitation message, got %q", got)
+		}
+
+		w = postPasswordResetForm(t, mux, "/register", newTestSignupForm(""))
+		if got := decodedFlashMessage(t, w); got != "Error: "+invitationInvalidMessage {
+			t.Errorf("want the invalid invitation message, got %q", got)
+		}
+		if len(mux.userRepo.Users) != 0 {
+			t.Errorf("want no user, got %d", len(mux.userRepo.Users))
+		}
+	})
+
+	t.Run("email confirmation", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeOpen, EmailConfirmation: true})
+
+		w := postPasswordResetForm(t, mux, "/register", newTestSignupForm(""))
+		if got := decodedFlashMessage(t, w); got != confirmationSentMessage {
+			t.Errorf("want the confirmation message, got %q", got)
+		}
+		if len(mux.userRepo.Users) != 0 {
+			t.Fatalf("want no user before confirmation, got %d", len(mux.userRepo.Users))
+		}
+		if len(mux.notifier.Messages) != 1 {
+			t.Fatalf("want 1 message, got %d", len(mux.notifier.Messages))
+		}
+
+		matches := testConfirmationURLRegex.FindStringSubmatch(mux.notifier.Messages[0].Body)
+		if matches == nil {
+			t.Fatalf("want a confirmation link, got:\n%s", mux.notifier.Messages[0].Body)
+		}
+
+		confirmPath := "/register/confirm/" + matches[1]
+
+		w = getRequest(t, mux, confirmPath)
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+		if got := w.Header().Get("Referrer-Policy"); got != "no-referrer" {
+			t.Errorf("want Referrer-Policy %q, got %q", "no-referrer", got)
+		}
+		if len(mux.userRepo.Users) != 0 {
+			t.Fatalf("want no user before the form is submitted, got %d", len(mux.userRepo.Users))
+		}
+
+		w = postPasswordResetForm(t, mux, confirmPath, url.Values{})
+		if got := decodedFlashLevel(t, w); got != "success" {
+			t.Errorf("want a success flash, got %q", got)
+		}
+		if len(mux.userRepo.Users) != 1 {
+			t.Fatalf("want 1 user, got %d", len(mux.userRepo.Users))
+		}
+
+		w = postPasswordResetForm(t, mux, confirmPath, url.Values{})
+		if got := decodedFlashMessage(t, w); got != "Error: "+confirmationInvalidMessage {
+			t.Errorf("want the invalid confirmation message, got %q", got)
+		}
+	})
+}
+
+func TestHandleInvitations(t *testing.T) {
+	inviter := newTestTwoFactorUser(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeOpen}, inviter)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, newTestTwoFactorAccountRequest(t, http.MethodGet, "/account/invitations", inviter, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Invitations are disabled") {
+			t.Error("want invitations to be disabled")
+		}
+	})
+
+	t.Run("invite and register", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeInviteOnly}, inviter)
+
+		form := url.Values{"max_uses": {"1"}, "validity_days": {"7"}}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/invitations", inviter, form))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		matches := testInvitationURLRegex.FindStringSubmatch(w.Body.String())
+		if matches == nil {
+			t.Fatal("want the invitation link to be displayed")
+		}
+		token, err := url.QueryUnescape(matches[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(mux.registration.Invitations) != 1 {
+			t.Fatalf("want 1 invitation, got %d", len(mux.registration.Invitations))
+		}
+		if mux.registration.Invitations[0].CreatedByUUID != inviter.UUID {
+			t.Errorf("want invitation issued by %q, got %q", inviter.UUID, mux.registration.Invitations[0].CreatedByUUID)
+		}
+
+		if w := getRequest(t, mux, "/register?invitation="+url.QueryEscape(token)); w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		w = postPasswordResetForm(t, mux, "/register", newTestSignupForm(token))
+		if got := decodedFlashLevel(t, w); got != "success" {
+			t.Fatalf("want a success flash, got %q: %q", got, decodedFlashMessage(t, w))
+		}
+		if len(mux.userRepo.Users) != 2 {
+			t.Fatalf("want 2 users, got %d", len(mux.userRepo.Users))
+		}
+
+		// the invitation may only be used once
+		if w := getRequest(t, mux, "/register?invitation="+url.QueryEscape(token)); w.Code != http.StatusSeeOther {
+			t.Errorf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		mux := newTestRegistrationMux(t, registration.Config{Mode: registration.ModeInviteOnly}, inviter)
+		mux.registration.Invitations = []registration.Invitation{
+			{UUID: "8b7f2c1e-4d3a-4f5e-9a8b-7c6d5e4f3a2b", CreatedByUUID: inviter.UUID, TokenHash: "hash", MaxUses: 1},
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, newTestTwoFactorAccountRequest(t, http.MethodPost, "/account/invitations/8b7f2c1e-4d3a-4f5e-9a8b-7c6d5e4f3a2b/delete", inviter, url.Values{}))
+
+		if got := decodedFlashLevel(t, w); got != "success" {
+			t.Errorf("want a success flash, got %q", got)
+		}
+		if len(mux.registration.Invitations) != 0 {
+			t.Errorf("want no invitation, got %d", len(mux.registration.Invitations))
+		}
+	})
+}
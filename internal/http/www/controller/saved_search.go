@@ -0,0 +1,523 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// RegisterSavedSearchHandlers registers handlers to manage, run and publish saved searches.
+func RegisterSavedSearchHandlers(
+	r *chi.Mux,
+	publicURL *url.URL,
+	bookmarkQueryingService *bookmarkquerying.Service,
+	feedService *feed.Service,
+	feedQueryingService *feedquerying.Service,
+	savedSearchService *savedsearch.Service,
+	userService *user.Service,
+) {
+	sc := savedSearchController{
+		publicURL: publicURL,
+
+		bookmarkQueryingService: bookmarkQueryingService,
+		feedService:             feedService,
+		feedQueryingService:     feedQueryingService,
+		savedSearchService:      savedSearchService,
+		userService:             userService,
+
+		savedSearchAddView:    view.New("search/saved_search_add.gohtml", "search/saved_search_form.gohtml"),
+		savedSearchDeleteView: view.New("search/saved_search_delete.gohtml"),
+		savedSearchEditView:   view.New("search/saved_search_edit.gohtml", "search/saved_search_form.gohtml"),
+		savedSearchListView:   view.New("search/saved_search_list.gohtml"),
+
+		publicSavedSearchView: view.New("public/saved_search.gohtml", "bookmark/bookmark_row.gohtml"),
+	}
+
+	for _, domain := range savedsearch.AllDomains {
+		r.Route(savedSearchBasePath(domain), func(r chi.Router) {
+			r.Use(func(h http.Handler) http.Handler {
+				return middleware.AuthenticatedUser(h.ServeHTTP)
+			})
+
+			r.Get("/", sc.handleSavedSearchListView(domain))
+			r.Get("/add", sc.handleSavedSearchAddView(domain))
+			r.Post("/add", sc.handleSavedSearchAdd(domain))
+			r.Get("/menu", sc.handleHxSavedSearchMenu(domain))
+			r.Get("/{uuid}/delete", sc.handleSavedSearchDeleteView(domain))
+			r.Post("/{uuid}/delete", sc.handleSavedSearchDelete(domain))
+			r.Get("/{uuid}/edit", sc.handleSavedSearchEditView(domain))
+			r.Post("/{uuid}/edit", sc.handleSavedSearchEdit(domain))
+		})
+	}
+
+	// published bookmark searches
+	r.Get("/u/{nickname}/searches/{slug}", sc.handlePublicSavedSearchView())
+	r.Get("/u/{nickname}/searches/{slug}/feed/atom", sc.handlePublicSavedSearchFeedAtom())
+}
+
+type savedSearchController struct {
+	publicURL *url.URL
+
+	bookmarkQueryingService *bookmarkquerying.Service
+	feedService             *feed.Service
+	feedQueryingService     *feedquerying.Service
+	savedSearchService      *savedsearch.Service
+	userService             *user.Service
+
+	savedSearchAddView    *view.View
+	savedSearchDeleteView *view.View
+	savedSearchEditView   *view.View
+	savedSearchListView   *view.View
+
+	publicSavedSearchView *view.View
+}
+
+type savedSearchForm struct {
+	Name   string `schema:"name"`
+	Query  string `schema:"query"`
+	Public bool   `schema:"public"`
+}
+
+func (f *savedSearchForm) asSavedSearch(userUUID string, domain savedsearch.Domain) savedsearch.SavedSearch {
+	return savedsearch.SavedSearch{
+		UserUUID: userUUID,
+		Domain:   domain,
+		Name:     f.Name,
+		Query:    f.Query,
+		Public:   f.Public,
+	}
+}
+
+// savedSearchCount holds a SavedSearch along with the number of items it currently matches.
+type savedSearchCount struct {
+	savedsearch.SavedSearch
+
+	Count uint
+
+	// Invalid is set when the saved query can no longer be parsed.
+	Invalid bool
+}
+
+type savedSearchListContent struct {
+	Domain   savedsearch.Domain
+	NickName string
+	Searches []savedSearchCount
+}
+
+type savedSearchFormContent struct {
+	Domain      savedsearch.Domain
+	SavedSearch savedsearch.SavedSearch
+}
+
+type publicSavedSearchContent struct {
+	bookmarkquerying.BookmarkPage
+
+	SavedSearch savedsearch.SavedSearch
+}
+
+// savedSearchBasePath returns the path under which saved searches are managed for a given Domain.
+func savedSearchBasePath(domain savedsearch.Domain) string {
+	return fmt.Sprintf("/%s/searches", domain)
+}
+
+// savedSearchByUUID returns a saved search belonging to the current user, for a given Domain.
+func (sc *savedSearchController) savedSearchByUUID(ctx context.Context, userUUID string, domain savedsearch.Domain, searchUUID string) (savedsearch.SavedSearch, error) {
+	s, err := sc.savedSearchService.ByUUID(ctx, userUUID, searchUUID)
+	if err != nil {
+		return savedsearch.SavedSearch{}, err
+	}
+
+	if s.Domain != domain {
+		return savedsearch.SavedSearch{}, savedsearch.ErrNotFound
+	}
+
+	return s, nil
+}
+
+// savedSearchCounts returns the user's saved searches for a given Domain, along with the
+// number of bookmarks or feed entries they currently match.
+func (sc *savedSearchController) savedSearchCounts(ctx context.Context, userUUID string, domain savedsearch.Domain) ([]savedSearchCount, error) {
+	searches, err := sc.savedSearchService.ByDomain(ctx, userUUID, domain)
+	if err != nil {
+		return []savedSearchCount{}, err
+	}
+
+	var countFn func(query string) (uint, error)
+
+	switch domain {
+	case savedsearch.DomainBookmarks:
+		countFn = func(query string) (uint, error) {
+			return sc.bookmarkQueryingService.BookmarkCountBySearchQuery(ctx, userUUID, bookmarkquerying.VisibilityAll, query)
+		}
+
+	case savedsearch.DomainFeeds:
+		preferences, err := sc.feedService.PreferencesByUserUUID(ctx, userUUID)
+		if err != nil {
+			return []savedSearchCount{}, err
+		}
+
+		countFn = func(query string) (uint, error) {
+			return sc.feedQueryingService.EntryCountByQuery(ctx, userUUID, preferences, query)
+		}
+	}
+
+	counts := make([]savedSearchCount, 0, len(searches))
+
+	for _, s := range searches {
+		count, err := countFn(s.Query)
+		if errors.Is(err, search.ErrQueryInvalid) {
+			log.Warn().Err(err).Str("saved_search_uuid", s.UUID).Msg("invalid saved search query")
+			counts = append(counts, savedSearchCount{SavedSearch: s, Invalid: true})
+			continue
+		} else if err != nil {
+			return []savedSearchCount{}, err
+		}
+
+		counts = append(counts, savedSearchCount{SavedSearch: s, Count: count})
+	}
+
+	return counts, nil
+}
+
+// handleSavedSearchAdd processes the saved search creation form.
+func (sc *savedSearchController) handleSavedSearchAdd(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form savedSearchForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse saved search creation form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if _, err := sc.savedSearchService.Add(ctx, form.asSavedSearch(ctxUser.UUID, domain)); err != nil {
+			log.Error().Err(err).Msg("failed to add saved search")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path+"?search="+url.QueryEscape(form.Query), http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, fmt.Sprintf("The search %q has been saved", form.Name))
+		http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+	}
+}
+
+// handleSavedSearchAddView renders the saved search creation form, pre-filled with the
+// search query passed as the "search" URL parameter.
+func (sc *savedSearchController) handleSavedSearchAddView(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewData := view.Data{
+			Title: "Save search",
+			Content: savedSearchFormContent{
+				Domain: domain,
+				SavedSearch: savedsearch.SavedSearch{
+					Query: r.URL.Query().Get("search"),
+				},
+			},
+		}
+
+		sc.savedSearchAddView.Render(w, r, viewData)
+	}
+}
+
+// handleSavedSearchDelete processes the saved search deletion form.
+func (sc *savedSearchController) handleSavedSearchDelete(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if _, err := sc.savedSearchByUUID(ctx, ctxUser.UUID, domain, searchUUID); err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved search")
+			view.PutFlashError(w, "failed to retrieve saved search")
+			http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+			return
+		}
+
+		if err := sc.savedSearchService.Delete(ctx, ctxUser.UUID, searchUUID); err != nil {
+			log.Error().Err(err).Msg("failed to delete saved search")
+			view.PutFlashError(w, "failed to delete saved search")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The saved search has been deleted")
+		http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+	}
+}
+
+// handleSavedSearchDeleteView renders the saved search deletion form.
+func (sc *savedSearchController) handleSavedSearchDeleteView(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		s, err := sc.savedSearchByUUID(ctx, ctxUser.UUID, domain, searchUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved search")
+			view.PutFlashError(w, "failed to retrieve saved search")
+			http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Delete saved search",
+			Content: s,
+		}
+
+		sc.savedSearchDeleteView.Render(w, r, viewData)
+	}
+}
+
+// handleSavedSearchEdit processes the saved search edition form.
+func (sc *savedSearchController) handleSavedSearchEdit(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form savedSearchForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse saved search edition form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if _, err := sc.savedSearchByUUID(ctx, ctxUser.UUID, domain, searchUUID); err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved search")
+			view.PutFlashError(w, "failed to retrieve saved search")
+			http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+			return
+		}
+
+		editedSearch := form.asSavedSearch(ctxUser.UUID, domain)
+		editedSearch.UUID = searchUUID
+
+		if err := sc.savedSearchService.Update(ctx, editedSearch); err != nil {
+			log.Error().Err(err).Msg("failed to update saved search")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The saved search has been updated")
+		http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+	}
+}
+
+// handleSavedSearchEditView renders the saved search edition form.
+func (sc *savedSearchController) handleSavedSearchEditView(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		s, err := sc.savedSearchByUUID(ctx, ctxUser.UUID, domain, searchUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved search")
+			view.PutFlashError(w, "failed to retrieve saved search")
+			http.Redirect(w, r, savedSearchBasePath(domain), http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Edit saved search",
+			Content: savedSearchFormContent{
+				Domain:      domain,
+				SavedSearch: s,
+			},
+		}
+
+		sc.savedSearchEditView.Render(w, r, viewData)
+	}
+}
+
+// handleSavedSearchListView renders the user's saved searches for a given Domain, along with
+// the number of items they currently match.
+func (sc *savedSearchController) handleSavedSearchListView(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		counts, err := sc.savedSearchCounts(ctx, ctxUser.UUID, domain)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved searches")
+			view.PutFlashError(w, "failed to retrieve saved searches")
+			http.Redirect(w, r, "/"+string(domain), http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Saved searches",
+			Content: savedSearchListContent{
+				Domain:   domain,
+				NickName: ctxUser.NickName,
+				Searches: counts,
+			},
+		}
+
+		sc.savedSearchListView.Render(w, r, viewData)
+	}
+}
+
+// handleHxSavedSearchMenu renders the user's saved searches and their match counts as
+// navigation menu items, meant to be loaded into the navbar when opening a dropdown menu.
+func (sc *savedSearchController) handleHxSavedSearchMenu(domain savedsearch.Domain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		counts, err := sc.savedSearchCounts(ctx, ctxUser.UUID, domain)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve saved searches")
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		content := savedSearchListContent{
+			Domain:   domain,
+			NickName: ctxUser.NickName,
+			Searches: counts,
+		}
+
+		if err := sc.savedSearchListView.RenderTemplate(w, "savedSearchMenu", content); err != nil {
+			log.Error().Err(err).Msg("failed to render saved search menu fragment")
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+	}
+}
+
+// handlePublicSavedSearchView renders the bookmarks matching a published saved search.
+func (sc *savedSearchController) handlePublicSavedSearchView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		nickName := chi.URLParam(r, "nickname")
+		searchSlug := chi.URLParam(r, "slug")
+
+		owner, err := sc.userService.ByNickName(ctx, nickName)
+		if err != nil {
+			log.Error().Err(err).Str("nickname", nickName).Msg("failed to retrieve user")
+			view.PutFlashError(w, "unknown user")
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		s, err := sc.savedSearchService.PublicBookmarkSearchBySlug(ctx, owner.UUID, searchSlug)
+		if err != nil {
+			log.Error().Err(err).Str("slug", searchSlug).Msg("failed to retrieve saved search")
+			view.PutFlashError(w, "unknown saved search")
+			http.Redirect(w, r, "/u/"+owner.NickName+"/bookmarks", http.StatusSeeOther)
+			return
+		}
+
+		pageNumber, pageNumberStr, err := paginate.GetPageNumber(r.URL.Query())
+		if err != nil {
+			log.Warn().Err(err).Str("page_number", pageNumberStr).Msg("invalid page number")
+			view.PutFlashError(w, fmt.Sprintf("invalid page number: %q", pageNumberStr))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		bookmarkPage, err := sc.bookmarkQueryingService.PublicBookmarksBySearchQueryAndPage(ctx, owner.UUID, s.Query, pageNumber)
+		if errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			msg := fmt.Sprintf("invalid page number: %d", pageNumber)
+			log.Error().Err(err).Msg(msg)
+			view.PutFlashError(w, msg)
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		} else if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve bookmarks")
+			view.PutFlashError(w, "failed to retrieve bookmarks")
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		// Pagination links point to this page, which runs the saved query.
+		bookmarkPage.SearchTerms = ""
+
+		viewData := view.Data{
+			AtomFeedURL: fmt.Sprintf("/u/%s/searches/%s/feed/atom", owner.NickName, s.Slug),
+			Title:       fmt.Sprintf("%s's bookmarks: %s", owner.DisplayName, s.Name),
+			Content: publicSavedSearchContent{
+				BookmarkPage: bookmarkPage,
+				SavedSearch:  s,
+			},
+		}
+
+		sc.publicSavedSearchView.Render(w, r, viewData)
+	}
+}
+
+// handlePublicSavedSearchFeedAtom renders the Atom feed for a published saved search.
+func (sc *savedSearchController) handlePublicSavedSearchFeedAtom() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		nickName := chi.URLParam(r, "nickname")
+		searchSlug := chi.URLParam(r, "slug")
+
+		owner, err := sc.userService.ByNickName(ctx, nickName)
+		if err != nil {
+			log.Error().Err(err).Str("nickname", nickName).Msg("failed to retrieve user")
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+
+		s, err := sc.savedSearchService.PublicBookmarkSearchBySlug(ctx, owner.UUID, searchSlug)
+		if err != nil {
+			log.Error().Err(err).Str("slug", searchSlug).Msg("failed to retrieve saved search")
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+
+		bookmarkPage, err := sc.bookmarkQueryingService.PublicBookmarksBySearchQueryAndPage(ctx, owner.UUID, s.Query, 1)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve bookmarks")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		atomFeed, err := bookmarksToFeed(sc.publicURL, bookmarkPage.Owner, bookmarkPage.Bookmarks)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to create feed")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		atomFeed.Title = fmt.Sprintf("%s: %s", atomFeed.Title, s.Name)
+		atomFeed.Link.Href = fmt.Sprintf("%s/u/%s/searches/%s", sc.publicURL.String(), owner.NickName, s.Slug)
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.atom", owner.NickName, s.Slug))
+		w.Header().Add("Content-Type", "application/atom+xml")
+
+		if err := atomFeed.WriteAtom(w); err != nil {
+			log.Error().Err(err).Msg("failed to marshal Atom feed")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	}
+}
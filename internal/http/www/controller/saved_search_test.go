@@ -0,0 +1,263 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// newTestSavedSearchController wires a savedSearchController against a saved search fake
+// repository, and a bookmark querying fake repository seeded with the given bookmarks,
+// owned by testBookmarkCtxUser.
+func newTestSavedSearchController(savedSearchRepo *savedsearch.FakeRepository, bookmarks []bookmark.Bookmark) savedSearchController {
+	queryingRepo := &bookmarkquerying.FakeRepository{
+		Bookmarks: bookmarks,
+		Users:     []user.User{testBookmarkCtxUser},
+	}
+	userRepo := &user.FakeRepository{
+		Users: []user.User{testBookmarkCtxUser},
+	}
+
+	return savedSearchController{
+		publicURL: &url.URL{Scheme: "https", Host: "sparklemuffin.example.org"},
+
+		bookmarkQueryingService: bookmarkquerying.NewService(queryingRepo),
+		savedSearchService:      savedsearch.NewService(savedSearchRepo),
+		userService:             user.NewService(userRepo, nil),
+
+		savedSearchAddView:  view.New("search/saved_search_add.gohtml", "search/saved_search_form.gohtml"),
+		savedSearchListView: view.New("search/saved_search_list.gohtml"),
+
+		publicSavedSearchView: view.New("public/saved_search.gohtml", "bookmark/bookmark_row.gohtml"),
+	}
+}
+
+// newSavedSearchRequest builds a request with the given user set in context, and the
+// optional form encoded in its body.
+func newSavedSearchRequest(t *testing.T, method string, target string, ctxUser user.User, form url.Values) *http.Request {
+	t.Helper()
+
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	}
+
+	ctx := httpcontext.WithUser(r.Context(), ctxUser)
+	return r.WithContext(ctx)
+}
+
+func TestHandleSavedSearchAdd(t *testing.T) {
+	t.Run("saves the search and redirects to the list", func(t *testing.T) {
+		savedSearchRepo := &savedsearch.FakeRepository{}
+		sc := newTestSavedSearchController(savedSearchRepo, []bookmark.Bookmark{})
+
+		form := url.Values{}
+		form.Set("name", "Go testing")
+		form.Set("query", "tag:go testing")
+
+		r := newSavedSearchRequest(t, http.MethodPost, "/bookmarks/searches/add", testBookmarkCtxUser, form)
+		w := httptest.NewRecorder()
+
+		sc.handleSavedSearchAdd(savedsearch.DomainBookmarks)(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d, body:\n%s", http.StatusSeeOther, w.Code, w.Body.String())
+		}
+		if want := "/bookmarks/searches"; w.Header().Get("Location") != want {
+			t.Errorf("want redirect to %q, got %q", want, w.Header().Get("Location"))
+		}
+		if len(savedSearchRepo.SavedSearches) != 1 {
+			t.Fatalf("want 1 saved search, got %d", len(savedSearchRepo.SavedSearches))
+		}
+
+		got := savedSearchRepo.SavedSearches[0]
+		if got.UserUUID != testBookmarkCtxUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testBookmarkCtxUser.UUID, got.UserUUID)
+		}
+		if got.Slug != "go-testing" {
+			t.Errorf("want slug %q, got %q", "go-testing", got.Slug)
+		}
+	})
+
+	t.Run("invalid query flashes a user-friendly message", func(t *testing.T) {
+		savedSearchRepo := &savedsearch.FakeRepository{}
+		sc := newTestSavedSearchController(savedSearchRepo, []bookmark.Bookmark{})
+
+		form := url.Values{}
+		form.Set("name", "Unread")
+		form.Set("query", "is:unread")
+
+		r := newSavedSearchRequest(t, http.MethodPost, "/bookmarks/searches/add", testBookmarkCtxUser, form)
+		w := httptest.NewRecorder()
+
+		sc.handleSavedSearchAdd(savedsearch.DomainBookmarks)(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if want := "/bookmarks/searches/add?search=is%3Aunread"; w.Header().Get("Location") != want {
+			t.Errorf("want redirect to %q, got %q", want, w.Header().Get("Location"))
+		}
+		if got := decodedFlashMessage(t, w); got != "Error: Invalid search query: is:unread cannot be used in this search." {
+			t.Errorf("want a user-friendly message, got %q", got)
+		}
+		if len(savedSearchRepo.SavedSearches) != 0 {
+			t.Errorf("want no saved search, got %d", len(savedSearchRepo.SavedSearches))
+		}
+	})
+}
+
+func TestHandleHxSavedSearchMenu(t *testing.T) {
+	bookmarks := []bookmark.Bookmark{
+		{UID: "bookmark-1", UserUUID: testBookmarkCtxUser.UUID, URL: "https://go.dev/doc", Title: "Go docs", Tags: []string{"go"}},
+		{UID: "bookmark-2", UserUUID: testBookmarkCtxUser.UUID, URL: "https://go.dev/blog", Title: "Go blog", Tags: []string{"go", "blog"}},
+		{UID: "bookmark-3", UserUUID: testBookmarkCtxUser.UUID, URL: "https://example.com", Title: "Example", Tags: []string{"example"}},
+	}
+	savedSearchRepo := &savedsearch.FakeRepository{
+		SavedSearches: []savedsearch.SavedSearch{
+			{
+				UUID:     "0f6e4a55-4d0e-4c43-9a4b-6c0f2f1a5b01",
+				UserUUID: testBookmarkCtxUser.UUID,
+				Domain:   savedsearch.DomainBookmarks,
+				Name:     "Go",
+				Slug:     "go",
+				Query:    "tag:go",
+			},
+			{
+				UUID:     "0f6e4a55-4d0e-4c43-9a4b-6c0f2f1a5b02",
+				UserUUID: testBookmarkCtxUser.UUID,
+				Domain:   savedsearch.DomainBookmarks,
+				Name:     "Broken",
+				Slug:     "broken",
+				Query:    `tag:go "unterminated`,
+			},
+			{
+				UUID:     "0f6e4a55-4d0e-4c43-9a4b-6c0f2f1a5b03",
+				UserUUID: testBookmarkCtxUser.UUID,
+				Domain:   savedsearch.DomainFeeds,
+				Name:     "Unread",
+				Slug:     "unread",
+				Query:    "is:unread",
+			},
+		},
+	}
+	sc := newTestSavedSearchController(savedSearchRepo, bookmarks)
+
+	r := newSavedSearchRequest(t, http.MethodGet, "/bookmarks/searches/menu", testBookmarkCtxUser, nil)
+	w := httptest.NewRecorder()
+
+	sc.handleHxSavedSearchMenu(savedsearch.DomainBookmarks)(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+	if strings.Contains(body, "<html") {
+		t.Errorf("want a fragment without the page layout, got:\n%s", body)
+	}
+	if !strings.Contains(body, `href="/bookmarks?search=tag%3ago"`) {
+		t.Errorf("want a link running the saved search, got:\n%s", body)
+	}
+	if !strings.Contains(body, `text-bg-secondary">2</span>`) {
+		t.Errorf("want the match count for the saved search, got:\n%s", body)
+	}
+	if !strings.Contains(body, `text-bg-warning">invalid</span>`) {
+		t.Errorf("want the invalid saved search to be flagged, got:\n%s", body)
+	}
+	if strings.Contains(body, "Unread") {
+		t.Errorf("want saved searches of other domains to be excluded, got:\n%s", body)
+	}
+}
+
+func TestHandlePublicSavedSearchView(t *testing.T) {
+	bookmarks := []bookmark.Bookmark{
+		{UID: "bookmark-1", UserUUID: testBookmarkCtxUser.UUID, URL: "https://go.dev/doc", Title: "Go docs", Tags: []string{"go"}},
+		{UID: "bookmark-2", UserUUID: testBookmarkCtxUser.UUID, URL: "https://go.dev/secret", Title: "Private Go notes", Tags: []string{"go"}, Private: true},
+	}
+	publicSearch := savedsearch.SavedSearch{
+		UUID:     "0f6e4a55-4d0e-4c43-9a4b-6c0f2f1a5b01",
+		UserUUID: testBookmarkCtxUser.UUID,
+		Domain:   savedsearch.DomainBookmarks,
+		Name:     "Go",
+		Slug:     "go",
+		Query:    "tag:go",
+		Public:   true,
+	}
+	privateSearch := savedsearch.SavedSearch{
+		UUID:     "0f6e4a55-4d0e-4c43-9a4b-6c0f2f1a5b02",
+		UserUUID: testBookmarkCtxUser.UUID,
+		Domain:   savedsearch.DomainBookmarks,
+		Name:     "Private",
+		Slug:     "private",
+		Query:    "tag:go",
+	}
+
+	newRequest := func(t *testing.T, searchSlug string) *http.Request {
+		t.Helper()
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/u/alice/searches/"+searchSlug, nil)
+		r = withURLParam(r, "nickname", testBookmarkCtxUser.NickName)
+
+		routeCtx := chi.RouteContext(r.Context())
+		routeCtx.URLParams.Add("slug", searchSlug)
+
+		return r
+	}
+
+	t.Run("lists the public bookmarks matching a published search", func(t *testing.T) {
+		savedSearchRepo := &savedsearch.FakeRepository{
+			SavedSearches: []savedsearch.SavedSearch{publicSearch, privateSearch},
+		}
+		sc := newTestSavedSearchController(savedSearchRepo, bookmarks)
+
+		w := httptest.NewRecorder()
+		sc.handlePublicSavedSearchView()(w, newRequest(t, publicSearch.Slug))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, "Go docs") {
+			t.Errorf("want the public bookmark to be listed, got:\n%s", body)
+		}
+		if strings.Contains(body, "Private Go notes") {
+			t.Errorf("want the private bookmark to be hidden, got:\n%s", body)
+		}
+	})
+
+	t.Run("unpublished search redirects to the user's public bookmarks", func(t *testing.T) {
+		savedSearchRepo := &savedsearch.FakeRepository{
+			SavedSearches: []savedsearch.SavedSearch{publicSearch, privateSearch},
+		}
+		sc := newTestSavedSearchController(savedSearchRepo, bookmarks)
+
+		w := httptest.NewRecorder()
+		sc.handlePublicSavedSearchView()(w, newRequest(t, privateSearch.Slug))
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if want := "/u/alice/bookmarks"; w.Header().Get("Location") != want {
+			t.Errorf("want redirect to %q, got %q", want, w.Header().Get("Location"))
+		}
+	})
+}
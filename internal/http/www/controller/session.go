@@ -4,8 +4,9 @@
 package controller
 
 import (
-	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,43 +16,142 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
 	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 const (
 	UserRememberTokenNBytes     int    = 32
 	UserRememberTokenCookieName string = "remember_me"
+
+	// UserLoginChallengeCookieName is the name of the cookie identifying a user who has
+	// entered their password, and must now provide their second factor.
+	UserLoginChallengeCookieName string = "login_challenge"
+
+	loginChallengePath           string = "/login/two-factor"
+	missingLoginChallengeRateKey string = "missing-login-challenge"
 )
 
 // RegisterSessionHandlers registers handlers for user session management..
+//
+// Failed login attempts are only throttled if lockoutService is not nil.
+//
+// Passkey login handlers are only registered if passkeyService is not nil.
+//
+// Password reset handlers are only registered if passwordResetService is not nil.
+//
+// Registration and invitation handlers are only registered if registrationService is not nil.
+//
+// Single sign-on handlers are only registered if ssoService is not nil.
 func RegisterSessionHandlers(
 	r *chi.Mux,
+	publicURL *url.URL,
 	secure bool,
+	auditService *audit.Service,
+	lockoutService *lockout.Service,
+	passkeyService *passkey.Service,
+	passwordResetService *passwordreset.Service,
+	registrationService *registration.Service,
 	sessionService *session.Service,
+	ssoService *sso.Service,
+	twoFactorService *twofactor.Service,
 	userService *user.Service,
 ) {
 	sc := sessionController{
-		secure:         secure,
-		sessionService: sessionService,
-		userService:    userService,
+		secure:               secure,
+		passwordResetEnabled: passwordResetService != nil,
+		registrationOpen:     registrationService != nil && registrationService.Enabled() && !registrationService.InvitationsEnabled(),
+		auditService:         auditService,
+		lockoutService:       lockoutService,
+		passkeyService:       passkeyService,
+		sessionService:       sessionService,
+		ssoService:           ssoService,
+		twoFactorService:     twoFactorService,
+		userService:          userService,
 
-		userLoginView: view.New("session/login.gohtml"),
+		userLoginView:          view.New("session/login.gohtml"),
+		userLoginTwoFactorView: view.New("session/login_two_factor.gohtml"),
 	}
 
 	// authentication
-	r.Get("/login", sc.userLoginView.Handle)
-	r.With(middleware.RateLimitLogin).Post("/login", sc.handleUserLogin())
+	r.Get("/login", sc.handleUserLoginView())
+	r.With(middleware.RateLimitLogin(sc.onLoginRateLimitExceeded)).Post("/login", sc.handleUserLogin())
+	r.Get(loginChallengePath, sc.handleUserLoginTwoFactorView())
+	r.With(middleware.RateLimitSecondFactor(sc.loginChallengeKeyFunc)).Post(loginChallengePath, sc.handleUserLoginTwoFactor())
 	r.Post("/logout", sc.handleUserLogout())
+
+	if passkeyService != nil {
+		r.With(middleware.RateLimitPasskeyLogin).Post(passkeyLoginPath+"/begin", sc.handleUserLoginPasskeyBegin())
+		r.With(middleware.RateLimitPasskeyLogin).Post(passkeyLoginPath+"/finish", sc.handleUserLoginPasskeyFinish())
+	}
+
+	if ssoService != nil {
+		r.With(middleware.RateLimitSSOLogin).Get(ssoLoginPath, sc.handleUserLoginSSO())
+		r.With(middleware.RateLimitSSOLogin).Get(ssoCallbackPath, sc.handleUserLoginSSOCallback())
+	}
+
+	if passwordResetService != nil {
+		registerPasswordResetHandlers(r, publicURL, auditService, passwordResetService, sessionService)
+	}
+
+	if registrationService != nil {
+		registerRegistrationHandlers(r, publicURL, auditService, registrationService)
+	}
 }
 
 type sessionController struct {
-	secure bool
+	secure               bool
+	passwordResetEnabled bool
+	registrationOpen     bool
 
-	sessionService *session.Service
-	userService    *user.Service
+	auditService     *audit.Service
+	lockoutService   *lockout.Service
+	passkeyService   *passkey.Service
+	sessionService   *session.Service
+	ssoService       *sso.Service
+	twoFactorService *twofactor.Service
+	userService      *user.Service
 
-	userLoginView *view.View
+	userLoginView          *view.View
+	userLoginTwoFactorView *view.View
+}
+
+// handleUserLoginView renders the user login form.
+func (sc *sessionController) handleUserLoginView() func(w http.ResponseWriter, r *http.Request) {
+	type loginViewContent struct {
+		PasskeyEnabled       bool
+		PasswordResetEnabled bool
+		RegistrationOpen     bool
+		SSOEnabled           bool
+		SSOProviderName      string
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		content := loginViewContent{
+			PasskeyEnabled:       sc.passkeyService != nil,
+			PasswordResetEnabled: sc.passwordResetEnabled,
+			RegistrationOpen:     sc.registrationOpen,
+			SSOEnabled:           sc.ssoService != nil,
+		}
+
+		if sc.ssoService != nil {
+			content.SSOProviderName = sc.ssoService.ProviderName()
+		}
+
+		viewData := view.Data{
+			Title:   "Login",
+			Content: content,
+		}
+
+		sc.userLoginView.Render(w, r, viewData)
+	}
 }
 
 // handleUserLogin processes data submitted through the user login form.
@@ -72,6 +172,17 @@ func (sc *sessionController) handleUserLogin() func(w http.ResponseWriter, r *ht
 			return
 		}
 
+		if throttle := sc.loginThrottle(r, form.Email); throttle.Active(time.Now().UTC()) {
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginThrottled,
+				TargetUUID: sc.userUUIDByEmail(r, form.Email),
+				Details:    fmt.Sprintf("%s: %s", throttle.Reason, form.Email),
+			})
+			view.PutFlashError(w, loginThrottleMessage(throttle))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
 		authenticatedUser, err := sc.userService.Authenticate(ctx, form.Email, form.Password)
 		if err != nil {
 			log.Error().
@@ -79,18 +190,238 @@ func (sc *sessionController) handleUserLogin() func(w http.ResponseWriter, r *ht
 				Str("client_ip", chimiddleware.GetClientIP(ctx)).
 				Str("email", form.Email).
 				Msg("failed to authenticate user")
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginFailed,
+				TargetUUID: sc.userUUIDByEmail(r, form.Email),
+				Details:    "password: " + form.Email,
+			})
+			sc.recordLoginFailure(r, form.Email)
 			view.PutFlashError(w, "invalid email or password")
 			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 			return
 		}
 
-		if err := sc.setUserRememberToken(ctx, w, authenticatedUser.UUID); err != nil {
+		twoFactorEnabled, err := sc.twoFactorService.IsEnabled(ctx, authenticatedUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve two-factor authentication status")
+			view.PutFlashError(w, "There was an error logging you in")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if twoFactorEnabled {
+			if err := sc.setUserLoginChallenge(w, authenticatedUser.UUID); err != nil {
+				log.Error().Err(err).Msg("failed to set login challenge")
+				view.PutFlashError(w, "There was an error logging you in")
+				http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+				return
+			}
+
+			http.Redirect(w, r, loginChallengePath, http.StatusSeeOther)
+			return
+		}
+
+		if err := sc.setUserRememberToken(w, r, authenticatedUser.UUID); err != nil {
 			log.Error().Err(err).Msg("failed to set remember token")
 			view.PutFlashError(w, "failed to save session cookie")
 			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
 			return
 		}
 
+		sc.resetLoginFailures(w, r, authenticatedUser)
+		sc.recordLoginSucceeded(r, authenticatedUser.UUID, "password")
+
+		http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+	}
+}
+
+// handleUserLoginPasskeyBegin starts a passkey login ceremony, and returns the options to pass
+// to the browser's WebAuthn API.
+func (sc *sessionController) handleUserLoginPasskeyBegin() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if throttle := sc.clientIPThrottle(r); throttle.Active(time.Now().UTC()) {
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:    audit.EventLoginThrottled,
+				Details: fmt.Sprintf("%s: passkey", throttle.Reason),
+			})
+			http.Error(w, loginThrottleMessage(throttle), http.StatusTooManyRequests)
+			return
+		}
+
+		assertion, token, err := sc.passkeyService.BeginLogin(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to begin passkey login")
+			http.Error(w, "There was an error logging you in", http.StatusInternalServerError)
+			return
+		}
+
+		setPasskeyCeremony(w, passkeyLoginPath, token, sc.secure)
+		writePasskeyOptions(w, assertion)
+	}
+}
+
+// handleUserLoginPasskeyFinish verifies the authenticator's response to a passkey login
+// ceremony, and logs the user in.
+//
+// Passkeys require user verification, and thus count as both authentication factors: users
+// who have enabled two-factor authentication are not asked for a code.
+//
+// The user is only known once the response is verified, hence the account throttling is
+// checked afterwards.
+func (sc *sessionController) handleUserLoginPasskeyFinish() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		token := passkeyCeremonyToken(r)
+		clearPasskeyCeremony(w, passkeyLoginPath, sc.secure)
+
+		if throttle := sc.clientIPThrottle(r); throttle.Active(time.Now().UTC()) {
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:    audit.EventLoginThrottled,
+				Details: fmt.Sprintf("%s: passkey", throttle.Reason),
+			})
+			http.Error(w, loginThrottleMessage(throttle), http.StatusTooManyRequests)
+			return
+		}
+
+		authenticatedUser, err := sc.passkeyService.FinishLogin(ctx, token, http.MaxBytesReader(w, r.Body, passkeyResponseMaxBytes))
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("client_ip", chimiddleware.GetClientIP(ctx)).
+				Msg("failed to authenticate user with a passkey")
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginFailed,
+				TargetUUID: authenticatedUser.UUID,
+				Details:    "passkey",
+			})
+			if authenticatedUser.Email != "" {
+				sc.recordLoginFailure(r, authenticatedUser.Email)
+			} else {
+				sc.recordClientIPLoginFailure(r)
+			}
+			http.Error(w, userFacingError(err), http.StatusUnauthorized)
+			return
+		}
+
+		if throttle := sc.loginThrottle(r, authenticatedUser.Email); throttle.Active(time.Now().UTC()) {
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginThrottled,
+				TargetUUID: authenticatedUser.UUID,
+				Details:    fmt.Sprintf("%s: %s", throttle.Reason, authenticatedUser.Email),
+			})
+			http.Error(w, loginThrottleMessage(throttle), http.StatusTooManyRequests)
+			return
+		}
+
+		if err := sc.setUserRememberToken(w, r, authenticatedUser.UUID); err != nil {
+			log.Error().Err(err).Msg("failed to set remember token")
+			http.Error(w, "failed to save session cookie", http.StatusInternalServerError)
+			return
+		}
+
+		sc.resetLoginFailures(w, r, authenticatedUser)
+		sc.recordLoginSucceeded(r, authenticatedUser.UUID, "passkey")
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleUserLoginTwoFactorView renders the second step of the login form, for users who have
+// enabled two-factor authentication.
+func (sc *sessionController) handleUserLoginTwoFactorView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := sc.loginChallengeUserUUID(r); err != nil {
+			view.PutFlashError(w, "Your login attempt has expired; please log in again")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Two-factor authentication",
+		}
+
+		sc.userLoginTwoFactorView.Render(w, r, viewData)
+	}
+}
+
+// handleUserLoginTwoFactor processes the authentication code submitted through the second
+// step of the login form.
+//
+// Invalid codes count as failed login attempts, so that the second factor is subject to the
+// same throttling and lockout as the password. Previous failures are only reset once the user
+// is logged in.
+func (sc *sessionController) handleUserLoginTwoFactor() func(w http.ResponseWriter, r *http.Request) {
+	type loginTwoFactorForm struct {
+		Code string `schema:"code"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userUUID, err := sc.loginChallengeUserUUID(r)
+		if err != nil {
+			view.PutFlashError(w, "Your login attempt has expired; please log in again")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		var form loginTwoFactorForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse two-factor login form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		u, err := sc.userService.ByUUID(ctx, userUUID)
+		if err != nil {
+			log.Error().Err(err).Str("user_uuid", userUUID).Msg("failed to retrieve user")
+			view.PutFlashError(w, "There was an error logging you in")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		if throttle := sc.loginThrottle(r, u.Email); throttle.Active(time.Now().UTC()) {
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginThrottled,
+				TargetUUID: userUUID,
+				Details:    fmt.Sprintf("%s: %s", throttle.Reason, u.Email),
+			})
+			view.PutFlashError(w, loginThrottleMessage(throttle))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		if err := sc.twoFactorService.Verify(ctx, userUUID, form.Code); err != nil {
+			log.Error().
+				Err(err).
+				Str("client_ip", chimiddleware.GetClientIP(ctx)).
+				Str("user_uuid", userUUID).
+				Msg("failed to verify second factor")
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:       audit.EventLoginFailed,
+				TargetUUID: userUUID,
+				Details:    "two-factor authentication code",
+			})
+			sc.recordLoginFailure(r, u.Email)
+			view.PutFlashError(w, "invalid authentication code")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		sc.clearUserLoginChallenge(w)
+
+		if err := sc.setUserRememberToken(w, r, userUUID); err != nil {
+			log.Error().Err(err).Msg("failed to set remember token")
+			view.PutFlashError(w, "failed to save session cookie")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		sc.resetLoginFailures(w, r, u)
+		sc.recordLoginSucceeded(r, userUUID, "two-factor authentication code")
+
 		http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
 	}
 }
@@ -120,9 +451,232 @@ func (sc *sessionController) handleUserLogout() func(w http.ResponseWriter, r *h
 	}
 }
 
+// setUserLoginChallenge sets a short-lived cookie identifying a user who has entered their
+// password, and must now provide their second factor.
+func (sc *sessionController) setUserLoginChallenge(w http.ResponseWriter, userUUID string) error {
+	challenge, err := sc.twoFactorService.IssueChallenge(userUUID)
+	if err != nil {
+		return err
+	}
+
+	cookie := http.Cookie{
+		Name:     UserLoginChallengeCookieName,
+		Value:    challenge,
+		Expires:  time.Now().UTC().Add(twofactor.ChallengeTTL),
+		Path:     loginChallengePath,
+		HttpOnly: true,
+		Secure:   sc.secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, &cookie)
+
+	return nil
+}
+
+// clearUserLoginChallenge clears the login challenge cookie.
+func (sc *sessionController) clearUserLoginChallenge(w http.ResponseWriter) {
+	cookie := http.Cookie{
+		Name:     UserLoginChallengeCookieName,
+		Value:    "",
+		Path:     loginChallengePath,
+		Expires:  time.Unix(0, 1),
+		HttpOnly: true,
+		Secure:   sc.secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, &cookie)
+}
+
+// loginChallengeUserUUID returns the UUID of the user identified by a valid login challenge cookie.
+func (sc *sessionController) loginChallengeUserUUID(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(UserLoginChallengeCookieName)
+	if err != nil {
+		return "", err
+	}
+
+	return sc.twoFactorService.ChallengeUserUUID(cookie.Value)
+}
+
+// loginChallengeKeyFunc identifies the user account targeted by a second factor login attempt,
+// for rate limiting purposes.
+func (sc *sessionController) loginChallengeKeyFunc(r *http.Request) (string, error) {
+	userUUID, err := sc.loginChallengeUserUUID(r)
+	if err != nil {
+		return missingLoginChallengeRateKey, nil // nolint: nilerr
+	}
+
+	return userUUID, nil
+}
+
+// onLoginRateLimitExceeded records login attempts rejected by the rate limiter.
+func (sc *sessionController) onLoginRateLimitExceeded(r *http.Request, email string) {
+	recordAuditEvent(r, sc.auditService, audit.Event{
+		Type:       audit.EventLoginRateLimited,
+		TargetUUID: sc.userUUIDByEmail(r, email),
+		Details:    email,
+	})
+}
+
+// loginThrottle returns the Throttle in effect for login attempts with a given email address,
+// from the client's IP address.
+//
+// Login attempts are not throttled if the lockout service is disabled or unavailable.
+func (sc *sessionController) loginThrottle(r *http.Request, email string) lockout.Throttle {
+	if sc.lockoutService == nil {
+		return lockout.Throttle{}
+	}
+
+	throttle, err := sc.lockoutService.Check(r.Context(), email, chimiddleware.GetClientIP(r.Context()))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check login throttling")
+		return lockout.Throttle{}
+	}
+
+	return throttle
+}
+
+// clientIPThrottle returns the Throttle in effect for login attempts from the client's IP
+// address, for login methods where the user is not known beforehand.
+//
+// Login attempts are not throttled if the lockout service is disabled or unavailable.
+func (sc *sessionController) clientIPThrottle(r *http.Request) lockout.Throttle {
+	if sc.lockoutService == nil {
+		return lockout.Throttle{}
+	}
+
+	throttle, err := sc.lockoutService.CheckClientIP(r.Context(), chimiddleware.GetClientIP(r.Context()))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check login throttling")
+		return lockout.Throttle{}
+	}
+
+	return throttle
+}
+
+// recordClientIPLoginFailure records a failed login attempt that cannot be attributed to an
+// account, e.g. with an unknown passkey.
+func (sc *sessionController) recordClientIPLoginFailure(r *http.Request) {
+	if sc.lockoutService == nil {
+		return
+	}
+
+	clientIP := chimiddleware.GetClientIP(r.Context())
+	if clientIP == "" {
+		return
+	}
+
+	if err := sc.lockoutService.RecordClientIPFailure(r.Context(), clientIP); err != nil {
+		log.Error().Err(err).Msg("failed to record login failure")
+	}
+}
+
+// recordLoginFailure records a failed password, passkey or second factor login attempt, and the
+// resulting account lock, if any.
+func (sc *sessionController) recordLoginFailure(r *http.Request, email string) {
+	if sc.lockoutService == nil {
+		return
+	}
+
+	throttle, err := sc.lockoutService.RecordFailure(r.Context(), email, chimiddleware.GetClientIP(r.Context()))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to record login failure")
+		return
+	}
+
+	if throttle.Reason == lockout.ReasonAccountLocked {
+		recordAuditEvent(r, sc.auditService, audit.Event{
+			Type:       audit.EventAccountLocked,
+			TargetUUID: sc.userUUIDByEmail(r, email),
+			Details:    fmt.Sprintf("%s until %s", email, throttle.Until.Format(time.RFC3339)),
+		})
+	}
+}
+
+// resetLoginFailures clears the failed login attempts recorded for a user who has logged in,
+// and warns them about these attempts.
+func (sc *sessionController) resetLoginFailures(w http.ResponseWriter, r *http.Request, u user.User) {
+	if sc.lockoutService == nil {
+		return
+	}
+
+	summary, err := sc.lockoutService.Reset(r.Context(), u.Email)
+	if err != nil {
+		log.Error().Err(err).Str("user_uuid", u.UUID).Msg("failed to reset login failures")
+		return
+	}
+
+	if summary.Count == 0 {
+		return
+	}
+
+	view.PutFlashWarning(
+		w,
+		fmt.Sprintf(
+			"There were %d failed login attempt(s) on your account since your last login; the last one came from %s on %s",
+			summary.Count,
+			summary.LastClientIP,
+			summary.LastFailureAt.Format("2006-01-02 15:04 MST"),
+		),
+	)
+}
+
+// loginThrottleMessage returns the message displayed to users whose login attempt is throttled.
+func loginThrottleMessage(throttle lockout.Throttle) string {
+	retryAfter := throttle.RetryAfter(time.Now().UTC())
+
+	switch throttle.Reason {
+	case lockout.ReasonAccountLocked:
+		return fmt.Sprintf(
+			"This account is temporarily locked after too many failed login attempts; please try again in %s",
+			retryAfter,
+		)
+
+	case lockout.ReasonClientIPBlocked:
+		return fmt.Sprintf(
+			"Too many failed login attempts from your network; please try again in %s",
+			retryAfter,
+		)
+	}
+
+	return fmt.Sprintf("Too many failed login attempts; please wait %s before trying again", retryAfter)
+}
+
+// recordLoginSucceeded records a successful login, for a given authentication method.
+func (sc *sessionController) recordLoginSucceeded(r *http.Request, userUUID string, method string) {
+	recordAuditEvent(r, sc.auditService, audit.Event{
+		Type:       audit.EventLoginSucceeded,
+		ActorUUID:  userUUID,
+		TargetUUID: userUUID,
+		Details:    method,
+	})
+}
+
+// userUUIDByEmail returns the UUID of the user registered with a given email address, so that
+// failed login attempts appear in the targeted user's history.
+//
+// It returns an empty string if no user is registered with this address.
+func (sc *sessionController) userUUIDByEmail(r *http.Request, email string) string {
+	if sc.auditService == nil {
+		return ""
+	}
+
+	u, err := sc.userService.ByEmail(r.Context(), email)
+	if err != nil {
+		return ""
+	}
+
+	return u.UUID
+}
+
 // setUserRememberToken creates and persists a new RememberToken if needed, and
 // sets it as a session cookie.
-func (sc *sessionController) setUserRememberToken(ctx context.Context, w http.ResponseWriter, userUUID string) error {
+//
+// The session records the client IP address and user agent of the login request.
+func (sc *sessionController) setUserRememberToken(w http.ResponseWriter, r *http.Request, userUUID string) error {
+	ctx := r.Context()
+
 	token, err := rand.RandomBase64URLString(UserRememberTokenNBytes)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to generate a remember token")
@@ -136,6 +690,8 @@ func (sc *sessionController) setUserRememberToken(ctx context.Context, w http.Re
 		UserUUID:               userUUID,
 		RememberToken:          token,
 		RememberTokenExpiresAt: expiresAt,
+		ClientIP:               chimiddleware.GetClientIP(ctx),
+		UserAgent:              r.UserAgent(),
 	}
 
 	if err = sc.sessionService.Add(ctx, userSession); err != nil {
@@ -4,13 +4,26 @@
 package controller
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 
 	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/test/webauthntest"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 func TestSetUserRememberToken_CookieAttributes(t *testing.T) {
@@ -35,7 +48,7 @@ func TestSetUserRememberToken_CookieAttributes(t *testing.T) {
 			r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/login", nil)
 			w := httptest.NewRecorder()
 
-			if err := sc.setUserRememberToken(r.Context(), w, testCtxUser.UUID); err != nil {
+			if err := sc.setUserRememberToken(w, r, testCtxUser.UUID); err != nil {
 				t.Fatalf("failed to set remember token: %q", err)
 			}
 
@@ -144,3 +157,527 @@ func TestHandleUserLogout(t *testing.T) {
 		}
 	})
 }
+
+func newTestTwoFactorLoginMux(t *testing.T, u user.User, twoFactorRepo *twofactor.FakeRepository) *chi.Mux {
+	t.Helper()
+
+	sessionService, err := session.NewService(&session.FakeRepository{}, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := chi.NewMux()
+	RegisterSessionHandlers(
+		mux,
+		nil,
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		sessionService,
+		nil,
+		newTestTwoFactorService(t, twoFactorRepo),
+		user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t)),
+	)
+
+	return mux
+}
+
+func postForm(t *testing.T, h http.Handler, target string, form url.Values, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, target, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+func TestHandleUserLogin_TwoFactor(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	loginForm := url.Values{
+		"email":    {u.Email},
+		"password": {testTwoFactorPassword},
+	}
+
+	t.Run("two-factor authentication disabled", func(t *testing.T) {
+		mux := newTestTwoFactorLoginMux(t, u, &twofactor.FakeRepository{})
+
+		w := postForm(t, mux, "/login", loginForm)
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Errorf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) == nil {
+			t.Error("want a remember token cookie to be set")
+		}
+	})
+
+	t.Run("two-factor authentication enabled", func(t *testing.T) {
+		twoFactorRepo := &twofactor.FakeRepository{
+			TOTPs: []twofactor.TOTP{
+				{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true},
+			},
+		}
+		mux := newTestTwoFactorLoginMux(t, u, twoFactorRepo)
+
+		w := postForm(t, mux, "/login", loginForm)
+
+		if got := w.Header().Get("Location"); got != "/login/two-factor" {
+			t.Fatalf("want redirect to %q, got %q", "/login/two-factor", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Fatal("want no remember token cookie before the second factor is verified")
+		}
+
+		challengeCookie := responseCookie(w, UserLoginChallengeCookieName)
+		if challengeCookie == nil {
+			t.Fatal("want a login challenge cookie to be set")
+		}
+		if !challengeCookie.HttpOnly || !challengeCookie.Secure {
+			t.Errorf("want HttpOnly and Secure login challenge cookie, got %+v", challengeCookie)
+		}
+
+		// invalid code
+		w = postForm(t, mux, "/login/two-factor", url.Values{"code": {"000000"}}, challengeCookie)
+
+		if got := w.Header().Get("Location"); got != "/login/two-factor" {
+			t.Errorf("want redirect to %q, got %q", "/login/two-factor", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no remember token cookie for an invalid code")
+		}
+
+		// missing challenge
+		w = postForm(t, mux, "/login/two-factor", url.Values{"code": {testTwoFactorCode(t)}})
+
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Errorf("want redirect to %q, got %q", "/login", got)
+		}
+
+		// valid code
+		w = postForm(t, mux, "/login/two-factor", url.Values{"code": {testTwoFactorCode(t)}}, challengeCookie)
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Errorf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) == nil {
+			t.Error("want a remember token cookie to be set")
+		}
+	})
+}
+
+func TestHandleUserLogin_Lockout(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+
+	newSessionController := func(t *testing.T, lockoutRepo *lockout.FakeRepository, totps ...twofactor.TOTP) sessionController {
+		t.Helper()
+
+		sessionService, err := session.NewService(&session.FakeRepository{}, "hmac-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userService := user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t))
+
+		return sessionController{
+			lockoutService:   lockout.NewService(lockoutRepo, userService, nil),
+			sessionService:   sessionService,
+			twoFactorService: newTestTwoFactorService(t, &twofactor.FakeRepository{TOTPs: totps}),
+			userService:      userService,
+		}
+	}
+
+	login := func(t *testing.T, sc sessionController, password string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		form := url.Values{"email": {u.Email}, "password": {password}}
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		w := httptest.NewRecorder()
+		sc.handleUserLogin()(w, r)
+
+		return w
+	}
+
+	newFailures := func(n uint, last time.Time) []lockout.Failure {
+		failures := make([]lockout.Failure, n)
+		for i := range failures {
+			failures[i] = lockout.Failure{Email: u.Email, ClientIP: "192.0.2.10", CreatedAt: last}
+		}
+		return failures
+	}
+
+	t.Run("delayed after repeated failures", func(t *testing.T) {
+		lockoutRepo := &lockout.FakeRepository{}
+		sc := newSessionController(t, lockoutRepo)
+
+		for range lockout.AccountDelayThreshold {
+			w := login(t, sc, "hunter2")
+
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, "invalid email or password") {
+				t.Fatalf("want flash message containing %q, got %q", "invalid email or password", got)
+			}
+		}
+
+		w := login(t, sc, testTwoFactorPassword)
+
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Errorf("want redirect to %q, got %q", "/login", got)
+		}
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "Too many failed login attempts") {
+			t.Errorf("want throttling flash message, got %q", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no remember token cookie while login attempts are delayed")
+		}
+	})
+
+	t.Run("account locked", func(t *testing.T) {
+		lockoutRepo := &lockout.FakeRepository{
+			Failures: newFailures(lockout.AccountLockoutThreshold, time.Now().UTC().Add(-5*time.Minute)),
+		}
+		sc := newSessionController(t, lockoutRepo)
+
+		w := login(t, sc, testTwoFactorPassword)
+
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "temporarily locked") {
+			t.Errorf("want account lock flash message, got %q", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no remember token cookie while the account is locked")
+		}
+	})
+
+	t.Run("warned about previous failures", func(t *testing.T) {
+		lockoutRepo := &lockout.FakeRepository{
+			Failures: newFailures(2, time.Now().UTC().Add(-time.Hour)),
+		}
+		sc := newSessionController(t, lockoutRepo)
+
+		w := login(t, sc, testTwoFactorPassword)
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Errorf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if got := decodedFlashLevel(t, w); got != "warning" {
+			t.Errorf("want flash level %q, got %q", "warning", got)
+		}
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "2 failed login attempt(s)") || !strings.Contains(got, "192.0.2.10") {
+			t.Errorf("want flash message reporting previous failures, got %q", got)
+		}
+		if len(lockoutRepo.Failures) != 0 {
+			t.Errorf("want failures to be reset, %d remain", len(lockoutRepo.Failures))
+		}
+	})
+
+	loginTwoFactor := func(t *testing.T, sc sessionController, challengeCookie *http.Cookie, code string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		form := url.Values{"code": {code}}
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/login/two-factor", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(challengeCookie)
+
+		w := httptest.NewRecorder()
+		sc.handleUserLoginTwoFactor()(w, r)
+
+		return w
+	}
+
+	t.Run("failures kept until the second factor is verified", func(t *testing.T) {
+		lockoutRepo := &lockout.FakeRepository{
+			Failures: newFailures(2, time.Now().UTC().Add(-time.Hour)),
+		}
+		sc := newSessionController(t, lockoutRepo, twofactor.TOTP{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true})
+
+		w := login(t, sc, testTwoFactorPassword)
+
+		if got := w.Header().Get("Location"); got != loginChallengePath {
+			t.Fatalf("want redirect to %q, got %q", loginChallengePath, got)
+		}
+		if responseCookie(w, "flash") != nil {
+			t.Error("want no flash message before the second factor is verified")
+		}
+		if len(lockoutRepo.Failures) != 2 {
+			t.Errorf("want failures to be kept, got %d", len(lockoutRepo.Failures))
+		}
+
+		challengeCookie := responseCookie(w, UserLoginChallengeCookieName)
+		if challengeCookie == nil {
+			t.Fatal("want a login challenge cookie to be set")
+		}
+
+		w = loginTwoFactor(t, sc, challengeCookie, testTwoFactorCode(t))
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Errorf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "2 failed login attempt(s)") {
+			t.Errorf("want flash message reporting previous failures, got %q", got)
+		}
+		if len(lockoutRepo.Failures) != 0 {
+			t.Errorf("want failures to be reset, %d remain", len(lockoutRepo.Failures))
+		}
+	})
+
+	t.Run("invalid second factor locks the account", func(t *testing.T) {
+		lockoutRepo := &lockout.FakeRepository{}
+		sc := newSessionController(t, lockoutRepo, twofactor.TOTP{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true})
+
+		w := login(t, sc, testTwoFactorPassword)
+
+		challengeCookie := responseCookie(w, UserLoginChallengeCookieName)
+		if challengeCookie == nil {
+			t.Fatal("want a login challenge cookie to be set")
+		}
+
+		for range lockout.AccountDelayThreshold {
+			w = loginTwoFactor(t, sc, challengeCookie, "000000")
+
+			if got := decodedFlashMessage(t, w); !strings.Contains(got, "invalid authentication code") {
+				t.Fatalf("want flash message containing %q, got %q", "invalid authentication code", got)
+			}
+		}
+
+		if len(lockoutRepo.Failures) != int(lockout.AccountDelayThreshold) {
+			t.Errorf("want %d failures recorded, got %d", lockout.AccountDelayThreshold, len(lockoutRepo.Failures))
+		}
+
+		w = loginTwoFactor(t, sc, challengeCookie, testTwoFactorCode(t))
+
+		if got := decodedFlashMessage(t, w); !strings.Contains(got, "Too many failed login attempts") {
+			t.Errorf("want throttling flash message, got %q", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no remember token cookie while login attempts are delayed")
+		}
+	})
+}
+
+func TestHandleUserLoginPasskey(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	passkeyRepo := &passkey.FakeRepository{}
+	passkeyService := newTestPasskeyService(t, passkeyRepo, u)
+	authenticator := webauthntest.NewAuthenticator(testPasskeyOrigin)
+
+	creation, token, err := passkeyService.BeginRegistration(t.Context(), u)
+	if err != nil {
+		t.Fatalf("failed to begin registration: %q", err)
+	}
+
+	creationJSON, err := json.Marshal(creation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential := authenticator.Create(t, creationJSON)
+
+	if _, err := passkeyService.FinishRegistration(t.Context(), u, token, "Phone", bytes.NewReader(credential)); err != nil {
+		t.Fatalf("failed to finish registration: %q", err)
+	}
+
+	// Users who have enabled two-factor authentication are not asked for a code.
+	twoFactorRepo := &twofactor.FakeRepository{
+		TOTPs: []twofactor.TOTP{{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true}},
+	}
+
+	sessionRepo := &session.FakeRepository{}
+	sessionService, err := session.NewService(sessionRepo, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userService := user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t))
+	lockoutRepo := &lockout.FakeRepository{}
+
+	mux := chi.NewMux()
+	mux.Use(chimiddleware.ClientIPFromRemoteAddr)
+	RegisterSessionHandlers(
+		mux,
+		nil,
+		true,
+		nil,
+		lockout.NewService(lockoutRepo, userService, nil),
+		passkeyService,
+		nil,
+		nil,
+		sessionService,
+		nil,
+		newTestTwoFactorService(t, twoFactorRepo),
+		userService,
+	)
+
+	// remote address of requests created with httptest.NewRequest
+	const clientIP = "192.0.2.1"
+
+	newFailures := func(n uint, email string) []lockout.Failure {
+		failures := make([]lockout.Failure, n)
+		for i := range failures {
+			failures[i] = lockout.Failure{Email: email, ClientIP: clientIP, CreatedAt: time.Now().UTC()}
+		}
+		return failures
+	}
+
+	beginLogin := func(t *testing.T) ([]byte, *http.Cookie) {
+		t.Helper()
+
+		w := postForm(t, mux, passkeyLoginPath+"/begin", url.Values{})
+		if w.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d", w.Code)
+		}
+
+		ceremonyCookie := responseCookie(w, PasskeyCeremonyCookieName)
+		if ceremonyCookie == nil {
+			t.Fatal("want a passkey ceremony cookie")
+		}
+
+		return w.Body.Bytes(), ceremonyCookie
+	}
+
+	finishLogin := func(t *testing.T, response []byte, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		t.Helper()
+
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, passkeyLoginPath+"/finish", bytes.NewReader(response))
+		r.Header.Set("Content-Type", "application/json")
+		for _, cookie := range cookies {
+			r.AddCookie(cookie)
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("valid passkey", func(t *testing.T) {
+		options, ceremonyCookie := beginLogin(t)
+
+		w := finishLogin(t, authenticator.Get(t, options), ceremonyCookie)
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("want status 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if responseCookie(w, UserRememberTokenCookieName) == nil {
+			t.Error("want a session cookie")
+		}
+		if len(sessionRepo.Sessions) != 1 {
+			t.Errorf("want 1 session, got %d", len(sessionRepo.Sessions))
+		}
+		if passkeyRepo.Passkeys[0].LastUsedAt.IsZero() {
+			t.Error("want the last usage date of the passkey to be set")
+		}
+	})
+
+	t.Run("missing ceremony", func(t *testing.T) {
+		options, _ := beginLogin(t)
+
+		w := finishLogin(t, authenticator.Get(t, options))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("want status 401, got %d", w.Code)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no session cookie")
+		}
+	})
+
+	t.Run("unknown passkey", func(t *testing.T) {
+		options, ceremonyCookie := beginLogin(t)
+
+		stranger := webauthntest.NewAuthenticator(testPasskeyOrigin)
+		stranger.Create(t, creationJSON)
+
+		lockoutRepo.Failures = nil
+
+		w := finishLogin(t, stranger.Get(t, options), ceremonyCookie)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("want status 401, got %d", w.Code)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no session cookie")
+		}
+		if len(lockoutRepo.Failures) != 1 || lockoutRepo.Failures[0].Email != "" {
+			t.Errorf("want 1 failure recorded for the client IP only, got %#v", lockoutRepo.Failures)
+		}
+	})
+
+	t.Run("client IP blocked", func(t *testing.T) {
+		lockoutRepo.Failures = newFailures(lockout.ClientIPBlockThreshold, "")
+		t.Cleanup(func() { lockoutRepo.Failures = nil })
+
+		w := postForm(t, mux, passkeyLoginPath+"/begin", url.Values{})
+
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("want status 429, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Too many failed login attempts from your network") {
+			t.Errorf("want throttling message, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("account locked", func(t *testing.T) {
+		options, ceremonyCookie := beginLogin(t)
+
+		lockoutRepo.Failures = newFailures(lockout.AccountLockoutThreshold, u.Email)
+		t.Cleanup(func() { lockoutRepo.Failures = nil })
+		sessionCount := len(sessionRepo.Sessions)
+
+		w := finishLogin(t, authenticator.Get(t, options), ceremonyCookie)
+
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("want status 429, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "temporarily locked") {
+			t.Errorf("want account lock message, got %q", w.Body.String())
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Error("want no session cookie")
+		}
+		if len(sessionRepo.Sessions) != sessionCount {
+			t.Errorf("want no new session, got %d", len(sessionRepo.Sessions)-sessionCount)
+		}
+	})
+
+	t.Run("cloned authenticator counts towards the account lockout", func(t *testing.T) {
+		clone := authenticator.Clone()
+
+		options, ceremonyCookie := beginLogin(t)
+		if w := finishLogin(t, authenticator.Get(t, options), ceremonyCookie); w.Code != http.StatusNoContent {
+			t.Fatalf("want status 204, got %d: %s", w.Code, w.Body.String())
+		}
+
+		options, ceremonyCookie = beginLogin(t)
+		w := finishLogin(t, clone.Get(t, options), ceremonyCookie)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("want status 401, got %d", w.Code)
+		}
+		if len(lockoutRepo.Failures) != 1 || lockoutRepo.Failures[0].Email != u.Email {
+			t.Errorf("want 1 failure recorded for %q, got %#v", u.Email, lockoutRepo.Failures)
+		}
+	})
+}
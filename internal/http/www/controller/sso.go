@@ -0,0 +1,140 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+)
+
+const (
+	// SSOStateCookieName is the name of the cookie binding a pending single sign-on
+	// authorization request to the browser that started it.
+	SSOStateCookieName string = "sso_state"
+
+	ssoLoginPath    string = "/login/sso"
+	ssoCallbackPath string = "/login/sso/callback"
+)
+
+// handleUserLoginSSO starts a single sign-on authorization request, and redirects the user to
+// the identity provider.
+func (sc *sessionController) handleUserLoginSSO() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authCodeURL, state, err := sc.ssoService.AuthCodeURL(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to start single sign-on authorization request")
+			view.PutFlashError(w, "There was an error logging you in")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		// The identity provider redirects the user back with a top-level navigation, hence
+		// the Lax SameSite policy.
+		cookie := http.Cookie{
+			Name:     SSOStateCookieName,
+			Value:    state,
+			Expires:  time.Now().UTC().Add(sso.AuthorizationRequestTTL),
+			Path:     ssoLoginPath,
+			HttpOnly: true,
+			Secure:   sc.secure,
+			SameSite: http.SameSiteLaxMode,
+		}
+		http.SetCookie(w, &cookie)
+
+		http.Redirect(w, r, authCodeURL, http.StatusSeeOther)
+	}
+}
+
+// handleUserLoginSSOCallback completes a single sign-on authorization request, and logs the
+// user in.
+//
+// Users who have enabled two-factor authentication are then asked for a code, as they would be
+// after a password login.
+func (sc *sessionController) handleUserLoginSSOCallback() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var expectedState string
+		if cookie, err := r.Cookie(SSOStateCookieName); err == nil {
+			expectedState = cookie.Value
+		}
+
+		clearCookie := http.Cookie{
+			Name:     SSOStateCookieName,
+			Value:    "",
+			Path:     ssoLoginPath,
+			Expires:  time.Unix(0, 1),
+			HttpOnly: true,
+			Secure:   sc.secure,
+			SameSite: http.SameSiteLaxMode,
+		}
+		http.SetCookie(w, &clearCookie)
+
+		query := r.URL.Query()
+
+		if errorCode := query.Get("error"); errorCode != "" {
+			log.Warn().
+				Str("client_ip", chimiddleware.GetClientIP(ctx)).
+				Str("error", errorCode).
+				Str("error_description", query.Get("error_description")).
+				Msg("single sign-on authorization request denied")
+			view.PutFlashError(w, "Your identity provider denied the login request")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		authenticatedUser, err := sc.ssoService.Authenticate(ctx, expectedState, query.Get("state"), query.Get("code"))
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("client_ip", chimiddleware.GetClientIP(ctx)).
+				Msg("failed to authenticate user with single sign-on")
+			recordAuditEvent(r, sc.auditService, audit.Event{
+				Type:    audit.EventLoginFailed,
+				Details: "single sign-on",
+			})
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		twoFactorEnabled, err := sc.twoFactorService.IsEnabled(ctx, authenticatedUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve two-factor authentication status")
+			view.PutFlashError(w, "There was an error logging you in")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		if twoFactorEnabled {
+			if err := sc.setUserLoginChallenge(w, authenticatedUser.UUID); err != nil {
+				log.Error().Err(err).Msg("failed to set login challenge")
+				view.PutFlashError(w, "There was an error logging you in")
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+
+			http.Redirect(w, r, loginChallengePath, http.StatusSeeOther)
+			return
+		}
+
+		if err := sc.setUserRememberToken(w, r, authenticatedUser.UUID); err != nil {
+			log.Error().Err(err).Msg("failed to set remember token")
+			view.PutFlashError(w, "failed to save session cookie")
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		sc.recordLoginSucceeded(r, authenticatedUser.UUID, "single sign-on")
+
+		http.Redirect(w, r, "/bookmarks", http.StatusSeeOther)
+	}
+}
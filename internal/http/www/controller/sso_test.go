@@ -0,0 +1,219 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/test/ssotest"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func newTestSSOLoginMux(t *testing.T, provider *ssotest.Provider, u user.User, twoFactorRepo *twofactor.FakeRepository) (*chi.Mux, *session.FakeRepository) {
+	t.Helper()
+
+	userService := user.NewService(&user.FakeRepository{Users: []user.User{u}}, user.FakePasswordHasher(t))
+
+	redirectURL, err := url.Parse(testPasskeyOrigin + ssoCallbackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := sso.Config{
+		IssuerURL:    provider.IssuerURL(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		ProviderName: "Example SSO",
+	}
+
+	ssoService, err := sso.NewService(t.Context(), &sso.FakeRepository{}, userService, config, redirectURL, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionRepo := &session.FakeRepository{}
+	sessionService, err := session.NewService(sessionRepo, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := chi.NewMux()
+	RegisterSessionHandlers(
+		mux,
+		nil,
+		true,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		sessionService,
+		ssoService,
+		newTestTwoFactorService(t, twoFactorRepo),
+		userService,
+	)
+
+	return mux, sessionRepo
+}
+
+func getWithCookies(t *testing.T, h http.Handler, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, target, nil)
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandleUserLoginSSO(t *testing.T) {
+	u := newTestTwoFactorUser(t)
+	claims := map[string]any{
+		"sub":            "jane-subject",
+		"email":          u.Email,
+		"email_verified": true,
+	}
+
+	// beginLogin starts an authorization request, and returns the callback URL the identity
+	// provider redirects the user to, along with the state cookie.
+	beginLogin := func(t *testing.T, mux *chi.Mux, provider *ssotest.Provider) (string, *http.Cookie) {
+		t.Helper()
+
+		w := getWithCookies(t, mux, ssoLoginPath)
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status 303, got %d", w.Code)
+		}
+
+		stateCookie := responseCookie(w, SSOStateCookieName)
+		if stateCookie == nil {
+			t.Fatal("want a state cookie")
+		}
+		if !stateCookie.HttpOnly || !stateCookie.Secure {
+			t.Error("want the state cookie to be HttpOnly and Secure")
+		}
+		if stateCookie.Path != ssoLoginPath {
+			t.Errorf("want cookie path %q, got %q", ssoLoginPath, stateCookie.Path)
+		}
+
+		callbackURL := provider.Authorize(t, w.Header().Get("Location"), claims)
+
+		return callbackURL.RequestURI(), stateCookie
+	}
+
+	t.Run("login view", func(t *testing.T) {
+		mux, _ := newTestSSOLoginMux(t, ssotest.NewProvider(t, "sparklemuffin", "client-secret"), u, &twofactor.FakeRepository{})
+
+		w := getWithCookies(t, mux, "/login")
+
+		if !strings.Contains(w.Body.String(), "Log in with Example SSO") {
+			t.Error("want a single sign-on button")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, "sparklemuffin", "client-secret")
+		mux, sessionRepo := newTestSSOLoginMux(t, provider, u, &twofactor.FakeRepository{})
+
+		callbackURI, stateCookie := beginLogin(t, mux, provider)
+
+		w := getWithCookies(t, mux, callbackURI, stateCookie)
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Fatalf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) == nil {
+			t.Error("want a session cookie")
+		}
+		if len(sessionRepo.Sessions) != 1 {
+			t.Errorf("want 1 session, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("two-factor authentication enabled", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, "sparklemuffin", "client-secret")
+		twoFactorRepo := &twofactor.FakeRepository{
+			TOTPs: []twofactor.TOTP{{UserUUID: u.UUID, Secret: testTwoFactorSecret, Enabled: true}},
+		}
+		mux, sessionRepo := newTestSSOLoginMux(t, provider, u, twoFactorRepo)
+
+		callbackURI, stateCookie := beginLogin(t, mux, provider)
+
+		w := getWithCookies(t, mux, callbackURI, stateCookie)
+
+		if got := w.Header().Get("Location"); got != loginChallengePath {
+			t.Fatalf("want redirect to %q, got %q", loginChallengePath, got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) != nil {
+			t.Fatal("want no remember token cookie before the second factor is verified")
+		}
+		if len(sessionRepo.Sessions) != 0 {
+			t.Fatalf("want no session, got %d", len(sessionRepo.Sessions))
+		}
+
+		challengeCookie := responseCookie(w, UserLoginChallengeCookieName)
+		if challengeCookie == nil {
+			t.Fatal("want a login challenge cookie to be set")
+		}
+
+		w = postForm(t, mux, loginChallengePath, url.Values{"code": {testTwoFactorCode(t)}}, challengeCookie)
+
+		if got := w.Header().Get("Location"); got != "/bookmarks" {
+			t.Fatalf("want redirect to %q, got %q", "/bookmarks", got)
+		}
+		if responseCookie(w, UserRememberTokenCookieName) == nil {
+			t.Error("want a session cookie")
+		}
+		if len(sessionRepo.Sessions) != 1 {
+			t.Errorf("want 1 session, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("missing state cookie", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, "sparklemuffin", "client-secret")
+		mux, sessionRepo := newTestSSOLoginMux(t, provider, u, &twofactor.FakeRepository{})
+
+		callbackURI, _ := beginLogin(t, mux, provider)
+
+		w := getWithCookies(t, mux, callbackURI)
+
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Fatalf("want redirect to %q, got %q", "/login", got)
+		}
+		if got, want := decodedFlashMessage(t, w), "Error: "+userFacingError(sso.ErrAuthorizationRequestInvalid); got != want {
+			t.Errorf("want flash message %q, got %q", want, got)
+		}
+		if len(sessionRepo.Sessions) != 0 {
+			t.Errorf("want no session, got %d", len(sessionRepo.Sessions))
+		}
+	})
+
+	t.Run("denied by the identity provider", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, "sparklemuffin", "client-secret")
+		mux, sessionRepo := newTestSSOLoginMux(t, provider, u, &twofactor.FakeRepository{})
+
+		_, stateCookie := beginLogin(t, mux, provider)
+
+		w := getWithCookies(t, mux, ssoCallbackPath+"?error=access_denied&state="+url.QueryEscape(stateCookie.Value), stateCookie)
+
+		if got := w.Header().Get("Location"); got != "/login" {
+			t.Fatalf("want redirect to %q, got %q", "/login", got)
+		}
+		if len(sessionRepo.Sessions) != 0 {
+			t.Errorf("want no session, got %d", len(sessionRepo.Sessions))
+		}
+	})
+}
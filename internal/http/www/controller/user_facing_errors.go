@@ -7,7 +7,20 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 var userFacingErrorMessages = map[error]string{
@@ -21,12 +34,91 @@ var userFacingErrorMessages = map[error]string{
 	user.ErrPasswordTooShort:             fmt.Sprintf("Password must be at least %d characters long.", user.MinPasswordLength),
 	user.ErrPasswordIncorrect:            "Your current password is incorrect.",
 	user.ErrPasswordConfirmationMismatch: "The new password and confirmation do not match.",
+	user.ErrNickNameConfirmationMismatch: "The nickname does not match your account's nickname.",
+	user.ErrReauthenticationRequired:     fmt.Sprintf("Please log in again, then confirm within %d minutes.", int(user.AccountDeletionReauthenticationWindow.Minutes())),
+
+	audit.ErrDateRangeInvalid: "The end date must not be before the start date.",
+	audit.ErrTypeInvalid:      "This event type is not supported.",
+	errAuditFilterDateInvalid: "Dates must be formatted as YYYY-MM-DD.",
+
+	bookmark.ErrBulkEditActionUnknown:     "This action is not supported.",
+	bookmark.ErrLinkStatusInvalid:         "Bookmarks cannot be filtered by this link status.",
+	bookmark.ErrSelectionRequired:         "Select at least one bookmark.",
+	bookmark.ErrTagAliasNotFound:          "This tag alias could not be found.",
+	bookmark.ErrTagAliasSameAsTag:         "An alias cannot apply to its own tag.",
+	bookmark.ErrTagCaseFoldingUnknown:     "This case option is not supported.",
+	bookmark.ErrTagMergeSourcesRequired:   "Select at least one tag to merge.",
+	bookmark.ErrTagNameContainsWhitespace: "Tag names cannot contain spaces.",
+	bookmark.ErrTagNameRequired:           "Tag name is required.",
+	bookmark.ErrUIDInvalid:                "This bookmark could not be found.",
+
+	feed.ErrFeedNotFound:                           "This feed could not be found.",
+	feed.ErrFeedURLInvalid:                         "This URL is invalid.",
+	feed.ErrFeedURLNoHost:                          "This URL has no host.",
+	feed.ErrFeedURLNoScheme:                        "This URL has no scheme; it must start with http:// or https://.",
+	feed.ErrFeedURLRequired:                        "URL is required.",
+	feed.ErrFeedURLUnsupportedScheme:               "This URL must start with http:// or https://.",
+	feedadministrating.ErrFeedMergeSameFeed:        "A feed cannot be merged into itself.",
+	feedadministrating.ErrFeedURLAlreadyRegistered: "Another feed is registered with this URL; merge the feeds instead.",
+
+	passkey.ErrAlreadyRegistered: "This authenticator is already registered.",
+	passkey.ErrCeremonyInvalid:   "This passkey request has expired; please try again.",
+	passkey.ErrCredentialInvalid: "This passkey could not be verified.",
+	passkey.ErrNameRequired:      "Name is required.",
+	passkey.ErrNameTooLong:       fmt.Sprintf("Name must be at most %d characters long.", passkey.NameMaxLength),
+
+	quota.ErrBookmarkLimitReached:     "You have reached the maximum number of bookmarks allowed for your account.",
+	quota.ErrImportFileTooLarge:       "This file exceeds the maximum import file size allowed for your account.",
+	quota.ErrLimitInvalid:             "Limits must be positive or zero.",
+	quota.ErrSubscriptionLimitReached: "You have reached the maximum number of feed subscriptions allowed for your account.",
+	errQuotaFormLimitInvalid:          "Limits must be whole numbers.",
+
+	registration.ErrInvitationMaxUsesInvalid:  fmt.Sprintf("An invitation must allow between 1 and %d accounts.", registration.InvitationMaxUsesLimit),
+	registration.ErrInvitationsDisabled:       "Invitations are disabled on this instance.",
+	registration.ErrInvitationValidityInvalid: fmt.Sprintf("An invitation must be valid for 1 to %d days.", registration.InvitationValidityDaysLimit),
+	registration.ErrRegistrationClosed:        "Registration is closed on this instance.",
+
+	session.ErrNotFound: "This session could not be found.",
+
+	sso.ErrAuthorizationRequestInvalid: "This login attempt has expired; please try again.",
+	sso.ErrEmailNotVerified:            "Your identity provider did not provide a verified email address.",
+	sso.ErrIDTokenInvalid:              "Your identity could not be verified.",
+	sso.ErrUserNotProvisioned:          "No account is associated with this identity; please contact an administrator.",
+
+	twofactor.ErrAlreadyEnabled: "Two-factor authentication is already enabled.",
+	twofactor.ErrCodeInvalid:    "This authentication code is invalid.",
+	twofactor.ErrCodeRequired:   "Authentication code is required.",
+
+	savedsearch.ErrAlreadyRegistered:  "A saved search with this name already exists.",
+	savedsearch.ErrDomainInvalid:      "This kind of saved search is not supported.",
+	savedsearch.ErrNameRequired:       "Name is required.",
+	savedsearch.ErrPublicNotSupported: "Only bookmark searches can be published.",
+	savedsearch.ErrQueryRequired:      "Search query is required.",
+	savedsearch.ErrSlugRequired:       "The name must contain at least one letter or digit.",
+
+	webhook.ErrEventsRequired:              "Select at least one event.",
+	webhook.ErrEventUnknown:                "This event is not supported.",
+	webhook.ErrFilterConflict:              "Filter on either a category or a subscription, not both.",
+	webhook.ErrFeedCategoryUUIDInvalid:     "This feed category is invalid.",
+	webhook.ErrFeedSubscriptionUUIDInvalid: "This feed subscription is invalid.",
+	webhook.ErrURLBlocked:                  "This URL points to a private or reserved network address.",
+	webhook.ErrURLInvalid:                  "This URL is invalid.",
+	webhook.ErrURLNoHost:                   "This URL has no host.",
+	webhook.ErrURLNoScheme:                 "This URL has no scheme; it must start with http:// or https://.",
+	webhook.ErrURLRequired:                 "URL is required.",
+	webhook.ErrURLUnsupportedScheme:        "This URL must start with http:// or https://.",
 }
 
-// userFacingError maps a domain error returned by the user package to a
-// message safe to display to an end user, falling back to a generic message
-// for anything not explicitly mapped (e.g. ErrNotFound, storage errors).
+// userFacingError maps a domain error returned by the user, audit, bookmark, feed administration, passkey,
+// quota, registration, saved search, session, single sign-on, two-factor or webhook packages to a message safe to display to an end user, falling
+// back to a generic message for anything not explicitly mapped (e.g. ErrNotFound, storage errors).
+//
+// Malformed search queries are explained with searchQueryErrorMessage.
 func userFacingError(err error) string {
+	if errors.Is(err, search.ErrQueryInvalid) {
+		return searchQueryErrorMessage(err)
+	}
+
 	for domainErr, message := range userFacingErrorMessages {
 		if errors.Is(err, domainErr) {
 			return message
@@ -35,3 +127,14 @@ func userFacingError(err error) string {
 
 	return "Something went wrong. Please try again."
 }
+
+// searchQueryErrorMessage returns a message explaining why a search query is malformed,
+// safe to display to an end user.
+func searchQueryErrorMessage(err error) string {
+	var queryErr *search.QueryError
+	if errors.As(err, &queryErr) {
+		return fmt.Sprintf("Invalid search query: %s.", queryErr.Reason)
+	}
+
+	return "Invalid search query."
+}
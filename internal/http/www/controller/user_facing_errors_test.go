@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -30,6 +31,7 @@ func TestUserFacingError(t *testing.T) {
 		{"password incorrect", user.ErrPasswordIncorrect, "Your current password is incorrect."},
 		{"password confirmation mismatch", user.ErrPasswordConfirmationMismatch, "The new password and confirmation do not match."},
 		{"wrapped sentinel is still recognized", fmt.Errorf("wrap: %w", user.ErrPasswordTooShort), passwordTooShort},
+		{"malformed search query", &search.QueryError{Reason: "missing closing quote"}, "Invalid search query: missing closing quote."},
 		{"unmapped error falls back to a generic message", errors.New("some internal detail"), "Something went wrong. Please try again."},
 	}
 
@@ -0,0 +1,360 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+const (
+	webhookFilterCategoryPrefix     = "category:"
+	webhookFilterSubscriptionPrefix = "subscription:"
+)
+
+// RegisterWebhookHandlers registers handlers to manage user webhooks.
+func RegisterWebhookHandlers(
+	r *chi.Mux,
+	feedQueryingService *feedquerying.Service,
+	webhookService *webhook.Service,
+) {
+	wc := webhookController{
+		feedQueryingService: feedQueryingService,
+		webhookService:      webhookService,
+
+		webhookAddView:        view.New("account/webhook_add.gohtml", "account/webhook_form.gohtml"),
+		webhookDeleteView:     view.New("account/webhook_delete.gohtml"),
+		webhookDeliveriesView: view.New("account/webhook_deliveries.gohtml"),
+		webhookEditView:       view.New("account/webhook_edit.gohtml", "account/webhook_form.gohtml"),
+		webhookListView:       view.New("account/webhook_list.gohtml"),
+	}
+
+	r.Route("/account/webhooks", func(r chi.Router) {
+		r.Use(func(h http.Handler) http.Handler {
+			return middleware.AuthenticatedUser(h.ServeHTTP)
+		})
+
+		r.Get("/", wc.handleWebhookListView())
+		r.Get("/add", wc.handleWebhookAddView())
+		r.Post("/add", wc.handleWebhookAdd())
+		r.Get("/{uuid}/delete", wc.handleWebhookDeleteView())
+		r.Post("/{uuid}/delete", wc.handleWebhookDelete())
+		r.Get("/{uuid}/deliveries", wc.handleWebhookDeliveriesView())
+		r.Get("/{uuid}/edit", wc.handleWebhookEditView())
+		r.Post("/{uuid}/edit", wc.handleWebhookEdit())
+		r.Post("/{uuid}/secret", wc.handleWebhookSecretRegenerate())
+	})
+}
+
+type webhookController struct {
+	feedQueryingService *feedquerying.Service
+	webhookService      *webhook.Service
+
+	webhookAddView        *view.View
+	webhookDeleteView     *view.View
+	webhookDeliveriesView *view.View
+	webhookEditView       *view.View
+	webhookListView       *view.View
+}
+
+type webhookForm struct {
+	URL        string   `schema:"url"`
+	Events     []string `schema:"events"`
+	FeedFilter string   `schema:"feed_filter"`
+	Active     bool     `schema:"active"`
+}
+
+func (f *webhookForm) asWebhook(userUUID string) webhook.Webhook {
+	w := webhook.Webhook{
+		UserUUID: userUUID,
+		URL:      f.URL,
+		Active:   f.Active,
+	}
+
+	for _, event := range f.Events {
+		w.Events = append(w.Events, webhook.Event(event))
+	}
+
+	switch {
+	case strings.HasPrefix(f.FeedFilter, webhookFilterCategoryPrefix):
+		w.FeedCategoryUUID = strings.TrimPrefix(f.FeedFilter, webhookFilterCategoryPrefix)
+	case strings.HasPrefix(f.FeedFilter, webhookFilterSubscriptionPrefix):
+		w.FeedSubscriptionUUID = strings.TrimPrefix(f.FeedFilter, webhookFilterSubscriptionPrefix)
+	}
+
+	return w
+}
+
+type webhookFormContent struct {
+	Webhook                 *webhook.Webhook
+	Events                  []webhook.Event
+	FeedFilter              string
+	SubscriptionsByCategory []feedquerying.SubscriptionsByCategory
+}
+
+func webhookFeedFilter(w webhook.Webhook) string {
+	switch {
+	case w.FeedCategoryUUID != "":
+		return webhookFilterCategoryPrefix + w.FeedCategoryUUID
+	case w.FeedSubscriptionUUID != "":
+		return webhookFilterSubscriptionPrefix + w.FeedSubscriptionUUID
+	default:
+		return ""
+	}
+}
+
+// handleWebhookAdd processes the webhook creation form.
+func (wc *webhookController) handleWebhookAdd() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form webhookForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse webhook creation form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		newWebhook, err := wc.webhookService.Add(ctx, form.asWebhook(ctxUser.UUID))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to add webhook")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The webhook has been created; use its secret to verify payload signatures")
+		http.Redirect(w, r, "/account/webhooks/"+newWebhook.UUID+"/edit", http.StatusSeeOther)
+	}
+}
+
+// handleWebhookAddView renders the webhook creation form.
+func (wc *webhookController) handleWebhookAddView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		subscriptionsByCategory, err := wc.feedQueryingService.SubscriptionsByCategory(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve feed subscriptions")
+			view.PutFlashError(w, "failed to retrieve feed subscriptions")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Add webhook",
+			Content: webhookFormContent{
+				Webhook:                 &webhook.Webhook{Active: true},
+				Events:                  webhook.AllEvents,
+				SubscriptionsByCategory: subscriptionsByCategory,
+			},
+		}
+
+		wc.webhookAddView.Render(w, r, viewData)
+	}
+}
+
+// handleWebhookDelete processes the webhook deletion form.
+func (wc *webhookController) handleWebhookDelete() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if err := wc.webhookService.Delete(ctx, ctxUser.UUID, webhookUUID); err != nil {
+			log.Error().Err(err).Msg("failed to delete webhook")
+			view.PutFlashError(w, "failed to delete webhook")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The webhook has been deleted")
+		http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+	}
+}
+
+// handleWebhookDeleteView renders the webhook deletion form.
+func (wc *webhookController) handleWebhookDeleteView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		wh, err := wc.webhookService.ByUUID(ctx, ctxUser.UUID, webhookUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve webhook")
+			view.PutFlashError(w, "failed to retrieve webhook")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Delete webhook",
+			Content: wh,
+		}
+
+		wc.webhookDeleteView.Render(w, r, viewData)
+	}
+}
+
+// handleWebhookDeliveriesView renders the delivery log for a given webhook.
+func (wc *webhookController) handleWebhookDeliveriesView() func(w http.ResponseWriter, r *http.Request) {
+	type webhookDeliveriesContent struct {
+		Webhook    webhook.Webhook
+		Deliveries []webhook.Delivery
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		wh, err := wc.webhookService.ByUUID(ctx, ctxUser.UUID, webhookUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve webhook")
+			view.PutFlashError(w, "failed to retrieve webhook")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		deliveries, err := wc.webhookService.Deliveries(ctx, ctxUser.UUID, webhookUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve webhook deliveries")
+			view.PutFlashError(w, "failed to retrieve webhook deliveries")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Webhook deliveries",
+			Content: webhookDeliveriesContent{
+				Webhook:    wh,
+				Deliveries: deliveries,
+			},
+		}
+
+		wc.webhookDeliveriesView.Render(w, r, viewData)
+	}
+}
+
+// handleWebhookEdit processes the webhook edition form.
+func (wc *webhookController) handleWebhookEdit() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		var form webhookForm
+		if err := decodeForm(r, &form); err != nil {
+			log.Error().Err(err).Msg("failed to parse webhook edition form")
+			view.PutFlashError(w, "There was an error processing the form")
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		editedWebhook := form.asWebhook(ctxUser.UUID)
+		editedWebhook.UUID = webhookUUID
+
+		if err := wc.webhookService.Update(ctx, editedWebhook); err != nil {
+			log.Error().Err(err).Msg("failed to update webhook")
+			view.PutFlashError(w, userFacingError(err))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The webhook has been updated")
+		http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+	}
+}
+
+// handleWebhookEditView renders the webhook edition form.
+func (wc *webhookController) handleWebhookEditView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		wh, err := wc.webhookService.ByUUID(ctx, ctxUser.UUID, webhookUUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve webhook")
+			view.PutFlashError(w, "failed to retrieve webhook")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		subscriptionsByCategory, err := wc.feedQueryingService.SubscriptionsByCategory(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve feed subscriptions")
+			view.PutFlashError(w, "failed to retrieve feed subscriptions")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title: "Edit webhook",
+			Content: webhookFormContent{
+				Webhook:                 &wh,
+				Events:                  webhook.AllEvents,
+				FeedFilter:              webhookFeedFilter(wh),
+				SubscriptionsByCategory: subscriptionsByCategory,
+			},
+		}
+
+		wc.webhookEditView.Render(w, r, viewData)
+	}
+}
+
+// handleWebhookListView renders the list of the user's webhooks.
+func (wc *webhookController) handleWebhookListView() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		webhooks, err := wc.webhookService.All(ctx, ctxUser.UUID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to retrieve webhooks")
+			view.PutFlashError(w, "failed to retrieve webhooks")
+			http.Redirect(w, r, "/account/info", http.StatusSeeOther)
+			return
+		}
+
+		viewData := view.Data{
+			Title:   "Webhooks",
+			Content: webhooks,
+		}
+
+		wc.webhookListView.Render(w, r, viewData)
+	}
+}
+
+// handleWebhookSecretRegenerate replaces the secret used to sign a webhook's payloads.
+func (wc *webhookController) handleWebhookSecretRegenerate() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookUUID := chi.URLParam(r, "uuid")
+		ctx := r.Context()
+		ctxUser := httpcontext.UserValue(ctx)
+
+		if _, err := wc.webhookService.RegenerateSecret(ctx, ctxUser.UUID, webhookUUID); err != nil {
+			log.Error().Err(err).Msg("failed to regenerate webhook secret")
+			view.PutFlashError(w, "failed to regenerate webhook secret")
+			http.Redirect(w, r, "/account/webhooks", http.StatusSeeOther)
+			return
+		}
+
+		view.PutFlashSuccess(w, "The webhook secret has been regenerated")
+		http.Redirect(w, r, "/account/webhooks/"+webhookUUID+"/edit", http.StatusSeeOther)
+	}
+}
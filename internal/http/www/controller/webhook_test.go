@@ -0,0 +1,238 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
+	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+func newTestWebhookController(webhookRepo *webhook.FakeRepository) webhookController {
+	queryingRepo := &feedquerying.FakeRepository{
+		Categories:    []feed.Category{testCategory},
+		Feeds:         []feed.Feed{testFeed},
+		Subscriptions: []feed.Subscription{testSubscription},
+	}
+
+	return webhookController{
+		feedQueryingService: feedquerying.NewService(queryingRepo),
+		webhookService:      webhook.NewService(webhookRepo, nil, nil),
+
+		webhookAddView:        view.New("account/webhook_add.gohtml", "account/webhook_form.gohtml"),
+		webhookDeliveriesView: view.New("account/webhook_deliveries.gohtml"),
+		webhookEditView:       view.New("account/webhook_edit.gohtml", "account/webhook_form.gohtml"),
+		webhookListView:       view.New("account/webhook_list.gohtml"),
+	}
+}
+
+func newWebhookRequest(t *testing.T, method string, target string, webhookUUID string, form url.Values) *http.Request {
+	t.Helper()
+
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	}
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("uuid", webhookUUID)
+
+	ctx := httpcontext.WithUser(r.Context(), testCtxUser)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, routeCtx)
+
+	return r.WithContext(ctx)
+}
+
+func TestHandleWebhookAdd(t *testing.T) {
+	t.Run("creates the webhook and redirects to its edition page", func(t *testing.T) {
+		webhookRepo := &webhook.FakeRepository{}
+		wc := newTestWebhookController(webhookRepo)
+
+		form := url.Values{}
+		form.Set("url", "https://hooks.example.com/sparklemuffin")
+		form.Add("events", string(webhook.EventBookmarkAdded))
+		form.Add("events", string(webhook.EventFeedEntryCreated))
+
+		r := newWebhookRequest(t, http.MethodPost, "/account/webhooks/add", "", form)
+		w := httptest.NewRecorder()
+
+		wc.handleWebhookAdd()(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d, body:\n%s", http.StatusSeeOther, w.Code, w.Body.String())
+		}
+		if len(webhookRepo.Webhooks) != 1 {
+			t.Fatalf("want 1 webhook, got %d", len(webhookRepo.Webhooks))
+		}
+
+		got := webhookRepo.Webhooks[0]
+		if got.UserUUID != testCtxUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testCtxUser.UUID, got.UserUUID)
+		}
+		if len(got.Events) != 2 {
+			t.Errorf("want 2 events, got %v", got.Events)
+		}
+		if want := "/account/webhooks/" + got.UUID + "/edit"; w.Header().Get("Location") != want {
+			t.Errorf("want redirect to %q, got %q", want, w.Header().Get("Location"))
+		}
+	})
+
+	t.Run("invalid URL flashes a user-friendly message", func(t *testing.T) {
+		webhookRepo := &webhook.FakeRepository{}
+		wc := newTestWebhookController(webhookRepo)
+
+		form := url.Values{}
+		form.Set("url", "ftp://hooks.example.com")
+		form.Add("events", string(webhook.EventBookmarkAdded))
+
+		r := newWebhookRequest(t, http.MethodPost, "/account/webhooks/add", "", form)
+		w := httptest.NewRecorder()
+
+		wc.handleWebhookAdd()(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("want status %d, got %d", http.StatusSeeOther, w.Code)
+		}
+		if got := decodedFlashMessage(t, w); got != "Error: This URL must start with http:// or https://." {
+			t.Errorf("want a user-friendly message, got %q", got)
+		}
+		if len(webhookRepo.Webhooks) != 0 {
+			t.Errorf("want no webhook, got %d", len(webhookRepo.Webhooks))
+		}
+	})
+}
+
+func TestHandleWebhookEditView(t *testing.T) {
+	wh := webhook.Webhook{
+		UUID:                 "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+		UserUUID:             testCtxUser.UUID,
+		URL:                  "https://hooks.example.com/sparklemuffin",
+		Secret:               "the-webhook-secret",
+		Events:               []webhook.Event{webhook.EventFeedEntryCreated},
+		FeedSubscriptionUUID: testSubscription.UUID,
+		Active:               true,
+	}
+	webhookRepo := &webhook.FakeRepository{Webhooks: []webhook.Webhook{wh}}
+	wc := newTestWebhookController(webhookRepo)
+
+	r := newWebhookRequest(t, http.MethodGet, "/account/webhooks/"+wh.UUID+"/edit", wh.UUID, nil)
+	w := httptest.NewRecorder()
+
+	wc.handleWebhookEditView()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, `value="the-webhook-secret"`) {
+		t.Errorf("want the secret to be displayed, got:\n%s", body)
+	}
+	if !strings.Contains(body, `value="feed.entry.created" checked`) {
+		t.Errorf("want the subscribed event to be checked, got:\n%s", body)
+	}
+	if !strings.Contains(body, `value="subscription:`+testSubscription.UUID+`" selected`) {
+		t.Errorf("want the subscription filter to be selected, got:\n%s", body)
+	}
+}
+
+func TestHandleWebhookDeliveriesView(t *testing.T) {
+	wh := webhook.Webhook{
+		UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+		UserUUID: testCtxUser.UUID,
+		URL:      "https://hooks.example.com/sparklemuffin",
+		Events:   []webhook.Event{webhook.EventBookmarkAdded},
+		Active:   true,
+	}
+	webhookRepo := &webhook.FakeRepository{
+		Webhooks: []webhook.Webhook{wh},
+		Deliveries: []webhook.Delivery{
+			{
+				UUID:               "c0ffee00-0000-4000-8000-000000000001",
+				WebhookUUID:        wh.UUID,
+				Event:              webhook.EventBookmarkAdded,
+				Status:             webhook.DeliveryStatusFailed,
+				Attempts:           webhook.MaxDeliveryAttempts,
+				ResponseStatusCode: http.StatusBadGateway,
+				Error:              "webhook: unexpected response status: 502 Bad Gateway",
+			},
+		},
+	}
+	wc := newTestWebhookController(webhookRepo)
+
+	r := newWebhookRequest(t, http.MethodGet, "/account/webhooks/"+wh.UUID+"/deliveries", wh.UUID, nil)
+	w := httptest.NewRecorder()
+
+	wc.handleWebhookDeliveriesView()(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d, body:\n%s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "<td>502</td>") {
+		t.Errorf("want the response status code to be displayed, got:\n%s", body)
+	}
+	if !strings.Contains(body, "502 Bad Gateway") {
+		t.Errorf("want the delivery error to be displayed, got:\n%s", body)
+	}
+}
+
+func TestHandleBookmarkDelete_NotifiesWebhooks(t *testing.T) {
+	b := bookmark.Bookmark{
+		UID:      "2JZ3R0D9Z4rXDPEQcSPhPJ2ebSA",
+		UserUUID: testCtxUser.UUID,
+		URL:      "https://example.com",
+		Title:    "Example",
+	}
+	webhookRepo := &webhook.FakeRepository{
+		Webhooks: []webhook.Webhook{
+			{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testCtxUser.UUID,
+				Events:   []webhook.Event{webhook.EventBookmarkDeleted},
+				Active:   true,
+			},
+		},
+	}
+
+	bc := bookmarkController{
+		bookmarkService: bookmark.NewService(&bookmark.FakeRepository{Bookmarks: []bookmark.Bookmark{b}}, nil),
+		webhookService:  webhook.NewService(webhookRepo, nil, nil),
+	}
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/bookmarks/"+b.UID+"/delete", nil)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("uid", b.UID)
+	ctx := httpcontext.WithUser(r.Context(), testCtxUser)
+	r = r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, routeCtx))
+	w := httptest.NewRecorder()
+
+	bc.handleBookmarkDelete()(w, r)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("want status %d, got %d, body:\n%s", http.StatusSeeOther, w.Code, w.Body.String())
+	}
+	if len(webhookRepo.Deliveries) != 1 {
+		t.Fatalf("want 1 queued delivery, got %d", len(webhookRepo.Deliveries))
+	}
+	if got := webhookRepo.Deliveries[0].Event; got != webhook.EventBookmarkDeleted {
+		t.Errorf("want event %q, got %q", webhook.EventBookmarkDeleted, got)
+	}
+}
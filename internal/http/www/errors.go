@@ -10,16 +10,37 @@ var (
 	ErrServerMetricsRegistryRequired = errors.New("server: metrics registry required")
 	ErrServerPublicURLRequired       = errors.New("server: public url required")
 
+	ErrServerAuditServiceRequired = errors.New("server: audit service required")
+
 	ErrServerBookmarkServiceRequired          = errors.New("server: bookmark service required")
+	ErrServerBookmarkCheckingServiceRequired  = errors.New("server: bookmark checking service required")
 	ErrServerBookmarkExportingServiceRequired = errors.New("server: bookmark exporting service required")
 	ErrServerBookmarkImportingServiceRequired = errors.New("server: bookmark importing service required")
 	ErrServerBookmarkQueryingServiceRequired  = errors.New("server: bookmark querying service required")
 
+	ErrServerBookmarkSuggestingServiceRequired = errors.New("server: bookmark suggesting service required")
+
+	ErrServerBookmarkArchivingServiceRequired = errors.New("server: bookmark archiving service required")
+
 	ErrServerFeedServiceRequired          = errors.New("server: feed service required")
 	ErrServerFeedExportingServiceRequired = errors.New("server: feed exporting service required")
 	ErrServerFeedImportingServiceRequired = errors.New("server: feed importing service required")
 	ErrServerFeedQueryingServiceRequired  = errors.New("server: feed querying service required")
 
-	ErrServerSessionServiceRequired = errors.New("server: session service required")
-	ErrServerUserServiceRequired    = errors.New("server: user service required")
+	ErrServerFeedAdministratingServiceRequired = errors.New("server: feed administrating service required")
+
+	ErrServerInstanceServiceRequired = errors.New("server: instance service required")
+
+	ErrServerLockoutServiceRequired   = errors.New("server: lockout service required")
+	ErrServerPasskeyServiceRequired   = errors.New("server: passkey service required")
+	ErrServerQuotaServiceRequired     = errors.New("server: quota service required")
+	ErrServerSessionServiceRequired   = errors.New("server: session service required")
+	ErrServerTwoFactorServiceRequired = errors.New("server: two-factor service required")
+	ErrServerUserServiceRequired      = errors.New("server: user service required")
+
+	ErrServerUserExportingServiceRequired = errors.New("server: user exporting service required")
+
+	ErrServerSavedSearchServiceRequired = errors.New("server: saved search service required")
+
+	ErrServerWebhookServiceRequired = errors.New("server: webhook service required")
 )
@@ -7,6 +7,7 @@ package httpcontext
 import (
 	"context"
 
+	"github.com/virtualtam/sparklemuffin/pkg/session"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -14,6 +15,7 @@ type contextKey string
 
 const (
 	cspNonceKey contextKey = "csp_nonce"
+	sessionKey  contextKey = "session"
 	userKey     contextKey = "user"
 )
 
@@ -33,6 +35,22 @@ func CSPNonceValue(ctx context.Context) string {
 	return ""
 }
 
+// WithSession enriches a context.Context with the current user session.Session.
+func WithSession(ctx context.Context, userSession session.Session) context.Context {
+	return context.WithValue(ctx, sessionKey, userSession)
+}
+
+// SessionValue retrieves the current user session.Session from a context.Context.
+func SessionValue(ctx context.Context) *session.Session {
+	if value := ctx.Value(sessionKey); value != nil {
+		if ctxSession, ok := value.(session.Session); ok {
+			return &ctxSession
+		}
+	}
+
+	return nil
+}
+
 // WithUser enriches a context.Context with a user.User.
 func WithUser(ctx context.Context, user user.User) context.Context {
 	return context.WithValue(ctx, userKey, user)
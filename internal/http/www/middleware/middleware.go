@@ -83,6 +83,15 @@ func StaticCacheControl(h http.Handler) http.HandlerFunc {
 	}
 }
 
+// NoReferrer sets the Referrer-Policy header to prevent browsers from disclosing the current URL,
+// e.g. when it contains a secret token, to other sites.
+func NoReferrer(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		h.ServeHTTP(w, r)
+	})
+}
+
 // ContentSecurityPolicy sets the Content-Security-Policy header.
 func ContentSecurityPolicy(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
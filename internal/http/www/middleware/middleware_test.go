@@ -141,6 +141,22 @@ func TestStaticCacheControl(t *testing.T) {
 	}
 }
 
+func TestNoReferrer(t *testing.T) {
+	want := "no-referrer"
+
+	handler := NoReferrer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+
+	handler.ServeHTTP(w, r)
+	got := w.Header().Get("Referrer-Policy")
+
+	if got != want {
+		t.Errorf("want Referrer-Policy %q, got %q", want, got)
+	}
+}
+
 func TestContentSecurityPolicy(t *testing.T) {
 	var gotNonce string
 
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httprate"
 	"github.com/rs/zerolog/log"
@@ -20,27 +21,90 @@ const (
 	loginRateLimitPerAccountRequests = 5
 	loginRateLimitPerAccountWindow   = 1 * time.Minute
 
+	passkeyLoginRateLimitPerIPRequests = 60
+	passkeyLoginRateLimitPerIPWindow   = 1 * time.Minute
+
+	passwordResetRateLimitPerIPRequests = 30
+	passwordResetRateLimitPerIPWindow   = 1 * time.Hour
+
+	passwordResetRateLimitPerAccountRequests = 5
+	passwordResetRateLimitPerAccountWindow   = 1 * time.Hour
+
+	registrationRateLimitPerIPRequests = 10
+	registrationRateLimitPerIPWindow   = 1 * time.Hour
+
+	ssoLoginRateLimitPerIPRequests = 60
+	ssoLoginRateLimitPerIPWindow   = 1 * time.Minute
+
+	secondFactorRateLimitPerIPRequests = 60
+	secondFactorRateLimitPerIPWindow   = 1 * time.Minute
+
+	secondFactorRateLimitPerAccountRequests = 5
+	secondFactorRateLimitPerAccountWindow   = 1 * time.Minute
+
 	missingEmailRateLimitKey = "missing-email"
 )
 
+// LoginRateLimitHook is called when a login attempt is rejected by the rate limiter, with the
+// normalized email address the attempt was made for.
+type LoginRateLimitHook func(r *http.Request, email string)
+
 // RateLimitLogin prevents brute-force login attacks by limiting login attempts by IP address
 // and by user email.
 //
+// onLimitExceeded, if set, is called for every rejected attempt.
+//
 // Responds with http.StatusTooManyRequests when triggered.
-func RateLimitLogin(h http.Handler) http.Handler {
-	return httprate.LimitBy(
-		loginRateLimitPerIPRequests,
-		loginRateLimitPerIPWindow,
-		loginIPKeyFunc,
-		httprate.WithLimitHandler(onLoginRateLimitExceeded),
-	)(
-		httprate.LimitBy(
-			loginRateLimitPerAccountRequests,
-			loginRateLimitPerAccountWindow,
-			loginEmailKeyFunc,
-			httprate.WithLimitHandler(onLoginRateLimitExceeded),
-		)(h),
-	)
+func RateLimitLogin(onLimitExceeded LoginRateLimitHook) func(http.Handler) http.Handler {
+	limitHandler := onLoginRateLimitExceeded(onLimitExceeded)
+
+	return func(h http.Handler) http.Handler {
+		return httprate.LimitBy(
+			loginRateLimitPerIPRequests,
+			loginRateLimitPerIPWindow,
+			loginIPKeyFunc,
+			httprate.WithLimitHandler(limitHandler),
+		)(
+			httprate.LimitBy(
+				loginRateLimitPerAccountRequests,
+				loginRateLimitPerAccountWindow,
+				loginEmailKeyFunc,
+				httprate.WithLimitHandler(limitHandler),
+			)(h),
+		)
+	}
+}
+
+// RateLimitSecondFactor prevents brute-force attacks on the second step of the login flow by
+// limiting attempts by IP address and by user account.
+//
+// accountKeyFunc identifies the user account for which the second factor is being submitted.
+//
+// Responds with http.StatusTooManyRequests when triggered.
+func RateLimitSecondFactor(accountKeyFunc httprate.KeyFunc) func(http.Handler) http.Handler {
+	onLimitExceeded := func(w http.ResponseWriter, r *http.Request) {
+		log.Warn().
+			Str("client_ip", chimiddleware.GetClientIP(r.Context())).
+			Msg("login: second factor rate limit exceeded")
+
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+
+	return func(h http.Handler) http.Handler {
+		return httprate.LimitBy(
+			secondFactorRateLimitPerIPRequests,
+			secondFactorRateLimitPerIPWindow,
+			loginIPKeyFunc,
+			httprate.WithLimitHandler(onLimitExceeded),
+		)(
+			httprate.LimitBy(
+				secondFactorRateLimitPerAccountRequests,
+				secondFactorRateLimitPerAccountWindow,
+				accountKeyFunc,
+				httprate.WithLimitHandler(onLimitExceeded),
+			)(h),
+		)
+	}
 }
 
 func loginIPKeyFunc(r *http.Request) (string, error) {
@@ -60,7 +124,122 @@ func loginEmailKeyFunc(r *http.Request) (string, error) {
 	return email, nil
 }
 
-func onLoginRateLimitExceeded(w http.ResponseWriter, r *http.Request) {
+func onLoginRateLimitExceeded(hook LoginRateLimitHook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email, err := loginEmailKeyFunc(r)
+		if err != nil {
+			email = missingEmailRateLimitKey
+		}
+
+		log.Warn().
+			Str("client_ip", chimiddleware.GetClientIP(r.Context())).
+			Str("email", email).
+			Msg("login: rate limit exceeded")
+
+		if hook != nil {
+			hook(r, email)
+		}
+
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+}
+
+// RateLimitPasskeyLogin prevents abuse of the passkey login flow by limiting login attempts by
+// IP address.
+//
+// The user account is only known once the authenticator's response has been verified, hence
+// attempts cannot be limited by account.
+//
+// Responds with http.StatusTooManyRequests when triggered.
+func RateLimitPasskeyLogin(h http.Handler) http.Handler {
+	onLimitExceeded := func(w http.ResponseWriter, r *http.Request) {
+		log.Warn().
+			Str("client_ip", chimiddleware.GetClientIP(r.Context())).
+			Msg("login: passkey rate limit exceeded")
+
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+
+	return httprate.LimitBy(
+		passkeyLoginRateLimitPerIPRequests,
+		passkeyLoginRateLimitPerIPWindow,
+		loginIPKeyFunc,
+		httprate.WithLimitHandler(onLimitExceeded),
+	)(h)
+}
+
+// RateLimitRegistration prevents abuse of the registration flow, such as mass account creation
+// or brute-forcing invitation and confirmation tokens, by limiting requests by IP address.
+//
+// Responds with http.StatusTooManyRequests when triggered.
+func RateLimitRegistration(h http.Handler) http.Handler {
+	onLimitExceeded := func(w http.ResponseWriter, r *http.Request) {
+		log.Warn().
+			Str("client_ip", chimiddleware.GetClientIP(r.Context())).
+			Msg("registration: rate limit exceeded")
+
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+
+	return httprate.LimitBy(
+		registrationRateLimitPerIPRequests,
+		registrationRateLimitPerIPWindow,
+		loginIPKeyFunc,
+		httprate.WithLimitHandler(onLimitExceeded),
+	)(h)
+}
+
+// RateLimitSSOLogin prevents abuse of the single sign-on login flow, such as flooding the
+// database with pending authorization requests, by limiting login attempts by IP address.
+//
+// Responds with http.StatusTooManyRequests when triggered.
+func RateLimitSSOLogin(h http.Handler) http.Handler {
+	onLimitExceeded := func(w http.ResponseWriter, r *http.Request) {
+		log.Warn().
+			Str("client_ip", chimiddleware.GetClientIP(r.Context())).
+			Msg("login: single sign-on rate limit exceeded")
+
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+
+	return httprate.LimitBy(
+		ssoLoginRateLimitPerIPRequests,
+		ssoLoginRateLimitPerIPWindow,
+		loginIPKeyFunc,
+		httprate.WithLimitHandler(onLimitExceeded),
+	)(h)
+}
+
+// RateLimitPasswordReset prevents abuse of the password reset flow, such as flooding a user's
+// inbox or brute-forcing reset tokens, by limiting requests by IP address and by user email
+// or reset token.
+//
+// Responds with http.StatusTooManyRequests when triggered.
+func RateLimitPasswordReset(h http.Handler) http.Handler {
+	return httprate.LimitBy(
+		passwordResetRateLimitPerIPRequests,
+		passwordResetRateLimitPerIPWindow,
+		loginIPKeyFunc,
+		httprate.WithLimitHandler(onPasswordResetRateLimitExceeded),
+	)(
+		httprate.LimitBy(
+			passwordResetRateLimitPerAccountRequests,
+			passwordResetRateLimitPerAccountWindow,
+			passwordResetAccountKeyFunc,
+			httprate.WithLimitHandler(onPasswordResetRateLimitExceeded),
+		)(h),
+	)
+}
+
+func passwordResetAccountKeyFunc(r *http.Request) (string, error) {
+	if token := chi.URLParam(r, "token"); token != "" {
+		return "token:" + token, nil
+	}
+
+	return loginEmailKeyFunc(r)
+}
+
+func onPasswordResetRateLimitExceeded(w http.ResponseWriter, r *http.Request) {
 	email, err := loginEmailKeyFunc(r)
 	if err != nil {
 		email = missingEmailRateLimitKey
@@ -69,7 +248,7 @@ func onLoginRateLimitExceeded(w http.ResponseWriter, r *http.Request) {
 	log.Warn().
 		Str("client_ip", chimiddleware.GetClientIP(r.Context())).
 		Str("email", email).
-		Msg("login: rate limit exceeded")
+		Msg("password reset: rate limit exceeded")
 
 	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
 }
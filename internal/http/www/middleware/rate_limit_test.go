@@ -14,7 +14,11 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"github.com/virtualtam/sparklemuffin/internal/http/www/controller"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -24,10 +28,11 @@ func TestRateLimit_PerAccount(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	userService := user.NewService(&user.FakeRepository{})
+	userService := user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t))
+	auditRepo := &audit.FakeRepository{}
 
 	mux := chi.NewMux()
-	controller.RegisterSessionHandlers(mux, true, sessionService, userService)
+	controller.RegisterSessionHandlers(mux, nil, true, audit.NewService(auditRepo), nil, nil, nil, nil, sessionService, nil, newTwoFactorService(t), userService)
 
 	var lastCode int
 	for range 6 {
@@ -37,6 +42,18 @@ func TestRateLimit_PerAccount(t *testing.T) {
 	if lastCode != http.StatusTooManyRequests {
 		t.Errorf("want status %d after exceeding the per-account limit, got %d", http.StatusTooManyRequests, lastCode)
 	}
+
+	if len(auditRepo.Events) != 6 {
+		t.Fatalf("want 6 audit events, got %d", len(auditRepo.Events))
+	}
+
+	rateLimited := auditRepo.Events[5]
+	if rateLimited.Type != audit.EventLoginRateLimited {
+		t.Errorf("want event type %q, got %q", audit.EventLoginRateLimited, rateLimited.Type)
+	}
+	if rateLimited.Details != "victim@example.com" {
+		t.Errorf("want event details %q, got %q", "victim@example.com", rateLimited.Details)
+	}
 }
 
 func TestRateLimit_PerIP(t *testing.T) {
@@ -45,10 +62,10 @@ func TestRateLimit_PerIP(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	userService := user.NewService(&user.FakeRepository{})
+	userService := user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t))
 
 	mux := chi.NewMux()
-	controller.RegisterSessionHandlers(mux, true, sessionService, userService)
+	controller.RegisterSessionHandlers(mux, nil, true, nil, nil, nil, nil, nil, sessionService, nil, newTwoFactorService(t), userService)
 
 	// distinct email per request keeps the per-account limiter from tripping first
 	var lastCode int
@@ -61,6 +78,59 @@ func TestRateLimit_PerIP(t *testing.T) {
 	}
 }
 
+func TestRateLimitPasswordReset_PerAccount(t *testing.T) {
+	userRepo := &user.FakeRepository{}
+	userService := user.NewService(userRepo, user.FakePasswordHasher(t))
+
+	passwordResetService, err := passwordreset.NewService(
+		&passwordreset.FakeRepository{UserRepository: userRepo},
+		userService,
+		&notification.FakeNotifier{},
+		"hmac-key",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService, err := session.NewService(&session.FakeRepository{}, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicURL, err := url.Parse("https://sparklemuffin.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := chi.NewMux()
+	controller.RegisterSessionHandlers(mux, publicURL, true, nil, nil, nil, passwordResetService, nil, sessionService, nil, newTwoFactorService(t), userService)
+
+	var lastCode int
+	for range 6 {
+		form := url.Values{"email": {"victim@example.com"}}
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/password-reset", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		lastCode = w.Code
+	}
+
+	if lastCode != http.StatusTooManyRequests {
+		t.Errorf("want status %d after exceeding the per-account limit, got %d", http.StatusTooManyRequests, lastCode)
+	}
+}
+
+func newTwoFactorService(t *testing.T) *twofactor.Service {
+	t.Helper()
+
+	twoFactorService, err := twofactor.NewService(&twofactor.FakeRepository{}, "hmac-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return twoFactorService
+}
+
 func postLoginForm(t *testing.T, h http.Handler, email string, password string) int {
 	t.Helper()
 
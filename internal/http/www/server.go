@@ -24,16 +24,33 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/www/middleware"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/static"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/view"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
 	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
 	bookmarkimporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/importing"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	bookmarksuggesting "github.com/virtualtam/sparklemuffin/pkg/bookmark/suggesting"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
 	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedimporting "github.com/virtualtam/sparklemuffin/pkg/feed/importing"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	userexporting "github.com/virtualtam/sparklemuffin/pkg/user/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 var _ http.Handler = &Server{}
@@ -47,21 +64,56 @@ type Server struct {
 	metricsPrefix   string
 	metricsRegistry *prometheus.Registry
 
+	// Security audit log service
+	auditService *audit.Service
+
 	// Bookmark services
 	bookmarkService          *bookmark.Service
 	bookmarkExportingService *bookmarkexporting.Service
 	bookmarkImportingService *bookmarkimporting.Service
 	bookmarkQueryingService  *bookmarkquerying.Service
 
+	// Bookmark link checking service
+	bookmarkCheckingService *bookmarkchecking.Service
+
+	// Bookmark metadata suggestion service
+	bookmarkSuggestingService *bookmarksuggesting.Service
+
+	// Bookmarked page archiving service
+	bookmarkArchivingService *bookmarkarchiving.Service
+
 	// Feed services
 	feedService          *feed.Service
 	feedExportingService *feedexporting.Service
 	feedImportingService *feedimporting.Service
 	feedQueryingService  *feedquerying.Service
 
+	// Instance-wide feed administration service
+	feedAdministratingService *feedadministrating.Service
+
+	// Feed synchronization metrics, displayed on the administration dashboard
+	feedSynchronizingCollector *feedsynchronizing.Collector
+
+	// Instance-wide statistics service
+	instanceService *instance.Service
+
 	// User and session management services
-	sessionService *session.Service
-	userService    *user.Service
+	lockoutService       *lockout.Service
+	passkeyService       *passkey.Service
+	passwordResetService *passwordreset.Service
+	quotaService         *quota.Service
+	registrationService  *registration.Service
+	sessionService       *session.Service
+	ssoService           *sso.Service
+	twoFactorService     *twofactor.Service
+	userService          *user.Service
+	userExportingService *userexporting.Service
+
+	// Saved search services
+	savedSearchService *savedsearch.Service
+
+	// Webhook services
+	webhookService *webhook.Service
 
 	homeView  *view.View
 	errorView *view.ErrorView
@@ -156,11 +208,13 @@ func (s *Server) registerHandlers() {
 
 	// Domain handlers
 	secure := s.publicURL.Scheme == "https"
-	controller.RegisterSessionHandlers(s.router, secure, s.sessionService, s.userService)
-	controller.RegisterAdminHandlers(s.router, s.sessionService, s.userService)
-	controller.RegisterAccountHandlers(s.router, s.feedService, s.sessionService, s.userService)
-	controller.RegisterBookmarkHandlers(s.router, s.publicURL, s.bookmarkService, s.bookmarkExportingService, s.bookmarkImportingService, s.bookmarkQueryingService, s.userService)
-	controller.RegisterFeedHandlers(s.router, s.feedService, s.feedExportingService, s.feedImportingService, s.feedQueryingService, s.userService)
+	controller.RegisterSessionHandlers(s.router, s.publicURL, secure, s.auditService, s.lockoutService, s.passkeyService, s.passwordResetService, s.registrationService, s.sessionService, s.ssoService, s.twoFactorService, s.userService)
+	controller.RegisterAdminHandlers(s.router, s.auditService, s.feedAdministratingService, s.feedSynchronizingCollector, s.instanceService, s.lockoutService, s.quotaService, s.sessionService, s.twoFactorService, s.userService)
+	controller.RegisterAccountHandlers(s.router, secure, s.bookmarkArchivingService, s.auditService, s.feedService, s.passkeyService, s.quotaService, s.sessionService, s.ssoService, s.twoFactorService, s.userService, s.userExportingService)
+	controller.RegisterBookmarkHandlers(s.router, s.publicURL, s.bookmarkArchivingService, s.auditService, s.bookmarkService, s.bookmarkCheckingService, s.bookmarkExportingService, s.bookmarkImportingService, s.bookmarkQueryingService, s.bookmarkSuggestingService, s.userService, s.webhookService)
+	controller.RegisterFeedHandlers(s.router, s.auditService, s.feedService, s.feedExportingService, s.feedImportingService, s.feedQueryingService, s.userService)
+	controller.RegisterSavedSearchHandlers(s.router, s.publicURL, s.bookmarkQueryingService, s.feedService, s.feedQueryingService, s.savedSearchService, s.userService)
+	controller.RegisterWebhookHandlers(s.router, s.feedQueryingService, s.webhookService)
 
 	// 404 handler
 	s.router.NotFound(s.handleNotFound())
@@ -226,8 +280,8 @@ func (s *Server) handleNotFound() func(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// rememberUser enriches the request context with a user.User if a valid
-// remember token cookie is set.
+// rememberUser enriches the request context with a user.User and their session.Session if a
+// valid remember token cookie is set, and records the session's activity.
 func (s *Server) rememberUser(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/robots.txt" || strings.HasPrefix(r.URL.Path, "/static") {
@@ -255,6 +309,11 @@ func (s *Server) rememberUser(h http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		if err := s.sessionService.UpdateLastSeen(ctx, userSession, chimiddleware.GetClientIP(ctx), r.UserAgent()); err != nil {
+			log.Error().Err(err).Msg("failed to update session activity")
+		}
+
+		ctx = httpcontext.WithSession(ctx, userSession)
 		ctx = httpcontext.WithUser(ctx, usr)
 		r = r.WithContext(ctx)
 
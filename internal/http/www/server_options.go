@@ -8,16 +8,33 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
 	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
 	bookmarkimporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/importing"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	bookmarksuggesting "github.com/virtualtam/sparklemuffin/pkg/bookmark/suggesting"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
 	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedimporting "github.com/virtualtam/sparklemuffin/pkg/feed/importing"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
 	"github.com/virtualtam/sparklemuffin/pkg/session"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
+	userexporting "github.com/virtualtam/sparklemuffin/pkg/user/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
 )
 
 // OptionFunc represents a function that configures a set of options for a Server.
@@ -67,6 +84,18 @@ func WithClientIpHeader(clientIpHeader string) OptionFunc {
 	}
 }
 
+// WithAuditService sets the security audit log service.
+func WithAuditService(auditService *audit.Service) OptionFunc {
+	return func(s *Server) error {
+		if auditService == nil {
+			return ErrServerAuditServiceRequired
+		}
+
+		s.auditService = auditService
+		return nil
+	}
+}
+
 // WithBookmarkServices sets the bookmark management services.
 func WithBookmarkServices(
 	bookmarkService *bookmark.Service,
@@ -96,6 +125,42 @@ func WithBookmarkServices(
 	}
 }
 
+// WithBookmarkCheckingService sets the bookmark link checking service.
+func WithBookmarkCheckingService(checkingService *bookmarkchecking.Service) OptionFunc {
+	return func(s *Server) error {
+		if checkingService == nil {
+			return ErrServerBookmarkCheckingServiceRequired
+		}
+
+		s.bookmarkCheckingService = checkingService
+		return nil
+	}
+}
+
+// WithBookmarkArchivingService sets the bookmarked page archiving service.
+func WithBookmarkArchivingService(archivingService *bookmarkarchiving.Service) OptionFunc {
+	return func(s *Server) error {
+		if archivingService == nil {
+			return ErrServerBookmarkArchivingServiceRequired
+		}
+
+		s.bookmarkArchivingService = archivingService
+		return nil
+	}
+}
+
+// WithBookmarkSuggestingService sets the bookmark metadata suggestion service.
+func WithBookmarkSuggestingService(suggestingService *bookmarksuggesting.Service) OptionFunc {
+	return func(s *Server) error {
+		if suggestingService == nil {
+			return ErrServerBookmarkSuggestingServiceRequired
+		}
+
+		s.bookmarkSuggestingService = suggestingService
+		return nil
+	}
+}
+
 // WithFeedServices sets the feed management services.
 func WithFeedServices(
 	feedService *feed.Service,
@@ -125,6 +190,110 @@ func WithFeedServices(
 	}
 }
 
+// WithFeedAdministratingService sets the instance-wide feed administration service.
+func WithFeedAdministratingService(feedAdministratingService *feedadministrating.Service) OptionFunc {
+	return func(s *Server) error {
+		if feedAdministratingService == nil {
+			return ErrServerFeedAdministratingServiceRequired
+		}
+
+		s.feedAdministratingService = feedAdministratingService
+		return nil
+	}
+}
+
+// WithFeedSynchronizingCollector sets the feed synchronization metrics collector.
+//
+// This option is not required; synchronization statistics are not displayed on the
+// administration dashboard if it is not set.
+func WithFeedSynchronizingCollector(feedSynchronizingCollector *feedsynchronizing.Collector) OptionFunc {
+	return func(s *Server) error {
+		s.feedSynchronizingCollector = feedSynchronizingCollector
+		return nil
+	}
+}
+
+// WithInstanceService sets the instance-wide statistics service.
+func WithInstanceService(instanceService *instance.Service) OptionFunc {
+	return func(s *Server) error {
+		if instanceService == nil {
+			return ErrServerInstanceServiceRequired
+		}
+
+		s.instanceService = instanceService
+		return nil
+	}
+}
+
+// WithLockoutService sets the login throttling and account lockout service.
+func WithLockoutService(lockoutService *lockout.Service) OptionFunc {
+	return func(s *Server) error {
+		if lockoutService == nil {
+			return ErrServerLockoutServiceRequired
+		}
+
+		s.lockoutService = lockoutService
+		return nil
+	}
+}
+
+// WithPasskeyService sets the WebAuthn passkey service.
+func WithPasskeyService(passkeyService *passkey.Service) OptionFunc {
+	return func(s *Server) error {
+		if passkeyService == nil {
+			return ErrServerPasskeyServiceRequired
+		}
+
+		s.passkeyService = passkeyService
+		return nil
+	}
+}
+
+// WithPasswordResetService sets the password reset service.
+//
+// This option is not required; the password reset pages are disabled if it is not set.
+func WithPasswordResetService(passwordResetService *passwordreset.Service) OptionFunc {
+	return func(s *Server) error {
+		s.passwordResetService = passwordResetService
+		return nil
+	}
+}
+
+// WithQuotaService sets the storage quota service.
+func WithQuotaService(quotaService *quota.Service) OptionFunc {
+	return func(s *Server) error {
+		if quotaService == nil {
+			return ErrServerQuotaServiceRequired
+		}
+
+		s.quotaService = quotaService
+		return nil
+	}
+}
+
+// WithRegistrationService sets the self-service registration and invitation service.
+//
+// This option is not required; the registration and invitation pages are disabled if it is
+// not set.
+func WithRegistrationService(registrationService *registration.Service) OptionFunc {
+	return func(s *Server) error {
+		s.registrationService = registrationService
+		return nil
+	}
+}
+
+// WithSavedSearchService sets the saved search management service.
+func WithSavedSearchService(savedSearchService *savedsearch.Service) OptionFunc {
+	return func(s *Server) error {
+		if savedSearchService == nil {
+			return ErrServerSavedSearchServiceRequired
+		}
+
+		s.savedSearchService = savedSearchService
+		return nil
+	}
+}
+
 // WithSessionService sets the user session management service.
 func WithSessionService(sessionService *session.Service) OptionFunc {
 	return func(s *Server) error {
@@ -137,6 +306,28 @@ func WithSessionService(sessionService *session.Service) OptionFunc {
 	}
 }
 
+// WithSSOService sets the OpenID Connect single sign-on service.
+//
+// This option is not required; single sign-on is disabled if it is not set.
+func WithSSOService(ssoService *sso.Service) OptionFunc {
+	return func(s *Server) error {
+		s.ssoService = ssoService
+		return nil
+	}
+}
+
+// WithTwoFactorService sets the two-factor authentication service.
+func WithTwoFactorService(twoFactorService *twofactor.Service) OptionFunc {
+	return func(s *Server) error {
+		if twoFactorService == nil {
+			return ErrServerTwoFactorServiceRequired
+		}
+
+		s.twoFactorService = twoFactorService
+		return nil
+	}
+}
+
 // WithUserService sets the user management service.
 func WithUserService(userService *user.Service) OptionFunc {
 	return func(s *Server) error {
@@ -148,3 +339,27 @@ func WithUserService(userService *user.Service) OptionFunc {
 		return nil
 	}
 }
+
+// WithUserExportingService sets the personal data exporting service.
+func WithUserExportingService(userExportingService *userexporting.Service) OptionFunc {
+	return func(s *Server) error {
+		if userExportingService == nil {
+			return ErrServerUserExportingServiceRequired
+		}
+
+		s.userExportingService = userExportingService
+		return nil
+	}
+}
+
+// WithWebhookService sets the user webhook management service.
+func WithWebhookService(webhookService *webhook.Service) OptionFunc {
+	return func(s *Server) error {
+		if webhookService == nil {
+			return ErrServerWebhookServiceRequired
+		}
+
+		s.webhookService = webhookService
+		return nil
+	}
+}
@@ -75,7 +75,7 @@ func TestServerRememberUser(t *testing.T) {
 			userRepository := &user.FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			userService := user.NewService(userRepository)
+			userService := user.NewService(userRepository, user.FakePasswordHasher(t))
 
 			publicURL, err := url.Parse("http://localhost:8080")
 			if err != nil {
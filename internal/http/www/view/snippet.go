@@ -0,0 +1,47 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package view
+
+import (
+	"html/template"
+	"strings"
+
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+// HighlightSnippet escapes a search snippet and highlights the terms it matches.
+//
+// Escaping leaves the highlight markers untouched, and they cannot be produced by escaping
+// page content, so only they turn into markup. Markers are stripped from page content when
+// it is indexed; stray markers, e.g. in content indexed beforehand, are dropped so that the
+// markup stays balanced.
+func HighlightSnippet(snippet string) template.HTML {
+	escaped := template.HTMLEscapeString(snippet)
+
+	var highlighted strings.Builder
+	var open bool
+
+	for _, r := range escaped {
+		switch string(r) {
+		case bookmarkquerying.SnippetHighlightStart:
+			if !open {
+				highlighted.WriteString("<mark>")
+				open = true
+			}
+		case bookmarkquerying.SnippetHighlightStop:
+			if open {
+				highlighted.WriteString("</mark>")
+				open = false
+			}
+		default:
+			highlighted.WriteRune(r)
+		}
+	}
+
+	if open {
+		highlighted.WriteString("</mark>")
+	}
+
+	return template.HTML(highlighted.String())
+}
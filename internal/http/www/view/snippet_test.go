@@ -0,0 +1,55 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package view
+
+import (
+	"html/template"
+	"testing"
+
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	cases := []struct {
+		tname   string
+		snippet string
+		want    template.HTML
+	}{
+		{
+			tname:   "empty",
+			snippet: "",
+			want:    "",
+		},
+		{
+			tname:   "no highlight",
+			snippet: "plain text",
+			want:    "plain text",
+		},
+		{
+			tname:   "highlighted terms",
+			snippet: "writing " + bookmarkquerying.SnippetHighlightStart + "tests" + bookmarkquerying.SnippetHighlightStop + " in Go",
+			want:    "writing <mark>tests</mark> in Go",
+		},
+		{
+			tname:   "markup is escaped",
+			snippet: "<script>alert(1)</script> " + bookmarkquerying.SnippetHighlightStart + "a < b" + bookmarkquerying.SnippetHighlightStop,
+			want:    "&lt;script&gt;alert(1)&lt;/script&gt; <mark>a &lt; b</mark>",
+		},
+		{
+			tname:   "stray markers",
+			snippet: bookmarkquerying.SnippetHighlightStop + "a " + bookmarkquerying.SnippetHighlightStart + bookmarkquerying.SnippetHighlightStart + "b" + bookmarkquerying.SnippetHighlightStop + bookmarkquerying.SnippetHighlightStop + " c " + bookmarkquerying.SnippetHighlightStart + "d",
+			want:    "a <mark>b</mark> c <mark>d</mark>",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := HighlightSnippet(tc.snippet)
+
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/http/www/httpcontext"
 	"github.com/virtualtam/sparklemuffin/internal/http/www/templates"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -56,12 +57,14 @@ func New(templateFiles ...string) *View {
 
 	t, err := template.New("base").
 		Funcs(template.FuncMap{
-			"Join":              strings.Join,
-			"MarkdownToHTML":    MarkdownToHTMLFunc(),
-			"mod":               func(i, j int) int { return i % j },
-			"dict":              dictFunc,
-			"toJSON":            toJSONFunc,
-			"MinPasswordLength": func() int { return user.MinPasswordLength },
+			"Join":                 strings.Join,
+			"HighlightSnippet":     HighlightSnippet,
+			"MarkdownToHTML":       MarkdownToHTMLFunc(),
+			"mod":                  func(i, j int) int { return i % j },
+			"dict":                 dictFunc,
+			"toJSON":               toJSONFunc,
+			"MinPasswordLength":    func() int { return user.MinPasswordLength },
+			"PasskeyNameMaxLength": func() int { return passkey.NameMaxLength },
 		}).
 		ParseFS(templates.FS, templateFiles...)
 
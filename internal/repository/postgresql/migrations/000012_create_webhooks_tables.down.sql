@@ -0,0 +1,15 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TYPE IF EXISTS webhook_delivery_status;
+
+DROP INDEX IF EXISTS idx_feed_entries_webhooks_pending; -- noqa: PG01
+DROP INDEX IF EXISTS idx_feed_entries_created_at; -- noqa: PG01
+
+ALTER TABLE feed_entries
+DROP COLUMN webhooks_queued;
+
+ALTER TABLE feed_entries
+DROP COLUMN created_at;
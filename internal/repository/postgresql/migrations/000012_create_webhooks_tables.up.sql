@@ -0,0 +1,68 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Track when entries are first stored, so that new entries can be detected
+-- independently of their (remote) publication date.
+ALTER TABLE feed_entries
+ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Track which entries have been considered for webhook deliveries, so that entries
+-- stored by transactions committing late are not missed; existing entries are
+-- considered as such, so that only entries stored from now on are delivered.
+ALTER TABLE feed_entries
+ADD COLUMN webhooks_queued BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE feed_entries
+ALTER COLUMN webhooks_queued SET DEFAULT FALSE;
+
+CREATE TYPE webhook_delivery_status AS ENUM(
+    'PENDING',
+    'SUCCEEDED',
+    'FAILED'
+);
+
+CREATE TABLE IF NOT EXISTS webhooks(
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    uuid                   UUID        UNIQUE   NOT NULL PRIMARY KEY, -- noqa: RF04
+    user_uuid              UUID        NOT NULL,
+    url                    TEXT        NOT NULL,
+    secret                 TEXT        NOT NULL,
+    events                 TEXT[]      NOT NULL,
+    feed_category_uuid     UUID,
+    feed_subscription_uuid UUID,
+    active                 BOOLEAN     NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_feed_category FOREIGN KEY(feed_category_uuid) REFERENCES feed_categories(uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_feed_subscription FOREIGN KEY(feed_subscription_uuid) REFERENCES feed_subscriptions(uuid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    created_at           TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    next_attempt_at      TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+
+    uuid                 UUID                    UNIQUE   NOT NULL PRIMARY KEY, -- noqa: RF04
+    webhook_uuid         UUID                    NOT NULL,
+    event                TEXT                    NOT NULL,
+    payload              JSONB                   NOT NULL,
+    status               webhook_delivery_status NOT NULL DEFAULT 'PENDING'::webhook_delivery_status,
+    attempts             INTEGER                 NOT NULL DEFAULT 0,
+    response_status_code INTEGER                 NOT NULL DEFAULT 0,
+    error                TEXT                    NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_webhook FOREIGN KEY(webhook_uuid) REFERENCES webhooks(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_pending -- noqa: PG01
+ON webhook_deliveries(next_attempt_at)
+WHERE status = 'PENDING'::webhook_delivery_status;
+
+CREATE INDEX idx_feed_entries_created_at -- noqa: PG01
+ON feed_entries(created_at);
+
+CREATE INDEX idx_feed_entries_webhooks_pending -- noqa: PG01
+ON feed_entries(created_at)
+WHERE NOT webhooks_queued;
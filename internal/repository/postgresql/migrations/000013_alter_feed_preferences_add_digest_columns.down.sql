@@ -0,0 +1,9 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+ALTER TABLE feed_preferences
+DROP COLUMN digest_sent_at,
+DROP COLUMN digest_mark_as_read,
+DROP COLUMN digest_frequency;
+
+DROP TYPE IF EXISTS feed_digest_frequency;
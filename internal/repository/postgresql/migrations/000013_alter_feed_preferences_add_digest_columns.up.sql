@@ -0,0 +1,13 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TYPE feed_digest_frequency AS ENUM(
+    'NONE',
+    'DAILY',
+    'WEEKLY'
+);
+
+ALTER TABLE feed_preferences
+ADD COLUMN digest_frequency feed_digest_frequency NOT NULL DEFAULT 'NONE'::feed_digest_frequency,
+ADD COLUMN digest_mark_as_read BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN digest_sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
@@ -0,0 +1,4 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP TABLE IF EXISTS password_reset_tokens;
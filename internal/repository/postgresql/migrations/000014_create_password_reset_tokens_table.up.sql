@@ -0,0 +1,15 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    token_hash TEXT        UNIQUE   NOT NULL PRIMARY KEY,
+    user_uuid  UUID        NOT NULL,
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_reset_tokens_user_uuid -- noqa: PG01
+ON password_reset_tokens(user_uuid);
@@ -0,0 +1,24 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS two_factor_totp(
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_uuid      UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    secret         TEXT        NOT NULL,
+    enabled        BOOLEAN     NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_uuid  UUID        NOT NULL,
+    code_hash  TEXT        NOT NULL,
+
+    CONSTRAINT pk_user_recovery_code PRIMARY KEY(user_uuid, code_hash),
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
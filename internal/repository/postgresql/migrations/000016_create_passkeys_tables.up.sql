@@ -0,0 +1,27 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS passkeys(
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at  TIMESTAMPTZ,
+
+    uuid          UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    user_uuid     UUID        NOT NULL,
+    name          TEXT        NOT NULL,
+    credential_id BYTEA       UNIQUE   NOT NULL,
+    credential    JSONB       NOT NULL,
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_passkeys_user_uuid -- noqa: PG01
+ON passkeys(user_uuid);
+
+CREATE TABLE IF NOT EXISTS passkey_ceremonies(
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+
+    token_hash   TEXT        UNIQUE   NOT NULL PRIMARY KEY,
+    session_data JSONB       NOT NULL
+);
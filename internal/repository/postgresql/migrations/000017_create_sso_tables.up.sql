@@ -0,0 +1,25 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS sso_identities(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    issuer     TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    user_uuid  UUID        NOT NULL,
+
+    PRIMARY KEY(issuer, subject),
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sso_identities_user_uuid -- noqa: PG01
+ON sso_identities(user_uuid);
+
+CREATE TABLE IF NOT EXISTS sso_authorization_requests(
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+
+    state_hash    TEXT        UNIQUE   NOT NULL PRIMARY KEY,
+    code_verifier TEXT        NOT NULL,
+    nonce         TEXT        NOT NULL
+);
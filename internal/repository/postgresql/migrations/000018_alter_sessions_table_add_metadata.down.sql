@@ -0,0 +1,11 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP INDEX IF EXISTS idx_sessions_user_uuid; -- noqa: PG01
+
+ALTER TABLE sessions
+DROP COLUMN last_seen_at,
+DROP COLUMN created_at,
+DROP COLUMN user_agent,
+DROP COLUMN client_ip,
+DROP COLUMN uuid;
@@ -0,0 +1,12 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+ALTER TABLE sessions
+ADD COLUMN uuid         UUID        UNIQUE   NOT NULL DEFAULT gen_random_uuid(),
+ADD COLUMN client_ip    TEXT        NOT NULL DEFAULT '',
+ADD COLUMN user_agent   TEXT        NOT NULL DEFAULT '',
+ADD COLUMN created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_sessions_user_uuid -- noqa: PG01
+ON sessions(user_uuid);
@@ -0,0 +1,39 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Audit events reference users without a foreign key constraint, so that the
+-- history is kept when users are deleted.
+CREATE TABLE IF NOT EXISTS audit_events(
+    uuid        UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    type        TEXT        NOT NULL,
+    actor_uuid  UUID,
+    target_uuid UUID,
+    client_ip   TEXT        NOT NULL DEFAULT '',
+    details     TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_audit_events_created_at -- noqa: PG01
+ON audit_events(created_at);
+
+CREATE INDEX idx_audit_events_actor_uuid -- noqa: PG01
+ON audit_events(actor_uuid);
+
+CREATE INDEX idx_audit_events_target_uuid -- noqa: PG01
+ON audit_events(target_uuid);
+
+-- The audit log is append-only.
+CREATE FUNCTION audit_events_reject_changes()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$;
+
+CREATE TRIGGER trg_audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW
+EXECUTE FUNCTION audit_events_reject_changes();
@@ -0,0 +1,32 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS registration_invitations(
+    uuid            UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+
+    created_by_uuid UUID        NOT NULL,
+    token_hash      TEXT        UNIQUE   NOT NULL,
+    max_uses        INTEGER     NOT NULL CHECK (max_uses > 0),
+    use_count       INTEGER     NOT NULL DEFAULT 0 CHECK (use_count >= 0),
+
+    CONSTRAINT fk_user FOREIGN KEY(created_by_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_registration_invitations_created_by_uuid -- noqa: PG01
+ON registration_invitations(created_by_uuid);
+
+-- Accounts awaiting the confirmation of their email address; the user is only created
+-- once the address is confirmed.
+CREATE TABLE IF NOT EXISTS registration_pending(
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+
+    token_hash    TEXT        UNIQUE   NOT NULL PRIMARY KEY,
+    user_uuid     UUID        UNIQUE   NOT NULL,
+    email         TEXT        UNIQUE   NOT NULL,
+    nick_name     TEXT        NOT NULL,
+    display_name  TEXT        NOT NULL,
+    password_hash TEXT        NOT NULL
+);
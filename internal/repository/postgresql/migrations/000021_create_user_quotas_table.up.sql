@@ -0,0 +1,15 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Per-user limits set by administrators; a NULL limit means the instance-wide default
+-- applies, and 0 means the resource is unlimited.
+CREATE TABLE IF NOT EXISTS user_quotas(
+    user_uuid            UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    max_bookmarks        BIGINT      CHECK (max_bookmarks >= 0),
+    max_subscriptions    BIGINT      CHECK (max_subscriptions >= 0),
+    max_import_file_size BIGINT      CHECK (max_import_file_size >= 0),
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
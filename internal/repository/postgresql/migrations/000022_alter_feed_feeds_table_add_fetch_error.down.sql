@@ -0,0 +1,6 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+ALTER TABLE feed_feeds
+DROP COLUMN fetch_error_at,
+DROP COLUMN fetch_error;
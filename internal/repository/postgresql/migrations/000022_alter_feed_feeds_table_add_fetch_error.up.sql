@@ -0,0 +1,7 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Last error encountered when fetching a feed; cleared on the next successful fetch.
+ALTER TABLE feed_feeds
+ADD COLUMN fetch_error    TEXT        NOT NULL DEFAULT '',
+ADD COLUMN fetch_error_at TIMESTAMPTZ;
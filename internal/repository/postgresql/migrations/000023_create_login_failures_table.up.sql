@@ -0,0 +1,18 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Failed login attempts reference the submitted email address rather than a
+-- user, so that attempts against unknown accounts are throttled as well.
+CREATE TABLE IF NOT EXISTS login_failures(
+    uuid       UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    email      TEXT        NOT NULL,
+    client_ip  TEXT        NOT NULL
+);
+
+CREATE INDEX idx_login_failures_email_created_at -- noqa: PG01
+ON login_failures(email, created_at);
+
+CREATE INDEX idx_login_failures_client_ip_created_at -- noqa: PG01
+ON login_failures(client_ip, created_at);
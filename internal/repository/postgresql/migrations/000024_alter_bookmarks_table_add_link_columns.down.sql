@@ -0,0 +1,13 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP INDEX IF EXISTS idx_bookmarks_link_checked_at; -- noqa: PG01
+DROP INDEX IF EXISTS idx_bookmarks_user_uuid_link_status; -- noqa: PG01
+
+ALTER TABLE bookmarks
+DROP COLUMN link_checked_at,
+DROP COLUMN link_not_found_count,
+DROP COLUMN link_error,
+DROP COLUMN link_redirect_url,
+DROP COLUMN link_status_code,
+DROP COLUMN link_status;
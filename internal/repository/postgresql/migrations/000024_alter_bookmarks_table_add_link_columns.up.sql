@@ -0,0 +1,20 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Result of the last check of a bookmark's URL; reset when the URL is changed.
+--
+-- link_not_found_count is the number of consecutive checks for which the URL was not found;
+-- links are only considered dead after several such checks.
+ALTER TABLE bookmarks
+ADD COLUMN link_status          TEXT        NOT NULL DEFAULT 'unchecked',
+ADD COLUMN link_status_code     INTEGER     NOT NULL DEFAULT 0,
+ADD COLUMN link_redirect_url    TEXT        NOT NULL DEFAULT '',
+ADD COLUMN link_error           TEXT        NOT NULL DEFAULT '',
+ADD COLUMN link_not_found_count INTEGER     NOT NULL DEFAULT 0,
+ADD COLUMN link_checked_at      TIMESTAMPTZ;
+
+CREATE INDEX idx_bookmarks_user_uuid_link_status -- noqa: PG01
+ON bookmarks(user_uuid, link_status);
+
+CREATE INDEX idx_bookmarks_link_checked_at -- noqa: PG01
+ON bookmarks(link_checked_at NULLS FIRST);
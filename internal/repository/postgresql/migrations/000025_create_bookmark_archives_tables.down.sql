@@ -0,0 +1,8 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP INDEX IF EXISTS idx_bookmark_archives_content_hash; -- noqa: PG01
+DROP INDEX IF EXISTS idx_bookmark_archives_pending; -- noqa: PG01
+
+DROP TABLE IF EXISTS bookmark_archives;
+DROP TABLE IF EXISTS bookmark_archive_blobs;
@@ -0,0 +1,35 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Content-addressed storage for page snapshots, used unless archives are stored on disk.
+CREATE TABLE IF NOT EXISTS bookmark_archive_blobs(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    hash       TEXT        UNIQUE   NOT NULL PRIMARY KEY, -- noqa: RF04
+    content    BYTEA       NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_archives(
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archived_at  TIMESTAMPTZ,
+
+    bookmark_uid TEXT        UNIQUE   NOT NULL PRIMARY KEY,
+    user_uuid    UUID        NOT NULL,
+    url          TEXT        NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'pending',
+    content_hash TEXT        NOT NULL DEFAULT '',
+    size_bytes   BIGINT      NOT NULL DEFAULT 0,
+    error        TEXT        NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_bookmark FOREIGN KEY(bookmark_uid) REFERENCES bookmarks(uid) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bookmark_archives_pending -- noqa: PG01
+ON bookmark_archives(updated_at)
+WHERE status = 'pending';
+
+-- Used to find page snapshots that are no longer referenced by any archive.
+CREATE INDEX idx_bookmark_archives_content_hash -- noqa: PG01
+ON bookmark_archives(content_hash);
@@ -0,0 +1,13 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP INDEX IF EXISTS idx_bookmarks_search_tsv; -- noqa: PG01
+
+CREATE INDEX idx_bookmarks_fulltextsearch_tsv -- noqa: PG01
+ON bookmarks
+USING gin(fulltextsearch_tsv);
+
+ALTER TABLE bookmarks
+DROP COLUMN search_tsv,
+DROP COLUMN content_tsv,
+DROP COLUMN content_text;
@@ -0,0 +1,17 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+-- Readable text of the archived page, indexed with a lower weight than the bookmark's own fields.
+ALTER TABLE bookmarks
+ADD COLUMN content_text TEXT     NOT NULL DEFAULT '',
+ADD COLUMN content_tsv  TSVECTOR NOT NULL DEFAULT '';
+
+ALTER TABLE bookmarks
+ADD COLUMN search_tsv TSVECTOR
+GENERATED ALWAYS AS (setweight(COALESCE(fulltextsearch_tsv, ''), 'A') || content_tsv) STORED;
+
+DROP INDEX IF EXISTS idx_bookmarks_fulltextsearch_tsv; -- noqa: PG01
+
+CREATE INDEX idx_bookmarks_search_tsv -- noqa: PG01
+ON bookmarks
+USING gin(search_tsv);
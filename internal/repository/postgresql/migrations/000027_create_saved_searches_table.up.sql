@@ -0,0 +1,18 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS saved_searches(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    uuid       UUID        UNIQUE   NOT NULL PRIMARY KEY, -- noqa: RF04
+    user_uuid  UUID        NOT NULL,
+    domain     TEXT        NOT NULL,
+    name       TEXT        NOT NULL,
+    slug       TEXT        NOT NULL,
+    query      TEXT        NOT NULL,
+    public     BOOLEAN     NOT NULL DEFAULT FALSE,
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE,
+    CONSTRAINT saved_searches_user_domain_slug_unique UNIQUE(user_uuid, domain, slug)
+);
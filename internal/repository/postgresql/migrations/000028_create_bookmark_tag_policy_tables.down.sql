@@ -0,0 +1,5 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+DROP TABLE IF EXISTS bookmark_tag_aliases;
+DROP TABLE IF EXISTS bookmark_tag_preferences;
@@ -0,0 +1,22 @@
+-- Copyright VirtualTam 2022, 2026
+-- SPDX-License-Identifier: MIT
+
+CREATE TABLE IF NOT EXISTS bookmark_tag_preferences(
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_uuid    UUID        UNIQUE   NOT NULL PRIMARY KEY,
+    case_folding TEXT        NOT NULL DEFAULT 'PRESERVE',
+
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_tag_aliases(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_uuid  UUID        NOT NULL,
+    alias      TEXT        NOT NULL,
+    tag        TEXT        NOT NULL,
+
+    CONSTRAINT pk_user_alias PRIMARY KEY(user_uuid, alias),
+    CONSTRAINT fk_user FOREIGN KEY(user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
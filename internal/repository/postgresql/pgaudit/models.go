@@ -0,0 +1,44 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgaudit
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+)
+
+type DBEvent struct {
+	UUID           string    `db:"uuid"`
+	Type           string    `db:"type"`
+	ActorUUID      *string   `db:"actor_uuid"`
+	ActorNickName  *string   `db:"actor_nick_name"`
+	TargetUUID     *string   `db:"target_uuid"`
+	TargetNickName *string   `db:"target_nick_name"`
+	ClientIP       string    `db:"client_ip"`
+	Details        string    `db:"details"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (e *DBEvent) asEvent() audit.Event {
+	return audit.Event{
+		UUID:           e.UUID,
+		Type:           audit.EventType(e.Type),
+		ActorUUID:      valueOrEmpty(e.ActorUUID),
+		ActorNickName:  valueOrEmpty(e.ActorNickName),
+		TargetUUID:     valueOrEmpty(e.TargetUUID),
+		TargetNickName: valueOrEmpty(e.TargetNickName),
+		ClientIP:       e.ClientIP,
+		Details:        e.Details,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,155 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgaudit
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+)
+
+var _ audit.Repository = &Repository{}
+
+const (
+	domain = "audit"
+
+	// filterClause matches audit events against an audit.Filter; NULL arguments are ignored.
+	filterClause = `
+	WHERE (@user_uuid::UUID IS NULL OR ae.actor_uuid=@user_uuid OR ae.target_uuid=@user_uuid)
+	AND   (@type::TEXT IS NULL OR ae.type=@type)
+	AND   (@since::TIMESTAMPTZ IS NULL OR ae.created_at>=@since)
+	AND   (@until::TIMESTAMPTZ IS NULL OR ae.created_at<=@until)`
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for the security audit log.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) EventAdd(ctx context.Context, event audit.Event) error {
+	query := `
+	INSERT INTO audit_events(
+		uuid,
+		type,
+		actor_uuid,
+		target_uuid,
+		client_ip,
+		details,
+		created_at
+	)
+	VALUES(
+		@uuid,
+		@type,
+		@actor_uuid,
+		@target_uuid,
+		@client_ip,
+		@details,
+		@created_at
+	)`
+
+	args := pgx.NamedArgs{
+		"uuid":        event.UUID,
+		"type":        string(event.Type),
+		"actor_uuid":  nullIfEmpty(event.ActorUUID),
+		"target_uuid": nullIfEmpty(event.TargetUUID),
+		"client_ip":   event.ClientIP,
+		"details":     event.Details,
+		"created_at":  event.CreatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "EventAdd", query, args)
+}
+
+func (r *Repository) EventGetCount(ctx context.Context, filter audit.Filter) (uint, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM audit_events ae` + filterClause
+
+	var count uint
+
+	if err := r.Pool.QueryRow(ctx, query, filterArgs(filter)).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) EventGetN(ctx context.Context, filter audit.Filter, n uint, offset uint) ([]audit.Event, error) {
+	query := `
+	SELECT
+		ae.uuid,
+		ae.type,
+		ae.actor_uuid,
+		actor.nick_name AS actor_nick_name,
+		ae.target_uuid,
+		target.nick_name AS target_nick_name,
+		ae.client_ip,
+		ae.details,
+		ae.created_at
+	FROM audit_events ae
+	LEFT JOIN users actor  ON actor.uuid=ae.actor_uuid
+	LEFT JOIN users target ON target.uuid=ae.target_uuid` + filterClause + `
+	ORDER BY ae.created_at DESC
+	LIMIT @limit OFFSET @offset`
+
+	args := filterArgs(filter)
+	args["limit"] = n
+	args["offset"] = offset
+
+	rows, err := r.Pool.Query(ctx, query, args)
+	if err != nil {
+		return []audit.Event{}, err
+	}
+	defer rows.Close()
+
+	var dbEvents []DBEvent
+
+	if err := pgxscan.ScanAll(&dbEvents, rows); err != nil {
+		return []audit.Event{}, err
+	}
+
+	events := make([]audit.Event, len(dbEvents))
+	for index, dbEvent := range dbEvents {
+		events[index] = dbEvent.asEvent()
+	}
+
+	return events, nil
+}
+
+func filterArgs(filter audit.Filter) pgx.NamedArgs {
+	args := pgx.NamedArgs{
+		"user_uuid": nullIfEmpty(filter.UserUUID),
+		"type":      nullIfEmpty(string(filter.Type)),
+		"since":     nil,
+		"until":     nil,
+	}
+
+	if !filter.Since.IsZero() {
+		args["since"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		args["until"] = filter.Until
+	}
+
+	return args
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
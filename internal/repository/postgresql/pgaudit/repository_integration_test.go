@@ -0,0 +1,185 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgaudit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgaudit"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/audit"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+
+	var testUsers []user.User
+	for range 2 {
+		u := user.FakeUser(t, &fake)
+		if err := us.Add(t.Context(), u); err != nil {
+			t.Fatalf("failed to create user: %q", err)
+		}
+
+		testUser, err := us.ByNickName(t.Context(), u.NickName)
+		if err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+
+		testUsers = append(testUsers, testUser)
+	}
+
+	admin, member := testUsers[0], testUsers[1]
+
+	r := pgaudit.NewRepository(pool)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	events := []audit.Event{
+		{
+			UUID:      "0c5e0d3b-7d62-4f3e-9d4a-2b1c8e7f6a50",
+			Type:      audit.EventLoginFailed,
+			ClientIP:  "192.0.2.1",
+			Details:   "unknown@example.org",
+			CreatedAt: now.Add(-3 * time.Hour),
+		},
+		{
+			UUID:       "1d6f1e4c-8e73-4a4f-8e5b-3c2d9f8a7b61",
+			Type:       audit.EventLoginSucceeded,
+			ActorUUID:  member.UUID,
+			TargetUUID: member.UUID,
+			ClientIP:   "192.0.2.2",
+			CreatedAt:  now.Add(-2 * time.Hour),
+		},
+		{
+			UUID:       "2e7a2f5d-9f84-4b5a-9f6c-4d3e0a9b8c72",
+			Type:       audit.EventAdminUserUpdated,
+			ActorUUID:  admin.UUID,
+			TargetUUID: member.UUID,
+			ClientIP:   "192.0.2.3",
+			CreatedAt:  now.Add(-1 * time.Hour),
+		},
+	}
+
+	for _, event := range events {
+		if err := r.EventAdd(t.Context(), event); err != nil {
+			t.Fatalf("failed to add event: %q", err)
+		}
+	}
+
+	t.Run("all events", func(t *testing.T) {
+		count, err := r.EventGetCount(t.Context(), audit.Filter{})
+		if err != nil {
+			t.Fatalf("failed to count events: %q", err)
+		}
+		if count != 3 {
+			t.Errorf("want 3 events, got %d", count)
+		}
+
+		got, err := r.EventGetN(t.Context(), audit.Filter{}, 10, 0)
+		if err != nil {
+			t.Fatalf("failed to retrieve events: %q", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("want 3 events, got %d", len(got))
+		}
+
+		latest := got[0]
+		if latest.UUID != events[2].UUID {
+			t.Errorf("want most recent event %q, got %q", events[2].UUID, latest.UUID)
+		}
+		if latest.ActorNickName != admin.NickName {
+			t.Errorf("want actor nickname %q, got %q", admin.NickName, latest.ActorNickName)
+		}
+		if latest.TargetNickName != member.NickName {
+			t.Errorf("want target nickname %q, got %q", member.NickName, latest.TargetNickName)
+		}
+		if !latest.CreatedAt.Equal(events[2].CreatedAt) {
+			t.Errorf("want creation date %s, got %s", events[2].CreatedAt, latest.CreatedAt)
+		}
+
+		anonymous := got[2]
+		if anonymous.ActorUUID != "" || anonymous.TargetUUID != "" {
+			t.Errorf("want no actor nor target, got %q and %q", anonymous.ActorUUID, anonymous.TargetUUID)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		cases := []struct {
+			tname     string
+			filter    audit.Filter
+			wantCount uint
+		}{
+			{
+				tname:     "by actor or target",
+				filter:    audit.Filter{UserUUID: member.UUID},
+				wantCount: 2,
+			},
+			{
+				tname:     "by type",
+				filter:    audit.Filter{Type: audit.EventLoginFailed},
+				wantCount: 1,
+			},
+			{
+				tname: "by date range",
+				filter: audit.Filter{
+					Since: now.Add(-150 * time.Minute),
+					Until: now.Add(-90 * time.Minute),
+				},
+				wantCount: 1,
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.tname, func(t *testing.T) {
+				count, err := r.EventGetCount(t.Context(), tc.filter)
+				if err != nil {
+					t.Fatalf("failed to count events: %q", err)
+				}
+				if count != tc.wantCount {
+					t.Errorf("want %d events, got %d", tc.wantCount, count)
+				}
+
+				got, err := r.EventGetN(t.Context(), tc.filter, 10, 0)
+				if err != nil {
+					t.Fatalf("failed to retrieve events: %q", err)
+				}
+				if uint(len(got)) != tc.wantCount {
+					t.Errorf("want %d events, got %d", tc.wantCount, len(got))
+				}
+			})
+		}
+	})
+
+	t.Run("append-only", func(t *testing.T) {
+		if _, err := pool.Exec(t.Context(), "DELETE FROM audit_events"); err == nil {
+			t.Error("want deleting events to fail")
+		}
+		if _, err := pool.Exec(t.Context(), "UPDATE audit_events SET details=''"); err == nil {
+			t.Error("want updating events to fail")
+		}
+	})
+
+	t.Run("events are kept when users are deleted", func(t *testing.T) {
+		if err := us.DeleteByUUID(t.Context(), member.UUID); err != nil {
+			t.Fatalf("failed to delete user: %q", err)
+		}
+
+		count, err := r.EventGetCount(t.Context(), audit.Filter{UserUUID: member.UUID})
+		if err != nil {
+			t.Fatalf("failed to count events: %q", err)
+		}
+		if count != 2 {
+			t.Errorf("want 2 events, got %d", count)
+		}
+	})
+}
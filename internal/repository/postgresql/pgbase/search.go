@@ -0,0 +1,19 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgbase
+
+import "regexp"
+
+// SiteURLPatterns returns case-insensitive regular expressions matching URLs whose host is
+// one of the given domain names, or one of their subdomains, for use with PostgreSQL's
+// `url ~* ANY(patterns)` operator.
+func SiteURLPatterns(sites []string) []string {
+	patterns := make([]string, len(sites))
+
+	for i, site := range sites {
+		patterns[i] = `^[a-z][a-z0-9+.-]*://([^/?#@]*@)?([^/?#:@]*\.)?` + regexp.QuoteMeta(site) + `([:/?#]|$)`
+	}
+
+	return patterns
+}
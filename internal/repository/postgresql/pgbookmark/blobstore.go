@@ -0,0 +1,96 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgbookmark
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+)
+
+var _ bookmarkarchiving.BlobStore = &ArchiveBlobStore{}
+
+// ArchiveBlobStore stores bookmarked page snapshots in PostgreSQL.
+type ArchiveBlobStore struct {
+	*pgbase.Repository
+}
+
+// NewArchiveBlobStore initializes and returns a PostgreSQL ArchiveBlobStore.
+func NewArchiveBlobStore(pool *pgxpool.Pool) *ArchiveBlobStore {
+	return &ArchiveBlobStore{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (s *ArchiveBlobStore) BlobDelete(ctx context.Context, hash string) error {
+	query := `
+	DELETE FROM bookmark_archive_blobs
+	WHERE hash=@hash`
+
+	args := pgx.NamedArgs{
+		"hash": hash,
+	}
+
+	return s.QueryTx(ctx, domain, "BlobDelete", query, args)
+}
+
+func (s *ArchiveBlobStore) BlobGet(ctx context.Context, hash string) ([]byte, error) {
+	query := `
+	SELECT content
+	FROM  bookmark_archive_blobs
+	WHERE hash=$1`
+
+	var content []byte
+
+	err := s.Pool.QueryRow(ctx, query, hash).Scan(&content)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, bookmarkarchiving.ErrArchiveNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+func (s *ArchiveBlobStore) BlobHashes(ctx context.Context) ([]string, error) {
+	query := `
+	SELECT hash
+	FROM  bookmark_archive_blobs
+	ORDER BY hash`
+
+	rows, err := s.Pool.Query(ctx, query)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+
+	if err := pgxscan.ScanAll(&hashes, rows); err != nil {
+		return []string{}, err
+	}
+
+	return hashes, nil
+}
+
+func (s *ArchiveBlobStore) BlobSave(ctx context.Context, hash string, content []byte) error {
+	query := `
+	INSERT INTO bookmark_archive_blobs(hash, content)
+	VALUES(@hash, @content)
+	ON CONFLICT (hash) DO NOTHING`
+
+	args := pgx.NamedArgs{
+		"hash":    hash,
+		"content": content,
+	}
+
+	return s.QueryTx(ctx, domain, "BlobSave", query, args)
+}
@@ -9,6 +9,7 @@ import (
 	"slices"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/jaswdr/faker/v2"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbookmark"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -55,10 +57,10 @@ func generateUniqueSortedTags(fake *faker.Faker, nTags int) []string {
 func TestBookmarkService(t *testing.T) {
 	pool := pgbase.CreateAndMigrateTestDatabase(t)
 	r := pgbookmark.NewRepository(pool)
-	bs := bookmark.NewService(r)
+	bs := bookmark.NewService(r, nil)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	fake := faker.New()
 
@@ -319,4 +321,454 @@ func TestBookmarkService(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("update and delete hierarchical tags", func(t *testing.T) {
+		ctx := t.Context()
+
+		nBookmarks := 5
+
+		for range nBookmarks {
+			bkm := bookmark.Bookmark{
+				UserUUID:    testUser.UUID,
+				URL:         fake.Internet().URL(),
+				Title:       fake.Lorem().Sentence(5),
+				Description: fake.Lorem().Text(500),
+				Tags:        []string{"dev", "dev/go", "dev/go/testing", "devops"},
+			}
+
+			if err := bs.Add(ctx, bkm); err != nil {
+				t.Fatalf("failed to create bookmark: %q", err)
+			}
+		}
+
+		descendants, err := r.BookmarkGetByTagWithDescendants(ctx, testUser.UUID, "dev/go")
+		if err != nil {
+			t.Fatalf("failed to retrieve bookmarks: %q", err)
+		}
+
+		if len(descendants) != nBookmarks {
+			t.Errorf("want %d bookmarks, got %d", nBookmarks, len(descendants))
+		}
+
+		uq := bookmark.TagUpdateQuery{
+			UserUUID:    testUser.UUID,
+			CurrentName: "dev",
+			NewName:     "code",
+			Cascade:     true,
+		}
+
+		got, err := bs.UpdateTag(ctx, uq)
+		if err != nil {
+			t.Fatalf("failed to update tag: %q", err)
+		}
+
+		if got != int64(nBookmarks) {
+			t.Errorf("want %d updated bookmarks, got %d", nBookmarks, got)
+		}
+
+		allBookmarks, err := bs.All(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve all bookmarks: %q", err)
+		}
+
+		wantTags := []string{"code", "code/go", "code/go/testing", "devops"}
+
+		for i, b := range allBookmarks {
+			if !slices.Equal(b.Tags, wantTags) {
+				t.Errorf("want bookmark %d to have tags %q, got %q", i, wantTags, b.Tags)
+			}
+		}
+
+		dq := bookmark.TagDeleteQuery{
+			UserUUID: testUser.UUID,
+			Name:     "code/go",
+			Cascade:  true,
+		}
+
+		got, err = bs.DeleteTag(ctx, dq)
+		if err != nil {
+			t.Fatalf("failed to delete tag: %q", err)
+		}
+
+		if got != int64(nBookmarks) {
+			t.Errorf("want %d updated bookmarks, got %d", nBookmarks, got)
+		}
+
+		allBookmarks, err = bs.All(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve all bookmarks: %q", err)
+		}
+
+		wantTags = []string{"code", "devops"}
+
+		for i, b := range allBookmarks {
+			if !slices.Equal(b.Tags, wantTags) {
+				t.Errorf("want bookmark %d to have tags %q, got %q", i, wantTags, b.Tags)
+			}
+		}
+
+		for _, b := range allBookmarks {
+			if err := bs.Delete(ctx, testUser.UUID, b.UID); err != nil {
+				t.Fatalf("failed to delete bookmark: %q", err)
+			}
+		}
+	})
+
+	t.Run("merge tags, aliases and case folding", func(t *testing.T) {
+		ctx := t.Context()
+
+		nBookmarks := 3
+
+		for range nBookmarks {
+			bkm := bookmark.Bookmark{
+				UserUUID: testUser.UUID,
+				URL:      fake.Internet().URL(),
+				Title:    fake.Lorem().Sentence(5),
+				Tags:     []string{"Go", "golang", "golang/testing"},
+			}
+
+			if err := bs.Add(ctx, bkm); err != nil {
+				t.Fatalf("failed to create bookmark: %q", err)
+			}
+		}
+
+		mq := bookmark.TagMergeQuery{
+			UserUUID:    testUser.UUID,
+			Sources:     []string{"Go", "golang"},
+			Target:      "go",
+			KeepAliases: true,
+		}
+
+		got, err := bs.MergeTags(ctx, mq)
+		if err != nil {
+			t.Fatalf("failed to merge tags: %q", err)
+		}
+
+		if got != int64(nBookmarks) {
+			t.Errorf("want %d updated bookmarks, got %d", nBookmarks, got)
+		}
+
+		aliases, err := bs.TagAliases(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve tag aliases: %q", err)
+		}
+
+		if len(aliases) != 2 {
+			t.Fatalf("want 2 tag aliases, got %d", len(aliases))
+		}
+
+		if aliases[0].Alias != "Go" || aliases[1].Alias != "golang" {
+			t.Errorf("want aliases Go and golang, got %q and %q", aliases[0].Alias, aliases[1].Alias)
+		}
+
+		preferences := bookmark.TagPreferences{
+			UserUUID:    testUser.UUID,
+			CaseFolding: bookmark.TagCaseFoldingLowercase,
+		}
+
+		if err := bs.UpdateTagPreferences(ctx, preferences); err != nil {
+			t.Fatalf("failed to update tag preferences: %q", err)
+		}
+
+		gotPreferences, err := bs.TagPreferencesByUserUUID(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve tag preferences: %q", err)
+		}
+
+		if gotPreferences.CaseFolding != bookmark.TagCaseFoldingLowercase {
+			t.Errorf("want case folding %q, got %q", bookmark.TagCaseFoldingLowercase, gotPreferences.CaseFolding)
+		}
+
+		aliasedBookmark := bookmark.Bookmark{
+			UserUUID: testUser.UUID,
+			URL:      fake.Internet().URL(),
+			Title:    fake.Lorem().Sentence(5),
+			Tags:     []string{"GoLang/Generics", "Rust"},
+		}
+
+		if err := bs.Add(ctx, aliasedBookmark); err != nil {
+			t.Fatalf("failed to create bookmark: %q", err)
+		}
+
+		allBookmarks, err := bs.All(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve all bookmarks: %q", err)
+		}
+
+		for i, b := range allBookmarks {
+			wantTags := []string{"go", "golang/testing"}
+			if b.URL == aliasedBookmark.URL {
+				wantTags = []string{"go/generics", "rust"}
+			}
+
+			if !slices.Equal(b.Tags, wantTags) {
+				t.Errorf("want bookmark %d to have tags %q, got %q", i, wantTags, b.Tags)
+			}
+		}
+
+		if err := bs.DeleteTagAlias(ctx, testUser.UUID, "golang"); err != nil {
+			t.Fatalf("failed to delete tag alias: %q", err)
+		}
+
+		aliases, err = bs.TagAliases(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve tag aliases: %q", err)
+		}
+
+		if len(aliases) != 1 {
+			t.Errorf("want 1 tag alias, got %d", len(aliases))
+		}
+
+		preferences.CaseFolding = bookmark.TagCaseFoldingPreserve
+
+		if err := bs.UpdateTagPreferences(ctx, preferences); err != nil {
+			t.Fatalf("failed to update tag preferences: %q", err)
+		}
+
+		for _, b := range allBookmarks {
+			if err := bs.Delete(ctx, testUser.UUID, b.UID); err != nil {
+				t.Fatalf("failed to delete bookmark: %q", err)
+			}
+		}
+	})
+
+	t.Run("bulk edit", func(t *testing.T) {
+		ctx := t.Context()
+
+		nBookmarks := 3
+
+		for range nBookmarks {
+			bkm := bookmark.Bookmark{
+				UserUUID: testUser.UUID,
+				URL:      fake.Internet().URL(),
+				Title:    fake.Lorem().Sentence(5),
+				Tags:     []string{"bulk"},
+			}
+
+			if err := bs.Add(ctx, bkm); err != nil {
+				t.Fatalf("failed to create bookmark: %q", err)
+			}
+		}
+
+		allBookmarks, err := bs.All(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve all bookmarks: %q", err)
+		}
+
+		var uids []string
+		for _, b := range allBookmarks {
+			uids = append(uids, b.UID)
+		}
+
+		addTagsQuery := bookmark.BulkEditQuery{
+			UserUUID: testUser.UUID,
+			UIDs:     uids[:2],
+			Action:   bookmark.BulkEditActionAddTags,
+			Tags:     []string{"selected"},
+		}
+
+		summary, err := bs.BulkEdit(ctx, addTagsQuery)
+		if err != nil {
+			t.Fatalf("failed to add tags: %q", err)
+		}
+
+		if summary.Updated != 2 {
+			t.Errorf("want 2 updated bookmarks, got %d", summary.Updated)
+		}
+
+		setPrivateQuery := bookmark.BulkEditQuery{
+			UserUUID: testUser.UUID,
+			UIDs:     uids,
+			Action:   bookmark.BulkEditActionSetPrivate,
+		}
+
+		summary, err = bs.BulkEdit(ctx, setPrivateQuery)
+		if err != nil {
+			t.Fatalf("failed to set bookmarks private: %q", err)
+		}
+
+		if summary.Updated != int64(nBookmarks) {
+			t.Errorf("want %d updated bookmarks, got %d", nBookmarks, summary.Updated)
+		}
+
+		for i, uid := range uids {
+			b, err := bs.ByUID(ctx, testUser.UUID, uid)
+			if err != nil {
+				t.Fatalf("failed to retrieve bookmark: %q", err)
+			}
+
+			wantTags := []string{"bulk"}
+			if i < 2 {
+				wantTags = []string{"bulk", "selected"}
+			}
+
+			if !slices.Equal(b.Tags, wantTags) {
+				t.Errorf("want bookmark %d to have tags %q, got %q", i, wantTags, b.Tags)
+			}
+			if !b.Private {
+				t.Errorf("want bookmark %d to be private", i)
+			}
+		}
+
+		deleteQuery := bookmark.BulkEditQuery{
+			UserUUID: testUser.UUID,
+			UIDs:     uids,
+			Action:   bookmark.BulkEditActionDelete,
+		}
+
+		summary, err = bs.BulkEdit(ctx, deleteQuery)
+		if err != nil {
+			t.Fatalf("failed to delete bookmarks: %q", err)
+		}
+
+		if summary.Deleted != int64(nBookmarks) {
+			t.Errorf("want %d deleted bookmarks, got %d", nBookmarks, summary.Deleted)
+		}
+
+		allBookmarks, err = bs.All(ctx, testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve all bookmarks: %q", err)
+		}
+
+		if len(allBookmarks) != 0 {
+			t.Errorf("want no bookmarks, got %d", len(allBookmarks))
+		}
+	})
+
+	t.Run("update link check", func(t *testing.T) {
+		ctx := t.Context()
+
+		bkm := bookmark.Bookmark{
+			UserUUID: testUser.UUID,
+			URL:      fake.Internet().URL(),
+			Title:    fake.Lorem().Sentence(5),
+		}
+
+		if err := bs.Add(ctx, bkm); err != nil {
+			t.Fatalf("failed to create bookmark: %q", err)
+		}
+
+		gotBookmark, err := bs.ByURL(ctx, testUser.UUID, bkm.URL)
+		if err != nil {
+			t.Fatalf("failed to retrieve bookmark: %q", err)
+		}
+
+		checked := gotBookmark
+		checked.Link = bookmark.LinkCheck{
+			Status:        bookmark.LinkStatusUnreachable,
+			StatusCode:    404,
+			CheckedAt:     time.Now().UTC().Truncate(time.Second),
+			NotFoundCount: 1,
+		}
+
+		if err := r.BookmarkLinkUpdate(ctx, checked); err != nil {
+			t.Fatalf("failed to update link check: %q", err)
+		}
+
+		gotChecked, err := bs.ByUID(ctx, testUser.UUID, gotBookmark.UID)
+		if err != nil {
+			t.Fatalf("failed to retrieve bookmark: %q", err)
+		}
+
+		if gotChecked.Link.Status != bookmark.LinkStatusUnreachable {
+			t.Errorf("want link status %q, got %q", bookmark.LinkStatusUnreachable, gotChecked.Link.Status)
+		}
+		if gotChecked.Link.NotFoundCount != 1 {
+			t.Errorf("want not found count 1, got %d", gotChecked.Link.NotFoundCount)
+		}
+
+		// the URL is edited while a check of the previous URL is in progress
+		edited := gotChecked
+		edited.URL = fake.Internet().URL()
+
+		if err := bs.Update(ctx, edited); err != nil {
+			t.Fatalf("failed to update bookmark: %q", err)
+		}
+
+		stale := checked
+		stale.Link.Status = bookmark.LinkStatusDead
+		stale.Link.NotFoundCount = 3
+
+		if err := r.BookmarkLinkUpdate(ctx, stale); err != nil {
+			t.Fatalf("failed to update link check: %q", err)
+		}
+
+		gotEdited, err := bs.ByUID(ctx, testUser.UUID, gotBookmark.UID)
+		if err != nil {
+			t.Fatalf("failed to retrieve bookmark: %q", err)
+		}
+
+		if gotEdited.Link.Status == bookmark.LinkStatusDead {
+			t.Errorf("want the stale check of the previous URL to be discarded, got link status %q", gotEdited.Link.Status)
+		}
+		if gotEdited.Link.NotFoundCount != 0 {
+			t.Errorf("want not found count reset after URL change, got %d", gotEdited.Link.NotFoundCount)
+		}
+
+		if err := bs.Delete(ctx, testUser.UUID, gotBookmark.UID); err != nil {
+			t.Fatalf("failed to delete bookmark: %q", err)
+		}
+	})
+
+	t.Run("enforce quota", func(t *testing.T) {
+		ctx := t.Context()
+
+		existing := bookmark.Bookmark{
+			UserUUID: testUser.UUID,
+			URL:      fake.Internet().URL(),
+			Title:    fake.Lorem().Sentence(5),
+		}
+
+		if err := bs.Add(ctx, existing); err != nil {
+			t.Fatalf("failed to create bookmark: %q", err)
+		}
+
+		now := time.Now().UTC()
+
+		newBookmark := func(url string) bookmark.Bookmark {
+			return bookmark.Bookmark{
+				UID:       fake.UUID().V4(),
+				UserUUID:  testUser.UUID,
+				URL:       url,
+				Title:     fake.Lorem().Sentence(5),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+		}
+
+		if err := r.BookmarkAdd(ctx, newBookmark(fake.Internet().URL()+"/over-limit"), 1); !errors.Is(err, quota.ErrBookmarkLimitReached) {
+			t.Fatalf("want error %q, got %q", quota.ErrBookmarkLimitReached, err)
+		}
+
+		bookmarks := []bookmark.Bookmark{
+			newBookmark(existing.URL),
+			newBookmark(fake.Internet().URL() + "/new-1"),
+			newBookmark(fake.Internet().URL() + "/new-2"),
+		}
+
+		if _, err := r.BookmarkAddMany(ctx, testUser.UUID, bookmarks, 2); !errors.Is(err, quota.ErrBookmarkLimitReached) {
+			t.Fatalf("want error %q, got %q", quota.ErrBookmarkLimitReached, err)
+		}
+
+		// the existing bookmark does not count towards the quota
+		got, err := r.BookmarkAddMany(ctx, testUser.UUID, bookmarks, 3)
+		if err != nil {
+			t.Fatalf("failed to add bookmarks: %q", err)
+		}
+
+		if got != 2 {
+			t.Errorf("want 2 new bookmarks, got %d", got)
+		}
+
+		for _, b := range bookmarks {
+			gotBookmark, err := bs.ByURL(ctx, testUser.UUID, b.URL)
+			if err != nil {
+				t.Fatalf("failed to retrieve bookmark: %q", err)
+			}
+
+			if err := bs.Delete(ctx, testUser.UUID, gotBookmark.UID); err != nil {
+				t.Fatalf("failed to delete bookmark: %q", err)
+			}
+		}
+	})
 }
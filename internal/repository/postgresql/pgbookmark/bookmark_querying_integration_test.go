@@ -4,6 +4,7 @@
 package pgbookmark_test
 
 import (
+	"slices"
 	"sort"
 	"testing"
 
@@ -20,11 +21,11 @@ import (
 func TestQueryingService(t *testing.T) {
 	pool := pgbase.CreateAndMigrateTestDatabase(t)
 	r := pgbookmark.NewRepository(pool)
-	bs := bookmark.NewService(r)
+	bs := bookmark.NewService(r, nil)
 	qs := bookmarkquerying.NewService(r)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	fake := faker.New()
 
@@ -91,6 +92,55 @@ func TestQueryingService(t *testing.T) {
 		}
 	})
 
+	t.Run("search private bookmarks", func(t *testing.T) {
+		gotPage, err := qs.BookmarksBySearchQueryAndPage(t.Context(), testUser.UUID, bookmarkquerying.VisibilityAll, "is:private", 1)
+		if err != nil {
+			t.Fatalf("failed to search bookmarks: %q", err)
+		}
+
+		if len(gotPage.Bookmarks) != nPrivateBookmarks {
+			t.Fatalf("want %d bookmarks, got %d", nPrivateBookmarks, len(gotPage.Bookmarks))
+		}
+
+		for _, b := range gotPage.Bookmarks {
+			if !b.Private {
+				t.Errorf("want bookmark %q to be private", b.UID)
+			}
+		}
+	})
+
+	t.Run("search bookmarks by tag", func(t *testing.T) {
+		var tag string
+		for _, b := range bookmarks {
+			if len(b.Tags) > 0 {
+				tag = b.Tags[0]
+				break
+			}
+		}
+
+		var want []bookmark.Bookmark
+		for _, b := range bookmarks {
+			if slices.Contains(b.Tags, tag) {
+				want = append(want, b)
+			}
+		}
+
+		gotPage, err := qs.BookmarksBySearchQueryAndPage(t.Context(), testUser.UUID, bookmarkquerying.VisibilityAll, "tag:"+tag, 1)
+		if err != nil {
+			t.Fatalf("failed to search bookmarks: %q", err)
+		}
+
+		if gotPage.ItemCount != uint(len(want)) {
+			t.Fatalf("want %d bookmarks, got %d", len(want), gotPage.ItemCount)
+		}
+
+		for _, b := range gotPage.Bookmarks {
+			if !slices.Contains(b.Tags, tag) {
+				t.Errorf("want bookmark %q to be tagged with %q", b.UID, tag)
+			}
+		}
+	})
+
 	t.Run("all tags", func(t *testing.T) {
 		ctx := t.Context()
 
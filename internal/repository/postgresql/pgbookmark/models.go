@@ -10,6 +10,7 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
 )
 
 type DBBookmark struct {
@@ -25,10 +26,51 @@ type DBBookmark struct {
 
 	FullTextSearchString string `db:"fulltextsearch_string"`
 
+	LinkStatus        string     `db:"link_status"`
+	LinkStatusCode    int        `db:"link_status_code"`
+	LinkRedirectURL   string     `db:"link_redirect_url"`
+	LinkError         string     `db:"link_error"`
+	LinkCheckedAt     *time.Time `db:"link_checked_at"`
+	LinkNotFoundCount int        `db:"link_not_found_count"`
+
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
+func (b *DBBookmark) asBookmark() bookmark.Bookmark {
+	link := bookmark.LinkCheck{
+		Status:        bookmark.LinkStatus(b.LinkStatus),
+		StatusCode:    b.LinkStatusCode,
+		RedirectURL:   b.LinkRedirectURL,
+		Error:         b.LinkError,
+		NotFoundCount: b.LinkNotFoundCount,
+	}
+
+	if b.LinkCheckedAt != nil {
+		link.CheckedAt = *b.LinkCheckedAt
+	}
+
+	return bookmark.Bookmark{
+		UserUUID:    b.UserUUID,
+		UID:         b.UID,
+		URL:         b.URL,
+		Title:       b.Title,
+		Description: b.Description,
+		Private:     b.Private,
+		Tags:        b.Tags,
+		Link:        link,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
+
+// DBBookmarkSearchResult holds a bookmark matching search terms, with a snippet of its archived page content.
+type DBBookmarkSearchResult struct {
+	DBBookmark
+
+	ContentSnippet string `db:"content_snippet"`
+}
+
 func bookmarkToFullTextSearchString(b bookmark.Bookmark) string {
 	return fmt.Sprintf(
 		"%s %s %s",
@@ -42,3 +84,53 @@ type DBTag struct {
 	Name  string `db:"name"`
 	Count uint   `db:"count"`
 }
+
+type DBTagAlias struct {
+	UserUUID string `db:"user_uuid"`
+	Alias    string `db:"alias"`
+	Tag      string `db:"tag"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type DBTagPreferences struct {
+	UserUUID    string `db:"user_uuid"`
+	CaseFolding string `db:"case_folding"`
+
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type DBArchive struct {
+	BookmarkUID string `db:"bookmark_uid"`
+	UserUUID    string `db:"user_uuid"`
+
+	URL         string `db:"url"`
+	Status      string `db:"status"`
+	ContentHash string `db:"content_hash"`
+	SizeBytes   int64  `db:"size_bytes"`
+	Error       string `db:"error"`
+
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	ArchivedAt *time.Time `db:"archived_at"`
+}
+
+func (a *DBArchive) asArchive() bookmarkarchiving.Archive {
+	archive := bookmarkarchiving.Archive{
+		BookmarkUID: a.BookmarkUID,
+		UserUUID:    a.UserUUID,
+		URL:         a.URL,
+		Status:      bookmarkarchiving.ArchiveStatus(a.Status),
+		ContentHash: a.ContentHash,
+		SizeBytes:   a.SizeBytes,
+		Error:       a.Error,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+
+	if a.ArchivedAt != nil {
+		archive.ArchivedAt = *a.ArchivedAt
+	}
+
+	return archive
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
@@ -15,12 +17,17 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkarchiving "github.com/virtualtam/sparklemuffin/pkg/bookmark/archiving"
+	bookmarkchecking "github.com/virtualtam/sparklemuffin/pkg/bookmark/checking"
 	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
 	bookmarkimporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/importing"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 var _ bookmark.Repository = &Repository{}
+var _ bookmarkarchiving.Repository = &Repository{}
+var _ bookmarkchecking.Repository = &Repository{}
 var _ bookmarkexporting.Repository = &Repository{}
 var _ bookmarkimporting.Repository = &Repository{}
 var _ bookmarkquerying.Repository = &Repository{}
@@ -39,7 +46,16 @@ const (
 	domain = "bookmarks"
 )
 
-func (r *Repository) BookmarkAdd(ctx context.Context, b bookmark.Bookmark) error {
+// snippetOptions configures the excerpts of archived page content returned with search results.
+//
+// See https://www.postgresql.org/docs/current/textsearch-controls.html#TEXTSEARCH-HEADLINE
+var snippetOptions = fmt.Sprintf(
+	`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`,
+	bookmarkquerying.SnippetHighlightStart,
+	bookmarkquerying.SnippetHighlightStop,
+)
+
+func (r *Repository) BookmarkAdd(ctx context.Context, b bookmark.Bookmark, maxBookmarks int64) error {
 	query := `
 	INSERT INTO bookmarks(
 		uid,
@@ -81,14 +97,29 @@ func (r *Repository) BookmarkAdd(ctx context.Context, b bookmark.Bookmark) error
 		"updated_at":            b.UpdatedAt,
 	}
 
-	return r.QueryTx(ctx, domain, "BookmarkAdd", query, args)
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "BookmarkAdd")
+
+	if err := bookmarkCheckQuotaTx(ctx, tx, b.UserUUID, []string{b.URL}, maxBookmarks); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *Repository) BookmarkAddMany(ctx context.Context, bookmarks []bookmark.Bookmark) (int64, error) {
-	return r.bookmarkUpsertMany(ctx, "ON CONFLICT DO NOTHING", bookmarks)
+func (r *Repository) BookmarkAddMany(ctx context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error) {
+	return r.bookmarkUpsertMany(ctx, "ON CONFLICT DO NOTHING", userUUID, bookmarks, maxBookmarks)
 }
 
-func (r *Repository) BookmarkUpsertMany(ctx context.Context, bookmarks []bookmark.Bookmark) (int64, error) {
+func (r *Repository) BookmarkUpsertMany(ctx context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error) {
 	return r.bookmarkUpsertMany(
 		ctx,
 		`
@@ -102,10 +133,105 @@ SET
 	created_at         = EXCLUDED.created_at,
 	updated_at         = EXCLUDED.updated_at
 `,
+		userUUID,
 		bookmarks,
+		maxBookmarks,
 	)
 }
 
+func (r *Repository) BookmarkBulkEdit(ctx context.Context, bq bookmark.BulkEditQuery, edit func(*bookmark.Bookmark) bool) (bookmark.BulkEditSummary, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return bookmark.BulkEditSummary{}, err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "BookmarkBulkEdit")
+
+	// Lock the selected bookmarks, so that concurrent changes are not overwritten.
+	selectQuery := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM bookmarks
+	WHERE user_uuid=$1
+	AND uid=ANY($2)
+	ORDER BY created_at DESC
+	FOR UPDATE`
+
+	rows, err := tx.Query(ctx, selectQuery, bq.UserUUID, bq.UIDs)
+	if err != nil {
+		return bookmark.BulkEditSummary{}, err
+	}
+	defer rows.Close()
+
+	var dbBookmarks []DBBookmark
+
+	if err := pgxscan.ScanAll(&dbBookmarks, rows); err != nil {
+		return bookmark.BulkEditSummary{}, err
+	}
+
+	var summary bookmark.BulkEditSummary
+
+	if bq.Action == bookmark.BulkEditActionDelete {
+		for _, dbBookmark := range dbBookmarks {
+			summary.Bookmarks = append(summary.Bookmarks, dbBookmark.asBookmark())
+		}
+
+		commandTag, err := tx.Exec(
+			ctx,
+			"DELETE FROM bookmarks WHERE user_uuid=$1 AND uid=ANY($2)",
+			bq.UserUUID,
+			bq.UIDs,
+		)
+		if err != nil {
+			return bookmark.BulkEditSummary{}, err
+		}
+
+		summary.Deleted = commandTag.RowsAffected()
+	} else {
+		updateQuery := `
+		UPDATE bookmarks
+		SET
+			private=@private,
+			tags=@tags,
+			fulltextsearch_tsv=TO_TSVECTOR(@fulltextsearch_string),
+			updated_at=@updated_at
+		WHERE user_uuid=@user_uuid
+		AND uid=@uid`
+
+		for _, dbBookmark := range dbBookmarks {
+			b := dbBookmark.asBookmark()
+
+			if !edit(&b) {
+				continue
+			}
+
+			args := pgx.NamedArgs{
+				"user_uuid":             b.UserUUID,
+				"uid":                   b.UID,
+				"private":               b.Private,
+				"tags":                  b.Tags,
+				"fulltextsearch_string": bookmarkToFullTextSearchString(b),
+				"updated_at":            b.UpdatedAt,
+			}
+
+			commandTag, err := tx.Exec(ctx, updateQuery, args)
+			if err != nil {
+				return bookmark.BulkEditSummary{}, err
+			}
+
+			summary.Updated += commandTag.RowsAffected()
+			summary.Bookmarks = append(summary.Bookmarks, b)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return bookmark.BulkEditSummary{}, err
+	}
+
+	return summary, nil
+}
+
 func (r *Repository) BookmarkDelete(ctx context.Context, userUUID, uid string) error {
 	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
@@ -137,7 +263,9 @@ func (r *Repository) BookmarkGetAll(ctx context.Context, userUUID string) ([]boo
 	return r.bookmarkGetManyQuery(
 		ctx,
 		`
-SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+SELECT user_uuid, uid, url, title, description, private, tags,
+       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+       created_at, updated_at
 FROM bookmarks
 WHERE user_uuid=$1
 ORDER BY created_at DESC`,
@@ -149,7 +277,9 @@ func (r *Repository) BookmarkGetAllPrivate(ctx context.Context, userUUID string)
 	return r.bookmarkGetManyQuery(
 		ctx,
 		`
-SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+SELECT user_uuid, uid, url, title, description, private, tags,
+       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+       created_at, updated_at
 FROM bookmarks
 WHERE user_uuid=$1
 AND   private=TRUE
@@ -162,7 +292,9 @@ func (r *Repository) BookmarkGetAllPublic(ctx context.Context, userUUID string)
 	return r.bookmarkGetManyQuery(
 		ctx,
 		`
-SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+SELECT user_uuid, uid, url, title, description, private, tags,
+       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+       created_at, updated_at
 FROM bookmarks
 WHERE user_uuid=$1
 AND   private=FALSE
@@ -173,7 +305,9 @@ ORDER BY created_at DESC`,
 
 func (r *Repository) BookmarkGetByTag(ctx context.Context, userUUID string, tag string) ([]bookmark.Bookmark, error) {
 	query := `
-	SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
 	FROM bookmarks
 	WHERE user_uuid=$1
 	AND   $2=ANY(tags)`
@@ -186,9 +320,33 @@ func (r *Repository) BookmarkGetByTag(ctx context.Context, userUUID string, tag
 	)
 }
 
+func (r *Repository) BookmarkGetByTagWithDescendants(ctx context.Context, userUUID string, tag string) ([]bookmark.Bookmark, error) {
+	query := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM bookmarks
+	WHERE user_uuid=$1
+	AND   EXISTS (
+		SELECT 1
+		FROM  UNNEST(tags) AS tag
+		WHERE tag=$2
+		OR    starts_with(tag, $2 || '/')
+	)`
+
+	return r.bookmarkGetManyQuery(
+		ctx,
+		query,
+		userUUID,
+		tag,
+	)
+}
+
 func (r *Repository) BookmarkGetByUID(ctx context.Context, userUUID, uid string) (bookmark.Bookmark, error) {
 	query := `
-	SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
 	FROM bookmarks
 	WHERE user_uuid=$1
 	AND uid=$2`
@@ -196,9 +354,24 @@ func (r *Repository) BookmarkGetByUID(ctx context.Context, userUUID, uid string)
 	return r.bookmarkGetQuery(ctx, query, userUUID, uid)
 }
 
+func (r *Repository) BookmarkGetByUIDs(ctx context.Context, userUUID string, uids []string) ([]bookmark.Bookmark, error) {
+	query := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM bookmarks
+	WHERE user_uuid=$1
+	AND uid=ANY($2)
+	ORDER BY created_at DESC`
+
+	return r.bookmarkGetManyQuery(ctx, query, userUUID, uids)
+}
+
 func (r *Repository) BookmarkGetByURL(ctx context.Context, userUUID, u string) (bookmark.Bookmark, error) {
 	query := `
-	SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
 	FROM bookmarks
 	WHERE user_uuid=$1
 	AND url=$2`
@@ -251,7 +424,9 @@ func (r *Repository) BookmarkGetN(ctx context.Context, userUUID string, visibili
 	switch visibility {
 	case bookmarkquerying.VisibilityPrivate:
 		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+		SELECT user_uuid, uid, url, title, description, private, tags,
+		       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+		       created_at, updated_at
 		FROM  bookmarks
 		WHERE user_uuid=$1
 		AND   private=TRUE
@@ -260,7 +435,9 @@ func (r *Repository) BookmarkGetN(ctx context.Context, userUUID string, visibili
 
 	case bookmarkquerying.VisibilityPublic:
 		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+		SELECT user_uuid, uid, url, title, description, private, tags,
+		       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+		       created_at, updated_at
 		FROM  bookmarks
 		WHERE user_uuid=$1
 		AND   private=FALSE
@@ -269,7 +446,9 @@ func (r *Repository) BookmarkGetN(ctx context.Context, userUUID string, visibili
 
 	default:
 		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+		SELECT user_uuid, uid, url, title, description, private, tags,
+		       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+		       created_at, updated_at
 		FROM  bookmarks
 		WHERE user_uuid=$1
 		ORDER BY created_at DESC
@@ -287,7 +466,9 @@ func (r *Repository) BookmarkGetN(ctx context.Context, userUUID string, visibili
 
 func (r *Repository) BookmarkGetPublicByUID(ctx context.Context, userUUID, uid string) (bookmark.Bookmark, error) {
 	query := `
-	SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
 	FROM bookmarks
 	WHERE user_uuid=$1
 	AND uid=$2
@@ -296,43 +477,20 @@ func (r *Repository) BookmarkGetPublicByUID(ctx context.Context, userUUID, uid s
 	return r.bookmarkGetQuery(ctx, query, userUUID, uid)
 }
 
-func (r *Repository) BookmarkSearchCount(ctx context.Context, userUUID string, visibility bookmarkquerying.Visibility, searchTerms string) (uint, error) {
-	var query string
-
-	switch visibility {
-	case bookmarkquerying.VisibilityPrivate:
-		query = `
-		SELECT COUNT(*)
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND PRIVATE=TRUE
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)`
-
-	case bookmarkquerying.VisibilityPublic:
-		query = `
-		SELECT COUNT(*)
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND PRIVATE=FALSE
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)`
-
-	default:
-		query = `
-		SELECT COUNT(*)
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)`
+func (r *Repository) BookmarkSearchCount(ctx context.Context, userUUID string, visibility bookmarkquerying.Visibility, query search.Query) (uint, error) {
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
 	}
 
+	sqlQuery := `
+	SELECT COUNT(*)
+	FROM bookmarks
+	WHERE user_uuid=@user_uuid
+	` + bookmarkSearchConditions(visibility, query, args)
+
 	var count uint
-	fullTextSearchTerms := pgbase.FullTextSearchReplacer.Replace(searchTerms)
 
-	err := r.Pool.QueryRow(
-		ctx,
-		query,
-		userUUID,
-		fullTextSearchTerms,
-	).Scan(&count)
+	err := r.Pool.QueryRow(ctx, sqlQuery, args).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -340,50 +498,64 @@ func (r *Repository) BookmarkSearchCount(ctx context.Context, userUUID string, v
 	return count, nil
 }
 
-func (r *Repository) BookmarkSearchN(ctx context.Context, userUUID string, visibility bookmarkquerying.Visibility, searchTerms string, n uint, offset uint) ([]bookmark.Bookmark, error) {
-	var query string
+// BookmarkSearchN returns bookmarks matching the search query, best matches first.
+//
+// Matches on the bookmark's own fields rank above matches on the archived page content,
+// which come with a highlighted snippet. Queries without free text terms return the most
+// recent bookmarks first.
+func (r *Repository) BookmarkSearchN(ctx context.Context, userUUID string, visibility bookmarkquerying.Visibility, query search.Query, n uint, offset uint) ([]bookmarkquerying.BookmarkSearchResult, error) {
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+		"limit":     n,
+		"offset":    offset,
+	}
 
-	switch visibility {
-	case bookmarkquerying.VisibilityPrivate:
-		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND private=TRUE
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+	snippetColumn := `'' AS content_snippet`
+	orderBy := `ORDER BY created_at DESC`
+
+	if query.HasTerms() {
+		snippetColumn = `
+	       CASE
+	           WHEN content_tsv @@ websearch_to_tsquery(@search_terms) THEN ts_headline(content_text, websearch_to_tsquery(@search_terms), @snippet_options)
+	           ELSE ''
+	       END AS content_snippet`
+		orderBy = `ORDER BY ts_rank(search_tsv, websearch_to_tsquery(@search_terms)) DESC, created_at DESC`
+		args["snippet_options"] = snippetOptions
+	}
 
-	case bookmarkquerying.VisibilityPublic:
-		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND private=FALSE
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+	sqlQuery := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at,
+	       ` + snippetColumn + `
+	FROM bookmarks
+	WHERE user_uuid=@user_uuid
+	` + bookmarkSearchConditions(visibility, query, args) + `
+	` + orderBy + `
+	LIMIT @limit OFFSET @offset`
 
-	default:
-		query = `
-		SELECT user_uuid, uid, url, title, description, private, tags, created_at, updated_at
-		FROM bookmarks
-		WHERE user_uuid=$1
-		AND fulltextsearch_tsv @@ websearch_to_tsquery($2)
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+	rows, err := r.Pool.Query(ctx, sqlQuery, args)
+	if err != nil {
+		return []bookmarkquerying.BookmarkSearchResult{}, err
 	}
+	defer rows.Close()
 
-	fullTextSearchTerms := pgbase.FullTextSearchReplacer.Replace(searchTerms)
+	var dbResults []DBBookmarkSearchResult
 
-	return r.bookmarkGetManyQuery(
-		ctx,
-		query,
-		userUUID,
-		fullTextSearchTerms,
-		n,
-		offset,
-	)
+	if err := pgxscan.ScanAll(&dbResults, rows); err != nil {
+		return []bookmarkquerying.BookmarkSearchResult{}, err
+	}
+
+	results := make([]bookmarkquerying.BookmarkSearchResult, len(dbResults))
+
+	for i, dbResult := range dbResults {
+		results[i] = bookmarkquerying.BookmarkSearchResult{
+			Bookmark: dbResult.asBookmark(),
+			Snippet:  dbResult.ContentSnippet,
+		}
+	}
+
+	return results, nil
 }
 
 func (r *Repository) BookmarkIsURLRegistered(ctx context.Context, userUUID, url string) (bool, error) {
@@ -405,8 +577,129 @@ func (r *Repository) BookmarkIsURLRegisteredToAnotherUID(ctx context.Context, us
 	)
 }
 
+func (r *Repository) BookmarkTagAliasGetAll(ctx context.Context, userUUID string) ([]bookmark.TagAlias, error) {
+	query := `
+	SELECT user_uuid, alias, tag, created_at
+	FROM bookmark_tag_aliases
+	WHERE user_uuid=$1
+	ORDER BY alias`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return []bookmark.TagAlias{}, err
+	}
+	defer rows.Close()
+
+	var dbAliases []DBTagAlias
+
+	if err := pgxscan.ScanAll(&dbAliases, rows); err != nil {
+		return []bookmark.TagAlias{}, err
+	}
+
+	aliases := make([]bookmark.TagAlias, len(dbAliases))
+
+	for i, dbAlias := range dbAliases {
+		aliases[i] = bookmark.TagAlias{
+			UserUUID:  dbAlias.UserUUID,
+			Alias:     dbAlias.Alias,
+			Tag:       dbAlias.Tag,
+			CreatedAt: dbAlias.CreatedAt,
+		}
+	}
+
+	return aliases, nil
+}
+
+func (r *Repository) BookmarkTagAliasReplaceAll(ctx context.Context, userUUID string, aliases []bookmark.TagAlias) error {
+	batch := &pgx.Batch{}
+
+	batch.Queue("DELETE FROM bookmark_tag_aliases WHERE user_uuid=$1", userUUID)
+
+	insertQuery := `
+	INSERT INTO bookmark_tag_aliases(
+		user_uuid,
+		alias,
+		tag,
+		created_at
+	)
+	VALUES(
+		@user_uuid,
+		@alias,
+		@tag,
+		@created_at
+	)`
+
+	for _, alias := range aliases {
+		args := pgx.NamedArgs{
+			"user_uuid":  userUUID,
+			"alias":      alias.Alias,
+			"tag":        alias.Tag,
+			"created_at": alias.CreatedAt,
+		}
+
+		batch.Queue(insertQuery, args)
+	}
+
+	return r.BatchTx(ctx, domain, "BookmarkTagAliasReplaceAll", batch)
+}
+
+func (r *Repository) BookmarkTagPreferencesGetByUserUUID(ctx context.Context, userUUID string) (bookmark.TagPreferences, error) {
+	query := `
+	SELECT user_uuid, case_folding, updated_at
+	FROM bookmark_tag_preferences
+	WHERE user_uuid=$1`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return bookmark.TagPreferences{}, err
+	}
+	defer rows.Close()
+
+	dbPreferences := DBTagPreferences{}
+	err = pgxscan.ScanOne(&dbPreferences, rows)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return bookmark.TagPreferences{}, bookmark.ErrTagPreferencesNotFound
+	}
+	if err != nil {
+		return bookmark.TagPreferences{}, err
+	}
+
+	return bookmark.TagPreferences{
+		UserUUID:    dbPreferences.UserUUID,
+		CaseFolding: bookmark.TagCaseFolding(dbPreferences.CaseFolding),
+		UpdatedAt:   dbPreferences.UpdatedAt,
+	}, nil
+}
+
+func (r *Repository) BookmarkTagPreferencesUpsert(ctx context.Context, preferences bookmark.TagPreferences) error {
+	query := `
+	INSERT INTO bookmark_tag_preferences(
+		user_uuid,
+		case_folding,
+		updated_at
+	)
+	VALUES(
+		@user_uuid,
+		@case_folding,
+		@updated_at
+	)
+	ON CONFLICT (user_uuid) DO UPDATE
+	SET
+		case_folding=EXCLUDED.case_folding,
+		updated_at=EXCLUDED.updated_at`
+
+	args := pgx.NamedArgs{
+		"user_uuid":    preferences.UserUUID,
+		"case_folding": string(preferences.CaseFolding),
+		"updated_at":   preferences.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "BookmarkTagPreferencesUpsert", query, args)
+}
+
 func (r *Repository) BookmarkTagUpdateMany(ctx context.Context, bookmarks []bookmark.Bookmark) (int64, error) {
-	return r.BookmarkUpsertMany(ctx, bookmarks)
+	// only existing bookmarks are updated, so the quota does not apply
+	return r.BookmarkUpsertMany(ctx, "", bookmarks, 0)
 }
 
 func (r *Repository) BookmarkUpdate(ctx context.Context, b bookmark.Bookmark) error {
@@ -419,7 +712,13 @@ func (r *Repository) BookmarkUpdate(ctx context.Context, b bookmark.Bookmark) er
 		private=@private,
 		tags=@tags,
 		fulltextsearch_tsv=TO_TSVECTOR(@fulltextsearch_string),
-		updated_at=@updated_at
+		updated_at=@updated_at,
+		link_status=CASE WHEN url=@url THEN link_status ELSE 'unchecked' END,
+		link_status_code=CASE WHEN url=@url THEN link_status_code ELSE 0 END,
+		link_redirect_url=CASE WHEN url=@url THEN link_redirect_url ELSE '' END,
+		link_error=CASE WHEN url=@url THEN link_error ELSE '' END,
+		link_checked_at=CASE WHEN url=@url THEN link_checked_at ELSE NULL END,
+		link_not_found_count=CASE WHEN url=@url THEN link_not_found_count ELSE 0 END
 	WHERE user_uuid=@user_uuid
 	AND uid=@uid
 			`
@@ -712,3 +1011,296 @@ func (r *Repository) BookmarkTagSearchN(ctx context.Context, userUUID string, vi
 
 	return r.tagGetQuery(ctx, query, userUUID, "%"+searchTerms+"%", n, offset)
 }
+
+func (r *Repository) BookmarkLinkGetAllByStatus(ctx context.Context, userUUID string, status bookmark.LinkStatus) ([]bookmark.Bookmark, error) {
+	query := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM  bookmarks
+	WHERE user_uuid=$1
+	AND   link_status=$2
+	ORDER BY created_at DESC`
+
+	return r.bookmarkGetManyQuery(ctx, query, userUUID, string(status))
+}
+
+func (r *Repository) BookmarkLinkGetCount(ctx context.Context, userUUID string, status bookmark.LinkStatus) (uint, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM  bookmarks
+	WHERE user_uuid=$1
+	AND   link_status=$2`
+
+	var count uint
+
+	err := r.Pool.QueryRow(
+		ctx,
+		query,
+		userUUID,
+		string(status),
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) BookmarkLinkGetN(ctx context.Context, userUUID string, status bookmark.LinkStatus, n uint, offset uint) ([]bookmark.Bookmark, error) {
+	query := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM  bookmarks
+	WHERE user_uuid=$1
+	AND   link_status=$2
+	ORDER BY created_at DESC
+	LIMIT $3 OFFSET $4`
+
+	return r.bookmarkGetManyQuery(ctx, query, userUUID, string(status), n, offset)
+}
+
+func (r *Repository) BookmarkLinkGetNToCheck(ctx context.Context, n uint, checkedBefore time.Time) ([]bookmark.Bookmark, error) {
+	query := `
+	SELECT user_uuid, uid, url, title, description, private, tags,
+	       link_status, link_status_code, link_redirect_url, link_error, link_checked_at, link_not_found_count,
+	       created_at, updated_at
+	FROM  bookmarks
+	WHERE link_checked_at IS NULL
+	OR    link_checked_at < $1
+	ORDER BY link_checked_at NULLS FIRST
+	LIMIT $2`
+
+	return r.bookmarkGetManyQuery(ctx, query, checkedBefore, n)
+}
+
+func (r *Repository) BookmarkLinkUpdate(ctx context.Context, b bookmark.Bookmark) error {
+	query := `
+	UPDATE bookmarks
+	SET
+		link_status=@link_status,
+		link_status_code=@link_status_code,
+		link_redirect_url=@link_redirect_url,
+		link_error=@link_error,
+		link_checked_at=@link_checked_at,
+		link_not_found_count=@link_not_found_count
+	WHERE user_uuid=@user_uuid
+	AND uid=@uid
+	AND url=@url`
+
+	args := pgx.NamedArgs{
+		"user_uuid":            b.UserUUID,
+		"uid":                  b.UID,
+		"url":                  b.URL,
+		"link_status":          string(b.Link.Status),
+		"link_status_code":     b.Link.StatusCode,
+		"link_redirect_url":    b.Link.RedirectURL,
+		"link_error":           b.Link.Error,
+		"link_checked_at":      b.Link.CheckedAt,
+		"link_not_found_count": b.Link.NotFoundCount,
+	}
+
+	return r.QueryTx(ctx, domain, "BookmarkLinkUpdate", query, args)
+}
+
+func (r *Repository) BookmarkArchiveGetByUID(ctx context.Context, userUUID string, bookmarkUID string) (bookmarkarchiving.Archive, error) {
+	query := `
+	SELECT bookmark_uid, user_uuid, url, status, content_hash, size_bytes, error,
+	       created_at, updated_at, archived_at
+	FROM  bookmark_archives
+	WHERE user_uuid=$1
+	AND   bookmark_uid=$2`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID, bookmarkUID)
+	if err != nil {
+		return bookmarkarchiving.Archive{}, err
+	}
+	defer rows.Close()
+
+	dbArchive := &DBArchive{}
+	err = pgxscan.ScanOne(dbArchive, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return bookmarkarchiving.Archive{}, bookmarkarchiving.ErrArchiveNotFound
+	}
+	if err != nil {
+		return bookmarkarchiving.Archive{}, err
+	}
+
+	return dbArchive.asArchive(), nil
+}
+
+func (r *Repository) BookmarkArchiveGetNPending(ctx context.Context, n uint) ([]bookmarkarchiving.Archive, error) {
+	query := `
+	SELECT bookmark_uid, user_uuid, url, status, content_hash, size_bytes, error,
+	       created_at, updated_at, archived_at
+	FROM  bookmark_archives
+	WHERE status=$1
+	ORDER BY updated_at
+	LIMIT $2`
+
+	rows, err := r.Pool.Query(ctx, query, string(bookmarkarchiving.ArchiveStatusPending), n)
+	if err != nil {
+		return []bookmarkarchiving.Archive{}, err
+	}
+	defer rows.Close()
+
+	var dbArchives []DBArchive
+
+	if err := pgxscan.ScanAll(&dbArchives, rows); err != nil {
+		return []bookmarkarchiving.Archive{}, err
+	}
+
+	archives := make([]bookmarkarchiving.Archive, len(dbArchives))
+
+	for i, dbArchive := range dbArchives {
+		archives[i] = dbArchive.asArchive()
+	}
+
+	return archives, nil
+}
+
+func (r *Repository) BookmarkArchiveHashesByUserUUID(ctx context.Context, userUUID string) ([]string, error) {
+	query := `
+	SELECT DISTINCT content_hash
+	FROM  bookmark_archives
+	WHERE user_uuid=$1
+	AND   content_hash<>''
+	ORDER BY content_hash`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+
+	if err := pgxscan.ScanAll(&hashes, rows); err != nil {
+		return []string{}, err
+	}
+
+	return hashes, nil
+}
+
+func (r *Repository) BookmarkArchiveHashesUnreferenced(ctx context.Context, hashes []string) ([]string, error) {
+	query := `
+	SELECT h.hash
+	FROM  UNNEST(@hashes::TEXT[]) AS h(hash)
+	WHERE NOT EXISTS (
+		SELECT 1
+		FROM  bookmark_archives ba
+		WHERE ba.content_hash=h.hash
+	)
+	ORDER BY h.hash`
+
+	args := pgx.NamedArgs{
+		"hashes": hashes,
+	}
+
+	rows, err := r.Pool.Query(ctx, query, args)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	var unreferenced []string
+
+	if err := pgxscan.ScanAll(&unreferenced, rows); err != nil {
+		return []string{}, err
+	}
+
+	return unreferenced, nil
+}
+
+func (r *Repository) BookmarkArchiveRequest(ctx context.Context, archive bookmarkarchiving.Archive) error {
+	query := `
+	INSERT INTO bookmark_archives(
+		bookmark_uid,
+		user_uuid,
+		url,
+		status,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@bookmark_uid,
+		@user_uuid,
+		@url,
+		@status,
+		@created_at,
+		@updated_at
+	)
+	ON CONFLICT (bookmark_uid) DO UPDATE
+	SET
+		url=EXCLUDED.url,
+		status=EXCLUDED.status,
+		error='',
+		updated_at=EXCLUDED.updated_at`
+
+	args := pgx.NamedArgs{
+		"bookmark_uid": archive.BookmarkUID,
+		"user_uuid":    archive.UserUUID,
+		"url":          archive.URL,
+		"status":       string(archive.Status),
+		"created_at":   archive.CreatedAt,
+		"updated_at":   archive.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "BookmarkArchiveRequest", query, args)
+}
+
+func (r *Repository) BookmarkArchiveUpdate(ctx context.Context, archive bookmarkarchiving.Archive) error {
+	archiveQuery := `
+	UPDATE bookmark_archives
+	SET
+		status=@status,
+		content_hash=@content_hash,
+		size_bytes=@size_bytes,
+		error=@error,
+		updated_at=@updated_at,
+		archived_at=@archived_at
+	WHERE user_uuid=@user_uuid
+	AND bookmark_uid=@bookmark_uid`
+
+	var archivedAt *time.Time
+	if !archive.ArchivedAt.IsZero() {
+		archivedAt = &archive.ArchivedAt
+	}
+
+	archiveArgs := pgx.NamedArgs{
+		"user_uuid":    archive.UserUUID,
+		"bookmark_uid": archive.BookmarkUID,
+		"status":       string(archive.Status),
+		"content_hash": archive.ContentHash,
+		"size_bytes":   archive.SizeBytes,
+		"error":        archive.Error,
+		"updated_at":   archive.UpdatedAt,
+		"archived_at":  archivedAt,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(archiveQuery, archiveArgs)
+
+	if archive.Status == bookmarkarchiving.ArchiveStatusArchived {
+		contentQuery := `
+		UPDATE bookmarks
+		SET
+			content_text=@content_text,
+			content_tsv=setweight(TO_TSVECTOR(@fulltextsearch_string), 'D')
+		WHERE user_uuid=@user_uuid
+		AND uid=@uid`
+
+		contentArgs := pgx.NamedArgs{
+			"user_uuid":             archive.UserUUID,
+			"uid":                   archive.BookmarkUID,
+			"content_text":          archive.Text,
+			"fulltextsearch_string": pgbase.FullTextSearchReplacer.Replace(archive.Text),
+		}
+
+		batch.Queue(contentQuery, contentArgs)
+	}
+
+	return r.BatchTx(ctx, domain, "BookmarkArchiveUpdate", batch)
+}
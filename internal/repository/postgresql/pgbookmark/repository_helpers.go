@@ -6,13 +6,17 @@ package pgbookmark
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
 	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 func (r *Repository) bookmarkGetQuery(ctx context.Context, query string, queryParams ...any) (bookmark.Bookmark, error) {
@@ -32,17 +36,7 @@ func (r *Repository) bookmarkGetQuery(ctx context.Context, query string, queryPa
 		return bookmark.Bookmark{}, err
 	}
 
-	return bookmark.Bookmark{
-		UserUUID:    dbBookmark.UserUUID,
-		UID:         dbBookmark.UID,
-		URL:         dbBookmark.URL,
-		Title:       dbBookmark.Title,
-		Description: dbBookmark.Description,
-		Private:     dbBookmark.Private,
-		Tags:        dbBookmark.Tags,
-		CreatedAt:   dbBookmark.CreatedAt,
-		UpdatedAt:   dbBookmark.UpdatedAt,
-	}, nil
+	return dbBookmark.asBookmark(), nil
 }
 
 func (r *Repository) bookmarkGetManyQuery(ctx context.Context, query string, queryParams ...any) ([]bookmark.Bookmark, error) {
@@ -61,25 +55,55 @@ func (r *Repository) bookmarkGetManyQuery(ctx context.Context, query string, que
 	var bookmarks []bookmark.Bookmark
 
 	for _, dbBookmark := range dbBookmarks {
-		b := bookmark.Bookmark{
-			UserUUID:    dbBookmark.UserUUID,
-			UID:         dbBookmark.UID,
-			URL:         dbBookmark.URL,
-			Title:       dbBookmark.Title,
-			Description: dbBookmark.Description,
-			Private:     dbBookmark.Private,
-			Tags:        dbBookmark.Tags,
-			CreatedAt:   dbBookmark.CreatedAt,
-			UpdatedAt:   dbBookmark.UpdatedAt,
-		}
-
-		bookmarks = append(bookmarks, b)
+		bookmarks = append(bookmarks, dbBookmark.asBookmark())
 	}
 
 	return bookmarks, nil
 }
 
-func (r *Repository) bookmarkUpsertMany(ctx context.Context, onConflictStmt string, bookmarks []bookmark.Bookmark) (int64, error) {
+// bookmarkCheckQuotaTx ensures adding bookmarks for the given URLs would not exceed the user's
+// quota; URLs that are already registered do not count towards the quota.
+//
+// The user's row is locked until the end of the transaction, so that concurrent additions
+// are counted one after the other.
+func bookmarkCheckQuotaTx(ctx context.Context, tx pgx.Tx, userUUID string, urls []string, maxBookmarks int64) error {
+	if maxBookmarks == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM users WHERE uuid=$1 FOR NO KEY UPDATE", userUUID); err != nil {
+		return err
+	}
+
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM bookmarks WHERE user_uuid=@user_uuid),
+		(
+			SELECT COUNT(DISTINCT u.url)
+			FROM UNNEST(@urls::TEXT[]) AS u(url)
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM  bookmarks b
+				WHERE b.user_uuid=@user_uuid
+				AND   b.url=u.url
+			)
+		)`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+		"urls":      urls,
+	}
+
+	var current, newURLs int64
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&current, &newURLs); err != nil {
+		return err
+	}
+
+	return quota.CheckBookmarkCount(maxBookmarks, current, newURLs)
+}
+
+func (r *Repository) bookmarkUpsertMany(ctx context.Context, onConflictStmt string, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error) {
 	insertQuery := `
 	INSERT INTO bookmarks(
 		uid,
@@ -108,6 +132,22 @@ func (r *Repository) bookmarkUpsertMany(ctx context.Context, onConflictStmt stri
 
 	query := insertQuery + onConflictStmt
 
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "BookmarkUpsertMany")
+
+	urls := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		urls[i] = b.URL
+	}
+
+	if err := bookmarkCheckQuotaTx(ctx, tx, userUUID, urls, maxBookmarks); err != nil {
+		return 0, err
+	}
+
 	batch := &pgx.Batch{}
 
 	for _, b := range bookmarks {
@@ -129,7 +169,7 @@ func (r *Repository) bookmarkUpsertMany(ctx context.Context, onConflictStmt stri
 		batch.Queue(query, args)
 	}
 
-	batchResults := r.Pool.SendBatch(ctx, batch)
+	batchResults := tx.SendBatch(ctx, batch)
 	defer func() {
 		if err := batchResults.Close(); err != nil {
 			log.Error().
@@ -151,6 +191,15 @@ func (r *Repository) bookmarkUpsertMany(ctx context.Context, onConflictStmt stri
 		rowsAffected += commandTag.RowsAffected()
 	}
 
+	// the batch results must be read entirely before committing the transaction
+	if err := batchResults.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
 	return rowsAffected, nil
 }
 
@@ -176,3 +225,74 @@ func (r *Repository) tagGetQuery(ctx context.Context, query string, queryParams
 
 	return tags, nil
 }
+
+// bookmarkSearchConditions returns the SQL conditions restricting bookmarks to those matching
+// a visibility filter and a search query, and sets the corresponding named arguments.
+//
+// Values are always passed as query arguments, never interpolated into the SQL statement.
+func bookmarkSearchConditions(visibility bookmarkquerying.Visibility, query search.Query, args pgx.NamedArgs) string {
+	var conditions []string
+
+	switch visibility {
+	case bookmarkquerying.VisibilityPrivate:
+		conditions = append(conditions, "AND private=TRUE")
+	case bookmarkquerying.VisibilityPublic:
+		conditions = append(conditions, "AND private=FALSE")
+	}
+
+	if query.HasTerms() {
+		conditions = append(conditions, "AND search_tsv @@ websearch_to_tsquery(@search_terms)")
+		args["search_terms"] = pgbase.FullTextSearchReplacer.Replace(query.Terms)
+	}
+
+	// tags match bookmarks tagged with the tag itself or one of its descendants,
+	// e.g. tag:dev matches bookmarks tagged dev/go
+	if len(query.Tags) > 0 {
+		conditions = append(conditions, `AND NOT EXISTS (
+		SELECT 1
+		FROM  UNNEST(@tags::TEXT[]) AS wanted
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM  UNNEST(tags) AS tag
+			WHERE tag=wanted
+			OR    starts_with(tag, wanted || '/')
+		)
+	)`)
+		args["tags"] = query.Tags
+	}
+
+	if len(query.ExcludedTags) > 0 {
+		conditions = append(conditions, `AND NOT EXISTS (
+		SELECT 1
+		FROM  UNNEST(tags) AS tag, UNNEST(@excluded_tags::TEXT[]) AS excluded
+		WHERE tag=excluded
+		OR    starts_with(tag, excluded || '/')
+	)`)
+		args["excluded_tags"] = query.ExcludedTags
+	}
+
+	if len(query.Sites) > 0 {
+		conditions = append(conditions, "AND url ~* ANY(@site_patterns)")
+		args["site_patterns"] = pgbase.SiteURLPatterns(query.Sites)
+	}
+
+	if query.Private {
+		conditions = append(conditions, "AND private=TRUE")
+	}
+
+	if query.Public {
+		conditions = append(conditions, "AND private=FALSE")
+	}
+
+	if !query.After.IsZero() {
+		conditions = append(conditions, "AND created_at >= @created_after")
+		args["created_after"] = query.After
+	}
+
+	if !query.Before.IsZero() {
+		conditions = append(conditions, "AND created_at < @created_before")
+		args["created_before"] = query.Before
+	}
+
+	return strings.Join(conditions, "\n\t")
+}
@@ -0,0 +1,176 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgfeed_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgfeed"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestFeedAdministratingService(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	r := pgfeed.NewRepository(pool)
+	as := administrating.NewService(r, nil, nil)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+
+	var testUsers []user.User
+
+	for range 2 {
+		u := user.FakeUser(t, &fake)
+
+		if err := us.Add(t.Context(), u); err != nil {
+			t.Fatalf("failed to create user: %q", err)
+		}
+
+		testUser, err := us.ByNickName(t.Context(), u.NickName)
+		if err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+
+		testUsers = append(testUsers, testUser)
+	}
+
+	now := time.Now().UTC()
+	fakeData := generateFakeData(t, &fake, now, testUsers[0])
+
+	sourceFeed := fakeData.feeds[0]
+	targetFeed := fakeData.feeds[1]
+
+	// the target feed also publishes the first entry of the source feed
+	sharedEntry := fakeData.entries[0]
+	sharedEntry.UID = fake.UUID().V4()
+	sharedEntry.FeedUUID = targetFeed.UUID
+	fakeData.entries = append(fakeData.entries, sharedEntry)
+
+	// the second user is only subscribed to the source feed
+	otherCategory := generateFakeCategory(t, &fake, testUsers[1].UUID, "Other")
+	fakeData.categories = append(fakeData.categories, otherCategory)
+	fakeData.subscriptions = append(fakeData.subscriptions, feed.Subscription{
+		UUID:         fake.UUID().V4(),
+		FeedUUID:     sourceFeed.UUID,
+		CategoryUUID: otherCategory.UUID,
+		UserUUID:     testUsers[1].UUID,
+	})
+
+	fakeData.insert(t, r)
+
+	t.Run("ByPage", func(t *testing.T) {
+		got, err := as.ByPage(t.Context(), "", 1)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if got.ItemCount != 2 {
+			t.Fatalf("want 2 feeds, got %d", got.ItemCount)
+		}
+
+		for _, f := range got.Feeds {
+			if f.UUID != sourceFeed.UUID {
+				continue
+			}
+
+			if f.Subscribers != 2 {
+				t.Errorf("want 2 subscribers, got %d", f.Subscribers)
+			}
+			if f.Entries != 3 {
+				t.Errorf("want 3 entries, got %d", f.Entries)
+			}
+		}
+
+		got, err = as.ByPage(t.Context(), targetFeed.Title, 1)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if got.ItemCount != 1 || got.Feeds[0].UUID != targetFeed.UUID {
+			t.Errorf("want feed %q, got %+v", targetFeed.FeedURL, got.Feeds)
+		}
+	})
+
+	t.Run("UpdateURL", func(t *testing.T) {
+		newURL := "https://feeds.example.org/source.xml"
+
+		if err := as.UpdateURL(t.Context(), sourceFeed.UUID, newURL); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := as.ByUUID(t.Context(), sourceFeed.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if got.FeedURL != newURL {
+			t.Errorf("want URL %q, got %q", newURL, got.FeedURL)
+		}
+		if got.Hash != 0 {
+			t.Errorf("want hash to be reset, got %d", got.Hash)
+		}
+
+		sourceFeed.FeedURL = newURL
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		got, err := as.Merge(t.Context(), sourceFeed.UUID, targetFeed.FeedURL)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if got.UUID != targetFeed.UUID {
+			t.Errorf("want target feed %q, got %q", targetFeed.UUID, got.UUID)
+		}
+
+		if _, err := as.ByUUID(t.Context(), sourceFeed.UUID); !errors.Is(err, feed.ErrFeedNotFound) {
+			t.Errorf("want source feed to be deleted, got %v", err)
+		}
+
+		subscribers, err := r.FeedAdminSubscriberGetAll(t.Context(), targetFeed.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(subscribers) != 2 {
+			t.Errorf("want 2 subscribers, got %d", len(subscribers))
+		}
+
+		entryMetadata, err := r.FeedEntryMetadataGetByUID(t.Context(), testUsers[0].UUID, sharedEntry.UID)
+		if err != nil {
+			t.Fatalf("want read status to be preserved, got %q", err)
+		}
+
+		if !entryMetadata.Read {
+			t.Error("want shared entry to be marked as read")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if _, err := as.Delete(t.Context(), targetFeed.UUID, ""); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if _, err := as.ByUUID(t.Context(), targetFeed.UUID); !errors.Is(err, feed.ErrFeedNotFound) {
+			t.Errorf("want feed to be deleted, got %v", err)
+		}
+
+		for _, u := range testUsers {
+			if _, err := r.FeedSubscriptionGetByFeed(t.Context(), u.UUID, targetFeed.UUID); !errors.Is(err, feed.ErrSubscriptionNotFound) {
+				t.Errorf("want subscription to be deleted, got %v", err)
+			}
+		}
+	})
+}
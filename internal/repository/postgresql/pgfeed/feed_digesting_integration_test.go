@@ -0,0 +1,159 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgfeed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgfeed"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/internal/test/assert"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestFeedDigestingRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	r := pgfeed.NewRepository(pool)
+	fs := feed.NewService(r, nil, nil, nil)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+
+	u := user.FakeUser(t, &fake)
+
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	now := time.Now().UTC()
+	fakeData := generateFakeData(t, &fake, now, testUser)
+	fakeData.insert(t, r)
+
+	t.Run("no recipients", func(t *testing.T) {
+		recipients, err := r.FeedDigestRecipientGetAll(t.Context())
+		if err != nil {
+			t.Fatalf("failed to retrieve recipients: %q", err)
+		}
+
+		if len(recipients) != 0 {
+			t.Errorf("want no recipients, got %d", len(recipients))
+		}
+	})
+
+	t.Run("subscribe to daily digests", func(t *testing.T) {
+		preferences := feed.Preferences{
+			UserUUID:         testUser.UUID,
+			ShowEntries:      feed.EntryVisibilityAll,
+			DigestFrequency:  feed.DigestFrequencyDaily,
+			DigestMarkAsRead: true,
+		}
+
+		if err := fs.UpdatePreferences(t.Context(), preferences); err != nil {
+			t.Fatalf("failed to update preferences: %q", err)
+		}
+
+		recipients, err := r.FeedDigestRecipientGetAll(t.Context())
+		if err != nil {
+			t.Fatalf("failed to retrieve recipients: %q", err)
+		}
+
+		if len(recipients) != 1 {
+			t.Fatalf("want 1 recipient, got %d", len(recipients))
+		}
+
+		got := recipients[0]
+		if got.Email != testUser.Email {
+			t.Errorf("want Email %q, got %q", testUser.Email, got.Email)
+		}
+		if got.Frequency != feed.DigestFrequencyDaily {
+			t.Errorf("want Frequency %q, got %q", feed.DigestFrequencyDaily, got.Frequency)
+		}
+		if !got.MarkAsRead {
+			t.Error("want MarkAsRead to be true")
+		}
+	})
+
+	t.Run("unread entries", func(t *testing.T) {
+		entries, err := r.FeedDigestEntryGetN(t.Context(), testUser.UUID, now.Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve entries: %q", err)
+		}
+
+		if len(entries) != 3 {
+			t.Fatalf("want 3 unread entries, got %d", len(entries))
+		}
+
+		for _, entry := range entries {
+			if entry.CategoryName != fakeData.categories[0].Name {
+				t.Errorf("want CategoryName %q, got %q", fakeData.categories[0].Name, entry.CategoryName)
+			}
+		}
+
+		entries, err = r.FeedDigestEntryGetN(t.Context(), testUser.UUID, time.Now().UTC().Add(time.Minute), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve entries: %q", err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("want no entries created in the future, got %d", len(entries))
+		}
+	})
+
+	t.Run("mark entries as read", func(t *testing.T) {
+		entries, err := r.FeedDigestEntryGetN(t.Context(), testUser.UUID, now.Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve entries: %q", err)
+		}
+
+		entryUIDs := make([]string, len(entries))
+		for i, entry := range entries {
+			entryUIDs[i] = entry.UID
+		}
+
+		if err := r.FeedDigestEntryMarkManyAsRead(t.Context(), testUser.UUID, entryUIDs); err != nil {
+			t.Fatalf("failed to mark entries as read: %q", err)
+		}
+
+		entries, err = r.FeedDigestEntryGetN(t.Context(), testUser.UUID, now.Add(-time.Hour), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve entries: %q", err)
+		}
+
+		if len(entries) != 0 {
+			t.Errorf("want no unread entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("update sent at", func(t *testing.T) {
+		sentAt := time.Now().UTC()
+
+		if err := r.FeedDigestSentAtUpdate(t.Context(), testUser.UUID, sentAt); err != nil {
+			t.Fatalf("failed to update sent at: %q", err)
+		}
+
+		recipients, err := r.FeedDigestRecipientGetAll(t.Context())
+		if err != nil {
+			t.Fatalf("failed to retrieve recipients: %q", err)
+		}
+
+		if len(recipients) != 1 {
+			t.Fatalf("want 1 recipient, got %d", len(recipients))
+		}
+
+		assert.TimeAlmostEquals(t, "SentAt", recipients[0].SentAt, sentAt, assert.TimeComparisonDelta)
+	})
+}
@@ -26,7 +26,7 @@ func TestFeedExportingService(t *testing.T) {
 	es := exporting.NewService(r)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	fake := faker.New()
 
@@ -92,4 +92,20 @@ func TestFeedExportingService(t *testing.T) {
 		assert.TimeAlmostEquals(t, "DateCreated", got.Head.DateCreated, wantDocument.Head.DateCreated, assert.TimeComparisonDelta)
 		opml.AssertOutlinesEqual(t, got.Body.Outlines, wantDocument.Body.Outlines)
 	})
+	t.Run("ExportEntryStatuses", func(t *testing.T) {
+		got, err := es.ExportEntryStatuses(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(got) != len(fakeData.entriesMetadata) {
+			t.Fatalf("want %d entry statuses, got %d", len(fakeData.entriesMetadata), len(got))
+		}
+
+		for _, entryStatus := range got {
+			if !entryStatus.Read {
+				t.Errorf("want entry %q to be read", entryStatus.URL)
+			}
+		}
+	})
 }
@@ -39,11 +39,11 @@ func TestImportingService(t *testing.T) {
 
 	// avoid a real DNS lookup for these tests' test-only hostnames
 	noopURLValidator := func(_ context.Context, _ string) error { return nil }
-	s := feed.NewService(r, feedClient, noopURLValidator)
-	is := importing.NewService(s)
+	s := feed.NewService(r, feedClient, noopURLValidator, nil)
+	is := importing.NewService(s, nil)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	u := user.FakeUser(t, new(faker.New()))
 
@@ -50,10 +50,10 @@ func TestFeedService(t *testing.T) {
 
 	// avoid a real DNS lookup for these tests' test-only hostnames
 	noopURLValidator := func(_ context.Context, _ string) error { return nil }
-	fs := feed.NewService(r, feedClient, noopURLValidator)
+	fs := feed.NewService(r, feedClient, noopURLValidator, nil)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	u := user.FakeUser(t, new(faker.New()))
 
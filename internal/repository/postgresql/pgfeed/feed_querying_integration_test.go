@@ -25,7 +25,7 @@ func TestFeedQueryingService(t *testing.T) {
 	qs := querying.NewService(r)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	fake := faker.New()
 
@@ -45,7 +45,7 @@ func (fd *fakeData) insert(t *testing.T, r *pgfeed.Repository) {
 	}
 
 	for _, subscription := range fd.subscriptions {
-		if _, err := r.FeedSubscriptionCreate(ctx, subscription); err != nil {
+		if _, err := r.FeedSubscriptionCreate(ctx, subscription, 0); err != nil {
 			t.Fatalf("failed to create subscription: %q", err)
 		}
 	}
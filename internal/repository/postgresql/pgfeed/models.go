@@ -10,6 +10,9 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	feeddigesting "github.com/virtualtam/sparklemuffin/pkg/feed/digesting"
+	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
 	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
 )
@@ -72,6 +75,47 @@ func (f *DBFeed) asFeed() feed.Feed {
 	}
 }
 
+type DBAdminFeed struct {
+	DBFeed
+
+	Subscribers uint `db:"subscribers"`
+	Entries     uint `db:"entries"`
+
+	FetchError   string     `db:"fetch_error"`
+	FetchErrorAt *time.Time `db:"fetch_error_at"`
+}
+
+func (f *DBAdminFeed) asAdminFeed() feedadministrating.Feed {
+	adminFeed := feedadministrating.Feed{
+		Feed:        f.asFeed(),
+		Subscribers: f.Subscribers,
+		Entries:     f.Entries,
+		FetchError:  f.FetchError,
+	}
+
+	if f.FetchErrorAt != nil {
+		adminFeed.FetchErrorAt = *f.FetchErrorAt
+	}
+
+	return adminFeed
+}
+
+type DBAdminSubscriber struct {
+	UserUUID    string `db:"user_uuid"`
+	NickName    string `db:"nick_name"`
+	DisplayName string `db:"display_name"`
+	Email       string `db:"email"`
+}
+
+func (s *DBAdminSubscriber) asSubscriber() feedadministrating.Subscriber {
+	return feedadministrating.Subscriber{
+		UserUUID:    s.UserUUID,
+		NickName:    s.NickName,
+		DisplayName: s.DisplayName,
+		Email:       s.Email,
+	}
+}
+
 func feedToFullTextSearchString(f feed.Feed) string {
 	return fmt.Sprintf(
 		"%s %s",
@@ -136,6 +180,24 @@ func (em *DBEntryMetadata) asEntryMetadata() feed.EntryMetadata {
 	}
 }
 
+type DBEntryStatus struct {
+	FeedURL     string    `db:"feed_url"`
+	URL         string    `db:"url"`
+	Title       string    `db:"title"`
+	PublishedAt time.Time `db:"published_at"`
+	Read        bool      `db:"read"`
+}
+
+func (es *DBEntryStatus) asEntryStatus() feedexporting.EntryStatus {
+	return feedexporting.EntryStatus{
+		FeedURL:     es.FeedURL,
+		URL:         es.URL,
+		Title:       es.Title,
+		PublishedAt: es.PublishedAt,
+		Read:        es.Read,
+	}
+}
+
 type DBQueryingSubscribedFeedEntry struct {
 	DBEntry
 
@@ -190,9 +252,53 @@ type DBPreferences struct {
 	UserUUID           string    `db:"user_uuid"`
 	ShowEntries        string    `db:"show_entries"`
 	ShowEntrySummaries bool      `db:"show_entry_summaries"`
+	DigestFrequency    string    `db:"digest_frequency"`
+	DigestMarkAsRead   bool      `db:"digest_mark_as_read"`
 	UpdatedAt          time.Time `db:"updated_at"`
 }
 
+type DBDigestRecipient struct {
+	UserUUID   string    `db:"user_uuid"`
+	NickName   string    `db:"nick_name"`
+	Email      string    `db:"email"`
+	Frequency  string    `db:"digest_frequency"`
+	MarkAsRead bool      `db:"digest_mark_as_read"`
+	SentAt     time.Time `db:"digest_sent_at"`
+}
+
+func (r *DBDigestRecipient) asRecipient() feeddigesting.Recipient {
+	return feeddigesting.Recipient{
+		UserUUID:   r.UserUUID,
+		NickName:   r.NickName,
+		Email:      r.Email,
+		Frequency:  feed.DigestFrequency(r.Frequency),
+		MarkAsRead: r.MarkAsRead,
+		SentAt:     r.SentAt,
+	}
+}
+
+type DBDigestEntry struct {
+	UID          string    `db:"uid"`
+	URL          string    `db:"url"`
+	Title        string    `db:"title"`
+	Summary      string    `db:"summary"`
+	PublishedAt  time.Time `db:"published_at"`
+	FeedTitle    string    `db:"feed_title"`
+	CategoryName string    `db:"category_name"`
+}
+
+func (e *DBDigestEntry) asEntry() feeddigesting.Entry {
+	return feeddigesting.Entry{
+		UID:          e.UID,
+		URL:          e.URL,
+		Title:        e.Title,
+		Summary:      e.Summary,
+		PublishedAt:  e.PublishedAt,
+		FeedTitle:    e.FeedTitle,
+		CategoryName: e.CategoryName,
+	}
+}
+
 type DBSubscription struct {
 	UUID         string `db:"uuid"`
 	CategoryUUID string `db:"category_uuid"`
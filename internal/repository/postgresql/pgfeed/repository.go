@@ -14,13 +14,19 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
+	feeddigesting "github.com/virtualtam/sparklemuffin/pkg/feed/digesting"
 	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
 	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
 var _ feed.Repository = &Repository{}
+var _ feedadministrating.Repository = &Repository{}
+var _ feeddigesting.Repository = &Repository{}
 var _ feedexporting.Repository = &Repository{}
 var _ feedquerying.Repository = &Repository{}
 var _ feedsynchronizing.Repository = &Repository{}
@@ -39,6 +45,185 @@ const (
 	domain = "feeds"
 )
 
+func (r *Repository) FeedAdminDelete(ctx context.Context, feedUUID string) error {
+	commandTag, err := r.Pool.Exec(ctx, "DELETE FROM feed_feeds WHERE uuid=$1", feedUUID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return feed.ErrFeedNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) FeedAdminGetByURL(ctx context.Context, feedURL string) (feedadministrating.Feed, error) {
+	query := adminFeedSelectQuery + `
+	WHERE f.feed_url=$1`
+
+	return r.adminFeedGetQuery(ctx, query, feedURL)
+}
+
+func (r *Repository) FeedAdminGetByUUID(ctx context.Context, feedUUID string) (feedadministrating.Feed, error) {
+	query := adminFeedSelectQuery + `
+	WHERE f.uuid=$1`
+
+	return r.adminFeedGetQuery(ctx, query, feedUUID)
+}
+
+func (r *Repository) FeedAdminGetCount(ctx context.Context, searchTerms string) (uint, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM feed_feeds
+	WHERE feed_url ILIKE $1
+	OR    title    ILIKE $1`
+
+	var count uint
+
+	if err := r.Pool.QueryRow(ctx, query, "%"+searchTerms+"%").Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) FeedAdminGetManyByURLs(ctx context.Context, feedURLs []string) ([]feedadministrating.Feed, error) {
+	query := adminFeedSelectQuery + `
+	WHERE f.feed_url = ANY($1)
+	ORDER BY f.feed_url`
+
+	return r.adminFeedGetManyQuery(ctx, query, feedURLs)
+}
+
+func (r *Repository) FeedAdminGetN(ctx context.Context, searchTerms string, n uint, offset uint) ([]feedadministrating.Feed, error) {
+	query := adminFeedSelectQuery + `
+	WHERE f.feed_url ILIKE $1
+	OR    f.title    ILIKE $1
+	ORDER BY f.feed_url
+	LIMIT $2
+	OFFSET $3`
+
+	return r.adminFeedGetManyQuery(ctx, query, "%"+searchTerms+"%", n, offset)
+}
+
+func (r *Repository) FeedAdminMerge(ctx context.Context, sourceUUID string, targetUUID string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "FeedAdminMerge")
+
+	// 1. Preserve the read status of entries published by both feeds
+	_, err = tx.Exec(
+		ctx,
+		`
+		INSERT INTO feed_entries_metadata(user_uuid, entry_uid, read)
+		SELECT fem.user_uuid, te.uid, fem.read
+		FROM feed_entries_metadata fem
+		INNER JOIN feed_entries se ON se.uid = fem.entry_uid
+		INNER JOIN feed_entries te ON te.url = se.url
+		WHERE se.feed_uuid=$1
+		AND   te.feed_uuid=$2
+		ON CONFLICT (user_uuid, entry_uid) DO NOTHING`,
+		sourceUUID,
+		targetUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	// 2. Delete subscriptions of users already subscribed to the target feed
+	_, err = tx.Exec(
+		ctx,
+		`
+		DELETE FROM feed_subscriptions
+		WHERE feed_uuid=$1
+		AND   user_uuid IN (
+			SELECT user_uuid
+			FROM feed_subscriptions
+			WHERE feed_uuid=$2
+		)`,
+		sourceUUID,
+		targetUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	// 3. Move the remaining subscriptions to the target feed
+	_, err = tx.Exec(
+		ctx,
+		"UPDATE feed_subscriptions SET feed_uuid=$2 WHERE feed_uuid=$1",
+		sourceUUID,
+		targetUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	// 4. Delete the source feed (cascaded to its entries)
+	commandTag, err := tx.Exec(ctx, "DELETE FROM feed_feeds WHERE uuid=$1", sourceUUID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return feed.ErrFeedNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) FeedAdminSubscriberGetAll(ctx context.Context, feedUUID string) ([]feedadministrating.Subscriber, error) {
+	query := `
+	SELECT u.uuid AS user_uuid, u.nick_name, u.display_name, u.email
+	FROM feed_subscriptions fs
+	INNER JOIN users u ON u.uuid = fs.user_uuid
+	WHERE fs.feed_uuid=$1
+	ORDER BY u.nick_name`
+
+	rows, err := r.Pool.Query(ctx, query, feedUUID)
+	if err != nil {
+		return []feedadministrating.Subscriber{}, err
+	}
+	defer rows.Close()
+
+	var dbSubscribers []DBAdminSubscriber
+	if err := pgxscan.ScanAll(&dbSubscribers, rows); err != nil {
+		return []feedadministrating.Subscriber{}, err
+	}
+
+	subscribers := make([]feedadministrating.Subscriber, len(dbSubscribers))
+	for i, dbSubscriber := range dbSubscribers {
+		subscribers[i] = dbSubscriber.asSubscriber()
+	}
+
+	return subscribers, nil
+}
+
+func (r *Repository) FeedAdminURLUpdate(ctx context.Context, feedUUID string, feedURL string, updatedAt time.Time) error {
+	query := `
+	UPDATE feed_feeds
+	SET
+		feed_url=@feed_url,
+		etag='',
+		last_modified=@last_modified,
+		hash_xxhash64=0,
+		updated_at=@updated_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":          feedUUID,
+		"feed_url":      feedURL,
+		"last_modified": time.Time{},
+		"updated_at":    updatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "FeedAdminURLUpdate", query, args)
+}
+
 func (r *Repository) FeedCreate(ctx context.Context, f feed.Feed) error {
 	query := `
 	INSERT INTO feed_feeds(
@@ -129,6 +314,23 @@ func (r *Repository) FeedGetNByLastSynchronizationTime(ctx context.Context, n ui
 	return r.feedGetManyQuery(ctx, query, before, n)
 }
 
+func (r *Repository) FeedUpdateFetchError(ctx context.Context, feedFetchError feedsynchronizing.FeedFetchError) error {
+	query := `
+	UPDATE feed_feeds
+	SET
+		fetch_error=@fetch_error,
+		fetch_error_at=@fetch_error_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":           feedFetchError.UUID,
+		"fetch_error":    feedFetchError.Error,
+		"fetch_error_at": feedFetchError.FailedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "FeedUpdateFetchError", query, args)
+}
+
 func (r *Repository) FeedUpdateFetchMetadata(ctx context.Context, feedFetchMetadata feedsynchronizing.FeedFetchMetadata) error {
 	query := `
 	UPDATE feed_feeds
@@ -136,7 +338,9 @@ func (r *Repository) FeedUpdateFetchMetadata(ctx context.Context, feedFetchMetad
 		etag=@etag,
 		last_modified=@last_modified,
 		updated_at=@updated_at,
-		fetched_at=@fetched_at
+		fetched_at=@fetched_at,
+		fetch_error='',
+		fetch_error_at=NULL
 	WHERE uuid=@uuid`
 
 	args := pgx.NamedArgs{
@@ -351,6 +555,128 @@ func (r *Repository) FeedCategoryUpdate(ctx context.Context, c feed.Category) er
 	return r.QueryTx(ctx, domain, "FeedCategoryUpdate", query, args)
 }
 
+func (r *Repository) FeedDigestRecipientGetAll(ctx context.Context) ([]feeddigesting.Recipient, error) {
+	const query = `
+	SELECT
+		fp.user_uuid,
+		u.nick_name,
+		u.email,
+		fp.digest_frequency,
+		fp.digest_mark_as_read,
+		fp.digest_sent_at
+	FROM feed_preferences fp
+	JOIN users u ON u.uuid = fp.user_uuid
+	WHERE fp.digest_frequency != 'NONE'::feed_digest_frequency`
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return []feeddigesting.Recipient{}, err
+	}
+	defer rows.Close()
+
+	var dbRecipients []DBDigestRecipient
+
+	if err := pgxscan.ScanAll(&dbRecipients, rows); err != nil {
+		return []feeddigesting.Recipient{}, err
+	}
+
+	recipients := make([]feeddigesting.Recipient, len(dbRecipients))
+
+	for i, dbRecipient := range dbRecipients {
+		recipients[i] = dbRecipient.asRecipient()
+	}
+
+	return recipients, nil
+}
+
+func (r *Repository) FeedDigestEntryGetN(ctx context.Context, userUUID string, since time.Time, n uint) ([]feeddigesting.Entry, error) {
+	const query = `
+	SELECT
+		fe.uid,
+		fe.url,
+		fe.title,
+		fe.summary,
+		fe.published_at,
+		COALESCE(NULLIF(fs.alias, ''), f.title) AS feed_title,
+		fc.name AS category_name
+	FROM feed_entries fe
+	JOIN feed_subscriptions fs ON fs.feed_uuid = fe.feed_uuid
+	JOIN feed_feeds f ON f.uuid = fe.feed_uuid
+	JOIN feed_categories fc ON fc.uuid = fs.category_uuid
+	LEFT JOIN feed_entries_metadata fem ON fem.entry_uid = fe.uid AND fem.user_uuid = fs.user_uuid
+	WHERE fs.user_uuid = @user_uuid
+	AND   fe.created_at > @since
+	AND   COALESCE(fem.read, FALSE) = FALSE
+	ORDER BY fc.name, fe.published_at DESC
+	LIMIT @limit`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+		"since":     since,
+		"limit":     n,
+	}
+
+	rows, err := r.Pool.Query(ctx, query, args)
+	if err != nil {
+		return []feeddigesting.Entry{}, err
+	}
+	defer rows.Close()
+
+	var dbEntries []DBDigestEntry
+
+	if err := pgxscan.ScanAll(&dbEntries, rows); err != nil {
+		return []feeddigesting.Entry{}, err
+	}
+
+	entries := make([]feeddigesting.Entry, len(dbEntries))
+
+	for i, dbEntry := range dbEntries {
+		entries[i] = dbEntry.asEntry()
+	}
+
+	return entries, nil
+}
+
+func (r *Repository) FeedDigestEntryMarkManyAsRead(ctx context.Context, userUUID string, entryUIDs []string) error {
+	query := `
+	INSERT INTO feed_entries_metadata(
+		user_uuid,
+		entry_uid,
+		read
+	)
+
+	SELECT @user_uuid, fe.uid, TRUE
+	FROM feed_entries fe
+	JOIN feed_subscriptions fs ON fs.feed_uuid=fe.feed_uuid
+	WHERE fs.user_uuid=@user_uuid
+	AND   fe.uid = ANY(@entry_uids)
+
+	ON CONFLICT(user_uuid, entry_uid) DO UPDATE SET read=TRUE
+	`
+
+	args := pgx.NamedArgs{
+		"user_uuid":  userUUID,
+		"entry_uids": entryUIDs,
+	}
+
+	return r.QueryTx(ctx, domain, "FeedDigestEntryMarkManyAsRead", query, args)
+}
+
+func (r *Repository) FeedDigestSentAtUpdate(ctx context.Context, userUUID string, sentAt time.Time) error {
+	query := `
+	UPDATE feed_preferences
+	SET digest_sent_at=@digest_sent_at
+	WHERE user_uuid=@user_uuid
+	`
+
+	args := pgx.NamedArgs{
+		"user_uuid":      userUUID,
+		"digest_sent_at": sentAt,
+	}
+
+	return r.QueryTx(ctx, domain, "FeedDigestSentAtUpdate", query, args)
+}
+
 func (r *Repository) FeedEntryCreateMany(ctx context.Context, entries []feed.Entry) (int64, error) {
 	return r.feedEntryUpsertMany(ctx, "FeedEntryCreateMany", "ON CONFLICT DO NOTHING", entries)
 }
@@ -402,42 +728,39 @@ func (r *Repository) FeedEntryGetCountBySubscription(ctx context.Context, userUU
 	return r.feedEntryGetCount(ctx, and, showEntries, args)
 }
 
-func (r *Repository) FeedEntryGetCountByQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, searchTerms string) (uint, error) {
-	const and = `AND (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-
+func (r *Repository) FeedEntryGetCountByQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, query search.Query) (uint, error) {
 	args := pgx.NamedArgs{
-		"user_uuid":    userUUID,
-		"search_terms": pgbase.FullTextSearchReplacer.Replace(searchTerms),
+		"user_uuid": userUUID,
 	}
 
+	and := feedEntrySearchConditions(query, args)
+
 	return r.feedEntryGetCount(ctx, and, showEntries, args)
 }
 
-func (r *Repository) FeedEntryGetCountByCategoryAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, searchTerms string) (uint, error) {
-	const and = `
-		AND fs.category_uuid=@category_uuid
-		AND (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-
+func (r *Repository) FeedEntryGetCountByCategoryAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, query search.Query) (uint, error) {
 	args := pgx.NamedArgs{
 		"user_uuid":     userUUID,
 		"category_uuid": categoryUUID,
-		"search_terms":  pgbase.FullTextSearchReplacer.Replace(searchTerms),
 	}
 
+	and := `
+		AND fs.category_uuid=@category_uuid
+		` + feedEntrySearchConditions(query, args)
+
 	return r.feedEntryGetCount(ctx, and, showEntries, args)
 }
 
-func (r *Repository) FeedEntryGetCountBySubscriptionAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, searchTerms string) (uint, error) {
-	const and = `
-		AND fs.uuid=@subscription_uuid
-		AND (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-
+func (r *Repository) FeedEntryGetCountBySubscriptionAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, query search.Query) (uint, error) {
 	args := pgx.NamedArgs{
 		"user_uuid":         userUUID,
 		"subscription_uuid": subscriptionUUID,
-		"search_terms":      pgbase.FullTextSearchReplacer.Replace(searchTerms),
 	}
 
+	and := `
+		AND fs.uuid=@subscription_uuid
+		` + feedEntrySearchConditions(query, args)
+
 	return r.feedEntryGetCount(ctx, and, showEntries, args)
 }
 
@@ -584,7 +907,7 @@ func (r *Repository) FeedEntryMetadataUpdate(ctx context.Context, entryMetadata
 func (r *Repository) FeedPreferencesGetByUserUUID(ctx context.Context, userUUID string) (feed.Preferences, error) {
 	const (
 		query = `
-		SELECT user_uuid, show_entries, show_entry_summaries, updated_at
+		SELECT user_uuid, show_entries, show_entry_summaries, digest_frequency, digest_mark_as_read, updated_at
 		FROM feed_preferences
 		WHERE user_uuid=$1`
 	)
@@ -608,6 +931,8 @@ func (r *Repository) FeedPreferencesGetByUserUUID(ctx context.Context, userUUID
 		UserUUID:           dbPreferences.UserUUID,
 		ShowEntries:        feed.EntryVisibility(dbPreferences.ShowEntries),
 		ShowEntrySummaries: dbPreferences.ShowEntrySummaries,
+		DigestFrequency:    feed.DigestFrequency(dbPreferences.DigestFrequency),
+		DigestMarkAsRead:   dbPreferences.DigestMarkAsRead,
 		UpdatedAt:          dbPreferences.UpdatedAt,
 	}, nil
 }
@@ -619,6 +944,8 @@ func (r *Repository) FeedPreferencesUpdate(ctx context.Context, preferences feed
 		SET
 			show_entries=@show_entries,
 			show_entry_summaries=@show_entry_summaries,
+			digest_frequency=@digest_frequency,
+			digest_mark_as_read=@digest_mark_as_read,
 			updated_at=@updated_at
 		WHERE user_uuid=@user_uuid`
 	)
@@ -627,6 +954,8 @@ func (r *Repository) FeedPreferencesUpdate(ctx context.Context, preferences feed
 		"user_uuid":            preferences.UserUUID,
 		"show_entries":         preferences.ShowEntries,
 		"show_entry_summaries": preferences.ShowEntrySummaries,
+		"digest_frequency":     preferences.DigestFrequency,
+		"digest_mark_as_read":  preferences.DigestMarkAsRead,
 		"updated_at":           preferences.UpdatedAt,
 	}
 
@@ -659,6 +988,45 @@ func (r *Repository) FeedCategorySubscriptionsGetAll(ctx context.Context, userUU
 	return categoriesSubscriptions, nil
 }
 
+func (r *Repository) FeedEntryStatusesGetAll(ctx context.Context, userUUID string) ([]feedexporting.EntryStatus, error) {
+	query := `
+	SELECT
+		f.feed_url,
+		fe.url,
+		fe.title,
+		fe.published_at,
+		fem.read
+	FROM feed_entries_metadata fem
+	JOIN feed_entries fe ON fe.uid = fem.entry_uid
+	JOIN feed_feeds f ON f.uuid = fe.feed_uuid
+	WHERE fem.user_uuid = @user_uuid
+	ORDER BY f.feed_url, fe.published_at DESC`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+	}
+
+	rows, err := r.Pool.Query(ctx, query, args)
+	if err != nil {
+		return []feedexporting.EntryStatus{}, err
+	}
+	defer rows.Close()
+
+	var dbEntryStatuses []DBEntryStatus
+
+	if err := pgxscan.ScanAll(&dbEntryStatuses, rows); err != nil {
+		return []feedexporting.EntryStatus{}, err
+	}
+
+	entryStatuses := make([]feedexporting.EntryStatus, len(dbEntryStatuses))
+
+	for i, dbEntryStatus := range dbEntryStatuses {
+		entryStatuses[i] = dbEntryStatus.asEntryStatus()
+	}
+
+	return entryStatuses, nil
+}
+
 func (r *Repository) FeedSubscriptionCategoryGetAll(ctx context.Context, userUUID string) ([]feedquerying.SubscribedFeedsByCategory, error) {
 	dbCategories, err := r.feedGetCategories(ctx, userUUID)
 	if err != nil {
@@ -786,57 +1154,49 @@ func (r *Repository) FeedSubscriptionEntryGetNBySubscription(ctx context.Context
 	return r.feedSubscriptionEntryGetN(ctx, where, preferences.ShowEntries, args)
 }
 
-func (r *Repository) FeedSubscriptionEntryGetNByQuery(ctx context.Context, userUUID string, preferences feed.Preferences, searchTerms string, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
-	const (
-		where = `
-		WHERE fs.user_uuid=@user_uuid
-		AND   (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-	)
-
+func (r *Repository) FeedSubscriptionEntryGetNByQuery(ctx context.Context, userUUID string, preferences feed.Preferences, query search.Query, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
 	args := pgx.NamedArgs{
-		"user_uuid":    userUUID,
-		"search_terms": pgbase.FullTextSearchReplacer.Replace(searchTerms),
-		"limit":        n,
-		"offset":       offset,
+		"user_uuid": userUUID,
+		"limit":     n,
+		"offset":    offset,
 	}
 
+	where := `
+		WHERE fs.user_uuid=@user_uuid
+		` + feedEntrySearchConditions(query, args)
+
 	return r.feedSubscriptionEntryGetN(ctx, where, preferences.ShowEntries, args)
 }
 
-func (r *Repository) FeedSubscriptionEntryGetNByCategoryAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, searchTerms string, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
-	const (
-		where = `
-		WHERE fs.user_uuid=@user_uuid
-		AND   fs.category_uuid=@category_uuid
-		AND   (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-	)
-
+func (r *Repository) FeedSubscriptionEntryGetNByCategoryAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, query search.Query, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
 	args := pgx.NamedArgs{
 		"user_uuid":     userUUID,
 		"category_uuid": categoryUUID,
-		"search_terms":  pgbase.FullTextSearchReplacer.Replace(searchTerms),
 		"limit":         n,
 		"offset":        offset,
 	}
 
+	where := `
+		WHERE fs.user_uuid=@user_uuid
+		AND   fs.category_uuid=@category_uuid
+		` + feedEntrySearchConditions(query, args)
+
 	return r.feedSubscriptionEntryGetN(ctx, where, preferences.ShowEntries, args)
 }
 
-func (r *Repository) FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, searchTerms string, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
-	const (
-		where = `
-		WHERE fs.user_uuid=@user_uuid
-		AND   fs.uuid=@subscription_uuid
-		AND   (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)`
-	)
-
+func (r *Repository) FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, query search.Query, n uint, offset uint) ([]feedquerying.SubscribedFeedEntry, error) {
 	args := pgx.NamedArgs{
 		"user_uuid":         userUUID,
 		"subscription_uuid": subscriptionUUID,
-		"search_terms":      pgbase.FullTextSearchReplacer.Replace(searchTerms),
 		"limit":             n,
 		"offset":            offset,
 	}
+
+	where := `
+		WHERE fs.user_uuid=@user_uuid
+		AND   fs.uuid=@subscription_uuid
+		` + feedEntrySearchConditions(query, args)
+
 	return r.feedSubscriptionEntryGetN(ctx, where, preferences.ShowEntries, args)
 }
 
@@ -849,7 +1209,7 @@ func (r *Repository) FeedSubscriptionIsRegistered(ctx context.Context, userUUID
 	)
 }
 
-func (r *Repository) FeedSubscriptionCreate(ctx context.Context, s feed.Subscription) (feed.Subscription, error) {
+func (r *Repository) FeedSubscriptionCreate(ctx context.Context, s feed.Subscription, maxSubscriptions int64) (feed.Subscription, error) {
 	query := `
 	INSERT INTO feed_subscriptions(
 		uuid,
@@ -877,7 +1237,35 @@ func (r *Repository) FeedSubscriptionCreate(ctx context.Context, s feed.Subscrip
 		"updated_at":    s.UpdatedAt,
 	}
 
-	if err := r.QueryTx(ctx, domain, "FeedSubscriptionCreate", query, args); err != nil {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return feed.Subscription{}, err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "FeedSubscriptionCreate")
+
+	if maxSubscriptions > 0 {
+		// lock the user's row, so that concurrent subscriptions are counted one after the other
+		if _, err := tx.Exec(ctx, "SELECT 1 FROM users WHERE uuid=$1 FOR NO KEY UPDATE", s.UserUUID); err != nil {
+			return feed.Subscription{}, err
+		}
+
+		var current int64
+
+		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM feed_subscriptions WHERE user_uuid=$1", s.UserUUID).Scan(&current); err != nil {
+			return feed.Subscription{}, err
+		}
+
+		if err := quota.CheckSubscriptionCount(maxSubscriptions, current, 1); err != nil {
+			return feed.Subscription{}, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return feed.Subscription{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return feed.Subscription{}, err
 	}
 
@@ -7,15 +7,69 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedadministrating "github.com/virtualtam/sparklemuffin/pkg/feed/administrating"
 	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
+const adminFeedSelectQuery = `
+	SELECT
+		f.uuid, f.feed_url, f.title, f.description, f.slug, f.etag, f.last_modified, f.hash_xxhash64,
+		f.created_at, f.updated_at, f.fetched_at, f.fetch_error, f.fetch_error_at,
+		(SELECT COUNT(*) FROM feed_subscriptions fs WHERE fs.feed_uuid = f.uuid) AS subscribers,
+		(SELECT COUNT(*) FROM feed_entries fe WHERE fe.feed_uuid = f.uuid)       AS entries
+	FROM feed_feeds f`
+
+func (r *Repository) adminFeedGetQuery(ctx context.Context, query string, queryParams ...any) (feedadministrating.Feed, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return feedadministrating.Feed{}, err
+	}
+	defer rows.Close()
+
+	dbAdminFeed := &DBAdminFeed{}
+	err = pgxscan.ScanOne(dbAdminFeed, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return feedadministrating.Feed{}, feed.ErrFeedNotFound
+	}
+	if err != nil {
+		return feedadministrating.Feed{}, err
+	}
+
+	return dbAdminFeed.asAdminFeed(), nil
+}
+
+func (r *Repository) adminFeedGetManyQuery(ctx context.Context, query string, queryParams ...any) ([]feedadministrating.Feed, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return []feedadministrating.Feed{}, err
+	}
+	defer rows.Close()
+
+	var dbAdminFeeds []DBAdminFeed
+
+	if err := pgxscan.ScanAll(&dbAdminFeeds, rows); err != nil {
+		return []feedadministrating.Feed{}, err
+	}
+
+	feeds := make([]feedadministrating.Feed, len(dbAdminFeeds))
+
+	for i, dbAdminFeed := range dbAdminFeeds {
+		feeds[i] = dbAdminFeed.asAdminFeed()
+	}
+
+	return feeds, nil
+}
+
 func (r *Repository) feedGetQuery(ctx context.Context, query string, queryParams ...any) (feed.Feed, error) {
 	rows, err := r.Pool.Query(ctx, query, queryParams...)
 	if err != nil {
@@ -380,3 +434,56 @@ ORDER BY
 
 	return dbSubscriptionTitles, nil
 }
+
+// feedEntrySearchConditions returns the SQL conditions restricting feed entries to those matching
+// a search query, and sets the corresponding named arguments.
+//
+// Values are always passed as query arguments, never interpolated into the SQL statement.
+func feedEntrySearchConditions(query search.Query, args pgx.NamedArgs) string {
+	var conditions []string
+
+	if query.HasTerms() {
+		conditions = append(conditions, "AND (f.fulltextsearch_tsv || fe.fulltextsearch_tsv) @@ websearch_to_tsquery(@search_terms)")
+		args["search_terms"] = pgbase.FullTextSearchReplacer.Replace(query.Terms)
+	}
+
+	if len(query.Feeds) > 0 {
+		conditions = append(conditions, "AND (f.slug = ANY(@feeds) OR LOWER(f.title) = ANY(@feeds) OR LOWER(fs.alias) = ANY(@feeds))")
+		args["feeds"] = query.Feeds
+	}
+
+	if len(query.Categories) > 0 {
+		conditions = append(conditions, `AND fs.category_uuid IN (
+			SELECT fc.uuid
+			FROM feed_categories fc
+			WHERE fc.user_uuid=@user_uuid
+			AND   (fc.slug = ANY(@categories) OR LOWER(fc.name) = ANY(@categories))
+		)`)
+		args["categories"] = query.Categories
+	}
+
+	if len(query.Sites) > 0 {
+		conditions = append(conditions, "AND fe.url ~* ANY(@site_patterns)")
+		args["site_patterns"] = pgbase.SiteURLPatterns(query.Sites)
+	}
+
+	if query.Read {
+		conditions = append(conditions, "AND COALESCE(fem.read, FALSE) = TRUE")
+	}
+
+	if query.Unread {
+		conditions = append(conditions, "AND COALESCE(fem.read, FALSE) = FALSE")
+	}
+
+	if !query.After.IsZero() {
+		conditions = append(conditions, "AND fe.published_at >= @published_after")
+		args["published_after"] = query.After
+	}
+
+	if !query.Before.IsZero() {
+		conditions = append(conditions, "AND fe.published_at < @published_before")
+		args["published_before"] = query.Before
+	}
+
+	return strings.Join(conditions, "\n\t\t")
+}
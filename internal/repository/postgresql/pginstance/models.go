@@ -0,0 +1,60 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pginstance
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+)
+
+type DBCounts struct {
+	Users          int64 `db:"users"`
+	Bookmarks      int64 `db:"bookmarks"`
+	Feeds          int64 `db:"feeds"`
+	FeedEntries    int64 `db:"feed_entries"`
+	Subscriptions  int64 `db:"subscriptions"`
+	ActiveSessions int64 `db:"active_sessions"`
+	FeedsInError   int64 `db:"feeds_in_error"`
+}
+
+func (c *DBCounts) asCounts() instance.Counts {
+	return instance.Counts{
+		Users:          c.Users,
+		Bookmarks:      c.Bookmarks,
+		Feeds:          c.Feeds,
+		FeedEntries:    c.FeedEntries,
+		Subscriptions:  c.Subscriptions,
+		ActiveSessions: c.ActiveSessions,
+		FeedsInError:   c.FeedsInError,
+	}
+}
+
+type DBFeedError struct {
+	FeedURL  string    `db:"feed_url"`
+	Title    string    `db:"title"`
+	Error    string    `db:"fetch_error"`
+	FailedAt time.Time `db:"fetch_error_at"`
+}
+
+func (e *DBFeedError) asFeedError() instance.FeedError {
+	return instance.FeedError{
+		FeedURL:  e.FeedURL,
+		Title:    e.Title,
+		Error:    e.Error,
+		FailedAt: e.FailedAt,
+	}
+}
+
+type DBTableSize struct {
+	Name      string `db:"name"`
+	SizeBytes int64  `db:"size_bytes"`
+}
+
+func (s *DBTableSize) asTableSize() instance.TableSize {
+	return instance.TableSize{
+		Name:      s.Name,
+		SizeBytes: s.SizeBytes,
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pginstance
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+)
+
+var _ instance.Repository = &Repository{}
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for instance-wide statistics.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) InstanceCountsGet(ctx context.Context) (instance.Counts, error) {
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM users)                                            AS users,
+		(SELECT COUNT(*) FROM bookmarks)                                        AS bookmarks,
+		(SELECT COUNT(*) FROM feed_feeds)                                       AS feeds,
+		(SELECT COUNT(*) FROM feed_entries)                                     AS feed_entries,
+		(SELECT COUNT(*) FROM feed_subscriptions)                               AS subscriptions,
+		(SELECT COUNT(*) FROM sessions WHERE remember_token_expires_at > NOW()) AS active_sessions,
+		(SELECT COUNT(*) FROM feed_feeds WHERE fetch_error <> '')               AS feeds_in_error`
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return instance.Counts{}, err
+	}
+	defer rows.Close()
+
+	dbCounts := &DBCounts{}
+	if err := pgxscan.ScanOne(dbCounts, rows); err != nil {
+		return instance.Counts{}, err
+	}
+
+	return dbCounts.asCounts(), nil
+}
+
+func (r *Repository) InstanceFeedErrorsGetN(ctx context.Context, n uint) ([]instance.FeedError, error) {
+	query := `
+	SELECT feed_url, title, fetch_error, fetch_error_at
+	FROM feed_feeds
+	WHERE fetch_error <> ''
+	ORDER BY fetch_error_at DESC
+	LIMIT $1`
+
+	rows, err := r.Pool.Query(ctx, query, n)
+	if err != nil {
+		return []instance.FeedError{}, err
+	}
+	defer rows.Close()
+
+	var dbFeedErrors []DBFeedError
+	if err := pgxscan.ScanAll(&dbFeedErrors, rows); err != nil {
+		return []instance.FeedError{}, err
+	}
+
+	feedErrors := make([]instance.FeedError, len(dbFeedErrors))
+	for i, dbFeedError := range dbFeedErrors {
+		feedErrors[i] = dbFeedError.asFeedError()
+	}
+
+	return feedErrors, nil
+}
+
+func (r *Repository) InstanceTableSizesGet(ctx context.Context) ([]instance.TableSize, error) {
+	query := `
+	SELECT
+		c.relname                     AS name,
+		pg_total_relation_size(c.oid) AS size_bytes
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = current_schema()
+	AND c.relkind = 'r'
+	ORDER BY size_bytes DESC, name`
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return []instance.TableSize{}, err
+	}
+	defer rows.Close()
+
+	var dbTableSizes []DBTableSize
+	if err := pgxscan.ScanAll(&dbTableSizes, rows); err != nil {
+		return []instance.TableSize{}, err
+	}
+
+	tableSizes := make([]instance.TableSize, len(dbTableSizes))
+	for i, dbTableSize := range dbTableSizes {
+		tableSizes[i] = dbTableSize.asTableSize()
+	}
+
+	return tableSizes, nil
+}
@@ -0,0 +1,154 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pginstance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgfeed"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pginstance"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedsynchronizing "github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/instance"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	fake := faker.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	us := user.NewService(pguser.NewRepository(pool), user.FakePasswordHasher(t))
+	if err := us.Add(t.Context(), user.FakeUser(t, &fake)); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	fr := pgfeed.NewRepository(pool)
+
+	healthyFeed := feed.Feed{
+		UUID:      fake.UUID().V4(),
+		FeedURL:   "https://healthy.example.org/feed.xml",
+		Title:     "Healthy",
+		Slug:      "healthy",
+		CreatedAt: now,
+		UpdatedAt: now,
+		FetchedAt: now,
+	}
+	failingFeed := feed.Feed{
+		UUID:      fake.UUID().V4(),
+		FeedURL:   "https://failing.example.org/feed.xml",
+		Title:     "Failing",
+		Slug:      "failing",
+		CreatedAt: now,
+		UpdatedAt: now,
+		FetchedAt: now,
+	}
+
+	for _, f := range []feed.Feed{healthyFeed, failingFeed} {
+		if err := fr.FeedCreate(t.Context(), f); err != nil {
+			t.Fatalf("failed to create feed: %q", err)
+		}
+	}
+
+	fetchError := feedsynchronizing.FeedFetchError{
+		UUID:     failingFeed.UUID,
+		Error:    "connection refused",
+		FailedAt: now,
+	}
+	if err := fr.FeedUpdateFetchError(t.Context(), fetchError); err != nil {
+		t.Fatalf("failed to record fetch error: %q", err)
+	}
+
+	r := pginstance.NewRepository(pool)
+
+	t.Run("counts", func(t *testing.T) {
+		got, err := r.InstanceCountsGet(t.Context())
+		if err != nil {
+			t.Fatalf("failed to retrieve counts: %q", err)
+		}
+
+		want := instance.Counts{
+			Users:        1,
+			Feeds:        2,
+			FeedsInError: 1,
+		}
+		if got != want {
+			t.Errorf("want counts %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("feed errors", func(t *testing.T) {
+		got, err := r.InstanceFeedErrorsGetN(t.Context(), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve feed errors: %q", err)
+		}
+
+		if len(got) != 1 {
+			t.Fatalf("want 1 feed error, got %d", len(got))
+		}
+
+		want := instance.FeedError{
+			FeedURL:  failingFeed.FeedURL,
+			Title:    failingFeed.Title,
+			Error:    fetchError.Error,
+			FailedAt: now,
+		}
+		if !got[0].FailedAt.Equal(want.FailedAt) {
+			t.Errorf("want FailedAt %q, got %q", want.FailedAt, got[0].FailedAt)
+		}
+		got[0].FailedAt = want.FailedAt
+
+		if got[0] != want {
+			t.Errorf("want feed error %+v, got %+v", want, got[0])
+		}
+	})
+
+	t.Run("fetch error cleared on successful fetch", func(t *testing.T) {
+		fetchMetadata := feedsynchronizing.FeedFetchMetadata{
+			UUID:      failingFeed.UUID,
+			UpdatedAt: now,
+			FetchedAt: now,
+		}
+		if err := fr.FeedUpdateFetchMetadata(t.Context(), fetchMetadata); err != nil {
+			t.Fatalf("failed to update fetch metadata: %q", err)
+		}
+
+		got, err := r.InstanceFeedErrorsGetN(t.Context(), 10)
+		if err != nil {
+			t.Fatalf("failed to retrieve feed errors: %q", err)
+		}
+
+		if len(got) != 0 {
+			t.Errorf("want no feed errors, got %d", len(got))
+		}
+	})
+
+	t.Run("table sizes", func(t *testing.T) {
+		got, err := r.InstanceTableSizesGet(t.Context())
+		if err != nil {
+			t.Fatalf("failed to retrieve table sizes: %q", err)
+		}
+
+		found := false
+		for _, tableSize := range got {
+			if tableSize.Name == "feed_feeds" {
+				found = true
+
+				if tableSize.SizeBytes <= 0 {
+					t.Errorf("want a positive size for feed_feeds, got %d", tableSize.SizeBytes)
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("want feed_feeds table in %+v", got)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pglockout
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+)
+
+type DBSummary struct {
+	Count         uint       `db:"count"`
+	LastClientIP  *string    `db:"last_client_ip"`
+	LastFailureAt *time.Time `db:"last_failure_at"`
+}
+
+func (s *DBSummary) asSummary() lockout.Summary {
+	summary := lockout.Summary{
+		Count: s.Count,
+	}
+
+	if s.LastClientIP != nil {
+		summary.LastClientIP = *s.LastClientIP
+	}
+	if s.LastFailureAt != nil {
+		summary.LastFailureAt = s.LastFailureAt.UTC()
+	}
+
+	return summary
+}
@@ -0,0 +1,121 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pglockout
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+)
+
+var _ lockout.Repository = &Repository{}
+
+const (
+	domain = "lockout"
+
+	summaryQuery = `
+	SELECT
+		COUNT(*) AS count,
+		(ARRAY_AGG(client_ip ORDER BY created_at DESC))[1] AS last_client_ip,
+		MAX(created_at) AS last_failure_at
+	FROM login_failures`
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for failed login attempts.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) LoginFailureAdd(ctx context.Context, f lockout.Failure) error {
+	query := `
+	INSERT INTO login_failures(
+		uuid,
+		email,
+		client_ip,
+		created_at
+	)
+	VALUES(
+		@uuid,
+		@email,
+		@client_ip,
+		@created_at
+	)`
+
+	args := pgx.NamedArgs{
+		"uuid":       f.UUID,
+		"email":      f.Email,
+		"client_ip":  f.ClientIP,
+		"created_at": f.CreatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "LoginFailureAdd", query, args)
+}
+
+func (r *Repository) LoginFailureDeleteByEmail(ctx context.Context, email string) error {
+	query := `DELETE FROM login_failures WHERE email=@email`
+
+	args := pgx.NamedArgs{
+		"email": email,
+	}
+
+	return r.QueryTx(ctx, domain, "LoginFailureDeleteByEmail", query, args)
+}
+
+func (r *Repository) LoginFailureDeleteBefore(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM login_failures WHERE created_at<@before`
+
+	args := pgx.NamedArgs{
+		"before": before,
+	}
+
+	return r.QueryTx(ctx, domain, "LoginFailureDeleteBefore", query, args)
+}
+
+func (r *Repository) LoginFailureGetSummaryByClientIP(ctx context.Context, clientIP string, since time.Time) (lockout.Summary, error) {
+	query := summaryQuery + `
+	WHERE client_ip=@client_ip
+	AND   created_at>=@since`
+
+	args := pgx.NamedArgs{
+		"client_ip": clientIP,
+		"since":     since,
+	}
+
+	return r.loginFailureGetSummary(ctx, query, args)
+}
+
+func (r *Repository) LoginFailureGetSummaryByEmail(ctx context.Context, email string, since time.Time) (lockout.Summary, error) {
+	query := summaryQuery + `
+	WHERE email=@email
+	AND   created_at>=@since`
+
+	args := pgx.NamedArgs{
+		"email": email,
+		"since": since,
+	}
+
+	return r.loginFailureGetSummary(ctx, query, args)
+}
+
+func (r *Repository) loginFailureGetSummary(ctx context.Context, query string, args pgx.NamedArgs) (lockout.Summary, error) {
+	var dbSummary DBSummary
+
+	if err := pgxscan.Get(ctx, r.Pool, &dbSummary, query, args); err != nil {
+		return lockout.Summary{}, err
+	}
+
+	return dbSummary.asSummary(), nil
+}
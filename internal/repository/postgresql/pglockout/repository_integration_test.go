@@ -0,0 +1,118 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pglockout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pglockout"
+	"github.com/virtualtam/sparklemuffin/pkg/lockout"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	r := pglockout.NewRepository(pool)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	failures := []lockout.Failure{
+		{
+			UUID:      "0c5e0d3b-7d62-4f3e-9d4a-2b1c8e7f6a50",
+			Email:     "jane.doe@example.org",
+			ClientIP:  "192.0.2.1",
+			CreatedAt: now.Add(-3 * time.Hour),
+		},
+		{
+			UUID:      "1d6f1e4c-8e73-4a4f-8e5b-3c2d9f8a7b61",
+			Email:     "jane.doe@example.org",
+			ClientIP:  "192.0.2.2",
+			CreatedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			UUID:      "2e7a2f5d-9f84-4b5a-9f6c-4d3e0a9b8c72",
+			Email:     "john.doe@example.org",
+			ClientIP:  "192.0.2.2",
+			CreatedAt: now.Add(-1 * time.Hour),
+		},
+	}
+
+	for _, f := range failures {
+		if err := r.LoginFailureAdd(t.Context(), f); err != nil {
+			t.Fatalf("failed to add failure: %q", err)
+		}
+	}
+
+	t.Run("summary by email", func(t *testing.T) {
+		summary, err := r.LoginFailureGetSummaryByEmail(t.Context(), "jane.doe@example.org", now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		want := lockout.Summary{Count: 2, LastClientIP: "192.0.2.2", LastFailureAt: now.Add(-2 * time.Hour)}
+		if summary != want {
+			t.Errorf("want summary %#v, got %#v", want, summary)
+		}
+	})
+
+	t.Run("summary by client IP since", func(t *testing.T) {
+		summary, err := r.LoginFailureGetSummaryByClientIP(t.Context(), "192.0.2.2", now.Add(-90*time.Minute))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if summary.Count != 1 {
+			t.Errorf("want 1 failure, got %d", summary.Count)
+		}
+	})
+
+	t.Run("summary without failures", func(t *testing.T) {
+		summary, err := r.LoginFailureGetSummaryByEmail(t.Context(), "unknown@example.org", now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if summary != (lockout.Summary{}) {
+			t.Errorf("want empty summary, got %#v", summary)
+		}
+	})
+
+	t.Run("delete before", func(t *testing.T) {
+		if err := r.LoginFailureDeleteBefore(t.Context(), now.Add(-150*time.Minute)); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		summary, err := r.LoginFailureGetSummaryByEmail(t.Context(), "jane.doe@example.org", now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if summary.Count != 1 {
+			t.Errorf("want 1 failure, got %d", summary.Count)
+		}
+	})
+
+	t.Run("delete by email", func(t *testing.T) {
+		if err := r.LoginFailureDeleteByEmail(t.Context(), "jane.doe@example.org"); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		summary, err := r.LoginFailureGetSummaryByEmail(t.Context(), "jane.doe@example.org", now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if summary.Count != 0 {
+			t.Errorf("want no failures, got %d", summary.Count)
+		}
+
+		summary, err = r.LoginFailureGetSummaryByEmail(t.Context(), "john.doe@example.org", now.Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if summary.Count != 1 {
+			t.Errorf("want 1 failure, got %d", summary.Count)
+		}
+	})
+}
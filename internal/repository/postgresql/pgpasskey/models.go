@@ -0,0 +1,66 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasskey
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+)
+
+type DBPasskey struct {
+	UUID       string     `db:"uuid"`
+	UserUUID   string     `db:"user_uuid"`
+	Name       string     `db:"name"`
+	Credential []byte     `db:"credential"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+func (p *DBPasskey) asPasskey() (passkey.Passkey, error) {
+	var credential webauthn.Credential
+	if err := json.Unmarshal(p.Credential, &credential); err != nil {
+		return passkey.Passkey{}, err
+	}
+
+	var lastUsedAt time.Time
+	if p.LastUsedAt != nil {
+		lastUsedAt = *p.LastUsedAt
+	}
+
+	return passkey.Passkey{
+		UUID:       p.UUID,
+		UserUUID:   p.UserUUID,
+		Name:       p.Name,
+		Credential: credential,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		LastUsedAt: lastUsedAt,
+	}, nil
+}
+
+type DBCeremony struct {
+	TokenHash   string    `db:"token_hash"`
+	SessionData []byte    `db:"session_data"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (c *DBCeremony) asCeremony() (passkey.Ceremony, error) {
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(c.SessionData, &sessionData); err != nil {
+		return passkey.Ceremony{}, err
+	}
+
+	return passkey.Ceremony{
+		TokenHash:   c.TokenHash,
+		SessionData: sessionData,
+		ExpiresAt:   c.ExpiresAt,
+		CreatedAt:   c.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,296 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+)
+
+var _ passkey.Repository = &Repository{}
+
+const (
+	domain = "passkey"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for passkeys.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) PasskeyAdd(ctx context.Context, p passkey.Passkey) error {
+	credential, err := json.Marshal(p.Credential)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO passkeys(
+		uuid,
+		user_uuid,
+		name,
+		credential_id,
+		credential,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@uuid,
+		@user_uuid,
+		@name,
+		@credential_id,
+		@credential,
+		@created_at,
+		@updated_at
+	)
+	ON CONFLICT (credential_id) DO NOTHING`
+
+	args := pgx.NamedArgs{
+		"uuid":          p.UUID,
+		"user_uuid":     p.UserUUID,
+		"name":          p.Name,
+		"credential_id": p.Credential.ID,
+		"credential":    string(credential),
+		"created_at":    p.CreatedAt,
+		"updated_at":    p.UpdatedAt,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return passkey.ErrAlreadyRegistered
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyDelete(ctx context.Context, userUUID string, passkeyUUID string) error {
+	query := `
+	DELETE FROM passkeys
+	WHERE uuid=@uuid
+	AND   user_uuid=@user_uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":      passkeyUUID,
+		"user_uuid": userUUID,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return passkey.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyGetByCredentialID(ctx context.Context, credentialID []byte) (passkey.Passkey, error) {
+	query := `
+	SELECT uuid, user_uuid, name, credential, created_at, updated_at, last_used_at
+	FROM passkeys
+	WHERE credential_id=$1`
+
+	rows, err := r.Pool.Query(ctx, query, credentialID)
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+	defer rows.Close()
+
+	dbPasskey := &DBPasskey{}
+	err = pgxscan.ScanOne(dbPasskey, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return passkey.Passkey{}, passkey.ErrNotFound
+	}
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+
+	return dbPasskey.asPasskey()
+}
+
+func (r *Repository) PasskeyGetByUserUUID(ctx context.Context, userUUID string) ([]passkey.Passkey, error) {
+	query := `
+	SELECT uuid, user_uuid, name, credential, created_at, updated_at, last_used_at
+	FROM passkeys
+	WHERE user_uuid=$1
+	ORDER BY created_at`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return []passkey.Passkey{}, err
+	}
+	defer rows.Close()
+
+	var dbPasskeys []DBPasskey
+
+	if err := pgxscan.ScanAll(&dbPasskeys, rows); err != nil {
+		return []passkey.Passkey{}, err
+	}
+
+	passkeys := make([]passkey.Passkey, 0, len(dbPasskeys))
+	for _, dbPasskey := range dbPasskeys {
+		p, err := dbPasskey.asPasskey()
+		if err != nil {
+			return []passkey.Passkey{}, err
+		}
+
+		passkeys = append(passkeys, p)
+	}
+
+	return passkeys, nil
+}
+
+func (r *Repository) PasskeyUpdateCredential(ctx context.Context, update passkey.CredentialUpdate) error {
+	credential, err := json.Marshal(update.Credential)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE passkeys
+	SET
+		credential=@credential,
+		last_used_at=@last_used_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":         update.UUID,
+		"credential":   string(credential),
+		"last_used_at": update.LastUsedAt,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return passkey.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyUpdateName(ctx context.Context, update passkey.NameUpdate) error {
+	query := `
+	UPDATE passkeys
+	SET
+		name=@name,
+		updated_at=@updated_at
+	WHERE uuid=@uuid
+	AND   user_uuid=@user_uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":       update.UUID,
+		"user_uuid":  update.UserUUID,
+		"name":       update.Name,
+		"updated_at": update.UpdatedAt,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return passkey.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyCeremonyAdd(ctx context.Context, c passkey.Ceremony) error {
+	sessionData, err := json.Marshal(c.SessionData)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "PasskeyCeremonyAdd")
+
+	// Take this opportunity to purge expired ceremonies.
+	if _, err := tx.Exec(ctx, "DELETE FROM passkey_ceremonies WHERE expires_at <= NOW()"); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO passkey_ceremonies(
+		token_hash,
+		session_data,
+		expires_at,
+		created_at
+	)
+	VALUES(
+		@token_hash,
+		@session_data,
+		@expires_at,
+		@created_at
+	)`
+
+	insertArgs := pgx.NamedArgs{
+		"token_hash":   c.TokenHash,
+		"session_data": string(sessionData),
+		"expires_at":   c.ExpiresAt,
+		"created_at":   c.CreatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, insertQuery, insertArgs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) PasskeyCeremonyConsume(ctx context.Context, tokenHash string) (passkey.Ceremony, error) {
+	// Deleting the ceremony guarantees it can only be completed once, even with
+	// concurrent requests.
+	query := `
+	DELETE FROM passkey_ceremonies
+	WHERE token_hash=$1
+	AND   expires_at > NOW()
+	RETURNING token_hash, session_data, expires_at, created_at`
+
+	rows, err := r.Pool.Query(ctx, query, tokenHash)
+	if err != nil {
+		return passkey.Ceremony{}, err
+	}
+	defer rows.Close()
+
+	dbCeremony := &DBCeremony{}
+	err = pgxscan.ScanOne(dbCeremony, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return passkey.Ceremony{}, passkey.ErrNotFound
+	}
+	if err != nil {
+		return passkey.Ceremony{}, err
+	}
+
+	return dbCeremony.asCeremony()
+}
@@ -0,0 +1,233 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasskey_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgpasskey"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/internal/test/webauthntest"
+	"github.com/virtualtam/sparklemuffin/pkg/passkey"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgpasskey.NewRepository(pool)
+	now := time.Now().UTC()
+
+	laptop := passkey.Passkey{
+		UUID:     "0b7e2c4a-5d6f-4a8b-9c1d-2e3f4a5b6c7d",
+		UserUUID: testUser.UUID,
+		Name:     "Laptop",
+		Credential: webauthn.Credential{
+			ID:        []byte("credential-id"),
+			PublicKey: []byte("public-key"),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	t.Run("add", func(t *testing.T) {
+		if err := r.PasskeyAdd(t.Context(), laptop); err != nil {
+			t.Fatalf("failed to add passkey: %q", err)
+		}
+
+		duplicate := laptop
+		duplicate.UUID = "1c8f3d5b-6e7a-4b9c-8d2e-3f4a5b6c7d8e"
+
+		err := r.PasskeyAdd(t.Context(), duplicate)
+		if !errors.Is(err, passkey.ErrAlreadyRegistered) {
+			t.Errorf("want %q, got %q", passkey.ErrAlreadyRegistered, err)
+		}
+
+		got, err := r.PasskeyGetByCredentialID(t.Context(), laptop.Credential.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve passkey: %q", err)
+		}
+		if got.UUID != laptop.UUID || got.Name != laptop.Name {
+			t.Errorf("unexpected passkey: %+v", got)
+		}
+		if !bytes.Equal(got.Credential.PublicKey, laptop.Credential.PublicKey) {
+			t.Error("want the public key to be saved")
+		}
+		if !got.LastUsedAt.IsZero() {
+			t.Error("want the passkey to have never been used")
+		}
+	})
+
+	t.Run("rename and update credential", func(t *testing.T) {
+		update := passkey.NameUpdate{
+			UUID:      laptop.UUID,
+			UserUUID:  testUser.UUID,
+			Name:      "Work laptop",
+			UpdatedAt: now,
+		}
+		if err := r.PasskeyUpdateName(t.Context(), update); err != nil {
+			t.Fatalf("failed to rename passkey: %q", err)
+		}
+
+		credential := laptop.Credential
+		credential.Authenticator.SignCount = 42
+
+		credentialUpdate := passkey.CredentialUpdate{
+			UUID:       laptop.UUID,
+			Credential: credential,
+			LastUsedAt: now,
+		}
+		if err := r.PasskeyUpdateCredential(t.Context(), credentialUpdate); err != nil {
+			t.Fatalf("failed to update credential: %q", err)
+		}
+
+		passkeys, err := r.PasskeyGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve passkeys: %q", err)
+		}
+		if len(passkeys) != 1 {
+			t.Fatalf("want 1 passkey, got %d", len(passkeys))
+		}
+		if passkeys[0].Name != "Work laptop" {
+			t.Errorf("want name %q, got %q", "Work laptop", passkeys[0].Name)
+		}
+		if passkeys[0].Credential.Authenticator.SignCount != 42 {
+			t.Errorf("want sign count 42, got %d", passkeys[0].Credential.Authenticator.SignCount)
+		}
+		if passkeys[0].LastUsedAt.IsZero() {
+			t.Error("want last usage date to be set")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := r.PasskeyDelete(t.Context(), "1c8f3d5b-6e7a-4b9c-8d2e-3f4a5b6c7d8e", laptop.UUID)
+		if !errors.Is(err, passkey.ErrNotFound) {
+			t.Errorf("want %q, got %q", passkey.ErrNotFound, err)
+		}
+
+		if err := r.PasskeyDelete(t.Context(), testUser.UUID, laptop.UUID); err != nil {
+			t.Fatalf("failed to delete passkey: %q", err)
+		}
+
+		_, err = r.PasskeyGetByCredentialID(t.Context(), laptop.Credential.ID)
+		if !errors.Is(err, passkey.ErrNotFound) {
+			t.Errorf("want %q, got %q", passkey.ErrNotFound, err)
+		}
+	})
+
+	t.Run("ceremonies", func(t *testing.T) {
+		ceremony := passkey.Ceremony{
+			TokenHash: "token-hash",
+			SessionData: webauthn.SessionData{
+				Challenge: "challenge",
+			},
+			ExpiresAt: now.Add(passkey.CeremonyTTL),
+			CreatedAt: now,
+		}
+		expired := passkey.Ceremony{
+			TokenHash: "expired-token-hash",
+			ExpiresAt: now.Add(-time.Minute),
+			CreatedAt: now.Add(-passkey.CeremonyTTL),
+		}
+
+		for _, c := range []passkey.Ceremony{expired, ceremony} {
+			if err := r.PasskeyCeremonyAdd(t.Context(), c); err != nil {
+				t.Fatalf("failed to add ceremony: %q", err)
+			}
+		}
+
+		_, err := r.PasskeyCeremonyConsume(t.Context(), expired.TokenHash)
+		if !errors.Is(err, passkey.ErrNotFound) {
+			t.Errorf("want %q, got %q", passkey.ErrNotFound, err)
+		}
+
+		got, err := r.PasskeyCeremonyConsume(t.Context(), ceremony.TokenHash)
+		if err != nil {
+			t.Fatalf("failed to consume ceremony: %q", err)
+		}
+		if got.SessionData.Challenge != ceremony.SessionData.Challenge {
+			t.Errorf("want challenge %q, got %q", ceremony.SessionData.Challenge, got.SessionData.Challenge)
+		}
+
+		_, err = r.PasskeyCeremonyConsume(t.Context(), ceremony.TokenHash)
+		if !errors.Is(err, passkey.ErrNotFound) {
+			t.Errorf("want %q, got %q", passkey.ErrNotFound, err)
+		}
+	})
+
+	t.Run("registration and login", func(t *testing.T) {
+		const origin = "https://sparklemuffin.test"
+
+		publicURL, err := url.Parse(origin)
+		if err != nil {
+			t.Fatalf("failed to parse URL: %q", err)
+		}
+
+		s, err := passkey.NewService(r, us, publicURL, "test-hmac-key")
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		authenticator := webauthntest.NewAuthenticator(origin)
+
+		creation, token, err := s.BeginRegistration(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		creationJSON, err := json.Marshal(creation)
+		if err != nil {
+			t.Fatalf("failed to encode creation options: %q", err)
+		}
+
+		response := authenticator.Create(t, creationJSON)
+
+		if _, err := s.FinishRegistration(t.Context(), testUser, token, "Phone", bytes.NewReader(response)); err != nil {
+			t.Fatalf("failed to finish registration: %q", err)
+		}
+
+		assertion, token, err := s.BeginLogin(t.Context())
+		if err != nil {
+			t.Fatalf("failed to begin login: %q", err)
+		}
+
+		assertionJSON, err := json.Marshal(assertion)
+		if err != nil {
+			t.Fatalf("failed to encode assertion options: %q", err)
+		}
+
+		response = authenticator.Get(t, assertionJSON)
+
+		got, err := s.FinishLogin(t.Context(), token, bytes.NewReader(response))
+		if err != nil {
+			t.Fatalf("failed to finish login: %q", err)
+		}
+		if got.UUID != testUser.UUID {
+			t.Errorf("want user %q, got %q", testUser.UUID, got.UUID)
+		}
+	})
+}
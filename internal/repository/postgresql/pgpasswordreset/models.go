@@ -0,0 +1,13 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasswordreset
+
+import "time"
+
+type DBToken struct {
+	UserUUID  string    `db:"user_uuid"`
+	TokenHash string    `db:"token_hash"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
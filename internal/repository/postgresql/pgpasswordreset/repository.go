@@ -0,0 +1,170 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasswordreset
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ passwordreset.Repository = &Repository{}
+
+const (
+	domain = "password-reset"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for password reset tokens.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) PasswordResetTokenAdd(ctx context.Context, t passwordreset.Token) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "PasswordResetTokenAdd")
+
+	// Only the latest token issued to a user is valid; also take this opportunity to
+	// purge expired tokens.
+	deleteQuery := `
+	DELETE FROM password_reset_tokens
+	WHERE user_uuid=@user_uuid
+	OR    expires_at <= NOW()`
+
+	deleteArgs := pgx.NamedArgs{
+		"user_uuid": t.UserUUID,
+	}
+
+	if _, err := tx.Exec(ctx, deleteQuery, deleteArgs); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO password_reset_tokens(
+		user_uuid,
+		token_hash,
+		expires_at,
+		created_at
+	)
+	VALUES(
+		@user_uuid,
+		@token_hash,
+		@expires_at,
+		@created_at
+	)`
+
+	insertArgs := pgx.NamedArgs{
+		"user_uuid":  t.UserUUID,
+		"token_hash": t.TokenHash,
+		"expires_at": t.ExpiresAt,
+		"created_at": t.CreatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, insertQuery, insertArgs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) PasswordResetTokenGetByHash(ctx context.Context, hash string) (passwordreset.Token, error) {
+	query := `
+	SELECT user_uuid, token_hash, expires_at, created_at
+	FROM password_reset_tokens
+	WHERE token_hash=$1
+	AND expires_at > NOW()`
+
+	rows, err := r.Pool.Query(ctx, query, hash)
+	if err != nil {
+		return passwordreset.Token{}, err
+	}
+	defer rows.Close()
+
+	dbToken := &DBToken{}
+	err = pgxscan.ScanOne(dbToken, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return passwordreset.Token{}, passwordreset.ErrNotFound
+	}
+	if err != nil {
+		return passwordreset.Token{}, err
+	}
+
+	return passwordreset.Token{
+		UserUUID:  dbToken.UserUUID,
+		TokenHash: dbToken.TokenHash,
+		ExpiresAt: dbToken.ExpiresAt,
+		CreatedAt: dbToken.CreatedAt,
+	}, nil
+}
+
+func (r *Repository) PasswordResetTokenConsume(ctx context.Context, hash string, passwordHashUpdate user.PasswordHashUpdate) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "PasswordResetTokenConsume")
+
+	// Deleting the token within the transaction guarantees it can only be used once,
+	// even with concurrent requests.
+	deleteQuery := `
+	DELETE FROM password_reset_tokens
+	WHERE user_uuid=(
+		SELECT user_uuid
+		FROM password_reset_tokens
+		WHERE token_hash=@token_hash
+		AND   user_uuid=@user_uuid
+		AND   expires_at > NOW()
+	)`
+
+	deleteArgs := pgx.NamedArgs{
+		"token_hash": hash,
+		"user_uuid":  passwordHashUpdate.UserUUID,
+	}
+
+	commandTag, err := tx.Exec(ctx, deleteQuery, deleteArgs)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return passwordreset.ErrNotFound
+	}
+
+	updateQuery := `
+	UPDATE users
+	SET
+		password_hash=@password_hash,
+		updated_at=@updated_at
+	WHERE uuid=@uuid`
+
+	updateArgs := pgx.NamedArgs{
+		"uuid":          passwordHashUpdate.UserUUID,
+		"password_hash": passwordHashUpdate.PasswordHash,
+		"updated_at":    passwordHashUpdate.UpdatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, updateQuery, updateArgs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
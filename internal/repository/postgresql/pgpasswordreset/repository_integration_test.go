@@ -0,0 +1,107 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgpasswordreset_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgpasswordreset"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/passwordreset"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgpasswordreset.NewRepository(pool)
+	now := time.Now().UTC()
+
+	t.Run("expired tokens are not returned", func(t *testing.T) {
+		token := passwordreset.Token{
+			UserUUID:  testUser.UUID,
+			TokenHash: "expired-hash",
+			ExpiresAt: now.Add(-1 * time.Hour),
+			CreatedAt: now.Add(-2 * time.Hour),
+		}
+		if err := r.PasswordResetTokenAdd(t.Context(), token); err != nil {
+			t.Fatalf("failed to add token: %q", err)
+		}
+
+		_, err := r.PasswordResetTokenGetByHash(t.Context(), "expired-hash")
+		if !errors.Is(err, passwordreset.ErrNotFound) {
+			t.Fatalf("want %q, got %q", passwordreset.ErrNotFound, err)
+		}
+	})
+
+	t.Run("a new token replaces the previous one", func(t *testing.T) {
+		for _, hash := range []string{"first-hash", "second-hash"} {
+			token := passwordreset.Token{
+				UserUUID:  testUser.UUID,
+				TokenHash: hash,
+				ExpiresAt: now.Add(time.Hour),
+				CreatedAt: now,
+			}
+			if err := r.PasswordResetTokenAdd(t.Context(), token); err != nil {
+				t.Fatalf("failed to add token: %q", err)
+			}
+		}
+
+		if _, err := r.PasswordResetTokenGetByHash(t.Context(), "first-hash"); !errors.Is(err, passwordreset.ErrNotFound) {
+			t.Fatalf("want %q, got %q", passwordreset.ErrNotFound, err)
+		}
+
+		got, err := r.PasswordResetTokenGetByHash(t.Context(), "second-hash")
+		if err != nil {
+			t.Fatalf("failed to retrieve token: %q", err)
+		}
+		if got.UserUUID != testUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testUser.UUID, got.UserUUID)
+		}
+	})
+
+	t.Run("consume a token", func(t *testing.T) {
+		passwordHashUpdate := user.PasswordHashUpdate{
+			UserUUID:     testUser.UUID,
+			PasswordHash: "new-password-hash",
+			UpdatedAt:    time.Now().UTC(),
+		}
+
+		if err := r.PasswordResetTokenConsume(t.Context(), "second-hash", passwordHashUpdate); err != nil {
+			t.Fatalf("failed to consume token: %q", err)
+		}
+
+		updatedUser, err := us.ByUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+		if updatedUser.PasswordHash != passwordHashUpdate.PasswordHash {
+			t.Errorf("want password hash %q, got %q", passwordHashUpdate.PasswordHash, updatedUser.PasswordHash)
+		}
+
+		err = r.PasswordResetTokenConsume(t.Context(), "second-hash", passwordHashUpdate)
+		if !errors.Is(err, passwordreset.ErrNotFound) {
+			t.Fatalf("want %q, got %q", passwordreset.ErrNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgquota
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+)
+
+type DBOverride struct {
+	UserUUID          string    `db:"user_uuid"`
+	MaxBookmarks      *int64    `db:"max_bookmarks"`
+	MaxSubscriptions  *int64    `db:"max_subscriptions"`
+	MaxImportFileSize *int64    `db:"max_import_file_size"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+func (o *DBOverride) asOverride() quota.Override {
+	return quota.Override{
+		UserUUID:          o.UserUUID,
+		MaxBookmarks:      o.MaxBookmarks,
+		MaxSubscriptions:  o.MaxSubscriptions,
+		MaxImportFileSize: o.MaxImportFileSize,
+		UpdatedAt:         o.UpdatedAt,
+	}
+}
+
+type DBUsage struct {
+	Bookmarks     int64 `db:"bookmarks"`
+	Subscriptions int64 `db:"subscriptions"`
+}
@@ -0,0 +1,125 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgquota
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+)
+
+var _ quota.Repository = &Repository{}
+
+const (
+	domain = "quota"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for user quotas.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) QuotaOverrideDelete(ctx context.Context, userUUID string) error {
+	query := `DELETE FROM user_quotas WHERE user_uuid=@user_uuid`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+	}
+
+	return r.QueryTx(ctx, domain, "QuotaOverrideDelete", query, args)
+}
+
+func (r *Repository) QuotaOverrideGetByUserUUID(ctx context.Context, userUUID string) (quota.Override, error) {
+	query := `
+	SELECT user_uuid, max_bookmarks, max_subscriptions, max_import_file_size, updated_at
+	FROM user_quotas
+	WHERE user_uuid=$1`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return quota.Override{}, err
+	}
+	defer rows.Close()
+
+	dbOverride := &DBOverride{}
+	err = pgxscan.ScanOne(dbOverride, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return quota.Override{UserUUID: userUUID}, nil
+	}
+	if err != nil {
+		return quota.Override{}, err
+	}
+
+	return dbOverride.asOverride(), nil
+}
+
+func (r *Repository) QuotaOverrideUpsert(ctx context.Context, override quota.Override) error {
+	query := `
+	INSERT INTO user_quotas(
+		user_uuid,
+		max_bookmarks,
+		max_subscriptions,
+		max_import_file_size,
+		updated_at
+	)
+	VALUES(
+		@user_uuid,
+		@max_bookmarks,
+		@max_subscriptions,
+		@max_import_file_size,
+		@updated_at
+	)
+	ON CONFLICT (user_uuid) DO UPDATE
+	SET
+		max_bookmarks=EXCLUDED.max_bookmarks,
+		max_subscriptions=EXCLUDED.max_subscriptions,
+		max_import_file_size=EXCLUDED.max_import_file_size,
+		updated_at=EXCLUDED.updated_at`
+
+	args := pgx.NamedArgs{
+		"user_uuid":            override.UserUUID,
+		"max_bookmarks":        override.MaxBookmarks,
+		"max_subscriptions":    override.MaxSubscriptions,
+		"max_import_file_size": override.MaxImportFileSize,
+		"updated_at":           override.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "QuotaOverrideUpsert", query, args)
+}
+
+func (r *Repository) QuotaUsageGetByUserUUID(ctx context.Context, userUUID string) (quota.Usage, error) {
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM bookmarks WHERE user_uuid=$1)          AS bookmarks,
+		(SELECT COUNT(*) FROM feed_subscriptions WHERE user_uuid=$1) AS subscriptions`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return quota.Usage{}, err
+	}
+	defer rows.Close()
+
+	dbUsage := &DBUsage{}
+	if err := pgxscan.ScanOne(dbUsage, rows); err != nil {
+		return quota.Usage{}, err
+	}
+
+	return quota.Usage{
+		Bookmarks:     dbUsage.Bookmarks,
+		Subscriptions: dbUsage.Subscriptions,
+	}, nil
+}
@@ -0,0 +1,125 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgquota_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbookmark"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgquota"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgquota.NewRepository(pool)
+
+	t.Run("no override", func(t *testing.T) {
+		got, err := r.QuotaOverrideGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve override: %q", err)
+		}
+
+		if !got.IsEmpty() {
+			t.Errorf("want an empty override, got %+v", got)
+		}
+	})
+
+	t.Run("upsert and delete override", func(t *testing.T) {
+		maxBookmarks := int64(100)
+		maxImportFileSize := int64(0)
+
+		override := quota.Override{
+			UserUUID:          testUser.UUID,
+			MaxBookmarks:      &maxBookmarks,
+			MaxImportFileSize: &maxImportFileSize,
+			UpdatedAt:         time.Now().UTC(),
+		}
+
+		if err := r.QuotaOverrideUpsert(t.Context(), override); err != nil {
+			t.Fatalf("failed to create override: %q", err)
+		}
+
+		maxBookmarks = 200
+		if err := r.QuotaOverrideUpsert(t.Context(), override); err != nil {
+			t.Fatalf("failed to update override: %q", err)
+		}
+
+		got, err := r.QuotaOverrideGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve override: %q", err)
+		}
+
+		if got.MaxBookmarks == nil || *got.MaxBookmarks != 200 {
+			t.Errorf("want MaxBookmarks 200, got %v", got.MaxBookmarks)
+		}
+		if got.MaxSubscriptions != nil {
+			t.Errorf("want MaxSubscriptions to be inherited, got %d", *got.MaxSubscriptions)
+		}
+		if got.MaxImportFileSize == nil || *got.MaxImportFileSize != 0 {
+			t.Errorf("want MaxImportFileSize 0, got %v", got.MaxImportFileSize)
+		}
+
+		if err := r.QuotaOverrideDelete(t.Context(), testUser.UUID); err != nil {
+			t.Fatalf("failed to delete override: %q", err)
+		}
+
+		got, err = r.QuotaOverrideGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve override: %q", err)
+		}
+
+		if !got.IsEmpty() {
+			t.Errorf("want an empty override, got %+v", got)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		bs := bookmark.NewService(pgbookmark.NewRepository(pool), nil)
+
+		for range 3 {
+			b := bookmark.Bookmark{
+				UserUUID: testUser.UUID,
+				URL:      fake.Internet().URL(),
+				Title:    fake.Lorem().Sentence(5),
+			}
+
+			if err := bs.Add(t.Context(), b); err != nil {
+				t.Fatalf("failed to create bookmark: %q", err)
+			}
+		}
+
+		got, err := r.QuotaUsageGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve usage: %q", err)
+		}
+
+		want := quota.Usage{Bookmarks: 3}
+		if got != want {
+			t.Errorf("want usage %+v, got %+v", want, got)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgregistration
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+)
+
+type DBInvitation struct {
+	UUID          string    `db:"uuid"`
+	CreatedByUUID string    `db:"created_by_uuid"`
+	TokenHash     string    `db:"token_hash"`
+	MaxUses       int       `db:"max_uses"`
+	UseCount      int       `db:"use_count"`
+	ExpiresAt     time.Time `db:"expires_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (i *DBInvitation) asInvitation() registration.Invitation {
+	return registration.Invitation{
+		UUID:          i.UUID,
+		CreatedByUUID: i.CreatedByUUID,
+		TokenHash:     i.TokenHash,
+		MaxUses:       i.MaxUses,
+		UseCount:      i.UseCount,
+		ExpiresAt:     i.ExpiresAt,
+		CreatedAt:     i.CreatedAt,
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgregistration
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ registration.Repository = &Repository{}
+
+const (
+	domain = "registration"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for invitations and
+// pending registrations.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) InvitationAdd(ctx context.Context, invitation registration.Invitation) error {
+	query := `
+	INSERT INTO registration_invitations(
+		uuid,
+		created_by_uuid,
+		token_hash,
+		max_uses,
+		use_count,
+		expires_at,
+		created_at
+	)
+	VALUES(
+		@uuid,
+		@created_by_uuid,
+		@token_hash,
+		@max_uses,
+		@use_count,
+		@expires_at,
+		@created_at
+	)`
+
+	args := pgx.NamedArgs{
+		"uuid":            invitation.UUID,
+		"created_by_uuid": invitation.CreatedByUUID,
+		"token_hash":      invitation.TokenHash,
+		"max_uses":        invitation.MaxUses,
+		"use_count":       invitation.UseCount,
+		"expires_at":      invitation.ExpiresAt,
+		"created_at":      invitation.CreatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "InvitationAdd", query, args)
+}
+
+func (r *Repository) InvitationDeleteByUUID(ctx context.Context, userUUID string, invitationUUID string) error {
+	query := `
+	DELETE FROM registration_invitations
+	WHERE created_by_uuid=@created_by_uuid
+	AND   uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"created_by_uuid": userUUID,
+		"uuid":            invitationUUID,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return registration.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) InvitationGetByTokenHash(ctx context.Context, hash string) (registration.Invitation, error) {
+	query := `
+	SELECT uuid, created_by_uuid, token_hash, max_uses, use_count, expires_at, created_at
+	FROM registration_invitations
+	WHERE token_hash=$1
+	AND   use_count < max_uses
+	AND   expires_at > NOW()`
+
+	rows, err := r.Pool.Query(ctx, query, hash)
+	if err != nil {
+		return registration.Invitation{}, err
+	}
+	defer rows.Close()
+
+	dbInvitation := &DBInvitation{}
+	err = pgxscan.ScanOne(dbInvitation, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return registration.Invitation{}, registration.ErrNotFound
+	}
+	if err != nil {
+		return registration.Invitation{}, err
+	}
+
+	return dbInvitation.asInvitation(), nil
+}
+
+func (r *Repository) InvitationGetByUserUUID(ctx context.Context, userUUID string) ([]registration.Invitation, error) {
+	query := `
+	SELECT uuid, created_by_uuid, token_hash, max_uses, use_count, expires_at, created_at
+	FROM registration_invitations
+	WHERE created_by_uuid=$1
+	ORDER BY created_at DESC`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return []registration.Invitation{}, err
+	}
+	defer rows.Close()
+
+	var dbInvitations []DBInvitation
+	if err := pgxscan.ScanAll(&dbInvitations, rows); err != nil {
+		return []registration.Invitation{}, err
+	}
+
+	invitations := make([]registration.Invitation, len(dbInvitations))
+	for i, dbInvitation := range dbInvitations {
+		invitations[i] = dbInvitation.asInvitation()
+	}
+
+	return invitations, nil
+}
+
+func (r *Repository) RegistrationAdd(ctx context.Context, invitationHash string, u user.User) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "RegistrationAdd")
+
+	if err := invitationUse(ctx, tx, invitationHash); err != nil {
+		return err
+	}
+
+	userAddQuery := `
+	INSERT INTO users(
+		uuid,
+		email,
+		nick_name,
+		display_name,
+		password_hash,
+		is_admin,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@uuid,
+		@email,
+		@nick_name,
+		@display_name,
+		@password_hash,
+		FALSE,
+		@created_at,
+		@updated_at
+	)`
+
+	userAddArgs := pgx.NamedArgs{
+		"uuid":          u.UUID,
+		"email":         u.Email,
+		"nick_name":     u.NickName,
+		"display_name":  u.DisplayName,
+		"password_hash": u.PasswordHash,
+		"created_at":    u.CreatedAt,
+		"updated_at":    u.UpdatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, userAddQuery, userAddArgs); err != nil {
+		return err
+	}
+
+	if err := feedPreferencesAdd(ctx, tx, u.UUID, u.UpdatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) PendingRegistrationAdd(ctx context.Context, invitationHash string, p registration.PendingRegistration) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "PendingRegistrationAdd")
+
+	if err := invitationUse(ctx, tx, invitationHash); err != nil {
+		return err
+	}
+
+	// Only the latest registration for an email address can be confirmed; also take this
+	// opportunity to purge expired registrations.
+	deleteQuery := `
+	DELETE FROM registration_pending
+	WHERE email=@email
+	OR    expires_at <= NOW()`
+
+	deleteArgs := pgx.NamedArgs{
+		"email": p.Email,
+	}
+
+	if _, err := tx.Exec(ctx, deleteQuery, deleteArgs); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO registration_pending(
+		token_hash,
+		user_uuid,
+		email,
+		nick_name,
+		display_name,
+		password_hash,
+		expires_at,
+		created_at
+	)
+	VALUES(
+		@token_hash,
+		@user_uuid,
+		@email,
+		@nick_name,
+		@display_name,
+		@password_hash,
+		@expires_at,
+		@created_at
+	)`
+
+	insertArgs := pgx.NamedArgs{
+		"token_hash":    p.TokenHash,
+		"user_uuid":     p.UserUUID,
+		"email":         p.Email,
+		"nick_name":     p.NickName,
+		"display_name":  p.DisplayName,
+		"password_hash": p.PasswordHash,
+		"expires_at":    p.ExpiresAt,
+		"created_at":    p.CreatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, insertQuery, insertArgs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) PendingRegistrationConfirm(ctx context.Context, hash string) (string, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "PendingRegistrationConfirm")
+
+	now := time.Now().UTC()
+
+	// Deleting the pending registration within the transaction guarantees the account can
+	// only be created once, even with concurrent requests.
+	query := `
+	WITH confirmed AS (
+		DELETE FROM registration_pending
+		WHERE token_hash=@token_hash
+		AND   expires_at > NOW()
+		RETURNING user_uuid, email, nick_name, display_name, password_hash
+	)
+	INSERT INTO users(
+		uuid,
+		email,
+		nick_name,
+		display_name,
+		password_hash,
+		is_admin,
+		created_at,
+		updated_at
+	)
+	SELECT user_uuid, email, nick_name, display_name, password_hash, FALSE, @now, @now
+	FROM confirmed
+	RETURNING uuid`
+
+	args := pgx.NamedArgs{
+		"token_hash": hash,
+		"now":        now,
+	}
+
+	var userUUID string
+
+	err = tx.QueryRow(ctx, query, args).Scan(&userUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", registration.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := feedPreferencesAdd(ctx, tx, userUUID, now); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	return userUUID, nil
+}
+
+// invitationUse increments the use count of the invitation corresponding to a given hash,
+// if set, provided it has neither expired nor reached its usage limit.
+func invitationUse(ctx context.Context, tx pgx.Tx, hash string) error {
+	if hash == "" {
+		return nil
+	}
+
+	query := `
+	UPDATE registration_invitations
+	SET   use_count=use_count+1
+	WHERE token_hash=@token_hash
+	AND   use_count < max_uses
+	AND   expires_at > NOW()`
+
+	args := pgx.NamedArgs{
+		"token_hash": hash,
+	}
+
+	commandTag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return registration.ErrInvitationInvalid
+	}
+
+	return nil
+}
+
+// feedPreferencesAdd initializes the feed preferences of a newly created user.
+func feedPreferencesAdd(ctx context.Context, tx pgx.Tx, userUUID string, updatedAt time.Time) error {
+	query := `
+	INSERT INTO feed_preferences(user_uuid, updated_at)
+	VALUES(@user_uuid, @updated_at)`
+
+	args := pgx.NamedArgs{
+		"user_uuid":  userUUID,
+		"updated_at": updatedAt,
+	}
+
+	_, err := tx.Exec(ctx, query, args)
+
+	return err
+}
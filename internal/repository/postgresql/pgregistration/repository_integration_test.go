@@ -0,0 +1,167 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgregistration_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgregistration"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/registration"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	inviter := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), inviter); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	r := pgregistration.NewRepository(pool)
+	now := time.Now().UTC()
+
+	invitation := registration.Invitation{
+		UUID:          fake.UUID().V4(),
+		CreatedByUUID: inviter.UUID,
+		TokenHash:     "invitation-hash",
+		MaxUses:       1,
+		ExpiresAt:     now.Add(time.Hour),
+		CreatedAt:     now,
+	}
+
+	t.Run("add an invitation", func(t *testing.T) {
+		if err := r.InvitationAdd(t.Context(), invitation); err != nil {
+			t.Fatalf("failed to add invitation: %q", err)
+		}
+
+		got, err := r.InvitationGetByTokenHash(t.Context(), invitation.TokenHash)
+		if err != nil {
+			t.Fatalf("failed to retrieve invitation: %q", err)
+		}
+		if got.UUID != invitation.UUID {
+			t.Errorf("want UUID %q, got %q", invitation.UUID, got.UUID)
+		}
+
+		invitations, err := r.InvitationGetByUserUUID(t.Context(), inviter.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve invitations: %q", err)
+		}
+		if len(invitations) != 1 {
+			t.Errorf("want 1 invitation, got %d", len(invitations))
+		}
+	})
+
+	t.Run("expired invitations are not returned", func(t *testing.T) {
+		expired := registration.Invitation{
+			UUID:          fake.UUID().V4(),
+			CreatedByUUID: inviter.UUID,
+			TokenHash:     "expired-hash",
+			MaxUses:       1,
+			ExpiresAt:     now.Add(-1 * time.Hour),
+			CreatedAt:     now.Add(-2 * time.Hour),
+		}
+		if err := r.InvitationAdd(t.Context(), expired); err != nil {
+			t.Fatalf("failed to add invitation: %q", err)
+		}
+
+		_, err := r.InvitationGetByTokenHash(t.Context(), expired.TokenHash)
+		if !errors.Is(err, registration.ErrNotFound) {
+			t.Fatalf("want %q, got %q", registration.ErrNotFound, err)
+		}
+
+		if err := r.RegistrationAdd(t.Context(), expired.TokenHash, user.FakeUser(t, &fake)); !errors.Is(err, registration.ErrInvitationInvalid) {
+			t.Fatalf("want %q, got %q", registration.ErrInvitationInvalid, err)
+		}
+	})
+
+	t.Run("register with an invitation", func(t *testing.T) {
+		invitee := user.FakeUser(t, &fake)
+		invitee.PasswordHash = "password-hash"
+		invitee.CreatedAt = now
+		invitee.UpdatedAt = now
+
+		if err := r.RegistrationAdd(t.Context(), invitation.TokenHash, invitee); err != nil {
+			t.Fatalf("failed to register: %q", err)
+		}
+
+		if _, err := us.ByUUID(t.Context(), invitee.UUID); err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+
+		// the invitation may only be used once
+		other := user.FakeUser(t, &fake)
+		other.PasswordHash = "password-hash"
+
+		if err := r.RegistrationAdd(t.Context(), invitation.TokenHash, other); !errors.Is(err, registration.ErrInvitationInvalid) {
+			t.Fatalf("want %q, got %q", registration.ErrInvitationInvalid, err)
+		}
+		if _, err := us.ByUUID(t.Context(), other.UUID); !errors.Is(err, user.ErrNotFound) {
+			t.Fatalf("want %q, got %q", user.ErrNotFound, err)
+		}
+	})
+
+	t.Run("confirm a pending registration", func(t *testing.T) {
+		pendingUser := user.FakeUser(t, &fake)
+
+		pendingRegistration := registration.PendingRegistration{
+			UserUUID:     pendingUser.UUID,
+			Email:        pendingUser.Email,
+			NickName:     pendingUser.NickName,
+			DisplayName:  pendingUser.DisplayName,
+			PasswordHash: "password-hash",
+			TokenHash:    "pending-hash",
+			ExpiresAt:    now.Add(time.Hour),
+			CreatedAt:    now,
+		}
+
+		if err := r.PendingRegistrationAdd(t.Context(), "", pendingRegistration); err != nil {
+			t.Fatalf("failed to add pending registration: %q", err)
+		}
+
+		if _, err := us.ByUUID(t.Context(), pendingUser.UUID); !errors.Is(err, user.ErrNotFound) {
+			t.Fatalf("want %q before confirmation, got %q", user.ErrNotFound, err)
+		}
+
+		userUUID, err := r.PendingRegistrationConfirm(t.Context(), pendingRegistration.TokenHash)
+		if err != nil {
+			t.Fatalf("failed to confirm registration: %q", err)
+		}
+		if userUUID != pendingUser.UUID {
+			t.Errorf("want user UUID %q, got %q", pendingUser.UUID, userUUID)
+		}
+
+		got, err := us.ByUUID(t.Context(), pendingUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+		if got.PasswordHash != pendingRegistration.PasswordHash {
+			t.Errorf("want password hash %q, got %q", pendingRegistration.PasswordHash, got.PasswordHash)
+		}
+
+		if _, err := r.PendingRegistrationConfirm(t.Context(), pendingRegistration.TokenHash); !errors.Is(err, registration.ErrNotFound) {
+			t.Fatalf("want %q, got %q", registration.ErrNotFound, err)
+		}
+	})
+
+	t.Run("delete an invitation", func(t *testing.T) {
+		if err := r.InvitationDeleteByUUID(t.Context(), inviter.UUID, invitation.UUID); err != nil {
+			t.Fatalf("failed to delete invitation: %q", err)
+		}
+
+		if err := r.InvitationDeleteByUUID(t.Context(), inviter.UUID, invitation.UUID); !errors.Is(err, registration.ErrNotFound) {
+			t.Fatalf("want %q, got %q", registration.ErrNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsavedsearch
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+)
+
+type DBSavedSearch struct {
+	UUID     string `db:"uuid"`
+	UserUUID string `db:"user_uuid"`
+
+	Domain string `db:"domain"`
+	Name   string `db:"name"`
+	Slug   string `db:"slug"`
+	Query  string `db:"query"`
+	Public bool   `db:"public"`
+
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (s *DBSavedSearch) asSavedSearch() savedsearch.SavedSearch {
+	return savedsearch.SavedSearch{
+		UUID:      s.UUID,
+		UserUUID:  s.UserUUID,
+		Domain:    savedsearch.Domain(s.Domain),
+		Name:      s.Name,
+		Slug:      s.Slug,
+		Query:     s.Query,
+		Public:    s.Public,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
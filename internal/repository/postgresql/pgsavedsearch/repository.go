@@ -0,0 +1,164 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsavedsearch
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+)
+
+var _ savedsearch.Repository = &Repository{}
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+const (
+	domain = "saved_searches"
+)
+
+func (r *Repository) SavedSearchAdd(ctx context.Context, s savedsearch.SavedSearch) error {
+	query := `
+	INSERT INTO saved_searches(
+		uuid,
+		user_uuid,
+		domain,
+		name,
+		slug,
+		query,
+		public,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@uuid,
+		@user_uuid,
+		@domain,
+		@name,
+		@slug,
+		@query,
+		@public,
+		@created_at,
+		@updated_at
+	)`
+
+	args := pgx.NamedArgs{
+		"uuid":       s.UUID,
+		"user_uuid":  s.UserUUID,
+		"domain":     string(s.Domain),
+		"name":       s.Name,
+		"slug":       s.Slug,
+		"query":      s.Query,
+		"public":     s.Public,
+		"created_at": s.CreatedAt,
+		"updated_at": s.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "SavedSearchAdd", query, args)
+}
+
+func (r *Repository) SavedSearchDelete(ctx context.Context, userUUID string, searchUUID string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "SavedSearchDelete")
+
+	commandTag, err := tx.Exec(
+		ctx,
+		"DELETE FROM saved_searches WHERE user_uuid=$1 AND uuid=$2",
+		userUUID,
+		searchUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return savedsearch.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) SavedSearchGetByDomain(ctx context.Context, userUUID string, searchDomain savedsearch.Domain) ([]savedsearch.SavedSearch, error) {
+	query := `
+	SELECT uuid, user_uuid, domain, name, slug, query, public, created_at, updated_at
+	FROM saved_searches
+	WHERE user_uuid=$1
+	AND   domain=$2
+	ORDER BY name`
+
+	return r.savedSearchGetManyQuery(ctx, query, userUUID, string(searchDomain))
+}
+
+func (r *Repository) SavedSearchGetBySlug(ctx context.Context, userUUID string, searchDomain savedsearch.Domain, slug string) (savedsearch.SavedSearch, error) {
+	query := `
+	SELECT uuid, user_uuid, domain, name, slug, query, public, created_at, updated_at
+	FROM saved_searches
+	WHERE user_uuid=$1
+	AND   domain=$2
+	AND   slug=$3`
+
+	return r.savedSearchGetQuery(ctx, query, userUUID, string(searchDomain), slug)
+}
+
+func (r *Repository) SavedSearchGetByUUID(ctx context.Context, userUUID string, searchUUID string) (savedsearch.SavedSearch, error) {
+	query := `
+	SELECT uuid, user_uuid, domain, name, slug, query, public, created_at, updated_at
+	FROM saved_searches
+	WHERE user_uuid=$1
+	AND   uuid=$2`
+
+	return r.savedSearchGetQuery(ctx, query, userUUID, searchUUID)
+}
+
+func (r *Repository) SavedSearchIsRegistered(ctx context.Context, s savedsearch.SavedSearch) (bool, error) {
+	return r.RowExistsByQuery(
+		ctx,
+		"SELECT 1 FROM saved_searches WHERE user_uuid=$1 AND domain=$2 AND uuid!=$3 AND (name=$4 OR slug=$5)",
+		s.UserUUID,
+		string(s.Domain),
+		s.UUID,
+		s.Name,
+		s.Slug,
+	)
+}
+
+func (r *Repository) SavedSearchUpdate(ctx context.Context, s savedsearch.SavedSearch) error {
+	query := `
+	UPDATE saved_searches
+	SET
+		name=@name,
+		slug=@slug,
+		query=@query,
+		public=@public,
+		updated_at=@updated_at
+	WHERE user_uuid=@user_uuid
+	AND   uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"user_uuid":  s.UserUUID,
+		"uuid":       s.UUID,
+		"name":       s.Name,
+		"slug":       s.Slug,
+		"query":      s.Query,
+		"public":     s.Public,
+		"updated_at": s.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "SavedSearchUpdate", query, args)
+}
@@ -0,0 +1,55 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsavedsearch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+)
+
+func (r *Repository) savedSearchGetQuery(ctx context.Context, query string, queryParams ...any) (savedsearch.SavedSearch, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return savedsearch.SavedSearch{}, err
+	}
+	defer rows.Close()
+
+	dbSavedSearch := &DBSavedSearch{}
+	err = pgxscan.ScanOne(dbSavedSearch, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return savedsearch.SavedSearch{}, savedsearch.ErrNotFound
+	}
+	if err != nil {
+		return savedsearch.SavedSearch{}, err
+	}
+
+	return dbSavedSearch.asSavedSearch(), nil
+}
+
+func (r *Repository) savedSearchGetManyQuery(ctx context.Context, query string, queryParams ...any) ([]savedsearch.SavedSearch, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return []savedsearch.SavedSearch{}, err
+	}
+	defer rows.Close()
+
+	var dbSavedSearches []DBSavedSearch
+
+	if err := pgxscan.ScanAll(&dbSavedSearches, rows); err != nil {
+		return []savedsearch.SavedSearch{}, err
+	}
+
+	savedSearches := make([]savedsearch.SavedSearch, 0, len(dbSavedSearches))
+	for _, dbSavedSearch := range dbSavedSearches {
+		savedSearches = append(savedSearches, dbSavedSearch.asSavedSearch())
+	}
+
+	return savedSearches, nil
+}
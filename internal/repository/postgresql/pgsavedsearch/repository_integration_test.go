@@ -0,0 +1,149 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsavedsearch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgsavedsearch"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/savedsearch"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgsavedsearch.NewRepository(pool)
+	s := savedsearch.NewService(r)
+
+	bookmarkSearch, err := s.Add(t.Context(), savedsearch.SavedSearch{
+		UserUUID: testUser.UUID,
+		Domain:   savedsearch.DomainBookmarks,
+		Name:     "Go testing",
+		Query:    "tag:go tag:testing",
+		Public:   true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add saved search: %q", err)
+	}
+
+	feedSearch, err := s.Add(t.Context(), savedsearch.SavedSearch{
+		UserUUID: testUser.UUID,
+		Domain:   savedsearch.DomainFeeds,
+		Name:     "Go testing",
+		Query:    "golang testing is:unread",
+	})
+	if err != nil {
+		t.Fatalf("failed to add saved search: %q", err)
+	}
+
+	t.Run("get by UUID", func(t *testing.T) {
+		got, err := r.SavedSearchGetByUUID(t.Context(), testUser.UUID, bookmarkSearch.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve saved search: %q", err)
+		}
+
+		if got.Domain != savedsearch.DomainBookmarks {
+			t.Errorf("want domain %q, got %q", savedsearch.DomainBookmarks, got.Domain)
+		}
+		if got.Slug != "go-testing" {
+			t.Errorf("want slug %q, got %q", "go-testing", got.Slug)
+		}
+		if got.Query != bookmarkSearch.Query {
+			t.Errorf("want query %q, got %q", bookmarkSearch.Query, got.Query)
+		}
+		if !got.Public {
+			t.Error("want the saved search to be public")
+		}
+	})
+
+	t.Run("get by domain", func(t *testing.T) {
+		got, err := r.SavedSearchGetByDomain(t.Context(), testUser.UUID, savedsearch.DomainFeeds)
+		if err != nil {
+			t.Fatalf("failed to retrieve saved searches: %q", err)
+		}
+
+		if len(got) != 1 {
+			t.Fatalf("want 1 saved search, got %d", len(got))
+		}
+		if got[0].UUID != feedSearch.UUID {
+			t.Errorf("want saved search %q, got %q", feedSearch.UUID, got[0].UUID)
+		}
+	})
+
+	t.Run("public search by slug", func(t *testing.T) {
+		got, err := s.PublicBookmarkSearchBySlug(t.Context(), testUser.UUID, "go-testing")
+		if err != nil {
+			t.Fatalf("failed to retrieve saved search: %q", err)
+		}
+
+		if got.UUID != bookmarkSearch.UUID {
+			t.Errorf("want saved search %q, got %q", bookmarkSearch.UUID, got.UUID)
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := s.Add(t.Context(), savedsearch.SavedSearch{
+			UserUUID: testUser.UUID,
+			Domain:   savedsearch.DomainBookmarks,
+			Name:     "Go Testing",
+			Query:    "golang testing",
+		})
+		if !errors.Is(err, savedsearch.ErrAlreadyRegistered) {
+			t.Errorf("want error %q, got %q", savedsearch.ErrAlreadyRegistered, err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		edited := bookmarkSearch
+		edited.Name = "Testing in Go"
+		edited.Public = false
+
+		if err := s.Update(t.Context(), edited); err != nil {
+			t.Fatalf("failed to update saved search: %q", err)
+		}
+
+		got, err := r.SavedSearchGetByUUID(t.Context(), testUser.UUID, bookmarkSearch.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve saved search: %q", err)
+		}
+
+		if got.Slug != "testing-in-go" {
+			t.Errorf("want slug %q, got %q", "testing-in-go", got.Slug)
+		}
+		if got.Public {
+			t.Error("want the saved search to be private")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := s.Delete(t.Context(), testUser.UUID, feedSearch.UUID); err != nil {
+			t.Fatalf("failed to delete saved search: %q", err)
+		}
+
+		_, err := r.SavedSearchGetByUUID(t.Context(), testUser.UUID, feedSearch.UUID)
+		if !errors.Is(err, savedsearch.ErrNotFound) {
+			t.Errorf("want error %q, got %q", savedsearch.ErrNotFound, err)
+		}
+	})
+}
@@ -3,10 +3,32 @@
 
 package pgsession
 
-import "time"
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/session"
+)
 
 type DBSession struct {
+	UUID                   string    `db:"uuid"`
 	UserUUID               string    `db:"user_uuid"`
 	RememberTokenHash      string    `db:"remember_token_hash"`
 	RememberTokenExpiresAt time.Time `db:"remember_token_expires_at"`
+	ClientIP               string    `db:"client_ip"`
+	UserAgent              string    `db:"user_agent"`
+	CreatedAt              time.Time `db:"created_at"`
+	LastSeenAt             time.Time `db:"last_seen_at"`
+}
+
+func (s *DBSession) asSession() session.Session {
+	return session.Session{
+		UUID:                   s.UUID,
+		UserUUID:               s.UserUUID,
+		RememberTokenHash:      s.RememberTokenHash,
+		RememberTokenExpiresAt: s.RememberTokenExpiresAt,
+		ClientIP:               s.ClientIP,
+		UserAgent:              s.UserAgent,
+		CreatedAt:              s.CreatedAt,
+		LastSeenAt:             s.LastSeenAt,
+	}
 }
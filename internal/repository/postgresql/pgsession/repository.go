@@ -96,20 +96,35 @@ func (r *Repository) deleteExpiredSessions(ctx context.Context) (int64, error) {
 func (r *Repository) SessionAdd(ctx context.Context, sess session.Session) error {
 	query := `
 	INSERT INTO sessions(
+		uuid,
 		user_uuid,
 		remember_token_hash,
-		remember_token_expires_at
+		remember_token_expires_at,
+		client_ip,
+		user_agent,
+		created_at,
+		last_seen_at
 	)
 	VALUES(
+		@uuid,
 		@user_uuid,
 		@remember_token_hash,
-		@remember_token_expires_at
+		@remember_token_expires_at,
+		@client_ip,
+		@user_agent,
+		@created_at,
+		@last_seen_at
 	)`
 
 	args := pgx.NamedArgs{
+		"uuid":                      sess.UUID,
 		"user_uuid":                 sess.UserUUID,
 		"remember_token_hash":       sess.RememberTokenHash,
 		"remember_token_expires_at": sess.RememberTokenExpiresAt,
+		"client_ip":                 sess.ClientIP,
+		"user_agent":                sess.UserAgent,
+		"created_at":                sess.CreatedAt,
+		"last_seen_at":              sess.LastSeenAt,
 	}
 
 	return r.QueryTx(ctx, domain, "SessionAdd", query, args)
@@ -117,7 +132,7 @@ func (r *Repository) SessionAdd(ctx context.Context, sess session.Session) error
 
 func (r *Repository) SessionGetByRememberTokenHash(ctx context.Context, hash string) (session.Session, error) {
 	query := `
-	SELECT user_uuid, remember_token_hash, remember_token_expires_at
+	SELECT uuid, user_uuid, remember_token_hash, remember_token_expires_at, client_ip, user_agent, created_at, last_seen_at
 	FROM sessions
 	WHERE remember_token_hash=$1
 	AND remember_token_expires_at > NOW()`
@@ -143,11 +158,54 @@ func (r *Repository) SessionGetByRememberTokenHash(ctx context.Context, hash str
 		return session.Session{}, err
 	}
 
-	return session.Session{
-		UserUUID:               dbSession.UserUUID,
-		RememberTokenHash:      dbSession.RememberTokenHash,
-		RememberTokenExpiresAt: dbSession.RememberTokenExpiresAt,
-	}, nil
+	return dbSession.asSession(), nil
+}
+
+func (r *Repository) SessionGetByUserUUID(ctx context.Context, userUUID string) ([]session.Session, error) {
+	query := `
+	SELECT uuid, user_uuid, remember_token_hash, remember_token_expires_at, client_ip, user_agent, created_at, last_seen_at
+	FROM sessions
+	WHERE user_uuid=$1
+	AND remember_token_expires_at > NOW()
+	ORDER BY last_seen_at DESC`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return []session.Session{}, err
+	}
+	defer rows.Close()
+
+	var dbSessions []DBSession
+
+	if err := pgxscan.ScanAll(&dbSessions, rows); err != nil {
+		return []session.Session{}, err
+	}
+
+	sessions := make([]session.Session, len(dbSessions))
+	for index, dbSession := range dbSessions {
+		sessions[index] = dbSession.asSession()
+	}
+
+	return sessions, nil
+}
+
+func (r *Repository) SessionUpdateLastSeen(ctx context.Context, update session.LastSeenUpdate) error {
+	query := `
+	UPDATE sessions
+	SET
+		client_ip=@client_ip,
+		user_agent=@user_agent,
+		last_seen_at=@last_seen_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":         update.UUID,
+		"client_ip":    update.ClientIP,
+		"user_agent":   update.UserAgent,
+		"last_seen_at": update.LastSeenAt,
+	}
+
+	return r.QueryTx(ctx, domain, "SessionUpdateLastSeen", query, args)
 }
 
 func (r *Repository) SessionDeleteByRememberTokenHash(ctx context.Context, hash string) error {
@@ -169,3 +227,40 @@ func (r *Repository) SessionDeleteByUserUUID(ctx context.Context, userUUID strin
 
 	return r.QueryTx(ctx, domain, "SessionDeleteByUserUUID", query, args)
 }
+
+func (r *Repository) SessionDeleteByUUID(ctx context.Context, userUUID string, sessionUUID string) error {
+	query := `
+	DELETE FROM sessions
+	WHERE uuid=@uuid
+	AND   user_uuid=@user_uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":      sessionUUID,
+		"user_uuid": userUUID,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return session.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) SessionDeleteByUserUUIDExcept(ctx context.Context, userUUID string, sessionUUID string) error {
+	query := `
+	DELETE FROM sessions
+	WHERE user_uuid=@user_uuid
+	AND   uuid<>@uuid`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+		"uuid":      sessionUUID,
+	}
+
+	return r.QueryTx(ctx, domain, "SessionDeleteByUserUUIDExcept", query, args)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/coder/quartz"
+	"github.com/google/uuid"
 	"github.com/jaswdr/faker/v2"
 
 	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
@@ -22,7 +23,7 @@ func TestRepository(t *testing.T) {
 	pool := pgbase.CreateAndMigrateTestDatabase(t)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	fake := faker.New()
 	u := user.FakeUser(t, &fake)
@@ -39,6 +40,7 @@ func TestRepository(t *testing.T) {
 
 	t.Run("expired sessions are not returned", func(t *testing.T) {
 		sess := session.Session{
+			UUID:                   uuid.NewString(),
 			UserUUID:               testUser.UUID,
 			RememberTokenHash:      "expired-hash",
 			RememberTokenExpiresAt: time.Now().UTC().Add(-1 * time.Hour),
@@ -55,6 +57,7 @@ func TestRepository(t *testing.T) {
 
 	t.Run("non-expired sessions are returned", func(t *testing.T) {
 		sess := session.Session{
+			UUID:                   uuid.NewString(),
 			UserUUID:               testUser.UUID,
 			RememberTokenHash:      "valid-hash",
 			RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
@@ -74,6 +77,7 @@ func TestRepository(t *testing.T) {
 
 	t.Run("delete by remember token hash", func(t *testing.T) {
 		sess := session.Session{
+			UUID:                   uuid.NewString(),
 			UserUUID:               testUser.UUID,
 			RememberTokenHash:      "delete-by-hash",
 			RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
@@ -104,6 +108,7 @@ func TestRepository(t *testing.T) {
 
 		for _, hash := range []string{"multi-1", "multi-2"} {
 			sess := session.Session{
+				UUID:                   uuid.NewString(),
 				UserUUID:               testUser.UUID,
 				RememberTokenHash:      hash,
 				RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
@@ -114,6 +119,7 @@ func TestRepository(t *testing.T) {
 		}
 
 		otherSess := session.Session{
+			UUID:                   uuid.NewString(),
 			UserUUID:               retrievedOtherUser.UUID,
 			RememberTokenHash:      "other-user-session",
 			RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
@@ -141,13 +147,103 @@ func TestRepository(t *testing.T) {
 			t.Errorf("want user UUID %q, got %q", retrievedOtherUser.UUID, got.UserUUID)
 		}
 	})
+
+	t.Run("list, update and revoke sessions", func(t *testing.T) {
+		listUser := user.FakeUser(t, &fake)
+		if err := us.Add(t.Context(), listUser); err != nil {
+			t.Fatalf("failed to create user: %q", err)
+		}
+		retrievedListUser, err := us.ByNickName(t.Context(), listUser.NickName)
+		if err != nil {
+			t.Fatalf("failed to retrieve user: %q", err)
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+
+		current := session.Session{
+			UUID:                   uuid.NewString(),
+			UserUUID:               retrievedListUser.UUID,
+			RememberTokenHash:      "list-current",
+			RememberTokenExpiresAt: now.Add(1 * time.Hour),
+			ClientIP:               "192.0.2.1",
+			UserAgent:              "Firefox",
+			CreatedAt:              now.Add(-2 * time.Hour),
+			LastSeenAt:             now.Add(-2 * time.Hour),
+		}
+		other := session.Session{
+			UUID:                   uuid.NewString(),
+			UserUUID:               retrievedListUser.UUID,
+			RememberTokenHash:      "list-other",
+			RememberTokenExpiresAt: now.Add(1 * time.Hour),
+			ClientIP:               "198.51.100.7",
+			UserAgent:              "Chromium",
+			CreatedAt:              now.Add(-1 * time.Hour),
+			LastSeenAt:             now.Add(-1 * time.Hour),
+		}
+		third := other
+		third.UUID = uuid.NewString()
+		third.RememberTokenHash = "list-third"
+
+		for _, sess := range []session.Session{current, other, third} {
+			if err := r.SessionAdd(t.Context(), sess); err != nil {
+				t.Fatalf("failed to add session: %q", err)
+			}
+		}
+
+		update := session.LastSeenUpdate{
+			UUID:       current.UUID,
+			ClientIP:   "203.0.113.42",
+			UserAgent:  "Firefox Mobile",
+			LastSeenAt: now,
+		}
+		if err := r.SessionUpdateLastSeen(t.Context(), update); err != nil {
+			t.Fatalf("failed to update session: %q", err)
+		}
+
+		got, err := r.SessionGetByUserUUID(t.Context(), retrievedListUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to list sessions: %q", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("want 3 sessions, got %d", len(got))
+		}
+		if got[0].UUID != current.UUID {
+			t.Errorf("want the most recently used session first, got %q", got[0].UUID)
+		}
+		if got[0].ClientIP != update.ClientIP || got[0].UserAgent != update.UserAgent {
+			t.Errorf("want updated client, got %q (%q)", got[0].ClientIP, got[0].UserAgent)
+		}
+		if !got[0].CreatedAt.Equal(current.CreatedAt) {
+			t.Errorf("want creation time %q, got %q", current.CreatedAt, got[0].CreatedAt)
+		}
+
+		if err := r.SessionDeleteByUUID(t.Context(), testUser.UUID, other.UUID); !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("want %q when revoking another user's session, got %q", session.ErrNotFound, err)
+		}
+
+		if err := r.SessionDeleteByUUID(t.Context(), retrievedListUser.UUID, other.UUID); err != nil {
+			t.Fatalf("failed to revoke session: %q", err)
+		}
+
+		if err := r.SessionDeleteByUserUUIDExcept(t.Context(), retrievedListUser.UUID, current.UUID); err != nil {
+			t.Fatalf("failed to revoke other sessions: %q", err)
+		}
+
+		got, err = r.SessionGetByUserUUID(t.Context(), retrievedListUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to list sessions: %q", err)
+		}
+		if len(got) != 1 || got[0].UUID != current.UUID {
+			t.Errorf("want only the current session to remain, got %+v", got)
+		}
+	})
 }
 
 func TestRepository_InvalidateSessions(t *testing.T) {
 	pool := pgbase.CreateAndMigrateTestDatabase(t)
 
 	ur := pguser.NewRepository(pool)
-	us := user.NewService(ur)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
 
 	u := user.FakeUser(t, new(faker.New()))
 	if err := us.Add(t.Context(), u); err != nil {
@@ -163,6 +259,7 @@ func TestRepository_InvalidateSessions(t *testing.T) {
 	r := pgsession.NewRepository(t.Context(), pool, mClock)
 
 	expiredSess := session.Session{
+		UUID:                   uuid.NewString(),
 		UserUUID:               testUser.UUID,
 		RememberTokenHash:      "periodic-cleanup-expired",
 		RememberTokenExpiresAt: time.Now().UTC().Add(-1 * time.Hour),
@@ -172,6 +269,7 @@ func TestRepository_InvalidateSessions(t *testing.T) {
 	}
 
 	validSess := session.Session{
+		UUID:                   uuid.NewString(),
 		UserUUID:               testUser.UUID,
 		RememberTokenHash:      "periodic-cleanup-valid",
 		RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
@@ -0,0 +1,44 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsso
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+)
+
+type DBIdentity struct {
+	Issuer    string    `db:"issuer"`
+	Subject   string    `db:"subject"`
+	UserUUID  string    `db:"user_uuid"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (i *DBIdentity) asIdentity() sso.Identity {
+	return sso.Identity{
+		Issuer:    i.Issuer,
+		Subject:   i.Subject,
+		UserUUID:  i.UserUUID,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+type DBAuthorizationRequest struct {
+	StateHash    string    `db:"state_hash"`
+	CodeVerifier string    `db:"code_verifier"`
+	Nonce        string    `db:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (r *DBAuthorizationRequest) asAuthorizationRequest() sso.AuthorizationRequest {
+	return sso.AuthorizationRequest{
+		StateHash:    r.StateHash,
+		CodeVerifier: r.CodeVerifier,
+		Nonce:        r.Nonce,
+		ExpiresAt:    r.ExpiresAt,
+		CreatedAt:    r.CreatedAt,
+	}
+}
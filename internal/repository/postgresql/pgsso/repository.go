@@ -0,0 +1,165 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsso
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+)
+
+var _ sso.Repository = &Repository{}
+
+const (
+	domain = "sso"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for single sign-on.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) IdentityAdd(ctx context.Context, identity sso.Identity) error {
+	query := `
+	INSERT INTO sso_identities(
+		issuer,
+		subject,
+		user_uuid,
+		created_at
+	)
+	VALUES(
+		@issuer,
+		@subject,
+		@user_uuid,
+		@created_at
+	)`
+
+	args := pgx.NamedArgs{
+		"issuer":     identity.Issuer,
+		"subject":    identity.Subject,
+		"user_uuid":  identity.UserUUID,
+		"created_at": identity.CreatedAt,
+	}
+
+	_, err := r.Pool.Exec(ctx, query, args)
+	return err
+}
+
+func (r *Repository) IdentityGetBySubject(ctx context.Context, issuer string, subject string) (sso.Identity, error) {
+	query := `
+	SELECT issuer, subject, user_uuid, created_at
+	FROM sso_identities
+	WHERE issuer=$1
+	AND   subject=$2`
+
+	rows, err := r.Pool.Query(ctx, query, issuer, subject)
+	if err != nil {
+		return sso.Identity{}, err
+	}
+	defer rows.Close()
+
+	dbIdentity := &DBIdentity{}
+	err = pgxscan.ScanOne(dbIdentity, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sso.Identity{}, sso.ErrNotFound
+	}
+	if err != nil {
+		return sso.Identity{}, err
+	}
+
+	return dbIdentity.asIdentity(), nil
+}
+
+func (r *Repository) IdentityIsRegistered(ctx context.Context, userUUID string) (bool, error) {
+	return r.RowExistsByQuery(
+		ctx,
+		"SELECT 1 FROM sso_identities WHERE user_uuid=$1",
+		userUUID,
+	)
+}
+
+func (r *Repository) AuthorizationRequestAdd(ctx context.Context, request sso.AuthorizationRequest) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "AuthorizationRequestAdd")
+
+	// Take this opportunity to purge expired authorization requests.
+	if _, err := tx.Exec(ctx, "DELETE FROM sso_authorization_requests WHERE expires_at <= NOW()"); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO sso_authorization_requests(
+		state_hash,
+		code_verifier,
+		nonce,
+		expires_at,
+		created_at
+	)
+	VALUES(
+		@state_hash,
+		@code_verifier,
+		@nonce,
+		@expires_at,
+		@created_at
+	)`
+
+	insertArgs := pgx.NamedArgs{
+		"state_hash":    request.StateHash,
+		"code_verifier": request.CodeVerifier,
+		"nonce":         request.Nonce,
+		"expires_at":    request.ExpiresAt,
+		"created_at":    request.CreatedAt,
+	}
+
+	if _, err := tx.Exec(ctx, insertQuery, insertArgs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) AuthorizationRequestConsume(ctx context.Context, stateHash string) (sso.AuthorizationRequest, error) {
+	// Deleting the request guarantees it can only be completed once, even with
+	// concurrent requests.
+	query := `
+	DELETE FROM sso_authorization_requests
+	WHERE state_hash=$1
+	AND   expires_at > NOW()
+	RETURNING state_hash, code_verifier, nonce, expires_at, created_at`
+
+	rows, err := r.Pool.Query(ctx, query, stateHash)
+	if err != nil {
+		return sso.AuthorizationRequest{}, err
+	}
+	defer rows.Close()
+
+	dbRequest := &DBAuthorizationRequest{}
+	err = pgxscan.ScanOne(dbRequest, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sso.AuthorizationRequest{}, sso.ErrNotFound
+	}
+	if err != nil {
+		return sso.AuthorizationRequest{}, err
+	}
+
+	return dbRequest.asAuthorizationRequest(), nil
+}
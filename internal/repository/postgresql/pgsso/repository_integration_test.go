@@ -0,0 +1,112 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgsso_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgsso"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/sso"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgsso.NewRepository(pool)
+	now := time.Now().UTC()
+
+	t.Run("identities", func(t *testing.T) {
+		identity := sso.Identity{
+			Issuer:    "https://idp.example.org",
+			Subject:   "subject",
+			UserUUID:  testUser.UUID,
+			CreatedAt: now,
+		}
+
+		if err := r.IdentityAdd(t.Context(), identity); err != nil {
+			t.Fatalf("failed to add identity: %q", err)
+		}
+
+		got, err := r.IdentityGetBySubject(t.Context(), identity.Issuer, identity.Subject)
+		if err != nil {
+			t.Fatalf("failed to retrieve identity: %q", err)
+		}
+		if got.UserUUID != testUser.UUID {
+			t.Errorf("want user %q, got %q", testUser.UUID, got.UserUUID)
+		}
+
+		_, err = r.IdentityGetBySubject(t.Context(), "https://other.example.org", identity.Subject)
+		if !errors.Is(err, sso.ErrNotFound) {
+			t.Errorf("want %q, got %q", sso.ErrNotFound, err)
+		}
+
+		linked, err := r.IdentityIsRegistered(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to check identity: %q", err)
+		}
+		if !linked {
+			t.Error("want the user to be linked to an identity")
+		}
+	})
+
+	t.Run("authorization requests", func(t *testing.T) {
+		expired := sso.AuthorizationRequest{
+			StateHash:    "expired-state-hash",
+			CodeVerifier: "expired-code-verifier",
+			Nonce:        "expired-nonce",
+			ExpiresAt:    now.Add(-time.Minute),
+			CreatedAt:    now.Add(-sso.AuthorizationRequestTTL),
+		}
+		pending := sso.AuthorizationRequest{
+			StateHash:    "pending-state-hash",
+			CodeVerifier: "pending-code-verifier",
+			Nonce:        "pending-nonce",
+			ExpiresAt:    now.Add(sso.AuthorizationRequestTTL),
+			CreatedAt:    now,
+		}
+
+		for _, request := range []sso.AuthorizationRequest{expired, pending} {
+			if err := r.AuthorizationRequestAdd(t.Context(), request); err != nil {
+				t.Fatalf("failed to add authorization request: %q", err)
+			}
+		}
+
+		if _, err := r.AuthorizationRequestConsume(t.Context(), expired.StateHash); !errors.Is(err, sso.ErrNotFound) {
+			t.Errorf("want %q for an expired request, got %q", sso.ErrNotFound, err)
+		}
+
+		got, err := r.AuthorizationRequestConsume(t.Context(), pending.StateHash)
+		if err != nil {
+			t.Fatalf("failed to consume authorization request: %q", err)
+		}
+		if got.CodeVerifier != pending.CodeVerifier || got.Nonce != pending.Nonce {
+			t.Errorf("unexpected authorization request: %+v", got)
+		}
+
+		if _, err := r.AuthorizationRequestConsume(t.Context(), pending.StateHash); !errors.Is(err, sso.ErrNotFound) {
+			t.Errorf("want %q for a consumed request, got %q", sso.ErrNotFound, err)
+		}
+	})
+}
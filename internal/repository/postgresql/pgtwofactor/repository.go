@@ -0,0 +1,257 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgtwofactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+)
+
+var _ twofactor.Repository = &Repository{}
+
+const (
+	domain = "two-factor"
+)
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+// NewRepository initializes and returns a PostgreSQL Repository for two-factor authentication.
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+func (r *Repository) TwoFactorTOTPGetByUserUUID(ctx context.Context, userUUID string) (twofactor.TOTP, error) {
+	query := `
+	SELECT user_uuid, secret, enabled, last_used_step, created_at, updated_at
+	FROM two_factor_totp
+	WHERE user_uuid=$1`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return twofactor.TOTP{}, err
+	}
+	defer rows.Close()
+
+	dbTOTP := &DBTOTP{}
+	err = pgxscan.ScanOne(dbTOTP, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return twofactor.TOTP{}, twofactor.ErrNotFound
+	}
+	if err != nil {
+		return twofactor.TOTP{}, err
+	}
+
+	return twofactor.TOTP{
+		UserUUID:     dbTOTP.UserUUID,
+		Secret:       dbTOTP.Secret,
+		Enabled:      dbTOTP.Enabled,
+		LastUsedStep: dbTOTP.LastUsedStep,
+		CreatedAt:    dbTOTP.CreatedAt,
+		UpdatedAt:    dbTOTP.UpdatedAt,
+	}, nil
+}
+
+func (r *Repository) TwoFactorTOTPSetup(ctx context.Context, t twofactor.TOTP) error {
+	// An enabled enrollment must be explicitly disabled before setting up a new one.
+	query := `
+	INSERT INTO two_factor_totp(
+		user_uuid,
+		secret,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@user_uuid,
+		@secret,
+		@created_at,
+		@updated_at
+	)
+	ON CONFLICT (user_uuid) DO UPDATE
+	SET
+		secret=EXCLUDED.secret,
+		last_used_step=0,
+		created_at=EXCLUDED.created_at,
+		updated_at=EXCLUDED.updated_at
+	WHERE two_factor_totp.enabled=FALSE`
+
+	args := pgx.NamedArgs{
+		"user_uuid":  t.UserUUID,
+		"secret":     t.Secret,
+		"created_at": t.CreatedAt,
+		"updated_at": t.UpdatedAt,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return twofactor.ErrAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *Repository) TwoFactorTOTPEnable(ctx context.Context, enable twofactor.TOTPEnable, recoveryCodes []twofactor.RecoveryCode) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "TwoFactorTOTPEnable")
+
+	updateQuery := `
+	UPDATE two_factor_totp
+	SET
+		enabled=TRUE,
+		last_used_step=@last_used_step,
+		updated_at=@updated_at
+	WHERE user_uuid=@user_uuid
+	AND   enabled=FALSE`
+
+	updateArgs := pgx.NamedArgs{
+		"user_uuid":      enable.UserUUID,
+		"last_used_step": enable.LastUsedStep,
+		"updated_at":     enable.UpdatedAt,
+	}
+
+	commandTag, err := tx.Exec(ctx, updateQuery, updateArgs)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return twofactor.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM two_factor_recovery_codes WHERE user_uuid=$1", enable.UserUUID); err != nil {
+		return err
+	}
+
+	insertQuery := `
+	INSERT INTO two_factor_recovery_codes(
+		user_uuid,
+		code_hash,
+		created_at
+	)
+	VALUES(
+		@user_uuid,
+		@code_hash,
+		@created_at
+	)`
+
+	batch := &pgx.Batch{}
+
+	for _, recoveryCode := range recoveryCodes {
+		args := pgx.NamedArgs{
+			"user_uuid":  recoveryCode.UserUUID,
+			"code_hash":  recoveryCode.CodeHash,
+			"created_at": recoveryCode.CreatedAt,
+		}
+
+		batch.Queue(insertQuery, args)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) TwoFactorTOTPLastUsedStepUpdate(ctx context.Context, userUUID string, step int64) error {
+	// The condition on the last used step guarantees a code is only accepted once,
+	// even with concurrent requests.
+	query := `
+	UPDATE two_factor_totp
+	SET last_used_step=@last_used_step
+	WHERE user_uuid=@user_uuid
+	AND   last_used_step < @last_used_step`
+
+	args := pgx.NamedArgs{
+		"user_uuid":      userUUID,
+		"last_used_step": step,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return twofactor.ErrCodeAlreadyUsed
+	}
+
+	return nil
+}
+
+func (r *Repository) TwoFactorRecoveryCodeConsume(ctx context.Context, userUUID string, codeHash string) error {
+	query := `
+	DELETE FROM two_factor_recovery_codes
+	WHERE user_uuid=@user_uuid
+	AND   code_hash=@code_hash`
+
+	args := pgx.NamedArgs{
+		"user_uuid": userUUID,
+		"code_hash": codeHash,
+	}
+
+	commandTag, err := r.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return twofactor.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) TwoFactorRecoveryCodeCountUnused(ctx context.Context, userUUID string) (int, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM two_factor_recovery_codes
+	WHERE user_uuid=$1`
+
+	var count int
+
+	if err := r.Pool.QueryRow(ctx, query, userUUID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) TwoFactorDelete(ctx context.Context, userUUID string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "TwoFactorDelete")
+
+	if _, err := tx.Exec(ctx, "DELETE FROM two_factor_recovery_codes WHERE user_uuid=$1", userUUID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM two_factor_totp WHERE user_uuid=$1", userUUID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
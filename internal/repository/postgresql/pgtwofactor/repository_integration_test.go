@@ -0,0 +1,143 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgtwofactor_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgtwofactor"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/pkg/twofactor"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	r := pgtwofactor.NewRepository(pool)
+	now := time.Now().UTC()
+
+	t.Run("not enrolled", func(t *testing.T) {
+		_, err := r.TwoFactorTOTPGetByUserUUID(t.Context(), testUser.UUID)
+		if !errors.Is(err, twofactor.ErrNotFound) {
+			t.Fatalf("want %q, got %q", twofactor.ErrNotFound, err)
+		}
+	})
+
+	t.Run("setup and enable", func(t *testing.T) {
+		for _, secret := range []string{"FIRSTSECRET", "SECONDSECRET"} {
+			totp := twofactor.TOTP{
+				UserUUID:  testUser.UUID,
+				Secret:    secret,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := r.TwoFactorTOTPSetup(t.Context(), totp); err != nil {
+				t.Fatalf("failed to set up TOTP: %q", err)
+			}
+		}
+
+		got, err := r.TwoFactorTOTPGetByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve TOTP: %q", err)
+		}
+		if got.Secret != "SECONDSECRET" {
+			t.Errorf("want the latest secret, got %q", got.Secret)
+		}
+		if got.Enabled {
+			t.Error("want TOTP to be disabled before confirmation")
+		}
+
+		enable := twofactor.TOTPEnable{
+			UserUUID:     testUser.UUID,
+			LastUsedStep: 100,
+			UpdatedAt:    now,
+		}
+		recoveryCodes := []twofactor.RecoveryCode{
+			{UserUUID: testUser.UUID, CodeHash: "hash-1", CreatedAt: now},
+			{UserUUID: testUser.UUID, CodeHash: "hash-2", CreatedAt: now},
+		}
+
+		if err := r.TwoFactorTOTPEnable(t.Context(), enable, recoveryCodes); err != nil {
+			t.Fatalf("failed to enable TOTP: %q", err)
+		}
+
+		totp := twofactor.TOTP{
+			UserUUID:  testUser.UUID,
+			Secret:    "THIRDSECRET",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := r.TwoFactorTOTPSetup(t.Context(), totp); !errors.Is(err, twofactor.ErrAlreadyEnabled) {
+			t.Fatalf("want %q, got %q", twofactor.ErrAlreadyEnabled, err)
+		}
+	})
+
+	t.Run("TOTP steps are only accepted once", func(t *testing.T) {
+		if err := r.TwoFactorTOTPLastUsedStepUpdate(t.Context(), testUser.UUID, 101); err != nil {
+			t.Fatalf("failed to update last used step: %q", err)
+		}
+
+		err := r.TwoFactorTOTPLastUsedStepUpdate(t.Context(), testUser.UUID, 101)
+		if !errors.Is(err, twofactor.ErrCodeAlreadyUsed) {
+			t.Fatalf("want %q, got %q", twofactor.ErrCodeAlreadyUsed, err)
+		}
+	})
+
+	t.Run("recovery codes are only accepted once", func(t *testing.T) {
+		if err := r.TwoFactorRecoveryCodeConsume(t.Context(), testUser.UUID, "hash-1"); err != nil {
+			t.Fatalf("failed to consume recovery code: %q", err)
+		}
+
+		err := r.TwoFactorRecoveryCodeConsume(t.Context(), testUser.UUID, "hash-1")
+		if !errors.Is(err, twofactor.ErrNotFound) {
+			t.Fatalf("want %q, got %q", twofactor.ErrNotFound, err)
+		}
+
+		count, err := r.TwoFactorRecoveryCodeCountUnused(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to count recovery codes: %q", err)
+		}
+		if count != 1 {
+			t.Errorf("want 1 unused recovery code, got %d", count)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := r.TwoFactorDelete(t.Context(), testUser.UUID); err != nil {
+			t.Fatalf("failed to delete TOTP: %q", err)
+		}
+
+		if _, err := r.TwoFactorTOTPGetByUserUUID(t.Context(), testUser.UUID); !errors.Is(err, twofactor.ErrNotFound) {
+			t.Fatalf("want %q, got %q", twofactor.ErrNotFound, err)
+		}
+
+		count, err := r.TwoFactorRecoveryCodeCountUnused(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("failed to count recovery codes: %q", err)
+		}
+		if count != 0 {
+			t.Errorf("want no recovery codes, got %d", count)
+		}
+	})
+}
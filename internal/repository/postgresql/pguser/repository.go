@@ -260,6 +260,23 @@ func (r *Repository) UserUpdate(ctx context.Context, u user.User) error {
 	return r.QueryTx(ctx, domain, "UserUpdate", query, args)
 }
 
+func (r *Repository) UserUpdateAdmin(ctx context.Context, adminUpdate user.AdminUpdate) error {
+	query := `
+	UPDATE users
+	SET
+		is_admin=@is_admin,
+		updated_at=@updated_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":       adminUpdate.UserUUID,
+		"is_admin":   adminUpdate.IsAdmin,
+		"updated_at": adminUpdate.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "UserUpdateAdmin", query, args)
+}
+
 func (r *Repository) UserUpdateInfo(ctx context.Context, info user.InfoUpdate) error {
 	query := `
 	UPDATE users
@@ -20,10 +20,10 @@ func TestUserService(t *testing.T) {
 	pool := pgbase.CreateAndMigrateTestDatabase(t)
 	r := pguser.NewRepository(pool)
 
-	s := user.NewService(r)
+	s := user.NewService(r, user.FakePasswordHasher(t))
 
 	fr := pgfeed.NewRepository(pool)
-	fs := feed.NewService(fr, nil, nil)
+	fs := feed.NewService(fr, nil, nil, nil)
 
 	fake := faker.New()
 
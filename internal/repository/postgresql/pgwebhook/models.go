@@ -0,0 +1,152 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgwebhook
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+type DBWebhook struct {
+	UUID     string `db:"uuid"`
+	UserUUID string `db:"user_uuid"`
+
+	URL    string   `db:"url"`
+	Secret string   `db:"secret"`
+	Events []string `db:"events"`
+
+	FeedCategoryUUID     *string `db:"feed_category_uuid"`
+	FeedSubscriptionUUID *string `db:"feed_subscription_uuid"`
+
+	Active bool `db:"active"`
+
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (w *DBWebhook) asWebhook() webhook.Webhook {
+	events := make([]webhook.Event, 0, len(w.Events))
+	for _, event := range w.Events {
+		events = append(events, webhook.Event(event))
+	}
+
+	return webhook.Webhook{
+		UUID:                 w.UUID,
+		UserUUID:             w.UserUUID,
+		URL:                  w.URL,
+		Secret:               w.Secret,
+		Events:               events,
+		FeedCategoryUUID:     fromNullableString(w.FeedCategoryUUID),
+		FeedSubscriptionUUID: fromNullableString(w.FeedSubscriptionUUID),
+		Active:               w.Active,
+		CreatedAt:            w.CreatedAt,
+		UpdatedAt:            w.UpdatedAt,
+	}
+}
+
+func webhookEventsToStrings(events []webhook.Event) []string {
+	values := make([]string, 0, len(events))
+	for _, event := range events {
+		values = append(values, string(event))
+	}
+
+	return values
+}
+
+type DBDelivery struct {
+	UUID        string `db:"uuid"`
+	WebhookUUID string `db:"webhook_uuid"`
+
+	Event   string `db:"event"`
+	Payload []byte `db:"payload"`
+
+	Status             string `db:"status"`
+	Attempts           int    `db:"attempts"`
+	ResponseStatusCode int    `db:"response_status_code"`
+	Error              string `db:"error"`
+
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (d *DBDelivery) asDelivery() webhook.Delivery {
+	return webhook.Delivery{
+		UUID:               d.UUID,
+		WebhookUUID:        d.WebhookUUID,
+		Event:              webhook.Event(d.Event),
+		Payload:            d.Payload,
+		Status:             webhook.DeliveryStatus(d.Status),
+		Attempts:           d.Attempts,
+		ResponseStatusCode: d.ResponseStatusCode,
+		Error:              d.Error,
+		NextAttemptAt:      d.NextAttemptAt,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+	}
+}
+
+type DBPendingDelivery struct {
+	DBDelivery
+
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func (pd *DBPendingDelivery) asPendingDelivery() webhook.PendingDelivery {
+	return webhook.PendingDelivery{
+		Delivery: pd.asDelivery(),
+		URL:      pd.URL,
+		Secret:   pd.Secret,
+	}
+}
+
+type DBFeedEntry struct {
+	UID         string    `db:"uid"`
+	URL         string    `db:"url"`
+	Title       string    `db:"title"`
+	Summary     string    `db:"summary"`
+	PublishedAt time.Time `db:"published_at"`
+	CreatedAt   time.Time `db:"created_at"`
+
+	FeedTitle string `db:"feed_title"`
+	FeedURL   string `db:"feed_url"`
+
+	SubscriptionUUID string `db:"subscription_uuid"`
+	CategoryUUID     string `db:"category_uuid"`
+	CategoryName     string `db:"category_name"`
+}
+
+func (e *DBFeedEntry) asFeedEntry() webhook.FeedEntry {
+	return webhook.FeedEntry{
+		UID:              e.UID,
+		URL:              e.URL,
+		Title:            e.Title,
+		Summary:          e.Summary,
+		PublishedAt:      e.PublishedAt,
+		CreatedAt:        e.CreatedAt,
+		FeedTitle:        e.FeedTitle,
+		FeedURL:          e.FeedURL,
+		SubscriptionUUID: e.SubscriptionUUID,
+		CategoryUUID:     e.CategoryUUID,
+		CategoryName:     e.CategoryName,
+	}
+}
+
+func fromNullableString(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func toNullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,354 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgwebhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+var _ webhook.Repository = &Repository{}
+
+type Repository struct {
+	*pgbase.Repository
+}
+
+func NewRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Repository: pgbase.NewRepository(pool),
+	}
+}
+
+const (
+	domain = "webhooks"
+)
+
+func (r *Repository) WebhookAdd(ctx context.Context, w webhook.Webhook) error {
+	query := `
+	INSERT INTO webhooks(
+		uuid,
+		user_uuid,
+		url,
+		secret,
+		events,
+		feed_category_uuid,
+		feed_subscription_uuid,
+		active,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@uuid,
+		@user_uuid,
+		@url,
+		@secret,
+		@events,
+		@feed_category_uuid,
+		@feed_subscription_uuid,
+		@active,
+		@created_at,
+		@updated_at
+	)`
+
+	args := pgx.NamedArgs{
+		"uuid":                   w.UUID,
+		"user_uuid":              w.UserUUID,
+		"url":                    w.URL,
+		"secret":                 w.Secret,
+		"events":                 webhookEventsToStrings(w.Events),
+		"feed_category_uuid":     toNullableString(w.FeedCategoryUUID),
+		"feed_subscription_uuid": toNullableString(w.FeedSubscriptionUUID),
+		"active":                 w.Active,
+		"created_at":             w.CreatedAt,
+		"updated_at":             w.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "WebhookAdd", query, args)
+}
+
+func (r *Repository) WebhookDelete(ctx context.Context, userUUID string, webhookUUID string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "WebhookDelete")
+
+	commandTag, err := tx.Exec(
+		ctx,
+		"DELETE FROM webhooks WHERE user_uuid=$1 AND uuid=$2",
+		userUUID,
+		webhookUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return webhook.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) WebhookGetAll(ctx context.Context, userUUID string) ([]webhook.Webhook, error) {
+	query := `
+	SELECT uuid, user_uuid, url, secret, events, feed_category_uuid, feed_subscription_uuid, active, created_at, updated_at
+	FROM webhooks
+	WHERE user_uuid=$1
+	ORDER BY created_at`
+
+	return r.webhookGetManyQuery(ctx, query, userUUID)
+}
+
+func (r *Repository) WebhookGetByUUID(ctx context.Context, userUUID string, webhookUUID string) (webhook.Webhook, error) {
+	query := `
+	SELECT uuid, user_uuid, url, secret, events, feed_category_uuid, feed_subscription_uuid, active, created_at, updated_at
+	FROM webhooks
+	WHERE user_uuid=$1
+	AND   uuid=$2`
+
+	return r.webhookGetQuery(ctx, query, userUUID, webhookUUID)
+}
+
+func (r *Repository) WebhookGetManyByEvent(ctx context.Context, event webhook.Event) ([]webhook.Webhook, error) {
+	query := `
+	SELECT uuid, user_uuid, url, secret, events, feed_category_uuid, feed_subscription_uuid, active, created_at, updated_at
+	FROM webhooks
+	WHERE active
+	AND   $1 = ANY(events)`
+
+	return r.webhookGetManyQuery(ctx, query, string(event))
+}
+
+func (r *Repository) WebhookGetManyByUserAndEvent(ctx context.Context, userUUID string, event webhook.Event) ([]webhook.Webhook, error) {
+	query := `
+	SELECT uuid, user_uuid, url, secret, events, feed_category_uuid, feed_subscription_uuid, active, created_at, updated_at
+	FROM webhooks
+	WHERE user_uuid=$1
+	AND   active
+	AND   $2 = ANY(events)`
+
+	return r.webhookGetManyQuery(ctx, query, userUUID, string(event))
+}
+
+func (r *Repository) WebhookUpdate(ctx context.Context, w webhook.Webhook) error {
+	query := `
+	UPDATE webhooks
+	SET
+		url=@url,
+		secret=@secret,
+		events=@events,
+		feed_category_uuid=@feed_category_uuid,
+		feed_subscription_uuid=@feed_subscription_uuid,
+		active=@active,
+		updated_at=@updated_at
+	WHERE user_uuid=@user_uuid
+	AND   uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"user_uuid":              w.UserUUID,
+		"uuid":                   w.UUID,
+		"url":                    w.URL,
+		"secret":                 w.Secret,
+		"events":                 webhookEventsToStrings(w.Events),
+		"feed_category_uuid":     toNullableString(w.FeedCategoryUUID),
+		"feed_subscription_uuid": toNullableString(w.FeedSubscriptionUUID),
+		"active":                 w.Active,
+		"updated_at":             w.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "WebhookUpdate", query, args)
+}
+
+func (r *Repository) WebhookFeedEntryQueuePending(ctx context.Context, webhooks []webhook.Webhook, n uint, newDeliveries func(w webhook.Webhook, entries []webhook.FeedEntry) ([]webhook.Delivery, error)) (int64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer r.Rollback(ctx, tx, domain, "WebhookFeedEntryQueuePending")
+
+	// Lock the pending entries, so that concurrent runs do not queue them twice.
+	pendingQuery := `
+	SELECT uid
+	FROM feed_entries
+	WHERE NOT webhooks_queued
+	ORDER BY created_at
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, pendingQuery, n)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var entryUIDs []string
+
+	if err := pgxscan.ScanAll(&entryUIDs, rows); err != nil {
+		return 0, err
+	}
+
+	if len(entryUIDs) == 0 {
+		return 0, nil
+	}
+
+	var deliveries []webhook.Delivery
+
+	for _, w := range webhooks {
+		entries, err := webhookFeedEntryGetManyTx(ctx, tx, w, entryUIDs)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(entries) == 0 {
+			continue
+		}
+
+		webhookDeliveries, err := newDeliveries(w, entries)
+		if err != nil {
+			return 0, err
+		}
+
+		deliveries = append(deliveries, webhookDeliveries...)
+	}
+
+	if len(deliveries) > 0 {
+		if err := tx.SendBatch(ctx, webhookDeliveryAddManyBatch(deliveries)).Close(); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE feed_entries SET webhooks_queued=TRUE WHERE uid=ANY($1)", entryUIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(entryUIDs)), nil
+}
+
+func (r *Repository) WebhookDeliveryAddMany(ctx context.Context, deliveries []webhook.Delivery) error {
+	return r.BatchTx(ctx, domain, "WebhookDeliveryAddMany", webhookDeliveryAddManyBatch(deliveries))
+}
+
+func (r *Repository) WebhookDeliveryGetNByWebhook(ctx context.Context, userUUID string, webhookUUID string, n uint) ([]webhook.Delivery, error) {
+	query := `
+	SELECT
+		d.uuid,
+		d.webhook_uuid,
+		d.event,
+		d.payload,
+		d.status,
+		d.attempts,
+		d.response_status_code,
+		d.error,
+		d.next_attempt_at,
+		d.created_at,
+		d.updated_at
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.uuid = d.webhook_uuid
+	WHERE w.user_uuid = $1
+	AND   d.webhook_uuid = $2
+	ORDER BY d.created_at DESC
+	LIMIT $3`
+
+	rows, err := r.Pool.Query(ctx, query, userUUID, webhookUUID, n)
+	if err != nil {
+		return []webhook.Delivery{}, err
+	}
+	defer rows.Close()
+
+	var dbDeliveries []DBDelivery
+
+	if err := pgxscan.ScanAll(&dbDeliveries, rows); err != nil {
+		return []webhook.Delivery{}, err
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(dbDeliveries))
+	for _, dbDelivery := range dbDeliveries {
+		deliveries = append(deliveries, dbDelivery.asDelivery())
+	}
+
+	return deliveries, nil
+}
+
+func (r *Repository) WebhookDeliveryGetNPending(ctx context.Context, n uint, before time.Time) ([]webhook.PendingDelivery, error) {
+	query := `
+	SELECT
+		d.uuid,
+		d.webhook_uuid,
+		d.event,
+		d.payload,
+		d.status,
+		d.attempts,
+		d.response_status_code,
+		d.error,
+		d.next_attempt_at,
+		d.created_at,
+		d.updated_at,
+		w.url,
+		w.secret
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.uuid = d.webhook_uuid
+	WHERE w.active
+	AND   d.status = 'PENDING'::webhook_delivery_status
+	AND   d.next_attempt_at <= $1
+	ORDER BY d.next_attempt_at
+	LIMIT $2`
+
+	rows, err := r.Pool.Query(ctx, query, before, n)
+	if err != nil {
+		return []webhook.PendingDelivery{}, err
+	}
+	defer rows.Close()
+
+	var dbPendingDeliveries []DBPendingDelivery
+
+	if err := pgxscan.ScanAll(&dbPendingDeliveries, rows); err != nil {
+		return []webhook.PendingDelivery{}, err
+	}
+
+	pendingDeliveries := make([]webhook.PendingDelivery, 0, len(dbPendingDeliveries))
+	for _, dbPendingDelivery := range dbPendingDeliveries {
+		pendingDeliveries = append(pendingDeliveries, dbPendingDelivery.asPendingDelivery())
+	}
+
+	return pendingDeliveries, nil
+}
+
+func (r *Repository) WebhookDeliveryUpdate(ctx context.Context, d webhook.Delivery) error {
+	query := `
+	UPDATE webhook_deliveries
+	SET
+		status=@status,
+		attempts=@attempts,
+		response_status_code=@response_status_code,
+		error=@error,
+		next_attempt_at=@next_attempt_at,
+		updated_at=@updated_at
+	WHERE uuid=@uuid`
+
+	args := pgx.NamedArgs{
+		"uuid":                 d.UUID,
+		"status":               string(d.Status),
+		"attempts":             d.Attempts,
+		"response_status_code": d.ResponseStatusCode,
+		"error":                d.Error,
+		"next_attempt_at":      d.NextAttemptAt,
+		"updated_at":           d.UpdatedAt,
+	}
+
+	return r.QueryTx(ctx, domain, "WebhookDeliveryUpdate", query, args)
+}
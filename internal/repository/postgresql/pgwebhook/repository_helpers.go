@@ -0,0 +1,165 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgwebhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+func (r *Repository) webhookGetQuery(ctx context.Context, query string, queryParams ...any) (webhook.Webhook, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	defer rows.Close()
+
+	dbWebhook := &DBWebhook{}
+	err = pgxscan.ScanOne(dbWebhook, rows)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return webhook.Webhook{}, webhook.ErrNotFound
+	}
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	return dbWebhook.asWebhook(), nil
+}
+
+func (r *Repository) webhookGetManyQuery(ctx context.Context, query string, queryParams ...any) ([]webhook.Webhook, error) {
+	rows, err := r.Pool.Query(ctx, query, queryParams...)
+	if err != nil {
+		return []webhook.Webhook{}, err
+	}
+	defer rows.Close()
+
+	var dbWebhooks []DBWebhook
+
+	if err := pgxscan.ScanAll(&dbWebhooks, rows); err != nil {
+		return []webhook.Webhook{}, err
+	}
+
+	webhooks := make([]webhook.Webhook, 0, len(dbWebhooks))
+	for _, dbWebhook := range dbWebhooks {
+		webhooks = append(webhooks, dbWebhook.asWebhook())
+	}
+
+	return webhooks, nil
+}
+
+// webhookFeedEntryGetManyTx returns the feed entries with the given UIDs for the subscriptions
+// of the Webhook's user, matching the Webhook's category or subscription filter.
+//
+// Entries created before the corresponding subscription are excluded, to avoid notifying
+// about the existing entries of a newly subscribed feed.
+func webhookFeedEntryGetManyTx(ctx context.Context, tx pgx.Tx, w webhook.Webhook, entryUIDs []string) ([]webhook.FeedEntry, error) {
+	query := `
+	SELECT
+		fe.uid,
+		fe.url,
+		fe.title,
+		fe.summary,
+		fe.published_at,
+		fe.created_at,
+		f.title    AS feed_title,
+		f.feed_url AS feed_url,
+		fs.uuid    AS subscription_uuid,
+		fc.uuid    AS category_uuid,
+		fc.name    AS category_name
+	FROM feed_entries fe
+	JOIN feed_feeds f          ON f.uuid = fe.feed_uuid
+	JOIN feed_subscriptions fs ON fs.feed_uuid = fe.feed_uuid
+	JOIN feed_categories fc    ON fc.uuid = fs.category_uuid
+	WHERE fs.user_uuid = @user_uuid
+	AND   fe.uid = ANY(@entry_uids)
+	AND   fe.created_at > fs.created_at
+	AND   (@category_uuid::UUID IS NULL OR fs.category_uuid = @category_uuid::UUID)
+	AND   (@subscription_uuid::UUID IS NULL OR fs.uuid = @subscription_uuid::UUID)
+	ORDER BY fe.created_at, fe.published_at`
+
+	args := pgx.NamedArgs{
+		"user_uuid":         w.UserUUID,
+		"entry_uids":        entryUIDs,
+		"category_uuid":     toNullableString(w.FeedCategoryUUID),
+		"subscription_uuid": toNullableString(w.FeedSubscriptionUUID),
+	}
+
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return []webhook.FeedEntry{}, err
+	}
+	defer rows.Close()
+
+	var dbEntries []DBFeedEntry
+
+	if err := pgxscan.ScanAll(&dbEntries, rows); err != nil {
+		return []webhook.FeedEntry{}, err
+	}
+
+	entries := make([]webhook.FeedEntry, 0, len(dbEntries))
+	for _, dbEntry := range dbEntries {
+		entries = append(entries, dbEntry.asFeedEntry())
+	}
+
+	return entries, nil
+}
+
+// webhookDeliveryAddManyBatch returns a batch of queries queuing the given deliveries.
+func webhookDeliveryAddManyBatch(deliveries []webhook.Delivery) *pgx.Batch {
+	query := `
+	INSERT INTO webhook_deliveries(
+		uuid,
+		webhook_uuid,
+		event,
+		payload,
+		status,
+		attempts,
+		response_status_code,
+		error,
+		next_attempt_at,
+		created_at,
+		updated_at
+	)
+	VALUES(
+		@uuid,
+		@webhook_uuid,
+		@event,
+		@payload,
+		@status,
+		@attempts,
+		@response_status_code,
+		@error,
+		@next_attempt_at,
+		@created_at,
+		@updated_at
+	)`
+
+	batch := &pgx.Batch{}
+
+	for _, d := range deliveries {
+		args := pgx.NamedArgs{
+			"uuid":                 d.UUID,
+			"webhook_uuid":         d.WebhookUUID,
+			"event":                string(d.Event),
+			"payload":              string(d.Payload),
+			"status":               string(d.Status),
+			"attempts":             d.Attempts,
+			"response_status_code": d.ResponseStatusCode,
+			"error":                d.Error,
+			"next_attempt_at":      d.NextAttemptAt,
+			"created_at":           d.CreatedAt,
+			"updated_at":           d.UpdatedAt,
+		}
+
+		batch.Queue(query, args)
+	}
+
+	return batch
+}
@@ -0,0 +1,215 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package pgwebhook_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgbase"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgfeed"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pguser"
+	"github.com/virtualtam/sparklemuffin/internal/repository/postgresql/pgwebhook"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+	"github.com/virtualtam/sparklemuffin/pkg/webhook"
+)
+
+func TestRepository(t *testing.T) {
+	pool := pgbase.CreateAndMigrateTestDatabase(t)
+
+	ur := pguser.NewRepository(pool)
+	us := user.NewService(ur, user.FakePasswordHasher(t))
+
+	fake := faker.New()
+	u := user.FakeUser(t, &fake)
+	if err := us.Add(t.Context(), u); err != nil {
+		t.Fatalf("failed to create user: %q", err)
+	}
+
+	testUser, err := us.ByNickName(t.Context(), u.NickName)
+	if err != nil {
+		t.Fatalf("failed to retrieve user: %q", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	r := pgwebhook.NewRepository(pool)
+	s := webhook.NewService(r, webhook.NewClient(server.Client(), "sparklemuffin/test"), nil)
+
+	w, err := s.Add(t.Context(), webhook.Webhook{
+		UserUUID: testUser.UUID,
+		URL:      server.URL,
+		Events:   []webhook.Event{webhook.EventBookmarkAdded, webhook.EventFeedEntryCreated},
+	})
+	if err != nil {
+		t.Fatalf("failed to add webhook: %q", err)
+	}
+
+	t.Run("get by UUID", func(t *testing.T) {
+		got, err := r.WebhookGetByUUID(t.Context(), testUser.UUID, w.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve webhook: %q", err)
+		}
+
+		if got.URL != w.URL {
+			t.Errorf("want URL %q, got %q", w.URL, got.URL)
+		}
+		if got.Secret != w.Secret {
+			t.Errorf("want secret %q, got %q", w.Secret, got.Secret)
+		}
+		if len(got.Events) != 2 {
+			t.Errorf("want 2 events, got %v", got.Events)
+		}
+		if got.FeedCategoryUUID != "" || got.FeedSubscriptionUUID != "" {
+			t.Errorf("want no filters, got %q and %q", got.FeedCategoryUUID, got.FeedSubscriptionUUID)
+		}
+	})
+
+	t.Run("get many by event", func(t *testing.T) {
+		got, err := r.WebhookGetManyByUserAndEvent(t.Context(), testUser.UUID, webhook.EventBookmarkAdded)
+		if err != nil {
+			t.Fatalf("failed to retrieve webhooks: %q", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("want 1 webhook, got %d", len(got))
+		}
+
+		got, err = r.WebhookGetManyByUserAndEvent(t.Context(), testUser.UUID, webhook.EventBookmarkDeleted)
+		if err != nil {
+			t.Fatalf("failed to retrieve webhooks: %q", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("want no webhook, got %d", len(got))
+		}
+	})
+
+	t.Run("queue and deliver", func(t *testing.T) {
+		b := bookmark.Bookmark{
+			UID:      "2JZ3R0D9Z4rXDPEQcSPhPJ2ebSA",
+			UserUUID: testUser.UUID,
+			URL:      "https://example.com",
+			Title:    "Example",
+		}
+
+		if err := s.NotifyBookmark(t.Context(), webhook.EventBookmarkAdded, b); err != nil {
+			t.Fatalf("failed to queue delivery: %q", err)
+		}
+
+		if err := s.Dispatch(t.Context(), "test-job"); err != nil {
+			t.Fatalf("failed to dispatch deliveries: %q", err)
+		}
+
+		deliveries, err := s.Deliveries(t.Context(), testUser.UUID, w.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve deliveries: %q", err)
+		}
+		if len(deliveries) != 1 {
+			t.Fatalf("want 1 delivery, got %d", len(deliveries))
+		}
+		if deliveries[0].Status != webhook.DeliveryStatusSucceeded {
+			t.Errorf("want status %q, got %q", webhook.DeliveryStatusSucceeded, deliveries[0].Status)
+		}
+		if deliveries[0].ResponseStatusCode != http.StatusOK {
+			t.Errorf("want response status code %d, got %d", http.StatusOK, deliveries[0].ResponseStatusCode)
+		}
+	})
+
+	t.Run("queue feed entries once", func(t *testing.T) {
+		now := time.Now().UTC()
+		fr := pgfeed.NewRepository(pool)
+
+		f := feed.Feed{
+			UUID:      fake.UUID().V4(),
+			FeedURL:   "https://example.org/feed.xml",
+			Title:     "Example",
+			Slug:      "example",
+			CreatedAt: now,
+			UpdatedAt: now,
+			FetchedAt: now,
+		}
+		if err := fr.FeedCreate(t.Context(), f); err != nil {
+			t.Fatalf("failed to create feed: %q", err)
+		}
+
+		category := feed.Category{
+			UUID:      fake.UUID().V4(),
+			UserUUID:  testUser.UUID,
+			Name:      "News",
+			Slug:      "news",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := fr.FeedCategoryCreate(t.Context(), category); err != nil {
+			t.Fatalf("failed to create category: %q", err)
+		}
+
+		subscription := feed.Subscription{
+			UUID:         fake.UUID().V4(),
+			CategoryUUID: category.UUID,
+			FeedUUID:     f.UUID,
+			UserUUID:     testUser.UUID,
+			CreatedAt:    now.Add(-1 * time.Hour),
+			UpdatedAt:    now.Add(-1 * time.Hour),
+		}
+		if _, err := fr.FeedSubscriptionCreate(t.Context(), subscription, 0); err != nil {
+			t.Fatalf("failed to create subscription: %q", err)
+		}
+
+		entries := []feed.Entry{
+			{
+				UID:         "2JZ3R0D9Z4rXDPEQcSPhPJ2ebSB",
+				FeedUUID:    f.UUID,
+				URL:         "https://example.org/entry",
+				Title:       "Entry",
+				PublishedAt: now,
+				UpdatedAt:   now,
+			},
+		}
+		if _, err := fr.FeedEntryCreateMany(t.Context(), entries); err != nil {
+			t.Fatalf("failed to create entries: %q", err)
+		}
+
+		for range 2 {
+			if err := s.Dispatch(t.Context(), "test-job"); err != nil {
+				t.Fatalf("failed to dispatch deliveries: %q", err)
+			}
+		}
+
+		deliveries, err := s.Deliveries(t.Context(), testUser.UUID, w.UUID)
+		if err != nil {
+			t.Fatalf("failed to retrieve deliveries: %q", err)
+		}
+
+		var feedEntryDeliveries int
+		for _, d := range deliveries {
+			if d.Event == webhook.EventFeedEntryCreated {
+				feedEntryDeliveries++
+			}
+		}
+		if feedEntryDeliveries != 1 {
+			t.Errorf("want 1 feed entry delivery, got %d", feedEntryDeliveries)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := s.Delete(t.Context(), testUser.UUID, w.UUID); err != nil {
+			t.Fatalf("failed to delete webhook: %q", err)
+		}
+
+		_, err := r.WebhookGetByUUID(t.Context(), testUser.UUID, w.UUID)
+		if !errors.Is(err, webhook.ErrNotFound) {
+			t.Fatalf("want %q, got %q", webhook.ErrNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package ssotest provides a mock OpenID Connect identity provider for tests involving single sign-on.
+package ssotest
@@ -0,0 +1,208 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package ssotest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+)
+
+const (
+	keyID = "ssotest"
+)
+
+type authorization struct {
+	claims        map[string]any
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// Provider is a mock OpenID Connect identity provider, serving discovery, signing keys
+// and a token endpoint enforcing PKCE.
+type Provider struct {
+	ClientID     string
+	ClientSecret string
+
+	server     *httptest.Server
+	privateKey *rsa.PrivateKey
+
+	mu             sync.Mutex
+	authorizations map[string]authorization
+}
+
+// NewProvider starts a Provider accepting a given OAuth2 client, which is stopped when
+// the test completes.
+func NewProvider(t *testing.T, clientID string, clientSecret string) *Provider {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %q", err)
+	}
+
+	p := &Provider{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		privateKey:     privateKey,
+		authorizations: map[string]authorization{},
+	}
+
+	oidcServer := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{
+			{
+				PublicKey: privateKey.Public(),
+				KeyID:     keyID,
+				Algorithm: oidc.RS256,
+			},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", oidcServer)
+	mux.HandleFunc("POST /token", p.handleToken)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	oidcServer.SetIssuer(p.server.URL)
+
+	return p
+}
+
+// IssuerURL returns the URL of the Provider, used for OpenID Connect discovery.
+func (p *Provider) IssuerURL() string {
+	return p.server.URL
+}
+
+// Authorize simulates a user logging in with the Provider, following the authorization
+// URL built by the Relying Party.
+//
+// It returns the callback URL the user is redirected to, carrying an authorization code that
+// can be exchanged for an ID token with the given claims.
+func (p *Provider) Authorize(t *testing.T, authCodeURL string, claims map[string]any) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(authCodeURL)
+	if err != nil {
+		t.Fatalf("failed to parse authorization URL: %q", err)
+	}
+
+	query := u.Query()
+
+	if got := query.Get("client_id"); got != p.ClientID {
+		t.Fatalf("want client ID %q, got %q", p.ClientID, got)
+	}
+	if got := query.Get("response_type"); got != "code" {
+		t.Fatalf("want response type %q, got %q", "code", got)
+	}
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("want code challenge method %q, got %q", "S256", got)
+	}
+
+	code := rand.Text()
+
+	p.mu.Lock()
+	p.authorizations[code] = authorization{
+		claims:        claims,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	p.mu.Unlock()
+
+	callbackURL, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		t.Fatalf("failed to parse redirect URI: %q", err)
+	}
+
+	callbackQuery := callbackURL.Query()
+	callbackQuery.Set("code", code)
+	callbackQuery.Set("state", query.Get("state"))
+	callbackURL.RawQuery = callbackQuery.Encode()
+
+	return callbackURL
+}
+
+// handleToken exchanges an authorization code for an ID token.
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	if clientID != p.ClientID || clientSecret != p.ClientSecret {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	p.mu.Lock()
+	auth, ok := p.authorizations[code]
+	delete(p.authorizations, code)
+	p.mu.Unlock()
+
+	if !ok || r.PostForm.Get("redirect_uri") != auth.redirectURI {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifierHash[:]) != auth.codeChallenge {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+
+	claims := map[string]any{
+		"iss":   p.server.URL,
+		"aud":   p.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": auth.nonce,
+	}
+	maps.Copy(claims, auth.claims)
+
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(p.privateKey, keyID, oidc.RS256, string(rawClaims)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
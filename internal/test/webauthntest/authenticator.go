@@ -0,0 +1,277 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagAttestedCredData byte = 0x40
+
+	credentialIDNBytes = 32
+)
+
+// creationOptions holds the parts of the JSON-encoded options passed to navigator.credentials.create()
+// that are used by the Authenticator.
+type creationOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+// requestOptions holds the parts of the JSON-encoded options passed to navigator.credentials.get()
+// that are used by the Authenticator.
+type requestOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+	} `json:"publicKey"`
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	privateKey *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// Authenticator is a software WebAuthn authenticator, which creates discoverable ES256
+// credentials with "none" attestation, and always verifies the user.
+type Authenticator struct {
+	origin      string
+	credentials []*credential
+}
+
+// NewAuthenticator returns an Authenticator, acting as a Web browser visiting origin.
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{
+		origin: origin,
+	}
+}
+
+// Clone returns a copy of the Authenticator, sharing the same private keys but with
+// independent signature counters.
+func (a *Authenticator) Clone() *Authenticator {
+	clone := &Authenticator{
+		origin:      a.origin,
+		credentials: make([]*credential, len(a.credentials)),
+	}
+
+	for i, c := range a.credentials {
+		credentialCopy := *c
+		clone.credentials[i] = &credentialCopy
+	}
+
+	return clone
+}
+
+// Create performs a registration ceremony from the JSON-encoded options returned by
+// the Relying Party, and returns the JSON-encoded response to send back.
+func (a *Authenticator) Create(t *testing.T, optionsJSON []byte) []byte {
+	t.Helper()
+
+	var options creationOptions
+	if err := json.Unmarshal(optionsJSON, &options); err != nil {
+		t.Fatalf("failed to decode creation options: %q", err)
+	}
+
+	userHandle := mustDecodeBase64URL(t, options.PublicKey.User.ID)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %q", err)
+	}
+
+	credentialID := make([]byte, credentialIDNBytes)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatalf("failed to generate credential ID: %q", err)
+	}
+
+	c := &credential{
+		id:         credentialID,
+		rpID:       options.PublicKey.RP.ID,
+		userHandle: userHandle,
+		privateKey: privateKey,
+	}
+	a.credentials = append(a.credentials, c)
+
+	clientDataJSON := a.clientDataJSON(t, protocol.CreateCeremony, options.PublicKey.Challenge)
+
+	// Uncompressed point encoding: 0x04 || X || Y
+	publicKeyBytes, err := privateKey.PublicKey.Bytes()
+	if err != nil {
+		t.Fatalf("failed to encode public key: %q", err)
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: publicKeyBytes[1:33],
+		YCoord: publicKeyBytes[33:],
+	})
+	if err != nil {
+		t.Fatalf("failed to encode public key: %q", err)
+	}
+
+	// Attested credential data: AAGUID (zeroed), credential ID length and value, public key
+	attestedCredentialData := make([]byte, 16, 16+2+len(credentialID)+len(publicKey))
+	attestedCredentialData = binary.BigEndian.AppendUint16(attestedCredentialData, uint16(len(credentialID)))
+	attestedCredentialData = append(attestedCredentialData, credentialID...)
+	attestedCredentialData = append(attestedCredentialData, publicKey...)
+
+	authData := c.authenticatorData(flagUserPresent|flagUserVerified|flagAttestedCredData, attestedCredentialData)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode attestation object: %q", err)
+	}
+
+	response := protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   base64.RawURLEncoding.EncodeToString(credentialID),
+				Type: string(protocol.PublicKeyCredentialType),
+			},
+			RawID: credentialID,
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: clientDataJSON,
+			},
+			AttestationObject: attestationObject,
+		},
+	}
+
+	return mustMarshalJSON(t, response)
+}
+
+// Get performs a login ceremony from the JSON-encoded options returned by the Relying Party,
+// using the most recently created credential for this Relying Party, and returns the JSON-encoded
+// response to send back.
+func (a *Authenticator) Get(t *testing.T, optionsJSON []byte) []byte {
+	t.Helper()
+
+	var options requestOptions
+	if err := json.Unmarshal(optionsJSON, &options); err != nil {
+		t.Fatalf("failed to decode request options: %q", err)
+	}
+
+	var c *credential
+	for _, candidate := range a.credentials {
+		if candidate.rpID == options.PublicKey.RPID {
+			c = candidate
+		}
+	}
+	if c == nil {
+		t.Fatalf("no credential for relying party %q", options.PublicKey.RPID)
+	}
+
+	c.signCount++
+
+	clientDataJSON := a.clientDataJSON(t, protocol.AssertCeremony, options.PublicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	authData := c.authenticatorData(flagUserPresent|flagUserVerified, nil)
+
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, c.privateKey, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign assertion: %q", err)
+	}
+
+	response := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   base64.RawURLEncoding.EncodeToString(c.id),
+				Type: string(protocol.PublicKeyCredentialType),
+			},
+			RawID: c.id,
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{
+				ClientDataJSON: clientDataJSON,
+			},
+			AuthenticatorData: authData,
+			Signature:         signature,
+			UserHandle:        c.userHandle,
+		},
+	}
+
+	return mustMarshalJSON(t, response)
+}
+
+func (a *Authenticator) clientDataJSON(t *testing.T, ceremonyType protocol.CeremonyType, challenge string) []byte {
+	t.Helper()
+
+	return mustMarshalJSON(t, protocol.CollectedClientData{
+		Type:      ceremonyType,
+		Challenge: challenge,
+		Origin:    a.origin,
+	})
+}
+
+// authenticatorData returns the authenticator data for this credential: RP ID hash, flags,
+// signature counter and optional attested credential data.
+func (c *credential) authenticatorData(flags byte, attestedCredentialData []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+
+	authData := make([]byte, 0, 32+1+4+len(attestedCredentialData))
+	authData = append(authData, rpIDHash[:]...)
+	authData = append(authData, flags)
+	authData = binary.BigEndian.AppendUint32(authData, c.signCount)
+	authData = append(authData, attestedCredentialData...)
+
+	return authData
+}
+
+func mustDecodeBase64URL(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("failed to decode base64url string: %q", err)
+	}
+
+	return b
+}
+
+func mustMarshalJSON(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode JSON: %q", err)
+	}
+
+	return b
+}
@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package webauthntest provides a software WebAuthn authenticator for tests involving passkeys.
+package webauthntest
@@ -0,0 +1,211 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package urlkit
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+const (
+	ampPathSuffix = "/amp"
+
+	// textFragmentDirective delimits the part of a URL fragment that is reserved for
+	// browser directives, e.g. text fragments (#:~:text=...).
+	textFragmentDirective = ":~:"
+)
+
+// Canonicalize returns the canonical form of an HTTP(S) URL, so that URLs pointing to the
+// same resource can be compared:
+//
+//   - the scheme and host are lower-cased, and the default port is removed;
+//   - AMP cache and viewer URLs are replaced with the URL of the original page, without its
+//     "/amp" path suffix and "amp" query parameter;
+//   - trailing slashes are removed from the path;
+//   - tracking and AMP query parameters are removed; other parameters are kept in order;
+//   - empty fragments and text fragment directives are removed.
+//
+// Canonicalize is idempotent. URLs that cannot be parsed, or that do not use the HTTP(S)
+// scheme, are returned with surrounding whitespace trimmed and are otherwise unchanged.
+func Canonicalize(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[u.Scheme]; !ok || u.Host == "" {
+		return rawURL
+	}
+
+	// The "/amp" path suffix and "amp" query parameter are commonly used by publishers to
+	// serve AMP pages, but are ordinary path segments and parameters on other sites: they are
+	// only removed from the pages served through an AMP cache or viewer.
+	var servedByAMP bool
+
+	for {
+		originURL, ok := unwrapAMPURL(u)
+		if !ok {
+			break
+		}
+
+		u = originURL
+		servedByAMP = true
+	}
+
+	canonicalizeHost(u)
+	canonicalizePath(u, servedByAMP)
+	canonicalizeQuery(u, servedByAMP)
+	canonicalizeFragment(u)
+
+	return u.String()
+}
+
+// unwrapAMPURL returns the URL of the original page served by an AMP cache or viewer:
+//
+//	https://www-example-org.cdn.ampproject.org/c/s/www.example.org/article
+//	https://www.google.com/amp/s/www.example.org/article
+func unwrapAMPURL(u *url.URL) (*url.URL, bool) {
+	hostname := strings.ToLower(u.Hostname())
+
+	var prefix string
+
+	switch {
+	case strings.HasSuffix(hostname, ".cdn.ampproject.org"):
+		for _, contentType := range []string{"/c/", "/v/"} {
+			if strings.HasPrefix(u.Path, contentType) {
+				prefix = contentType
+				break
+			}
+		}
+
+	case hostname == "google.com" || hostname == "www.google.com":
+		if strings.HasPrefix(u.Path, "/amp/") {
+			prefix = "/amp/"
+		}
+	}
+
+	if prefix == "" {
+		return nil, false
+	}
+
+	scheme := "http"
+	originPath := strings.TrimPrefix(u.EscapedPath(), prefix)
+
+	if rest, ok := strings.CutPrefix(originPath, "s/"); ok {
+		scheme = "https"
+		originPath = rest
+	}
+
+	originURL, err := url.Parse(scheme + "://" + originPath)
+	if err != nil || originURL.Host == "" {
+		return nil, false
+	}
+
+	originURL.RawQuery = u.RawQuery
+	originURL.Fragment = u.Fragment
+	originURL.RawFragment = u.RawFragment
+
+	return originURL, true
+}
+
+func canonicalizeHost(u *url.URL) {
+	hostname := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(hostname, port)
+	case strings.Contains(hostname, ":"):
+		// IPv6 address
+		u.Host = "[" + hostname + "]"
+	default:
+		u.Host = hostname
+	}
+}
+
+func canonicalizePath(u *url.URL, servedByAMP bool) {
+	escapedPath := strings.TrimRight(u.EscapedPath(), "/")
+
+	if servedByAMP && strings.HasSuffix(escapedPath, ampPathSuffix) && len(escapedPath) > len(ampPathSuffix) {
+		escapedPath = strings.TrimRight(strings.TrimSuffix(escapedPath, ampPathSuffix), "/")
+	}
+
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return
+	}
+
+	u.Path = path
+	u.RawPath = escapedPath
+}
+
+func canonicalizeQuery(u *url.URL, servedByAMP bool) {
+	u.ForceQuery = false
+
+	if u.RawQuery == "" {
+		return
+	}
+
+	var kept []string
+
+	for parameter := range strings.SplitSeq(u.RawQuery, "&") {
+		if parameter == "" {
+			continue
+		}
+
+		if isStrippedQueryParameter(parameter, servedByAMP) {
+			continue
+		}
+
+		kept = append(kept, parameter)
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+}
+
+func isStrippedQueryParameter(parameter string, servedByAMP bool) bool {
+	rawName, rawValue, _ := strings.Cut(parameter, "=")
+
+	name, err := url.QueryUnescape(rawName)
+	if err != nil {
+		return false
+	}
+
+	value, err := url.QueryUnescape(rawValue)
+	if err != nil {
+		return false
+	}
+
+	for _, rule := range strippedQueryParameters {
+		if rule.AMPOnly && !servedByAMP {
+			continue
+		}
+
+		if rule.matches(name, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func canonicalizeFragment(u *url.URL) {
+	fragment, _, _ := strings.Cut(u.EscapedFragment(), textFragmentDirective)
+
+	unescapedFragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return
+	}
+
+	u.Fragment = unescapedFragment
+	u.RawFragment = fragment
+}
@@ -0,0 +1,242 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package urlkit_test
+
+import (
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/urlkit"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct {
+		tname  string
+		rawURL string
+		want   string
+	}{
+		// unchanged
+		{
+			tname: "empty string",
+		},
+		{
+			tname:  "canonical URL",
+			rawURL: "https://example.org/articles/42?page=2#comments",
+			want:   "https://example.org/articles/42?page=2#comments",
+		},
+		{
+			tname:  "whitespace",
+			rawURL: "  https://example.org/articles/42 ",
+			want:   "https://example.org/articles/42",
+		},
+		{
+			tname:  "not a URL",
+			rawURL: " example.org/articles/42 ",
+			want:   "example.org/articles/42",
+		},
+		{
+			tname:  "unsupported scheme",
+			rawURL: "ftp://EXAMPLE.org/pub/",
+			want:   "ftp://EXAMPLE.org/pub/",
+		},
+		{
+			tname:  "invalid URL",
+			rawURL: "https://example.org/%zz",
+			want:   "https://example.org/%zz",
+		},
+		{
+			tname:  "escaped path",
+			rawURL: "https://example.org/a%2Fb/c%20d",
+			want:   "https://example.org/a%2Fb/c%20d",
+		},
+
+		// scheme and host
+		{
+			tname:  "scheme and host case",
+			rawURL: "HTTPS://WWW.Example.ORG/Articles",
+			want:   "https://www.example.org/Articles",
+		},
+		{
+			tname:  "fully qualified domain name",
+			rawURL: "https://example.org./articles",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "HTTP default port",
+			rawURL: "http://example.org:80/articles",
+			want:   "http://example.org/articles",
+		},
+		{
+			tname:  "HTTPS default port",
+			rawURL: "https://example.org:443/articles",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "other port",
+			rawURL: "https://example.org:8443/articles",
+			want:   "https://example.org:8443/articles",
+		},
+		{
+			tname:  "IPv6 address with default port",
+			rawURL: "http://[2001:DB8::1]:80/articles",
+			want:   "http://[2001:db8::1]/articles",
+		},
+
+		// path
+		{
+			tname:  "trailing slash",
+			rawURL: "https://example.org/articles/",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "trailing slashes",
+			rawURL: "https://example.org/articles//",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "root path",
+			rawURL: "https://example.org/",
+			want:   "https://example.org",
+		},
+		{
+			tname:  "amp path segment",
+			rawURL: "https://example.org/articles/42/amp/",
+			want:   "https://example.org/articles/42/amp",
+		},
+		{
+			tname:  "amp path suffix",
+			rawURL: "https://example.org/guitars/amp",
+			want:   "https://example.org/guitars/amp",
+		},
+		{
+			tname:  "repeated amp path segments",
+			rawURL: "https://example.org/a/amp/amp",
+			want:   "https://example.org/a/amp/amp",
+		},
+		{
+			tname:  "AMP root path",
+			rawURL: "https://example.org/amp",
+			want:   "https://example.org/amp",
+		},
+		{
+			tname:  "AMP path segment",
+			rawURL: "https://example.org/amp/articles/42",
+			want:   "https://example.org/amp/articles/42",
+		},
+
+		// query
+		{
+			tname:  "UTM parameters",
+			rawURL: "https://example.org/articles?utm_source=newsletter&utm_medium=email&UTM_Campaign=launch",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "click identifiers",
+			rawURL: "https://example.org/articles?fbclid=abc&gclid=def&msclkid=ghi",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "Mailchimp parameters",
+			rawURL: "https://example.org/articles?mc_cid=123&mc_eid=456",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "tracking and content parameters",
+			rawURL: "https://example.org/search?q=go+modules&utm_source=rss&page=2&fbclid=abc",
+			want:   "https://example.org/search?q=go+modules&page=2",
+		},
+		{
+			tname:  "escaped parameter name",
+			rawURL: "https://example.org/articles?utm%5Fsource=rss&id=1",
+			want:   "https://example.org/articles?id=1",
+		},
+		{
+			tname:  "parameter similar to a tracking parameter",
+			rawURL: "https://example.org/articles?gclid_x=1&xutm_source=2",
+			want:   "https://example.org/articles?gclid_x=1&xutm_source=2",
+		},
+		{
+			tname:  "AMP parameters",
+			rawURL: "https://example.org/articles/42?amp=1&outputType=AMP",
+			want:   "https://example.org/articles/42?amp=1",
+		},
+		{
+			tname:  "outputType parameter with another value",
+			rawURL: "https://example.org/articles/42?outputType=print",
+			want:   "https://example.org/articles/42?outputType=print",
+		},
+		{
+			tname:  "empty query",
+			rawURL: "https://example.org/articles?",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "empty parameters",
+			rawURL: "https://example.org/articles?&id=1&&",
+			want:   "https://example.org/articles?id=1",
+		},
+
+		// fragment
+		{
+			tname:  "empty fragment",
+			rawURL: "https://example.org/articles#",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "text fragment",
+			rawURL: "https://example.org/articles#:~:text=canonical",
+			want:   "https://example.org/articles",
+		},
+		{
+			tname:  "anchor and text fragment",
+			rawURL: "https://example.org/articles#usage:~:text=canonical",
+			want:   "https://example.org/articles#usage",
+		},
+
+		// AMP caches and viewers
+		{
+			tname:  "AMP cache, HTTPS origin",
+			rawURL: "https://www-example-org.cdn.ampproject.org/c/s/www.example.org/articles/42/amp?utm_source=amp",
+			want:   "https://www.example.org/articles/42",
+		},
+		{
+			tname:  "AMP cache, amp parameter",
+			rawURL: "https://www-example-org.cdn.ampproject.org/c/s/www.example.org/articles/42/?amp=1&page=2",
+			want:   "https://www.example.org/articles/42?page=2",
+		},
+		{
+			tname:  "AMP cache, HTTP origin",
+			rawURL: "https://example-org.cdn.ampproject.org/v/example.org/articles/42",
+			want:   "http://example.org/articles/42",
+		},
+		{
+			tname:  "Google AMP viewer",
+			rawURL: "https://www.google.com/amp/s/www.example.org/articles/42.amp.html",
+			want:   "https://www.example.org/articles/42.amp.html",
+		},
+		{
+			tname:  "Google AMP viewer, amp path suffix",
+			rawURL: "https://www.google.com/amp/s/www.example.org/articles/42/amp/",
+			want:   "https://www.example.org/articles/42",
+		},
+		{
+			tname:  "Google page",
+			rawURL: "https://www.google.com/search?q=amp",
+			want:   "https://www.google.com/search?q=amp",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := urlkit.Canonicalize(tc.rawURL)
+
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+
+			if again := urlkit.Canonicalize(got); again != got {
+				t.Errorf("want canonicalization to be idempotent, got %q then %q", got, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package urlkit provides helpers to normalize and canonicalize URLs.
+package urlkit
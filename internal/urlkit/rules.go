@@ -0,0 +1,88 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package urlkit
+
+import "strings"
+
+// queryParameterRule matches query parameters that are stripped from URLs.
+type queryParameterRule struct {
+	// Name is the parameter name, compared case-insensitively; a trailing '*' matches
+	// all parameters starting with the given prefix.
+	Name string
+
+	// Value restricts the rule to parameters with a given value, compared
+	// case-insensitively; the rule matches any value if it is empty.
+	Value string
+
+	// AMPOnly restricts the rule to pages served through an AMP cache or viewer, for
+	// parameters that are too generic to be removed from other URLs.
+	AMPOnly bool
+}
+
+func (r queryParameterRule) matches(name string, value string) bool {
+	name = strings.ToLower(name)
+
+	if prefix, ok := strings.CutSuffix(r.Name, "*"); ok {
+		if !strings.HasPrefix(name, prefix) {
+			return false
+		}
+	} else if name != r.Name {
+		return false
+	}
+
+	return r.Value == "" || strings.EqualFold(value, r.Value)
+}
+
+// strippedQueryParameters lists the tracking and AMP query parameters removed from URLs.
+//
+// Only add parameters that never affect the content of the target page.
+var strippedQueryParameters = []queryParameterRule{
+	// Google Analytics (Urchin Tracking Module)
+	{Name: "utm_*"},
+
+	// Matomo (formerly Piwik)
+	{Name: "mtm_*"},
+	{Name: "pk_campaign"},
+	{Name: "pk_content"},
+	{Name: "pk_keyword"},
+	{Name: "pk_kwd"},
+	{Name: "pk_medium"},
+	{Name: "pk_source"},
+
+	// Advertising click identifiers
+	{Name: "dclid"},     // Google Display & Video
+	{Name: "fbclid"},    // Facebook
+	{Name: "gbraid"},    // Google Ads (iOS)
+	{Name: "gclid"},     // Google Ads
+	{Name: "gclsrc"},    // Google Ads
+	{Name: "li_fat_id"}, // LinkedIn
+	{Name: "msclkid"},   // Microsoft Advertising
+	{Name: "ttclid"},    // TikTok
+	{Name: "twclid"},    // Twitter
+	{Name: "wbraid"},    // Google Ads (iOS)
+	{Name: "yclid"},     // Yandex
+
+	// Email marketing
+	{Name: "_hsenc"},    // HubSpot
+	{Name: "_hsmi"},     // HubSpot
+	{Name: "mc_cid"},    // Mailchimp
+	{Name: "mc_eid"},    // Mailchimp
+	{Name: "mkt_tok"},   // Marketo
+	{Name: "vero_conv"}, // Vero
+	{Name: "vero_id"},   // Vero
+
+	// Social networks
+	{Name: "igsh"},   // Instagram
+	{Name: "igshid"}, // Instagram
+
+	// Accelerated Mobile Pages (AMP) variants
+	{Name: "amp", AMPOnly: true},
+	{Name: "outputtype", Value: "amp"},
+}
+
+// defaultPorts maps URL schemes to their default port, which is stripped from URLs.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
@@ -0,0 +1,12 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import "errors"
+
+var (
+	ErrDateRangeInvalid = errors.New("audit: the end date must not be before the start date")
+	ErrTypeInvalid      = errors.New("audit: invalid event type")
+	ErrTypeRequired     = errors.New("audit: event type required")
+)
@@ -0,0 +1,197 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"slices"
+	"time"
+)
+
+// EventType identifies a kind of security-relevant Event.
+type EventType string
+
+const (
+	EventLoginSucceeded   EventType = "login.succeeded"
+	EventLoginFailed      EventType = "login.failed"
+	EventLoginRateLimited EventType = "login.rate_limited"
+	EventLoginThrottled   EventType = "login.throttled"
+	EventAccountLocked    EventType = "login.account_locked"
+
+	EventAccountInfoUpdated     EventType = "account.info_updated"
+	EventAccountPasswordUpdated EventType = "account.password_updated"
+	EventPasswordResetRequested EventType = "account.password_reset_requested"
+	EventPasswordResetCompleted EventType = "account.password_reset_completed"
+	EventPasskeyRegistered      EventType = "account.passkey_registered"
+	EventPasskeyDeleted         EventType = "account.passkey_deleted"
+	EventTwoFactorEnabled       EventType = "account.two_factor_enabled"
+	EventTwoFactorDisabled      EventType = "account.two_factor_disabled"
+	EventSessionRevoked         EventType = "account.session_revoked"
+	EventAccountRegistered      EventType = "account.registered"
+	EventInvitationCreated      EventType = "account.invitation_created"
+	EventInvitationDeleted      EventType = "account.invitation_deleted"
+	EventAccountDataExported    EventType = "account.data_exported"
+	EventAccountDeleted         EventType = "account.deleted"
+
+	EventAdminUserCreated    EventType = "admin.user_created"
+	EventAdminUserUpdated    EventType = "admin.user_updated"
+	EventAdminUserDeleted    EventType = "admin.user_deleted"
+	EventAdminTwoFactorReset EventType = "admin.two_factor_reset"
+	EventAdminUserUnlocked   EventType = "admin.user_unlocked"
+	EventAdminQuotaUpdated   EventType = "admin.quota_updated"
+	EventAdminFeedURLUpdated EventType = "admin.feed_url_updated"
+	EventAdminFeedMerged     EventType = "admin.feed_merged"
+	EventAdminFeedDeleted    EventType = "admin.feed_deleted"
+	EventBookmarksExported   EventType = "bookmarks.exported"
+	EventBookmarksImported   EventType = "bookmarks.imported"
+	EventFeedsExported       EventType = "feeds.exported"
+	EventFeedsImported       EventType = "feeds.imported"
+)
+
+// EventTypes lists all known event types, in display order.
+var EventTypes = []EventType{
+	EventLoginSucceeded,
+	EventLoginFailed,
+	EventLoginRateLimited,
+	EventLoginThrottled,
+	EventAccountLocked,
+	EventAccountInfoUpdated,
+	EventAccountPasswordUpdated,
+	EventPasswordResetRequested,
+	EventPasswordResetCompleted,
+	EventPasskeyRegistered,
+	EventPasskeyDeleted,
+	EventTwoFactorEnabled,
+	EventTwoFactorDisabled,
+	EventSessionRevoked,
+	EventAccountRegistered,
+	EventInvitationCreated,
+	EventInvitationDeleted,
+	EventAccountDataExported,
+	EventAccountDeleted,
+	EventAdminUserCreated,
+	EventAdminUserUpdated,
+	EventAdminUserDeleted,
+	EventAdminTwoFactorReset,
+	EventAdminUserUnlocked,
+	EventAdminQuotaUpdated,
+	EventAdminFeedURLUpdated,
+	EventAdminFeedMerged,
+	EventAdminFeedDeleted,
+	EventBookmarksExported,
+	EventBookmarksImported,
+	EventFeedsExported,
+	EventFeedsImported,
+}
+
+var eventTypeLabels = map[EventType]string{
+	EventLoginSucceeded:         "Login succeeded",
+	EventLoginFailed:            "Login failed",
+	EventLoginRateLimited:       "Login rate limit exceeded",
+	EventLoginThrottled:         "Login throttled",
+	EventAccountLocked:          "Account locked",
+	EventAccountInfoUpdated:     "Account information updated",
+	EventAccountPasswordUpdated: "Password updated",
+	EventPasswordResetRequested: "Password reset requested",
+	EventPasswordResetCompleted: "Password reset completed",
+	EventPasskeyRegistered:      "Passkey registered",
+	EventPasskeyDeleted:         "Passkey deleted",
+	EventTwoFactorEnabled:       "Two-factor authentication enabled",
+	EventTwoFactorDisabled:      "Two-factor authentication disabled",
+	EventSessionRevoked:         "Session revoked",
+	EventAccountRegistered:      "Account registered",
+	EventInvitationCreated:      "Invitation created",
+	EventInvitationDeleted:      "Invitation deleted",
+	EventAccountDataExported:    "Personal data exported",
+	EventAccountDeleted:         "Account deleted",
+	EventAdminUserCreated:       "User created",
+	EventAdminUserUpdated:       "User updated",
+	EventAdminUserDeleted:       "User deleted",
+	EventAdminTwoFactorReset:    "Two-factor authentication reset",
+	EventAdminUserUnlocked:      "User unlocked",
+	EventAdminQuotaUpdated:      "Storage quota updated",
+	EventAdminFeedURLUpdated:    "Feed URL updated",
+	EventAdminFeedMerged:        "Feeds merged",
+	EventAdminFeedDeleted:       "Feed deleted",
+	EventBookmarksExported:      "Bookmarks exported",
+	EventBookmarksImported:      "Bookmarks imported",
+	EventFeedsExported:          "Feed subscriptions exported",
+	EventFeedsImported:          "Feed subscriptions imported",
+}
+
+// Label returns a human-readable description of the EventType.
+func (t EventType) Label() string {
+	if label, ok := eventTypeLabels[t]; ok {
+		return label
+	}
+
+	return string(t)
+}
+
+// Valid returns whether the EventType is known.
+func (t EventType) Valid() bool {
+	return slices.Contains(EventTypes, t)
+}
+
+// Event represents a security-relevant action performed on the instance.
+//
+// Events are append-only: once recorded, they are never updated nor deleted, and are kept
+// when the users they relate to are deleted.
+type Event struct {
+	UUID string
+	Type EventType
+
+	// ActorUUID identifies the user who performed the action; it is empty for anonymous
+	// requests, e.g. failed login attempts for unknown accounts.
+	ActorUUID string
+
+	// TargetUUID identifies the user affected by the action, if any.
+	TargetUUID string
+
+	ClientIP  string
+	Details   string
+	CreatedAt time.Time
+
+	// ActorNickName and TargetNickName are populated when reading events, if the
+	// corresponding users still exist.
+	ActorNickName  string
+	TargetNickName string
+}
+
+// ValidateForAddition ensures mandatory fields are set.
+func (e *Event) ValidateForAddition() error {
+	if e.Type == "" {
+		return ErrTypeRequired
+	}
+	if !e.Type.Valid() {
+		return ErrTypeInvalid
+	}
+
+	return nil
+}
+
+// Filter restricts the set of Events returned by the Repository.
+//
+// Zero values are ignored.
+type Filter struct {
+	// UserUUID matches events where the user is either the actor or the target.
+	UserUUID string
+
+	Type EventType
+
+	// Since and Until bound the event creation date, inclusively.
+	Since time.Time
+	Until time.Time
+}
+
+// Validate ensures the Filter's date range is consistent.
+func (f *Filter) Validate() error {
+	if f.Type != "" && !f.Type.Valid() {
+		return ErrTypeInvalid
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return ErrDateRangeInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import "github.com/virtualtam/sparklemuffin/internal/paginate"
+
+// An EventPage holds a set of paginated audit Events.
+type EventPage struct {
+	paginate.Page
+
+	Events []Event
+}
+
+// NewEventPage initializes and returns a new EventPage.
+func NewEventPage(number uint, totalPages uint, eventCount uint, events []Event) EventPage {
+	return EventPage{
+		Page:   paginate.NewPage(number, totalPages, eventsPerPage, eventCount),
+		Events: events,
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import "context"
+
+// Repository provides access to the audit log.
+type Repository interface {
+	// EventAdd appends an Event to the audit log.
+	EventAdd(ctx context.Context, event Event) error
+
+	// EventGetCount returns the number of Events matching a given Filter.
+	EventGetCount(ctx context.Context, filter Filter) (uint, error)
+
+	// EventGetN returns at most n Events matching a given Filter, starting at a given offset,
+	// most recent first.
+	EventGetN(ctx context.Context, filter Filter, n uint, offset uint) ([]Event, error)
+}
@@ -0,0 +1,64 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+	"slices"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Events []Event
+}
+
+func (r *FakeRepository) EventAdd(_ context.Context, event Event) error {
+	r.Events = append(r.Events, event)
+	return nil
+}
+
+func (r *FakeRepository) EventGetCount(_ context.Context, filter Filter) (uint, error) {
+	return uint(len(r.filter(filter))), nil
+}
+
+func (r *FakeRepository) EventGetN(_ context.Context, filter Filter, n uint, offset uint) ([]Event, error) {
+	events := r.filter(filter)
+
+	if offset >= uint(len(events)) {
+		return []Event{}, nil
+	}
+
+	end := min(offset+n, uint(len(events)))
+
+	return events[offset:end], nil
+}
+
+func (r *FakeRepository) filter(filter Filter) []Event {
+	var events []Event
+
+	for _, e := range r.Events {
+		if filter.UserUUID != "" && e.ActorUUID != filter.UserUUID && e.TargetUUID != filter.UserUUID {
+			continue
+		}
+		if filter.Type != "" && e.Type != filter.Type {
+			continue
+		}
+		if !filter.Since.IsZero() && e.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && e.CreatedAt.After(filter.Until) {
+			continue
+		}
+
+		events = append(events, e)
+	}
+
+	slices.SortStableFunc(events, func(a, b Event) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return events
+}
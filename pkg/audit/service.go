@@ -0,0 +1,150 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+)
+
+const (
+	eventsPerPage   uint = 50
+	exportBatchSize uint = 500
+)
+
+var csvHeader = []string{
+	"created_at",
+	"type",
+	"actor_uuid",
+	"actor_nick_name",
+	"target_uuid",
+	"target_nick_name",
+	"client_ip",
+	"details",
+}
+
+// Service handles operations for the security audit log.
+type Service struct {
+	r Repository
+}
+
+// NewService initializes and returns an audit Service.
+func NewService(r Repository) *Service {
+	return &Service{
+		r: r,
+	}
+}
+
+// Record appends an Event to the audit log.
+func (s *Service) Record(ctx context.Context, event Event) error {
+	if err := event.ValidateForAddition(); err != nil {
+		return err
+	}
+
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	event.UUID = generatedUUID.String()
+	event.CreatedAt = time.Now().UTC()
+
+	return s.r.EventAdd(ctx, event)
+}
+
+// ByUserUUID returns a Page containing the Events where a given user is either the actor or
+// the target.
+func (s *Service) ByUserUUID(ctx context.Context, userUUID string, number uint) (EventPage, error) {
+	return s.ByPage(ctx, Filter{UserUUID: userUUID}, number)
+}
+
+// ByPage returns a Page containing a limited and offset number of Events matching a given Filter.
+func (s *Service) ByPage(ctx context.Context, filter Filter, number uint) (EventPage, error) {
+	if err := filter.Validate(); err != nil {
+		return EventPage{}, err
+	}
+
+	if number < 1 {
+		return EventPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	eventCount, err := s.r.EventGetCount(ctx, filter)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	totalPages := paginate.PageCount(eventCount, eventsPerPage)
+
+	if number > totalPages {
+		return EventPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	if eventCount == 0 {
+		// early return: nothing to display
+		return NewEventPage(1, 1, 0, []Event{}), nil
+	}
+
+	dbOffset := (number - 1) * eventsPerPage
+
+	events, err := s.r.EventGetN(ctx, filter, eventsPerPage, dbOffset)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	return NewEventPage(number, totalPages, eventCount, events), nil
+}
+
+// ExportCSV writes all Events matching a given Filter to w, as comma-separated values.
+//
+// Events are read in batches, so that large audit logs can be exported without being
+// loaded in memory.
+func (s *Service) ExportCSV(ctx context.Context, w io.Writer, filter Filter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for offset := uint(0); ; offset += exportBatchSize {
+		events, err := s.r.EventGetN(ctx, filter, exportBatchSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			record := []string{
+				e.CreatedAt.UTC().Format(time.RFC3339),
+				string(e.Type),
+				e.ActorUUID,
+				e.ActorNickName,
+				e.TargetUUID,
+				e.TargetNickName,
+				e.ClientIP,
+				e.Details,
+			}
+
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+
+		if uint(len(events)) < exportBatchSize {
+			break
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
@@ -0,0 +1,231 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+)
+
+const (
+	testActorUUID  = "0695b57a-1ab9-401d-b2db-a4430b7059ec"
+	testTargetUUID = "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c"
+)
+
+func TestServiceRecord(t *testing.T) {
+	cases := []struct {
+		tname   string
+		event   Event
+		wantErr error
+	}{
+		{
+			tname:   "empty event",
+			wantErr: ErrTypeRequired,
+		},
+		{
+			tname:   "unknown type",
+			event:   Event{Type: "login.teleported"},
+			wantErr: ErrTypeInvalid,
+		},
+		{
+			tname: "anonymous event",
+			event: Event{
+				Type:     EventLoginFailed,
+				ClientIP: "192.0.2.1",
+				Details:  "unknown@example.org",
+			},
+		},
+		{
+			tname: "admin event",
+			event: Event{
+				Type:       EventAdminUserDeleted,
+				ActorUUID:  testActorUUID,
+				TargetUUID: testTargetUUID,
+				ClientIP:   "192.0.2.1",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{}
+			s := NewService(r)
+
+			err := s.Record(t.Context(), tc.event)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %v", tc.wantErr, err)
+				}
+				if len(r.Events) != 0 {
+					t.Errorf("want no event recorded, got %d", len(r.Events))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if len(r.Events) != 1 {
+				t.Fatalf("want 1 event recorded, got %d", len(r.Events))
+			}
+
+			got := r.Events[0]
+			if got.UUID == "" {
+				t.Error("want a UUID to be generated")
+			}
+			if got.CreatedAt.IsZero() {
+				t.Error("want the creation date to be set")
+			}
+			if got.Type != tc.event.Type {
+				t.Errorf("want type %q, got %q", tc.event.Type, got.Type)
+			}
+		})
+	}
+}
+
+func TestServiceByPage(t *testing.T) {
+	now := time.Now().UTC()
+
+	var events []Event
+	for i := range 60 {
+		events = append(events, Event{
+			UUID:       fmt.Sprintf("event-%02d", i),
+			Type:       EventLoginSucceeded,
+			ActorUUID:  testActorUUID,
+			TargetUUID: testActorUUID,
+			CreatedAt:  now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	events = append(events, Event{
+		UUID:       "admin-event",
+		Type:       EventAdminUserUpdated,
+		ActorUUID:  testActorUUID,
+		TargetUUID: testTargetUUID,
+		CreatedAt:  now.Add(-90 * time.Minute),
+	})
+
+	s := NewService(&FakeRepository{Events: events})
+
+	t.Run("user history", func(t *testing.T) {
+		page, err := s.ByUserUUID(t.Context(), testTargetUUID, 1)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(page.Events) != 1 {
+			t.Fatalf("want 1 event, got %d", len(page.Events))
+		}
+		if page.Events[0].UUID != "admin-event" {
+			t.Errorf("want event %q, got %q", "admin-event", page.Events[0].UUID)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		page, err := s.ByPage(t.Context(), Filter{}, 2)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		paginate.AssertPageEquals(t, page.Page, paginate.Page{
+			PageNumber:         2,
+			PreviousPageNumber: 1,
+			NextPageNumber:     2,
+			TotalPages:         2,
+			PagesLeft:          0,
+			ItemCount:          61,
+			ItemOffset:         51,
+		})
+
+		if len(page.Events) != 11 {
+			t.Errorf("want 11 events, got %d", len(page.Events))
+		}
+	})
+
+	t.Run("filter by type and date", func(t *testing.T) {
+		filter := Filter{
+			Type:  EventLoginSucceeded,
+			Since: now.Add(-150 * time.Minute),
+			Until: now,
+		}
+
+		page, err := s.ByPage(t.Context(), filter, 1)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if page.ItemCount != 3 {
+			t.Errorf("want 3 events, got %d", page.ItemCount)
+		}
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		page, err := s.ByPage(t.Context(), Filter{Type: EventAdminUserDeleted}, 1)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(page.Events) != 0 {
+			t.Errorf("want no events, got %d", len(page.Events))
+		}
+	})
+
+	t.Run("page out of bounds", func(t *testing.T) {
+		_, err := s.ByPage(t.Context(), Filter{}, 3)
+		if !errors.Is(err, paginate.ErrPageNumberOutOfBounds) {
+			t.Errorf("want error %q, got %v", paginate.ErrPageNumberOutOfBounds, err)
+		}
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		_, err := s.ByPage(t.Context(), Filter{Since: now, Until: now.Add(-time.Hour)}, 1)
+		if !errors.Is(err, ErrDateRangeInvalid) {
+			t.Errorf("want error %q, got %v", ErrDateRangeInvalid, err)
+		}
+	})
+}
+
+func TestServiceExportCSV(t *testing.T) {
+	now := time.Now().UTC()
+
+	var events []Event
+	for i := range 1200 {
+		events = append(events, Event{
+			UUID:      fmt.Sprintf("event-%04d", i),
+			Type:      EventLoginFailed,
+			ClientIP:  "192.0.2.1",
+			Details:   "jane@example.org, \"quoted\"",
+			CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+
+	s := NewService(&FakeRepository{Events: events})
+
+	var buf bytes.Buffer
+	if err := s.ExportCSV(t.Context(), &buf, Filter{}); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV export: %q", err)
+	}
+
+	if len(records) != 1201 {
+		t.Fatalf("want 1201 records, got %d", len(records))
+	}
+	if records[0][0] != "created_at" {
+		t.Errorf("want a header row, got %v", records[0])
+	}
+	if got := records[1][7]; got != events[0].Details {
+		t.Errorf("want details %q, got %q", events[0].Details, got)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ArchiveStatus represents the state of a bookmarked page archive.
+type ArchiveStatus string
+
+const (
+	// ArchiveStatusPending means the page is queued to be archived.
+	ArchiveStatusPending ArchiveStatus = "pending"
+
+	// ArchiveStatusArchived means a snapshot of the page has been stored.
+	ArchiveStatusArchived ArchiveStatus = "archived"
+
+	// ArchiveStatusFailed means the page could not be archived.
+	ArchiveStatusFailed ArchiveStatus = "failed"
+)
+
+// An Archive tracks the snapshot of a bookmarked Web page.
+type Archive struct {
+	BookmarkUID string
+	UserUUID    string
+
+	// URL of the archived page, at the time archiving was requested.
+	URL string
+
+	Status ArchiveStatus
+
+	// ContentHash is the SHA-256 hash of the snapshot, used as its key in the BlobStore.
+	ContentHash string
+
+	// SizeBytes is the size of the snapshot, in bytes.
+	SizeBytes int64
+
+	// Error describes why the page could not be archived.
+	Error string
+
+	// Text is the readable text of the snapshot, indexed for full-text search with the bookmark.
+	Text string
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArchivedAt time.Time
+}
+
+// contentHash returns the hexadecimal SHA-256 hash of a snapshot.
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import "context"
+
+// BlobStore provides content-addressed storage for page snapshots.
+type BlobStore interface {
+	// BlobDelete deletes the blob with a given hash.
+	//
+	// Deleting a blob that does not exist is a no-op.
+	BlobDelete(ctx context.Context, hash string) error
+
+	// BlobGet returns the content of the blob with a given hash.
+	BlobGet(ctx context.Context, hash string) ([]byte, error)
+
+	// BlobHashes returns the hashes of all stored blobs.
+	BlobHashes(ctx context.Context) ([]string, error)
+
+	// BlobSave stores the content of a blob under a given hash.
+	BlobSave(ctx context.Context, hash string, content []byte) error
+}
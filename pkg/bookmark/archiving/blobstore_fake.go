@@ -0,0 +1,54 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+var _ BlobStore = &FakeBlobStore{}
+
+var errFakeBlobNotFound = errors.New("blob not found")
+
+// FakeBlobStore stores blobs in memory.
+type FakeBlobStore struct {
+	Blobs map[string][]byte
+}
+
+func (s *FakeBlobStore) BlobDelete(_ context.Context, hash string) error {
+	delete(s.Blobs, hash)
+	return nil
+}
+
+func (s *FakeBlobStore) BlobGet(_ context.Context, hash string) ([]byte, error) {
+	content, ok := s.Blobs[hash]
+	if !ok {
+		return []byte{}, errFakeBlobNotFound
+	}
+
+	return content, nil
+}
+
+func (s *FakeBlobStore) BlobHashes(_ context.Context) ([]string, error) {
+	hashes := make([]string, 0, len(s.Blobs))
+
+	for hash := range s.Blobs {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Strings(hashes)
+
+	return hashes, nil
+}
+
+func (s *FakeBlobStore) BlobSave(_ context.Context, hash string, content []byte) error {
+	if s.Blobs == nil {
+		s.Blobs = make(map[string][]byte)
+	}
+
+	s.Blobs[hash] = content
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+)
+
+const (
+	// DefaultMaxSnapshotBytes is the default maximum size of a page snapshot, including inlined resources.
+	DefaultMaxSnapshotBytes int64 = 10 * 1024 * 1024
+
+	// maxResources bounds the number of stylesheets, images and fonts inlined in a snapshot.
+	maxResources = 100
+)
+
+// A Client performs outgoing HTTP requests to capture snapshots of Web pages.
+type Client struct {
+	httpClient       *http.Client
+	userAgent        string
+	maxSnapshotBytes int64
+}
+
+// NewClient initializes and returns a Client using httpClient to perform requests as-is.
+//
+// This client must only be used when HTTP/HTTPS traffic goes through a proxy, or for testing.
+func NewClient(httpClient *http.Client, userAgent string, maxSnapshotBytes int64) *Client {
+	return &Client{
+		httpClient:       httpClient,
+		userAgent:        userAgent,
+		maxSnapshotBytes: maxSnapshotBytes,
+	}
+}
+
+// NewSafeClient initializes and returns a Client backed by an SSRF-guarded http.Client.
+//
+// It MUST NOT be used when HTTP/HTTPS traffic goes through a proxy (otherwise all requests will be blocked).
+func NewSafeClient(userAgent string, timeout time.Duration, maxSnapshotBytes int64) (*Client, error) {
+	transport, err := httpsafe.NewSafeTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	return NewClient(httpClient, userAgent, maxSnapshotBytes), nil
+}
+
+// Capture retrieves a Web page and returns a self-contained HTML snapshot, with its stylesheets,
+// images and fonts inlined, and its scripts removed.
+//
+// Resources are inlined as long as the snapshot stays under the maximum size; other resources
+// keep referencing their original location.
+func (c *Client) Capture(ctx context.Context, pageURL string) ([]byte, error) {
+	body, contentType, finalURL, err := c.get(ctx, pageURL, c.maxSnapshotBytes)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return []byte{}, fmt.Errorf("%w: %q", ErrContentTypeUnsupported, mediaType)
+	}
+
+	decoded, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return []byte{}, fmt.Errorf("archiving: failed to decode page: %w", err)
+	}
+
+	doc, err := html.ParseWithOptions(decoded, html.ParseOptionEnableScripting(false))
+	if err != nil {
+		return []byte{}, fmt.Errorf("archiving: failed to parse page: %w", err)
+	}
+
+	s := &snapshot{
+		ctx:       ctx,
+		client:    c,
+		budget:    c.maxSnapshotBytes - int64(len(body)),
+		resources: make(map[string]string),
+	}
+	s.rewrite(doc, finalURL)
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return []byte{}, fmt.Errorf("archiving: failed to render snapshot: %w", err)
+	}
+
+	if int64(buf.Len()) > c.maxSnapshotBytes {
+		return []byte{}, ErrSnapshotTooLarge
+	}
+
+	return buf.Bytes(), nil
+}
+
+// get performs an HTTP GET request, and returns the response body, its content type and the
+// URL of the final response.
+//
+// ErrSnapshotTooLarge is returned if the response body is larger than maxBytes.
+func (c *Client) get(ctx context.Context, rawURL string, maxBytes int64) ([]byte, string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return []byte{}, "", nil, fmt.Errorf("archiving: failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return []byte{}, "", nil, fmt.Errorf("archiving: failed to perform request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return []byte{}, "", nil, fmt.Errorf("%w: status %d", ErrPageUnavailable, resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxBytes {
+		return []byte{}, "", nil, ErrSnapshotTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return []byte{}, "", nil, fmt.Errorf("archiving: failed to read response body: %w", err)
+	}
+
+	if int64(len(body)) > maxBytes {
+		return []byte{}, "", nil, ErrSnapshotTooLarge
+	}
+
+	return body, strings.TrimSpace(resp.Header.Get("Content-Type")), resp.Request.URL, nil
+}
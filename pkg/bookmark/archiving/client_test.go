@@ -0,0 +1,168 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="iso-8859-1">
+<meta http-equiv="refresh" content="0; url=https://example.org/">
+<title>Archived page</title>
+<link rel="stylesheet" href="/style.css">
+<link rel="icon" href="/favicon.ico">
+<link rel="canonical" href="/page">
+<script src="/script.js"></script>
+</head>
+<body onload="alert('loaded')">
+<h1 style="background: url(/pixel.png)">Hello</h1>
+<img src="/pixel.png" srcset="/pixel-2x.png 2x" alt="Pixel">
+<img src="/missing.png" alt="Missing">
+<a href="/other" onclick="alert('clicked')">Other page</a>
+<a href="javascript:alert('link')">Script link</a>
+<iframe src="https://example.org/embed"></iframe>
+<script>alert('inline');</script>
+</body>
+</html>`
+
+	testStylesheet = `body { background: url('pixel.png'); font-family: sans-serif; }`
+)
+
+var testPixel = []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/page", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(testPage))
+	})
+	mux.HandleFunc("/style.css", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		_, _ = w.Write([]byte(testStylesheet))
+	})
+	mux.HandleFunc("/pixel.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPixel)
+	})
+	mux.HandleFunc("/document.pdf", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.4"))
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClientCapture(t *testing.T) {
+	server := newTestServer(t)
+
+	t.Run("self-contained snapshot", func(t *testing.T) {
+		client := NewClient(server.Client(), "test", DefaultMaxSnapshotBytes)
+
+		content, err := client.Capture(t.Context(), server.URL+"/page")
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		snapshot := string(content)
+		pixelDataURI := "data:image/png;base64,iVBORw0KGgo="
+
+		for _, want := range []string{
+			`<meta charset="utf-8"/>`,
+			"<title>Archived page</title>",
+			`<style>body { background: url("` + pixelDataURI + `")`,
+			`<h1 style="background: url(&#34;` + pixelDataURI + `&#34;)">`,
+			`<img src="` + pixelDataURI + `" alt="Pixel"/>`,
+			`<img src="` + server.URL + `/missing.png" alt="Missing"/>`,
+			`<a href="` + server.URL + `/other">Other page</a>`,
+			`<link rel="canonical" href="` + server.URL + `/page"/>`,
+			"<a>Script link</a>",
+		} {
+			if !strings.Contains(snapshot, want) {
+				t.Errorf("want %q in snapshot, got:\n%s", want, snapshot)
+			}
+		}
+
+		for _, unwanted := range []string{
+			"<script",
+			"<iframe",
+			"alert(",
+			"srcset",
+			"iso-8859-1",
+			"http-equiv",
+			"favicon.ico",
+			"style.css",
+		} {
+			if strings.Contains(snapshot, unwanted) {
+				t.Errorf("want no %q in snapshot, got:\n%s", unwanted, snapshot)
+			}
+		}
+	})
+
+	t.Run("resources are not inlined past the size limit", func(t *testing.T) {
+		client := NewClient(server.Client(), "test", int64(len(testPage)+64))
+
+		content, err := client.Capture(t.Context(), server.URL+"/page")
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if strings.Contains(string(content), "data:image/png") {
+			t.Errorf("want no inlined image, got:\n%s", content)
+		}
+	})
+
+	cases := []struct {
+		tname            string
+		path             string
+		maxSnapshotBytes int64
+		wantErr          error
+	}{
+		{
+			tname:            "page not found",
+			path:             "/gone",
+			maxSnapshotBytes: DefaultMaxSnapshotBytes,
+			wantErr:          ErrPageUnavailable,
+		},
+		{
+			tname:            "unsupported content type",
+			path:             "/document.pdf",
+			maxSnapshotBytes: DefaultMaxSnapshotBytes,
+			wantErr:          ErrContentTypeUnsupported,
+		},
+		{
+			tname:            "page too large",
+			path:             "/page",
+			maxSnapshotBytes: 128,
+			wantErr:          ErrSnapshotTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			client := NewClient(server.Client(), "test", tc.maxSnapshotBytes)
+
+			_, err := client.Capture(t.Context(), server.URL+tc.path)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
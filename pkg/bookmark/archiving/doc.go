@@ -0,0 +1,6 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package archiving captures self-contained snapshots of bookmarked Web pages, so that their
+// content remains available when the original page goes offline.
+package archiving
@@ -0,0 +1,14 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import "errors"
+
+var (
+	ErrArchiveNotAvailable    = errors.New("archiving: archive not available")
+	ErrArchiveNotFound        = errors.New("archiving: archive not found")
+	ErrContentTypeUnsupported = errors.New("archiving: unsupported content type")
+	ErrPageUnavailable        = errors.New("archiving: page unavailable")
+	ErrSnapshotTooLarge       = errors.New("archiving: snapshot too large")
+)
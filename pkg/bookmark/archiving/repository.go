@@ -0,0 +1,35 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+)
+
+// Repository provides access to bookmarked page archives.
+type Repository interface {
+	// BookmarkArchiveGetByUID returns the archive for a given bookmark.
+	BookmarkArchiveGetByUID(ctx context.Context, userUUID string, bookmarkUID string) (Archive, error)
+
+	// BookmarkArchiveGetNPending returns at most n pending archives, for all users,
+	// in the order they were requested.
+	BookmarkArchiveGetNPending(ctx context.Context, n uint) ([]Archive, error)
+
+	// BookmarkArchiveHashesByUserUUID returns the distinct content hashes of a given
+	// user's archives.
+	BookmarkArchiveHashesByUserUUID(ctx context.Context, userUUID string) ([]string, error)
+
+	// BookmarkArchiveHashesUnreferenced returns the hashes, among the given ones,
+	// that are not referenced by any archive.
+	BookmarkArchiveHashesUnreferenced(ctx context.Context, hashes []string) ([]string, error)
+
+	// BookmarkArchiveRequest creates or resets the archive for a given bookmark, and
+	// marks it as pending.
+	BookmarkArchiveRequest(ctx context.Context, archive Archive) error
+
+	// BookmarkArchiveUpdate saves the outcome of archiving a page.
+	//
+	// When the page has been archived, its text is indexed for full-text search with the bookmark.
+	BookmarkArchiveUpdate(ctx context.Context, archive Archive) error
+}
@@ -0,0 +1,106 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+	"slices"
+	"sort"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository stores archives in memory.
+type FakeRepository struct {
+	Archives []Archive
+}
+
+func (r *FakeRepository) BookmarkArchiveGetByUID(_ context.Context, userUUID string, bookmarkUID string) (Archive, error) {
+	for _, archive := range r.Archives {
+		if archive.UserUUID == userUUID && archive.BookmarkUID == bookmarkUID {
+			return archive, nil
+		}
+	}
+
+	return Archive{}, ErrArchiveNotFound
+}
+
+func (r *FakeRepository) BookmarkArchiveGetNPending(_ context.Context, n uint) ([]Archive, error) {
+	var archives []Archive
+
+	for _, archive := range r.Archives {
+		if archive.Status == ArchiveStatusPending {
+			archives = append(archives, archive)
+		}
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].UpdatedAt.Before(archives[j].UpdatedAt)
+	})
+
+	if uint(len(archives)) > n {
+		archives = archives[:n]
+	}
+
+	return archives, nil
+}
+
+func (r *FakeRepository) BookmarkArchiveHashesByUserUUID(_ context.Context, userUUID string) ([]string, error) {
+	var hashes []string
+
+	for _, archive := range r.Archives {
+		if archive.UserUUID != userUUID || archive.ContentHash == "" {
+			continue
+		}
+
+		if !slices.Contains(hashes, archive.ContentHash) {
+			hashes = append(hashes, archive.ContentHash)
+		}
+	}
+
+	return hashes, nil
+}
+
+func (r *FakeRepository) BookmarkArchiveHashesUnreferenced(_ context.Context, hashes []string) ([]string, error) {
+	var unreferenced []string
+
+	for _, hash := range hashes {
+		referenced := slices.ContainsFunc(r.Archives, func(archive Archive) bool {
+			return archive.ContentHash == hash
+		})
+
+		if !referenced {
+			unreferenced = append(unreferenced, hash)
+		}
+	}
+
+	return unreferenced, nil
+}
+
+func (r *FakeRepository) BookmarkArchiveRequest(_ context.Context, archive Archive) error {
+	for index, existing := range r.Archives {
+		if existing.UserUUID == archive.UserUUID && existing.BookmarkUID == archive.BookmarkUID {
+			archive.CreatedAt = existing.CreatedAt
+			archive.ContentHash = existing.ContentHash
+			archive.SizeBytes = existing.SizeBytes
+			archive.ArchivedAt = existing.ArchivedAt
+			r.Archives[index] = archive
+			return nil
+		}
+	}
+
+	r.Archives = append(r.Archives, archive)
+	return nil
+}
+
+func (r *FakeRepository) BookmarkArchiveUpdate(_ context.Context, archive Archive) error {
+	for index, existing := range r.Archives {
+		if existing.UserUUID == archive.UserUUID && existing.BookmarkUID == archive.BookmarkUID {
+			r.Archives[index] = archive
+			return nil
+		}
+	}
+
+	return ErrArchiveNotFound
+}
@@ -0,0 +1,82 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	defaultArchiveInterval = 1 * time.Minute
+	defaultSweepInterval   = 24 * time.Hour
+	defaultTaskTimeout     = 5 * time.Minute
+)
+
+// A Scheduler periodically archives pending bookmarked pages, and deletes
+// page snapshots that are no longer referenced.
+type Scheduler struct {
+	s             *Service
+	locker        sync.Locker
+	interval      time.Duration
+	sweepInterval time.Duration
+	taskTimeout   time.Duration
+}
+
+// NewScheduler initializes and returns a Scheduler.
+func NewScheduler(service *Service, locker sync.Locker) *Scheduler {
+	return &Scheduler{
+		s:             service,
+		locker:        locker,
+		interval:      defaultArchiveInterval,
+		sweepInterval: defaultSweepInterval,
+		taskTimeout:   defaultTaskTimeout,
+	}
+}
+
+// Run periodically archives pending bookmarked pages, deletes the page snapshots
+// requested for deletion, and sweeps unreferenced page snapshots.
+//
+// All tasks share the same lock, so that a snapshot being archived is never
+// deleted before the archive referencing it has been saved.
+func (sc *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(sc.interval)
+	sweepTicker := time.NewTicker(sc.sweepInterval)
+	log.Info().
+		Dur("interval", sc.interval).
+		Dur("sweep_interval", sc.sweepInterval).
+		Msg("bookmarks: archive scheduler started")
+
+	for {
+		select {
+		case <-ticker.C:
+			go sc.runTask(ctx, sc.s.Archive, "bookmarks: failed to archive pages")
+			go sc.runTask(ctx, sc.s.DeleteRequestedBlobs, "bookmarks: failed to delete page snapshots")
+		case <-sweepTicker.C:
+			go sc.runTask(ctx, sc.s.SweepBlobs, "bookmarks: failed to sweep page snapshots")
+		}
+	}
+}
+
+func (sc *Scheduler) runTask(ctx context.Context, task func(context.Context, string) error, errorMsg string) {
+	jobID := ksuid.New().String()
+
+	sc.locker.Lock()
+	defer sc.locker.Unlock()
+
+	taskCtx, cancel := context.WithTimeout(ctx, sc.taskTimeout)
+	defer cancel()
+
+	if err := task(taskCtx, jobID); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg(errorMsg)
+	}
+}
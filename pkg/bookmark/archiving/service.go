@@ -0,0 +1,317 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	pagesToArchive uint = 10
+
+	nWorkers int = 2
+)
+
+// Service handles bookmarked page archiving operations.
+type Service struct {
+	r               Repository
+	blobStore       BlobStore
+	bookmarkService *bookmark.Service
+	client          *Client
+
+	// pendingBlobsMu guards pendingBlobs, the hashes of page snapshots that may no longer
+	// be referenced, pending deletion by the Scheduler.
+	pendingBlobsMu sync.Mutex
+	pendingBlobs   []string
+}
+
+// NewService initializes and returns a new archiving Service.
+func NewService(r Repository, blobStore BlobStore, bookmarkService *bookmark.Service, client *Client) *Service {
+	return &Service{
+		r:               r,
+		blobStore:       blobStore,
+		bookmarkService: bookmarkService,
+		client:          client,
+	}
+}
+
+// ByBookmarkUID returns the archive for a given bookmark.
+func (s *Service) ByBookmarkUID(ctx context.Context, userUUID string, bookmarkUID string) (Archive, error) {
+	return s.r.BookmarkArchiveGetByUID(ctx, userUUID, bookmarkUID)
+}
+
+// Content returns the archive and the snapshot content for a given bookmark.
+//
+// The latest snapshot remains available while the page is being archived again.
+func (s *Service) Content(ctx context.Context, userUUID string, bookmarkUID string) (Archive, []byte, error) {
+	archive, err := s.r.BookmarkArchiveGetByUID(ctx, userUUID, bookmarkUID)
+	if err != nil {
+		return Archive{}, []byte{}, err
+	}
+
+	if archive.ContentHash == "" {
+		return Archive{}, []byte{}, ErrArchiveNotAvailable
+	}
+
+	content, err := s.blobStore.BlobGet(ctx, archive.ContentHash)
+	if err != nil {
+		return Archive{}, []byte{}, err
+	}
+
+	return archive, content, nil
+}
+
+// Request queues the page for a given bookmark to be archived.
+func (s *Service) Request(ctx context.Context, userUUID string, bookmarkUID string) error {
+	b, err := s.bookmarkService.ByUID(ctx, userUUID, bookmarkUID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	archive := Archive{
+		BookmarkUID: b.UID,
+		UserUUID:    b.UserUUID,
+		URL:         b.URL,
+		Status:      ArchiveStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	return s.r.BookmarkArchiveRequest(ctx, archive)
+}
+
+// Archive captures and stores snapshots of pending pages, for all users.
+func (s *Service) Archive(ctx context.Context, jobID string) error {
+	archives, err := s.r.BookmarkArchiveGetNPending(ctx, pagesToArchive)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to list pages to archive")
+		return err
+	}
+
+	if len(archives) == 0 {
+		log.Debug().Str("job_id", jobID).Msg("bookmarks: no pages to archive")
+		return nil
+	}
+
+	workerPool := pool.New().WithErrors().WithMaxGoroutines(nWorkers)
+
+	for _, archive := range archives {
+		workerPool.Go(func() error {
+			return s.archivePage(ctx, archive, jobID)
+		})
+	}
+
+	if err := workerPool.Wait(); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to archive some pages")
+		return err
+	}
+
+	log.
+		Info().
+		Int("n_pages", len(archives)).
+		Str("job_id", jobID).
+		Msg("bookmarks: pages archived")
+
+	return nil
+}
+
+func (s *Service) archivePage(ctx context.Context, archive Archive, jobID string) error {
+	now := time.Now().UTC()
+	archive.UpdatedAt = now
+
+	content, err := s.client.Capture(ctx, archive.URL)
+	if err != nil {
+		log.
+			Warn().
+			Err(err).
+			Str("bookmark_uid", archive.BookmarkUID).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to capture page")
+
+		archive.Status = ArchiveStatusFailed
+		archive.Error = err.Error()
+
+		return s.updateArchive(ctx, archive, jobID)
+	}
+
+	hash := contentHash(content)
+	previousHash := archive.ContentHash
+
+	if err := s.blobStore.BlobSave(ctx, hash, content); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("bookmark_uid", archive.BookmarkUID).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to store page snapshot")
+		return err
+	}
+
+	archive.Status = ArchiveStatusArchived
+	archive.ContentHash = hash
+	archive.SizeBytes = int64(len(content))
+	archive.Text = ExtractText(content)
+	archive.Error = ""
+	archive.ArchivedAt = now
+
+	if err := s.updateArchive(ctx, archive, jobID); err != nil {
+		return err
+	}
+
+	if previousHash == "" || previousHash == hash {
+		return nil
+	}
+
+	// The previous snapshot may be shared with other archives, or is otherwise
+	// left to the next sweep if it cannot be deleted right away.
+	if _, err := s.deleteUnreferencedBlobs(ctx, []string{previousHash}); err != nil {
+		log.
+			Warn().
+			Err(err).
+			Str("bookmark_uid", archive.BookmarkUID).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to delete previous page snapshot")
+	}
+
+	return nil
+}
+
+func (s *Service) updateArchive(ctx context.Context, archive Archive, jobID string) error {
+	if err := s.r.BookmarkArchiveUpdate(ctx, archive); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("bookmark_uid", archive.BookmarkUID).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to save page archive")
+		return err
+	}
+
+	return nil
+}
+
+// SweepBlobs deletes stored page snapshots that are no longer referenced by any
+// archive, e.g. after the corresponding bookmarks have been deleted.
+func (s *Service) SweepBlobs(ctx context.Context, jobID string) error {
+	hashes, err := s.blobStore.BlobHashes(ctx)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to list page snapshots")
+		return err
+	}
+
+	if len(hashes) == 0 {
+		log.Debug().Str("job_id", jobID).Msg("bookmarks: no page snapshots to sweep")
+		return nil
+	}
+
+	deleted, err := s.deleteUnreferencedBlobs(ctx, hashes)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to delete unreferenced page snapshots")
+		return err
+	}
+
+	log.
+		Info().
+		Int("n_snapshots", deleted).
+		Str("job_id", jobID).
+		Msg("bookmarks: unreferenced page snapshots deleted")
+
+	return nil
+}
+
+// ContentHashesByUserUUID returns the hashes of the page snapshots referenced by
+// a given user's archives.
+func (s *Service) ContentHashesByUserUUID(ctx context.Context, userUUID string) ([]string, error) {
+	return s.r.BookmarkArchiveHashesByUserUUID(ctx, userUUID)
+}
+
+// RequestBlobDeletion marks page snapshots for deletion, e.g. after the archives
+// referencing them have been deleted.
+//
+// Snapshots are deleted by the Scheduler, so that a snapshot being archived again is never
+// deleted before the archive referencing it has been saved; those that are still referenced
+// by other archives are kept.
+func (s *Service) RequestBlobDeletion(hashes []string) {
+	s.pendingBlobsMu.Lock()
+	defer s.pendingBlobsMu.Unlock()
+
+	s.pendingBlobs = append(s.pendingBlobs, hashes...)
+}
+
+// DeleteRequestedBlobs deletes the page snapshots marked for deletion with
+// RequestBlobDeletion that are no longer referenced by any archive.
+//
+// Snapshots that cannot be deleted are left to the next sweep.
+func (s *Service) DeleteRequestedBlobs(ctx context.Context, jobID string) error {
+	s.pendingBlobsMu.Lock()
+	hashes := s.pendingBlobs
+	s.pendingBlobs = nil
+	s.pendingBlobsMu.Unlock()
+
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	deleted, err := s.deleteUnreferencedBlobs(ctx, hashes)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to delete page snapshots")
+		return err
+	}
+
+	log.
+		Info().
+		Int("n_snapshots", deleted).
+		Str("job_id", jobID).
+		Msg("bookmarks: page snapshots deleted")
+
+	return nil
+}
+
+// deleteUnreferencedBlobs deletes the page snapshots, among the given ones, that
+// are not referenced by any archive, and returns the number of deleted snapshots.
+//
+// It must only be called by tasks run by the Scheduler.
+func (s *Service) deleteUnreferencedBlobs(ctx context.Context, hashes []string) (int, error) {
+	unreferenced, err := s.r.BookmarkArchiveHashesUnreferenced(ctx, hashes)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, hash := range unreferenced {
+		if err := s.blobStore.BlobDelete(ctx, hash); err != nil {
+			return i, err
+		}
+	}
+
+	return len(unreferenced), nil
+}
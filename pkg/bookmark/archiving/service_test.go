@@ -0,0 +1,322 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	testUserUUID = "5d75c769-059c-4b36-9db6-1c82619e704a"
+)
+
+func TestServiceRequest(t *testing.T) {
+	bookmarkRepository := &bookmark.FakeRepository{
+		Bookmarks: []bookmark.Bookmark{
+			{
+				UID:      "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID: testUserUUID,
+				URL:      "https://example.org/page",
+				Title:    "Page",
+			},
+		},
+	}
+
+	cases := []struct {
+		tname            string
+		repositoryState  []Archive
+		bookmarkUID      string
+		wantContentHash  string
+		wantArchiveCount int
+		wantErr          error
+	}{
+		{
+			tname:       "unknown bookmark",
+			bookmarkUID: "2Cz5ReZFgCXtVCgHPzC6zsGDjFH",
+			wantErr:     bookmark.ErrNotFound,
+		},
+		{
+			tname:            "new archive",
+			bookmarkUID:      "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+			wantArchiveCount: 1,
+		},
+		{
+			tname: "existing archive keeps its snapshot",
+			repositoryState: []Archive{
+				{
+					BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+					UserUUID:    testUserUUID,
+					URL:         "https://example.org/page",
+					Status:      ArchiveStatusArchived,
+					ContentHash: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+				},
+			},
+			bookmarkUID:      "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+			wantContentHash:  "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			wantArchiveCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{Archives: tc.repositoryState}
+			s := NewService(r, &FakeBlobStore{}, bookmark.NewService(bookmarkRepository, nil), nil)
+
+			err := s.Request(t.Context(), testUserUUID, tc.bookmarkUID)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if len(r.Archives) != tc.wantArchiveCount {
+				t.Fatalf("want %d archives, got %d", tc.wantArchiveCount, len(r.Archives))
+			}
+
+			archive := r.Archives[0]
+
+			if archive.Status != ArchiveStatusPending {
+				t.Errorf("want status %q, got %q", ArchiveStatusPending, archive.Status)
+			}
+			if archive.URL != "https://example.org/page" {
+				t.Errorf("want URL %q, got %q", "https://example.org/page", archive.URL)
+			}
+			if archive.ContentHash != tc.wantContentHash {
+				t.Errorf("want content hash %q, got %q", tc.wantContentHash, archive.ContentHash)
+			}
+		})
+	}
+}
+
+func TestServiceArchive(t *testing.T) {
+	server := newTestServer(t)
+
+	r := &FakeRepository{
+		Archives: []Archive{
+			{
+				BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID:    testUserUUID,
+				URL:         server.URL + "/page",
+				Status:      ArchiveStatusPending,
+				UpdatedAt:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				BookmarkUID: "2Cz5ReZFgCXtVCgHPzC6zsGDjFH",
+				UserUUID:    testUserUUID,
+				URL:         server.URL + "/gone",
+				Status:      ArchiveStatusPending,
+				UpdatedAt:   time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	blobStore := &FakeBlobStore{}
+	s := NewService(r, blobStore, nil, NewClient(server.Client(), "test", DefaultMaxSnapshotBytes))
+
+	if err := s.Archive(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	archived := r.Archives[0]
+	if archived.Status != ArchiveStatusArchived {
+		t.Errorf("want status %q, got %q (error: %q)", ArchiveStatusArchived, archived.Status, archived.Error)
+	}
+	if archived.ArchivedAt.IsZero() {
+		t.Error("want archival time to be set")
+	}
+	if archived.Text == "" {
+		t.Error("want the page text to be extracted")
+	}
+	if len(blobStore.Blobs) != 1 {
+		t.Fatalf("want 1 stored snapshot, got %d", len(blobStore.Blobs))
+	}
+	if int64(len(blobStore.Blobs[archived.ContentHash])) != archived.SizeBytes {
+		t.Errorf("want snapshot of %d bytes stored under its hash", archived.SizeBytes)
+	}
+
+	failed := r.Archives[1]
+	if failed.Status != ArchiveStatusFailed {
+		t.Errorf("want status %q, got %q", ArchiveStatusFailed, failed.Status)
+	}
+	if failed.Error == "" {
+		t.Error("want an error message")
+	}
+
+	t.Run("content", func(t *testing.T) {
+		archive, content, err := s.Content(t.Context(), testUserUUID, archived.BookmarkUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if archive.ContentHash != contentHash(content) {
+			t.Errorf("want content matching hash %q", archive.ContentHash)
+		}
+	})
+
+	t.Run("content not available", func(t *testing.T) {
+		_, _, err := s.Content(t.Context(), testUserUUID, failed.BookmarkUID)
+		if !errors.Is(err, ErrArchiveNotAvailable) {
+			t.Fatalf("want error %q, got %q", ErrArchiveNotAvailable, err)
+		}
+	})
+
+	t.Run("archive not found", func(t *testing.T) {
+		_, _, err := s.Content(t.Context(), testUserUUID, "2Cz5SXbCRyr6Mh5QQnx2DcLnzPj")
+		if !errors.Is(err, ErrArchiveNotFound) {
+			t.Fatalf("want error %q, got %q", ErrArchiveNotFound, err)
+		}
+	})
+}
+
+func TestServiceArchiveReplacesSnapshot(t *testing.T) {
+	server := newTestServer(t)
+
+	const (
+		previousHash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		sharedHash   = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+	)
+
+	r := &FakeRepository{
+		Archives: []Archive{
+			{
+				BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID:    testUserUUID,
+				URL:         server.URL + "/page",
+				Status:      ArchiveStatusPending,
+				ContentHash: previousHash,
+			},
+			{
+				BookmarkUID: "2Cz5ReZFgCXtVCgHPzC6zsGDjFH",
+				UserUUID:    testUserUUID,
+				URL:         server.URL + "/page",
+				Status:      ArchiveStatusPending,
+				ContentHash: sharedHash,
+			},
+			{
+				BookmarkUID: "2Cz5SXbCRyr6Mh5QQnx2DcLnzPj",
+				UserUUID:    testUserUUID,
+				URL:         "https://example.org/shared",
+				Status:      ArchiveStatusArchived,
+				ContentHash: sharedHash,
+			},
+		},
+	}
+	blobStore := &FakeBlobStore{
+		Blobs: map[string][]byte{
+			previousHash: []byte("hello"),
+			sharedHash:   []byte("world"),
+		},
+	}
+	s := NewService(r, blobStore, nil, NewClient(server.Client(), "test", DefaultMaxSnapshotBytes))
+
+	if err := s.Archive(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if _, ok := blobStore.Blobs[previousHash]; ok {
+		t.Error("want the previous snapshot to be deleted")
+	}
+	if _, ok := blobStore.Blobs[sharedHash]; !ok {
+		t.Error("want the snapshot still referenced by another archive to be kept")
+	}
+	if _, ok := blobStore.Blobs[r.Archives[0].ContentHash]; !ok {
+		t.Error("want the new snapshot to be stored")
+	}
+}
+
+func TestServiceSweepBlobs(t *testing.T) {
+	const (
+		referencedHash   = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		unreferencedHash = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+	)
+
+	r := &FakeRepository{
+		Archives: []Archive{
+			{
+				BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID:    testUserUUID,
+				URL:         "https://example.org/page",
+				Status:      ArchiveStatusArchived,
+				ContentHash: referencedHash,
+			},
+		},
+	}
+	blobStore := &FakeBlobStore{
+		Blobs: map[string][]byte{
+			referencedHash:   []byte("hello"),
+			unreferencedHash: []byte("world"),
+		},
+	}
+	s := NewService(r, blobStore, nil, nil)
+
+	if err := s.SweepBlobs(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	hashes, err := blobStore.BlobHashes(t.Context())
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(hashes) != 1 || hashes[0] != referencedHash {
+		t.Errorf("want only the referenced snapshot %q to be kept, got %q", referencedHash, hashes)
+	}
+}
+
+func TestServiceDeleteRequestedBlobs(t *testing.T) {
+	const (
+		referencedHash   = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		unreferencedHash = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+		otherHash        = "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"
+	)
+
+	r := &FakeRepository{
+		Archives: []Archive{
+			{
+				BookmarkUID: "2Cz5Rf1kWz9SZrQoJtVmGHcDpCw",
+				UserUUID:    testUserUUID,
+				URL:         "https://example.org/page",
+				Status:      ArchiveStatusArchived,
+				ContentHash: referencedHash,
+			},
+		},
+	}
+	blobStore := &FakeBlobStore{
+		Blobs: map[string][]byte{
+			referencedHash:   []byte("hello"),
+			unreferencedHash: []byte("world"),
+			otherHash:        []byte("bar"),
+		},
+	}
+	s := NewService(r, blobStore, nil, nil)
+
+	s.RequestBlobDeletion([]string{referencedHash, unreferencedHash})
+
+	if err := s.DeleteRequestedBlobs(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	hashes, err := blobStore.BlobHashes(t.Context())
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	// unreferenced snapshots that were not requested for deletion are left to the sweep
+	want := []string{referencedHash, otherHash}
+	slices.Sort(want)
+
+	if !slices.Equal(hashes, want) {
+		t.Errorf("want snapshots %q to be kept, got %q", want, hashes)
+	}
+}
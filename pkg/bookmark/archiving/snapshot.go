@@ -0,0 +1,339 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"context"
+	"encoding/base64"
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// cssURLRegexp matches url() references in stylesheets.
+	cssURLRegexp = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)['"]?\s*\)`)
+
+	// removedElements lists elements that are removed from snapshots, as they either hold
+	// active content, or embed external documents that cannot be archived.
+	removedElements = []atom.Atom{
+		atom.Applet,
+		atom.Base,
+		atom.Embed,
+		atom.Frame,
+		atom.Iframe,
+		atom.Object,
+		atom.Script,
+		atom.Source,
+	}
+
+	// removedAttributes lists attributes that are removed from all elements.
+	removedAttributes = []string{
+		"crossorigin",
+		"integrity",
+		"nonce",
+		"ping",
+		"srcset",
+	}
+
+	// imageMediaTypePrefixes lists the media types of resources inlined from HTML image elements.
+	imageMediaTypePrefixes = []string{"image/"}
+
+	// stylesheetMediaTypePrefixes lists the media types of resources inlined from stylesheets.
+	stylesheetMediaTypePrefixes = []string{
+		"application/font-",
+		"application/vnd.ms-fontobject",
+		"application/x-font-",
+		"font/",
+		"image/",
+	}
+)
+
+// A snapshot rewrites a parsed HTML document to make it self-contained.
+type snapshot struct {
+	ctx    context.Context
+	client *Client
+
+	// budget is the number of bytes that can still be used to inline resources.
+	budget int64
+
+	// nResources is the number of resources that have been requested.
+	nResources int
+
+	// resources caches the data URIs of resources that have already been inlined.
+	resources map[string]string
+}
+
+// rewrite removes active content from a document, inlines its resources, and resolves
+// references to other pages against the page URL.
+func (s *snapshot) rewrite(doc *html.Node, pageURL *url.URL) {
+	var removed []*html.Node
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if s.rewriteElement(n, pageURL) {
+				removed = append(removed, n)
+				return
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+
+	for _, n := range removed {
+		n.Parent.RemoveChild(n)
+	}
+
+	if head := findElement(doc, atom.Head); head != nil {
+		head.InsertBefore(
+			&html.Node{
+				Type:     html.ElementNode,
+				Data:     "meta",
+				DataAtom: atom.Meta,
+				Attr:     []html.Attribute{{Key: "charset", Val: "utf-8"}},
+			},
+			head.FirstChild,
+		)
+	}
+}
+
+// rewriteElement rewrites a HTML element, and returns whether it should be removed.
+func (s *snapshot) rewriteElement(n *html.Node, pageURL *url.URL) bool {
+	if slices.Contains(removedElements, n.DataAtom) {
+		return true
+	}
+
+	n.Attr = slices.DeleteFunc(n.Attr, func(a html.Attribute) bool {
+		key := strings.ToLower(a.Key)
+
+		if strings.HasPrefix(key, "on") || slices.Contains(removedAttributes, key) {
+			return true
+		}
+
+		if key == "href" || key == "src" || key == "action" || key == "formaction" {
+			return strings.HasPrefix(strings.ToLower(strings.TrimSpace(a.Val)), "javascript:")
+		}
+
+		return false
+	})
+
+	for i, a := range n.Attr {
+		if a.Key == "style" {
+			n.Attr[i].Val = s.rewriteStylesheet(a.Val, pageURL)
+		}
+	}
+
+	switch n.DataAtom {
+	case atom.A, atom.Area, atom.Form:
+		for i, a := range n.Attr {
+			if a.Key == "href" || a.Key == "action" {
+				n.Attr[i].Val = resolveURL(pageURL, a.Val)
+			}
+		}
+
+	case atom.Img:
+		for i, a := range n.Attr {
+			if a.Key == "src" {
+				n.Attr[i].Val = s.inline(resolveURL(pageURL, a.Val), imageMediaTypePrefixes)
+			}
+		}
+
+	case atom.Meta:
+		// The snapshot is re-encoded to UTF-8, and must not be redirected or restricted by the page.
+		return attr(n, "charset") != "" || attr(n, "http-equiv") != ""
+
+	case atom.Link:
+		return s.rewriteLink(n, pageURL)
+
+	case atom.Style:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				c.Data = s.rewriteStylesheet(c.Data, pageURL)
+			}
+		}
+	}
+
+	return false
+}
+
+// rewriteLink replaces a stylesheet link with a style element holding the stylesheet, and
+// returns whether the link should be removed.
+//
+// Links to other resources (icons, feeds, preloaded scripts, etc.) are removed, except for
+// the canonical page URL.
+func (s *snapshot) rewriteLink(n *html.Node, pageURL *url.URL) bool {
+	rels := strings.Fields(strings.ToLower(attr(n, "rel")))
+	href := resolveURL(pageURL, attr(n, "href"))
+
+	if slices.Contains(rels, "canonical") {
+		setAttr(n, "href", href)
+		return false
+	}
+
+	if !slices.Contains(rels, "stylesheet") {
+		return true
+	}
+
+	stylesheet, ok := s.fetchStylesheet(href)
+	if !ok {
+		setAttr(n, "href", href)
+		return false
+	}
+
+	n.Data = "style"
+	n.DataAtom = atom.Style
+	n.Attr = slices.DeleteFunc(n.Attr, func(a html.Attribute) bool {
+		return a.Key != "media"
+	})
+	n.AppendChild(&html.Node{
+		Type: html.TextNode,
+		Data: stylesheet,
+	})
+
+	return false
+}
+
+// fetchStylesheet retrieves a stylesheet, and inlines the resources it references.
+func (s *snapshot) fetchStylesheet(stylesheetURL string) (string, bool) {
+	if !s.canFetch(stylesheetURL) {
+		return "", false
+	}
+
+	s.nResources++
+
+	body, contentType, finalURL, err := s.client.get(s.ctx, stylesheetURL, s.budget)
+	if err != nil {
+		return "", false
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/css" {
+		return "", false
+	}
+
+	// The closing tag sequence must not appear in the content of a style element.
+	stylesheet := strings.ReplaceAll(string(body), "</", `<\/`)
+	s.budget -= int64(len(stylesheet))
+
+	return s.rewriteStylesheet(stylesheet, finalURL), true
+}
+
+// rewriteStylesheet inlines the resources referenced by a stylesheet.
+func (s *snapshot) rewriteStylesheet(stylesheet string, stylesheetURL *url.URL) string {
+	return cssURLRegexp.ReplaceAllStringFunc(stylesheet, func(match string) string {
+		submatches := cssURLRegexp.FindStringSubmatch(match)
+		ref := strings.TrimSpace(submatches[2])
+
+		if strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") {
+			return match
+		}
+
+		resolved := s.inline(resolveURL(stylesheetURL, ref), stylesheetMediaTypePrefixes)
+
+		return `url("` + strings.ReplaceAll(resolved, `"`, `%22`) + `")`
+	})
+}
+
+// inline retrieves a resource, and returns it as a data URI if its media type is allowed and
+// it fits in the snapshot; otherwise, the resource URL is returned unchanged.
+func (s *snapshot) inline(resourceURL string, mediaTypePrefixes []string) string {
+	if dataURI, ok := s.resources[resourceURL]; ok {
+		return dataURI
+	}
+
+	if !s.canFetch(resourceURL) {
+		return resourceURL
+	}
+
+	s.nResources++
+
+	// Base64 encoding increases the size of the resource by a third.
+	body, contentType, _, err := s.client.get(s.ctx, resourceURL, s.budget*3/4)
+	if err != nil {
+		return resourceURL
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !slices.ContainsFunc(mediaTypePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(mediaType, prefix)
+	}) {
+		return resourceURL
+	}
+
+	dataURI := "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(body)
+	s.budget -= int64(len(dataURI))
+	s.resources[resourceURL] = dataURI
+
+	return dataURI
+}
+
+// canFetch returns whether a resource can be requested to be inlined in the snapshot.
+func (s *snapshot) canFetch(resourceURL string) bool {
+	if s.budget <= 0 || s.nResources >= maxResources {
+		return false
+	}
+
+	return strings.HasPrefix(resourceURL, "http://") || strings.HasPrefix(resourceURL, "https://")
+}
+
+// attr returns the value of a given attribute for a HTML element node.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// setAttr sets the value of a given attribute for a HTML element node.
+func setAttr(n *html.Node, key string, value string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// findElement returns the first element of a given type in a document, if any.
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// resolveURL resolves a (possibly relative) URL reference against a base URL.
+//
+// The reference is returned unchanged if it cannot be parsed.
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return base.ResolveReference(refURL).String()
+}
@@ -0,0 +1,85 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+const (
+	// textMaxLength bounds the length of the text extracted from a snapshot, in bytes,
+	// to keep it well under the size PostgreSQL accepts for a text search vector.
+	textMaxLength = 256 * 1024
+)
+
+// snippetMarkerStripper removes the characters highlighting search terms in snippets,
+// so that page content cannot inject highlights in search results.
+var snippetMarkerStripper = strings.NewReplacer(
+	bookmarkquerying.SnippetHighlightStart, "",
+	bookmarkquerying.SnippetHighlightStop, "",
+)
+
+// ExtractText returns the readable text of a page snapshot.
+//
+// Page boilerplate (navigation, headers, footers, sidebars and forms) is left out, as well
+// as the characters reserved to highlight search terms in snippets.
+func ExtractText(content []byte) string {
+	doc, err := html.ParseWithOptions(bytes.NewReader(content), html.ParseOptionEnableScripting(false))
+	if err != nil {
+		return ""
+	}
+
+	var text strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if text.Len() >= textMaxLength {
+			return
+		}
+
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg,
+				atom.Nav, atom.Header, atom.Footer, atom.Aside, atom.Form:
+				return
+			}
+		}
+
+		if n.Type == html.TextNode {
+			if data := strings.Join(strings.Fields(snippetMarkerStripper.Replace(n.Data)), " "); data != "" {
+				text.WriteString(data)
+				text.WriteString(" ")
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+
+	return truncateText(strings.TrimSpace(text.String()), textMaxLength)
+}
+
+// truncateText truncates a string to at most maxLength bytes, without splitting a UTF-8 sequence.
+func truncateText(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	return s[:end]
+}
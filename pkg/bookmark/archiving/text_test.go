@@ -0,0 +1,75 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package archiving
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestExtractText(t *testing.T) {
+	cases := []struct {
+		tname   string
+		content string
+		want    string
+	}{
+		{
+			tname:   "empty",
+			content: "",
+			want:    "",
+		},
+		{
+			tname: "body text",
+			content: `<!DOCTYPE html><html><head><title>Page title</title><style>p { color: red; }</style></head>
+<body>
+  <h1>Heading</h1>
+  <p>First   paragraph,
+  on two lines.</p>
+  <p>Second <em>paragraph</em>.</p>
+</body></html>`,
+			want: "Heading First paragraph, on two lines. Second paragraph .",
+		},
+		{
+			tname: "boilerplate is left out",
+			content: `<html><body>
+  <header>Site header</header>
+  <nav><a href="/">Home</a></nav>
+  <main><p>Article</p><script>var x = 1;</script><noscript>Enable JavaScript</noscript></main>
+  <aside>Related links</aside>
+  <form><label>Search</label></form>
+  <footer>Copyright</footer>
+</body></html>`,
+			want: "Article",
+		},
+		{
+			tname:   "snippet highlight markers are stripped",
+			content: "<html><body><p>\uE001</p><p>Fake \uE000highlight\uE001</p></body></html>",
+			want:    "Fake highlight",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := ExtractText([]byte(tc.content))
+
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractTextMaxLength(t *testing.T) {
+	content := "<html><body><p>" + strings.Repeat("é", textMaxLength) + "</p></body></html>"
+
+	got := ExtractText([]byte(content))
+
+	if len(got) > textMaxLength {
+		t.Errorf("want at most %d bytes, got %d", textMaxLength, len(got))
+	}
+	if !utf8.ValidString(got) {
+		t.Error("want valid UTF-8 text")
+	}
+}
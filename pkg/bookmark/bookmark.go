@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/segmentio/ksuid"
+
+	"github.com/virtualtam/sparklemuffin/internal/urlkit"
 )
 
 // Bookmark represents a Web bookmark.
@@ -25,6 +27,9 @@ type Bookmark struct {
 	Private bool
 	Tags    []string
 
+	// Link holds the result of the last check of the URL.
+	Link LinkCheck
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -42,11 +47,14 @@ func NewBookmark(userUUID string) *Bookmark {
 }
 
 // Normalize sanitizes and normalizes all fields.
-func (b *Bookmark) Normalize() {
+//
+// Tags are normalized according to the given policy, replacing aliases and folding
+// their case; the zero TagPolicy leaves them unchanged.
+func (b *Bookmark) Normalize(policy TagPolicy) {
 	b.normalizeURL()
 	b.normalizeTitle()
 	b.normalizeDescription()
-	b.normalizeTags()
+	b.normalizeTags(policy)
 	b.deduplicateTags()
 	b.sortTags()
 }
@@ -71,6 +79,15 @@ func (b *Bookmark) ValidateForAddition(ctx context.Context, r ValidationReposito
 	return nil
 }
 
+// ValidateURL ensures the Bookmark URL is set and valid.
+func (b *Bookmark) ValidateURL() error {
+	if err := b.requireURL(); err != nil {
+		return err
+	}
+
+	return b.ensureURLIsValid()
+}
+
 // ValidateForUpdate ensures mandatory fields are properly set when updating an
 // existing Bookmark.
 func (b *Bookmark) ValidateForUpdate(ctx context.Context, r Repository) error {
@@ -97,11 +114,11 @@ func (b *Bookmark) normalizeDescription() {
 	b.Description = strings.TrimSpace(b.Description)
 }
 
-func (b *Bookmark) normalizeTags() {
+func (b *Bookmark) normalizeTags(policy TagPolicy) {
 	var tags []string
 
 	for _, tag := range b.Tags {
-		tag := strings.TrimSpace(tag)
+		tag := policy.Apply(NormalizeTag(tag))
 		if tag == "" {
 			continue
 		}
@@ -137,8 +154,10 @@ func (b *Bookmark) normalizeTitle() {
 	b.Title = strings.TrimSpace(b.Title)
 }
 
+// normalizeURL canonicalizes the URL, so that tracking parameters are not saved and
+// URLs pointing to the same page are detected as duplicates.
 func (b *Bookmark) normalizeURL() {
-	b.URL = strings.TrimSpace(b.URL)
+	b.URL = urlkit.Canonicalize(b.URL)
 }
 
 func (b *Bookmark) generateUID() {
@@ -0,0 +1,206 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package bookmark
+
+import (
+	"slices"
+
+	"github.com/segmentio/ksuid"
+)
+
+// BulkEditAction represents a change applied to several bookmarks at once.
+type BulkEditAction string
+
+const (
+	// BulkEditActionAddTags adds tags to the selected bookmarks.
+	BulkEditActionAddTags BulkEditAction = "add_tags"
+
+	// BulkEditActionRemoveTags removes tags from the selected bookmarks.
+	BulkEditActionRemoveTags BulkEditAction = "remove_tags"
+
+	// BulkEditActionSetPrivate marks the selected bookmarks as private.
+	BulkEditActionSetPrivate BulkEditAction = "set_private"
+
+	// BulkEditActionSetPublic marks the selected bookmarks as public.
+	BulkEditActionSetPublic BulkEditAction = "set_public"
+
+	// BulkEditActionDelete deletes the selected bookmarks.
+	BulkEditActionDelete BulkEditAction = "delete"
+)
+
+var (
+	allBulkEditActions = []BulkEditAction{
+		BulkEditActionAddTags,
+		BulkEditActionRemoveTags,
+		BulkEditActionSetPrivate,
+		BulkEditActionSetPublic,
+		BulkEditActionDelete,
+	}
+)
+
+// BulkEditQuery represents a change applied to a selection of bookmarks of an authenticated
+// user.
+type BulkEditQuery struct {
+	UserUUID string
+	UIDs     []string
+	Action   BulkEditAction
+
+	// Tags holds the tags added or removed by BulkEditActionAddTags and
+	// BulkEditActionRemoveTags.
+	Tags []string
+}
+
+// BulkEditSummary reports the outcome of a BulkEditQuery.
+type BulkEditSummary struct {
+	// Selected is the number of selected bookmarks.
+	Selected int64
+
+	// Updated is the number of bookmarks that were changed.
+	Updated int64
+
+	// Deleted is the number of bookmarks that were deleted.
+	Deleted int64
+
+	// Bookmarks holds the updated or deleted bookmarks.
+	Bookmarks []Bookmark
+}
+
+// Unchanged returns the number of selected bookmarks that were neither updated nor deleted,
+// e.g. because they already had the tags to add.
+func (s BulkEditSummary) Unchanged() int64 {
+	return s.Selected - s.Updated - s.Deleted
+}
+
+// apply applies the change to a bookmark, and returns whether the bookmark was changed.
+//
+// Deleting a bookmark is not a change, and is handled by the repository.
+func (bq *BulkEditQuery) apply(b *Bookmark) bool {
+	switch bq.Action {
+	case BulkEditActionAddTags:
+		tags := slices.Clone(b.Tags)
+		for _, tag := range bq.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		if len(tags) == len(b.Tags) {
+			return false
+		}
+
+		b.Tags = tags
+		b.sortTags()
+
+	case BulkEditActionRemoveTags:
+		tags := slices.DeleteFunc(slices.Clone(b.Tags), func(tag string) bool {
+			return slices.Contains(bq.Tags, tag)
+		})
+
+		if len(tags) == len(b.Tags) {
+			return false
+		}
+
+		b.Tags = tags
+
+	case BulkEditActionSetPrivate, BulkEditActionSetPublic:
+		private := bq.Action == BulkEditActionSetPrivate
+
+		if b.Private == private {
+			return false
+		}
+
+		b.Private = private
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (bq *BulkEditQuery) hasTags() bool {
+	return bq.Action == BulkEditActionAddTags || bq.Action == BulkEditActionRemoveTags
+}
+
+// normalize deduplicates the selected UIDs, and normalizes tags.
+//
+// The tag policy is applied to tags being added, so that they are saved the same way as
+// tags typed when editing a bookmark.
+func (bq *BulkEditQuery) normalize(policy TagPolicy) {
+	var uids []string
+
+	for _, uid := range bq.UIDs {
+		if uid == "" || slices.Contains(uids, uid) {
+			continue
+		}
+
+		uids = append(uids, uid)
+	}
+
+	bq.UIDs = uids
+
+	if !bq.hasTags() {
+		bq.Tags = nil
+		return
+	}
+
+	var tags []string
+
+	for _, tag := range bq.Tags {
+		tag = NormalizeTag(tag)
+
+		if bq.Action == BulkEditActionAddTags {
+			tag = policy.Apply(tag)
+		}
+
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	bq.Tags = tags
+}
+
+func (bq *BulkEditQuery) requireUserUUID() error {
+	return requireUserUUID(bq.UserUUID)
+}
+
+func (bq *BulkEditQuery) requireUIDs() error {
+	if len(bq.UIDs) == 0 {
+		return ErrSelectionRequired
+	}
+	return nil
+}
+
+func (bq *BulkEditQuery) validateUIDs() error {
+	for _, uid := range bq.UIDs {
+		if _, err := ksuid.Parse(uid); err != nil {
+			return ErrUIDInvalid
+		}
+	}
+	return nil
+}
+
+func (bq *BulkEditQuery) validateAction() error {
+	if !slices.Contains(allBulkEditActions, bq.Action) {
+		return ErrBulkEditActionUnknown
+	}
+	return nil
+}
+
+func (bq *BulkEditQuery) requireTags() error {
+	if bq.hasTags() && len(bq.Tags) == 0 {
+		return newValidationError("tags", ErrTagNameRequired)
+	}
+	return nil
+}
+
+func (bq *BulkEditQuery) ensureTagsHaveNoWhitespace() error {
+	if slices.ContainsFunc(bq.Tags, whitespaceRegexp.MatchString) {
+		return newValidationError("tags", ErrTagNameContainsWhitespace)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package checking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+	"github.com/virtualtam/sparklemuffin/internal/urlkit"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	// responseBodyMaxBytes bounds how much of the response body is read before closing it.
+	responseBodyMaxBytes = 64 * 1024
+
+	// maxRedirects bounds the number of redirects followed when checking a URL.
+	maxRedirects = 10
+)
+
+// A Client performs outgoing HTTP requests to check bookmark URLs.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient initializes and returns a Client using httpClient to perform requests as-is.
+//
+// This client must only be used when HTTP/HTTPS traffic goes through a proxy, or for testing.
+func NewClient(httpClient *http.Client, userAgent string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		userAgent:  userAgent,
+	}
+}
+
+// NewSafeClient initializes and returns a Client backed by an SSRF-guarded http.Client.
+//
+// It MUST NOT be used when HTTP/HTTPS traffic goes through a proxy (otherwise all requests will be blocked).
+func NewSafeClient(userAgent string, timeout time.Duration) (*Client, error) {
+	transport, err := httpsafe.NewSafeTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+
+	return NewClient(httpClient, userAgent), nil
+}
+
+// Check requests a URL, following redirects, and returns the outcome.
+//
+// A HEAD request is attempted first; as some servers do not support HEAD requests, or
+// respond to them with an error, a GET request is attempted if it fails.
+//
+// A URL responding with a 404 Not Found or 410 Gone status is reported as dead; as this
+// may be a temporary mistake of the site, the Service only considers it dead after several
+// consecutive checks. Network errors, rate limiting and server errors may be temporary too,
+// and are reported as unreachable.
+func (c *Client) Check(ctx context.Context, rawURL string) bookmark.LinkCheck {
+	check := bookmark.LinkCheck{
+		CheckedAt: time.Now().UTC(),
+	}
+
+	statusCode, finalURL, err := c.request(ctx, http.MethodHead, rawURL)
+	if err != nil || statusCode >= http.StatusBadRequest {
+		statusCode, finalURL, err = c.request(ctx, http.MethodGet, rawURL)
+	}
+
+	switch {
+	case errors.Is(err, httpsafe.ErrIPBlocked):
+		check.Status = bookmark.LinkStatusSkipped
+
+	case err != nil:
+		check.Status = bookmark.LinkStatusUnreachable
+		check.Error = err.Error()
+
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		check.Status = bookmark.LinkStatusDead
+		check.StatusCode = statusCode
+
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		check.Status = bookmark.LinkStatusForbidden
+		check.StatusCode = statusCode
+
+	case statusCode >= http.StatusBadRequest:
+		check.Status = bookmark.LinkStatusUnreachable
+		check.StatusCode = statusCode
+
+	case urlkit.Canonicalize(finalURL) != urlkit.Canonicalize(rawURL):
+		check.Status = bookmark.LinkStatusRedirected
+		check.StatusCode = statusCode
+		check.RedirectURL = finalURL
+
+	default:
+		check.Status = bookmark.LinkStatusAlive
+		check.StatusCode = statusCode
+	}
+
+	return check
+}
+
+// request performs an HTTP request, and returns the status code and URL of the final response.
+func (c *Client) request(ctx context.Context, method string, rawURL string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, responseBodyMaxBytes))
+
+	return resp.StatusCode, resp.Request.URL.String(), nil
+}
@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package checking periodically checks bookmark URLs to detect dead and redirected links.
+package checking
@@ -0,0 +1,26 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package checking
+
+import (
+	"context"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+// Repository provides access to bookmark link checks.
+type Repository interface {
+	// BookmarkLinkGetNToCheck returns at most n bookmarks, for all users, that have never
+	// been checked, or were last checked before a given time; bookmarks that have never
+	// been checked are returned first.
+	BookmarkLinkGetNToCheck(ctx context.Context, n uint, checkedBefore time.Time) ([]bookmark.Bookmark, error)
+
+	// BookmarkLinkGetAllByStatus returns all bookmarks for a given user and link status.
+	BookmarkLinkGetAllByStatus(ctx context.Context, userUUID string, status bookmark.LinkStatus) ([]bookmark.Bookmark, error)
+
+	// BookmarkLinkUpdate saves the result of a link check, provided the bookmark's URL
+	// has not changed since it was checked.
+	BookmarkLinkUpdate(ctx context.Context, b bookmark.Bookmark) error
+}
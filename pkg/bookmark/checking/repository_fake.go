@@ -0,0 +1,63 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package checking
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository stores bookmarks in memory, and shares them with a bookmark.FakeRepository
+// so that it can back both a link checking Service and a bookmark Service.
+type FakeRepository struct {
+	*bookmark.FakeRepository
+}
+
+func (r *FakeRepository) BookmarkLinkGetNToCheck(_ context.Context, n uint, checkedBefore time.Time) ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	for _, b := range r.Bookmarks {
+		if b.Link.CheckedAt.IsZero() || b.Link.CheckedAt.Before(checkedBefore) {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Link.CheckedAt.Before(bookmarks[j].Link.CheckedAt)
+	})
+
+	if uint(len(bookmarks)) > n {
+		bookmarks = bookmarks[:n]
+	}
+
+	return bookmarks, nil
+}
+
+func (r *FakeRepository) BookmarkLinkGetAllByStatus(_ context.Context, userUUID string, status bookmark.LinkStatus) ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == userUUID && b.Link.Status == status {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+func (r *FakeRepository) BookmarkLinkUpdate(_ context.Context, b bookmark.Bookmark) error {
+	for index, existing := range r.Bookmarks {
+		if existing.UserUUID == b.UserUUID && existing.UID == b.UID && existing.URL == b.URL {
+			r.Bookmarks[index].Link = b.Link
+			return nil
+		}
+	}
+
+	return nil
+}
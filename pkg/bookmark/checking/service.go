@@ -0,0 +1,197 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package checking
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	linksToCheck uint = 50
+	minLinkAge        = 7 * 24 * time.Hour
+
+	// deadLinkThreshold is the number of consecutive checks for which a URL must respond
+	// with a 404 Not Found or 410 Gone status before it is considered dead.
+	deadLinkThreshold int = 3
+
+	nWorkers int = 5
+)
+
+// Service handles bookmark link checking operations.
+type Service struct {
+	r               Repository
+	bookmarkService *bookmark.Service
+	client          *Client
+}
+
+// NewService initializes and returns a new link checking Service.
+func NewService(r Repository, bookmarkService *bookmark.Service, client *Client) *Service {
+	return &Service{
+		r:               r,
+		bookmarkService: bookmarkService,
+		client:          client,
+	}
+}
+
+// Check checks the URLs of bookmarks that have never been checked, or were last checked
+// a while ago, for all users.
+func (s *Service) Check(ctx context.Context, jobID string) error {
+	checkedBefore := time.Now().UTC().Add(-minLinkAge)
+
+	bookmarks, err := s.r.BookmarkLinkGetNToCheck(ctx, linksToCheck, checkedBefore)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to list links to check")
+		return err
+	}
+
+	if len(bookmarks) == 0 {
+		log.Debug().Str("job_id", jobID).Msg("bookmarks: no links to check")
+		return nil
+	}
+
+	workerPool := pool.New().WithErrors().WithMaxGoroutines(nWorkers)
+
+	for _, b := range bookmarks {
+		workerPool.Go(func() error {
+			return s.checkBookmark(ctx, b, jobID)
+		})
+	}
+
+	if err := workerPool.Wait(); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to check some links")
+		return err
+	}
+
+	log.
+		Info().
+		Int("n_links", len(bookmarks)).
+		Str("job_id", jobID).
+		Msg("bookmarks: links checked")
+
+	return nil
+}
+
+func (s *Service) checkBookmark(ctx context.Context, b bookmark.Bookmark, jobID string) error {
+	b.Link = confirmDeadLink(b.Link, s.client.Check(ctx, b.URL))
+
+	log.
+		Debug().
+		Str("bookmark_uid", b.UID).
+		Str("job_id", jobID).
+		Str("link_status", string(b.Link.Status)).
+		Int("status_code", b.Link.StatusCode).
+		Msg("bookmarks: link checked")
+
+	if err := s.r.BookmarkLinkUpdate(ctx, b); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("bookmark_uid", b.UID).
+			Str("job_id", jobID).
+			Msg("bookmarks: failed to save link check")
+		return err
+	}
+
+	return nil
+}
+
+// confirmDeadLink counts consecutive checks reporting a URL as dead, and only reports it
+// as dead once deadLinkThreshold is reached; until then, the URL is reported as
+// unreachable.
+//
+// Bookmarks with a dead link can be deleted in bulk: this avoids deleting bookmarks whose
+// site was temporarily misconfigured.
+func confirmDeadLink(previous bookmark.LinkCheck, check bookmark.LinkCheck) bookmark.LinkCheck {
+	if check.Status != bookmark.LinkStatusDead {
+		check.NotFoundCount = 0
+		return check
+	}
+
+	check.NotFoundCount = previous.NotFoundCount + 1
+
+	if check.NotFoundCount < deadLinkThreshold {
+		check.Status = bookmark.LinkStatusUnreachable
+	}
+
+	return check
+}
+
+// RedirectUpdateResult summarizes the update of redirected bookmarks to their redirect URL.
+type RedirectUpdateResult struct {
+	// Updated lists the bookmarks whose URL has been updated.
+	Updated []bookmark.Bookmark
+
+	// Conflicts is the number of bookmarks that were left unchanged, as the user has
+	// already saved a bookmark for their redirect URL.
+	Conflicts int
+}
+
+// UpdateRedirected updates the URL of all redirected bookmarks for a given user to the
+// URL they redirect to.
+func (s *Service) UpdateRedirected(ctx context.Context, userUUID string) (RedirectUpdateResult, error) {
+	bookmarks, err := s.r.BookmarkLinkGetAllByStatus(ctx, userUUID, bookmark.LinkStatusRedirected)
+	if err != nil {
+		return RedirectUpdateResult{}, err
+	}
+
+	var result RedirectUpdateResult
+
+	for _, b := range bookmarks {
+		b.URL = b.Link.RedirectURL
+
+		err := s.bookmarkService.Update(ctx, b)
+		if errors.Is(err, bookmark.ErrURLAlreadyRegistered) {
+			result.Conflicts++
+			continue
+		}
+		if err != nil {
+			return RedirectUpdateResult{}, err
+		}
+
+		updated, err := s.bookmarkService.ByUID(ctx, userUUID, b.UID)
+		if err != nil {
+			return RedirectUpdateResult{}, err
+		}
+
+		result.Updated = append(result.Updated, updated)
+	}
+
+	return result, nil
+}
+
+// DeleteDead permanently deletes all bookmarks with a dead link for a given user, and
+// returns the deleted bookmarks.
+func (s *Service) DeleteDead(ctx context.Context, userUUID string) ([]bookmark.Bookmark, error) {
+	bookmarks, err := s.r.BookmarkLinkGetAllByStatus(ctx, userUUID, bookmark.LinkStatusDead)
+	if err != nil {
+		return []bookmark.Bookmark{}, err
+	}
+
+	var deleted []bookmark.Bookmark
+
+	for _, b := range bookmarks {
+		if err := s.bookmarkService.Delete(ctx, userUUID, b.UID); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, b)
+	}
+
+	return deleted, nil
+}
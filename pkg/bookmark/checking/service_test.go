@@ -0,0 +1,376 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package checking
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	testUserUUID = "5d75c769-059c-4b36-9db6-1c82619e704a"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/alive", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/forbidden", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/rate-limited", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/server-error", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/alive", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/slash", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/slash/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/slash/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServiceCheck(t *testing.T) {
+	server := newTestServer(t)
+
+	cases := []struct {
+		tname             string
+		path              string
+		previous          bookmark.LinkCheck
+		wantStatus        bookmark.LinkStatus
+		wantStatusCode    int
+		wantRedirect      string
+		wantNotFoundCount int
+	}{
+		{
+			tname:          "alive",
+			path:           "/alive",
+			wantStatus:     bookmark.LinkStatusAlive,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			tname:          "HEAD not allowed",
+			path:           "/no-head",
+			wantStatus:     bookmark.LinkStatusAlive,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			tname:             "gone",
+			path:              "/gone",
+			wantStatus:        bookmark.LinkStatusUnreachable,
+			wantStatusCode:    http.StatusGone,
+			wantNotFoundCount: 1,
+		},
+		{
+			tname:             "not found",
+			path:              "/not-found",
+			wantStatus:        bookmark.LinkStatusUnreachable,
+			wantStatusCode:    http.StatusNotFound,
+			wantNotFoundCount: 1,
+		},
+		{
+			tname: "not found, repeatedly",
+			path:  "/not-found",
+			previous: bookmark.LinkCheck{
+				Status:        bookmark.LinkStatusUnreachable,
+				StatusCode:    http.StatusNotFound,
+				NotFoundCount: 2,
+			},
+			wantStatus:        bookmark.LinkStatusDead,
+			wantStatusCode:    http.StatusNotFound,
+			wantNotFoundCount: 3,
+		},
+		{
+			tname: "alive after not found",
+			path:  "/alive",
+			previous: bookmark.LinkCheck{
+				Status:        bookmark.LinkStatusUnreachable,
+				StatusCode:    http.StatusNotFound,
+				NotFoundCount: 2,
+			},
+			wantStatus:     bookmark.LinkStatusAlive,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			tname: "server error after not found",
+			path:  "/server-error",
+			previous: bookmark.LinkCheck{
+				Status:        bookmark.LinkStatusUnreachable,
+				StatusCode:    http.StatusNotFound,
+				NotFoundCount: 2,
+			},
+			wantStatus:     bookmark.LinkStatusUnreachable,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			tname:          "forbidden",
+			path:           "/forbidden",
+			wantStatus:     bookmark.LinkStatusForbidden,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			tname:          "rate limited",
+			path:           "/rate-limited",
+			wantStatus:     bookmark.LinkStatusUnreachable,
+			wantStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			tname:          "server error",
+			path:           "/server-error",
+			wantStatus:     bookmark.LinkStatusUnreachable,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			tname:          "redirected",
+			path:           "/moved",
+			wantStatus:     bookmark.LinkStatusRedirected,
+			wantStatusCode: http.StatusOK,
+			wantRedirect:   server.URL + "/alive",
+		},
+		{
+			tname:          "redirected to the canonical URL",
+			path:           "/slash",
+			wantStatus:     bookmark.LinkStatusAlive,
+			wantStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			b := bookmark.Bookmark{
+				UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+				UserUUID: testUserUUID,
+				URL:      server.URL + tc.path,
+				Link:     bookmark.LinkCheck{Status: bookmark.LinkStatusUnchecked},
+			}
+			if tc.previous.Status != "" {
+				b.Link = tc.previous
+				b.Link.CheckedAt = time.Now().UTC().Add(-2 * minLinkAge)
+			}
+
+			r := &FakeRepository{
+				FakeRepository: &bookmark.FakeRepository{
+					Bookmarks: []bookmark.Bookmark{b},
+				},
+			}
+			s := NewService(r, bookmark.NewService(r.FakeRepository, nil), NewClient(server.Client(), "sparklemuffin-test"))
+
+			if err := s.Check(t.Context(), "test"); err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			got := r.Bookmarks[0].Link
+
+			if got.Status != tc.wantStatus {
+				t.Errorf("want status %q, got %q", tc.wantStatus, got.Status)
+			}
+
+			if got.StatusCode != tc.wantStatusCode {
+				t.Errorf("want status code %d, got %d", tc.wantStatusCode, got.StatusCode)
+			}
+
+			if got.RedirectURL != tc.wantRedirect {
+				t.Errorf("want redirect URL %q, got %q", tc.wantRedirect, got.RedirectURL)
+			}
+
+			if got.NotFoundCount != tc.wantNotFoundCount {
+				t.Errorf("want not found count %d, got %d", tc.wantNotFoundCount, got.NotFoundCount)
+			}
+
+			if got.CheckedAt.IsZero() {
+				t.Error("want checked at to be set")
+			}
+		})
+	}
+}
+
+func TestServiceCheckRecentlyChecked(t *testing.T) {
+	server := newTestServer(t)
+	checkedAt := time.Now().UTC().Add(-1 * time.Hour)
+
+	r := &FakeRepository{
+		FakeRepository: &bookmark.FakeRepository{
+			Bookmarks: []bookmark.Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: testUserUUID,
+					URL:      server.URL + "/gone",
+					Link: bookmark.LinkCheck{
+						Status:     bookmark.LinkStatusAlive,
+						StatusCode: http.StatusOK,
+						CheckedAt:  checkedAt,
+					},
+				},
+			},
+		},
+	}
+	s := NewService(r, bookmark.NewService(r.FakeRepository, nil), NewClient(server.Client(), "sparklemuffin-test"))
+
+	if err := s.Check(t.Context(), "test"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	got := r.Bookmarks[0].Link
+
+	if got.Status != bookmark.LinkStatusAlive {
+		t.Errorf("want status %q, got %q", bookmark.LinkStatusAlive, got.Status)
+	}
+
+	if !got.CheckedAt.Equal(checkedAt) {
+		t.Errorf("want checked at %q, got %q", checkedAt, got.CheckedAt)
+	}
+}
+
+func TestServiceUpdateRedirected(t *testing.T) {
+	r := &FakeRepository{
+		FakeRepository: &bookmark.FakeRepository{
+			Bookmarks: []bookmark.Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: testUserUUID,
+					Title:    "Moved",
+					URL:      "https://domain.tld/moved",
+					Link: bookmark.LinkCheck{
+						Status:      bookmark.LinkStatusRedirected,
+						StatusCode:  http.StatusOK,
+						RedirectURL: "https://domain.tld/new-location/?utm_source=redirect",
+					},
+				},
+				{
+					UID:      "27L4GHH8fHrbcmYkHUqEVKRsBsS",
+					UserUUID: testUserUUID,
+					Title:    "Moved to an existing bookmark",
+					URL:      "https://domain.tld/old",
+					Link: bookmark.LinkCheck{
+						Status:      bookmark.LinkStatusRedirected,
+						StatusCode:  http.StatusOK,
+						RedirectURL: "https://domain.tld/existing",
+					},
+				},
+				{
+					UID:      "27L4IbqUOJMDmgtTiqBGrUaI4RL",
+					UserUUID: testUserUUID,
+					Title:    "Existing",
+					URL:      "https://domain.tld/existing",
+					Link: bookmark.LinkCheck{
+						Status:     bookmark.LinkStatusAlive,
+						StatusCode: http.StatusOK,
+					},
+				},
+			},
+		},
+	}
+	s := NewService(r, bookmark.NewService(r.FakeRepository, nil), nil)
+
+	got, err := s.UpdateRedirected(t.Context(), testUserUUID)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(got.Updated) != 1 {
+		t.Fatalf("want 1 updated bookmark, got %d", len(got.Updated))
+	}
+
+	if got.Conflicts != 1 {
+		t.Errorf("want 1 conflict, got %d", got.Conflicts)
+	}
+
+	updated := r.Bookmarks[0]
+
+	if updated.URL != "https://domain.tld/new-location" {
+		t.Errorf("want URL %q, got %q", "https://domain.tld/new-location", updated.URL)
+	}
+
+	if updated.Link.Status != bookmark.LinkStatusUnchecked {
+		t.Errorf("want link status %q, got %q", bookmark.LinkStatusUnchecked, updated.Link.Status)
+	}
+
+	if r.Bookmarks[1].URL != "https://domain.tld/old" {
+		t.Errorf("want conflicting bookmark URL to be unchanged, got %q", r.Bookmarks[1].URL)
+	}
+}
+
+func TestServiceDeleteDead(t *testing.T) {
+	r := &FakeRepository{
+		FakeRepository: &bookmark.FakeRepository{
+			Bookmarks: []bookmark.Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: testUserUUID,
+					URL:      "https://domain.tld/gone",
+					Link: bookmark.LinkCheck{
+						Status:     bookmark.LinkStatusDead,
+						StatusCode: http.StatusGone,
+					},
+				},
+				{
+					UID:      "27L4GHH8fHrbcmYkHUqEVKRsBsS",
+					UserUUID: testUserUUID,
+					URL:      "https://domain.tld/alive",
+					Link: bookmark.LinkCheck{
+						Status:     bookmark.LinkStatusAlive,
+						StatusCode: http.StatusOK,
+					},
+				},
+				{
+					UID:      "27L4IbqUOJMDmgtTiqBGrUaI4RL",
+					UserUUID: "218d03f8-976c-4387-9d74-95ed656e3921",
+					URL:      "https://domain.tld/gone",
+					Link: bookmark.LinkCheck{
+						Status:     bookmark.LinkStatusDead,
+						StatusCode: http.StatusGone,
+					},
+				},
+			},
+		},
+	}
+	s := NewService(r, bookmark.NewService(r.FakeRepository, nil), nil)
+
+	got, err := s.DeleteDead(t.Context(), testUserUUID)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("want 1 deleted bookmark, got %d", len(got))
+	}
+
+	if len(r.Bookmarks) != 2 {
+		t.Fatalf("want 2 remaining bookmarks, got %d", len(r.Bookmarks))
+	}
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == testUserUUID && b.Link.Status == bookmark.LinkStatusDead {
+			t.Errorf("want dead bookmark %q to be deleted", b.UID)
+		}
+	}
+}
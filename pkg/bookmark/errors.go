@@ -9,9 +9,17 @@ import (
 )
 
 var (
+	ErrBulkEditActionUnknown     = errors.New("bookmark: unknown bulk edit action")
+	ErrLinkStatusInvalid         = errors.New("bookmark: invalid link status")
 	ErrNotFound                  = errors.New("bookmark: not found")
+	ErrSelectionRequired         = errors.New("bookmark: no bookmarks selected")
+	ErrTagAliasNotFound          = errors.New("bookmark: tag alias not found")
+	ErrTagAliasSameAsTag         = errors.New("bookmark: tag alias applies to its own tag")
+	ErrTagCaseFoldingUnknown     = errors.New("bookmark: unknown tag case folding")
+	ErrTagMergeSourcesRequired   = errors.New("bookmark: tags to merge required")
 	ErrTagNameContainsWhitespace = errors.New("bookmark: tag name contains whitespace")
 	ErrTagNameRequired           = errors.New("bookmark: tag name required")
+	ErrTagPreferencesNotFound    = errors.New("bookmark: tag preferences not found")
 	ErrTitleRequired             = errors.New("bookmark: title required")
 	ErrUIDInvalid                = errors.New("bookmark: invalid UID")
 	ErrUIDRequired               = errors.New("bookmark: UID required")
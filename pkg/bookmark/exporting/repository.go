@@ -18,4 +18,7 @@ type Repository interface {
 
 	// BookmarkGetAllPublic returns all public bookmarks for a given user UUID.
 	BookmarkGetAllPublic(ctx context.Context, userUUID string) ([]bookmark.Bookmark, error)
+
+	// BookmarkGetByUIDs returns the bookmarks for a given user UUID and a list of UIDs.
+	BookmarkGetByUIDs(ctx context.Context, userUUID string, uids []string) ([]bookmark.Bookmark, error)
 }
@@ -5,6 +5,7 @@ package exporting
 
 import (
 	"context"
+	"slices"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
 )
@@ -50,3 +51,15 @@ func (r *FakeRepository) BookmarkGetAllPublic(_ context.Context, userUUID string
 
 	return bookmarks, nil
 }
+
+func (r *FakeRepository) BookmarkGetByUIDs(_ context.Context, userUUID string, uids []string) ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == userUUID && slices.Contains(uids, b.UID) {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	return bookmarks, nil
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
 )
 
+const (
+	selectionDocumentTitle = "SparkleMuffin export of selected bookmarks"
+)
+
 // Service handles bookmark export operations.
 type Service struct {
 	r Repository
@@ -25,6 +29,14 @@ func NewService(r Repository) *Service {
 	}
 }
 
+func (s *Service) getSelectedBookmarks(ctx context.Context, userUUID string, uids []string) ([]bookmark.Bookmark, error) {
+	if len(uids) == 0 {
+		return []bookmark.Bookmark{}, bookmark.ErrSelectionRequired
+	}
+
+	return s.r.BookmarkGetByUIDs(ctx, userUUID, uids)
+}
+
 func (s *Service) getBookmarks(ctx context.Context, userUUID string, visibility Visibility) ([]bookmark.Bookmark, error) {
 	switch visibility {
 	case VisibilityAll:
@@ -46,11 +58,46 @@ func (s *Service) ExportAsJSONDocument(ctx context.Context, userUUID string, vis
 		return &JsonDocument{}, err
 	}
 
-	now := time.Now().UTC()
+	return newJSONDocument(fmt.Sprintf("SparkleMuffin export of %s bookmarks", visibility), bookmarks), nil
+}
 
+// ExportAsNetscapeDocument exports a given user's bookmarks matching the
+// provided Visibility as a Netscape bookmark document.
+func (s *Service) ExportAsNetscapeDocument(ctx context.Context, userUUID string, visibility Visibility) (*netscape.Document, error) {
+	bookmarks, err := s.getBookmarks(ctx, userUUID, visibility)
+	if err != nil {
+		return &netscape.Document{}, err
+	}
+
+	return newNetscapeDocument(fmt.Sprintf("SparkleMuffin export of %s bookmarks", visibility), bookmarks), nil
+}
+
+// ExportSelectionAsJSONDocument exports a selection of a given user's bookmarks
+// as a JSON bookmark document.
+func (s *Service) ExportSelectionAsJSONDocument(ctx context.Context, userUUID string, uids []string) (*JsonDocument, error) {
+	bookmarks, err := s.getSelectedBookmarks(ctx, userUUID, uids)
+	if err != nil {
+		return &JsonDocument{}, err
+	}
+
+	return newJSONDocument(selectionDocumentTitle, bookmarks), nil
+}
+
+// ExportSelectionAsNetscapeDocument exports a selection of a given user's bookmarks
+// as a Netscape bookmark document.
+func (s *Service) ExportSelectionAsNetscapeDocument(ctx context.Context, userUUID string, uids []string) (*netscape.Document, error) {
+	bookmarks, err := s.getSelectedBookmarks(ctx, userUUID, uids)
+	if err != nil {
+		return &netscape.Document{}, err
+	}
+
+	return newNetscapeDocument(selectionDocumentTitle, bookmarks), nil
+}
+
+func newJSONDocument(title string, bookmarks []bookmark.Bookmark) *JsonDocument {
 	document := &JsonDocument{
-		Title:      fmt.Sprintf("SparkleMuffin export of %s bookmarks", visibility),
-		ExportedAt: now,
+		Title:      title,
+		ExportedAt: time.Now().UTC(),
 	}
 
 	for _, b := range bookmarks {
@@ -67,22 +114,14 @@ func (s *Service) ExportAsJSONDocument(ctx context.Context, userUUID string, vis
 		document.Bookmarks = append(document.Bookmarks, jsonBookmark)
 	}
 
-	return document, nil
+	return document
 }
 
-// ExportAsNetscapeDocument exports a given user's bookmarks matching the
-// provided Visibility as a Netscape bookmark document.
-func (s *Service) ExportAsNetscapeDocument(ctx context.Context, userUUID string, visibility Visibility) (*netscape.Document, error) {
-	bookmarks, err := s.getBookmarks(ctx, userUUID, visibility)
-	if err != nil {
-		return &netscape.Document{}, err
-	}
-
-	documentTitle := fmt.Sprintf("SparkleMuffin export of %s bookmarks", visibility)
+func newNetscapeDocument(title string, bookmarks []bookmark.Bookmark) *netscape.Document {
 	document := &netscape.Document{
-		Title: documentTitle,
+		Title: title,
 		Root: netscape.Folder{
-			Name: documentTitle,
+			Name: title,
 		},
 	}
 
@@ -100,5 +139,5 @@ func (s *Service) ExportAsNetscapeDocument(ctx context.Context, userUUID string,
 		document.Root.Bookmarks = append(document.Root.Bookmarks, netscapeBookmark)
 	}
 
-	return document, nil
+	return document
 }
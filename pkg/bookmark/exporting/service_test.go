@@ -321,3 +321,101 @@ func TestServiceExportAsNetscapeDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceExportSelectionAsJSONDocument(t *testing.T) {
+	now := time.Now().UTC()
+
+	selectionBookmarks := []bookmark.Bookmark{
+		{
+			UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+			UserUUID: "5d75c769-059c-4b36-9db6-1c82619e704a",
+			Title:    "Bookmark 1",
+			URL:      "https://example1.tld",
+		},
+		{
+			UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+			UserUUID: "5d75c769-059c-4b36-9db6-1c82619e704a",
+			Title:    "Bookmark 2 (private)",
+			URL:      "https://example2.tld",
+			Tags:     []string{"example"},
+			Private:  true,
+		},
+		{
+			UID:      "2Ecp9wXhdPNxUyFbtaRTwkzUGdX",
+			UserUUID: "218d03f8-976c-4387-9d74-95ed656e3921",
+			Title:    "Other user's bookmark",
+			URL:      "https://test.co.uk",
+		},
+	}
+
+	cases := []struct {
+		tname string
+
+		userUUID string
+		uids     []string
+
+		want    *JsonDocument
+		wantErr error
+	}{
+		// error cases
+		{
+			tname:    "no bookmarks selected",
+			userUUID: "5d75c769-059c-4b36-9db6-1c82619e704a",
+			wantErr:  bookmark.ErrSelectionRequired,
+		},
+
+		// nominal cases
+		{
+			tname:    "export selected bookmarks",
+			userUUID: "5d75c769-059c-4b36-9db6-1c82619e704a",
+			uids:     []string{"2Ecp9vyGeHJ9rLMNxMn6cAYrTun", "2Ecp9wXhdPNxUyFbtaRTwkzUGdX"},
+			want: &JsonDocument{
+				Title:      "SparkleMuffin export of selected bookmarks",
+				ExportedAt: now,
+				Bookmarks: []JsonBookmark{
+					{
+						Title:   "Bookmark 2 (private)",
+						URL:     "https://example2.tld",
+						Tags:    []string{"example"},
+						Private: true,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks: selectionBookmarks,
+			}
+			s := NewService(r)
+
+			got, err := s.ExportSelectionAsJSONDocument(t.Context(), tc.userUUID, tc.uids)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.Title != tc.want.Title {
+				t.Errorf("want Title %q, got %q", tc.want.Title, got.Title)
+			}
+
+			assert.TimeAlmostEquals(t, "ExportedAt", got.ExportedAt, tc.want.ExportedAt, assert.TimeComparisonDelta)
+
+			if !reflect.DeepEqual(got.Bookmarks, tc.want.Bookmarks) {
+				t.Errorf("want exported bookmarks %#v, got %#v", tc.want.Bookmarks, got.Bookmarks)
+			}
+		})
+	}
+}
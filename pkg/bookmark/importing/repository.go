@@ -24,10 +24,18 @@ func (r *validationRepository) BookmarkIsURLRegisteredToAnotherUID(_ context.Con
 }
 
 type Repository interface {
-	// BookmarkAddMany adds a collection of new bookmarks.
-	BookmarkAddMany(ctx context.Context, bookmarks []bookmark.Bookmark) (int64, error)
+	bookmark.TagPolicyRepository
 
-	// BookmarkUpsertMany adds a collection of new bookmarks and updates
+	// BookmarkAddMany adds a collection of new bookmarks for a given user.
+	//
+	// No bookmark is added if the new bookmarks would exceed maxBookmarks, in which case
+	// quota.ErrBookmarkLimitReached is returned; a zero maxBookmarks means the number of
+	// bookmarks is unlimited.
+	BookmarkAddMany(ctx context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error)
+
+	// BookmarkUpsertMany adds a collection of new bookmarks for a given user and updates
 	// existing bookmarks in case of conflict.
-	BookmarkUpsertMany(ctx context.Context, bookmarks []bookmark.Bookmark) (int64, error)
+	//
+	// Updated bookmarks do not count towards maxBookmarks.
+	BookmarkUpsertMany(ctx context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error)
 }
@@ -7,28 +7,74 @@ import (
 	"context"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 var _ Repository = &FakeRepository{}
 
 type FakeRepository struct {
-	Bookmarks []bookmark.Bookmark
+	Bookmarks      []bookmark.Bookmark
+	TagAliases     []bookmark.TagAlias
+	TagPreferences []bookmark.TagPreferences
 }
 
-func (r *FakeRepository) BookmarkAddMany(_ context.Context, bookmarks []bookmark.Bookmark) (int64, error) {
-	return r.bookmarkUpsertMany(bookmarks, false)
+func (r *FakeRepository) BookmarkAddMany(_ context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error) {
+	return r.bookmarkUpsertMany(userUUID, bookmarks, maxBookmarks, false)
 }
 
-func (r *FakeRepository) BookmarkUpsertMany(_ context.Context, bookmarks []bookmark.Bookmark) (int64, error) {
-	return r.bookmarkUpsertMany(bookmarks, true)
+func (r *FakeRepository) BookmarkTagAliasGetAll(_ context.Context, userUUID string) ([]bookmark.TagAlias, error) {
+	var aliases []bookmark.TagAlias
+
+	for _, alias := range r.TagAliases {
+		if alias.UserUUID == userUUID {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases, nil
+}
+
+func (r *FakeRepository) BookmarkTagPreferencesGetByUserUUID(_ context.Context, userUUID string) (bookmark.TagPreferences, error) {
+	for _, preferences := range r.TagPreferences {
+		if preferences.UserUUID == userUUID {
+			return preferences, nil
+		}
+	}
+
+	return bookmark.TagPreferences{}, bookmark.ErrTagPreferencesNotFound
+}
+
+func (r *FakeRepository) BookmarkUpsertMany(_ context.Context, userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64) (int64, error) {
+	return r.bookmarkUpsertMany(userUUID, bookmarks, maxBookmarks, true)
 }
 
-func (r *FakeRepository) bookmarkUpsertMany(bookmarks []bookmark.Bookmark, overwriteExisting bool) (int64, error) {
+func (r *FakeRepository) bookmarkUpsertMany(userUUID string, bookmarks []bookmark.Bookmark, maxBookmarks int64, overwriteExisting bool) (int64, error) {
 	uniqueURLs := map[string]int{}
 	for index, b := range r.Bookmarks {
 		uniqueURLs[b.URL] = index
 	}
 
+	var current, newURLs int64
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == userUUID {
+			current++
+		}
+	}
+	for _, b := range bookmarks {
+		registered, err := r.BookmarkIsURLRegistered(userUUID, b.URL)
+		if err != nil {
+			return 0, err
+		}
+
+		if !registered {
+			newURLs++
+		}
+	}
+
+	if err := quota.CheckBookmarkCount(maxBookmarks, current, newURLs); err != nil {
+		return 0, err
+	}
+
 	var newOrUpdated int64
 
 	for _, b := range bookmarks {
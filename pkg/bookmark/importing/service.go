@@ -9,23 +9,67 @@ import (
 	"github.com/virtualtam/netscape-go/v2"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 // Service handles bookmark import operations.
 type Service struct {
-	r  Repository
-	vr bookmark.ValidationRepository
+	r            Repository
+	vr           bookmark.ValidationRepository
+	quotaService *quota.Service
 }
 
 // NewService initializes and returns a new Service.
-func NewService(r Repository) *Service {
+//
+// quotaService may be nil, in which case imports are not limited.
+func NewService(r Repository, quotaService *quota.Service) *Service {
 	return &Service{
-		r:  r,
-		vr: &validationRepository{},
+		r:            r,
+		vr:           &validationRepository{},
+		quotaService: quotaService,
+	}
+}
+
+// MaxImportFileSize returns the maximum size of an import file for a given user, in bytes.
+//
+// A zero value means the size of import files is not limited.
+func (s *Service) MaxImportFileSize(ctx context.Context, userUUID string) (int64, error) {
+	if s.quotaService == nil {
+		return 0, nil
+	}
+
+	limits, err := s.quotaService.LimitsByUserUUID(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	return limits.MaxImportFileSize, nil
+}
+
+// ValidateImportFileSize ensures a given user may import a file of the given size, in bytes.
+func (s *Service) ValidateImportFileSize(ctx context.Context, userUUID string, size int64) error {
+	if s.quotaService == nil {
+		return nil
+	}
+
+	return s.quotaService.CheckImportFileSize(ctx, userUUID, size)
+}
+
+// maxBookmarks returns the maximum number of bookmarks a given user may store.
+func (s *Service) maxBookmarks(ctx context.Context, userUUID string) (int64, error) {
+	if s.quotaService == nil {
+		return 0, nil
+	}
+
+	limits, err := s.quotaService.LimitsByUserUUID(ctx, userUUID)
+	if err != nil {
+		return 0, err
 	}
+
+	return limits.MaxBookmarks, nil
 }
 
-func (s *Service) bulkImport(ctx context.Context, bookmarks []bookmark.Bookmark, overwriteExisting bool) (Status, error) {
+func (s *Service) bulkImport(ctx context.Context, userUUID string, bookmarks []bookmark.Bookmark, overwriteExisting bool) (Status, error) {
 	status := Status{
 		overwriteExisting: overwriteExisting,
 	}
@@ -57,13 +101,19 @@ func (s *Service) bulkImport(ctx context.Context, bookmarks []bookmark.Bookmark,
 		return status, nil
 	}
 
+	// bookmarks that are already registered do not count towards the quota,
+	// as they are either skipped or updated
+	maxBookmarks, err := s.maxBookmarks(ctx, userUUID)
+	if err != nil {
+		return Status{}, err
+	}
+
 	var rowsAffected int64
-	var err error
 
 	if overwriteExisting {
-		rowsAffected, err = s.r.BookmarkUpsertMany(ctx, filteredBookmarks)
+		rowsAffected, err = s.r.BookmarkUpsertMany(ctx, userUUID, filteredBookmarks, maxBookmarks)
 	} else {
-		rowsAffected, err = s.r.BookmarkAddMany(ctx, filteredBookmarks)
+		rowsAffected, err = s.r.BookmarkAddMany(ctx, userUUID, filteredBookmarks, maxBookmarks)
 	}
 
 	if err != nil {
@@ -81,6 +131,10 @@ func (s *Service) bulkImport(ctx context.Context, bookmarks []bookmark.Bookmark,
 // The import will ignore:
 // - duplicate bookmarks for a given URL; only the first entry will be imported;
 // - bookmarks with missing or invalid values for required fields, such as the Title and URL.
+//
+// Tags are normalized according to the user's tag policy, replacing aliases and folding their case.
+//
+// The import is rejected as a whole if the new bookmarks would exceed the user's quota.
 func (s *Service) ImportFromNetscapeDocument(ctx context.Context, userUUID string, document *netscape.Document, visibility Visibility, overwrite OnConflictStrategy) (Status, error) {
 	var overwriteExisting bool
 
@@ -92,6 +146,11 @@ func (s *Service) ImportFromNetscapeDocument(ctx context.Context, userUUID strin
 		return Status{}, ErrOnConflictStrategyInvalid
 	}
 
+	policy, err := bookmark.TagPolicyByUserUUID(ctx, s.r, userUUID)
+	if err != nil {
+		return Status{}, err
+	}
+
 	var bookmarks []bookmark.Bookmark
 
 	flattenedDocument := document.Flatten()
@@ -125,10 +184,10 @@ func (s *Service) ImportFromNetscapeDocument(ctx context.Context, userUUID strin
 			newBookmark.UpdatedAt = newBookmark.CreatedAt
 		}
 
-		newBookmark.Normalize()
+		newBookmark.Normalize(policy)
 
 		bookmarks = append(bookmarks, *newBookmark)
 	}
 
-	return s.bulkImport(ctx, bookmarks, overwriteExisting)
+	return s.bulkImport(ctx, userUUID, bookmarks, overwriteExisting)
 }
@@ -10,12 +10,15 @@ import (
 	"github.com/virtualtam/netscape-go/v2"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 func TestServiceImportFromNetscapeDocument(t *testing.T) {
 	cases := []struct {
-		tname               string
-		repositoryBookmarks []bookmark.Bookmark
+		tname                    string
+		repositoryBookmarks      []bookmark.Bookmark
+		repositoryTagAliases     []bookmark.TagAlias
+		repositoryTagPreferences []bookmark.TagPreferences
 
 		userUUID           string
 		document           netscape.Document
@@ -59,6 +62,71 @@ func TestServiceImportFromNetscapeDocument(t *testing.T) {
 				NewOrUpdated: 1,
 			},
 		},
+		{
+			tname:              "flat document with tracking parameters and duplicate URLs",
+			userUUID:           "1632e701-e153-4f43-87ab-7fecacf8763f",
+			onConflictStrategy: OnConflictKeepExisting,
+			visibility:         VisibilityDefault,
+			document: netscape.Document{
+				Root: netscape.Folder{
+					Bookmarks: []netscape.Bookmark{
+						{
+							Title: "Flat 1",
+							URL:   "https://Flat1.domain.tld/articles/?utm_source=rss&id=1",
+						},
+						{
+							Title: "Flat 1 (duplicate)",
+							URL:   "https://flat1.domain.tld/articles?id=1&fbclid=abc",
+						},
+					},
+				},
+			},
+			want: []bookmark.Bookmark{
+				{
+					UserUUID: "1632e701-e153-4f43-87ab-7fecacf8763f",
+					Title:    "Flat 1",
+					URL:      "https://flat1.domain.tld/articles?id=1",
+				},
+			},
+			wantStatus: Status{
+				NewOrUpdated: 1,
+				Invalid:      1,
+			},
+		},
+		{
+			tname:    "flat document with aliased tags",
+			userUUID: "1632e701-e153-4f43-87ab-7fecacf8763f",
+			repositoryTagAliases: []bookmark.TagAlias{
+				{UserUUID: "1632e701-e153-4f43-87ab-7fecacf8763f", Alias: "golang", Tag: "go"},
+			},
+			repositoryTagPreferences: []bookmark.TagPreferences{
+				{UserUUID: "1632e701-e153-4f43-87ab-7fecacf8763f", CaseFolding: bookmark.TagCaseFoldingLowercase},
+			},
+			onConflictStrategy: OnConflictKeepExisting,
+			visibility:         VisibilityDefault,
+			document: netscape.Document{
+				Root: netscape.Folder{
+					Bookmarks: []netscape.Bookmark{
+						{
+							Title: "Flat 1",
+							URL:   "https://flat1.domain.tld",
+							Tags:  []string{"Golang", "Testing", "go"},
+						},
+					},
+				},
+			},
+			want: []bookmark.Bookmark{
+				{
+					UserUUID: "1632e701-e153-4f43-87ab-7fecacf8763f",
+					Title:    "Flat 1",
+					URL:      "https://flat1.domain.tld",
+					Tags:     []string{"go", "testing"},
+				},
+			},
+			wantStatus: Status{
+				NewOrUpdated: 1,
+			},
+		},
 		{
 			tname: "flat document with new and conflicting bookmarks (keep existing)",
 			repositoryBookmarks: []bookmark.Bookmark{
@@ -185,10 +253,12 @@ func TestServiceImportFromNetscapeDocument(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{
-				Bookmarks: tc.repositoryBookmarks,
+				Bookmarks:      tc.repositoryBookmarks,
+				TagAliases:     tc.repositoryTagAliases,
+				TagPreferences: tc.repositoryTagPreferences,
 			}
 
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			status, err := s.ImportFromNetscapeDocument(
 				t.Context(),
@@ -230,6 +300,82 @@ func TestServiceImportFromNetscapeDocument(t *testing.T) {
 	}
 }
 
+func TestServiceImportFromNetscapeDocumentQuota(t *testing.T) {
+	const userUUID = "1632e701-e153-4f43-87ab-7fecacf8763f"
+
+	existingBookmark := bookmark.Bookmark{
+		UserUUID: userUUID,
+		Title:    "Existing",
+		URL:      "https://existing.domain.tld",
+	}
+
+	document := netscape.Document{
+		Root: netscape.Folder{
+			Bookmarks: []netscape.Bookmark{
+				{Title: "Existing", URL: "https://existing.domain.tld"},
+				{Title: "New 1", URL: "https://new1.domain.tld"},
+				{Title: "New 2", URL: "https://new2.domain.tld"},
+			},
+		},
+	}
+
+	cases := []struct {
+		tname        string
+		maxBookmarks int64
+		wantErr      error
+	}{
+		{
+			tname:        "new bookmarks within quota",
+			maxBookmarks: 3,
+		},
+		{
+			tname:        "new bookmarks exceeding quota",
+			maxBookmarks: 2,
+			wantErr:      quota.ErrBookmarkLimitReached,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks: []bookmark.Bookmark{existingBookmark},
+			}
+
+			quotaRepository := &quota.FakeRepository{
+				Usages: map[string]quota.Usage{
+					userUUID: {Bookmarks: 1},
+				},
+			}
+			quotaService, err := quota.NewService(quotaRepository, quota.Limits{MaxBookmarks: tc.maxBookmarks})
+			if err != nil {
+				t.Fatalf("failed to create quota service: %q", err)
+			}
+
+			s := NewService(r, quotaService)
+
+			_, err = s.ImportFromNetscapeDocument(t.Context(), userUUID, &document, VisibilityDefault, OnConflictOverwrite)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %v", tc.wantErr, err)
+				}
+				if len(r.Bookmarks) != 1 {
+					t.Errorf("want no bookmark to be imported, got %d bookmark(s)", len(r.Bookmarks))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if len(r.Bookmarks) != 3 {
+				t.Errorf("want 3 bookmarks, got %d", len(r.Bookmarks))
+			}
+		})
+	}
+}
+
 func assertBookmarksEqual(t *testing.T, got, want bookmark.Bookmark) {
 	t.Helper()
 
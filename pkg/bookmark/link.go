@@ -0,0 +1,78 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package bookmark
+
+import "time"
+
+// LinkStatus represents the outcome of the last check of a bookmark's URL.
+type LinkStatus string
+
+const (
+	// LinkStatusUnchecked indicates that the URL has not been checked yet.
+	LinkStatusUnchecked LinkStatus = "unchecked"
+
+	// LinkStatusAlive indicates that the URL responded with a success status.
+	LinkStatusAlive LinkStatus = "alive"
+
+	// LinkStatusRedirected indicates that the URL redirected to another page.
+	LinkStatusRedirected LinkStatus = "redirected"
+
+	// LinkStatusDead indicates that the URL repeatedly responded with a 404 Not Found
+	// or 410 Gone status.
+	LinkStatusDead LinkStatus = "dead"
+
+	// LinkStatusForbidden indicates that the URL responded with a 401 Unauthorized or
+	// 403 Forbidden status, e.g. as it requires logging in or blocks automated requests.
+	LinkStatusForbidden LinkStatus = "forbidden"
+
+	// LinkStatusUnreachable indicates that the URL could not be reached, or responded
+	// with a status that may be temporary, e.g. a server error or rate limiting.
+	LinkStatusUnreachable LinkStatus = "unreachable"
+
+	// LinkStatusSkipped indicates that the URL was not checked, as it points to
+	// a private network address.
+	LinkStatusSkipped LinkStatus = "skipped"
+)
+
+var linkStatusValues = map[LinkStatus]bool{
+	LinkStatusUnchecked:   true,
+	LinkStatusAlive:       true,
+	LinkStatusRedirected:  true,
+	LinkStatusDead:        true,
+	LinkStatusForbidden:   true,
+	LinkStatusUnreachable: true,
+	LinkStatusSkipped:     true,
+}
+
+// ParseLinkStatus returns the LinkStatus corresponding to a given string.
+func ParseLinkStatus(value string) (LinkStatus, error) {
+	status := LinkStatus(value)
+
+	if !linkStatusValues[status] {
+		return "", ErrLinkStatusInvalid
+	}
+
+	return status, nil
+}
+
+// LinkCheck holds the result of the last check of a bookmark's URL.
+type LinkCheck struct {
+	Status LinkStatus
+
+	// StatusCode is the HTTP status code of the final response, if any.
+	StatusCode int
+
+	// RedirectURL is the URL of the page the bookmark's URL redirects to, if it differs
+	// from the bookmark's URL once canonicalized.
+	RedirectURL string
+
+	// Error describes why the URL could not be reached.
+	Error string
+
+	// NotFoundCount is the number of consecutive checks for which the URL responded with
+	// a 404 Not Found or 410 Gone status.
+	NotFoundCount int
+
+	CheckedAt time.Time
+}
@@ -17,7 +17,14 @@ type BookmarkPage struct {
 	// Owner exposes public metadata for the User owning the bookmarks.
 	Owner Owner
 
+	// LinkStatus is set when bookmarks are filtered by link status.
+	LinkStatus bookmark.LinkStatus
+
 	Bookmarks []bookmark.Bookmark
+
+	// Snippets holds excerpts of archived page content matching the search terms,
+	// indexed by bookmark UID.
+	Snippets map[string]string
 }
 
 // NewBookmarkPage initializes and returns a new BookmarkPage.
@@ -32,9 +39,30 @@ func NewBookmarkPage(owner Owner, number uint, totalPages uint, totalBookmarkCou
 }
 
 // NewBookmarkSearchResultPage initializes and returns a new BookmarkPage containing search results.
-func NewBookmarkSearchResultPage(owner Owner, searchTerms string, searchResultCount uint, number uint, totalPages uint, bookmarks []bookmark.Bookmark) BookmarkPage {
+func NewBookmarkSearchResultPage(owner Owner, searchTerms string, searchResultCount uint, number uint, totalPages uint, results []BookmarkSearchResult) BookmarkPage {
+	bookmarks := make([]bookmark.Bookmark, len(results))
+	snippets := make(map[string]string)
+
+	for i, result := range results {
+		bookmarks[i] = result.Bookmark
+
+		if result.Snippet != "" {
+			snippets[result.Bookmark.UID] = result.Snippet
+		}
+	}
+
 	page := NewBookmarkPage(owner, number, totalPages, searchResultCount, bookmarks)
 	page.SearchTerms = searchTerms
+	page.Snippets = snippets
+
+	return page
+}
+
+// NewBookmarkLinkStatusPage initializes and returns a new BookmarkPage containing bookmarks
+// filtered by link status.
+func NewBookmarkLinkStatusPage(owner Owner, status bookmark.LinkStatus, number uint, totalPages uint, bookmarkCount uint, bookmarks []bookmark.Bookmark) BookmarkPage {
+	page := NewBookmarkPage(owner, number, totalPages, bookmarkCount, bookmarks)
+	page.LinkStatus = status
 
 	return page
 }
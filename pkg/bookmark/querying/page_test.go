@@ -140,3 +140,31 @@ func assertTagPageEquals(t *testing.T, got, want TagPage) {
 		}
 	}
 }
+
+func TestNewBookmarkSearchResultPage(t *testing.T) {
+	results := []BookmarkSearchResult{
+		{
+			Bookmark: bookmark.Bookmark{UID: "bookmark-1", Title: "Title match"},
+		},
+		{
+			Bookmark: bookmark.Bookmark{UID: "bookmark-2", Title: "Content match"},
+			Snippet:  "page " + SnippetHighlightStart + "content" + SnippetHighlightStop,
+		},
+	}
+
+	got := NewBookmarkSearchResultPage(Owner{}, "content", 2, 1, 1, results)
+
+	if len(got.Bookmarks) != 2 {
+		t.Fatalf("want 2 bookmarks, got %d", len(got.Bookmarks))
+	}
+	if got.Bookmarks[0].UID != "bookmark-1" || got.Bookmarks[1].UID != "bookmark-2" {
+		t.Errorf("want bookmarks in result order, got %q and %q", got.Bookmarks[0].UID, got.Bookmarks[1].UID)
+	}
+
+	if len(got.Snippets) != 1 {
+		t.Fatalf("want 1 snippet, got %d", len(got.Snippets))
+	}
+	if got.Snippets["bookmark-2"] != results[1].Snippet {
+		t.Errorf("want snippet %q, got %q", results[1].Snippet, got.Snippets["bookmark-2"])
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 // Repository provides access to query user bookmarks.
@@ -18,16 +19,23 @@ type Repository interface {
 	// a given offset.
 	BookmarkGetN(ctx context.Context, userUUID string, visibility Visibility, n uint, offset uint) ([]bookmark.Bookmark, error)
 
+	// BookmarkLinkGetCount returns the number of bookmarks for a given user and link status.
+	BookmarkLinkGetCount(ctx context.Context, userUUID string, status bookmark.LinkStatus) (uint, error)
+
+	// BookmarkLinkGetN returns at most n bookmarks for a given user and link status,
+	// starting at a given offset.
+	BookmarkLinkGetN(ctx context.Context, userUUID string, status bookmark.LinkStatus, n uint, offset uint) ([]bookmark.Bookmark, error)
+
 	// BookmarkGetPublicByUID returns the bookmark for a given user and UID, provided the bookmark is public.
 	BookmarkGetPublicByUID(ctx context.Context, userUUID, uid string) (bookmark.Bookmark, error)
 
 	// BookmarkSearchCount returns the number of bookmarks for a given user and
-	// search terms.
-	BookmarkSearchCount(ctx context.Context, userUUID string, visibility Visibility, searchTerms string) (uint, error)
+	// search query.
+	BookmarkSearchCount(ctx context.Context, userUUID string, visibility Visibility, query search.Query) (uint, error)
 
 	// BookmarkSearchN returns at most n bookmarks for a given user and search
-	// terms, starting at a given offset.
-	BookmarkSearchN(ctx context.Context, userUUID string, visibility Visibility, searchTerms string, n uint, offset uint) ([]bookmark.Bookmark, error)
+	// query, starting at a given offset, with snippets of their archived page content.
+	BookmarkSearchN(ctx context.Context, userUUID string, visibility Visibility, query search.Query, n uint, offset uint) ([]BookmarkSearchResult, error)
 
 	// OwnerGetByUUID returns the Owner corresponding to a given UUID.
 	OwnerGetByUUID(ctx context.Context, uuid string) (Owner, error)
@@ -5,10 +5,13 @@ package querying
 
 import (
 	"context"
+	"net/url"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -17,6 +20,9 @@ var _ Repository = &FakeRepository{}
 type FakeRepository struct {
 	Bookmarks []bookmark.Bookmark
 	Users     []user.User
+
+	// Snippets holds the search snippets returned for matching bookmarks, indexed by UID.
+	Snippets map[string]string
 }
 
 func visibilityMatches(visibility Visibility, private bool) bool {
@@ -51,6 +57,36 @@ func (r *FakeRepository) BookmarkGetN(_ context.Context, userUUID string, visibi
 	return userBookmarks[offset : offset+nBookmarks], nil
 }
 
+func (r *FakeRepository) BookmarkLinkGetCount(_ context.Context, userUUID string, status bookmark.LinkStatus) (uint, error) {
+	var userBookmarkCount uint
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == userUUID && b.Link.Status == status {
+			userBookmarkCount++
+		}
+	}
+
+	return userBookmarkCount, nil
+}
+
+func (r *FakeRepository) BookmarkLinkGetN(_ context.Context, userUUID string, status bookmark.LinkStatus, n uint, offset uint) ([]bookmark.Bookmark, error) {
+	var userBookmarks []bookmark.Bookmark
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == userUUID && b.Link.Status == status {
+			userBookmarks = append(userBookmarks, b)
+		}
+	}
+
+	sort.Slice(userBookmarks, func(i, j int) bool {
+		return userBookmarks[i].CreatedAt.After(userBookmarks[j].CreatedAt)
+	})
+
+	nBookmarks := min(n, uint(len(userBookmarks[offset:])))
+
+	return userBookmarks[offset : offset+nBookmarks], nil
+}
+
 func (r *FakeRepository) BookmarkGetCount(_ context.Context, userUUID string, visibility Visibility) (uint, error) {
 	var userBookmarkCount uint
 
@@ -76,7 +112,7 @@ func (r *FakeRepository) BookmarkGetPublicByUID(_ context.Context, userUUID, uid
 	return bookmark.Bookmark{}, bookmark.ErrNotFound
 }
 
-func bookmarkMatchesSearch(b bookmark.Bookmark, searchTerms string) bool {
+func bookmarkMatchesTerms(b bookmark.Bookmark, searchTerms string) bool {
 	term := strings.ToLower(searchTerms)
 
 	if strings.Contains(strings.ToLower(b.Title), term) {
@@ -96,7 +132,65 @@ func bookmarkMatchesSearch(b bookmark.Bookmark, searchTerms string) bool {
 	return false
 }
 
-func (r *FakeRepository) bookmarkSearchMatches(userUUID string, visibility Visibility, searchTerms string) []bookmark.Bookmark {
+func bookmarkMatchesSite(b bookmark.Bookmark, sites []string) bool {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, site := range sites {
+		if host == site || strings.HasSuffix(host, "."+site) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bookmarkHasTag returns whether a bookmark is tagged with a given tag, or one of its descendants.
+func bookmarkHasTag(b bookmark.Bookmark, tag string) bool {
+	return slices.ContainsFunc(b.Tags, func(bookmarkTag string) bool {
+		return bookmark.TagMatches(bookmarkTag, tag)
+	})
+}
+
+func bookmarkMatchesSearch(b bookmark.Bookmark, query search.Query) bool {
+	if query.HasTerms() && !bookmarkMatchesTerms(b, query.Terms) {
+		return false
+	}
+
+	for _, tag := range query.Tags {
+		if !bookmarkHasTag(b, tag) {
+			return false
+		}
+	}
+	for _, tag := range query.ExcludedTags {
+		if bookmarkHasTag(b, tag) {
+			return false
+		}
+	}
+
+	if len(query.Sites) > 0 && !bookmarkMatchesSite(b, query.Sites) {
+		return false
+	}
+
+	if (query.Private && !b.Private) || (query.Public && b.Private) {
+		return false
+	}
+
+	if !query.After.IsZero() && b.CreatedAt.Before(query.After) {
+		return false
+	}
+	if !query.Before.IsZero() && !b.CreatedAt.Before(query.Before) {
+		return false
+	}
+
+	return true
+}
+
+func (r *FakeRepository) bookmarkSearchMatches(userUUID string, visibility Visibility, query search.Query) []bookmark.Bookmark {
 	var matches []bookmark.Bookmark
 
 	for _, b := range r.Bookmarks {
@@ -106,7 +200,7 @@ func (r *FakeRepository) bookmarkSearchMatches(userUUID string, visibility Visib
 		if !visibilityMatches(visibility, b.Private) {
 			continue
 		}
-		if bookmarkMatchesSearch(b, searchTerms) {
+		if bookmarkMatchesSearch(b, query) {
 			matches = append(matches, b)
 		}
 	}
@@ -118,19 +212,28 @@ func (r *FakeRepository) bookmarkSearchMatches(userUUID string, visibility Visib
 	return matches
 }
 
-func (r *FakeRepository) BookmarkSearchCount(_ context.Context, userUUID string, visibility Visibility, searchTerms string) (uint, error) {
-	return uint(len(r.bookmarkSearchMatches(userUUID, visibility, searchTerms))), nil
+func (r *FakeRepository) BookmarkSearchCount(_ context.Context, userUUID string, visibility Visibility, query search.Query) (uint, error) {
+	return uint(len(r.bookmarkSearchMatches(userUUID, visibility, query))), nil
 }
 
-func (r *FakeRepository) BookmarkSearchN(_ context.Context, userUUID string, visibility Visibility, searchTerms string, n uint, offset uint) ([]bookmark.Bookmark, error) {
-	matches := r.bookmarkSearchMatches(userUUID, visibility, searchTerms)
+func (r *FakeRepository) BookmarkSearchN(_ context.Context, userUUID string, visibility Visibility, query search.Query, n uint, offset uint) ([]BookmarkSearchResult, error) {
+	matches := r.bookmarkSearchMatches(userUUID, visibility, query)
 
 	if offset >= uint(len(matches)) {
-		return []bookmark.Bookmark{}, nil
+		return []BookmarkSearchResult{}, nil
 	}
 
 	end := min(offset+n, uint(len(matches)))
-	return matches[offset:end], nil
+
+	results := make([]BookmarkSearchResult, 0, end-offset)
+	for _, b := range matches[offset:end] {
+		results = append(results, BookmarkSearchResult{
+			Bookmark: b,
+			Snippet:  r.Snippets[b.UID],
+		})
+	}
+
+	return results, nil
 }
 
 func (r *FakeRepository) OwnerGetByUUID(_ context.Context, userUUID string) (Owner, error) {
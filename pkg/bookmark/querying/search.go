@@ -0,0 +1,51 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package querying
+
+import (
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
+)
+
+var (
+	// BookmarkSearchFilters lists the search operators supported for a user's own bookmarks.
+	BookmarkSearchFilters = []search.Filter{
+		search.FilterTag,
+		search.FilterSite,
+		search.FilterBefore,
+		search.FilterAfter,
+		search.FilterPrivate,
+		search.FilterPublic,
+	}
+
+	// PublicBookmarkSearchFilters lists the search operators supported for public bookmarks.
+	PublicBookmarkSearchFilters = []search.Filter{
+		search.FilterTag,
+		search.FilterSite,
+		search.FilterBefore,
+		search.FilterAfter,
+	}
+)
+
+// Search terms are highlighted in snippets with characters from the Unicode Private Use Area,
+// which are stripped from page content when it is indexed, so snippets can safely be escaped
+// before rendering.
+const (
+	// SnippetHighlightStart marks the start of a search term in a Snippet.
+	SnippetHighlightStart = "\uE000"
+
+	// SnippetHighlightStop marks the end of a search term in a Snippet.
+	SnippetHighlightStop = "\uE001"
+)
+
+// A BookmarkSearchResult holds a bookmark matching a set of search terms.
+type BookmarkSearchResult struct {
+	Bookmark bookmark.Bookmark
+
+	// Snippet is an excerpt of the archived page content matching the search terms,
+	// which are enclosed within SnippetHighlightStart and SnippetHighlightStop.
+	//
+	// It is empty if the search terms only match the bookmark's own fields.
+	Snippet string
+}
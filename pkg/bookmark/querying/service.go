@@ -9,6 +9,7 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/paginate"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 const (
@@ -66,18 +67,48 @@ func (s *Service) BookmarksByPage(ctx context.Context, ownerUUID string, visibil
 }
 
 // BookmarksBySearchQueryAndPage returns a SearchPage containing a limited and offset
-// number of bookmarks for a given set of search terms.
+// number of bookmarks for a given search query.
+//
+// The query is parsed with the search query language, and a search.QueryError is returned
+// if it is malformed.
 func (s *Service) BookmarksBySearchQueryAndPage(ctx context.Context, ownerUUID string, visibility Visibility, searchTerms string, number uint) (BookmarkPage, error) {
+	return s.bookmarksBySearchQueryAndPage(ctx, ownerUUID, visibility, searchTerms, BookmarkSearchFilters, number)
+}
+
+// BookmarkCountBySearchQuery returns the number of bookmarks matching a given search query.
+//
+// Search operators restricted to the owner's own bookmarks are rejected when visibility
+// is VisibilityPublic.
+func (s *Service) BookmarkCountBySearchQuery(ctx context.Context, ownerUUID string, visibility Visibility, searchTerms string) (uint, error) {
+	filters := BookmarkSearchFilters
+	if visibility == VisibilityPublic {
+		filters = PublicBookmarkSearchFilters
+	}
+
+	query, err := search.Parse(searchTerms, filters...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.r.BookmarkSearchCount(ctx, ownerUUID, visibility, query)
+}
+
+func (s *Service) bookmarksBySearchQueryAndPage(ctx context.Context, ownerUUID string, visibility Visibility, searchTerms string, filters []search.Filter, number uint) (BookmarkPage, error) {
 	owner, err := s.r.OwnerGetByUUID(ctx, ownerUUID)
 	if err != nil {
 		return BookmarkPage{}, err
 	}
 
+	query, err := search.Parse(searchTerms, filters...)
+	if err != nil {
+		return BookmarkPage{}, err
+	}
+
 	if number < 1 {
 		return BookmarkPage{}, paginate.ErrPageNumberOutOfBounds
 	}
 
-	bookmarkCount, err := s.r.BookmarkSearchCount(ctx, ownerUUID, visibility, searchTerms)
+	bookmarkCount, err := s.r.BookmarkSearchCount(ctx, ownerUUID, visibility, query)
 	if err != nil {
 		return BookmarkPage{}, err
 	}
@@ -90,17 +121,55 @@ func (s *Service) BookmarksBySearchQueryAndPage(ctx context.Context, ownerUUID s
 
 	if bookmarkCount == 0 {
 		// early return: nothing to display
-		return NewBookmarkSearchResultPage(owner, searchTerms, 0, 1, 1, []bookmark.Bookmark{}), nil
+		return NewBookmarkSearchResultPage(owner, searchTerms, 0, 1, 1, []BookmarkSearchResult{}), nil
 	}
 
 	dbOffset := (number - 1) * bookmarksPerPage
 
-	bookmarks, err := s.r.BookmarkSearchN(ctx, ownerUUID, visibility, searchTerms, bookmarksPerPage, dbOffset)
+	results, err := s.r.BookmarkSearchN(ctx, ownerUUID, visibility, query, bookmarksPerPage, dbOffset)
 	if err != nil {
 		return BookmarkPage{}, err
 	}
 
-	return NewBookmarkSearchResultPage(owner, searchTerms, bookmarkCount, number, totalPages, bookmarks), nil
+	return NewBookmarkSearchResultPage(owner, searchTerms, bookmarkCount, number, totalPages, results), nil
+}
+
+// BookmarksByLinkStatusAndPage returns a Page containing a limited and offset number of
+// bookmarks for a given link status.
+func (s *Service) BookmarksByLinkStatusAndPage(ctx context.Context, ownerUUID string, status bookmark.LinkStatus, number uint) (BookmarkPage, error) {
+	owner, err := s.r.OwnerGetByUUID(ctx, ownerUUID)
+	if err != nil {
+		return BookmarkPage{}, err
+	}
+
+	if number < 1 {
+		return BookmarkPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	bookmarkCount, err := s.r.BookmarkLinkGetCount(ctx, ownerUUID, status)
+	if err != nil {
+		return BookmarkPage{}, err
+	}
+
+	totalPages := paginate.PageCount(bookmarkCount, bookmarksPerPage)
+
+	if number > totalPages {
+		return BookmarkPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	if bookmarkCount == 0 {
+		// early return: nothing to display
+		return NewBookmarkLinkStatusPage(owner, status, 1, 1, 0, []bookmark.Bookmark{}), nil
+	}
+
+	dbOffset := (number - 1) * bookmarksPerPage
+
+	bookmarks, err := s.r.BookmarkLinkGetN(ctx, ownerUUID, status, bookmarksPerPage, dbOffset)
+	if err != nil {
+		return BookmarkPage{}, err
+	}
+
+	return NewBookmarkLinkStatusPage(owner, status, number, totalPages, bookmarkCount, bookmarks), nil
 }
 
 // PublicBookmarkByUID returns a Page containing a single public bookmark.
@@ -126,9 +195,9 @@ func (s *Service) PublicBookmarksByPage(ctx context.Context, ownerUUID string, n
 }
 
 // PublicBookmarksBySearchQueryAndPage returns a SearchPage containing a limited and offset
-// number of bookmarks for a given set of search terms.
+// number of public bookmarks for a given search query.
 func (s *Service) PublicBookmarksBySearchQueryAndPage(ctx context.Context, ownerUUID string, searchTerms string, number uint) (BookmarkPage, error) {
-	return s.BookmarksBySearchQueryAndPage(ctx, ownerUUID, VisibilityPublic, searchTerms, number)
+	return s.bookmarksBySearchQueryAndPage(ctx, ownerUUID, VisibilityPublic, searchTerms, PublicBookmarkSearchFilters, number)
 }
 
 // Tags return all tags for a given user.
@@ -136,6 +205,16 @@ func (s *Service) Tags(ctx context.Context, userUUID string, visibility Visibili
 	return s.r.BookmarkTagGetAll(ctx, userUUID, visibility)
 }
 
+// TagMergeSuggestions returns suggestions to merge near-duplicate tags for a given user.
+func (s *Service) TagMergeSuggestions(ctx context.Context, userUUID string) ([]TagMergeSuggestion, error) {
+	tags, err := s.r.BookmarkTagGetAll(ctx, userUUID, VisibilityAll)
+	if err != nil {
+		return []TagMergeSuggestion{}, err
+	}
+
+	return NewTagMergeSuggestions(tags), nil
+}
+
 // TagNamesByCount returns all tag names for a given user,
 // sorted by count in descending order.
 func (s *Service) TagNamesByCount(ctx context.Context, userUUID string, visibility Visibility) ([]string, error) {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/paginate"
 	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -494,8 +495,122 @@ func TestServiceBookmarksBySearchQueryAndPage(t *testing.T) {
 				},
 			},
 		},
+		{
+			tname:               "query language, tag",
+			repositoryBookmarks: testRepositoryBookmarks,
+			ownerUUID:           "5d75c769-059c-4b36-9db6-1c82619e704a",
+			visibility:          VisibilityAll,
+			searchTerms:         "tag:test",
+			pageNumber:          1,
+			want: BookmarkPage{
+				Page: paginate.Page{
+					PageNumber:         1,
+					PreviousPageNumber: 1,
+					NextPageNumber:     1,
+					TotalPages:         1,
+					ItemOffset:         1,
+					ItemCount:          1,
+					SearchTerms:        "tag:test",
+				},
+				Bookmarks: []bookmark.Bookmark{
+					{
+						Title:     "Bookmark 2",
+						CreatedAt: time.Date(2021, 8, 17, 14, 30, 45, 100, time.Local),
+					},
+				},
+			},
+		},
+		{
+			tname: "query language, parent tag includes descendants",
+			repositoryBookmarks: []bookmark.Bookmark{
+				{
+					UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+					Title:     "Go testing",
+					Tags:      []string{"dev/go/testing"},
+					CreatedAt: time.Date(2021, 8, 15, 14, 30, 45, 100, time.Local),
+				},
+				{
+					UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+					Title:     "DevOps",
+					Tags:      []string{"devops"},
+					CreatedAt: time.Date(2021, 8, 16, 14, 30, 45, 100, time.Local),
+				},
+				{
+					UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+					Title:     "Software development",
+					Tags:      []string{"dev"},
+					CreatedAt: time.Date(2021, 8, 17, 14, 30, 45, 100, time.Local),
+				},
+			},
+			ownerUUID:   "5d75c769-059c-4b36-9db6-1c82619e704a",
+			visibility:  VisibilityAll,
+			searchTerms: "tag:dev -tag:dev/go/testing/legacy",
+			pageNumber:  1,
+			want: BookmarkPage{
+				Page: paginate.Page{
+					PageNumber:         1,
+					PreviousPageNumber: 1,
+					NextPageNumber:     1,
+					TotalPages:         1,
+					ItemOffset:         1,
+					ItemCount:          2,
+					SearchTerms:        "tag:dev -tag:dev/go/testing/legacy",
+				},
+				Bookmarks: []bookmark.Bookmark{
+					{
+						Title:     "Software development",
+						CreatedAt: time.Date(2021, 8, 17, 14, 30, 45, 100, time.Local),
+					},
+					{
+						Title:     "Go testing",
+						CreatedAt: time.Date(2021, 8, 15, 14, 30, 45, 100, time.Local),
+					},
+				},
+			},
+		},
+		{
+			tname:               "query language, free text, site and visibility",
+			repositoryBookmarks: testRepositoryBookmarks,
+			ownerUUID:           "5d75c769-059c-4b36-9db6-1c82619e704a",
+			visibility:          VisibilityAll,
+			searchTerms:         "bookmark is:public -tag:test after:2021-08-01 site:example1.tld",
+			pageNumber:          1,
+			want: BookmarkPage{
+				Page: paginate.Page{
+					PageNumber:         1,
+					PreviousPageNumber: 1,
+					NextPageNumber:     1,
+					TotalPages:         1,
+					ItemOffset:         1,
+					ItemCount:          1,
+					SearchTerms:        "bookmark is:public -tag:test after:2021-08-01 site:example1.tld",
+				},
+				Bookmarks: []bookmark.Bookmark{
+					{
+						Title:     "Bookmark 1",
+						CreatedAt: time.Date(2021, 8, 15, 14, 30, 45, 100, time.Local),
+					},
+				},
+			},
+		},
 
 		// error cases
+		{
+			tname:               "malformed query",
+			repositoryBookmarks: testRepositoryBookmarks,
+			ownerUUID:           "5d75c769-059c-4b36-9db6-1c82619e704a",
+			searchTerms:         "before:yesterday",
+			pageNumber:          1,
+			wantErr:             search.ErrQueryInvalid,
+		},
+		{
+			tname:               "unsupported operator",
+			repositoryBookmarks: testRepositoryBookmarks,
+			ownerUUID:           "5d75c769-059c-4b36-9db6-1c82619e704a",
+			searchTerms:         "is:unread",
+			pageNumber:          1,
+			wantErr:             search.ErrQueryInvalid,
+		},
 		{
 			tname:       "owner not found",
 			ownerUUID:   "9681e525-f205-489d-b53e-1a858b4ca561",
@@ -550,6 +665,141 @@ func TestServiceBookmarksBySearchQueryAndPage(t *testing.T) {
 	}
 }
 
+func TestServiceBookmarksByLinkStatusAndPage(t *testing.T) {
+	repositoryBookmarks := []bookmark.Bookmark{
+		{
+			UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			Title:     "Dead bookmark 1",
+			URL:       "https://dead1.tld",
+			Link:      bookmark.LinkCheck{Status: bookmark.LinkStatusDead, StatusCode: 404},
+			CreatedAt: time.Date(2021, 8, 15, 14, 30, 45, 100, time.Local),
+		},
+		{
+			UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			Title:     "Redirected bookmark",
+			URL:       "https://redirected.tld",
+			Link:      bookmark.LinkCheck{Status: bookmark.LinkStatusRedirected, RedirectURL: "https://target.tld"},
+			CreatedAt: time.Date(2021, 8, 16, 14, 30, 45, 100, time.Local),
+		},
+		{
+			UserUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			Title:     "Dead bookmark 2",
+			URL:       "https://dead2.tld",
+			Link:      bookmark.LinkCheck{Status: bookmark.LinkStatusDead, Error: "connection refused"},
+			CreatedAt: time.Date(2021, 8, 17, 14, 30, 45, 100, time.Local),
+		},
+		{
+			UserUUID: "218d03f8-976c-4387-9d74-95ed656e3921",
+			Title:    "Other user's dead bookmark",
+			URL:      "https://other.tld",
+			Link:     bookmark.LinkCheck{Status: bookmark.LinkStatusDead, StatusCode: 410},
+		},
+	}
+
+	cases := []struct {
+		tname      string
+		ownerUUID  string
+		status     bookmark.LinkStatus
+		pageNumber uint
+		want       BookmarkPage
+		wantErr    error
+	}{
+		// nominal cases
+		{
+			tname:      "no bookmarks with this status",
+			ownerUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			status:     bookmark.LinkStatusUnchecked,
+			pageNumber: 1,
+			want: BookmarkPage{
+				Page: paginate.Page{
+					PageNumber:         1,
+					PreviousPageNumber: 1,
+					NextPageNumber:     1,
+					TotalPages:         1,
+					ItemOffset:         1,
+				},
+				LinkStatus: bookmark.LinkStatusUnchecked,
+			},
+		},
+		{
+			tname:      "dead links",
+			ownerUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			status:     bookmark.LinkStatusDead,
+			pageNumber: 1,
+			want: BookmarkPage{
+				Page: paginate.Page{
+					PageNumber:         1,
+					PreviousPageNumber: 1,
+					NextPageNumber:     1,
+					TotalPages:         1,
+					ItemOffset:         1,
+					ItemCount:          2,
+				},
+				LinkStatus: bookmark.LinkStatusDead,
+				Bookmarks: []bookmark.Bookmark{
+					{
+						Title:     "Dead bookmark 2",
+						CreatedAt: time.Date(2021, 8, 17, 14, 30, 45, 100, time.Local),
+					},
+					{
+						Title:     "Dead bookmark 1",
+						CreatedAt: time.Date(2021, 8, 15, 14, 30, 45, 100, time.Local),
+					},
+				},
+			},
+		},
+
+		// error cases
+		{
+			tname:      "owner not found",
+			ownerUUID:  "9681e525-f205-489d-b53e-1a858b4ca561",
+			status:     bookmark.LinkStatusDead,
+			pageNumber: 1,
+			wantErr:    ErrOwnerNotFound,
+		},
+		{
+			tname:      "page number out of bounds",
+			ownerUUID:  "5d75c769-059c-4b36-9db6-1c82619e704a",
+			status:     bookmark.LinkStatusDead,
+			pageNumber: 2,
+			wantErr:    paginate.ErrPageNumberOutOfBounds,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks: repositoryBookmarks,
+				Users:     testRepositoryUsers,
+			}
+
+			s := NewService(r)
+
+			got, err := s.BookmarksByLinkStatusAndPage(t.Context(), tc.ownerUUID, tc.status, tc.pageNumber)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.LinkStatus != tc.want.LinkStatus {
+				t.Errorf("want link status %q, got %q", tc.want.LinkStatus, got.LinkStatus)
+			}
+
+			assertBookmarkPageEquals(t, got, tc.want)
+		})
+	}
+}
+
 func TestServicePublicBookmarksBySearchQueryAndPage(t *testing.T) {
 	cases := []struct {
 		tname               string
@@ -0,0 +1,182 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package querying
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TagSimilarity describes why two tags are considered near-duplicates.
+type TagSimilarity string
+
+const (
+	// TagSimilarityCase indicates tags only differ by case, e.g. Go and go.
+	TagSimilarityCase TagSimilarity = "case"
+
+	// TagSimilarityPlural indicates tags are the singular and plural forms of a word,
+	// e.g. tool and tools.
+	TagSimilarityPlural TagSimilarity = "plural"
+
+	// TagSimilaritySpelling indicates tags are spelled almost the same, e.g. kubernetes
+	// and kubernets.
+	TagSimilaritySpelling TagSimilarity = "spelling"
+)
+
+const (
+	// tagSpellingMinLength is the minimum length of tags compared by spelling, as short
+	// tags only differing by one letter are usually unrelated, e.g. go and js.
+	tagSpellingMinLength = 5
+
+	// tagSpellingLongLength is the length from which tags may differ by two letters.
+	tagSpellingLongLength = 9
+)
+
+// A TagDuplicate is a tag suggested to be merged into another tag.
+type TagDuplicate struct {
+	Tag
+
+	Similarity TagSimilarity
+}
+
+// A TagMergeSuggestion suggests merging near-duplicate tags into the most used one.
+type TagMergeSuggestion struct {
+	Target     Tag
+	Duplicates []TagDuplicate
+}
+
+// NewTagMergeSuggestions detects near-duplicate tags, and suggests merging them into the
+// most used tag of each group.
+//
+// Tags are near-duplicates when they only differ by case, are the singular and plural
+// forms of a word, or are spelled almost the same.
+func NewTagMergeSuggestions(tags []Tag) []TagMergeSuggestion {
+	sortedTags := make([]Tag, len(tags))
+	copy(sortedTags, tags)
+
+	sort.SliceStable(sortedTags, func(i, j int) bool {
+		if sortedTags[i].Count != sortedTags[j].Count {
+			return sortedTags[i].Count > sortedTags[j].Count
+		}
+		return sortedTags[i].Name < sortedTags[j].Name
+	})
+
+	suggestions := []TagMergeSuggestion{}
+	merged := make([]bool, len(sortedTags))
+
+	for i, target := range sortedTags {
+		if merged[i] {
+			continue
+		}
+
+		var duplicates []TagDuplicate
+
+		for j := i + 1; j < len(sortedTags); j++ {
+			if merged[j] {
+				continue
+			}
+
+			similarity, ok := tagSimilarity(target.Name, sortedTags[j].Name)
+			if !ok {
+				continue
+			}
+
+			merged[j] = true
+			duplicates = append(duplicates, TagDuplicate{
+				Tag:        sortedTags[j],
+				Similarity: similarity,
+			})
+		}
+
+		if len(duplicates) == 0 {
+			continue
+		}
+
+		suggestions = append(suggestions, TagMergeSuggestion{
+			Target:     target,
+			Duplicates: duplicates,
+		})
+	}
+
+	return suggestions
+}
+
+// tagSimilarity returns how two tags are similar, and whether they are near-duplicates.
+func tagSimilarity(a string, b string) (TagSimilarity, bool) {
+	if strings.EqualFold(a, b) {
+		return TagSimilarityCase, true
+	}
+
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+
+	if singularTag(a) == singularTag(b) {
+		return TagSimilarityPlural, true
+	}
+
+	// tags differing by a number are usually distinct versions, e.g. python2 and python3
+	if strings.ContainsFunc(a, unicode.IsDigit) || strings.ContainsFunc(b, unicode.IsDigit) {
+		return "", false
+	}
+
+	length := min(len([]rune(a)), len([]rune(b)))
+
+	switch {
+	case length >= tagSpellingLongLength && editDistance(a, b) <= 2:
+		return TagSimilaritySpelling, true
+	case length >= tagSpellingMinLength && editDistance(a, b) <= 1:
+		return TagSimilaritySpelling, true
+	}
+
+	return "", false
+}
+
+// singularTag returns the singular form of a lowercase English tag, following the most
+// common pluralization rules.
+func singularTag(tag string) string {
+	switch {
+	case len(tag) > 4 && strings.HasSuffix(tag, "ies"):
+		return strings.TrimSuffix(tag, "ies") + "y"
+	case strings.HasSuffix(tag, "ches"), strings.HasSuffix(tag, "shes"),
+		strings.HasSuffix(tag, "sses"), strings.HasSuffix(tag, "xes"):
+		return strings.TrimSuffix(tag, "es")
+	case len(tag) > 3 && strings.HasSuffix(tag, "s") && !strings.HasSuffix(tag, "ss"):
+		return strings.TrimSuffix(tag, "s")
+	}
+
+	return tag
+}
+
+// editDistance returns the Levenshtein distance between two strings, that is the minimum
+// number of single-character insertions, deletions and substitutions to change one into
+// the other.
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			substitution := previous[j-1]
+			if ra[i-1] != rb[j-1] {
+				substitution++
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
@@ -0,0 +1,156 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package querying
+
+import (
+	"testing"
+)
+
+func TestNewTagMergeSuggestions(t *testing.T) {
+	cases := []struct {
+		tname string
+		tags  []Tag
+		want  []TagMergeSuggestion
+	}{
+		{
+			tname: "no tags",
+			tags:  []Tag{},
+			want:  []TagMergeSuggestion{},
+		},
+		{
+			tname: "distinct tags",
+			tags: []Tag{
+				NewTag("go", 4),
+				NewTag("js", 3),
+				NewTag("python2", 2),
+				NewTag("python3", 2),
+				NewTag("rust", 1),
+				NewTag("test", 1),
+			},
+			want: []TagMergeSuggestion{},
+		},
+		{
+			tname: "case",
+			tags: []Tag{
+				NewTag("Go", 1),
+				NewTag("go", 5),
+				NewTag("GO", 2),
+			},
+			want: []TagMergeSuggestion{
+				{
+					Target: NewTag("go", 5),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("GO", 2), Similarity: TagSimilarityCase},
+						{Tag: NewTag("Go", 1), Similarity: TagSimilarityCase},
+					},
+				},
+			},
+		},
+		{
+			tname: "plural",
+			tags: []Tag{
+				NewTag("tool", 2),
+				NewTag("tools", 3),
+				NewTag("library", 1),
+				NewTag("libraries", 4),
+				NewTag("dev/patch", 2),
+				NewTag("dev/patches", 1),
+			},
+			want: []TagMergeSuggestion{
+				{
+					Target: NewTag("libraries", 4),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("library", 1), Similarity: TagSimilarityPlural},
+					},
+				},
+				{
+					Target: NewTag("tools", 3),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("tool", 2), Similarity: TagSimilarityPlural},
+					},
+				},
+				{
+					Target: NewTag("dev/patch", 2),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("dev/patches", 1), Similarity: TagSimilarityPlural},
+					},
+				},
+			},
+		},
+		{
+			tname: "spelling",
+			tags: []Tag{
+				NewTag("kubernetes", 8),
+				NewTag("kubrenetes", 1),
+				NewTag("docker", 3),
+				NewTag("dokcer", 1),
+				NewTag("podman", 2),
+				NewTag("devtools", 1),
+				NewTag("dev/tools", 2),
+			},
+			want: []TagMergeSuggestion{
+				{
+					Target: NewTag("kubernetes", 8),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("kubrenetes", 1), Similarity: TagSimilaritySpelling},
+					},
+				},
+				{
+					Target: NewTag("dev/tools", 2),
+					Duplicates: []TagDuplicate{
+						{Tag: NewTag("devtools", 1), Similarity: TagSimilaritySpelling},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := NewTagMergeSuggestions(tc.tags)
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("want %d suggestions, got %d: %#v", len(tc.want), len(got), got)
+			}
+
+			for i, want := range tc.want {
+				if got[i].Target != want.Target {
+					t.Errorf("want suggestion %d target %#v, got %#v", i, want.Target, got[i].Target)
+				}
+
+				if len(got[i].Duplicates) != len(want.Duplicates) {
+					t.Fatalf("want suggestion %d to have %d duplicates, got %d", i, len(want.Duplicates), len(got[i].Duplicates))
+				}
+
+				for j, wantDuplicate := range want.Duplicates {
+					if got[i].Duplicates[j] != wantDuplicate {
+						t.Errorf("want suggestion %d duplicate %d %#v, got %#v", i, j, wantDuplicate, got[i].Duplicates[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "abc", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "kubernetes", b: "kubrenetes", want: 2},
+		{a: "café", b: "cafe", want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			if got := editDistance(tc.a, tc.b); got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package querying
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+// A TagNode holds a bookmark tag within the tag hierarchy, along with its descendants.
+type TagNode struct {
+	Tag
+
+	// Label is the last level of the tag name, e.g. testing for dev/go/testing.
+	Label string
+
+	// Total is the sum of the counts of the tag and all its descendants.
+	Total uint
+
+	Children []TagNode
+}
+
+// NewTagTree arranges tags as a tree, following the hierarchy of their names.
+//
+// Parent tags that are only used through their descendants are added with a count of zero.
+// Sibling nodes are sorted by name.
+func NewTagTree(tags []Tag) []TagNode {
+	counts := map[string]uint{}
+
+	for _, tag := range tags {
+		counts[tag.Name] += tag.Count
+
+		// register ancestors that are not used on their own
+		for parent := tagParent(tag.Name); parent != ""; parent = tagParent(parent) {
+			if _, ok := counts[parent]; !ok {
+				counts[parent] = 0
+			}
+		}
+	}
+
+	children := map[string][]string{}
+
+	for name := range counts {
+		parent := tagParent(name)
+		children[parent] = append(children[parent], name)
+	}
+
+	return newTagNodes(children, counts, "")
+}
+
+// newTagNodes recursively builds the nodes for the children of a given parent tag.
+func newTagNodes(children map[string][]string, counts map[string]uint, parent string) []TagNode {
+	names := children[parent]
+	sort.Strings(names)
+
+	nodes := make([]TagNode, 0, len(names))
+
+	for _, name := range names {
+		node := TagNode{
+			Tag:      NewTag(name, counts[name]),
+			Label:    strings.TrimPrefix(name, parent+bookmark.TagSeparator),
+			Total:    counts[name],
+			Children: newTagNodes(children, counts, name),
+		}
+
+		if parent == "" {
+			node.Label = name
+		}
+
+		for _, child := range node.Children {
+			node.Total += child.Total
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// tagParent returns the name of the parent of a tag, or an empty string for top-level tags.
+func tagParent(name string) string {
+	index := strings.LastIndex(name, bookmark.TagSeparator)
+	if index <= 0 {
+		return ""
+	}
+
+	return name[:index]
+}
@@ -0,0 +1,115 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package querying
+
+import (
+	"testing"
+)
+
+func TestNewTagTree(t *testing.T) {
+	cases := []struct {
+		tname string
+		tags  []Tag
+		want  []TagNode
+	}{
+		{
+			tname: "no tags",
+			tags:  []Tag{},
+			want:  []TagNode{},
+		},
+		{
+			tname: "flat tags",
+			tags: []Tag{
+				NewTag("video", 3),
+				NewTag("music", 5),
+			},
+			want: []TagNode{
+				{Tag: NewTag("music", 5), Label: "music", Total: 5, Children: []TagNode{}},
+				{Tag: NewTag("video", 3), Label: "video", Total: 3, Children: []TagNode{}},
+			},
+		},
+		{
+			tname: "hierarchical tags",
+			tags: []Tag{
+				NewTag("dev/go", 4),
+				NewTag("dev", 2),
+				NewTag("dev/go/testing", 1),
+				NewTag("dev/rust", 3),
+			},
+			want: []TagNode{
+				{
+					Tag:   NewTag("dev", 2),
+					Label: "dev",
+					Total: 10,
+					Children: []TagNode{
+						{
+							Tag:   NewTag("dev/go", 4),
+							Label: "go",
+							Total: 5,
+							Children: []TagNode{
+								{Tag: NewTag("dev/go/testing", 1), Label: "testing", Total: 1, Children: []TagNode{}},
+							},
+						},
+						{Tag: NewTag("dev/rust", 3), Label: "rust", Total: 3, Children: []TagNode{}},
+					},
+				},
+			},
+		},
+		{
+			tname: "parent only used through its descendants",
+			tags: []Tag{
+				NewTag("lang/fr/grammar", 2),
+			},
+			want: []TagNode{
+				{
+					Tag:   NewTag("lang", 0),
+					Label: "lang",
+					Total: 2,
+					Children: []TagNode{
+						{
+							Tag:   NewTag("lang/fr", 0),
+							Label: "fr",
+							Total: 2,
+							Children: []TagNode{
+								{Tag: NewTag("lang/fr/grammar", 2), Label: "grammar", Total: 2, Children: []TagNode{}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := NewTagTree(tc.tags)
+
+			assertTagNodesEqual(t, got, tc.want)
+		})
+	}
+}
+
+func assertTagNodesEqual(t *testing.T, got []TagNode, want []TagNode) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("want %d nodes, got %d", len(want), len(got))
+	}
+
+	for i, wantNode := range want {
+		gotNode := got[i]
+
+		if gotNode.Tag != wantNode.Tag {
+			t.Errorf("want node %d tag %#v, got %#v", i, wantNode.Tag, gotNode.Tag)
+		}
+		if gotNode.Label != wantNode.Label {
+			t.Errorf("want node %q label %q, got %q", wantNode.Name, wantNode.Label, gotNode.Label)
+		}
+		if gotNode.Total != wantNode.Total {
+			t.Errorf("want node %q total %d, got %d", wantNode.Name, wantNode.Total, gotNode.Total)
+		}
+
+		assertTagNodesEqual(t, gotNode.Children, wantNode.Children)
+	}
+}
@@ -18,12 +18,33 @@ type ValidationRepository interface {
 	BookmarkIsURLRegisteredToAnotherUID(ctx context.Context, userUUID, url, uid string) (bool, error)
 }
 
+// TagPolicyRepository provides access to the rules applied to a user's tags.
+type TagPolicyRepository interface {
+	// BookmarkTagAliasGetAll returns all tag aliases for a given user UUID.
+	BookmarkTagAliasGetAll(ctx context.Context, userUUID string) ([]TagAlias, error)
+
+	// BookmarkTagPreferencesGetByUserUUID returns the tag preferences for a given user UUID.
+	BookmarkTagPreferencesGetByUserUUID(ctx context.Context, userUUID string) (TagPreferences, error)
+}
+
 // Repository provides access to user bookmarks.
 type Repository interface {
+	TagPolicyRepository
 	ValidationRepository
 
 	// BookmarkAdd adds a new bookmark for the logged-in user.
-	BookmarkAdd(ctx context.Context, bookmark Bookmark) error
+	//
+	// The bookmark is only added if the user stores less than maxBookmarks bookmarks,
+	// otherwise quota.ErrBookmarkLimitReached is returned; a zero maxBookmarks means
+	// the number of bookmarks is unlimited.
+	BookmarkAdd(ctx context.Context, bookmark Bookmark, maxBookmarks int64) error
+
+	// BookmarkBulkEdit applies a BulkEditQuery to the selected bookmarks of a given user.
+	//
+	// The selected bookmarks are locked, then deleted, or edited and saved, within a single
+	// transaction; the edit function reports whether it changed a bookmark, and only changed
+	// bookmarks are saved. The summary holds the updated or deleted bookmarks and their count.
+	BookmarkBulkEdit(ctx context.Context, bq BulkEditQuery, edit func(*Bookmark) bool) (BulkEditSummary, error)
 
 	// BookmarkDelete deletes a given bookmark for the logged-in user.
 	BookmarkDelete(ctx context.Context, userUUID, uid string) error
@@ -34,12 +55,22 @@ type Repository interface {
 	// BookmarkGetByTag returns all bookmarks for a given user UUID and tag.
 	BookmarkGetByTag(ctx context.Context, userUUID string, tag string) ([]Bookmark, error)
 
+	// BookmarkGetByTagWithDescendants returns all bookmarks for a given user UUID, tagged
+	// with a given tag or one of its descendants.
+	BookmarkGetByTagWithDescendants(ctx context.Context, userUUID string, tag string) ([]Bookmark, error)
+
 	// BookmarkGetByUID returns the bookmark for a given user UUID and UID.
 	BookmarkGetByUID(ctx context.Context, userUUID, uid string) (Bookmark, error)
 
 	// BookmarkGetByURL returns the bookmark for a given user UUID and URL.
 	BookmarkGetByURL(ctx context.Context, userUUID, u string) (Bookmark, error)
 
+	// BookmarkTagAliasReplaceAll replaces all tag aliases for a given user UUID.
+	BookmarkTagAliasReplaceAll(ctx context.Context, userUUID string, aliases []TagAlias) error
+
+	// BookmarkTagPreferencesUpsert saves the tag preferences for a given user.
+	BookmarkTagPreferencesUpsert(ctx context.Context, preferences TagPreferences) error
+
 	// BookmarkTagUpdateMany updates a tag for collection of existing bookmarks.
 	BookmarkTagUpdateMany(ctx context.Context, bookmarks []Bookmark) (int64, error)
 
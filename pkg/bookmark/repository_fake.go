@@ -6,19 +6,70 @@ package bookmark
 import (
 	"context"
 	"slices"
+
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 var _ Repository = &FakeRepository{}
 
 type FakeRepository struct {
-	Bookmarks []Bookmark
+	Bookmarks      []Bookmark
+	TagAliases     []TagAlias
+	TagPreferences []TagPreferences
 }
 
-func (r *FakeRepository) BookmarkAdd(_ context.Context, bookmark Bookmark) error {
+func (r *FakeRepository) BookmarkAdd(_ context.Context, bookmark Bookmark, maxBookmarks int64) error {
+	var current int64
+	for _, b := range r.Bookmarks {
+		if b.UserUUID == bookmark.UserUUID {
+			current++
+		}
+	}
+
+	if err := quota.CheckBookmarkCount(maxBookmarks, current, 1); err != nil {
+		return err
+	}
+
 	r.Bookmarks = append(r.Bookmarks, bookmark)
 	return nil
 }
 
+func (r *FakeRepository) BookmarkBulkEdit(_ context.Context, bq BulkEditQuery, edit func(*Bookmark) bool) (BulkEditSummary, error) {
+	var summary BulkEditSummary
+
+	if bq.Action == BulkEditActionDelete {
+		r.Bookmarks = slices.DeleteFunc(r.Bookmarks, func(b Bookmark) bool {
+			if b.UserUUID != bq.UserUUID || !slices.Contains(bq.UIDs, b.UID) {
+				return false
+			}
+
+			summary.Bookmarks = append(summary.Bookmarks, b)
+			summary.Deleted++
+			return true
+		})
+
+		return summary, nil
+	}
+
+	for index, b := range r.Bookmarks {
+		if b.UserUUID != bq.UserUUID || !slices.Contains(bq.UIDs, b.UID) {
+			continue
+		}
+
+		b.Tags = slices.Clone(b.Tags)
+
+		if !edit(&b) {
+			continue
+		}
+
+		r.Bookmarks[index] = b
+		summary.Bookmarks = append(summary.Bookmarks, b)
+		summary.Updated++
+	}
+
+	return summary, nil
+}
+
 func (r *FakeRepository) BookmarkDelete(_ context.Context, userUUID, uid string) error {
 	for index, b := range r.Bookmarks {
 		if b.UserUUID == userUUID && b.UID == uid {
@@ -56,6 +107,22 @@ func (r *FakeRepository) BookmarkGetByTag(_ context.Context, userUUID string, ta
 	return bookmarks, nil
 }
 
+func (r *FakeRepository) BookmarkGetByTagWithDescendants(_ context.Context, userUUID string, tag string) ([]Bookmark, error) {
+	var bookmarks []Bookmark
+
+	for _, b := range r.Bookmarks {
+		if b.UserUUID != userUUID {
+			continue
+		}
+
+		if slices.ContainsFunc(b.Tags, func(bookmarkTag string) bool { return TagMatches(bookmarkTag, tag) }) {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	return bookmarks, nil
+}
+
 func (r *FakeRepository) BookmarkGetByUID(_ context.Context, userUUID, uid string) (Bookmark, error) {
 	for _, b := range r.Bookmarks {
 		if b.UserUUID == userUUID && b.UID == uid {
@@ -96,6 +163,50 @@ func (r *FakeRepository) BookmarkIsURLRegisteredToAnotherUID(_ context.Context,
 	return false, nil
 }
 
+func (r *FakeRepository) BookmarkTagAliasGetAll(_ context.Context, userUUID string) ([]TagAlias, error) {
+	var aliases []TagAlias
+
+	for _, alias := range r.TagAliases {
+		if alias.UserUUID == userUUID {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases, nil
+}
+
+func (r *FakeRepository) BookmarkTagAliasReplaceAll(_ context.Context, userUUID string, aliases []TagAlias) error {
+	r.TagAliases = slices.DeleteFunc(r.TagAliases, func(alias TagAlias) bool {
+		return alias.UserUUID == userUUID
+	})
+	r.TagAliases = append(r.TagAliases, aliases...)
+
+	return nil
+}
+
+func (r *FakeRepository) BookmarkTagPreferencesGetByUserUUID(_ context.Context, userUUID string) (TagPreferences, error) {
+	for _, preferences := range r.TagPreferences {
+		if preferences.UserUUID == userUUID {
+			return preferences, nil
+		}
+	}
+
+	return TagPreferences{}, ErrTagPreferencesNotFound
+}
+
+func (r *FakeRepository) BookmarkTagPreferencesUpsert(_ context.Context, preferences TagPreferences) error {
+	for index, p := range r.TagPreferences {
+		if p.UserUUID == preferences.UserUUID {
+			r.TagPreferences[index] = preferences
+			return nil
+		}
+	}
+
+	r.TagPreferences = append(r.TagPreferences, preferences)
+
+	return nil
+}
+
 func (r *FakeRepository) BookmarkTagUpdateMany(_ context.Context, bookmarks []Bookmark) (int64, error) {
 	for _, bookmark := range bookmarks {
 		for index, b := range r.Bookmarks {
@@ -111,6 +222,13 @@ func (r *FakeRepository) BookmarkTagUpdateMany(_ context.Context, bookmarks []Bo
 func (r *FakeRepository) BookmarkUpdate(_ context.Context, bookmark Bookmark) error {
 	for index, b := range r.Bookmarks {
 		if b.UserUUID == bookmark.UserUUID && b.UID == bookmark.UID {
+			// the result of the last link check only applies to the URL that was checked
+			if b.URL == bookmark.URL {
+				bookmark.Link = b.Link
+			} else {
+				bookmark.Link = LinkCheck{Status: LinkStatusUnchecked}
+			}
+
 			r.Bookmarks[index] = bookmark
 			return nil
 		}
@@ -7,17 +7,24 @@ import (
 	"context"
 	"slices"
 	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/urlkit"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 // Service handles operations for the bookmark domain.
 type Service struct {
-	r Repository
+	r            Repository
+	quotaService *quota.Service
 }
 
 // NewService initializes and returns a bookmark Service.
-func NewService(r Repository) *Service {
+//
+// quotaService may be nil, in which case the number of bookmarks is not limited.
+func NewService(r Repository, quotaService *quota.Service) *Service {
 	return &Service{
-		r: r,
+		r:            r,
+		quotaService: quotaService,
 	}
 }
 
@@ -29,13 +36,29 @@ func (s *Service) Add(ctx context.Context, bookmark Bookmark) error {
 	bookmark.CreatedAt = now
 	bookmark.UpdatedAt = now
 
-	bookmark.Normalize()
+	policy, err := s.tagPolicy(ctx, bookmark.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	bookmark.Normalize(policy)
 
 	if err := bookmark.ValidateForAddition(ctx, s.r); err != nil {
 		return err
 	}
 
-	return s.r.BookmarkAdd(ctx, bookmark)
+	var maxBookmarks int64
+
+	if s.quotaService != nil {
+		limits, err := s.quotaService.LimitsByUserUUID(ctx, bookmark.UserUUID)
+		if err != nil {
+			return err
+		}
+
+		maxBookmarks = limits.MaxBookmarks
+	}
+
+	return s.r.BookmarkAdd(ctx, bookmark, maxBookmarks)
 }
 
 // All returns all bookmarks for a given user.
@@ -72,7 +95,7 @@ func (s *Service) ByURL(ctx context.Context, userUUID string, u string) (Bookmar
 		URL:      u,
 	}
 
-	b.Normalize()
+	b.Normalize(TagPolicy{})
 
 	fns := []func() error{
 		b.requireURL,
@@ -88,6 +111,58 @@ func (s *Service) ByURL(ctx context.Context, userUUID string, u string) (Bookmar
 	return s.r.BookmarkGetByURL(ctx, userUUID, b.URL)
 }
 
+// BulkEdit applies a change to a selection of bookmarks, within a single transaction.
+//
+// Selected bookmarks that do not belong to the user, or are already in the requested
+// state, are left unchanged.
+func (s *Service) BulkEdit(ctx context.Context, bq BulkEditQuery) (BulkEditSummary, error) {
+	now := time.Now().UTC()
+
+	var policy TagPolicy
+
+	if bq.Action == BulkEditActionAddTags {
+		var err error
+
+		policy, err = s.tagPolicy(ctx, bq.UserUUID)
+		if err != nil {
+			return BulkEditSummary{}, err
+		}
+	}
+
+	bq.normalize(policy)
+
+	fns := []func() error{
+		bq.requireUserUUID,
+		bq.requireUIDs,
+		bq.validateUIDs,
+		bq.validateAction,
+		bq.requireTags,
+		bq.ensureTagsHaveNoWhitespace,
+	}
+
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return BulkEditSummary{}, err
+		}
+	}
+
+	summary, err := s.r.BookmarkBulkEdit(ctx, bq, func(b *Bookmark) bool {
+		if !bq.apply(b) {
+			return false
+		}
+
+		b.UpdatedAt = now
+		return true
+	})
+	if err != nil {
+		return BulkEditSummary{}, err
+	}
+
+	summary.Selected = int64(len(bq.UIDs))
+
+	return summary, nil
+}
+
 // Delete permanently deletes a bookmark.
 func (s *Service) Delete(ctx context.Context, userUUID, uid string) error {
 	b := Bookmark{
@@ -115,7 +190,12 @@ func (s *Service) Update(ctx context.Context, bookmark Bookmark) error {
 	now := time.Now().UTC()
 	bookmark.UpdatedAt = now
 
-	bookmark.Normalize()
+	policy, err := s.tagPolicy(ctx, bookmark.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	bookmark.Normalize(policy)
 
 	if err := bookmark.ValidateForUpdate(ctx, s.r); err != nil {
 		return err
@@ -124,7 +204,132 @@ func (s *Service) Update(ctx context.Context, bookmark Bookmark) error {
 	return s.r.BookmarkUpdate(ctx, bookmark)
 }
 
+// TagPolicyByUserUUID returns the rules applied to tags when bookmarks are saved or imported
+// for a given user.
+func (s *Service) TagPolicyByUserUUID(ctx context.Context, userUUID string) (TagPolicy, error) {
+	if err := requireUserUUID(userUUID); err != nil {
+		return TagPolicy{}, err
+	}
+
+	return TagPolicyByUserUUID(ctx, s.r, userUUID)
+}
+
+// tagPolicy returns the tag policy applied when saving a bookmark.
+//
+// Bookmarks with no user UUID are normalized without policy, and rejected when validated.
+func (s *Service) tagPolicy(ctx context.Context, userUUID string) (TagPolicy, error) {
+	if userUUID == "" {
+		return TagPolicy{}, nil
+	}
+
+	return TagPolicyByUserUUID(ctx, s.r, userUUID)
+}
+
+// TagPreferencesByUserUUID returns the tag preferences for a given user.
+func (s *Service) TagPreferencesByUserUUID(ctx context.Context, userUUID string) (TagPreferences, error) {
+	if err := requireUserUUID(userUUID); err != nil {
+		return TagPreferences{}, err
+	}
+
+	return tagPreferencesByUserUUID(ctx, s.r, userUUID)
+}
+
+// UpdateTagPreferences updates the tag preferences for a given user.
+//
+// The preferences apply to bookmarks saved or imported afterwards; existing tags are left
+// unchanged.
+func (s *Service) UpdateTagPreferences(ctx context.Context, preferences TagPreferences) error {
+	preferences.Normalize()
+
+	if err := preferences.ValidateForUpdate(); err != nil {
+		return err
+	}
+
+	preferences.UpdatedAt = time.Now().UTC()
+
+	return s.r.BookmarkTagPreferencesUpsert(ctx, preferences)
+}
+
+// TagAliases returns all tag aliases for a given user.
+func (s *Service) TagAliases(ctx context.Context, userUUID string) ([]TagAlias, error) {
+	if err := requireUserUUID(userUUID); err != nil {
+		return []TagAlias{}, err
+	}
+
+	return s.r.BookmarkTagAliasGetAll(ctx, userUUID)
+}
+
+// AddTagAlias adds a tag alias, replacing any existing alias with the same name.
+//
+// The alias applies to bookmarks saved or imported afterwards; to replace the aliased tag
+// on existing bookmarks, use MergeTags.
+func (s *Service) AddTagAlias(ctx context.Context, alias TagAlias) error {
+	alias.Normalize()
+
+	if err := alias.ValidateForAddition(); err != nil {
+		return err
+	}
+
+	alias.CreatedAt = time.Now().UTC()
+
+	aliases, err := s.r.BookmarkTagAliasGetAll(ctx, alias.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	return s.r.BookmarkTagAliasReplaceAll(ctx, alias.UserUUID, addTagAlias(aliases, alias))
+}
+
+// DeleteTagAlias deletes a given tag alias for a given user.
+func (s *Service) DeleteTagAlias(ctx context.Context, userUUID string, name string) error {
+	if err := requireUserUUID(userUUID); err != nil {
+		return err
+	}
+
+	aliases, err := s.r.BookmarkTagAliasGetAll(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(aliases), func(alias TagAlias) bool {
+		return alias.Alias == name
+	})
+
+	if len(remaining) == len(aliases) {
+		return ErrTagAliasNotFound
+	}
+
+	return s.r.BookmarkTagAliasReplaceAll(ctx, userUUID, remaining)
+}
+
+// renameTagAliasTargets renames the tags that a user's aliases point to.
+func (s *Service) renameTagAliasTargets(ctx context.Context, userUUID string, rename func(tag string) (string, bool)) error {
+	aliases, err := s.r.BookmarkTagAliasGetAll(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	aliases, changed := renameTagAliasTargets(aliases, rename)
+	if !changed {
+		return nil
+	}
+
+	return s.r.BookmarkTagAliasReplaceAll(ctx, userUUID, aliases)
+}
+
+// bookmarksByTag returns all bookmarks for a given user and tag, including bookmarks tagged
+// with descendants of the tag if withDescendants is true.
+func (s *Service) bookmarksByTag(ctx context.Context, userUUID string, tag string, withDescendants bool) ([]Bookmark, error) {
+	if withDescendants {
+		return s.r.BookmarkGetByTagWithDescendants(ctx, userUUID, tag)
+	}
+
+	return s.r.BookmarkGetByTag(ctx, userUUID, tag)
+}
+
 // DeleteTag deletes a given tag from all bookmarks for a given user.
+//
+// If the query cascades, descendants of the tag are deleted as well.
 func (s *Service) DeleteTag(ctx context.Context, dq TagDeleteQuery) (int64, error) {
 	now := time.Now().UTC()
 
@@ -142,19 +347,13 @@ func (s *Service) DeleteTag(ctx context.Context, dq TagDeleteQuery) (int64, erro
 		}
 	}
 
-	bookmarks, err := s.r.BookmarkGetByTag(ctx, dq.UserUUID, dq.Name)
+	bookmarks, err := s.bookmarksByTag(ctx, dq.UserUUID, dq.Name, dq.Cascade)
 	if err != nil {
 		return 0, err
 	}
 
 	for i, bookmark := range bookmarks {
-		for j, bookmarkTag := range bookmark.Tags {
-			if bookmarkTag == dq.Name {
-				bookmark.Tags = slices.Delete(bookmark.Tags, j, j+1)
-				break
-			}
-		}
-
+		bookmark.Tags = slices.DeleteFunc(bookmark.Tags, dq.matches)
 		bookmark.UpdatedAt = now
 
 		bookmarks[i] = bookmark
@@ -164,6 +363,9 @@ func (s *Service) DeleteTag(ctx context.Context, dq TagDeleteQuery) (int64, erro
 }
 
 // UpdateTag updates a given tag for all bookmarks for a given user.
+//
+// If the query cascades, descendants of the tag are renamed as well, keeping their
+// position in the hierarchy.
 func (s *Service) UpdateTag(ctx context.Context, uq TagUpdateQuery) (int64, error) {
 	now := time.Now().UTC()
 
@@ -183,7 +385,7 @@ func (s *Service) UpdateTag(ctx context.Context, uq TagUpdateQuery) (int64, erro
 		}
 	}
 
-	bookmarks, err := s.r.BookmarkGetByTag(ctx, uq.UserUUID, uq.CurrentName)
+	bookmarks, err := s.bookmarksByTag(ctx, uq.UserUUID, uq.CurrentName, uq.Cascade)
 	if err != nil {
 		return 0, err
 	}
@@ -194,8 +396,8 @@ func (s *Service) UpdateTag(ctx context.Context, uq TagUpdateQuery) (int64, erro
 
 	for i, bookmark := range bookmarks {
 		for j, bookmarkTag := range bookmark.Tags {
-			if bookmarkTag == uq.CurrentName {
-				bookmark.Tags[j] = uq.NewName
+			if newName, renamed := uq.rename(bookmarkTag); renamed {
+				bookmark.Tags[j] = newName
 			}
 		}
 
@@ -206,5 +408,177 @@ func (s *Service) UpdateTag(ctx context.Context, uq TagUpdateQuery) (int64, erro
 		bookmarks[i] = bookmark
 	}
 
-	return s.r.BookmarkTagUpdateMany(ctx, bookmarks)
+	updated, err := s.r.BookmarkTagUpdateMany(ctx, bookmarks)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.renameTagAliasTargets(ctx, uq.UserUUID, uq.rename); err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// MergeTags replaces several tags with a single tag, for all bookmarks of a given user.
+//
+// Aliases pointing to the merged tags are redirected to the target tag. If the query keeps
+// aliases, the merged tags become aliases of the target tag, so that they are replaced when
+// bookmarks are saved or imported afterwards.
+func (s *Service) MergeTags(ctx context.Context, mq TagMergeQuery) (int64, error) {
+	now := time.Now().UTC()
+
+	mq.normalize()
+
+	fns := []func() error{
+		mq.requireUserUUID,
+		mq.requireTarget,
+		mq.ensureTargetHasNoWhitespace,
+		mq.requireSources,
+		mq.ensureSourcesHaveNoWhitespace,
+	}
+
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return 0, err
+		}
+	}
+
+	var bookmarks []Bookmark
+	merged := map[string]bool{}
+
+	for _, source := range mq.Sources {
+		sourceBookmarks, err := s.r.BookmarkGetByTag(ctx, mq.UserUUID, source)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, bookmark := range sourceBookmarks {
+			if merged[bookmark.UID] {
+				continue
+			}
+
+			merged[bookmark.UID] = true
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+
+	for i, bookmark := range bookmarks {
+		for j, bookmarkTag := range bookmark.Tags {
+			if newName, renamed := mq.merge(bookmarkTag); renamed {
+				bookmark.Tags[j] = newName
+			}
+		}
+
+		bookmark.deduplicateTags()
+		bookmark.sortTags()
+		bookmark.UpdatedAt = now
+
+		bookmarks[i] = bookmark
+	}
+
+	updated, err := s.r.BookmarkTagUpdateMany(ctx, bookmarks)
+	if err != nil {
+		return 0, err
+	}
+
+	aliases, err := s.r.BookmarkTagAliasGetAll(ctx, mq.UserUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	aliases, changed := renameTagAliasTargets(aliases, mq.merge)
+
+	if mq.KeepAliases {
+		for _, source := range mq.Sources {
+			alias := TagAlias{
+				UserUUID:  mq.UserUUID,
+				Alias:     source,
+				Tag:       mq.Target,
+				CreatedAt: now,
+			}
+
+			// merging a tag into one of its descendants must not nest it further on every save
+			if err := alias.ensureTagIsNotAliased(); err != nil {
+				continue
+			}
+
+			aliases = addTagAlias(aliases, alias)
+		}
+
+		changed = true
+	}
+
+	if changed {
+		if err := s.r.BookmarkTagAliasReplaceAll(ctx, mq.UserUUID, aliases); err != nil {
+			return 0, err
+		}
+	}
+
+	return updated, nil
+}
+
+// CanonicalizeURLs canonicalizes the URLs of all bookmarks for a given user.
+//
+// Bookmarks whose canonical URL would be shared with other bookmarks are left unchanged,
+// and reported as duplicates to be resolved manually. If dryRun is true, the report is
+// computed but no bookmark is updated.
+func (s *Service) CanonicalizeURLs(ctx context.Context, userUUID string, dryRun bool) (URLCanonicalizationReport, error) {
+	if err := requireUserUUID(userUUID); err != nil {
+		return URLCanonicalizationReport{}, err
+	}
+
+	bookmarks, err := s.r.BookmarkGetAll(ctx, userUUID)
+	if err != nil {
+		return URLCanonicalizationReport{}, err
+	}
+
+	var canonicalURLs []string
+	bookmarksByCanonicalURL := map[string][]Bookmark{}
+
+	for _, b := range bookmarks {
+		canonicalURL := urlkit.Canonicalize(b.URL)
+
+		if _, ok := bookmarksByCanonicalURL[canonicalURL]; !ok {
+			canonicalURLs = append(canonicalURLs, canonicalURL)
+		}
+
+		bookmarksByCanonicalURL[canonicalURL] = append(bookmarksByCanonicalURL[canonicalURL], b)
+	}
+
+	report := URLCanonicalizationReport{
+		UserUUID: userUUID,
+	}
+
+	for _, canonicalURL := range canonicalURLs {
+		group := bookmarksByCanonicalURL[canonicalURL]
+
+		if len(group) > 1 {
+			report.Duplicates = append(report.Duplicates, URLDuplicate{
+				CanonicalURL: canonicalURL,
+				Bookmarks:    group,
+			})
+			continue
+		}
+
+		b := group[0]
+		if b.URL == canonicalURL {
+			continue
+		}
+
+		report.Canonicalized++
+
+		if dryRun {
+			continue
+		}
+
+		// The bookmark content is unchanged: preserve its modification date.
+		b.URL = canonicalURL
+
+		if err := s.r.BookmarkUpdate(ctx, b); err != nil {
+			return URLCanonicalizationReport{}, err
+		}
+	}
+
+	return report, nil
 }
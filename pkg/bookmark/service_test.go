@@ -12,11 +12,13 @@ import (
 
 func TestServiceAdd(t *testing.T) {
 	cases := []struct {
-		tname               string
-		repositoryBookmarks []Bookmark
-		bookmark            Bookmark
-		want                Bookmark
-		wantErr             error
+		tname                    string
+		repositoryBookmarks      []Bookmark
+		repositoryTagAliases     []TagAlias
+		repositoryTagPreferences []TagPreferences
+		bookmark                 Bookmark
+		want                     Bookmark
+		wantErr                  error
 	}{
 		// error cases
 		{
@@ -233,14 +235,94 @@ func TestServiceAdd(t *testing.T) {
 				},
 			},
 		},
+		{
+			tname: "add bookmark with hierarchical tags",
+			bookmark: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"dev/go/testing",
+					"/dev//go/",
+					"/",
+				},
+			},
+			want: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"dev/go",
+					"dev/go/testing",
+				},
+			},
+		},
+		{
+			tname: "add bookmark with aliased tags",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096", Alias: "golang", Tag: "go"},
+				{UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096", Alias: "k8s", Tag: "kubernetes"},
+			},
+			bookmark: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"GoLang",
+					"golang/testing",
+					"go",
+					"K8s",
+				},
+			},
+			want: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"go",
+					"go/testing",
+					"kubernetes",
+				},
+			},
+		},
+		{
+			tname: "add bookmark with lowercase tags",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096", Alias: "js", Tag: "JavaScript"},
+			},
+			repositoryTagPreferences: []TagPreferences{
+				{UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096", CaseFolding: TagCaseFoldingLowercase},
+			},
+			bookmark: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"Dev/Go",
+					"dev/go",
+					"JS",
+				},
+			},
+			want: Bookmark{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				URL:      "https://domain.tld",
+				Title:    "Example Domain",
+				Tags: []string{
+					"dev/go",
+					"javascript",
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{
-				Bookmarks: tc.repositoryBookmarks,
+				Bookmarks:      tc.repositoryBookmarks,
+				TagAliases:     tc.repositoryTagAliases,
+				TagPreferences: tc.repositoryTagPreferences,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			err := s.Add(t.Context(), tc.bookmark)
 
@@ -322,7 +404,7 @@ func TestServiceByUID(t *testing.T) {
 			r := &FakeRepository{
 				Bookmarks: tc.repositoryBookmarks,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			got, err := s.ByUID(t.Context(), tc.userUUID, tc.uid)
 
@@ -401,7 +483,7 @@ func TestServiceDelete(t *testing.T) {
 			r := &FakeRepository{
 				Bookmarks: tc.repositoryBookmarks,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			err := s.Delete(t.Context(), tc.userUUID, tc.uid)
 
@@ -618,7 +700,7 @@ func TestServiceUpdate(t *testing.T) {
 			r := &FakeRepository{
 				Bookmarks: tc.repositoryBookmarks,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			err := s.Update(t.Context(), tc.bookmark)
 
@@ -714,6 +796,73 @@ func TestServiceDeleteTag(t *testing.T) {
 				},
 			},
 		},
+		{
+			tname: "delete hierarchical tag, keep descendants",
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"dev", "dev/go", "devops"},
+					Title:    "Example Domain",
+				},
+			},
+			tagDeleteQuery: TagDeleteQuery{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				Name:     "dev/",
+			},
+			want: 1,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"dev/go", "devops"},
+					Title:    "Example Domain",
+				},
+			},
+		},
+		{
+			tname: "delete hierarchical tag and its descendants",
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"dev", "dev/go", "dev/go/testing", "devops"},
+					Title:    "Example Domain",
+				},
+				{
+					UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://other.tld",
+					Tags:     []string{"dev/rust", "video"},
+					Title:    "Other Domain",
+				},
+			},
+			tagDeleteQuery: TagDeleteQuery{
+				UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				Name:     "dev",
+				Cascade:  true,
+			},
+			want: 2,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"devops"},
+					Title:    "Example Domain",
+				},
+				{
+					UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://other.tld",
+					Tags:     []string{"video"},
+					Title:    "Other Domain",
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -721,7 +870,7 @@ func TestServiceDeleteTag(t *testing.T) {
 			r := &FakeRepository{
 				Bookmarks: tc.repositoryBookmarks,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			got, err := s.DeleteTag(t.Context(), tc.tagDeleteQuery)
 
@@ -873,6 +1022,75 @@ func TestServiceUpdateTag(t *testing.T) {
 				},
 			},
 		},
+		{
+			tname: "rename hierarchical tag, keep descendants",
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"dev", "dev/go"},
+					Title:    "Example Domain",
+				},
+			},
+			tagNameUpdate: TagUpdateQuery{
+				UserUUID:    "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				CurrentName: "dev",
+				NewName:     "/code/",
+			},
+			want: 1,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"code", "dev/go"},
+					Title:    "Example Domain",
+				},
+			},
+		},
+		{
+			tname: "rename hierarchical tag and its descendants",
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"dev", "dev/go", "dev/go/testing", "devops"},
+					Title:    "Example Domain",
+				},
+				{
+					UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://other.tld",
+					Tags:     []string{"code/go", "dev/go"},
+					Title:    "Other Domain",
+				},
+			},
+			tagNameUpdate: TagUpdateQuery{
+				UserUUID:    "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+				CurrentName: "dev",
+				NewName:     "code",
+				Cascade:     true,
+			},
+			want: 2,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://domain.tld",
+					Tags:     []string{"code", "code/go", "code/go/testing", "devops"},
+					Title:    "Example Domain",
+				},
+				{
+					UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+					UserUUID: "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096",
+					URL:      "https://other.tld",
+					Tags:     []string{"code/go"},
+					Title:    "Other Domain",
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -880,7 +1098,7 @@ func TestServiceUpdateTag(t *testing.T) {
 			r := &FakeRepository{
 				Bookmarks: tc.repositoryBookmarks,
 			}
-			s := NewService(r)
+			s := NewService(r, nil)
 
 			got, err := s.UpdateTag(t.Context(), tc.tagNameUpdate)
 
@@ -908,3 +1126,954 @@ func TestServiceUpdateTag(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceMergeTags(t *testing.T) {
+	userUUID := "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+
+	repositoryBookmarks := func() []Bookmark {
+		return []Bookmark{
+			{
+				UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+				UserUUID: userUUID,
+				URL:      "https://domain.tld",
+				Tags:     []string{"Go", "golang", "testing"},
+				Title:    "Example Domain",
+			},
+			{
+				UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+				UserUUID: userUUID,
+				URL:      "https://other.tld",
+				Tags:     []string{"go", "golang/testing"},
+				Title:    "Other Domain",
+			},
+			{
+				UID:      "2Ecp9wXhdPNxUyFbtaRTwkzUGdX",
+				UserUUID: userUUID,
+				URL:      "https://third.tld",
+				Tags:     []string{"rust"},
+				Title:    "Third Domain",
+			},
+		}
+	}
+
+	cases := []struct {
+		tname                    string
+		repositoryTagAliases     []TagAlias
+		mergeQuery               TagMergeQuery
+		want                     int64
+		wantErr                  error
+		wantRepositoryBookmarks  []Bookmark
+		wantRepositoryTagAliases []TagAlias
+	}{
+		// error cases
+		{
+			tname: "missing user UUID",
+			mergeQuery: TagMergeQuery{
+				Sources: []string{"golang"},
+				Target:  "go",
+			},
+			wantErr: user.ErrUUIDRequired,
+		},
+		{
+			tname: "missing target",
+			mergeQuery: TagMergeQuery{
+				UserUUID: userUUID,
+				Sources:  []string{"golang"},
+			},
+			wantErr: ErrTagNameRequired,
+		},
+		{
+			tname: "target contains whitespace",
+			mergeQuery: TagMergeQuery{
+				UserUUID: userUUID,
+				Sources:  []string{"golang"},
+				Target:   "go lang",
+			},
+			wantErr: ErrTagNameContainsWhitespace,
+		},
+		{
+			tname: "missing sources",
+			mergeQuery: TagMergeQuery{
+				UserUUID: userUUID,
+				Sources:  []string{" ", "go"},
+				Target:   "go",
+			},
+			wantErr: ErrTagMergeSourcesRequired,
+		},
+		{
+			tname: "source contains whitespace",
+			mergeQuery: TagMergeQuery{
+				UserUUID: userUUID,
+				Sources:  []string{"go lang"},
+				Target:   "go",
+			},
+			wantErr: ErrTagNameContainsWhitespace,
+		},
+
+		// nominal cases
+		{
+			tname: "merge tags",
+			mergeQuery: TagMergeQuery{
+				UserUUID: userUUID,
+				Sources:  []string{"Go", "golang", "golang"},
+				Target:   "go",
+			},
+			want: 1,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:   "https://domain.tld",
+					Tags:  []string{"go", "testing"},
+					Title: "Example Domain",
+				},
+				{
+					URL:   "https://other.tld",
+					Tags:  []string{"go", "golang/testing"},
+					Title: "Other Domain",
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"rust"},
+					Title: "Third Domain",
+				},
+			},
+		},
+		{
+			tname: "merge tags and keep aliases",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "gopher", Tag: "golang"},
+				{UserUUID: userUUID, Alias: "go", Tag: "golang"},
+			},
+			mergeQuery: TagMergeQuery{
+				UserUUID:    userUUID,
+				Sources:     []string{"golang", "golang/testing"},
+				Target:      "go",
+				KeepAliases: true,
+			},
+			want: 2,
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:   "https://domain.tld",
+					Tags:  []string{"Go", "go", "testing"},
+					Title: "Example Domain",
+				},
+				{
+					URL:   "https://other.tld",
+					Tags:  []string{"go"},
+					Title: "Other Domain",
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"rust"},
+					Title: "Third Domain",
+				},
+			},
+			wantRepositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "golang", Tag: "go"},
+				{UserUUID: userUUID, Alias: "golang/testing", Tag: "go"},
+				{UserUUID: userUUID, Alias: "gopher", Tag: "go"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks:  repositoryBookmarks(),
+				TagAliases: tc.repositoryTagAliases,
+			}
+			s := NewService(r, nil)
+
+			got, err := s.MergeTags(t.Context(), tc.mergeQuery)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("want %d updated bookmarks, got %d", tc.want, got)
+			}
+
+			for index, bookmark := range r.Bookmarks {
+				AssertBookmarkEquals(t, bookmark, tc.wantRepositoryBookmarks[index])
+			}
+
+			assertTagAliasesEqual(t, r.TagAliases, tc.wantRepositoryTagAliases)
+		})
+	}
+}
+
+func TestServiceAddTagAlias(t *testing.T) {
+	userUUID := "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+
+	cases := []struct {
+		tname                    string
+		repositoryTagAliases     []TagAlias
+		alias                    TagAlias
+		wantErr                  error
+		wantRepositoryTagAliases []TagAlias
+	}{
+		// error cases
+		{
+			tname: "missing user UUID",
+			alias: TagAlias{
+				Alias: "golang",
+				Tag:   "go",
+			},
+			wantErr: user.ErrUUIDRequired,
+		},
+		{
+			tname: "missing alias",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    " ",
+				Tag:      "go",
+			},
+			wantErr: ErrTagNameRequired,
+		},
+		{
+			tname: "missing tag",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "golang",
+			},
+			wantErr: ErrTagNameRequired,
+		},
+		{
+			tname: "alias contains whitespace",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "go lang",
+				Tag:      "go",
+			},
+			wantErr: ErrTagNameContainsWhitespace,
+		},
+		{
+			tname: "alias identical to tag",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "go/",
+				Tag:      "go",
+			},
+			wantErr: ErrTagAliasSameAsTag,
+		},
+		{
+			tname: "alias applies to its own tag",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "dev",
+				Tag:      "DEV/go",
+			},
+			wantErr: ErrTagAliasSameAsTag,
+		},
+
+		// nominal cases
+		{
+			tname: "add alias",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "k8s", Tag: "kubernetes"},
+			},
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    " golang ",
+				Tag:      "go",
+			},
+			wantRepositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "golang", Tag: "go"},
+				{UserUUID: userUUID, Alias: "k8s", Tag: "kubernetes"},
+			},
+		},
+		{
+			tname: "add case preference alias",
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "javascript",
+				Tag:      "JavaScript",
+			},
+			wantRepositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "javascript", Tag: "JavaScript"},
+			},
+		},
+		{
+			tname: "replace alias and redirect aliases pointing to it",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "Golang", Tag: "gopher"},
+				{UserUUID: userUUID, Alias: "go-lang", Tag: "golang"},
+				{UserUUID: userUUID, Alias: "go", Tag: "golang"},
+			},
+			alias: TagAlias{
+				UserUUID: userUUID,
+				Alias:    "golang",
+				Tag:      "go",
+			},
+			wantRepositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "go-lang", Tag: "go"},
+				{UserUUID: userUUID, Alias: "golang", Tag: "go"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				TagAliases: tc.repositoryTagAliases,
+			}
+			s := NewService(r, nil)
+
+			err := s.AddTagAlias(t.Context(), tc.alias)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			assertTagAliasesEqual(t, r.TagAliases, tc.wantRepositoryTagAliases)
+		})
+	}
+}
+
+func TestServiceDeleteTagAlias(t *testing.T) {
+	userUUID := "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+
+	cases := []struct {
+		tname                    string
+		repositoryTagAliases     []TagAlias
+		userUUID                 string
+		alias                    string
+		wantErr                  error
+		wantRepositoryTagAliases []TagAlias
+	}{
+		// error cases
+		{
+			tname:   "missing user UUID",
+			alias:   "golang",
+			wantErr: user.ErrUUIDRequired,
+		},
+		{
+			tname: "not found",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: "e4b4a9b6-52d5-4bb4-a1c4-9f9f5f0d9e6a", Alias: "golang", Tag: "go"},
+			},
+			userUUID: userUUID,
+			alias:    "golang",
+			wantErr:  ErrTagAliasNotFound,
+		},
+
+		// nominal cases
+		{
+			tname: "delete alias",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "golang", Tag: "go"},
+				{UserUUID: userUUID, Alias: "k8s", Tag: "kubernetes"},
+			},
+			userUUID: userUUID,
+			alias:    "golang",
+			wantRepositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "k8s", Tag: "kubernetes"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				TagAliases: tc.repositoryTagAliases,
+			}
+			s := NewService(r, nil)
+
+			err := s.DeleteTagAlias(t.Context(), tc.userUUID, tc.alias)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			assertTagAliasesEqual(t, r.TagAliases, tc.wantRepositoryTagAliases)
+		})
+	}
+}
+
+func TestServiceUpdateTagPreferences(t *testing.T) {
+	userUUID := "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+
+	cases := []struct {
+		tname       string
+		preferences TagPreferences
+		want        TagPreferences
+		wantErr     error
+	}{
+		// error cases
+		{
+			tname: "missing user UUID",
+			preferences: TagPreferences{
+				CaseFolding: TagCaseFoldingLowercase,
+			},
+			wantErr: user.ErrUUIDRequired,
+		},
+		{
+			tname: "unknown case folding",
+			preferences: TagPreferences{
+				UserUUID:    userUUID,
+				CaseFolding: "UPPERCASE",
+			},
+			wantErr: ErrTagCaseFoldingUnknown,
+		},
+
+		// nominal cases
+		{
+			tname: "default case folding",
+			preferences: TagPreferences{
+				UserUUID: userUUID,
+			},
+			want: TagPreferences{
+				UserUUID:    userUUID,
+				CaseFolding: TagCaseFoldingPreserve,
+			},
+		},
+		{
+			tname: "lowercase tags",
+			preferences: TagPreferences{
+				UserUUID:    userUUID,
+				CaseFolding: TagCaseFoldingLowercase,
+			},
+			want: TagPreferences{
+				UserUUID:    userUUID,
+				CaseFolding: TagCaseFoldingLowercase,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{}
+			s := NewService(r, nil)
+
+			err := s.UpdateTagPreferences(t.Context(), tc.preferences)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			got, err := s.TagPreferencesByUserUUID(t.Context(), tc.preferences.UserUUID)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.CaseFolding != tc.want.CaseFolding {
+				t.Errorf("want case folding %q, got %q", tc.want.CaseFolding, got.CaseFolding)
+			}
+		})
+	}
+}
+
+func assertTagAliasesEqual(t *testing.T, got []TagAlias, want []TagAlias) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("want %d tag aliases, got %d", len(want), len(got))
+	}
+
+	for i, wantAlias := range want {
+		if got[i].Alias != wantAlias.Alias || got[i].Tag != wantAlias.Tag {
+			t.Errorf("want alias %d %q -> %q, got %q -> %q", i, wantAlias.Alias, wantAlias.Tag, got[i].Alias, got[i].Tag)
+		}
+	}
+}
+
+func TestServiceBulkEdit(t *testing.T) {
+	userUUID := "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+	otherUserUUID := "b9e785dc-3613-4d8d-909b-31a4728b530d"
+
+	repositoryBookmarks := func() []Bookmark {
+		return []Bookmark{
+			{
+				UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+				UserUUID: userUUID,
+				URL:      "https://domain.tld",
+				Tags:     []string{"go", "testing"},
+				Title:    "Example Domain",
+			},
+			{
+				UID:      "2Ecp9vyGeHJ9rLMNxMn6cAYrTun",
+				UserUUID: userUUID,
+				URL:      "https://other.tld",
+				Tags:     []string{"rust"},
+				Title:    "Other Domain",
+				Private:  true,
+			},
+			{
+				UID:      "2Ecp9wXhdPNxUyFbtaRTwkzUGdX",
+				UserUUID: otherUserUUID,
+				URL:      "https://third.tld",
+				Tags:     []string{"go"},
+				Title:    "Other User's Domain",
+			},
+		}
+	}
+
+	selectedUIDs := []string{"27L4DoEZaRASKhQKygRCrvVAwkr", "2Ecp9vyGeHJ9rLMNxMn6cAYrTun", "2Ecp9wXhdPNxUyFbtaRTwkzUGdX"}
+
+	cases := []struct {
+		tname                   string
+		repositoryTagAliases    []TagAlias
+		bulkEditQuery           BulkEditQuery
+		want                    BulkEditSummary
+		wantErr                 error
+		wantRepositoryBookmarks []Bookmark
+	}{
+		// error cases
+		{
+			tname: "missing user UUID",
+			bulkEditQuery: BulkEditQuery{
+				UIDs:   selectedUIDs,
+				Action: BulkEditActionSetPrivate,
+			},
+			wantErr: user.ErrUUIDRequired,
+		},
+		{
+			tname: "no bookmarks selected",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     []string{""},
+				Action:   BulkEditActionSetPrivate,
+			},
+			wantErr: ErrSelectionRequired,
+		},
+		{
+			tname: "invalid UID",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     []string{"invalid"},
+				Action:   BulkEditActionSetPrivate,
+			},
+			wantErr: ErrUIDInvalid,
+		},
+		{
+			tname: "unknown action",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   "archive",
+			},
+			wantErr: ErrBulkEditActionUnknown,
+		},
+		{
+			tname: "missing tags",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   BulkEditActionAddTags,
+				Tags:     []string{" "},
+			},
+			wantErr: ErrTagNameRequired,
+		},
+		{
+			tname: "tag contains whitespace",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   BulkEditActionRemoveTags,
+				Tags:     []string{"go lang"},
+			},
+			wantErr: ErrTagNameContainsWhitespace,
+		},
+
+		// nominal cases
+		{
+			tname: "add tags",
+			repositoryTagAliases: []TagAlias{
+				{UserUUID: userUUID, Alias: "golang", Tag: "go"},
+			},
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   BulkEditActionAddTags,
+				Tags:     []string{"golang", "dev"},
+			},
+			want: BulkEditSummary{Selected: 3, Updated: 2},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:   "https://domain.tld",
+					Tags:  []string{"dev", "go", "testing"},
+					Title: "Example Domain",
+				},
+				{
+					URL:     "https://other.tld",
+					Tags:    []string{"dev", "go", "rust"},
+					Title:   "Other Domain",
+					Private: true,
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"go"},
+					Title: "Other User's Domain",
+				},
+			},
+		},
+		{
+			tname: "remove tags",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   BulkEditActionRemoveTags,
+				Tags:     []string{"go"},
+			},
+			want: BulkEditSummary{Selected: 3, Updated: 1},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:   "https://domain.tld",
+					Tags:  []string{"testing"},
+					Title: "Example Domain",
+				},
+				{
+					URL:     "https://other.tld",
+					Tags:    []string{"rust"},
+					Title:   "Other Domain",
+					Private: true,
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"go"},
+					Title: "Other User's Domain",
+				},
+			},
+		},
+		{
+			tname: "set public",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     selectedUIDs,
+				Action:   BulkEditActionSetPublic,
+			},
+			want: BulkEditSummary{Selected: 3, Updated: 1},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:   "https://domain.tld",
+					Tags:  []string{"go", "testing"},
+					Title: "Example Domain",
+				},
+				{
+					URL:   "https://other.tld",
+					Tags:  []string{"rust"},
+					Title: "Other Domain",
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"go"},
+					Title: "Other User's Domain",
+				},
+			},
+		},
+		{
+			tname: "delete",
+			bulkEditQuery: BulkEditQuery{
+				UserUUID: userUUID,
+				UIDs:     []string{"27L4DoEZaRASKhQKygRCrvVAwkr", "2Ecp9wXhdPNxUyFbtaRTwkzUGdX", "27L4DoEZaRASKhQKygRCrvVAwkr"},
+				Action:   BulkEditActionDelete,
+			},
+			want: BulkEditSummary{Selected: 2, Deleted: 1},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					URL:     "https://other.tld",
+					Tags:    []string{"rust"},
+					Title:   "Other Domain",
+					Private: true,
+				},
+				{
+					URL:   "https://third.tld",
+					Tags:  []string{"go"},
+					Title: "Other User's Domain",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks:  repositoryBookmarks(),
+				TagAliases: tc.repositoryTagAliases,
+			}
+			s := NewService(r, nil)
+
+			got, err := s.BulkEdit(t.Context(), tc.bulkEditQuery)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.Selected != tc.want.Selected {
+				t.Errorf("want %d selected bookmarks, got %d", tc.want.Selected, got.Selected)
+			}
+			if got.Updated != tc.want.Updated {
+				t.Errorf("want %d updated bookmarks, got %d", tc.want.Updated, got.Updated)
+			}
+			if got.Deleted != tc.want.Deleted {
+				t.Errorf("want %d deleted bookmarks, got %d", tc.want.Deleted, got.Deleted)
+			}
+			if int64(len(got.Bookmarks)) != got.Updated+got.Deleted {
+				t.Errorf("want %d affected bookmarks, got %d", got.Updated+got.Deleted, len(got.Bookmarks))
+			}
+
+			if len(r.Bookmarks) != len(tc.wantRepositoryBookmarks) {
+				t.Fatalf("want %d bookmarks, got %d", len(tc.wantRepositoryBookmarks), len(r.Bookmarks))
+			}
+
+			for index, bookmark := range r.Bookmarks {
+				AssertBookmarkEquals(t, bookmark, tc.wantRepositoryBookmarks[index])
+			}
+		})
+	}
+}
+
+func TestServiceCanonicalizeURLs(t *testing.T) {
+	const userUUID = "6fe6a0c6-62da-4d05-b0c5-dc9d6ef58096"
+
+	cases := []struct {
+		tname                   string
+		userUUID                string
+		repositoryBookmarks     []Bookmark
+		dryRun                  bool
+		want                    URLCanonicalizationReport
+		wantRepositoryBookmarks []Bookmark
+		wantErr                 error
+	}{
+		// error cases
+		{
+			tname:   "missing user UUID",
+			wantErr: user.ErrUUIDRequired,
+		},
+
+		// nominal cases
+		{
+			tname:    "no bookmarks",
+			userUUID: userUUID,
+			want: URLCanonicalizationReport{
+				UserUUID: userUUID,
+			},
+		},
+		{
+			tname:    "canonical URL",
+			userUUID: userUUID,
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles",
+				},
+			},
+			want: URLCanonicalizationReport{
+				UserUUID: userUUID,
+			},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles",
+				},
+			},
+		},
+		{
+			tname:    "tracking parameters",
+			userUUID: userUUID,
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://Domain.tld/articles/?utm_source=rss",
+				},
+			},
+			want: URLCanonicalizationReport{
+				UserUUID:      userUUID,
+				Canonicalized: 1,
+			},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles",
+				},
+			},
+		},
+		{
+			tname:    "tracking parameters, dry run",
+			userUUID: userUUID,
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles?fbclid=abc",
+				},
+			},
+			dryRun: true,
+			want: URLCanonicalizationReport{
+				UserUUID:      userUUID,
+				Canonicalized: 1,
+			},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles?fbclid=abc",
+				},
+			},
+		},
+		{
+			tname:    "duplicates",
+			userUUID: userUUID,
+			repositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles",
+				},
+				{
+					UID:      "27L4GHH8fHrbcmYkHUqEVKRsBsS",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles/?utm_source=rss",
+				},
+				{
+					UID:      "27L4IbqUOJMDmgtTiqBGrUaI4RL",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/about/",
+				},
+			},
+			want: URLCanonicalizationReport{
+				UserUUID:      userUUID,
+				Canonicalized: 1,
+				Duplicates: []URLDuplicate{
+					{
+						CanonicalURL: "https://domain.tld/articles",
+						Bookmarks: []Bookmark{
+							{
+								UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+								UserUUID: userUUID,
+								URL:      "https://domain.tld/articles",
+							},
+							{
+								UID:      "27L4GHH8fHrbcmYkHUqEVKRsBsS",
+								UserUUID: userUUID,
+								URL:      "https://domain.tld/articles/?utm_source=rss",
+							},
+						},
+					},
+				},
+			},
+			wantRepositoryBookmarks: []Bookmark{
+				{
+					UID:      "27L4DoEZaRASKhQKygRCrvVAwkr",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles",
+				},
+				{
+					UID:      "27L4GHH8fHrbcmYkHUqEVKRsBsS",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/articles/?utm_source=rss",
+				},
+				{
+					UID:      "27L4IbqUOJMDmgtTiqBGrUaI4RL",
+					UserUUID: userUUID,
+					URL:      "https://domain.tld/about",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Bookmarks: tc.repositoryBookmarks,
+			}
+			s := NewService(r, nil)
+
+			got, err := s.CanonicalizeURLs(t.Context(), tc.userUUID, tc.dryRun)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.UserUUID != tc.want.UserUUID {
+				t.Errorf("want user UUID %q, got %q", tc.want.UserUUID, got.UserUUID)
+			}
+
+			if got.Canonicalized != tc.want.Canonicalized {
+				t.Errorf("want %d canonicalized URLs, got %d", tc.want.Canonicalized, got.Canonicalized)
+			}
+
+			if len(got.Duplicates) != len(tc.want.Duplicates) {
+				t.Fatalf("want %d duplicates, got %d", len(tc.want.Duplicates), len(got.Duplicates))
+			}
+
+			for i, wantDuplicate := range tc.want.Duplicates {
+				if got.Duplicates[i].CanonicalURL != wantDuplicate.CanonicalURL {
+					t.Errorf("want duplicate %d canonical URL %q, got %q", i, wantDuplicate.CanonicalURL, got.Duplicates[i].CanonicalURL)
+				}
+
+				for index, bookmark := range got.Duplicates[i].Bookmarks {
+					AssertBookmarkEquals(t, bookmark, wantDuplicate.Bookmarks[index])
+				}
+			}
+
+			for index, bookmark := range r.Bookmarks {
+				AssertBookmarkEquals(t, bookmark, tc.wantRepositoryBookmarks[index])
+			}
+		})
+	}
+}
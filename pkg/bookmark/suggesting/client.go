@@ -0,0 +1,96 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+)
+
+const (
+	// responseBodyMaxBytes bounds how much of the response body is read and parsed.
+	responseBodyMaxBytes = 2 * 1024 * 1024
+)
+
+// A Client performs outgoing HTTP requests to retrieve Web page metadata.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient initializes and returns a Client using httpClient to perform requests as-is.
+//
+// This client must only be used when HTTP/HTTPS traffic goes through a proxy, or for testing.
+func NewClient(httpClient *http.Client, userAgent string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		userAgent:  userAgent,
+	}
+}
+
+// NewSafeClient initializes and returns a Client backed by an SSRF-guarded http.Client.
+//
+// It MUST NOT be used when HTTP/HTTPS traffic goes through a proxy (otherwise all requests will be blocked).
+func NewSafeClient(userAgent string, timeout time.Duration) (*Client, error) {
+	transport, err := httpsafe.NewSafeTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	return NewClient(httpClient, userAgent), nil
+}
+
+// Fetch retrieves a Web page and extracts its Metadata.
+func (c *Client) Fetch(ctx context.Context, pageURL string) (Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("suggesting: failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("suggesting: failed to perform request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Metadata{}, fmt.Errorf("%w: status %d", ErrPageUnavailable, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Metadata{}, fmt.Errorf("%w: %q", ErrContentTypeUnsupported, mediaType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, responseBodyMaxBytes), contentType)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("suggesting: failed to decode response body: %w", err)
+	}
+
+	metadata, err := ParseMetadata(body, resp.Request.URL)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("suggesting: failed to parse response body: %w", err)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,5 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+// Package suggesting retrieves Web pages to suggest a title, description and tags when adding a bookmark.
+package suggesting
@@ -0,0 +1,11 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import "errors"
+
+var (
+	ErrContentTypeUnsupported = errors.New("suggesting: unsupported content type")
+	ErrPageUnavailable        = errors.New("suggesting: page unavailable")
+)
@@ -0,0 +1,155 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// metadataTextMaxLength bounds the length of the text extracted from the page body.
+	metadataTextMaxLength = 100_000
+)
+
+// Metadata holds the information extracted from a Web page.
+type Metadata struct {
+	// Title of the page, from the <title> element, or its OpenGraph / Twitter card title.
+	Title string
+
+	// Description of the page, from its OpenGraph / Twitter card description, or its description meta tag.
+	Description string
+
+	// CanonicalURL is the URL the page declares as its canonical location, if any.
+	CanonicalURL string
+
+	// SiteName is the name of the website the page belongs to, if any.
+	SiteName string
+
+	// Text is the readable text of the page body.
+	Text string
+}
+
+// ParseMetadata parses an HTML document and extracts its Metadata.
+//
+// Relative canonical URLs are resolved against pageURL.
+func ParseMetadata(r io.Reader, pageURL *url.URL) (Metadata, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var (
+		title        string
+		meta         = make(map[string]string)
+		canonicalURL string
+		text         strings.Builder
+	)
+
+	var walk func(n *html.Node, inBody bool)
+	walk = func(n *html.Node, inBody bool) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Iframe:
+				return
+
+			case atom.Title:
+				if title == "" && n.FirstChild != nil {
+					title = collapseWhitespace(n.FirstChild.Data)
+				}
+				return
+
+			case atom.Meta:
+				key := attr(n, "property")
+				if key == "" {
+					key = attr(n, "name")
+				}
+				key = strings.ToLower(key)
+
+				if _, ok := meta[key]; key != "" && !ok {
+					meta[key] = collapseWhitespace(attr(n, "content"))
+				}
+				return
+
+			case atom.Link:
+				if canonicalURL == "" && strings.EqualFold(attr(n, "rel"), "canonical") {
+					canonicalURL = resolveURL(pageURL, attr(n, "href"))
+				}
+				return
+
+			case atom.Body:
+				inBody = true
+			}
+		}
+
+		if n.Type == html.TextNode && inBody && text.Len() < metadataTextMaxLength {
+			if data := collapseWhitespace(n.Data); data != "" {
+				text.WriteString(data)
+				text.WriteString(" ")
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inBody)
+		}
+	}
+
+	walk(doc, false)
+
+	metadata := Metadata{
+		Title:        firstNonEmpty(title, meta["og:title"], meta["twitter:title"]),
+		Description:  firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+		CanonicalURL: canonicalURL,
+		SiteName:     meta["og:site_name"],
+		Text:         strings.TrimSpace(text.String()),
+	}
+
+	return metadata, nil
+}
+
+// attr returns the value of a given attribute for a HTML element node.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// collapseWhitespace trims leading and trailing whitespace, and replaces inner whitespace with single spaces.
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// resolveURL resolves a (possibly relative) HTTP(S) URL reference against a base URL.
+func resolveURL(base *url.URL, ref string) string {
+	refURL, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || ref == "" {
+		return ""
+	}
+
+	resolved := base.ResolveReference(refURL)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+
+	return resolved.String()
+}
@@ -0,0 +1,109 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	pageURL, err := url.Parse("https://example.org/articles/1?page=2")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %q", err)
+	}
+
+	cases := []struct {
+		tname    string
+		document string
+		want     Metadata
+	}{
+		{
+			tname:    "empty document",
+			document: "",
+			want:     Metadata{},
+		},
+		{
+			tname: "title and description",
+			document: `<html><head>
+<title>
+  A  page
+  title
+</title>
+<meta name="description" content="Plain description">
+</head><body><p>Hello</p><p>World</p></body></html>`,
+			want: Metadata{
+				Title:       "A page title",
+				Description: "Plain description",
+				Text:        "Hello World",
+			},
+		},
+		{
+			tname: "OpenGraph and Twitter card metadata",
+			document: `<html><head>
+<meta property="og:title" content="OpenGraph title">
+<meta name="twitter:description" content="Twitter description">
+<meta property="og:description" content="OpenGraph description">
+<meta property="og:site_name" content="Example Site">
+<meta name="description" content="Plain description">
+</head><body></body></html>`,
+			want: Metadata{
+				Title:       "OpenGraph title",
+				Description: "OpenGraph description",
+				SiteName:    "Example Site",
+			},
+		},
+		{
+			tname: "relative canonical URL",
+			document: `<html><head>
+<title>Canonical</title>
+<link rel="canonical" href="/articles/1">
+</head></html>`,
+			want: Metadata{
+				Title:        "Canonical",
+				CanonicalURL: "https://example.org/articles/1",
+			},
+		},
+		{
+			tname: "non-HTTP canonical URL",
+			document: `<html><head>
+<title>Canonical</title>
+<link rel="canonical" href="javascript:alert(1)">
+</head></html>`,
+			want: Metadata{
+				Title: "Canonical",
+			},
+		},
+		{
+			tname: "scripts, styles and SVG titles are ignored",
+			document: `<html><head>
+<title>Page</title>
+<style>body { color: red; }</style>
+</head><body>
+<script>var title = "script";</script>
+<svg><title>Icon</title></svg>
+<p>Visible text</p>
+<noscript>Enable JavaScript</noscript>
+</body></html>`,
+			want: Metadata{
+				Title: "Page",
+				Text:  "Visible text",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got, err := ParseMetadata(strings.NewReader(tc.document), pageURL)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("want %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
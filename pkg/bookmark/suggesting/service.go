@@ -0,0 +1,156 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/virtualtam/sparklemuffin/internal/textkit"
+	"github.com/virtualtam/sparklemuffin/internal/urlkit"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+const (
+	// rankedWordsTopN is the number of top ranking words considered as tag candidates.
+	rankedWordsTopN = 20
+
+	// suggestedTagsMax is the maximum number of suggested tags.
+	suggestedTagsMax = 8
+
+	// tagCandidateMinLength is the minimum length for a ranked word to be suggested as a new tag.
+	tagCandidateMinLength = 3
+)
+
+// Service handles operations to suggest bookmark metadata.
+type Service struct {
+	queryingService *querying.Service
+	client          *Client
+	textRanker      *textkit.TextRanker
+}
+
+// NewService initializes and returns a Service.
+func NewService(queryingService *querying.Service, client *Client) *Service {
+	return &Service{
+		queryingService: queryingService,
+		client:          client,
+		textRanker:      textkit.NewTextRanker(),
+	}
+}
+
+// Suggest retrieves a Web page and returns a Suggestion to pre-fill the bookmark addition form.
+//
+// Suggested tags are extracted from the page text using TextRank, and boosted when they match
+// tags the user already uses.
+func (s *Service) Suggest(ctx context.Context, userUUID string, pageURL string) (Suggestion, error) {
+	b := bookmark.Bookmark{URL: pageURL}
+	b.Normalize(bookmark.TagPolicy{})
+
+	if err := b.ValidateURL(); err != nil {
+		return Suggestion{}, err
+	}
+
+	metadata, err := s.client.Fetch(ctx, b.URL)
+	if err != nil {
+		return Suggestion{}, err
+	}
+
+	existingTags, err := s.queryingService.Tags(ctx, userUUID, querying.VisibilityAll)
+	if err != nil {
+		return Suggestion{}, err
+	}
+
+	suggestion := Suggestion{
+		URL:         b.URL,
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		SiteName:    metadata.SiteName,
+	}
+
+	if metadata.CanonicalURL != "" {
+		suggestion.URL = urlkit.Canonicalize(metadata.CanonicalURL)
+	}
+
+	text := strings.Join([]string{metadata.Title, metadata.Description, metadata.Text}, ". ")
+	suggestion.Tags = s.suggestTags(text, existingTags)
+
+	return suggestion, nil
+}
+
+// suggestTags ranks words from the page text, and returns the best tag candidates.
+//
+// Candidates matching existing tags are boosted, and existing tags that appear in the
+// text are considered even when they are not among the top ranking words.
+func (s *Service) suggestTags(text string, existingTags []querying.Tag) []string {
+	existing := make(map[string]string, len(existingTags))
+	for _, tag := range existingTags {
+		existing[strings.ToLower(tag.Name)] = tag.Name
+	}
+
+	scores := make(map[string]int)
+
+	rankedWords := s.textRanker.RankTopNWords(text, rankedWordsTopN)
+	for i, word := range rankedWords {
+		word = strings.ToLower(word)
+		score := rankedWordsTopN - i
+
+		if tag, ok := existing[word]; ok {
+			scores[tag] = max(scores[tag], score+rankedWordsTopN)
+			continue
+		}
+
+		if !isTagCandidate(word) {
+			continue
+		}
+
+		scores[word] = max(scores[word], score)
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isWordSeparator) {
+		if tag, ok := existing[word]; ok {
+			scores[tag] = max(scores[tag], rankedWordsTopN)
+		}
+	}
+
+	tags := make([]string, 0, len(scores))
+	for tag := range scores {
+		tags = append(tags, tag)
+	}
+
+	slices.SortFunc(tags, func(a, b string) int {
+		if scores[a] != scores[b] {
+			return scores[b] - scores[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	if len(tags) > suggestedTagsMax {
+		tags = tags[:suggestedTagsMax]
+	}
+
+	return tags
+}
+
+// isTagCandidate returns whether a ranked word can be suggested as a new tag.
+func isTagCandidate(word string) bool {
+	if len(word) < tagCandidateMinLength {
+		return false
+	}
+
+	return strings.ContainsFunc(word, unicode.IsLetter)
+}
+
+// isWordSeparator returns whether a rune separates words in the page text.
+//
+// Characters commonly found in tags, such as '-', '.', '/' and '_', are considered part of words.
+func isWordSeparator(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+
+	return !strings.ContainsRune("-./_+#", r)
+}
@@ -0,0 +1,153 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+)
+
+const (
+	testUserUUID = "5d75c769-059c-4b36-9db6-1c82619e704a"
+
+	testArticle = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Testing in Go</title>
+<meta property="og:description" content="How to write table-driven tests in Go.">
+<meta property="og:site_name" content="Gopher Blog">
+<link rel="canonical" href="https://example.org/testing?utm_source=feed">
+</head>
+<body>
+<p>Table-driven tests are a common pattern for testing in Go.</p>
+<p>Each test case describes inputs and expected outputs, and the testing package runs them as subtests.</p>
+<p>Subtests make it easy to run a single test case, and testing helpers reduce duplication.</p>
+<p>The golang toolchain also provides benchmarks and fuzzing alongside tests.</p>
+</body>
+</html>`
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/article", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(testArticle))
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte{0x89, 0x50, 0x4e, 0x47})
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServiceSuggest(t *testing.T) {
+	server := newTestServer(t)
+
+	repository := &querying.FakeRepository{
+		Bookmarks: []bookmark.Bookmark{
+			{
+				UserUUID: testUserUUID,
+				URL:      "https://example.org/existing",
+				Tags:     []string{"golang", "fuzzing"},
+			},
+		},
+	}
+
+	s := NewService(querying.NewService(repository), NewClient(server.Client(), "test"))
+
+	cases := []struct {
+		tname   string
+		url     string
+		want    Suggestion
+		wantErr error
+	}{
+		{
+			tname:   "empty URL",
+			wantErr: bookmark.ErrURLRequired,
+		},
+		{
+			tname:   "URL with no scheme",
+			url:     "example.org/article",
+			wantErr: bookmark.ErrURLNoScheme,
+		},
+		{
+			tname:   "page not found",
+			url:     server.URL + "/gone",
+			wantErr: ErrPageUnavailable,
+		},
+		{
+			tname:   "unsupported content type",
+			url:     server.URL + "/image.png",
+			wantErr: ErrContentTypeUnsupported,
+		},
+		{
+			tname: "HTML page",
+			url:   server.URL + "/article",
+			want: Suggestion{
+				URL:         "https://example.org/testing",
+				Title:       "Testing in Go",
+				Description: "How to write table-driven tests in Go.",
+				SiteName:    "Gopher Blog",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got, err := s.Suggest(t.Context(), testUserUUID, tc.url)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.URL != tc.want.URL {
+				t.Errorf("want URL %q, got %q", tc.want.URL, got.URL)
+			}
+			if got.Title != tc.want.Title {
+				t.Errorf("want title %q, got %q", tc.want.Title, got.Title)
+			}
+			if got.Description != tc.want.Description {
+				t.Errorf("want description %q, got %q", tc.want.Description, got.Description)
+			}
+			if got.SiteName != tc.want.SiteName {
+				t.Errorf("want site name %q, got %q", tc.want.SiteName, got.SiteName)
+			}
+
+			if len(got.Tags) == 0 || len(got.Tags) > suggestedTagsMax {
+				t.Fatalf("want between 1 and %d tags, got %v", suggestedTagsMax, got.Tags)
+			}
+
+			// Existing tags found in the page are boosted.
+			for _, tag := range []string{"golang", "fuzzing"} {
+				if !slices.Contains(got.Tags[:2], tag) {
+					t.Errorf("want existing tag %q to be suggested first, got %v", tag, got.Tags)
+				}
+			}
+		})
+	}
+}
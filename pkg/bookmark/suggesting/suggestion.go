@@ -0,0 +1,13 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package suggesting
+
+// A Suggestion holds the values suggested to pre-fill the bookmark addition form.
+type Suggestion struct {
+	URL         string
+	Title       string
+	Description string
+	SiteName    string
+	Tags        []string
+}
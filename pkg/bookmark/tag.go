@@ -5,21 +5,65 @@ package bookmark
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 )
 
+const (
+	// TagSeparator separates the levels of a hierarchical tag, e.g. dev/go/testing.
+	TagSeparator = "/"
+)
+
 var (
 	whitespaceRegexp = regexp.MustCompile(`\s`)
 )
 
+// NormalizeTag trims whitespace and removes empty levels from a tag,
+// e.g. " /dev//go/ " becomes "dev/go".
+func NormalizeTag(tag string) string {
+	var levels []string
+
+	for level := range strings.SplitSeq(strings.TrimSpace(tag), TagSeparator) {
+		if level == "" {
+			continue
+		}
+		levels = append(levels, level)
+	}
+
+	return strings.Join(levels, TagSeparator)
+}
+
+// TagIsDescendantOf returns whether a tag is nested under a given parent tag,
+// e.g. dev/go and dev/go/testing are descendants of dev.
+func TagIsDescendantOf(tag string, parent string) bool {
+	return strings.HasPrefix(tag, parent+TagSeparator)
+}
+
+// TagMatches returns whether a tag is equal to a given tag, or one of its descendants.
+func TagMatches(tag string, parent string) bool {
+	return tag == parent || TagIsDescendantOf(tag, parent)
+}
+
 // TagDeleteQuery represents a tag deletion for all bookmarks of an authenticated user.
 type TagDeleteQuery struct {
 	UserUUID string
 	Name     string
+
+	// Cascade also deletes the descendants of the tag, e.g. dev/go when deleting dev.
+	Cascade bool
+}
+
+// matches returns whether a bookmark tag is to be deleted.
+func (dq *TagDeleteQuery) matches(tag string) bool {
+	if dq.Cascade {
+		return TagMatches(tag, dq.Name)
+	}
+
+	return tag == dq.Name
 }
 
 func (dq *TagDeleteQuery) normalize() {
-	dq.Name = strings.TrimSpace(dq.Name)
+	dq.Name = NormalizeTag(dq.Name)
 }
 
 func (dq *TagDeleteQuery) requireUserUUID() error {
@@ -45,6 +89,23 @@ type TagUpdateQuery struct {
 	UserUUID    string
 	CurrentName string
 	NewName     string
+
+	// Cascade also renames the descendants of the tag, e.g. dev/go becomes code/go
+	// when renaming dev to code.
+	Cascade bool
+}
+
+// rename returns the new name of a bookmark tag, and whether it is affected by the update.
+func (uq *TagUpdateQuery) rename(tag string) (string, bool) {
+	if tag == uq.CurrentName {
+		return uq.NewName, true
+	}
+
+	if uq.Cascade && TagIsDescendantOf(tag, uq.CurrentName) {
+		return uq.NewName + strings.TrimPrefix(tag, uq.CurrentName), true
+	}
+
+	return tag, false
 }
 
 func (uq *TagUpdateQuery) ensureCurrentNameHasNoWhitespace() error {
@@ -62,8 +123,8 @@ func (uq *TagUpdateQuery) ensureNewNameHasNoWhitespace() error {
 }
 
 func (uq *TagUpdateQuery) normalize() {
-	uq.CurrentName = strings.TrimSpace(uq.CurrentName)
-	uq.NewName = strings.TrimSpace(uq.NewName)
+	uq.CurrentName = NormalizeTag(uq.CurrentName)
+	uq.NewName = NormalizeTag(uq.NewName)
 }
 
 func (uq *TagUpdateQuery) requireCurrentName() error {
@@ -83,3 +144,74 @@ func (uq *TagUpdateQuery) requireNewName() error {
 func (uq *TagUpdateQuery) requireUserUUID() error {
 	return requireUserUUID(uq.UserUUID)
 }
+
+// TagMergeQuery represents merging several tags into a single tag, for all bookmarks of an
+// authenticated user.
+type TagMergeQuery struct {
+	UserUUID string
+	Sources  []string
+	Target   string
+
+	// KeepAliases registers the merged tags as aliases of the target tag, so that they are
+	// replaced when bookmarks are saved or imported.
+	KeepAliases bool
+}
+
+// merge returns the new name of a bookmark tag, and whether it is affected by the merge.
+func (mq *TagMergeQuery) merge(tag string) (string, bool) {
+	if slices.Contains(mq.Sources, tag) {
+		return mq.Target, true
+	}
+
+	return tag, false
+}
+
+func (mq *TagMergeQuery) normalize() {
+	mq.Target = NormalizeTag(mq.Target)
+
+	var sources []string
+
+	for _, source := range mq.Sources {
+		source = NormalizeTag(source)
+
+		if source == "" || source == mq.Target || slices.Contains(sources, source) {
+			continue
+		}
+
+		sources = append(sources, source)
+	}
+
+	mq.Sources = sources
+}
+
+func (mq *TagMergeQuery) requireUserUUID() error {
+	return requireUserUUID(mq.UserUUID)
+}
+
+func (mq *TagMergeQuery) requireSources() error {
+	if len(mq.Sources) == 0 {
+		return ErrTagMergeSourcesRequired
+	}
+	return nil
+}
+
+func (mq *TagMergeQuery) ensureSourcesHaveNoWhitespace() error {
+	if slices.ContainsFunc(mq.Sources, whitespaceRegexp.MatchString) {
+		return newValidationError("sources", ErrTagNameContainsWhitespace)
+	}
+	return nil
+}
+
+func (mq *TagMergeQuery) requireTarget() error {
+	if mq.Target == "" {
+		return newValidationError("target", ErrTagNameRequired)
+	}
+	return nil
+}
+
+func (mq *TagMergeQuery) ensureTargetHasNoWhitespace() error {
+	if whitespaceRegexp.MatchString(mq.Target) {
+		return newValidationError("target", ErrTagNameContainsWhitespace)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package bookmark
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TagCaseFolding allows users to choose how the case of tag names is normalized.
+type TagCaseFolding string
+
+const (
+	// TagCaseFoldingPreserve indicates tags are saved as they are typed.
+	TagCaseFoldingPreserve TagCaseFolding = "PRESERVE"
+
+	// TagCaseFoldingLowercase indicates tags are converted to lowercase.
+	TagCaseFoldingLowercase TagCaseFolding = "LOWERCASE"
+)
+
+var (
+	allTagCaseFoldings = []TagCaseFolding{TagCaseFoldingPreserve, TagCaseFoldingLowercase}
+)
+
+// TagPreferences represents a user's preferences for bookmark tags.
+type TagPreferences struct {
+	UserUUID    string
+	CaseFolding TagCaseFolding
+
+	UpdatedAt time.Time
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (p *TagPreferences) Normalize() {
+	if p.CaseFolding == "" {
+		p.CaseFolding = TagCaseFoldingPreserve
+	}
+}
+
+// ValidateForUpdate ensures mandatory fields are set when updating TagPreferences.
+func (p *TagPreferences) ValidateForUpdate() error {
+	if err := requireUserUUID(p.UserUUID); err != nil {
+		return err
+	}
+
+	if !slices.Contains(allTagCaseFoldings, p.CaseFolding) {
+		return ErrTagCaseFoldingUnknown
+	}
+
+	return nil
+}
+
+// A TagAlias replaces a tag with another when bookmarks are saved or imported,
+// e.g. golang with go.
+//
+// Aliases are matched regardless of case, and also apply to the descendants of the
+// aliased tag, e.g. golang/testing becomes go/testing.
+type TagAlias struct {
+	UserUUID string
+	Alias    string
+	Tag      string
+
+	CreatedAt time.Time
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (a *TagAlias) Normalize() {
+	a.Alias = NormalizeTag(a.Alias)
+	a.Tag = NormalizeTag(a.Tag)
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when adding a new TagAlias.
+func (a *TagAlias) ValidateForAddition() error {
+	fns := []func() error{
+		a.requireUserUUID,
+		a.requireAlias,
+		a.ensureAliasHasNoWhitespace,
+		a.requireTag,
+		a.ensureTagHasNoWhitespace,
+		a.ensureTagIsNotAliased,
+	}
+
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matches returns the levels of a tag that remain once the alias is replaced,
+// and whether the alias applies to the tag.
+func (a *TagAlias) matches(tagLevels []string) ([]string, bool) {
+	aliasLevels := strings.Split(a.Alias, TagSeparator)
+
+	if len(aliasLevels) > len(tagLevels) {
+		return nil, false
+	}
+
+	for i, aliasLevel := range aliasLevels {
+		if !strings.EqualFold(aliasLevel, tagLevels[i]) {
+			return nil, false
+		}
+	}
+
+	return tagLevels[len(aliasLevels):], true
+}
+
+func (a *TagAlias) requireUserUUID() error {
+	return requireUserUUID(a.UserUUID)
+}
+
+func (a *TagAlias) requireAlias() error {
+	if a.Alias == "" {
+		return newValidationError("alias", ErrTagNameRequired)
+	}
+	return nil
+}
+
+func (a *TagAlias) ensureAliasHasNoWhitespace() error {
+	if whitespaceRegexp.MatchString(a.Alias) {
+		return newValidationError("alias", ErrTagNameContainsWhitespace)
+	}
+	return nil
+}
+
+func (a *TagAlias) requireTag() error {
+	if a.Tag == "" {
+		return newValidationError("tag", ErrTagNameRequired)
+	}
+	return nil
+}
+
+func (a *TagAlias) ensureTagHasNoWhitespace() error {
+	if whitespaceRegexp.MatchString(a.Tag) {
+		return newValidationError("tag", ErrTagNameContainsWhitespace)
+	}
+	return nil
+}
+
+// ensureTagIsNotAliased rejects aliases that would apply to their own tag, e.g. dev to
+// dev/go, as the tag would be nested further every time a bookmark is saved.
+func (a *TagAlias) ensureTagIsNotAliased() error {
+	if a.Alias == a.Tag {
+		return ErrTagAliasSameAsTag
+	}
+
+	if rest, ok := a.matches(strings.Split(a.Tag, TagSeparator)); ok && len(rest) > 0 {
+		return ErrTagAliasSameAsTag
+	}
+
+	return nil
+}
+
+// A TagPolicy holds the rules applied to tags when bookmarks are saved or imported.
+//
+// The zero value leaves tags unchanged.
+type TagPolicy struct {
+	CaseFolding TagCaseFolding
+	Aliases     []TagAlias
+}
+
+// NewTagPolicy initializes and returns a TagPolicy, from a user's preferences and aliases.
+func NewTagPolicy(preferences TagPreferences, aliases []TagAlias) TagPolicy {
+	return TagPolicy{
+		CaseFolding: preferences.CaseFolding,
+		Aliases:     aliases,
+	}
+}
+
+// Apply returns the tag resulting from replacing its alias, if any, and folding its case.
+//
+// When several aliases apply to a hierarchical tag, the most specific one is used.
+func (p TagPolicy) Apply(tag string) string {
+	if tag == "" {
+		return tag
+	}
+
+	tagLevels := strings.Split(tag, TagSeparator)
+
+	var replacement string
+	var remainingLevels []string
+	matched := false
+
+	for _, alias := range p.Aliases {
+		rest, ok := alias.matches(tagLevels)
+		if !ok {
+			continue
+		}
+
+		if !matched || len(rest) < len(remainingLevels) {
+			replacement = alias.Tag
+			remainingLevels = rest
+			matched = true
+		}
+	}
+
+	if matched {
+		tag = strings.Join(append([]string{replacement}, remainingLevels...), TagSeparator)
+	}
+
+	if p.CaseFolding == TagCaseFoldingLowercase {
+		tag = strings.ToLower(tag)
+	}
+
+	return tag
+}
+
+// TagPolicyByUserUUID returns the tag policy for a given user.
+//
+// Users who never saved their tag preferences get the default preferences.
+func TagPolicyByUserUUID(ctx context.Context, r TagPolicyRepository, userUUID string) (TagPolicy, error) {
+	preferences, err := tagPreferencesByUserUUID(ctx, r, userUUID)
+	if err != nil {
+		return TagPolicy{}, err
+	}
+
+	aliases, err := r.BookmarkTagAliasGetAll(ctx, userUUID)
+	if err != nil {
+		return TagPolicy{}, err
+	}
+
+	return NewTagPolicy(preferences, aliases), nil
+}
+
+func tagPreferencesByUserUUID(ctx context.Context, r TagPolicyRepository, userUUID string) (TagPreferences, error) {
+	preferences, err := r.BookmarkTagPreferencesGetByUserUUID(ctx, userUUID)
+	if errors.Is(err, ErrTagPreferencesNotFound) {
+		preferences = TagPreferences{UserUUID: userUUID}
+	} else if err != nil {
+		return TagPreferences{}, err
+	}
+
+	preferences.Normalize()
+
+	return preferences, nil
+}
+
+// renameTagAliasTargets renames the tags that aliases point to, so that aliases keep
+// pointing to tags rather than to other aliases.
+//
+// Aliases that end up pointing to themselves are dropped. It returns whether any alias
+// was changed.
+func renameTagAliasTargets(aliases []TagAlias, rename func(tag string) (string, bool)) ([]TagAlias, bool) {
+	var changed bool
+
+	for i, alias := range aliases {
+		if newTag, renamed := rename(alias.Tag); renamed {
+			aliases[i].Tag = newTag
+			changed = true
+		}
+	}
+
+	aliases = slices.DeleteFunc(aliases, func(alias TagAlias) bool {
+		return alias.Alias == alias.Tag
+	})
+
+	return aliases, changed
+}
+
+// addTagAlias returns the given aliases, once a new alias has been added.
+//
+// The new alias replaces an existing alias with the same name, regardless of case. As its
+// tag is a tag and not an alias, an alias with the same name as the tag is removed, and
+// aliases pointing to the new alias are redirected to its tag.
+func addTagAlias(aliases []TagAlias, newAlias TagAlias) []TagAlias {
+	aliases = slices.DeleteFunc(aliases, func(alias TagAlias) bool {
+		return strings.EqualFold(alias.Alias, newAlias.Alias) || strings.EqualFold(alias.Alias, newAlias.Tag)
+	})
+
+	aliases, _ = renameTagAliasTargets(aliases, func(tag string) (string, bool) {
+		if strings.EqualFold(tag, newAlias.Alias) {
+			return newAlias.Tag, true
+		}
+		return tag, false
+	})
+
+	aliases = append(aliases, newAlias)
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+
+	return aliases
+}
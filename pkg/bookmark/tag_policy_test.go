@@ -0,0 +1,87 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package bookmark
+
+import (
+	"testing"
+)
+
+func TestTagPolicyApply(t *testing.T) {
+	aliases := []TagAlias{
+		{Alias: "golang", Tag: "go"},
+		{Alias: "golang/test", Tag: "go/testing"},
+		{Alias: "js", Tag: "JavaScript"},
+	}
+
+	cases := []struct {
+		tname  string
+		policy TagPolicy
+		tag    string
+		want   string
+	}{
+		{
+			tname: "zero policy",
+			tag:   "GoLang",
+			want:  "GoLang",
+		},
+		{
+			tname:  "no matching alias",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "rust",
+			want:   "rust",
+		},
+		{
+			tname:  "alias",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "golang",
+			want:   "go",
+		},
+		{
+			tname:  "alias with a different case",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "GoLang",
+			want:   "go",
+		},
+		{
+			tname:  "alias prefix that is not a parent tag",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "golangci",
+			want:   "golangci",
+		},
+		{
+			tname:  "descendant of an alias",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "golang/Generics",
+			want:   "go/Generics",
+		},
+		{
+			tname:  "most specific alias",
+			policy: TagPolicy{Aliases: aliases},
+			tag:    "golang/test/fuzzing",
+			want:   "go/testing/fuzzing",
+		},
+		{
+			tname:  "preserve case",
+			policy: TagPolicy{CaseFolding: TagCaseFoldingPreserve, Aliases: aliases},
+			tag:    "JS",
+			want:   "JavaScript",
+		},
+		{
+			tname:  "lowercase",
+			policy: TagPolicy{CaseFolding: TagCaseFoldingLowercase, Aliases: aliases},
+			tag:    "JS",
+			want:   "javascript",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := tc.policy.Apply(tc.tag)
+
+			if got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package bookmark
+
+// URLCanonicalizationReport summarizes the canonicalization of a user's bookmark URLs.
+type URLCanonicalizationReport struct {
+	UserUUID string
+
+	// Canonicalized is the number of bookmarks whose URL was (or would be) canonicalized.
+	Canonicalized int
+
+	// Duplicates lists the groups of bookmarks that share the same canonical URL, and
+	// were left unchanged.
+	Duplicates []URLDuplicate
+}
+
+// URLDuplicate represents a group of bookmarks whose URLs share the same canonical form.
+type URLDuplicate struct {
+	CanonicalURL string
+	Bookmarks    []Bookmark
+}
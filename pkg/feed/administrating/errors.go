@@ -0,0 +1,11 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import "errors"
+
+var (
+	ErrFeedMergeSameFeed        = errors.New("feed administration: cannot merge a feed into itself")
+	ErrFeedURLAlreadyRegistered = errors.New("feed administration: another feed is registered with this URL")
+)
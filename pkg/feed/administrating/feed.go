@@ -0,0 +1,89 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+)
+
+// Feed represents a syndication feed shared by all subscribers, along with its usage and
+// synchronization state.
+type Feed struct {
+	feed.Feed
+
+	Subscribers uint
+	Entries     uint
+
+	FetchError   string
+	FetchErrorAt time.Time
+}
+
+// InError returns whether the last attempt to fetch this Feed failed.
+func (f Feed) InError() bool {
+	return f.FetchError != ""
+}
+
+// Subscriber represents a user subscribed to a given Feed.
+type Subscriber struct {
+	UserUUID    string
+	NickName    string
+	DisplayName string
+	Email       string
+}
+
+// duplicateURLCandidates returns the URLs a duplicate of a given feed URL may be registered
+// with, e.g. using another scheme, with or without a "www." prefix or a trailing slash.
+func duplicateURLCandidates(feedURL string) []string {
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Host == "" {
+		return []string{}
+	}
+
+	var schemes []string
+	switch u.Scheme {
+	case "http":
+		schemes = []string{"http", "https"}
+	case "https":
+		schemes = []string{"https", "http"}
+	default:
+		return []string{}
+	}
+
+	hosts := []string{u.Host}
+	if host, ok := strings.CutPrefix(u.Host, "www."); ok {
+		hosts = append(hosts, host)
+	} else {
+		hosts = append(hosts, "www."+u.Host)
+	}
+
+	paths := []string{u.Path}
+	if path, ok := strings.CutSuffix(u.Path, "/"); ok {
+		paths = append(paths, path)
+	} else {
+		paths = append(paths, u.Path+"/")
+	}
+
+	var candidates []string
+
+	for _, scheme := range schemes {
+		for _, host := range hosts {
+			for _, path := range paths {
+				candidate := *u
+				candidate.Scheme = scheme
+				candidate.Host = host
+				candidate.Path = path
+
+				if candidateURL := candidate.String(); candidateURL != feedURL {
+					candidates = append(candidates, candidateURL)
+				}
+			}
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,24 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import "github.com/virtualtam/sparklemuffin/internal/paginate"
+
+// A FeedPage holds a set of paginated Feeds.
+type FeedPage struct {
+	paginate.Page
+
+	Feeds []Feed
+}
+
+// NewFeedPage initializes and returns a new FeedPage.
+func NewFeedPage(number uint, totalPages uint, searchTerms string, feedCount uint, feeds []Feed) FeedPage {
+	page := FeedPage{
+		Page:  paginate.NewPage(number, totalPages, feedsPerPage, feedCount),
+		Feeds: feeds,
+	}
+	page.SearchTerms = searchTerms
+
+	return page
+}
@@ -0,0 +1,44 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import (
+	"context"
+	"time"
+)
+
+// Repository provides access to all syndication feeds, for administration purposes.
+type Repository interface {
+	// FeedAdminDelete deletes a Feed, along with its entries and subscriptions.
+	FeedAdminDelete(ctx context.Context, feedUUID string) error
+
+	// FeedAdminGetByURL returns the Feed for a given URL.
+	FeedAdminGetByURL(ctx context.Context, feedURL string) (Feed, error)
+
+	// FeedAdminGetByUUID returns the Feed for a given UUID.
+	FeedAdminGetByUUID(ctx context.Context, feedUUID string) (Feed, error)
+
+	// FeedAdminGetCount returns the number of Feeds whose URL or title contain searchTerms.
+	FeedAdminGetCount(ctx context.Context, searchTerms string) (uint, error)
+
+	// FeedAdminGetManyByURLs returns the Feeds registered with any of the given URLs.
+	FeedAdminGetManyByURLs(ctx context.Context, feedURLs []string) ([]Feed, error)
+
+	// FeedAdminGetN returns at most n Feeds whose URL or title contain searchTerms,
+	// sorted by URL.
+	FeedAdminGetN(ctx context.Context, searchTerms string, n uint, offset uint) ([]Feed, error)
+
+	// FeedAdminMerge moves subscriptions from a source Feed to a target Feed, then deletes
+	// the source Feed.
+	//
+	// Subscriptions of users already subscribed to the target Feed are deleted, and the read
+	// status of entries also published by the target Feed is preserved.
+	FeedAdminMerge(ctx context.Context, sourceUUID string, targetUUID string) error
+
+	// FeedAdminSubscriberGetAll returns all users subscribed to a given Feed.
+	FeedAdminSubscriberGetAll(ctx context.Context, feedUUID string) ([]Subscriber, error)
+
+	// FeedAdminURLUpdate updates the URL of a given Feed, and resets its synchronization state.
+	FeedAdminURLUpdate(ctx context.Context, feedUUID string, feedURL string, updatedAt time.Time) error
+}
@@ -0,0 +1,142 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+)
+
+var _ Repository = &FakeRepository{}
+
+type FakeRepository struct {
+	Feeds []Feed
+
+	// Subscribers maps feed UUIDs to their subscribers.
+	Subscribers map[string][]Subscriber
+}
+
+func (r *FakeRepository) FeedAdminDelete(_ context.Context, feedUUID string) error {
+	r.Feeds = slices.DeleteFunc(r.Feeds, func(f Feed) bool {
+		return f.UUID == feedUUID
+	})
+	delete(r.Subscribers, feedUUID)
+
+	return nil
+}
+
+func (r *FakeRepository) FeedAdminGetByURL(_ context.Context, feedURL string) (Feed, error) {
+	for _, f := range r.Feeds {
+		if f.FeedURL == feedURL {
+			return f, nil
+		}
+	}
+
+	return Feed{}, feed.ErrFeedNotFound
+}
+
+func (r *FakeRepository) FeedAdminGetByUUID(_ context.Context, feedUUID string) (Feed, error) {
+	for _, f := range r.Feeds {
+		if f.UUID == feedUUID {
+			return f, nil
+		}
+	}
+
+	return Feed{}, feed.ErrFeedNotFound
+}
+
+func (r *FakeRepository) FeedAdminGetCount(_ context.Context, searchTerms string) (uint, error) {
+	return uint(len(r.search(searchTerms))), nil
+}
+
+func (r *FakeRepository) FeedAdminGetManyByURLs(_ context.Context, feedURLs []string) ([]Feed, error) {
+	var feeds []Feed
+
+	for _, f := range r.Feeds {
+		if slices.Contains(feedURLs, f.FeedURL) {
+			feeds = append(feeds, f)
+		}
+	}
+
+	return feeds, nil
+}
+
+func (r *FakeRepository) FeedAdminGetN(_ context.Context, searchTerms string, n uint, offset uint) ([]Feed, error) {
+	feeds := r.search(searchTerms)
+
+	if offset >= uint(len(feeds)) {
+		return []Feed{}, nil
+	}
+
+	feeds = feeds[offset:]
+
+	if uint(len(feeds)) > n {
+		feeds = feeds[:n]
+	}
+
+	return feeds, nil
+}
+
+func (r *FakeRepository) FeedAdminMerge(_ context.Context, sourceUUID string, targetUUID string) error {
+	for _, subscriber := range r.Subscribers[sourceUUID] {
+		alreadySubscribed := slices.ContainsFunc(r.Subscribers[targetUUID], func(s Subscriber) bool {
+			return s.UserUUID == subscriber.UserUUID
+		})
+
+		if !alreadySubscribed {
+			r.Subscribers[targetUUID] = append(r.Subscribers[targetUUID], subscriber)
+		}
+	}
+
+	for i, f := range r.Feeds {
+		if f.UUID == targetUUID {
+			r.Feeds[i].Subscribers = uint(len(r.Subscribers[targetUUID]))
+		}
+	}
+
+	r.Feeds = slices.DeleteFunc(r.Feeds, func(f Feed) bool {
+		return f.UUID == sourceUUID
+	})
+	delete(r.Subscribers, sourceUUID)
+
+	return nil
+}
+
+func (r *FakeRepository) FeedAdminSubscriberGetAll(_ context.Context, feedUUID string) ([]Subscriber, error) {
+	return r.Subscribers[feedUUID], nil
+}
+
+func (r *FakeRepository) FeedAdminURLUpdate(_ context.Context, feedUUID string, feedURL string, updatedAt time.Time) error {
+	for i, f := range r.Feeds {
+		if f.UUID != feedUUID {
+			continue
+		}
+
+		r.Feeds[i].FeedURL = feedURL
+		r.Feeds[i].ETag = ""
+		r.Feeds[i].LastModified = time.Time{}
+		r.Feeds[i].Hash = 0
+		r.Feeds[i].UpdatedAt = updatedAt
+
+		return nil
+	}
+
+	return feed.ErrFeedNotFound
+}
+
+func (r *FakeRepository) search(searchTerms string) []Feed {
+	var feeds []Feed
+
+	for _, f := range r.Feeds {
+		if strings.Contains(f.FeedURL, searchTerms) || strings.Contains(f.Title, searchTerms) {
+			feeds = append(feeds, f)
+		}
+	}
+
+	return feeds
+}
@@ -0,0 +1,258 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/feed/synchronizing"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+)
+
+const (
+	feedsPerPage uint = 50
+
+	refreshJobID = "admin"
+)
+
+var deletionNoticeTemplate = template.Must(template.New("deletion").Parse(`Hello {{ .DisplayName }},
+
+The feed you subscribed to has been removed from SparkleMuffin by an administrator:
+
+  {{ .FeedTitle }}
+  {{ .FeedURL }}
+
+Your subscription and the corresponding entries have been deleted.
+{{- if .Reason }}
+
+Reason: {{ .Reason }}
+{{- end }}
+`))
+
+// Service handles administration operations for syndication feeds shared by all users.
+type Service struct {
+	r                    Repository
+	synchronizingService *synchronizing.Service
+	notifier             notification.Notifier
+}
+
+// NewService initializes and returns a feed administration Service.
+//
+// notifier may be nil, in which case subscribers are not notified when a feed is deleted.
+func NewService(r Repository, synchronizingService *synchronizing.Service, notifier notification.Notifier) *Service {
+	return &Service{
+		r:                    r,
+		synchronizingService: synchronizingService,
+		notifier:             notifier,
+	}
+}
+
+// ByPage returns a Page containing a limited and offset number of Feeds whose URL or title
+// contain searchTerms.
+func (s *Service) ByPage(ctx context.Context, searchTerms string, number uint) (FeedPage, error) {
+	if number < 1 {
+		return FeedPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	feedCount, err := s.r.FeedAdminGetCount(ctx, searchTerms)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
+	totalPages := paginate.PageCount(feedCount, feedsPerPage)
+
+	if number > totalPages {
+		return FeedPage{}, paginate.ErrPageNumberOutOfBounds
+	}
+
+	if feedCount == 0 {
+		// early return: nothing to display
+		return NewFeedPage(1, 1, searchTerms, 0, []Feed{}), nil
+	}
+
+	dbOffset := (number - 1) * feedsPerPage
+
+	feeds, err := s.r.FeedAdminGetN(ctx, searchTerms, feedsPerPage, dbOffset)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
+	return NewFeedPage(number, totalPages, searchTerms, feedCount, feeds), nil
+}
+
+// ByUUID returns the Feed for a given UUID.
+func (s *Service) ByUUID(ctx context.Context, feedUUID string) (Feed, error) {
+	if feedUUID == "" {
+		return Feed{}, feed.ErrFeedUUIDRequired
+	}
+
+	return s.r.FeedAdminGetByUUID(ctx, feedUUID)
+}
+
+// Duplicates returns the Feeds that are likely duplicates of a given Feed, e.g. registered
+// with another URL scheme.
+func (s *Service) Duplicates(ctx context.Context, f Feed) ([]Feed, error) {
+	candidates := duplicateURLCandidates(f.FeedURL)
+	if len(candidates) == 0 {
+		return []Feed{}, nil
+	}
+
+	return s.r.FeedAdminGetManyByURLs(ctx, candidates)
+}
+
+// Delete deletes a Feed, along with its entries and subscriptions, and notifies its
+// subscribers.
+//
+// It returns the number of subscribers who were notified; failing to notify a subscriber
+// is logged, and does not prevent the Feed from being deleted.
+func (s *Service) Delete(ctx context.Context, feedUUID string, reason string) (int, error) {
+	f, err := s.ByUUID(ctx, feedUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	subscribers, err := s.r.FeedAdminSubscriberGetAll(ctx, feedUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.r.FeedAdminDelete(ctx, feedUUID); err != nil {
+		return 0, err
+	}
+
+	if s.notifier == nil {
+		return 0, nil
+	}
+
+	var nNotified int
+
+	for _, subscriber := range subscribers {
+		if err := s.notifyDeletion(ctx, f, subscriber, reason); err != nil {
+			log.
+				Error().
+				Err(err).
+				Str("feed_url", f.FeedURL).
+				Str("user_uuid", subscriber.UserUUID).
+				Msg("feeds: failed to notify subscriber of feed deletion")
+			continue
+		}
+
+		nNotified++
+	}
+
+	return nNotified, nil
+}
+
+func (s *Service) notifyDeletion(ctx context.Context, f Feed, subscriber Subscriber, reason string) error {
+	displayName := subscriber.DisplayName
+	if displayName == "" {
+		displayName = subscriber.NickName
+	}
+
+	data := struct {
+		DisplayName string
+		FeedTitle   string
+		FeedURL     string
+		Reason      string
+	}{
+		DisplayName: displayName,
+		FeedTitle:   f.Title,
+		FeedURL:     f.FeedURL,
+		Reason:      reason,
+	}
+
+	var body bytes.Buffer
+
+	if err := deletionNoticeTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+
+	message := notification.Message{
+		To:      subscriber.Email,
+		Subject: "A feed you subscribed to has been removed",
+		Body:    body.String(),
+	}
+
+	return s.notifier.Notify(ctx, message)
+}
+
+// Merge moves the subscriptions of a source Feed to the Feed registered with a given URL,
+// then deletes the source Feed; it returns the target Feed.
+func (s *Service) Merge(ctx context.Context, sourceUUID string, targetURL string) (Feed, error) {
+	source, err := s.ByUUID(ctx, sourceUUID)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	targetFeed := feed.Feed{FeedURL: strings.TrimSpace(targetURL)}
+
+	if err := targetFeed.ValidateURL(); err != nil {
+		return Feed{}, err
+	}
+
+	target, err := s.r.FeedAdminGetByURL(ctx, targetFeed.FeedURL)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	if target.UUID == source.UUID {
+		return Feed{}, ErrFeedMergeSameFeed
+	}
+
+	if err := s.r.FeedAdminMerge(ctx, source.UUID, target.UUID); err != nil {
+		return Feed{}, err
+	}
+
+	return target, nil
+}
+
+// Refresh synchronizes a Feed immediately, ignoring HTTP caching headers.
+func (s *Service) Refresh(ctx context.Context, feedUUID string) error {
+	f, err := s.ByUUID(ctx, feedUUID)
+	if err != nil {
+		return err
+	}
+
+	return s.synchronizingService.SynchronizeFeed(ctx, f.Feed, refreshJobID)
+}
+
+// UpdateURL updates the URL of a Feed.
+//
+// The Feed's HTTP caching headers and content hash are reset, so that it is fully
+// synchronized from the new URL.
+func (s *Service) UpdateURL(ctx context.Context, feedUUID string, feedURL string) error {
+	f, err := s.ByUUID(ctx, feedUUID)
+	if err != nil {
+		return err
+	}
+
+	updatedFeed := feed.Feed{FeedURL: strings.TrimSpace(feedURL)}
+
+	if err := updatedFeed.ValidateURL(); err != nil {
+		return err
+	}
+
+	if updatedFeed.FeedURL == f.FeedURL {
+		return nil
+	}
+
+	_, err = s.r.FeedAdminGetByURL(ctx, updatedFeed.FeedURL)
+	if err == nil {
+		return ErrFeedURLAlreadyRegistered
+	}
+	if !errors.Is(err, feed.ErrFeedNotFound) {
+		return err
+	}
+
+	return s.r.FeedAdminURLUpdate(ctx, feedUUID, updatedFeed.FeedURL, time.Now().UTC())
+}
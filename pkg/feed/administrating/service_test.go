@@ -0,0 +1,444 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package administrating
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/paginate"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+)
+
+var (
+	testHTTPFeed = Feed{
+		Feed: feed.Feed{
+			UUID:    "0b2c4f6e-1a3d-4e5f-8a9b-0c1d2e3f4a5b",
+			FeedURL: "http://example.org/feed.xml",
+			Title:   "Example (HTTP)",
+			ETag:    `W/"abc"`,
+			Hash:    42,
+		},
+		Subscribers: 2,
+	}
+	testHTTPSFeed = Feed{
+		Feed: feed.Feed{
+			UUID:    "7d8e9f0a-1b2c-4d3e-9f4a-5b6c7d8e9f0a",
+			FeedURL: "https://example.org/feed.xml",
+			Title:   "Example",
+		},
+		Subscribers: 1,
+	}
+	testOtherFeed = Feed{
+		Feed: feed.Feed{
+			UUID:    "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e6f",
+			FeedURL: "https://other.example.com/atom.xml",
+			Title:   "Other",
+		},
+	}
+
+	testJane = Subscriber{
+		UserUUID:    "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		NickName:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.org",
+	}
+	testJohn = Subscriber{
+		UserUUID: "e1d2c3b4-a5f6-4e7d-8c9b-0a1f2e3d4c5b",
+		NickName: "john",
+		Email:    "john.doe@example.org",
+	}
+)
+
+func newTestRepository() *FakeRepository {
+	return &FakeRepository{
+		Feeds: []Feed{testHTTPFeed, testHTTPSFeed, testOtherFeed},
+		Subscribers: map[string][]Subscriber{
+			testHTTPFeed.UUID:  {testJane, testJohn},
+			testHTTPSFeed.UUID: {testJane},
+		},
+	}
+}
+
+func TestDuplicateURLCandidates(t *testing.T) {
+	cases := []struct {
+		tname   string
+		feedURL string
+		want    []string
+	}{
+		{
+			tname:   "invalid URL",
+			feedURL: "example.org/feed.xml",
+			want:    []string{},
+		},
+		{
+			tname:   "unsupported scheme",
+			feedURL: "ftp://example.org/feed.xml",
+			want:    []string{},
+		},
+		{
+			tname:   "HTTP URL",
+			feedURL: "http://example.org/feed.xml",
+			want: []string{
+				"http://example.org/feed.xml/",
+				"http://www.example.org/feed.xml",
+				"http://www.example.org/feed.xml/",
+				"https://example.org/feed.xml",
+				"https://example.org/feed.xml/",
+				"https://www.example.org/feed.xml",
+				"https://www.example.org/feed.xml/",
+			},
+		},
+		{
+			tname:   "HTTPS URL with www prefix and trailing slash",
+			feedURL: "https://www.example.org/feed/",
+			want: []string{
+				"https://www.example.org/feed",
+				"https://example.org/feed/",
+				"https://example.org/feed",
+				"http://www.example.org/feed/",
+				"http://www.example.org/feed",
+				"http://example.org/feed/",
+				"http://example.org/feed",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := duplicateURLCandidates(tc.feedURL)
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("want candidates %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceByPage(t *testing.T) {
+	cases := []struct {
+		tname       string
+		searchTerms string
+		number      uint
+		wantCount   uint
+		wantErr     error
+	}{
+		{
+			tname:   "page 0",
+			number:  0,
+			wantErr: paginate.ErrPageNumberOutOfBounds,
+		},
+		{
+			tname:   "page out of bounds",
+			number:  2,
+			wantErr: paginate.ErrPageNumberOutOfBounds,
+		},
+		{
+			tname:     "all feeds",
+			number:    1,
+			wantCount: 3,
+		},
+		{
+			tname:       "search",
+			searchTerms: "example.org",
+			number:      1,
+			wantCount:   2,
+		},
+		{
+			tname:       "no match",
+			searchTerms: "nothing",
+			number:      1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s := NewService(newTestRepository(), nil, nil)
+
+			got, err := s.ByPage(t.Context(), tc.searchTerms, tc.number)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if got.ItemCount != tc.wantCount {
+				t.Errorf("want %d feeds, got %d", tc.wantCount, got.ItemCount)
+			}
+			if uint(len(got.Feeds)) != tc.wantCount {
+				t.Errorf("want %d feeds on the page, got %d", tc.wantCount, len(got.Feeds))
+			}
+			if got.SearchTerms != tc.searchTerms {
+				t.Errorf("want search terms %q, got %q", tc.searchTerms, got.SearchTerms)
+			}
+		})
+	}
+}
+
+func TestServiceDuplicates(t *testing.T) {
+	s := NewService(newTestRepository(), nil, nil)
+
+	got, err := s.Duplicates(t.Context(), testHTTPFeed)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(got) != 1 || got[0].UUID != testHTTPSFeed.UUID {
+		t.Errorf("want duplicate %q, got %+v", testHTTPSFeed.FeedURL, got)
+	}
+}
+
+func TestServiceDelete(t *testing.T) {
+	errNotify := errors.New("SMTP server unavailable")
+
+	cases := []struct {
+		tname        string
+		feedUUID     string
+		reason       string
+		notifier     *notification.FakeNotifier
+		wantNotified int
+		wantErr      error
+	}{
+		{
+			tname:   "UUID required",
+			wantErr: feed.ErrFeedUUIDRequired,
+		},
+		{
+			tname:    "not found",
+			feedUUID: "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d",
+			wantErr:  feed.ErrFeedNotFound,
+		},
+		{
+			tname:    "no notifier",
+			feedUUID: testHTTPFeed.UUID,
+		},
+		{
+			tname:        "subscribers notified",
+			feedUUID:     testHTTPFeed.UUID,
+			reason:       "Duplicate of https://example.org/feed.xml",
+			notifier:     &notification.FakeNotifier{},
+			wantNotified: 2,
+		},
+		{
+			tname:    "notification fails",
+			feedUUID: testHTTPFeed.UUID,
+			notifier: &notification.FakeNotifier{NotifyErr: errNotify},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := newTestRepository()
+
+			var s *Service
+			if tc.notifier != nil {
+				s = NewService(r, nil, tc.notifier)
+			} else {
+				s = NewService(r, nil, nil)
+			}
+
+			got, err := s.Delete(t.Context(), tc.feedUUID, tc.reason)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if got != tc.wantNotified {
+				t.Errorf("want %d subscribers notified, got %d", tc.wantNotified, got)
+			}
+
+			if _, err := r.FeedAdminGetByUUID(t.Context(), tc.feedUUID); !errors.Is(err, feed.ErrFeedNotFound) {
+				t.Errorf("want feed to be deleted, got %v", err)
+			}
+
+			if tc.wantNotified == 0 {
+				return
+			}
+
+			message := tc.notifier.Messages[0]
+
+			if message.To != testJane.Email {
+				t.Errorf("want recipient %q, got %q", testJane.Email, message.To)
+			}
+
+			for _, want := range []string{"Hello Jane Doe,", testHTTPFeed.FeedURL, "Reason: " + tc.reason} {
+				if !strings.Contains(message.Body, want) {
+					t.Errorf("want %q in message body, got:\n%s", want, message.Body)
+				}
+			}
+
+			if !strings.Contains(tc.notifier.Messages[1].Body, "Hello john,") {
+				t.Errorf("want nickname greeting, got:\n%s", tc.notifier.Messages[1].Body)
+			}
+		})
+	}
+}
+
+func TestServiceMerge(t *testing.T) {
+	cases := []struct {
+		tname           string
+		sourceUUID      string
+		targetURL       string
+		wantErr         error
+		wantSubscribers uint
+	}{
+		{
+			tname:      "source not found",
+			sourceUUID: "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d",
+			targetURL:  testHTTPSFeed.FeedURL,
+			wantErr:    feed.ErrFeedNotFound,
+		},
+		{
+			tname:      "invalid target URL",
+			sourceUUID: testHTTPFeed.UUID,
+			targetURL:  "example.org/feed.xml",
+			wantErr:    feed.ErrFeedURLNoScheme,
+		},
+		{
+			tname:      "target not found",
+			sourceUUID: testHTTPFeed.UUID,
+			targetURL:  "https://unknown.example.org/feed.xml",
+			wantErr:    feed.ErrFeedNotFound,
+		},
+		{
+			tname:      "same feed",
+			sourceUUID: testHTTPFeed.UUID,
+			targetURL:  testHTTPFeed.FeedURL,
+			wantErr:    ErrFeedMergeSameFeed,
+		},
+		{
+			tname:           "merge HTTP into HTTPS variant",
+			sourceUUID:      testHTTPFeed.UUID,
+			targetURL:       "  " + testHTTPSFeed.FeedURL + " ",
+			wantSubscribers: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := newTestRepository()
+			s := NewService(r, nil, nil)
+
+			got, err := s.Merge(t.Context(), tc.sourceUUID, tc.targetURL)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if got.UUID != testHTTPSFeed.UUID {
+				t.Errorf("want target %q, got %q", testHTTPSFeed.UUID, got.UUID)
+			}
+
+			if _, err := r.FeedAdminGetByUUID(t.Context(), tc.sourceUUID); !errors.Is(err, feed.ErrFeedNotFound) {
+				t.Errorf("want source feed to be deleted, got %v", err)
+			}
+
+			target, err := r.FeedAdminGetByUUID(t.Context(), testHTTPSFeed.UUID)
+			if err != nil {
+				t.Fatalf("failed to retrieve target feed: %q", err)
+			}
+
+			if target.Subscribers != tc.wantSubscribers {
+				t.Errorf("want %d subscribers, got %d", tc.wantSubscribers, target.Subscribers)
+			}
+		})
+	}
+}
+
+func TestServiceRefresh(t *testing.T) {
+	s := NewService(newTestRepository(), nil, nil)
+
+	err := s.Refresh(t.Context(), "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d")
+
+	if !errors.Is(err, feed.ErrFeedNotFound) {
+		t.Errorf("want error %v, got %v", feed.ErrFeedNotFound, err)
+	}
+}
+
+func TestServiceUpdateURL(t *testing.T) {
+	cases := []struct {
+		tname    string
+		feedUUID string
+		feedURL  string
+		wantURL  string
+		wantErr  error
+	}{
+		{
+			tname:    "not found",
+			feedUUID: "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d",
+			feedURL:  "https://example.org/new.xml",
+			wantErr:  feed.ErrFeedNotFound,
+		},
+		{
+			tname:    "URL required",
+			feedUUID: testHTTPFeed.UUID,
+			feedURL:  "   ",
+			wantErr:  feed.ErrFeedURLRequired,
+		},
+		{
+			tname:    "unsupported scheme",
+			feedUUID: testHTTPFeed.UUID,
+			feedURL:  "gopher://example.org/feed.xml",
+			wantErr:  feed.ErrFeedURLUnsupportedScheme,
+		},
+		{
+			tname:    "URL registered to another feed",
+			feedUUID: testHTTPFeed.UUID,
+			feedURL:  testHTTPSFeed.FeedURL,
+			wantErr:  ErrFeedURLAlreadyRegistered,
+		},
+		{
+			tname:    "unchanged",
+			feedUUID: testHTTPFeed.UUID,
+			feedURL:  testHTTPFeed.FeedURL,
+			wantURL:  testHTTPFeed.FeedURL,
+		},
+		{
+			tname:    "updated",
+			feedUUID: testHTTPFeed.UUID,
+			feedURL:  " https://feeds.example.org/main.xml ",
+			wantURL:  "https://feeds.example.org/main.xml",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := newTestRepository()
+			s := NewService(r, nil, nil)
+
+			err := s.UpdateURL(t.Context(), tc.feedUUID, tc.feedURL)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			got, err := r.FeedAdminGetByUUID(t.Context(), tc.feedUUID)
+			if err != nil {
+				t.Fatalf("failed to retrieve feed: %q", err)
+			}
+
+			if got.FeedURL != tc.wantURL {
+				t.Errorf("want URL %q, got %q", tc.wantURL, got.FeedURL)
+			}
+
+			if tc.wantURL != testHTTPFeed.FeedURL && (got.ETag != "" || got.Hash != 0) {
+				t.Errorf("want synchronization state to be reset, got ETag %q and hash %d", got.ETag, got.Hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+)
+
+const (
+	// sentAtTolerance compensates for the time spent preparing and sending previous digests,
+	// so that they do not drift by a whole scheduling interval.
+	sentAtTolerance = 5 * time.Minute
+)
+
+// Recipient represents a user who subscribed to periodic feed digests.
+type Recipient struct {
+	UserUUID   string
+	NickName   string
+	Email      string
+	Frequency  feed.DigestFrequency
+	MarkAsRead bool
+	SentAt     time.Time
+}
+
+// isDue returns true if a new digest should be sent to this Recipient.
+func (r Recipient) isDue(now time.Time) bool {
+	period := r.Frequency.Period()
+	if period == 0 {
+		return false
+	}
+
+	return !now.Before(r.SentAt.Add(period - sentAtTolerance))
+}
+
+// since returns the time after which feed entries should be included in the next digest.
+//
+// Entries older than the digest period are never included, e.g. when a user switches
+// a digest on after a long time.
+func (r Recipient) since(now time.Time) time.Time {
+	periodStart := now.Add(-r.Frequency.Period())
+
+	if r.SentAt.After(periodStart) {
+		return r.SentAt
+	}
+
+	return periodStart
+}
+
+// Entry represents an unread feed entry included in a digest.
+type Entry struct {
+	UID          string
+	URL          string
+	Title        string
+	Summary      string
+	PublishedAt  time.Time
+	FeedTitle    string
+	CategoryName string
+}
+
+// Category groups digest entries by feed category.
+type Category struct {
+	Name    string
+	Entries []Entry
+}
+
+// Digest represents a summary of unread feed entries for a given Recipient.
+type Digest struct {
+	Recipient  Recipient
+	Since      time.Time
+	Categories []Category
+	EntryCount int
+}
+
+// newDigest groups entries by category.
+//
+// Entries are expected to be sorted by category name.
+func newDigest(recipient Recipient, since time.Time, entries []Entry) Digest {
+	d := Digest{
+		Recipient:  recipient,
+		Since:      since,
+		EntryCount: len(entries),
+	}
+
+	for _, entry := range entries {
+		last := len(d.Categories) - 1
+
+		if last < 0 || d.Categories[last].Name != entry.CategoryName {
+			d.Categories = append(d.Categories, Category{Name: entry.CategoryName})
+			last++
+		}
+
+		d.Categories[last].Entries = append(d.Categories[last].Entries, entry)
+	}
+
+	return d
+}
+
+var digestBodyTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"indent": func(s string) string {
+		return "  " + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n  ")
+	},
+}).Parse(`Hello {{ .Recipient.NickName }},
+
+Here {{ if eq .EntryCount 1 }}is the feed entry{{ else }}are the {{ .EntryCount }} feed entries{{ end }} you have not read since {{ .Since.Format "Monday, January 2 at 15:04 MST" }}.
+{{ range .Categories }}
+## {{ .Name }}
+{{ range .Entries }}
+- {{ .Title }} ({{ .FeedTitle }})
+  {{ .URL }}
+{{ with .Summary }}
+{{ indent . }}
+{{ end }}{{ end }}{{ end }}
+--
+{{ if .Recipient.MarkAsRead }}These entries have been marked as read.
+{{ end }}You are receiving this digest because you subscribed to it in your SparkleMuffin preferences.
+`))
+
+// message renders the Digest as a notification.Message.
+func (d Digest) message() (notification.Message, error) {
+	var body bytes.Buffer
+
+	if err := digestBodyTemplate.Execute(&body, d); err != nil {
+		return notification.Message{}, err
+	}
+
+	var frequency string
+	switch d.Recipient.Frequency {
+	case feed.DigestFrequencyWeekly:
+		frequency = "Weekly"
+	default:
+		frequency = "Daily"
+	}
+
+	entries := "entries"
+	if d.EntryCount == 1 {
+		entries = "entry"
+	}
+
+	return notification.Message{
+		To:      d.Recipient.Email,
+		Subject: fmt.Sprintf("%s feed digest: %d unread %s", frequency, d.EntryCount, entries),
+		Body:    body.String(),
+	}, nil
+}
+
+// entryUIDs returns the UIDs of all entries included in the Digest.
+func (d Digest) entryUIDs() []string {
+	uids := make([]string, 0, d.EntryCount)
+
+	for _, category := range d.Categories {
+		for _, entry := range category.Entries {
+			uids = append(uids, entry.UID)
+		}
+	}
+
+	return uids
+}
@@ -0,0 +1,25 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"context"
+	"time"
+)
+
+// Repository provides access to feed data for digests.
+type Repository interface {
+	// FeedDigestRecipientGetAll returns all users who subscribed to feed digests.
+	FeedDigestRecipientGetAll(ctx context.Context) ([]Recipient, error)
+
+	// FeedDigestEntryGetN returns at most n unread entries that have been created after a given time.Time,
+	// sorted by category name and publication date.
+	FeedDigestEntryGetN(ctx context.Context, userUUID string, since time.Time, n uint) ([]Entry, error)
+
+	// FeedDigestEntryMarkManyAsRead marks a collection of entries as read.
+	FeedDigestEntryMarkManyAsRead(ctx context.Context, userUUID string, entryUIDs []string) error
+
+	// FeedDigestSentAtUpdate updates the time when the last digest was sent to a given user.
+	FeedDigestSentAtUpdate(ctx context.Context, userUUID string, sentAt time.Time) error
+}
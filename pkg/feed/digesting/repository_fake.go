@@ -0,0 +1,87 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Recipients []Recipient
+
+	// Entries holds unread entries, indexed by user UUID.
+	Entries map[string][]FakeEntry
+
+	// ReadEntryUIDs holds the UIDs of entries marked as read, indexed by user UUID.
+	ReadEntryUIDs map[string][]string
+}
+
+// FakeEntry holds an Entry and the time it was created at.
+type FakeEntry struct {
+	Entry
+	CreatedAt time.Time
+}
+
+func (r *FakeRepository) FeedDigestRecipientGetAll(_ context.Context) ([]Recipient, error) {
+	return r.Recipients, nil
+}
+
+func (r *FakeRepository) FeedDigestEntryGetN(_ context.Context, userUUID string, since time.Time, n uint) ([]Entry, error) {
+	var entries []Entry
+
+	for _, fakeEntry := range r.Entries[userUUID] {
+		if !fakeEntry.CreatedAt.After(since) {
+			continue
+		}
+		if slices.Contains(r.ReadEntryUIDs[userUUID], fakeEntry.UID) {
+			continue
+		}
+
+		entries = append(entries, fakeEntry.Entry)
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		if a.CategoryName < b.CategoryName {
+			return -1
+		}
+		if a.CategoryName > b.CategoryName {
+			return 1
+		}
+
+		return b.PublishedAt.Compare(a.PublishedAt)
+	})
+
+	if uint(len(entries)) > n {
+		entries = entries[:n]
+	}
+
+	return entries, nil
+}
+
+func (r *FakeRepository) FeedDigestEntryMarkManyAsRead(_ context.Context, userUUID string, entryUIDs []string) error {
+	if r.ReadEntryUIDs == nil {
+		r.ReadEntryUIDs = map[string][]string{}
+	}
+
+	r.ReadEntryUIDs[userUUID] = append(r.ReadEntryUIDs[userUUID], entryUIDs...)
+
+	return nil
+}
+
+func (r *FakeRepository) FeedDigestSentAtUpdate(_ context.Context, userUUID string, sentAt time.Time) error {
+	for i, recipient := range r.Recipients {
+		if recipient.UserUUID != userUUID {
+			continue
+		}
+
+		r.Recipients[i].SentAt = sentAt
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	defaultDigestInterval = 1 * time.Hour
+	defaultTaskTimeout    = 10 * time.Minute
+)
+
+// A Scheduler periodically sends feed digests.
+type Scheduler struct {
+	s           *Service
+	locker      sync.Locker
+	interval    time.Duration
+	taskTimeout time.Duration
+}
+
+// NewScheduler initializes and returns a Scheduler.
+func NewScheduler(service *Service, locker sync.Locker) *Scheduler {
+	return &Scheduler{
+		s:           service,
+		locker:      locker,
+		interval:    defaultDigestInterval,
+		taskTimeout: defaultTaskTimeout,
+	}
+}
+
+// Run periodically sends feed digests.
+func (sc *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(sc.interval)
+	log.Info().
+		Dur("interval", sc.interval).
+		Msg("digests: scheduler started")
+
+	for {
+		<-ticker.C
+
+		go func() {
+			jobID := ksuid.New().String()
+
+			sc.locker.Lock()
+			defer sc.locker.Unlock()
+
+			taskCtx, cancel := context.WithTimeout(ctx, sc.taskTimeout)
+			defer cancel()
+
+			if err := sc.s.Send(taskCtx, jobID); err != nil {
+				log.
+					Error().
+					Err(err).
+					Str("job_id", jobID).
+					Msg("digests: failed to send digests")
+			}
+		}()
+	}
+}
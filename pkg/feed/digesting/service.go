@@ -0,0 +1,116 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+)
+
+const (
+	maxDigestEntries uint = 100
+)
+
+// Service handles feed digest operations.
+type Service struct {
+	r        Repository
+	notifier notification.Notifier
+}
+
+// NewService initializes and returns a new feed digest service.
+func NewService(r Repository, notifier notification.Notifier) *Service {
+	return &Service{
+		r:        r,
+		notifier: notifier,
+	}
+}
+
+// Send sends feed digests to all users for whom one is due.
+func (s *Service) Send(ctx context.Context, jobID string) error {
+	now := time.Now().UTC()
+
+	recipients, err := s.r.FeedDigestRecipientGetAll(ctx)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("digests: failed to list recipients")
+		return err
+	}
+
+	var nSent int
+
+	for _, recipient := range recipients {
+		if !recipient.isDue(now) {
+			continue
+		}
+
+		sent, err := s.sendDigest(ctx, recipient, now)
+		if err != nil {
+			log.
+				Error().
+				Err(err).
+				Str("job_id", jobID).
+				Str("user_uuid", recipient.UserUUID).
+				Msg("digests: failed to send digest")
+			continue
+		}
+
+		if sent {
+			nSent++
+		}
+	}
+
+	log.
+		Info().
+		Str("job_id", jobID).
+		Int("digests_sent", nSent).
+		Msg("digests: done")
+
+	return nil
+}
+
+// sendDigest sends a digest of unread entries to a given Recipient, and returns whether a message was sent.
+//
+// No message is sent if there are no new unread entries.
+func (s *Service) sendDigest(ctx context.Context, recipient Recipient, now time.Time) (bool, error) {
+	since := recipient.since(now)
+
+	entries, err := s.r.FeedDigestEntryGetN(ctx, recipient.UserUUID, since, maxDigestEntries)
+	if err != nil {
+		return false, err
+	}
+
+	if len(entries) == 0 {
+		return false, s.r.FeedDigestSentAtUpdate(ctx, recipient.UserUUID, now)
+	}
+
+	digest := newDigest(recipient, since, entries)
+
+	message, err := digest.message()
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.notifier.Notify(ctx, message); err != nil {
+		return false, err
+	}
+
+	if err := s.r.FeedDigestSentAtUpdate(ctx, recipient.UserUUID, now); err != nil {
+		return true, err
+	}
+
+	if recipient.MarkAsRead {
+		if err := s.r.FeedDigestEntryMarkManyAsRead(ctx, recipient.UserUUID, digest.entryUIDs()); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
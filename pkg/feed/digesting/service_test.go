@@ -0,0 +1,219 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package digesting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+)
+
+func TestRecipientIsDue(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 8, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		tname     string
+		recipient Recipient
+		want      bool
+	}{
+		{
+			tname:     "no digest",
+			recipient: Recipient{Frequency: feed.DigestFrequencyNone, SentAt: now.Add(-30 * 24 * time.Hour)},
+			want:      false,
+		},
+		{
+			tname:     "daily digest sent yesterday",
+			recipient: Recipient{Frequency: feed.DigestFrequencyDaily, SentAt: now.Add(-24*time.Hour + 30*time.Second)},
+			want:      true,
+		},
+		{
+			tname:     "daily digest sent this morning",
+			recipient: Recipient{Frequency: feed.DigestFrequencyDaily, SentAt: now.Add(-2 * time.Hour)},
+			want:      false,
+		},
+		{
+			tname:     "weekly digest sent two days ago",
+			recipient: Recipient{Frequency: feed.DigestFrequencyWeekly, SentAt: now.Add(-48 * time.Hour)},
+			want:      false,
+		},
+		{
+			tname:     "weekly digest sent last week",
+			recipient: Recipient{Frequency: feed.DigestFrequencyWeekly, SentAt: now.Add(-7 * 24 * time.Hour)},
+			want:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			if got := tc.recipient.isDue(now); got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceSend(t *testing.T) {
+	now := time.Now().UTC()
+
+	dailyRecipient := Recipient{
+		UserUUID:  "2cb5d1a5-1f8a-4f4c-9a1e-0c8f5ac2d5c1",
+		NickName:  "jane",
+		Email:     "jane@example.org",
+		Frequency: feed.DigestFrequencyDaily,
+		SentAt:    now.Add(-25 * time.Hour),
+	}
+	weeklyRecipient := Recipient{
+		UserUUID:   "a9f3c2e0-7e1b-4d5c-8f0a-3b6d2e1c4f7a",
+		NickName:   "john",
+		Email:      "john@example.org",
+		Frequency:  feed.DigestFrequencyWeekly,
+		MarkAsRead: true,
+		SentAt:     now.Add(-8 * 24 * time.Hour),
+	}
+	notDueRecipient := Recipient{
+		UserUUID:  "5d7e9f1a-2b3c-4d5e-8f9a-0b1c2d3e4f5a",
+		NickName:  "jack",
+		Email:     "jack@example.org",
+		Frequency: feed.DigestFrequencyWeekly,
+		SentAt:    now.Add(-24 * time.Hour),
+	}
+
+	r := &FakeRepository{
+		Recipients: []Recipient{dailyRecipient, weeklyRecipient, notDueRecipient},
+		Entries: map[string][]FakeEntry{
+			dailyRecipient.UserUUID: {
+				{
+					Entry: Entry{
+						UID:          "entry-1",
+						URL:          "https://blog.example.org/posts/1",
+						Title:        "First post",
+						Summary:      "A summary\nover two lines",
+						PublishedAt:  now.Add(-2 * time.Hour),
+						FeedTitle:    "Example Blog",
+						CategoryName: "Tech",
+					},
+					CreatedAt: now.Add(-time.Hour),
+				},
+				{
+					Entry: Entry{
+						UID:          "entry-2",
+						URL:          "https://news.example.org/2",
+						Title:        "Breaking news",
+						PublishedAt:  now.Add(-3 * time.Hour),
+						FeedTitle:    "Example News",
+						CategoryName: "News",
+					},
+					CreatedAt: now.Add(-2 * time.Hour),
+				},
+				{
+					Entry: Entry{
+						UID:          "entry-old",
+						URL:          "https://news.example.org/old",
+						Title:        "Already digested",
+						PublishedAt:  now.Add(-48 * time.Hour),
+						FeedTitle:    "Example News",
+						CategoryName: "News",
+					},
+					CreatedAt: now.Add(-48 * time.Hour),
+				},
+			},
+			weeklyRecipient.UserUUID: {
+				{
+					Entry: Entry{
+						UID:          "entry-3",
+						URL:          "https://blog.example.org/posts/3",
+						Title:        "Weekly post",
+						PublishedAt:  now.Add(-72 * time.Hour),
+						FeedTitle:    "Example Blog",
+						CategoryName: "Tech",
+					},
+					CreatedAt: now.Add(-72 * time.Hour),
+				},
+			},
+			notDueRecipient.UserUUID: {
+				{
+					Entry: Entry{
+						UID:          "entry-4",
+						URL:          "https://blog.example.org/posts/4",
+						Title:        "Not yet",
+						PublishedAt:  now.Add(-time.Hour),
+						FeedTitle:    "Example Blog",
+						CategoryName: "Tech",
+					},
+					CreatedAt: now.Add(-time.Hour),
+				},
+			},
+		},
+	}
+	notifier := &notification.FakeNotifier{}
+	s := NewService(r, notifier)
+
+	if err := s.Send(t.Context(), "test-job"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(notifier.Messages) != 2 {
+		t.Fatalf("want 2 messages, got %d", len(notifier.Messages))
+	}
+
+	t.Run("daily digest", func(t *testing.T) {
+		message := notifier.Messages[0]
+
+		if message.To != dailyRecipient.Email {
+			t.Errorf("want recipient %q, got %q", dailyRecipient.Email, message.To)
+		}
+		if want := "Daily feed digest: 2 unread entries"; message.Subject != want {
+			t.Errorf("want subject %q, got %q", want, message.Subject)
+		}
+
+		newsIndex := strings.Index(message.Body, "## News")
+		techIndex := strings.Index(message.Body, "## Tech")
+		if newsIndex < 0 || techIndex < 0 || newsIndex > techIndex {
+			t.Errorf("want entries grouped by category, got:\n%s", message.Body)
+		}
+		if !strings.Contains(message.Body, "- First post (Example Blog)\n  https://blog.example.org/posts/1\n\n  A summary\n  over two lines\n") {
+			t.Errorf("want entry with indented summary, got:\n%s", message.Body)
+		}
+		if strings.Contains(message.Body, "Already digested") {
+			t.Errorf("want previously digested entries to be skipped, got:\n%s", message.Body)
+		}
+		if len(r.ReadEntryUIDs[dailyRecipient.UserUUID]) != 0 {
+			t.Errorf("want no entries marked as read, got %v", r.ReadEntryUIDs[dailyRecipient.UserUUID])
+		}
+	})
+
+	t.Run("weekly digest marked as read", func(t *testing.T) {
+		message := notifier.Messages[1]
+
+		if want := "Weekly feed digest: 1 unread entry"; message.Subject != want {
+			t.Errorf("want subject %q, got %q", want, message.Subject)
+		}
+		if !strings.Contains(message.Body, "These entries have been marked as read.") {
+			t.Errorf("want mark as read notice, got:\n%s", message.Body)
+		}
+
+		got := r.ReadEntryUIDs[weeklyRecipient.UserUUID]
+		if len(got) != 1 || got[0] != "entry-3" {
+			t.Errorf("want entry-3 marked as read, got %v", got)
+		}
+	})
+
+	t.Run("sent at", func(t *testing.T) {
+		for _, recipient := range r.Recipients {
+			if recipient.UserUUID == notDueRecipient.UserUUID {
+				if !recipient.SentAt.Equal(notDueRecipient.SentAt) {
+					t.Errorf("want sent at unchanged for %s", recipient.NickName)
+				}
+				continue
+			}
+
+			if recipient.SentAt.Before(now) {
+				t.Errorf("want sent at updated for %s, got %s", recipient.NickName, recipient.SentAt)
+			}
+		}
+	})
+}
@@ -43,6 +43,7 @@ var (
 
 	ErrEntryMetadataNotFound = errors.New("entry-metadata: not found")
 
+	ErrPreferencesDigestFrequencyUnknown = errors.New("preferences: unknown digest frequency")
 	ErrPreferencesEntryVisibilityUnknown = errors.New("preferences: unknown entry visibility")
 
 	ErrSubscriptionAlreadyRegistered = errors.New("subscription: already registered")
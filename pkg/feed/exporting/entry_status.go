@@ -0,0 +1,15 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package exporting
+
+import "time"
+
+// EntryStatus represents the read status of a feed entry for a given user.
+type EntryStatus struct {
+	FeedURL     string    `json:"feed_url"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	PublishedAt time.Time `json:"published_at"`
+	Read        bool      `json:"read"`
+}
@@ -7,8 +7,11 @@ import (
 	"context"
 )
 
-// Repository provides access to user feed subscriptions for exporting.
+// Repository provides access to user feed subscriptions and entry statuses for exporting.
 type Repository interface {
 	// FeedCategorySubscriptionsGetAll returns all CategorySubscriptions for a given user.
 	FeedCategorySubscriptionsGetAll(ctx context.Context, userUUID string) ([]CategorySubscriptions, error)
+
+	// FeedEntryStatusesGetAll returns the read status of all feed entries a given user has interacted with.
+	FeedEntryStatusesGetAll(ctx context.Context, userUUID string) ([]EntryStatus, error)
 }
@@ -11,8 +11,13 @@ var _ Repository = &fakeRepository{}
 
 type fakeRepository struct {
 	categoriesSubscriptions []CategorySubscriptions
+	entryStatuses           []EntryStatus
 }
 
 func (r *fakeRepository) FeedCategorySubscriptionsGetAll(_ context.Context, userUUID string) ([]CategorySubscriptions, error) {
 	return r.categoriesSubscriptions, nil
 }
+
+func (r *fakeRepository) FeedEntryStatusesGetAll(_ context.Context, _ string) ([]EntryStatus, error) {
+	return r.entryStatuses, nil
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
-// Service handles feed subscription export operations.
+// Service handles feed subscription and entry status export operations.
 type Service struct {
 	r Repository
 }
@@ -73,3 +73,8 @@ func (s *Service) ExportAsOPMLDocument(ctx context.Context, user user.User) (*op
 
 	return document, nil
 }
+
+// ExportEntryStatuses exports the read status of all feed entries a given user has interacted with.
+func (s *Service) ExportEntryStatuses(ctx context.Context, userUUID string) ([]EntryStatus, error) {
+	return s.r.FeedEntryStatusesGetAll(ctx, userUUID)
+}
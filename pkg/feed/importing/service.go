@@ -11,21 +11,55 @@ import (
 	"github.com/virtualtam/opml-go"
 
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 // Service handles feed subscription import operations.
 type Service struct {
 	*feed.Service
+
+	quotaService *quota.Service
 }
 
 // NewService initializes and returns a new Service.
-func NewService(feedService *feed.Service) *Service {
+//
+// quotaService may be nil, in which case the size of imported files is not limited.
+func NewService(feedService *feed.Service, quotaService *quota.Service) *Service {
 	return &Service{
-		Service: feedService,
+		Service:      feedService,
+		quotaService: quotaService,
+	}
+}
+
+// MaxImportFileSize returns the maximum size of an import file for a given user, in bytes.
+//
+// A zero value means the size of import files is not limited.
+func (s *Service) MaxImportFileSize(ctx context.Context, userUUID string) (int64, error) {
+	if s.quotaService == nil {
+		return 0, nil
+	}
+
+	limits, err := s.quotaService.LimitsByUserUUID(ctx, userUUID)
+	if err != nil {
+		return 0, err
 	}
+
+	return limits.MaxImportFileSize, nil
+}
+
+// ValidateImportFileSize ensures a given user may import a file of the given size, in bytes.
+func (s *Service) ValidateImportFileSize(ctx context.Context, userUUID string, size int64) error {
+	if s.quotaService == nil {
+		return nil
+	}
+
+	return s.quotaService.CheckImportFileSize(ctx, userUUID, size)
 }
 
 // ImportFromOPMLDocument imports feed subscriptions and categories from an OPML document.
+//
+// If the user's subscription quota is reached, the import stops and returns the status
+// of the subscriptions imported so far, along with the quota error.
 func (s *Service) ImportFromOPMLDocument(ctx context.Context, userUUID string, document *opml.Document) (Status, error) {
 	var status Status
 	var errs []error
@@ -63,7 +97,9 @@ func (s *Service) ImportFromOPMLDocument(ctx context.Context, userUUID string, d
 			}
 
 			_, created, err = s.GetOrCreateSubscription(ctx, newSubscription)
-			if err != nil {
+			if errors.Is(err, quota.ErrSubscriptionLimitReached) {
+				return status, err
+			} else if err != nil {
 				return Status{}, err
 			}
 
@@ -5,6 +5,7 @@ package importing
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/test/feedtest"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
 	"github.com/virtualtam/sparklemuffin/pkg/feed/fetching"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -138,8 +140,8 @@ func TestServiceImportFromOPMLDocument(t *testing.T) {
 
 			// avoid a real DNS lookup for tc.outlines' test-only hostnames
 			noopURLValidator := func(_ context.Context, _ string) error { return nil }
-			feedService := feed.NewService(r, feedClient, noopURLValidator)
-			s := NewService(feedService)
+			feedService := feed.NewService(r, feedClient, noopURLValidator, nil)
+			s := NewService(feedService, nil)
 
 			document := &opml.Document{
 				Body: opml.Body{
@@ -158,3 +160,115 @@ func TestServiceImportFromOPMLDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceImportFromOPMLDocumentQuota(t *testing.T) {
+	fake := faker.New()
+
+	testUser := user.User{
+		UUID: fake.UUID().V4(),
+	}
+
+	now := time.Now().UTC()
+	testFeed := feedtest.GenerateDummyFeed(t, now)
+	transport := feedtest.NewRoundTripperFromFeed(t, testFeed)
+
+	testHTTPClient := &http.Client{
+		Transport: transport,
+	}
+
+	r := &feed.FakeRepository{
+		Subscriptions: []feed.Subscription{
+			{
+				UUID:     fake.UUID().V4(),
+				FeedUUID: fake.UUID().V4(),
+				UserUUID: testUser.UUID,
+			},
+		},
+	}
+	feedClient := fetching.NewClient(testHTTPClient, "sparklemuffin/test")
+	noopURLValidator := func(_ context.Context, _ string) error { return nil }
+
+	quotaRepository := &quota.FakeRepository{
+		Usages: map[string]quota.Usage{
+			testUser.UUID: {Subscriptions: 1},
+		},
+	}
+	quotaService, err := quota.NewService(quotaRepository, quota.Limits{MaxSubscriptions: 1})
+	if err != nil {
+		t.Fatalf("failed to create quota service: %q", err)
+	}
+
+	feedService := feed.NewService(r, feedClient, noopURLValidator, quotaService)
+	s := NewService(feedService, quotaService)
+
+	document := &opml.Document{
+		Body: opml.Body{
+			Outlines: []opml.Outline{
+				{
+					Text:    "Outline 1",
+					Title:   "Outline 1",
+					Type:    opml.OutlineTypeSubscription,
+					HtmlUrl: "http://dev1.local",
+					XmlUrl:  "http://dev1.local/feed",
+				},
+			},
+		},
+	}
+
+	status, err := s.ImportFromOPMLDocument(t.Context(), testUser.UUID, document)
+	if !errors.Is(err, quota.ErrSubscriptionLimitReached) {
+		t.Fatalf("want error %q, got %v", quota.ErrSubscriptionLimitReached, err)
+	}
+
+	if status.Subscriptions.Created != 0 {
+		t.Errorf("want no subscription to be created, got %d", status.Subscriptions.Created)
+	}
+
+	if len(r.Subscriptions) != 1 {
+		t.Errorf("want no subscription to be saved, got %d", len(r.Subscriptions)-1)
+	}
+}
+
+func TestServiceValidateImportFileSize(t *testing.T) {
+	quotaService, err := quota.NewService(&quota.FakeRepository{}, quota.Limits{MaxImportFileSize: 1024})
+	if err != nil {
+		t.Fatalf("failed to create quota service: %q", err)
+	}
+
+	const userUUID = "1632e701-e153-4f43-87ab-7fecacf8763f"
+
+	cases := []struct {
+		tname        string
+		quotaService *quota.Service
+		size         int64
+		wantErr      error
+	}{
+		{
+			tname: "unlimited",
+			size:  1 << 30,
+		},
+		{
+			tname:        "within limit",
+			quotaService: quotaService,
+			size:         1024,
+		},
+		{
+			tname:        "exceeding limit",
+			quotaService: quotaService,
+			size:         1025,
+			wantErr:      quota.ErrImportFileTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s := NewService(feed.NewService(&feed.FakeRepository{}, nil, nil, tc.quotaService), tc.quotaService)
+
+			err := s.ValidateImportFileSize(t.Context(), userUUID, tc.size)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("want error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
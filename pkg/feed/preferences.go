@@ -14,6 +14,9 @@ type Preferences struct {
 	ShowEntries        EntryVisibility
 	ShowEntrySummaries bool
 
+	DigestFrequency  DigestFrequency
+	DigestMarkAsRead bool
+
 	UpdatedAt time.Time
 }
 
@@ -31,15 +34,53 @@ const (
 	EntryVisibilityUnread EntryVisibility = "UNREAD"
 )
 
+// DigestFrequency allows users to receive a periodic email digest of their unread feed entries.
+type DigestFrequency string
+
+const (
+	// DigestFrequencyNone indicates the user does not want to receive digests.
+	DigestFrequencyNone DigestFrequency = "NONE"
+
+	// DigestFrequencyDaily indicates the user wants to receive a digest every day.
+	DigestFrequencyDaily DigestFrequency = "DAILY"
+
+	// DigestFrequencyWeekly indicates the user wants to receive a digest every week.
+	DigestFrequencyWeekly DigestFrequency = "WEEKLY"
+)
+
+// Period returns the time elapsed between two digests.
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestFrequencyDaily:
+		return 24 * time.Hour
+	case DigestFrequencyWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
 var (
 	allEntryVisibilities = []EntryVisibility{EntryVisibilityAll, EntryVisibilityRead, EntryVisibilityUnread}
+	allDigestFrequencies = []DigestFrequency{DigestFrequencyNone, DigestFrequencyDaily, DigestFrequencyWeekly}
 )
 
+// Normalize sanitizes and normalizes all fields.
+func (p *Preferences) Normalize() {
+	if p.DigestFrequency == "" {
+		p.DigestFrequency = DigestFrequencyNone
+	}
+}
+
 // ValidateForUpdate ensures mandatory fields are set when updating existing Preferences.
 func (p *Preferences) ValidateForUpdate() error {
 	if !slices.Contains(allEntryVisibilities, p.ShowEntries) {
 		return ErrPreferencesEntryVisibilityUnknown
 	}
 
+	if !slices.Contains(allDigestFrequencies, p.DigestFrequency) {
+		return ErrPreferencesDigestFrequencyUnknown
+	}
+
 	return nil
 }
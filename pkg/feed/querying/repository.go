@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 // Repository provides access to user feed subscriptions for querying.
@@ -28,15 +29,15 @@ type Repository interface {
 
 	// FeedEntryGetCountByQuery returns the count of entries corresponding to a feed subscription
 	// for a giver user, and matching a search query.
-	FeedEntryGetCountByQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, searchTerms string) (uint, error)
+	FeedEntryGetCountByQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, query search.Query) (uint, error)
 
 	// FeedEntryGetCountByCategoryAndQuery returns the count of entries corresponding to a feed subscription
 	// for a giver user and category, and matching a search query.
-	FeedEntryGetCountByCategoryAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, query string) (uint, error)
+	FeedEntryGetCountByCategoryAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, query search.Query) (uint, error)
 
 	// FeedEntryGetCountBySubscriptionAndQuery returns the count of entries corresponding to a feed subscription
 	// for a giver user and subscription, and matching a search query.
-	FeedEntryGetCountBySubscriptionAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, query string) (uint, error)
+	FeedEntryGetCountBySubscriptionAndQuery(ctx context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, query search.Query) (uint, error)
 
 	// FeedSubscriptionCategoryGetAll returns SubscribedFeeds, sorted by SubscriptionCategory.
 	FeedSubscriptionCategoryGetAll(ctx context.Context, userUUID string) ([]SubscribedFeedsByCategory, error)
@@ -54,13 +55,13 @@ type Repository interface {
 	FeedSubscriptionEntryGetNBySubscription(ctx context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
 
 	// FeedSubscriptionEntryGetNByQuery returns at most n SubscriptionEntries matching a search query, starting at a given offset.
-	FeedSubscriptionEntryGetNByQuery(ctx context.Context, userUUID string, preferences feed.Preferences, searchTerms string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
+	FeedSubscriptionEntryGetNByQuery(ctx context.Context, userUUID string, preferences feed.Preferences, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
 
 	// FeedSubscriptionEntryGetNByCategoryAndQuery returns at most n SubscriptionEntries matching a search query, starting at a given offset.
-	FeedSubscriptionEntryGetNByCategoryAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, query string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
+	FeedSubscriptionEntryGetNByCategoryAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
 
 	// FeedSubscriptionEntryGetNBySubscriptionAndQuery returns at most n SubscriptionEntries matching a search query, starting at a given offset.
-	FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, query string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
+	FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error)
 
 	// FeedQueryingSubscriptionByUUID returns feed subscription metadata for a given user and subscription.
 	FeedQueryingSubscriptionByUUID(ctx context.Context, userUUID string, subscriptionUUID string) (Subscription, error)
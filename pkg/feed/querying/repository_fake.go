@@ -10,6 +10,7 @@ import (
 	"sort"
 
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 var _ Repository = &FakeRepository{}
@@ -197,15 +198,15 @@ func (r *FakeRepository) FeedEntryGetCountBySubscription(ctx context.Context, us
 	return count, nil
 }
 
-func (r *FakeRepository) FeedEntryGetCountByQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, searchTerms string) (uint, error) {
+func (r *FakeRepository) FeedEntryGetCountByQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, query search.Query) (uint, error) {
 	return 0, errors.New("not implemented")
 }
 
-func (r *FakeRepository) FeedEntryGetCountByCategoryAndQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, searchTerms string) (uint, error) {
+func (r *FakeRepository) FeedEntryGetCountByCategoryAndQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, categoryUUID string, query search.Query) (uint, error) {
 	return 0, errors.New("not implemented")
 }
 
-func (r *FakeRepository) FeedEntryGetCountBySubscriptionAndQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, searchTerms string) (uint, error) {
+func (r *FakeRepository) FeedEntryGetCountBySubscriptionAndQuery(_ context.Context, userUUID string, showEntries feed.EntryVisibility, subscriptionUUID string, query search.Query) (uint, error) {
 	return 0, errors.New("not implemented")
 }
 
@@ -392,15 +393,15 @@ func (r *FakeRepository) FeedSubscriptionEntryGetNBySubscription(ctx context.Con
 	return subscriptionEntries[offset : offset+nEntries], nil
 }
 
-func (r *FakeRepository) FeedSubscriptionEntryGetNByQuery(_ context.Context, userUUID string, preferences feed.Preferences, searchTerms string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
+func (r *FakeRepository) FeedSubscriptionEntryGetNByQuery(_ context.Context, userUUID string, preferences feed.Preferences, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
 	return []SubscribedFeedEntry{}, errors.New("not implemented")
 }
 
-func (r *FakeRepository) FeedSubscriptionEntryGetNByCategoryAndQuery(_ context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, searchTerms string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
+func (r *FakeRepository) FeedSubscriptionEntryGetNByCategoryAndQuery(_ context.Context, userUUID string, preferences feed.Preferences, categoryUUID string, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
 	return []SubscribedFeedEntry{}, errors.New("not implemented")
 }
 
-func (r *FakeRepository) FeedSubscriptionEntryGetNBySubscriptionAndQuery(_ context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, searchTerms string, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
+func (r *FakeRepository) FeedSubscriptionEntryGetNBySubscriptionAndQuery(_ context.Context, userUUID string, preferences feed.Preferences, subscriptionUUID string, query search.Query, entriesPerPage uint, offset uint) ([]SubscribedFeedEntry, error) {
 	return []SubscribedFeedEntry{}, errors.New("not implemented")
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/virtualtam/sparklemuffin/internal/paginate"
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
 )
 
 const (
@@ -15,6 +16,19 @@ const (
 	PageHeaderAll  string = "All"
 )
 
+var (
+	// EntrySearchFilters lists the search operators supported for feed entries.
+	EntrySearchFilters = []search.Filter{
+		search.FilterSite,
+		search.FilterBefore,
+		search.FilterAfter,
+		search.FilterFeed,
+		search.FilterCategory,
+		search.FilterRead,
+		search.FilterUnread,
+	}
+)
+
 // Service handles operations related to displaying and paginating feeds.
 type Service struct {
 	r Repository
@@ -175,36 +189,62 @@ func (s *Service) feedsByQueryAndPage(
 	return NewFeedSearchResultPage(query, entryCount, number, totalPages, pageTitle, pageDescription, categories, entries), nil
 }
 
+// EntryCountByQuery returns the number of entries matching a given search query, among the
+// entries displayed according to the user's preferences.
+func (s *Service) EntryCountByQuery(ctx context.Context, userUUID string, preferences feed.Preferences, query string) (uint, error) {
+	searchQuery, err := search.Parse(query, EntrySearchFilters...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.r.FeedEntryGetCountByQuery(ctx, userUUID, preferences.ShowEntries, searchQuery)
+}
+
 func (s *Service) FeedsByQueryAndPage(ctx context.Context, userUUID string, preferences feed.Preferences, query string, number uint) (FeedPage, error) {
+	searchQuery, err := search.Parse(query, EntrySearchFilters...)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
 	getCountFn := func() (uint, error) {
-		return s.r.FeedEntryGetCountByQuery(ctx, userUUID, preferences.ShowEntries, query)
+		return s.r.FeedEntryGetCountByQuery(ctx, userUUID, preferences.ShowEntries, searchQuery)
 	}
 
 	subscriptionEntryGetNFn := func(offset uint) ([]SubscribedFeedEntry, error) {
-		return s.r.FeedSubscriptionEntryGetNByQuery(ctx, userUUID, preferences, query, entriesPerPage, offset)
+		return s.r.FeedSubscriptionEntryGetNByQuery(ctx, userUUID, preferences, searchQuery, entriesPerPage, offset)
 	}
 
 	return s.feedsByQueryAndPage(ctx, userUUID, query, number, getCountFn, subscriptionEntryGetNFn, PageHeaderAll, "")
 }
 
 func (s *Service) FeedsByCategoryAndQueryAndPage(ctx context.Context, userUUID string, preferences feed.Preferences, category feed.Category, query string, number uint) (FeedPage, error) {
+	searchQuery, err := search.Parse(query, EntrySearchFilters...)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
 	getCountFn := func() (uint, error) {
-		return s.r.FeedEntryGetCountByCategoryAndQuery(ctx, userUUID, preferences.ShowEntries, category.UUID, query)
+		return s.r.FeedEntryGetCountByCategoryAndQuery(ctx, userUUID, preferences.ShowEntries, category.UUID, searchQuery)
 	}
 
 	subscriptionEntryGetNFn := func(offset uint) ([]SubscribedFeedEntry, error) {
-		return s.r.FeedSubscriptionEntryGetNByCategoryAndQuery(ctx, userUUID, preferences, category.UUID, query, entriesPerPage, offset)
+		return s.r.FeedSubscriptionEntryGetNByCategoryAndQuery(ctx, userUUID, preferences, category.UUID, searchQuery, entriesPerPage, offset)
 	}
 
 	return s.feedsByQueryAndPage(ctx, userUUID, query, number, getCountFn, subscriptionEntryGetNFn, category.Name, "")
 }
 func (s *Service) FeedsBySubscriptionAndQueryAndPage(ctx context.Context, userUUID string, preferences feed.Preferences, subscription feed.Subscription, query string, number uint) (FeedPage, error) {
+	searchQuery, err := search.Parse(query, EntrySearchFilters...)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
 	getCountFn := func() (uint, error) {
-		return s.r.FeedEntryGetCountBySubscriptionAndQuery(ctx, userUUID, preferences.ShowEntries, subscription.UUID, query)
+		return s.r.FeedEntryGetCountBySubscriptionAndQuery(ctx, userUUID, preferences.ShowEntries, subscription.UUID, searchQuery)
 	}
 
 	subscriptionEntryGetNFn := func(offset uint) ([]SubscribedFeedEntry, error) {
-		return s.r.FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx, userUUID, preferences, subscription.UUID, query, entriesPerPage, offset)
+		return s.r.FeedSubscriptionEntryGetNBySubscriptionAndQuery(ctx, userUUID, preferences, subscription.UUID, searchQuery, entriesPerPage, offset)
 	}
 
 	f, err := s.r.FeedGetByUUID(ctx, subscription.FeedUUID)
@@ -84,7 +84,11 @@ type Repository interface {
 	FeedPreferencesUpdate(ctx context.Context, preferences Preferences) error
 
 	// FeedSubscriptionCreate creates a new Feed subscription for a given user.
-	FeedSubscriptionCreate(ctx context.Context, subscription Subscription) (Subscription, error)
+	//
+	// The subscription is only created if the user has less than maxSubscriptions
+	// subscriptions, otherwise quota.ErrSubscriptionLimitReached is returned; a zero
+	// maxSubscriptions means the number of subscriptions is unlimited.
+	FeedSubscriptionCreate(ctx context.Context, subscription Subscription, maxSubscriptions int64) (Subscription, error)
 
 	// FeedSubscriptionDelete deletes a given Feed subscription.
 	FeedSubscriptionDelete(ctx context.Context, userUUID string, subscriptionUUID string) error
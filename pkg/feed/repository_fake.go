@@ -7,6 +7,7 @@ import (
 	"context"
 	"slices"
 
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -332,7 +333,18 @@ func (r *FakeRepository) FeedSubscriptionIsRegistered(_ context.Context, userUUI
 	return false, nil
 }
 
-func (r *FakeRepository) FeedSubscriptionCreate(_ context.Context, subscription Subscription) (Subscription, error) {
+func (r *FakeRepository) FeedSubscriptionCreate(_ context.Context, subscription Subscription, maxSubscriptions int64) (Subscription, error) {
+	var current int64
+	for _, s := range r.Subscriptions {
+		if s.UserUUID == subscription.UserUUID {
+			current++
+		}
+	}
+
+	if err := quota.CheckSubscriptionCount(maxSubscriptions, current, 1); err != nil {
+		return Subscription{}, err
+	}
+
 	r.Subscriptions = append(r.Subscriptions, subscription)
 	return subscription, nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
 	"github.com/virtualtam/sparklemuffin/internal/textkit"
 	"github.com/virtualtam/sparklemuffin/pkg/feed/fetching"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 )
 
 // Service handles operations for the feed domain.
@@ -27,16 +28,20 @@ type Service struct {
 	// httpsafe.ValidateURL for production use.
 	validateURL func(ctx context.Context, rawURL string) error
 
+	// quotaService may be nil (subscriptions are not limited).
+	quotaService *quota.Service
+
 	textRanker       *textkit.TextRanker
 	textRankMaxTerms int
 }
 
 // NewService initializes and returns a Feed Service.
-func NewService(r Repository, client *fetching.Client, validateURL func(ctx context.Context, rawURL string) error) *Service {
+func NewService(r Repository, client *fetching.Client, validateURL func(ctx context.Context, rawURL string) error, quotaService *quota.Service) *Service {
 	return &Service{
 		r:                r,
 		client:           client,
 		validateURL:      validateURL,
+		quotaService:     quotaService,
 		textRanker:       textkit.NewTextRanker(),
 		textRankMaxTerms: EntryTextRankMaxTerms,
 	}
@@ -155,6 +160,11 @@ func (s *Service) FeedBySlug(ctx context.Context, slug string) (Feed, error) {
 // Subscribe creates a new Feed if needed, and creates the corresponding Subscription
 // for a given user.
 func (s *Service) Subscribe(ctx context.Context, userUUID string, categoryUUID string, feedURL string) error {
+	// check the quota before fetching the feed, to avoid unnecessary requests
+	if err := s.checkSubscriptionQuota(ctx, userUUID); err != nil {
+		return err
+	}
+
 	feed, _, err := s.GetOrCreateFeedAndEntries(ctx, feedURL)
 	if err != nil {
 		return fmt.Errorf("failed to create or retrieve feed: %w", err)
@@ -223,6 +233,7 @@ func (s *Service) PreferencesByUserUUID(ctx context.Context, userUUID string) (P
 }
 
 func (s *Service) UpdatePreferences(ctx context.Context, preferences Preferences) error {
+	preferences.Normalize()
 	preferences.UpdatedAt = time.Now().UTC()
 
 	if err := preferences.ValidateForUpdate(); err != nil {
@@ -395,5 +406,24 @@ func (s *Service) createSubscription(ctx context.Context, newSubscription Subscr
 		return Subscription{}, err
 	}
 
-	return s.r.FeedSubscriptionCreate(ctx, subscription)
+	var maxSubscriptions int64
+
+	if s.quotaService != nil {
+		limits, err := s.quotaService.LimitsByUserUUID(ctx, subscription.UserUUID)
+		if err != nil {
+			return Subscription{}, err
+		}
+
+		maxSubscriptions = limits.MaxSubscriptions
+	}
+
+	return s.r.FeedSubscriptionCreate(ctx, subscription, maxSubscriptions)
+}
+
+func (s *Service) checkSubscriptionQuota(ctx context.Context, userUUID string) error {
+	if s.quotaService == nil {
+		return nil
+	}
+
+	return s.quotaService.CheckSubscriptions(ctx, userUUID, 1)
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
 	"github.com/virtualtam/sparklemuffin/internal/test/feedtest"
 	"github.com/virtualtam/sparklemuffin/pkg/feed/fetching"
+	"github.com/virtualtam/sparklemuffin/pkg/quota"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
 )
 
@@ -125,7 +126,7 @@ func TestServiceAddCategory(t *testing.T) {
 			r := &FakeRepository{
 				Categories: tc.repositoryCategories,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			got, err := s.CreateCategory(t.Context(), userUUID, tc.name)
 
@@ -215,7 +216,7 @@ func TestServiceCategoryBySlug(t *testing.T) {
 			r := &FakeRepository{
 				Categories: tc.repositoryCategories,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			got, err := s.CategoryBySlug(t.Context(), userUUID, tc.slug)
 
@@ -300,7 +301,7 @@ func TestServiceCategoryByUUID(t *testing.T) {
 			r := &FakeRepository{
 				Categories: tc.repositoryCategories,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			got, err := s.CategoryByUUID(t.Context(), userUUID, tc.categoryUUID)
 
@@ -339,7 +340,7 @@ func TestServiceDeleteCategory(t *testing.T) {
 		r := &FakeRepository{
 			Categories: []Category{emptyCategory},
 		}
-		s := NewService(r, nil, nil)
+		s := NewService(r, nil, nil, nil)
 
 		if err := s.DeleteCategory(t.Context(), userUUID, emptyCategory.UUID); err != nil {
 			t.Fatalf("want no error, got %q", err)
@@ -397,7 +398,7 @@ func TestServiceDeleteCategory(t *testing.T) {
 			Entries:       entries,
 			Subscriptions: subscriptions,
 		}
-		s := NewService(r, nil, nil)
+		s := NewService(r, nil, nil, nil)
 
 		if err := s.DeleteCategory(t.Context(), userUUID, category.UUID); err != nil {
 			t.Fatalf("want no error, got %q", err)
@@ -454,7 +455,7 @@ func TestServiceDeleteCategoryValidation(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.DeleteCategory(t.Context(), tc.userUUID, tc.categoryUUID)
 
@@ -571,7 +572,7 @@ func TestServiceUpdateCategory(t *testing.T) {
 					existingCategory,
 				},
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.UpdateCategory(t.Context(), tc.updatedCategory)
 
@@ -727,7 +728,7 @@ In the second half of the 20th century, aluminium gained usage in transportation
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.createEntries(t.Context(), feedUUID, feedURL, tc.feedItems)
 
@@ -913,7 +914,7 @@ func TestServiceGetOrCreateFeedAndEntries(t *testing.T) {
 			feedClient := fetching.NewClient(testHTTPClient, "sparklemuffin/test")
 
 			// avoid a real DNS lookup for tc.feedURL's test-only hostnames
-			s := NewService(r, feedClient, noopURLValidator)
+			s := NewService(r, feedClient, noopURLValidator, nil)
 
 			gotFeed, gotIsCreated, err := s.GetOrCreateFeedAndEntries(t.Context(), tc.feedURL)
 
@@ -964,7 +965,7 @@ func TestServiceGetOrCreateFeedAndEntries_BlockedDestination(t *testing.T) {
 	transport := feedtest.NewRoundTripperFromFeed(t, feedtest.GenerateDummyFeed(t, time.Now().UTC()))
 	feedClient := fetching.NewClient(&http.Client{Transport: transport}, "sparklemuffin/test")
 
-	s := NewService(r, feedClient, httpsafe.ValidateURL)
+	s := NewService(r, feedClient, httpsafe.ValidateURL, nil)
 
 	_, _, err := s.GetOrCreateFeedAndEntries(t.Context(), "http://127.0.0.1/feed")
 
@@ -1056,7 +1057,7 @@ func TestServiceToggleEntryRead(t *testing.T) {
 				Entries:         tc.repositoryEntries,
 				EntriesMetadata: tc.repositoryEntriesMetadata,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.ToggleEntryRead(t.Context(), userUUID, tc.entryUID)
 
@@ -1103,6 +1104,22 @@ func TestServiceUpdatePreferences(t *testing.T) {
 				ShowEntries: EntryVisibilityUnread,
 			},
 		},
+		{
+			tname: "subscribe to a weekly digest",
+			repositoryPreferences: map[string]Preferences{
+				userUUID: {
+					UserUUID:        userUUID,
+					ShowEntries:     EntryVisibilityAll,
+					DigestFrequency: DigestFrequencyNone,
+				},
+			},
+			preferences: Preferences{
+				UserUUID:         userUUID,
+				ShowEntries:      EntryVisibilityAll,
+				DigestFrequency:  DigestFrequencyWeekly,
+				DigestMarkAsRead: true,
+			},
+		},
 
 		// Error cases.
 		{
@@ -1126,6 +1143,20 @@ func TestServiceUpdatePreferences(t *testing.T) {
 			},
 			wantErr: ErrPreferencesEntryVisibilityUnknown,
 		},
+		{
+			tname: "unknown digest frequency",
+			repositoryPreferences: map[string]Preferences{
+				userUUID: {
+					UserUUID: userUUID,
+				},
+			},
+			preferences: Preferences{
+				UserUUID:        userUUID,
+				ShowEntries:     EntryVisibilityAll,
+				DigestFrequency: DigestFrequency("HOURLY"),
+			},
+			wantErr: ErrPreferencesDigestFrequencyUnknown,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1133,7 +1164,7 @@ func TestServiceUpdatePreferences(t *testing.T) {
 			r := &FakeRepository{
 				Preferences: tc.repositoryPreferences,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.UpdatePreferences(t.Context(), tc.preferences)
 
@@ -1155,6 +1186,17 @@ func TestServiceUpdatePreferences(t *testing.T) {
 			if got.ShowEntries != tc.preferences.ShowEntries {
 				t.Errorf("want ShowEntries %q, got %q", tc.preferences.ShowEntries, got.ShowEntries)
 			}
+
+			wantDigestFrequency := tc.preferences.DigestFrequency
+			if wantDigestFrequency == "" {
+				wantDigestFrequency = DigestFrequencyNone
+			}
+			if got.DigestFrequency != wantDigestFrequency {
+				t.Errorf("want DigestFrequency %q, got %q", wantDigestFrequency, got.DigestFrequency)
+			}
+			if got.DigestMarkAsRead != tc.preferences.DigestMarkAsRead {
+				t.Errorf("want DigestMarkAsRead %t, got %t", tc.preferences.DigestMarkAsRead, got.DigestMarkAsRead)
+			}
 		})
 	}
 }
@@ -1178,9 +1220,10 @@ func TestServiceCreateSubscription(t *testing.T) {
 	repositorySubscriptions := []Subscription{existingSubscription}
 
 	cases := []struct {
-		tname        string
-		subscription Subscription
-		wantErr      error
+		tname            string
+		maxSubscriptions int64
+		subscription     Subscription
+		wantErr          error
 	}{
 		// nominal cases
 		{
@@ -1192,6 +1235,16 @@ func TestServiceCreateSubscription(t *testing.T) {
 				FeedUUID:     "c2b0fc18-c234-456e-a18c-6d453cfe11ab",
 			},
 		},
+		{
+			tname:            "new subscription within quota",
+			maxSubscriptions: 2,
+			subscription: Subscription{
+				UUID:         "0779aef5-269d-4ae3-9658-93427dd04581",
+				CategoryUUID: "99cee38d-1eab-4f2a-b598-d341b3b147ab",
+				UserUUID:     "a8343f4e-dd9e-4c81-bf5c-1a06d19e1ccf",
+				FeedUUID:     "c2b0fc18-c234-456e-a18c-6d453cfe11ab",
+			},
+		},
 
 		// error cases
 		{
@@ -1209,6 +1262,17 @@ func TestServiceCreateSubscription(t *testing.T) {
 			},
 			wantErr: ErrSubscriptionAlreadyRegistered,
 		},
+		{
+			tname:            "subscription limit reached",
+			maxSubscriptions: 1,
+			subscription: Subscription{
+				UUID:         "0779aef5-269d-4ae3-9658-93427dd04581",
+				CategoryUUID: "99cee38d-1eab-4f2a-b598-d341b3b147ab",
+				UserUUID:     "a8343f4e-dd9e-4c81-bf5c-1a06d19e1ccf",
+				FeedUUID:     "c2b0fc18-c234-456e-a18c-6d453cfe11ab",
+			},
+			wantErr: quota.ErrSubscriptionLimitReached,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1217,7 +1281,23 @@ func TestServiceCreateSubscription(t *testing.T) {
 				Feeds:         repositoryFeeds,
 				Subscriptions: repositorySubscriptions,
 			}
-			s := NewService(r, nil, nil)
+
+			var quotaService *quota.Service
+			if tc.maxSubscriptions > 0 {
+				quotaRepository := &quota.FakeRepository{
+					Usages: map[string]quota.Usage{
+						existingSubscription.UserUUID: {Subscriptions: int64(len(repositorySubscriptions))},
+					},
+				}
+
+				var err error
+				quotaService, err = quota.NewService(quotaRepository, quota.Limits{MaxSubscriptions: tc.maxSubscriptions})
+				if err != nil {
+					t.Fatalf("failed to create quota service: %q", err)
+				}
+			}
+
+			s := NewService(r, nil, nil, quotaService)
 
 			_, err := s.createSubscription(t.Context(), tc.subscription)
 
@@ -1278,7 +1358,7 @@ func TestServiceDeleteSubscription(t *testing.T) {
 			Entries:       entries,
 			Subscriptions: []Subscription{subscription},
 		}
-		s := NewService(r, nil, nil)
+		s := NewService(r, nil, nil, nil)
 
 		if err := s.DeleteSubscription(t.Context(), userUUID, subscription.UUID); err != nil {
 			t.Fatalf("want no error, got %q", err)
@@ -1299,7 +1379,7 @@ func TestServiceDeleteSubscriptionNotFound(t *testing.T) {
 	userUUID := fake.UUID().V4()
 
 	r := &FakeRepository{}
-	s := NewService(r, nil, nil)
+	s := NewService(r, nil, nil, nil)
 
 	err := s.DeleteSubscription(t.Context(), userUUID, fake.UUID().V4())
 
@@ -1419,7 +1499,7 @@ func TestServiceUpdateSubscription(t *testing.T) {
 			r := &FakeRepository{
 				Subscriptions: tc.repositorySubscriptions,
 			}
-			s := NewService(r, nil, nil)
+			s := NewService(r, nil, nil, nil)
 
 			err := s.UpdateSubscription(t.Context(), tc.subscription)
 
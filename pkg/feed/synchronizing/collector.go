@@ -4,7 +4,12 @@
 package synchronizing
 
 import (
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 const (
@@ -28,6 +33,9 @@ type Collector struct {
 	bytesTotal    prometheus.Counter
 	entriesTotal  prometheus.Counter
 	errorsTotal   *prometheus.CounterVec
+
+	runsMu     sync.Mutex
+	recentRuns []Run
 }
 
 // NewCollector initializes and returns a new Collector for feed synchronization metrics.
@@ -130,3 +138,56 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(c, ch)
 }
+
+// Statistics returns a snapshot of the feed synchronization metrics.
+func (c *Collector) Statistics() Statistics {
+	c.runsMu.Lock()
+	recentRuns := slices.Clone(c.recentRuns)
+	c.runsMu.Unlock()
+
+	return Statistics{
+		Tasks:        uint64(counterValue(c.tasksTotal)),
+		Duration:     time.Duration(counterValue(c.durationTotal)) * time.Millisecond,
+		UpdatedFeeds: uint64(counterValue(c.updatedFeeds)),
+		SkippedFeeds: uint64(counterValue(c.skippedFeeds)),
+		Bytes:        uint64(counterValue(c.bytesTotal)),
+		Entries:      uint64(counterValue(c.entriesTotal)),
+		Errors:       uint64(counterValue(c.errorsTotal)),
+		RecentRuns:   recentRuns,
+	}
+}
+
+// recordRun keeps track of the most recent synchronization runs.
+func (c *Collector) recordRun(run Run) {
+	c.runsMu.Lock()
+	defer c.runsMu.Unlock()
+
+	c.recentRuns = slices.Insert(c.recentRuns, 0, run)
+
+	if len(c.recentRuns) > maxRecentRuns {
+		c.recentRuns = c.recentRuns[:maxRecentRuns]
+	}
+}
+
+// counterValue returns the sum of all counters exposed by a Prometheus collector.
+func counterValue(collector prometheus.Collector) float64 {
+	ch := make(chan prometheus.Metric)
+
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+
+	var total float64
+
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+
+		total += m.GetCounter().GetValue()
+	}
+
+	return total
+}
@@ -16,6 +16,16 @@ type FeedFetchMetadata struct {
 	FetchedAt time.Time
 }
 
+// FeedFetchError represents a failure to fetch a feed from its remote server.
+//
+// The error is cleared once the feed has been fetched successfully.
+type FeedFetchError struct {
+	UUID string
+
+	Error    string
+	FailedAt time.Time
+}
+
 // FeedMetadata represents the metadata for a feed and its content.
 type FeedMetadata struct {
 	UUID string
@@ -18,7 +18,11 @@ type Repository interface {
 	// This method must only return feeds with at least one active user Subscription.
 	FeedGetNByLastSynchronizationTime(ctx context.Context, n uint, before time.Time) ([]feed.Feed, error)
 
-	// FeedUpdateFetchMetadata updates fetch metadata (ETag, FetchedAt, UpdatedAt) for a given feed.Feed.
+	// FeedUpdateFetchError records the last fetch error for a given feed.Feed.
+	FeedUpdateFetchError(ctx context.Context, feedFetchError FeedFetchError) error
+
+	// FeedUpdateFetchMetadata updates fetch metadata (ETag, FetchedAt, UpdatedAt) for a given feed.Feed,
+	// and clears its last fetch error.
 	FeedUpdateFetchMetadata(ctx context.Context, feedFetchMetadata FeedFetchMetadata) error
 
 	// FeedUpdateMetadata updates metadata (Title, Description) for a given feed.Feed.
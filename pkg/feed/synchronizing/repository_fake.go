@@ -5,6 +5,7 @@ package synchronizing
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/virtualtam/sparklemuffin/pkg/feed"
@@ -13,8 +14,12 @@ import (
 var _ Repository = &fakeRepository{}
 
 type fakeRepository struct {
-	Feeds   []feed.Feed
-	Entries []feed.Entry
+	Feeds       []feed.Feed
+	Entries     []feed.Entry
+	FetchErrors map[string]FeedFetchError
+
+	// fetchErrorsMu guards FetchErrors, which is updated by concurrent workers.
+	fetchErrorsMu sync.Mutex
 
 	FeedGetNByLastSynchronizationTimeErr error
 	FeedUpdateFetchMetadataErr           error
@@ -40,6 +45,19 @@ func (r *fakeRepository) FeedGetNByLastSynchronizationTime(_ context.Context, n
 	return feedsToSync, nil
 }
 
+func (r *fakeRepository) FeedUpdateFetchError(_ context.Context, feedFetchError FeedFetchError) error {
+	r.fetchErrorsMu.Lock()
+	defer r.fetchErrorsMu.Unlock()
+
+	if r.FetchErrors == nil {
+		r.FetchErrors = map[string]FeedFetchError{}
+	}
+
+	r.FetchErrors[feedFetchError.UUID] = feedFetchError
+
+	return nil
+}
+
 func (r *fakeRepository) FeedUpdateFetchMetadata(_ context.Context, feedFetchMetadata FeedFetchMetadata) error {
 	if r.FeedUpdateFetchMetadataErr != nil {
 		return r.FeedUpdateFetchMetadataErr
@@ -52,6 +70,10 @@ func (r *fakeRepository) FeedUpdateFetchMetadata(_ context.Context, feedFetchMet
 			r.Feeds[index].UpdatedAt = feedFetchMetadata.UpdatedAt
 			r.Feeds[index].FetchedAt = feedFetchMetadata.FetchedAt
 
+			r.fetchErrorsMu.Lock()
+			delete(r.FetchErrors, f.UUID)
+			r.fetchErrorsMu.Unlock()
+
 			return nil
 		}
 	}
@@ -55,11 +55,26 @@ func (s *Service) Collector() *Collector {
 // Synchronize synchronizes syndication feeds for all users.
 func (s *Service) Synchronize(ctx context.Context, jobID string) error {
 	s.collector.tasksTotal.Inc()
-	start := time.Now()
-	defer func() {
-		s.collector.durationTotal.Add(float64(time.Since(start).Milliseconds()))
-	}()
+	start := time.Now().UTC()
 
+	nFeeds, err := s.synchronize(ctx, jobID)
+
+	duration := time.Since(start)
+	s.collector.durationTotal.Add(float64(duration.Milliseconds()))
+	s.collector.recordRun(Run{
+		JobID:     jobID,
+		StartedAt: start,
+		Duration:  duration,
+		Feeds:     nFeeds,
+		Err:       err,
+	})
+
+	return err
+}
+
+// synchronize synchronizes syndication feeds for all users, and returns the number of feeds
+// that were due for synchronization.
+func (s *Service) synchronize(ctx context.Context, jobID string) (int, error) {
 	lastSyncBefore := time.Now().UTC().Add(-minFeedAge)
 
 	// 1. List all feeds that have last been synchronized before a given time.Time
@@ -71,12 +86,12 @@ func (s *Service) Synchronize(ctx context.Context, jobID string) error {
 			Str("job_id", jobID).
 			Msg("feeds: failed to list feeds to synchronize")
 		s.collector.errorsTotal.WithLabelValues(labelErrorTypeList).Inc()
-		return err
+		return 0, err
 	}
 
 	if len(feeds) == 0 {
 		log.Info().Msg("feeds: nothing to synchronize")
-		return nil
+		return 0, nil
 	}
 
 	// 2. Start a concurrent worker pool
@@ -101,10 +116,23 @@ func (s *Service) Synchronize(ctx context.Context, jobID string) error {
 			Err(err).
 			Str("job_id", jobID).
 			Msg("feeds: failed to synchronize some feeds")
-		return err
+		return len(feeds), err
 	}
 
-	return nil
+	return len(feeds), nil
+}
+
+// SynchronizeFeed synchronizes a single syndication feed, regardless of when it was last
+// synchronized.
+//
+// HTTP conditional request headers and the content hash are ignored, so that the remote
+// feed is always fetched and its entries updated.
+func (s *Service) SynchronizeFeed(ctx context.Context, f feed.Feed, jobID string) error {
+	f.ETag = ""
+	f.LastModified = time.Time{}
+	f.Hash = 0
+
+	return s.synchronizeFeed(ctx, f, jobID)
 }
 
 func (s *Service) synchronizeFeed(ctx context.Context, feed feed.Feed, jobID string) error {
@@ -123,6 +151,22 @@ func (s *Service) synchronizeFeed(ctx context.Context, feed feed.Feed, jobID str
 			Str("job_id", jobID).
 			Msg("feeds: failed to fetch feed")
 		s.collector.errorsTotal.WithLabelValues(labelErrorTypeFetch).Inc()
+
+		feedFetchError := FeedFetchError{
+			UUID:     feed.UUID,
+			Error:    err.Error(),
+			FailedAt: time.Now().UTC(),
+		}
+
+		if updateErr := s.r.FeedUpdateFetchError(ctx, feedFetchError); updateErr != nil {
+			log.
+				Error().
+				Err(updateErr).
+				Str("feed_url", feed.FeedURL).
+				Str("job_id", jobID).
+				Msg("feeds: failed to record fetch error")
+		}
+
 		return err
 	}
 
@@ -652,3 +652,139 @@ func TestServiceSynchronize(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceSynchronizeRecordsFetchErrors(t *testing.T) {
+	yesterday := time.Now().UTC().Add(-24 * time.Hour)
+
+	repositoryFeed := feed.Feed{
+		UUID:      "38d1d4c0-4f4f-4bd6-9b2c-2a8b4e5b6c7d",
+		FeedURL:   "http://test.local",
+		Title:     "Local Test",
+		Slug:      "local-test",
+		CreatedAt: yesterday,
+		UpdatedAt: yesterday,
+		FetchedAt: yesterday,
+	}
+
+	r := &fakeRepository{
+		Feeds: []feed.Feed{repositoryFeed},
+	}
+
+	feedClient := fetching.NewClient(&http.Client{Transport: &errorRoundTripper{}}, "sparklemuffin/test")
+	s := NewService(r, feedClient, "test")
+
+	if err := s.Synchronize(t.Context(), "first-run"); !errors.Is(err, errFetchFailed) {
+		t.Fatalf("want error %q, got %q", errFetchFailed, err)
+	}
+
+	fetchError, ok := r.FetchErrors[repositoryFeed.UUID]
+	if !ok {
+		t.Fatalf("want fetch error to be recorded for feed %q", repositoryFeed.UUID)
+	}
+	if fetchError.Error == "" {
+		t.Error("want fetch error message, got empty string")
+	}
+	if fetchError.FailedAt.IsZero() {
+		t.Error("want fetch error time, got zero value")
+	}
+
+	if err := s.Synchronize(t.Context(), "second-run"); !errors.Is(err, errFetchFailed) {
+		t.Fatalf("want error %q, got %q", errFetchFailed, err)
+	}
+
+	statistics := s.Collector().Statistics()
+
+	if statistics.Tasks != 2 {
+		t.Errorf("want 2 tasks, got %d", statistics.Tasks)
+	}
+	if statistics.Errors != 2 {
+		t.Errorf("want 2 errors, got %d", statistics.Errors)
+	}
+
+	if len(statistics.RecentRuns) != 2 {
+		t.Fatalf("want 2 recent runs, got %d", len(statistics.RecentRuns))
+	}
+
+	latestRun := statistics.RecentRuns[0]
+	if latestRun.JobID != "second-run" {
+		t.Errorf("want latest run %q, got %q", "second-run", latestRun.JobID)
+	}
+	if latestRun.Feeds != 1 {
+		t.Errorf("want 1 feed, got %d", latestRun.Feeds)
+	}
+	if !errors.Is(latestRun.Err, errFetchFailed) {
+		t.Errorf("want run error %q, got %q", errFetchFailed, latestRun.Err)
+	}
+}
+
+func TestCollectorRecordRun(t *testing.T) {
+	c := NewCollector("test")
+
+	for i := range maxRecentRuns + 5 {
+		c.recordRun(Run{Feeds: i})
+	}
+
+	runs := c.Statistics().RecentRuns
+
+	if len(runs) != maxRecentRuns {
+		t.Fatalf("want %d recent runs, got %d", maxRecentRuns, len(runs))
+	}
+
+	if runs[0].Feeds != maxRecentRuns+4 {
+		t.Errorf("want latest run first (%d feeds), got %d feeds", maxRecentRuns+4, runs[0].Feeds)
+	}
+}
+
+func TestServiceSynchronizeFeed(t *testing.T) {
+	today, err := time.Parse(time.DateTime, "2024-10-30 20:54:16")
+	if err != nil {
+		t.Fatalf("failed to parse date: %q", err)
+	}
+
+	atomFeed := feedtest.GenerateDummyFeed(t, today)
+
+	feedStr, err := atomFeed.ToAtom()
+	if err != nil {
+		t.Fatalf("failed to encode feed to Atom: %q", err)
+	}
+
+	// The feed is up-to-date: a regular synchronization would skip it.
+	repositoryFeed := feed.Feed{
+		UUID:         "38d1d4c0-4f4f-4bd6-9b2c-2a8b4e5b6c7d",
+		FeedURL:      "http://test.local",
+		Title:        atomFeed.Title,
+		Description:  atomFeed.Description,
+		Slug:         "local-test",
+		ETag:         feedtest.HashETag(feedStr),
+		LastModified: today,
+		Hash:         xxhash.Sum64([]byte(feedStr)),
+		CreatedAt:    today,
+		UpdatedAt:    today,
+		FetchedAt:    time.Now().UTC(),
+	}
+
+	r := &fakeRepository{
+		Feeds: []feed.Feed{repositoryFeed},
+	}
+
+	roundTripper := feedtest.NewRoundTripperFromFeed(t, atomFeed)
+	feedClient := fetching.NewClient(&http.Client{Transport: roundTripper}, "sparklemuffin/test")
+	s := NewService(r, feedClient, "test")
+
+	if err := s.SynchronizeFeed(t.Context(), repositoryFeed, "admin"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(roundTripper.Requests) != 1 {
+		t.Fatalf("want 1 request, got %d", len(roundTripper.Requests))
+	}
+
+	req := roundTripper.Requests[0]
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		t.Errorf("want no conditional request headers, got %v", req.Header)
+	}
+
+	if len(r.Entries) != len(atomFeed.Items) {
+		t.Errorf("want %d entries, got %d", len(atomFeed.Items), len(r.Entries))
+	}
+}
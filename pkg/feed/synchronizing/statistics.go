@@ -0,0 +1,55 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package synchronizing
+
+import "time"
+
+const (
+	// maxRecentRuns is the number of synchronization runs kept in memory for monitoring.
+	maxRecentRuns = 10
+)
+
+// Run represents a single execution of the feed synchronization task.
+type Run struct {
+	JobID     string
+	StartedAt time.Time
+	Duration  time.Duration
+
+	// Feeds is the number of feeds that were due for synchronization.
+	Feeds int
+
+	Err error
+}
+
+// Statistics represents feed synchronization metrics, since the service was started.
+type Statistics struct {
+	Tasks        uint64
+	Duration     time.Duration
+	UpdatedFeeds uint64
+	SkippedFeeds uint64
+	Bytes        uint64
+	Entries      uint64
+	Errors       uint64
+
+	// RecentRuns lists the most recent synchronization runs, latest first.
+	RecentRuns []Run
+}
+
+// BytesPerSecond returns the average amount of feed data fetched per second of synchronization.
+func (s Statistics) BytesPerSecond() uint64 {
+	if s.Duration < time.Second {
+		return 0
+	}
+
+	return s.Bytes / uint64(s.Duration/time.Second)
+}
+
+// EntriesPerTask returns the average number of feed entries synchronized per task.
+func (s Statistics) EntriesPerTask() uint64 {
+	if s.Tasks == 0 {
+		return 0
+	}
+
+	return s.Entries / s.Tasks
+}
@@ -0,0 +1,19 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import "context"
+
+// Repository provides access to instance-wide usage statistics.
+type Repository interface {
+	// InstanceCountsGet returns the number of resources stored on the instance.
+	InstanceCountsGet(ctx context.Context) (Counts, error)
+
+	// InstanceFeedErrorsGetN returns at most n feeds whose last fetch failed,
+	// most recent failures first.
+	InstanceFeedErrorsGetN(ctx context.Context, n uint) ([]FeedError, error)
+
+	// InstanceTableSizesGet returns the disk space used by database tables, largest first.
+	InstanceTableSizesGet(ctx context.Context) ([]TableSize, error)
+}
@@ -0,0 +1,30 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import "context"
+
+var _ Repository = &FakeRepository{}
+
+type FakeRepository struct {
+	Counts     Counts
+	FeedErrors []FeedError
+	TableSizes []TableSize
+}
+
+func (r *FakeRepository) InstanceCountsGet(_ context.Context) (Counts, error) {
+	return r.Counts, nil
+}
+
+func (r *FakeRepository) InstanceFeedErrorsGetN(_ context.Context, n uint) ([]FeedError, error) {
+	if uint(len(r.FeedErrors)) > n {
+		return r.FeedErrors[:n], nil
+	}
+
+	return r.FeedErrors, nil
+}
+
+func (r *FakeRepository) InstanceTableSizesGet(_ context.Context) ([]TableSize, error) {
+	return r.TableSizes, nil
+}
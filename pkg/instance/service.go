@@ -0,0 +1,49 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import "context"
+
+const (
+	// maxFeedErrors is the number of failing feeds listed in the Overview.
+	maxFeedErrors uint = 20
+)
+
+// Service handles instance-wide usage statistics, for administration purposes.
+type Service struct {
+	r Repository
+}
+
+// NewService initializes and returns an instance Service.
+func NewService(r Repository) *Service {
+	return &Service{
+		r: r,
+	}
+}
+
+// Overview returns usage statistics for the whole instance.
+func (s *Service) Overview(ctx context.Context) (Overview, error) {
+	counts, err := s.r.InstanceCountsGet(ctx)
+	if err != nil {
+		return Overview{}, err
+	}
+
+	tableSizes, err := s.r.InstanceTableSizesGet(ctx)
+	if err != nil {
+		return Overview{}, err
+	}
+
+	feedErrors, err := s.r.InstanceFeedErrorsGetN(ctx, maxFeedErrors)
+	if err != nil {
+		return Overview{}, err
+	}
+
+	overview := Overview{
+		Counts:     counts,
+		TableSizes: tableSizes,
+		FeedErrors: feedErrors,
+	}
+
+	return overview, nil
+}
@@ -0,0 +1,97 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestServiceOverview(t *testing.T) {
+	now := time.Now().UTC()
+
+	var feedErrors []FeedError
+	for i := range maxFeedErrors + 5 {
+		feedErrors = append(feedErrors, FeedError{
+			FeedURL:  fmt.Sprintf("https://example.org/feed-%d.xml", i),
+			Error:    "connection refused",
+			FailedAt: now,
+		})
+	}
+
+	cases := []struct {
+		tname string
+		r     *FakeRepository
+
+		wantCounts       Counts
+		wantDatabaseSize int64
+		wantFeedErrors   int
+	}{
+		{
+			tname: "empty instance",
+			r:     &FakeRepository{},
+		},
+		{
+			tname: "populated instance",
+			r: &FakeRepository{
+				Counts: Counts{
+					Users:          3,
+					Bookmarks:      120,
+					Feeds:          12,
+					FeedEntries:    4500,
+					Subscriptions:  15,
+					ActiveSessions: 2,
+					FeedsInError:   1,
+				},
+				FeedErrors: feedErrors[:1],
+				TableSizes: []TableSize{
+					{Name: "feed_entries", SizeBytes: 4096},
+					{Name: "bookmarks", SizeBytes: 1024},
+				},
+			},
+			wantCounts: Counts{
+				Users:          3,
+				Bookmarks:      120,
+				Feeds:          12,
+				FeedEntries:    4500,
+				Subscriptions:  15,
+				ActiveSessions: 2,
+				FeedsInError:   1,
+			},
+			wantDatabaseSize: 5120,
+			wantFeedErrors:   1,
+		},
+		{
+			tname: "feed errors are limited",
+			r: &FakeRepository{
+				FeedErrors: feedErrors,
+			},
+			wantFeedErrors: int(maxFeedErrors),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s := NewService(tc.r)
+
+			overview, err := s.Overview(t.Context())
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if overview.Counts != tc.wantCounts {
+				t.Errorf("want counts %+v, got %+v", tc.wantCounts, overview.Counts)
+			}
+
+			if overview.DatabaseSize() != tc.wantDatabaseSize {
+				t.Errorf("want database size %d, got %d", tc.wantDatabaseSize, overview.DatabaseSize())
+			}
+
+			if len(overview.FeedErrors) != tc.wantFeedErrors {
+				t.Errorf("want %d feed errors, got %d", tc.wantFeedErrors, len(overview.FeedErrors))
+			}
+		})
+	}
+}
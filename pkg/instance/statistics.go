@@ -0,0 +1,49 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import "time"
+
+// Counts represents the number of resources stored on the instance.
+type Counts struct {
+	Users          int64
+	Bookmarks      int64
+	Feeds          int64
+	FeedEntries    int64
+	Subscriptions  int64
+	ActiveSessions int64
+	FeedsInError   int64
+}
+
+// TableSize represents the disk space used by a database table, including its indexes.
+type TableSize struct {
+	Name      string
+	SizeBytes int64
+}
+
+// FeedError represents the last error encountered when fetching a syndication feed.
+type FeedError struct {
+	FeedURL  string
+	Title    string
+	Error    string
+	FailedAt time.Time
+}
+
+// Overview represents usage statistics for the whole instance.
+type Overview struct {
+	Counts     Counts
+	TableSizes []TableSize
+	FeedErrors []FeedError
+}
+
+// DatabaseSize returns the total disk space used by database tables.
+func (o Overview) DatabaseSize() int64 {
+	var total int64
+
+	for _, tableSize := range o.TableSizes {
+		total += tableSize.SizeBytes
+	}
+
+	return total
+}
@@ -0,0 +1,11 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import "errors"
+
+var (
+	ErrClientIPRequired = errors.New("lockout: client IP required")
+	ErrEmailRequired    = errors.New("lockout: email required")
+)
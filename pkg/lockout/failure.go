@@ -0,0 +1,62 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Failure represents a failed login attempt for a given account, from a given IP address.
+//
+// Failures are recorded for the submitted email address, whether or not an account is
+// registered with it, so that throttling does not reveal which accounts exist. Failures that
+// cannot be attributed to an account have an empty email address, and only count towards
+// the throttling of their IP address.
+type Failure struct {
+	UUID      string
+	Email     string
+	ClientIP  string
+	CreatedAt time.Time
+}
+
+// NewFailure initializes and returns a new Failure.
+func NewFailure(email string, clientIP string, now time.Time) (Failure, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Failure{}, err
+	}
+
+	return Failure{
+		UUID:      generatedUUID.String(),
+		Email:     normalizeEmail(email),
+		ClientIP:  strings.TrimSpace(clientIP),
+		CreatedAt: now,
+	}, nil
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when recording a Failure.
+//
+// The client IP address may be empty, e.g. if the configured proxy header is missing from
+// the request; such failures only count towards the account's lockout.
+func (f *Failure) ValidateForAddition() error {
+	if f.Email == "" {
+		return ErrEmailRequired
+	}
+
+	return nil
+}
+
+// Summary holds the failed login attempts recorded for an account or an IP address.
+type Summary struct {
+	Count         uint
+	LastClientIP  string
+	LastFailureAt time.Time
+}
+
+func normalizeEmail(email string) string {
+	return strings.TrimSpace(strings.ToLower(email))
+}
@@ -0,0 +1,29 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Repository provides access to failed login attempts.
+type Repository interface {
+	// LoginFailureAdd saves a new Failure.
+	LoginFailureAdd(ctx context.Context, f Failure) error
+
+	// LoginFailureDeleteByEmail deletes all failures recorded for a given email address.
+	LoginFailureDeleteByEmail(ctx context.Context, email string) error
+
+	// LoginFailureDeleteBefore deletes all failures recorded before a given time.
+	LoginFailureDeleteBefore(ctx context.Context, before time.Time) error
+
+	// LoginFailureGetSummaryByClientIP returns a Summary of the failures recorded for a
+	// given IP address since a given time.
+	LoginFailureGetSummaryByClientIP(ctx context.Context, clientIP string, since time.Time) (Summary, error)
+
+	// LoginFailureGetSummaryByEmail returns a Summary of the failures recorded for a given
+	// email address since a given time.
+	LoginFailureGetSummaryByEmail(ctx context.Context, email string, since time.Time) (Summary, error)
+}
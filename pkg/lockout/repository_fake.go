@@ -0,0 +1,67 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Failures []Failure
+}
+
+func (r *FakeRepository) LoginFailureAdd(_ context.Context, f Failure) error {
+	r.Failures = append(r.Failures, f)
+	return nil
+}
+
+func (r *FakeRepository) LoginFailureDeleteByEmail(_ context.Context, email string) error {
+	r.Failures = slices.DeleteFunc(r.Failures, func(f Failure) bool {
+		return f.Email == email
+	})
+	return nil
+}
+
+func (r *FakeRepository) LoginFailureDeleteBefore(_ context.Context, before time.Time) error {
+	r.Failures = slices.DeleteFunc(r.Failures, func(f Failure) bool {
+		return f.CreatedAt.Before(before)
+	})
+	return nil
+}
+
+func (r *FakeRepository) LoginFailureGetSummaryByClientIP(_ context.Context, clientIP string, since time.Time) (Summary, error) {
+	return r.summary(since, func(f Failure) bool {
+		return f.ClientIP == clientIP
+	}), nil
+}
+
+func (r *FakeRepository) LoginFailureGetSummaryByEmail(_ context.Context, email string, since time.Time) (Summary, error) {
+	return r.summary(since, func(f Failure) bool {
+		return f.Email == email
+	}), nil
+}
+
+func (r *FakeRepository) summary(since time.Time, match func(Failure) bool) Summary {
+	var summary Summary
+
+	for _, f := range r.Failures {
+		if f.CreatedAt.Before(since) || !match(f) {
+			continue
+		}
+
+		summary.Count++
+
+		if f.CreatedAt.After(summary.LastFailureAt) {
+			summary.LastFailureAt = f.CreatedAt
+			summary.LastClientIP = f.ClientIP
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,279 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const notifyTimeout = 1 * time.Minute
+
+var lockoutNoticeTemplate = template.Must(template.New("lockout").Parse(`Hello {{ .DisplayName }},
+
+Your SparkleMuffin account has been temporarily locked after {{ .Count }} failed login attempts.
+
+  Last attempt from: {{ .ClientIP }}
+  Locked until:      {{ .Until.Format "2006-01-02 15:04:05 MST" }}
+
+If you did not try to log in, someone may be trying to guess your password;
+consider changing it once the lock expires, and enabling two-factor authentication.
+`))
+
+// Service handles failed login attempts, and the resulting throttling of login attempts.
+type Service struct {
+	r           Repository
+	userService *user.Service
+	notifier    notification.Notifier
+
+	// pending tracks lockout notices being sent in the background.
+	pending sync.WaitGroup
+}
+
+// NewService initializes and returns a lockout Service.
+//
+// notifier may be nil, in which case users are not notified when their account is locked.
+func NewService(r Repository, userService *user.Service, notifier notification.Notifier) *Service {
+	return &Service{
+		r:           r,
+		userService: userService,
+		notifier:    notifier,
+	}
+}
+
+// Check returns the Throttle in effect for login attempts with a given email address,
+// from a given IP address.
+//
+// The returned Throttle is inactive if the login attempt can proceed.
+func (s *Service) Check(ctx context.Context, email string, clientIP string) (Throttle, error) {
+	now := time.Now().UTC()
+
+	if throttle, err := s.CheckClientIP(ctx, clientIP); err != nil || throttle.Active(now) {
+		return throttle, err
+	}
+
+	accountSummary, err := s.r.LoginFailureGetSummaryByEmail(ctx, normalizeEmail(email), now.Add(-AccountFailureWindow))
+	if err != nil {
+		return Throttle{}, err
+	}
+
+	if throttle := accountThrottle(accountSummary); throttle.Active(now) {
+		return throttle, nil
+	}
+
+	return Throttle{}, nil
+}
+
+// CheckClientIP returns the Throttle in effect for login attempts from a given IP address,
+// regardless of the targeted account.
+//
+// The returned Throttle is inactive if the login attempt can proceed.
+func (s *Service) CheckClientIP(ctx context.Context, clientIP string) (Throttle, error) {
+	if clientIP == "" {
+		return Throttle{}, nil
+	}
+
+	now := time.Now().UTC()
+
+	summary, err := s.r.LoginFailureGetSummaryByClientIP(ctx, clientIP, now.Add(-ClientIPFailureWindow))
+	if err != nil {
+		return Throttle{}, err
+	}
+
+	if throttle := clientIPThrottle(summary); throttle.Active(now) {
+		return throttle, nil
+	}
+
+	return Throttle{}, nil
+}
+
+// RecordFailure records a failed login attempt, and returns the Throttle it results in
+// for the corresponding account.
+//
+// If the account gets locked, its owner is notified by email in the background, so as not
+// to disclose which addresses are registered through the duration of the login attempt.
+func (s *Service) RecordFailure(ctx context.Context, email string, clientIP string) (Throttle, error) {
+	now := time.Now().UTC()
+
+	failure, err := NewFailure(email, clientIP, now)
+	if err != nil {
+		return Throttle{}, err
+	}
+
+	if err := failure.ValidateForAddition(); err != nil {
+		return Throttle{}, err
+	}
+
+	if err := s.r.LoginFailureDeleteBefore(ctx, now.Add(-AccountFailureWindow)); err != nil {
+		return Throttle{}, err
+	}
+
+	if err := s.r.LoginFailureAdd(ctx, failure); err != nil {
+		return Throttle{}, err
+	}
+
+	summary, err := s.r.LoginFailureGetSummaryByEmail(ctx, failure.Email, now.Add(-AccountFailureWindow))
+	if err != nil {
+		return Throttle{}, err
+	}
+
+	throttle := accountThrottle(summary)
+
+	if throttle.Reason == ReasonAccountLocked && s.notifier != nil && s.userService != nil {
+		s.pending.Go(func() {
+			notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+			defer cancel()
+
+			s.notifyLockout(notifyCtx, failure.Email, summary, throttle)
+		})
+	}
+
+	return throttle, nil
+}
+
+// RecordClientIPFailure records a failed login attempt that cannot be attributed to an
+// account, e.g. with an unknown passkey.
+//
+// Such failures only count towards the throttling of the client's IP address.
+func (s *Service) RecordClientIPFailure(ctx context.Context, clientIP string) error {
+	now := time.Now().UTC()
+
+	failure, err := NewFailure("", clientIP, now)
+	if err != nil {
+		return err
+	}
+
+	if failure.ClientIP == "" {
+		return ErrClientIPRequired
+	}
+
+	if err := s.r.LoginFailureDeleteBefore(ctx, now.Add(-AccountFailureWindow)); err != nil {
+		return err
+	}
+
+	return s.r.LoginFailureAdd(ctx, failure)
+}
+
+// Reset clears the failed login attempts recorded for a given email address, and returns
+// a Summary of the cleared attempts.
+//
+// It is called after a successful login, so the user can be told about suspicious attempts.
+func (s *Service) Reset(ctx context.Context, email string) (Summary, error) {
+	summary, err := s.Status(ctx, email)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	if summary.Count == 0 {
+		return Summary{}, nil
+	}
+
+	if err := s.r.LoginFailureDeleteByEmail(ctx, normalizeEmail(email)); err != nil {
+		return Summary{}, err
+	}
+
+	return summary, nil
+}
+
+// Status returns a Summary of the recent failed login attempts for a given email address.
+func (s *Service) Status(ctx context.Context, email string) (Summary, error) {
+	email = normalizeEmail(email)
+	if email == "" {
+		return Summary{}, ErrEmailRequired
+	}
+
+	return s.r.LoginFailureGetSummaryByEmail(ctx, email, time.Now().UTC().Add(-AccountFailureWindow))
+}
+
+// Throttle returns the Throttle currently in effect for a given email address.
+func (s *Service) Throttle(ctx context.Context, email string) (Throttle, error) {
+	summary, err := s.Status(ctx, email)
+	if err != nil {
+		return Throttle{}, err
+	}
+
+	throttle := accountThrottle(summary)
+	if !throttle.Active(time.Now().UTC()) {
+		return Throttle{}, nil
+	}
+
+	return throttle, nil
+}
+
+// Unlock clears the failed login attempts recorded for a given email address, lifting
+// any delay or lock applied to the corresponding account.
+func (s *Service) Unlock(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if email == "" {
+		return ErrEmailRequired
+	}
+
+	return s.r.LoginFailureDeleteByEmail(ctx, email)
+}
+
+// Wait blocks until all lockout notices being sent in the background have been processed.
+func (s *Service) Wait() {
+	s.pending.Wait()
+}
+
+func (s *Service) notifyLockout(ctx context.Context, email string, summary Summary, throttle Throttle) {
+	u, err := s.userService.ByEmail(ctx, email)
+	if errors.Is(err, user.ErrNotFound) {
+		// failed attempts for unknown accounts are throttled, but there is no one to notify
+		return
+	} else if err != nil {
+		log.Error().Err(err).Msg("lockout: failed to retrieve user")
+		return
+	}
+
+	if err := s.sendLockoutNotice(ctx, u, summary, throttle); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("user_uuid", u.UUID).
+			Msg("lockout: failed to notify user of account lockout")
+	}
+}
+
+func (s *Service) sendLockoutNotice(ctx context.Context, u user.User, summary Summary, throttle Throttle) error {
+	displayName := u.DisplayName
+	if displayName == "" {
+		displayName = u.NickName
+	}
+
+	data := struct {
+		DisplayName string
+		Count       uint
+		ClientIP    string
+		Until       time.Time
+	}{
+		DisplayName: displayName,
+		Count:       summary.Count,
+		ClientIP:    summary.LastClientIP,
+		Until:       throttle.Until,
+	}
+
+	var body bytes.Buffer
+
+	if err := lockoutNoticeTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+
+	message := notification.Message{
+		To:      u.Email,
+		Subject: "Your account has been temporarily locked",
+		Body:    body.String(),
+	}
+
+	return s.notifier.Notify(ctx, message)
+}
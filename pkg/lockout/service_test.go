@@ -0,0 +1,447 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testEmail    = "jane.doe@example.org"
+	testClientIP = "192.0.2.10"
+)
+
+var testJane = user.User{
+	UUID:        "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+	Email:       testEmail,
+	NickName:    "jane",
+	DisplayName: "Jane Doe",
+}
+
+// blockingNotifier blocks sending messages until released.
+type blockingNotifier struct {
+	release chan struct{}
+	sent    int
+}
+
+func (n *blockingNotifier) Notify(ctx context.Context, _ notification.Message) error {
+	select {
+	case <-n.release:
+		n.sent++
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newTestFailures returns n failures for an email address and IP address, the most recent
+// one being recorded at a given time.
+func newTestFailures(n int, email string, clientIP string, last time.Time) []Failure {
+	failures := make([]Failure, n)
+
+	for i := range failures {
+		failures[i] = Failure{
+			Email:     email,
+			ClientIP:  clientIP,
+			CreatedAt: last.Add(-time.Duration(n-1-i) * time.Second),
+		}
+	}
+
+	return failures
+}
+
+func TestAccountThrottle(t *testing.T) {
+	last := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		tname string
+		count uint
+		want  Throttle
+	}{
+		{
+			tname: "no failures",
+		},
+		{
+			tname: "below delay threshold",
+			count: AccountDelayThreshold - 1,
+		},
+		{
+			tname: "delay threshold",
+			count: AccountDelayThreshold,
+			want:  Throttle{Reason: ReasonDelayed, Until: last.Add(AccountDelayBase)},
+		},
+		{
+			tname: "delay doubles",
+			count: AccountDelayThreshold + 2,
+			want:  Throttle{Reason: ReasonDelayed, Until: last.Add(4 * AccountDelayBase)},
+		},
+		{
+			tname: "delay is capped",
+			count: AccountLockoutThreshold - 1,
+			want:  Throttle{Reason: ReasonDelayed, Until: last.Add(AccountDelayMax)},
+		},
+		{
+			tname: "lockout threshold",
+			count: AccountLockoutThreshold,
+			want:  Throttle{Reason: ReasonAccountLocked, Until: last.Add(AccountLockoutDuration)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got := accountThrottle(Summary{Count: tc.count, LastFailureAt: last})
+
+			if got != tc.want {
+				t.Errorf("want %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestThrottleRetryAfter(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		tname    string
+		throttle Throttle
+		want     time.Duration
+	}{
+		{
+			tname: "inactive",
+		},
+		{
+			tname:    "expired",
+			throttle: Throttle{Reason: ReasonDelayed, Until: now.Add(-time.Second)},
+		},
+		{
+			tname:    "rounded up",
+			throttle: Throttle{Reason: ReasonDelayed, Until: now.Add(1500 * time.Millisecond)},
+			want:     2 * time.Second,
+		},
+		{
+			tname:    "locked",
+			throttle: Throttle{Reason: ReasonAccountLocked, Until: now.Add(AccountLockoutDuration)},
+			want:     AccountLockoutDuration,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			if got := tc.throttle.RetryAfter(now); got != tc.want {
+				t.Errorf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceCheck(t *testing.T) {
+	now := time.Now().UTC()
+
+	cases := []struct {
+		tname      string
+		failures   []Failure
+		email      string
+		wantReason Reason
+	}{
+		{
+			tname: "no failures",
+			email: testEmail,
+		},
+		{
+			tname:    "few failures",
+			failures: newTestFailures(2, testEmail, testClientIP, now),
+			email:    testEmail,
+		},
+		{
+			tname:      "delayed",
+			failures:   newTestFailures(5, testEmail, testClientIP, now),
+			email:      testEmail,
+			wantReason: ReasonDelayed,
+		},
+		{
+			tname:    "delay elapsed",
+			failures: newTestFailures(5, testEmail, testClientIP, now.Add(-time.Minute)),
+			email:    testEmail,
+		},
+		{
+			tname:      "locked, email is normalized",
+			failures:   newTestFailures(int(AccountLockoutThreshold), testEmail, testClientIP, now),
+			email:      "  Jane.Doe@Example.org ",
+			wantReason: ReasonAccountLocked,
+		},
+		{
+			tname:    "lock expired",
+			failures: newTestFailures(int(AccountLockoutThreshold), testEmail, testClientIP, now.Add(-AccountLockoutDuration)),
+			email:    testEmail,
+		},
+		{
+			tname:    "other account locked",
+			failures: newTestFailures(int(AccountLockoutThreshold), "john.doe@example.org", "198.51.100.1", now),
+			email:    testEmail,
+		},
+		{
+			tname:      "client IP blocked",
+			failures:   newTestFailures(int(ClientIPBlockThreshold), "john.doe@example.org", testClientIP, now),
+			email:      testEmail,
+			wantReason: ReasonClientIPBlocked,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{Failures: tc.failures}
+			s := NewService(r, nil, nil)
+
+			throttle, err := s.Check(t.Context(), tc.email, testClientIP)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if throttle.Reason != tc.wantReason {
+				t.Errorf("want reason %q, got %q", tc.wantReason, throttle.Reason)
+			}
+		})
+	}
+}
+
+func TestServiceRecordFailure(t *testing.T) {
+	now := time.Now().UTC()
+
+	cases := []struct {
+		tname        string
+		failures     []Failure
+		users        []user.User
+		email        string
+		clientIP     string
+		wantReason   Reason
+		wantErr      error
+		wantFailures int
+		wantNotices  int
+	}{
+		{
+			tname:    "email required",
+			email:    " ",
+			clientIP: testClientIP,
+			wantErr:  ErrEmailRequired,
+		},
+		{
+			tname:        "unknown client IP",
+			email:        testEmail,
+			wantFailures: 1,
+		},
+		{
+			tname:        "first failure",
+			email:        testEmail,
+			clientIP:     testClientIP,
+			wantFailures: 1,
+		},
+		{
+			tname:        "outdated failures are purged",
+			failures:     newTestFailures(5, testEmail, testClientIP, now.Add(-AccountFailureWindow)),
+			email:        testEmail,
+			clientIP:     testClientIP,
+			wantFailures: 1,
+		},
+		{
+			tname:        "delayed",
+			failures:     newTestFailures(int(AccountDelayThreshold)-1, testEmail, testClientIP, now),
+			email:        testEmail,
+			clientIP:     testClientIP,
+			wantReason:   ReasonDelayed,
+			wantFailures: int(AccountDelayThreshold),
+		},
+		{
+			tname:        "locked, user notified",
+			failures:     newTestFailures(int(AccountLockoutThreshold)-1, testEmail, testClientIP, now),
+			users:        []user.User{testJane},
+			email:        "Jane.Doe@example.org",
+			clientIP:     testClientIP,
+			wantReason:   ReasonAccountLocked,
+			wantFailures: int(AccountLockoutThreshold),
+			wantNotices:  1,
+		},
+		{
+			tname:        "locked, unknown user",
+			failures:     newTestFailures(int(AccountLockoutThreshold)-1, testEmail, testClientIP, now),
+			email:        testEmail,
+			clientIP:     testClientIP,
+			wantReason:   ReasonAccountLocked,
+			wantFailures: int(AccountLockoutThreshold),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{Failures: tc.failures}
+			userService := user.NewService(&user.FakeRepository{Users: tc.users}, user.FakePasswordHasher(t))
+			notifier := &notification.FakeNotifier{}
+			s := NewService(r, userService, notifier)
+
+			throttle, err := s.RecordFailure(t.Context(), tc.email, tc.clientIP)
+			s.Wait()
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if throttle.Reason != tc.wantReason {
+				t.Errorf("want reason %q, got %q", tc.wantReason, throttle.Reason)
+			}
+
+			if got := len(r.Failures); got != tc.wantFailures {
+				t.Errorf("want %d failures, got %d", tc.wantFailures, got)
+			}
+
+			if got := len(notifier.Messages); got != tc.wantNotices {
+				t.Fatalf("want %d notices, got %d", tc.wantNotices, got)
+			}
+
+			if tc.wantNotices > 0 {
+				message := notifier.Messages[0]
+
+				if message.To != testEmail {
+					t.Errorf("want notice sent to %q, got %q", testEmail, message.To)
+				}
+				if !strings.Contains(message.Body, testClientIP) {
+					t.Errorf("want notice to include the client IP, got %q", message.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceRecordFailureNotifiesInBackground(t *testing.T) {
+	now := time.Now().UTC()
+
+	r := &FakeRepository{
+		Failures: newTestFailures(int(AccountLockoutThreshold)-1, testEmail, testClientIP, now),
+	}
+	userService := user.NewService(&user.FakeRepository{Users: []user.User{testJane}}, user.FakePasswordHasher(t))
+	notifier := &blockingNotifier{release: make(chan struct{})}
+	s := NewService(r, userService, notifier)
+
+	recordErr := make(chan error, 1)
+	go func() {
+		_, err := s.RecordFailure(t.Context(), testEmail, testClientIP)
+		recordErr <- err
+	}()
+
+	// the failure is recorded while the notice is still being sent
+	select {
+	case err := <-recordErr:
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want the failure to be recorded before the notice is sent")
+	}
+
+	close(notifier.release)
+	s.Wait()
+
+	if notifier.sent != 1 {
+		t.Errorf("want 1 notice, got %d", notifier.sent)
+	}
+}
+
+func TestServiceRecordClientIPFailure(t *testing.T) {
+	now := time.Now().UTC()
+
+	r := &FakeRepository{
+		Failures: newTestFailures(int(ClientIPBlockThreshold)-1, "", testClientIP, now),
+	}
+	s := NewService(r, nil, nil)
+
+	if err := s.RecordClientIPFailure(t.Context(), " "); !errors.Is(err, ErrClientIPRequired) {
+		t.Errorf("want error %q, got %q", ErrClientIPRequired, err)
+	}
+
+	if err := s.RecordClientIPFailure(t.Context(), testClientIP); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	throttle, err := s.CheckClientIP(t.Context(), testClientIP)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if throttle.Reason != ReasonClientIPBlocked {
+		t.Errorf("want reason %q, got %q", ReasonClientIPBlocked, throttle.Reason)
+	}
+
+	// failures without an email address do not count towards any account
+	throttle, err = s.Check(t.Context(), testEmail, "198.51.100.1")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if throttle.Active(now) {
+		t.Errorf("want no throttle, got %#v", throttle)
+	}
+}
+
+func TestServiceReset(t *testing.T) {
+	now := time.Now().UTC()
+
+	otherFailures := newTestFailures(2, "john.doe@example.org", testClientIP, now)
+
+	r := &FakeRepository{
+		Failures: append(newTestFailures(4, testEmail, "198.51.100.7", now), otherFailures...),
+	}
+	s := NewService(r, nil, nil)
+
+	summary, err := s.Reset(t.Context(), "JANE.DOE@example.org")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	want := Summary{Count: 4, LastClientIP: "198.51.100.7", LastFailureAt: now}
+	if summary != want {
+		t.Errorf("want summary %#v, got %#v", want, summary)
+	}
+
+	if got := len(r.Failures); got != len(otherFailures) {
+		t.Errorf("want %d failures, got %d", len(otherFailures), got)
+	}
+}
+
+func TestServiceUnlock(t *testing.T) {
+	now := time.Now().UTC()
+
+	r := &FakeRepository{
+		Failures: newTestFailures(int(AccountLockoutThreshold), testEmail, testClientIP, now),
+	}
+	s := NewService(r, nil, nil)
+
+	if err := s.Unlock(t.Context(), ""); !errors.Is(err, ErrEmailRequired) {
+		t.Errorf("want error %q, got %q", ErrEmailRequired, err)
+	}
+
+	if err := s.Unlock(t.Context(), testEmail); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	throttle, err := s.Check(t.Context(), testEmail, testClientIP)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if throttle.Active(now) {
+		t.Errorf("want no throttle, got %#v", throttle)
+	}
+}
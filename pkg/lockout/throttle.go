@@ -0,0 +1,101 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package lockout
+
+import "time"
+
+const (
+	// AccountFailureWindow is the period over which failed attempts are counted for an
+	// account; older failures are purged.
+	AccountFailureWindow = 24 * time.Hour
+
+	// AccountDelayThreshold is the number of consecutive failures after which each new
+	// attempt for an account is delayed; the delay doubles with every failure.
+	AccountDelayThreshold uint = 3
+	AccountDelayBase           = 1 * time.Second
+	AccountDelayMax            = 1 * time.Minute
+
+	// AccountLockoutThreshold is the number of consecutive failures after which an account
+	// is temporarily locked.
+	AccountLockoutThreshold uint = 10
+	AccountLockoutDuration       = 15 * time.Minute
+
+	// ClientIPFailureWindow is the period over which failed attempts are counted for an
+	// IP address, regardless of the targeted accounts.
+	ClientIPFailureWindow = 1 * time.Hour
+
+	// ClientIPBlockThreshold is the number of failures after which an IP address is
+	// temporarily blocked.
+	ClientIPBlockThreshold uint = 50
+	ClientIPBlockDuration       = 15 * time.Minute
+)
+
+// Reason describes why login attempts are throttled.
+type Reason string
+
+const (
+	ReasonNone            Reason = ""
+	ReasonDelayed         Reason = "delayed"
+	ReasonAccountLocked   Reason = "account-locked"
+	ReasonClientIPBlocked Reason = "client-ip-blocked"
+)
+
+// Throttle describes a restriction on login attempts, in effect until a given time.
+type Throttle struct {
+	Reason Reason
+	Until  time.Time
+}
+
+// Active returns whether the Throttle is in effect at a given time.
+func (t Throttle) Active(now time.Time) bool {
+	return t.Reason != ReasonNone && now.Before(t.Until)
+}
+
+// RetryAfter returns how long to wait before attempting to log in again, rounded up to the
+// second.
+func (t Throttle) RetryAfter(now time.Time) time.Duration {
+	if !t.Active(now) {
+		return 0
+	}
+
+	retryAfter := t.Until.Sub(now)
+	if rounded := retryAfter.Truncate(time.Second); rounded < retryAfter {
+		return rounded + time.Second
+	}
+
+	return retryAfter
+}
+
+// accountThrottle returns the Throttle applying to an account, given its recent failures.
+func accountThrottle(s Summary) Throttle {
+	switch {
+	case s.Count >= AccountLockoutThreshold:
+		return Throttle{
+			Reason: ReasonAccountLocked,
+			Until:  s.LastFailureAt.Add(AccountLockoutDuration),
+		}
+
+	case s.Count >= AccountDelayThreshold:
+		delay := AccountDelayBase << (s.Count - AccountDelayThreshold)
+
+		return Throttle{
+			Reason: ReasonDelayed,
+			Until:  s.LastFailureAt.Add(min(delay, AccountDelayMax)),
+		}
+	}
+
+	return Throttle{}
+}
+
+// clientIPThrottle returns the Throttle applying to an IP address, given its recent failures.
+func clientIPThrottle(s Summary) Throttle {
+	if s.Count < ClientIPBlockThreshold {
+		return Throttle{}
+	}
+
+	return Throttle{
+		Reason: ReasonClientIPBlocked,
+		Until:  s.LastFailureAt.Add(ClientIPBlockDuration),
+	}
+}
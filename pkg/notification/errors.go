@@ -0,0 +1,16 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import "errors"
+
+var (
+	ErrBodyRequired      = errors.New("notification: body required")
+	ErrRecipientInvalid  = errors.New("notification: invalid recipient address")
+	ErrRecipientRequired = errors.New("notification: recipient required")
+	ErrSenderInvalid     = errors.New("notification: invalid sender address")
+	ErrSenderRequired    = errors.New("notification: sender required")
+	ErrSMTPAddrRequired  = errors.New("notification: SMTP server address required")
+	ErrSubjectRequired   = errors.New("notification: subject required")
+)
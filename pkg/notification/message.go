@@ -0,0 +1,72 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Message represents a plain-text notification sent to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (m *Message) Normalize() {
+	m.To = strings.TrimSpace(m.To)
+	m.Subject = strings.TrimSpace(m.Subject)
+}
+
+// Validate ensures mandatory fields are set.
+func (m *Message) Validate() error {
+	fns := []func() error{
+		m.requireTo,
+		m.ensureToIsValid,
+		m.requireSubject,
+		m.requireBody,
+	}
+
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Message) requireTo() error {
+	if m.To == "" {
+		return ErrRecipientRequired
+	}
+
+	return nil
+}
+
+func (m *Message) ensureToIsValid() error {
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return ErrRecipientInvalid
+	}
+
+	return nil
+}
+
+func (m *Message) requireSubject() error {
+	if m.Subject == "" {
+		return ErrSubjectRequired
+	}
+
+	return nil
+}
+
+func (m *Message) requireBody() error {
+	if m.Body == "" {
+		return ErrBodyRequired
+	}
+
+	return nil
+}
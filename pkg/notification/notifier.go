@@ -0,0 +1,12 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import "context"
+
+// Notifier sends notification messages to users.
+type Notifier interface {
+	// Notify sends a Message to its recipient.
+	Notify(ctx context.Context, message Message) error
+}
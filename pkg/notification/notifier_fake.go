@@ -0,0 +1,32 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import (
+	"context"
+	"sync"
+)
+
+var _ Notifier = &FakeNotifier{}
+
+// FakeNotifier records sent messages in memory.
+type FakeNotifier struct {
+	mu       sync.Mutex
+	Messages []Message
+
+	NotifyErr error
+}
+
+func (n *FakeNotifier) Notify(_ context.Context, message Message) error {
+	if n.NotifyErr != nil {
+		return n.NotifyErr
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.Messages = append(n.Messages, message)
+
+	return nil
+}
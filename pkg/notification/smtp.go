@@ -0,0 +1,216 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+)
+
+var _ Notifier = &SMTPNotifier{}
+
+// SMTPConfig holds the settings used to reach an SMTP server.
+type SMTPConfig struct {
+	// Addr is the address of the SMTP server (host:port).
+	Addr string
+
+	// Username and Password are used for PLAIN authentication; authentication is skipped if Username is empty.
+	Username string
+	Password string
+
+	// From is the sender address, e.g. "SparkleMuffin <sparklemuffin@example.org>".
+	From string
+
+	// ImplicitTLS requests a TLS connection from the start (usually on port 465), instead of upgrading
+	// the connection with STARTTLS when the server supports it.
+	ImplicitTLS bool
+}
+
+// SMTPNotifier sends notifications by email.
+type SMTPNotifier struct {
+	config SMTPConfig
+	host   string
+	from   *mail.Address
+}
+
+// NewSMTPNotifier initializes and returns a new SMTPNotifier.
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	if config.Addr == "" {
+		return nil, ErrSMTPAddrRequired
+	}
+
+	host, _, err := net.SplitHostPort(config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("notification: invalid SMTP server address: %w", err)
+	}
+
+	if config.From == "" {
+		return nil, ErrSenderRequired
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, ErrSenderInvalid
+	}
+
+	return &SMTPNotifier{
+		config: config,
+		host:   host,
+		from:   from,
+	}, nil
+}
+
+// Notify sends a Message by email.
+func (n *SMTPNotifier) Notify(ctx context.Context, message Message) error {
+	message.Normalize()
+
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return ErrRecipientInvalid
+	}
+
+	data, err := n.encode(to, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if !n.config.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(n.tlsConfig()); err != nil {
+				return fmt.Errorf("notification: STARTTLS failed: %w", err)
+			}
+		}
+	}
+
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.host)); err != nil {
+			return fmt.Errorf("notification: SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.from.Address); err != nil {
+		return fmt.Errorf("notification: SMTP MAIL command failed: %w", err)
+	}
+
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("notification: SMTP RCPT command failed: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("notification: SMTP DATA command failed: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("notification: failed to write message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("notification: failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) dial(ctx context.Context) (*smtp.Client, error) {
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if n.config.ImplicitTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: n.tlsConfig()}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", n.config.Addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", n.config.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to connect to SMTP server: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("notification: failed to greet SMTP server: %w", err)
+	}
+
+	return client, nil
+}
+
+func (n *SMTPNotifier) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName: n.host,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// encode formats a Message as a MIME email with a quoted-printable plain text body.
+func (n *SMTPNotifier) encode(to *mail.Address, message Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	domain := n.from.Address[strings.LastIndex(n.from.Address, "@")+1:]
+
+	headers := [][2]string{
+		{"From", n.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", ksuid.New().String(), domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	body := strings.ReplaceAll(message.Body, "\r\n", "\n")
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
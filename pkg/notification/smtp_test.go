@@ -0,0 +1,227 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package notification
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpStandIn is a minimal SMTP server accepting a single message.
+type smtpStandIn struct {
+	listener net.Listener
+
+	from string
+	to   string
+	data chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %q", err)
+	}
+
+	s := &smtpStandIn{
+		listener: listener,
+		data:     make(chan string, 1),
+	}
+
+	go s.serve()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return s
+}
+
+func (s *smtpStandIn) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "MAIL":
+			s.from = arg
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			s.to = arg
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 Go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data <- string(data)
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifierNotify(t *testing.T) {
+	standIn := newSMTPStandIn(t)
+
+	notifier, err := NewSMTPNotifier(SMTPConfig{
+		Addr: standIn.listener.Addr().String(),
+		From: "SparkleMuffin <sparklemuffin@example.org>",
+	})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %q", err)
+	}
+
+	message := Message{
+		To:      "jane.doe@example.org",
+		Subject: "Your daily digest – 3 unread entries",
+		Body:    "Hello Jane,\n\nHere are your unread entries.\n",
+	}
+
+	if err := notifier.Notify(t.Context(), message); err != nil {
+		t.Fatalf("failed to send message: %q", err)
+	}
+
+	data := <-standIn.data
+
+	if standIn.from != "FROM:<sparklemuffin@example.org>" {
+		t.Errorf("want MAIL FROM sparklemuffin@example.org, got %q", standIn.from)
+	}
+	if standIn.to != "TO:<jane.doe@example.org>" {
+		t.Errorf("want RCPT TO jane.doe@example.org, got %q", standIn.to)
+	}
+
+	got, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %q", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(got.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %q", err)
+	}
+	if subject != message.Subject {
+		t.Errorf("want subject %q, got %q", message.Subject, subject)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(bufio.NewReader(got.Body)))
+	if err != nil {
+		t.Fatalf("failed to decode body: %q", err)
+	}
+	if string(body) != message.Body {
+		t.Errorf("want body %q, got %q", message.Body, string(body))
+	}
+}
+
+func TestSMTPNotifierNotifyInvalidMessage(t *testing.T) {
+	notifier, err := NewSMTPNotifier(SMTPConfig{
+		Addr: "127.0.0.1:25",
+		From: "sparklemuffin@example.org",
+	})
+	if err != nil {
+		t.Fatalf("failed to create notifier: %q", err)
+	}
+
+	cases := []struct {
+		tname   string
+		message Message
+		wantErr error
+	}{
+		{
+			tname:   "missing recipient",
+			message: Message{Subject: "Digest", Body: "Hello"},
+			wantErr: ErrRecipientRequired,
+		},
+		{
+			tname:   "invalid recipient",
+			message: Message{To: "jane.doe", Subject: "Digest", Body: "Hello"},
+			wantErr: ErrRecipientInvalid,
+		},
+		{
+			tname:   "missing subject",
+			message: Message{To: "jane.doe@example.org", Body: "Hello"},
+			wantErr: ErrSubjectRequired,
+		},
+		{
+			tname:   "missing body",
+			message: Message{To: "jane.doe@example.org", Subject: "Digest"},
+			wantErr: ErrBodyRequired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			err := notifier.Notify(t.Context(), tc.message)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("want error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewSMTPNotifier(t *testing.T) {
+	cases := []struct {
+		tname   string
+		config  SMTPConfig
+		wantErr error
+	}{
+		{
+			tname:   "missing address",
+			config:  SMTPConfig{From: "sparklemuffin@example.org"},
+			wantErr: ErrSMTPAddrRequired,
+		},
+		{
+			tname:   "missing sender",
+			config:  SMTPConfig{Addr: "localhost:25"},
+			wantErr: ErrSenderRequired,
+		},
+		{
+			tname:   "invalid sender",
+			config:  SMTPConfig{Addr: "localhost:25", From: "sparklemuffin"},
+			wantErr: ErrSenderInvalid,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			_, err := NewSMTPNotifier(tc.config)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("want error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	// CeremonyTTL is the time a user has to complete a registration or login ceremony
+	// with their authenticator.
+	CeremonyTTL = 5 * time.Minute
+
+	ceremonyTokenNBytes = 32
+)
+
+// Ceremony represents a pending WebAuthn registration or login ceremony.
+//
+// The challenge sent to the authenticator is kept server-side, so that each ceremony
+// can only be completed once.
+type Ceremony struct {
+	// TokenHash is the HMAC hash of the clear-text token identifying the ceremony on
+	// the client side, used to look it up.
+	TokenHash string
+
+	SessionData webauthn.SessionData
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,21 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import "errors"
+
+var (
+	ErrAlreadyRegistered   = errors.New("passkey: already registered")
+	ErrCeremonyInvalid     = errors.New("passkey: invalid or expired ceremony")
+	ErrCredentialInvalid   = errors.New("passkey: invalid credential")
+	ErrHmacKeyRequired     = errors.New("passkey: hmac key is required")
+	ErrNameRequired        = errors.New("passkey: name required")
+	ErrNameTooLong         = errors.New("passkey: name is too long")
+	ErrNotFound            = errors.New("passkey: not found")
+	ErrPublicURLRequired   = errors.New("passkey: public URL is required")
+	ErrUserServiceRequired = errors.New("passkey: user service is required")
+	ErrUserUUIDRequired    = errors.New("passkey: user UUID required")
+	ErrUUIDInvalid         = errors.New("passkey: invalid UUID")
+	ErrUUIDRequired        = errors.New("passkey: UUID required")
+)
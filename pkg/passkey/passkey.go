@@ -0,0 +1,192 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	// NameMaxLength is the maximum number of characters of a Passkey name.
+	NameMaxLength = 64
+)
+
+// Passkey represents a WebAuthn public key credential registered by a user, that
+// can be used to log in without a password.
+type Passkey struct {
+	UUID     string
+	UserUUID string
+
+	// Name helps users tell their authenticators apart.
+	Name string
+
+	// Credential holds the public key, signature counter and flags of the credential,
+	// as returned by the authenticator.
+	Credential webauthn.Credential
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// newPasskey initializes and returns a new Passkey with a random UUID.
+func newPasskey(userUUID string, name string, credential webauthn.Credential) (Passkey, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	now := time.Now().UTC()
+
+	return Passkey{
+		UUID:       generatedUUID.String(),
+		UserUUID:   userUUID,
+		Name:       name,
+		Credential: credential,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (p *Passkey) Normalize() {
+	p.Name = normalizeName(p.Name)
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when adding a Passkey.
+func (p *Passkey) ValidateForAddition() error {
+	fns := []func() error{
+		p.requireUUID,
+		p.requireUserUUID,
+		p.requireName,
+		p.ensureNameIsNotTooLong,
+		p.requireCredential,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+func (p *Passkey) ensureNameIsNotTooLong() error {
+	return ensureNameIsNotTooLong(p.Name)
+}
+
+func (p *Passkey) requireCredential() error {
+	if len(p.Credential.ID) == 0 || len(p.Credential.PublicKey) == 0 {
+		return ErrCredentialInvalid
+	}
+	return nil
+}
+
+func (p *Passkey) requireName() error {
+	if p.Name == "" {
+		return ErrNameRequired
+	}
+	return nil
+}
+
+func (p *Passkey) requireUserUUID() error {
+	if p.UserUUID == "" {
+		return ErrUserUUIDRequired
+	}
+	return nil
+}
+
+func (p *Passkey) requireUUID() error {
+	if p.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
+
+// NameUpdate represents a name change for a Passkey.
+type NameUpdate struct {
+	UUID      string
+	UserUUID  string
+	Name      string
+	UpdatedAt time.Time
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (u *NameUpdate) Normalize() {
+	u.Name = normalizeName(u.Name)
+}
+
+// ValidateForUpdate ensures mandatory fields are properly set when renaming a Passkey.
+func (u *NameUpdate) ValidateForUpdate() error {
+	fns := []func() error{
+		u.requireUUID,
+		u.validateUUID,
+		u.requireUserUUID,
+		u.requireName,
+		u.ensureNameIsNotTooLong,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+func (u *NameUpdate) ensureNameIsNotTooLong() error {
+	return ensureNameIsNotTooLong(u.Name)
+}
+
+func (u *NameUpdate) requireName() error {
+	if u.Name == "" {
+		return ErrNameRequired
+	}
+	return nil
+}
+
+func (u *NameUpdate) requireUserUUID() error {
+	if u.UserUUID == "" {
+		return ErrUserUUIDRequired
+	}
+	return nil
+}
+
+func (u *NameUpdate) requireUUID() error {
+	if u.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
+
+func (u *NameUpdate) validateUUID() error {
+	if err := uuid.Validate(u.UUID); err != nil {
+		return ErrUUIDInvalid
+	}
+	return nil
+}
+
+// CredentialUpdate represents the state of a Passkey credential after a successful login,
+// including its updated signature counter.
+type CredentialUpdate struct {
+	UUID       string
+	Credential webauthn.Credential
+	LastUsedAt time.Time
+}
+
+func ensureNameIsNotTooLong(name string) error {
+	if utf8.RuneCountInString(name) > NameMaxLength {
+		return ErrNameTooLong
+	}
+	return nil
+}
+
+func normalizeName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+func runValidationFuncs(fns []func() error) error {
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
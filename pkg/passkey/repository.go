@@ -0,0 +1,42 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import "context"
+
+// Repository provides access to user passkeys and pending WebAuthn ceremonies.
+type Repository interface {
+	// PasskeyAdd saves a new Passkey.
+	//
+	// It returns ErrAlreadyRegistered if a Passkey with the same credential ID exists.
+	PasskeyAdd(ctx context.Context, p Passkey) error
+
+	// PasskeyDelete deletes a given Passkey.
+	//
+	// It returns ErrNotFound if the Passkey does not exist or belongs to another user.
+	PasskeyDelete(ctx context.Context, userUUID string, passkeyUUID string) error
+
+	// PasskeyGetByCredentialID returns the Passkey for a given WebAuthn credential ID.
+	PasskeyGetByCredentialID(ctx context.Context, credentialID []byte) (Passkey, error)
+
+	// PasskeyGetByUserUUID returns all passkeys registered by a given user.
+	PasskeyGetByUserUUID(ctx context.Context, userUUID string) ([]Passkey, error)
+
+	// PasskeyUpdateCredential saves the state of a Passkey credential after a successful login.
+	PasskeyUpdateCredential(ctx context.Context, update CredentialUpdate) error
+
+	// PasskeyUpdateName renames a given Passkey.
+	//
+	// It returns ErrNotFound if the Passkey does not exist or belongs to another user.
+	PasskeyUpdateName(ctx context.Context, update NameUpdate) error
+
+	// PasskeyCeremonyAdd saves a pending Ceremony, and deletes expired ceremonies.
+	PasskeyCeremonyAdd(ctx context.Context, c Ceremony) error
+
+	// PasskeyCeremonyConsume deletes and returns a pending Ceremony.
+	//
+	// It returns ErrNotFound if the ceremony does not exist, has already been consumed or
+	// has expired.
+	PasskeyCeremonyConsume(ctx context.Context, tokenHash string) (Ceremony, error)
+}
@@ -0,0 +1,119 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"time"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Passkeys   []Passkey
+	Ceremonies []Ceremony
+}
+
+func (r *FakeRepository) PasskeyAdd(_ context.Context, p Passkey) error {
+	for _, existing := range r.Passkeys {
+		if bytes.Equal(existing.Credential.ID, p.Credential.ID) {
+			return ErrAlreadyRegistered
+		}
+	}
+
+	r.Passkeys = append(r.Passkeys, p)
+
+	return nil
+}
+
+func (r *FakeRepository) PasskeyDelete(_ context.Context, userUUID string, passkeyUUID string) error {
+	for i, p := range r.Passkeys {
+		if p.UserUUID == userUUID && p.UUID == passkeyUUID {
+			r.Passkeys = slices.Delete(r.Passkeys, i, i+1)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) PasskeyGetByCredentialID(_ context.Context, credentialID []byte) (Passkey, error) {
+	for _, p := range r.Passkeys {
+		if bytes.Equal(p.Credential.ID, credentialID) {
+			return p, nil
+		}
+	}
+
+	return Passkey{}, ErrNotFound
+}
+
+func (r *FakeRepository) PasskeyGetByUserUUID(_ context.Context, userUUID string) ([]Passkey, error) {
+	passkeys := []Passkey{}
+
+	for _, p := range r.Passkeys {
+		if p.UserUUID == userUUID {
+			passkeys = append(passkeys, p)
+		}
+	}
+
+	return passkeys, nil
+}
+
+func (r *FakeRepository) PasskeyUpdateCredential(_ context.Context, update CredentialUpdate) error {
+	for i, p := range r.Passkeys {
+		if p.UUID == update.UUID {
+			r.Passkeys[i].Credential = update.Credential
+			r.Passkeys[i].LastUsedAt = update.LastUsedAt
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) PasskeyUpdateName(_ context.Context, update NameUpdate) error {
+	for i, p := range r.Passkeys {
+		if p.UserUUID == update.UserUUID && p.UUID == update.UUID {
+			r.Passkeys[i].Name = update.Name
+			r.Passkeys[i].UpdatedAt = update.UpdatedAt
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) PasskeyCeremonyAdd(_ context.Context, c Ceremony) error {
+	now := time.Now().UTC()
+
+	r.Ceremonies = slices.DeleteFunc(r.Ceremonies, func(existing Ceremony) bool {
+		return existing.ExpiresAt.Before(now)
+	})
+	r.Ceremonies = append(r.Ceremonies, c)
+
+	return nil
+}
+
+func (r *FakeRepository) PasskeyCeremonyConsume(_ context.Context, tokenHash string) (Ceremony, error) {
+	now := time.Now().UTC()
+
+	for i, c := range r.Ceremonies {
+		if c.TokenHash != tokenHash {
+			continue
+		}
+
+		r.Ceremonies = slices.Delete(r.Ceremonies, i, i+1)
+
+		if c.ExpiresAt.Before(now) {
+			return Ceremony{}, ErrNotFound
+		}
+
+		return c, nil
+	}
+
+	return Ceremony{}, ErrNotFound
+}
@@ -0,0 +1,349 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	// RelyingPartyDisplayName is the name of the application, as displayed by authenticators.
+	RelyingPartyDisplayName = "SparkleMuffin"
+)
+
+// Service handles WebAuthn passkey registration and login ceremonies.
+type Service struct {
+	r           Repository
+	userService *user.Service
+	hmac        *hash.HMAC
+	webAuthn    *webauthn.WebAuthn
+}
+
+// NewService initializes and returns a passkey Service.
+//
+// The WebAuthn Relying Party is identified by the host name of publicURL, and
+// authenticator responses are only accepted from its origin.
+func NewService(r Repository, userService *user.Service, publicURL *url.URL, hmacKey string) (*Service, error) {
+	if userService == nil {
+		return &Service{}, ErrUserServiceRequired
+	}
+	if publicURL == nil || publicURL.Hostname() == "" {
+		return &Service{}, ErrPublicURLRequired
+	}
+	if hmacKey == "" {
+		return &Service{}, ErrHmacKeyRequired
+	}
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce: true,
+		Timeout: CeremonyTTL,
+	}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          publicURL.Hostname(),
+		RPDisplayName: RelyingPartyDisplayName,
+		RPOrigins:     []string{publicURL.Scheme + "://" + publicURL.Host},
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return &Service{}, err
+	}
+
+	return &Service{
+		r:           r,
+		userService: userService,
+		hmac:        hash.NewHMAC(hmacKey),
+		webAuthn:    webAuthn,
+	}, nil
+}
+
+// ByUserUUID returns all passkeys registered by a given user.
+func (s *Service) ByUserUUID(ctx context.Context, userUUID string) ([]Passkey, error) {
+	if userUUID == "" {
+		return []Passkey{}, ErrUserUUIDRequired
+	}
+
+	return s.r.PasskeyGetByUserUUID(ctx, userUUID)
+}
+
+// ByUUID returns a given Passkey registered by a given user.
+func (s *Service) ByUUID(ctx context.Context, userUUID string, passkeyUUID string) (Passkey, error) {
+	passkeys, err := s.ByUserUUID(ctx, userUUID)
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	for _, p := range passkeys {
+		if p.UUID == passkeyUUID {
+			return p, nil
+		}
+	}
+
+	return Passkey{}, ErrNotFound
+}
+
+// BeginRegistration starts the registration of a new passkey for a given user.
+//
+// It returns the options to pass to the authenticator, and the token identifying the
+// registration ceremony, that must be provided to FinishRegistration.
+func (s *Service) BeginRegistration(ctx context.Context, u user.User) (*protocol.CredentialCreation, string, error) {
+	if u.UUID == "" {
+		return nil, "", ErrUserUUIDRequired
+	}
+
+	passkeys, err := s.ByUserUUID(ctx, u.UUID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	waUser := &webAuthnUser{user: u, passkeys: passkeys}
+
+	// Prevent registering the same authenticator twice.
+	exclusions := webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()
+
+	creation, sessionData, err := s.webAuthn.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.addCeremony(ctx, *sessionData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return creation, token, nil
+}
+
+// FinishRegistration verifies the response of the authenticator to a registration ceremony
+// started with BeginRegistration, and saves the corresponding Passkey.
+func (s *Service) FinishRegistration(ctx context.Context, u user.User, ceremonyToken string, name string, response io.Reader) (Passkey, error) {
+	if u.UUID == "" {
+		return Passkey{}, ErrUserUUIDRequired
+	}
+
+	ceremony, err := s.consumeCeremony(ctx, ceremonyToken)
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	parsedResponse, err := protocol.ParseCredentialCreationResponseBody(response)
+	if err != nil {
+		return Passkey{}, fmt.Errorf("%w: %w", ErrCredentialInvalid, err)
+	}
+
+	passkeys, err := s.ByUserUUID(ctx, u.UUID)
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	waUser := &webAuthnUser{user: u, passkeys: passkeys}
+
+	credential, err := s.webAuthn.CreateCredential(waUser, ceremony.SessionData, parsedResponse)
+	if err != nil {
+		return Passkey{}, fmt.Errorf("%w: %w", ErrCredentialInvalid, err)
+	}
+
+	p, err := newPasskey(u.UUID, name, *credential)
+	if err != nil {
+		return Passkey{}, err
+	}
+
+	p.Normalize()
+
+	if err := p.ValidateForAddition(); err != nil {
+		return Passkey{}, err
+	}
+
+	if err := s.r.PasskeyAdd(ctx, p); err != nil {
+		return Passkey{}, err
+	}
+
+	return p, nil
+}
+
+// BeginLogin starts a passkey login ceremony.
+//
+// The user is not known beforehand: they are identified by the passkey they choose.
+//
+// It returns the options to pass to the authenticator, and the token identifying the
+// login ceremony, that must be provided to FinishLogin.
+func (s *Service) BeginLogin(ctx context.Context) (*protocol.CredentialAssertion, string, error) {
+	assertion, sessionData, err := s.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.addCeremony(ctx, *sessionData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return assertion, token, nil
+}
+
+// FinishLogin verifies the response of the authenticator to a login ceremony started
+// with BeginLogin, and returns the user owning the passkey.
+//
+// If the response is invalid for a registered passkey, its owner is returned along with
+// the error, so that the failed attempt can be accounted for.
+func (s *Service) FinishLogin(ctx context.Context, ceremonyToken string, response io.Reader) (user.User, error) {
+	ceremony, err := s.consumeCeremony(ctx, ceremonyToken)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBody(response)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%w: %w", ErrCredentialInvalid, err)
+	}
+
+	var (
+		p Passkey
+		u user.User
+	)
+
+	userHandler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		p, err = s.r.PasskeyGetByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.UserUUID != string(userHandle) {
+			return nil, ErrCredentialInvalid
+		}
+
+		u, err = s.userService.ByUUID(ctx, p.UserUUID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &webAuthnUser{user: u, passkeys: []Passkey{p}}, nil
+	}
+
+	_, credential, err := s.webAuthn.ValidatePasskeyLogin(userHandler, ceremony.SessionData, parsedResponse)
+	if err != nil {
+		return u, fmt.Errorf("%w: %w", ErrCredentialInvalid, err)
+	}
+
+	// The signature counter went backwards: the authenticator may have been cloned.
+	if credential.Authenticator.CloneWarning {
+		return u, fmt.Errorf("%w: signature counter mismatch", ErrCredentialInvalid)
+	}
+
+	update := CredentialUpdate{
+		UUID:       p.UUID,
+		Credential: *credential,
+		LastUsedAt: time.Now().UTC(),
+	}
+
+	if err := s.r.PasskeyUpdateCredential(ctx, update); err != nil {
+		return user.User{}, err
+	}
+
+	return u, nil
+}
+
+// Rename changes the name of a given Passkey.
+func (s *Service) Rename(ctx context.Context, update NameUpdate) error {
+	update.Normalize()
+	update.UpdatedAt = time.Now().UTC()
+
+	if err := update.ValidateForUpdate(); err != nil {
+		return err
+	}
+
+	return s.r.PasskeyUpdateName(ctx, update)
+}
+
+// Delete revokes a given Passkey, which can no longer be used to log in.
+func (s *Service) Delete(ctx context.Context, userUUID string, passkeyUUID string) error {
+	if userUUID == "" {
+		return ErrUserUUIDRequired
+	}
+	if passkeyUUID == "" {
+		return ErrUUIDRequired
+	}
+	if err := uuid.Validate(passkeyUUID); err != nil {
+		return ErrUUIDInvalid
+	}
+
+	return s.r.PasskeyDelete(ctx, userUUID, passkeyUUID)
+}
+
+// addCeremony saves the state of a pending ceremony, and returns the token identifying it.
+func (s *Service) addCeremony(ctx context.Context, sessionData webauthn.SessionData) (string, error) {
+	token, err := rand.RandomBase64URLString(ceremonyTokenNBytes)
+	if err != nil {
+		return "", err
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+
+	expiresAt := sessionData.Expires
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(CeremonyTTL)
+	}
+
+	ceremony := Ceremony{
+		TokenHash:   tokenHash,
+		SessionData: sessionData,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+	}
+
+	if err := s.r.PasskeyCeremonyAdd(ctx, ceremony); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeCeremony returns the state of a pending ceremony, which can only be used once.
+func (s *Service) consumeCeremony(ctx context.Context, token string) (Ceremony, error) {
+	if token == "" {
+		return Ceremony{}, ErrCeremonyInvalid
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return Ceremony{}, err
+	}
+
+	ceremony, err := s.r.PasskeyCeremonyConsume(ctx, tokenHash)
+	if errors.Is(err, ErrNotFound) {
+		return Ceremony{}, ErrCeremonyInvalid
+	}
+	if err != nil {
+		return Ceremony{}, err
+	}
+
+	return ceremony, nil
+}
@@ -0,0 +1,374 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/test/webauthntest"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testHmacKey = "test-hmac-key"
+	testOrigin  = "https://sparklemuffin.test"
+)
+
+var (
+	testUser = user.User{
+		UUID:        "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:       "jane.doe@example.org",
+		NickName:    "jane-doe",
+		DisplayName: "Jane Doe",
+	}
+)
+
+func newTestService(t *testing.T) (*Service, *FakeRepository) {
+	t.Helper()
+
+	r := &FakeRepository{}
+	userService := user.NewService(&user.FakeRepository{Users: []user.User{testUser}}, user.FakePasswordHasher(t))
+
+	publicURL, err := url.Parse(testOrigin)
+	if err != nil {
+		t.Fatalf("failed to parse public URL: %q", err)
+	}
+
+	s, err := NewService(r, userService, publicURL, testHmacKey)
+	if err != nil {
+		t.Fatalf("failed to create service: %q", err)
+	}
+
+	return s, r
+}
+
+func mustMarshalJSON(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode JSON: %q", err)
+	}
+
+	return b
+}
+
+// register registers a new passkey for testUser with a software authenticator.
+func register(t *testing.T, s *Service, authenticator *webauthntest.Authenticator, name string) Passkey {
+	t.Helper()
+
+	creation, token, err := s.BeginRegistration(t.Context(), testUser)
+	if err != nil {
+		t.Fatalf("failed to begin registration: %q", err)
+	}
+
+	response := authenticator.Create(t, mustMarshalJSON(t, creation))
+
+	p, err := s.FinishRegistration(t.Context(), testUser, token, name, bytes.NewReader(response))
+	if err != nil {
+		t.Fatalf("failed to finish registration: %q", err)
+	}
+
+	return p
+}
+
+// login performs a passkey login ceremony with a software authenticator.
+func login(t *testing.T, s *Service, authenticator *webauthntest.Authenticator) (user.User, error) {
+	t.Helper()
+
+	assertion, token, err := s.BeginLogin(t.Context())
+	if err != nil {
+		t.Fatalf("failed to begin login: %q", err)
+	}
+
+	response := authenticator.Get(t, mustMarshalJSON(t, assertion))
+
+	return s.FinishLogin(t.Context(), token, bytes.NewReader(response))
+}
+
+func TestNewService(t *testing.T) {
+	publicURL := &url.URL{Scheme: "https", Host: "sparklemuffin.test"}
+	userService := user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t))
+
+	cases := []struct {
+		tname       string
+		userService *user.Service
+		publicURL   *url.URL
+		hmacKey     string
+		wantErr     error
+	}{
+		{
+			tname:     "missing user service",
+			publicURL: publicURL,
+			hmacKey:   testHmacKey,
+			wantErr:   ErrUserServiceRequired,
+		},
+		{
+			tname:       "missing public URL",
+			userService: userService,
+			hmacKey:     testHmacKey,
+			wantErr:     ErrPublicURLRequired,
+		},
+		{
+			tname:       "missing HMAC key",
+			userService: userService,
+			publicURL:   publicURL,
+			wantErr:     ErrHmacKeyRequired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			_, err := NewService(&FakeRepository{}, tc.userService, tc.publicURL, tc.hmacKey)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("want %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServiceRegistration(t *testing.T) {
+	t.Run("register multiple authenticators", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		laptop := register(t, s, webauthntest.NewAuthenticator(testOrigin), "  Laptop  ")
+		phone := register(t, s, webauthntest.NewAuthenticator(testOrigin), "Phone")
+
+		if laptop.Name != "Laptop" {
+			t.Errorf("want name %q, got %q", "Laptop", laptop.Name)
+		}
+
+		passkeys, err := s.ByUserUUID(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(passkeys) != 2 {
+			t.Fatalf("want 2 passkeys, got %d", len(passkeys))
+		}
+		if passkeys[0].UUID != laptop.UUID || passkeys[1].UUID != phone.UUID {
+			t.Error("unexpected passkeys")
+		}
+	})
+
+	t.Run("name is required", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		creation, token, err := s.BeginRegistration(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		response := webauthntest.NewAuthenticator(testOrigin).Create(t, mustMarshalJSON(t, creation))
+
+		_, err = s.FinishRegistration(t.Context(), testUser, token, " ", bytes.NewReader(response))
+		if !errors.Is(err, ErrNameRequired) {
+			t.Errorf("want %q, got %q", ErrNameRequired, err)
+		}
+	})
+
+	t.Run("ceremony can only be used once", func(t *testing.T) {
+		s, _ := newTestService(t)
+		authenticator := webauthntest.NewAuthenticator(testOrigin)
+
+		creation, token, err := s.BeginRegistration(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		response := authenticator.Create(t, mustMarshalJSON(t, creation))
+
+		if _, err := s.FinishRegistration(t.Context(), testUser, token, "Laptop", bytes.NewReader(response)); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		_, err = s.FinishRegistration(t.Context(), testUser, token, "Laptop", bytes.NewReader(response))
+		if !errors.Is(err, ErrCeremonyInvalid) {
+			t.Errorf("want %q, got %q", ErrCeremonyInvalid, err)
+		}
+	})
+
+	t.Run("origin mismatch", func(t *testing.T) {
+		s, r := newTestService(t)
+
+		creation, token, err := s.BeginRegistration(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		response := webauthntest.NewAuthenticator("https://phishing.test").Create(t, mustMarshalJSON(t, creation))
+
+		_, err = s.FinishRegistration(t.Context(), testUser, token, "Laptop", bytes.NewReader(response))
+		if !errors.Is(err, ErrCredentialInvalid) {
+			t.Errorf("want %q, got %q", ErrCredentialInvalid, err)
+		}
+
+		if len(r.Passkeys) != 0 {
+			t.Errorf("want no passkey to be saved, got %d", len(r.Passkeys))
+		}
+	})
+
+	t.Run("ceremony started by another user", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		otherUser := user.User{UUID: "0b7e2c4a-5d6f-4a8b-9c1d-2e3f4a5b6c7d"}
+
+		creation, token, err := s.BeginRegistration(t.Context(), otherUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		response := webauthntest.NewAuthenticator(testOrigin).Create(t, mustMarshalJSON(t, creation))
+
+		_, err = s.FinishRegistration(t.Context(), testUser, token, "Laptop", bytes.NewReader(response))
+		if !errors.Is(err, ErrCredentialInvalid) {
+			t.Errorf("want %q, got %q", ErrCredentialInvalid, err)
+		}
+	})
+}
+
+func TestServiceLogin(t *testing.T) {
+	t.Run("login with a registered passkey", func(t *testing.T) {
+		s, r := newTestService(t)
+		authenticator := webauthntest.NewAuthenticator(testOrigin)
+
+		register(t, s, authenticator, "Laptop")
+
+		for range 2 {
+			got, err := login(t, s, authenticator)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.UUID != testUser.UUID {
+				t.Errorf("want user %q, got %q", testUser.UUID, got.UUID)
+			}
+		}
+
+		if r.Passkeys[0].LastUsedAt.IsZero() {
+			t.Error("want last usage date to be set")
+		}
+		if r.Passkeys[0].Credential.Authenticator.SignCount != 2 {
+			t.Errorf("want sign count 2, got %d", r.Passkeys[0].Credential.Authenticator.SignCount)
+		}
+	})
+
+	t.Run("unknown passkey", func(t *testing.T) {
+		s, _ := newTestService(t)
+		authenticator := webauthntest.NewAuthenticator(testOrigin)
+
+		p := register(t, s, authenticator, "Laptop")
+
+		if err := s.Delete(t.Context(), testUser.UUID, p.UUID); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := login(t, s, authenticator)
+		if !errors.Is(err, ErrCredentialInvalid) {
+			t.Errorf("want %q, got %q", ErrCredentialInvalid, err)
+		}
+
+		if got.UUID != "" {
+			t.Errorf("want no user, got %q", got.UUID)
+		}
+	})
+
+	t.Run("cloned authenticator", func(t *testing.T) {
+		s, _ := newTestService(t)
+		authenticator := webauthntest.NewAuthenticator(testOrigin)
+
+		register(t, s, authenticator, "Laptop")
+		clone := authenticator.Clone()
+
+		if _, err := login(t, s, authenticator); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := login(t, s, clone)
+		if !errors.Is(err, ErrCredentialInvalid) {
+			t.Errorf("want %q, got %q", ErrCredentialInvalid, err)
+		}
+
+		if got.UUID != testUser.UUID {
+			t.Errorf("want the passkey owner %q, got %q", testUser.UUID, got.UUID)
+		}
+	})
+
+	t.Run("registration ceremony", func(t *testing.T) {
+		s, _ := newTestService(t)
+		authenticator := webauthntest.NewAuthenticator(testOrigin)
+
+		register(t, s, authenticator, "Laptop")
+
+		_, registrationToken, err := s.BeginRegistration(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("failed to begin registration: %q", err)
+		}
+
+		assertion, _, err := s.BeginLogin(t.Context())
+		if err != nil {
+			t.Fatalf("failed to begin login: %q", err)
+		}
+
+		response := authenticator.Get(t, mustMarshalJSON(t, assertion))
+
+		_, err = s.FinishLogin(t.Context(), registrationToken, bytes.NewReader(response))
+		if !errors.Is(err, ErrCredentialInvalid) {
+			t.Errorf("want %q, got %q", ErrCredentialInvalid, err)
+		}
+	})
+
+	t.Run("invalid ceremony", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		_, err := s.FinishLogin(t.Context(), "", bytes.NewReader([]byte("{}")))
+		if !errors.Is(err, ErrCeremonyInvalid) {
+			t.Errorf("want %q, got %q", ErrCeremonyInvalid, err)
+		}
+	})
+}
+
+func TestServiceRenameAndDelete(t *testing.T) {
+	s, r := newTestService(t)
+	p := register(t, s, webauthntest.NewAuthenticator(testOrigin), "Laptop")
+
+	t.Run("rename", func(t *testing.T) {
+		err := s.Rename(t.Context(), NameUpdate{UUID: p.UUID, UserUUID: testUser.UUID, Name: "Work laptop"})
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if r.Passkeys[0].Name != "Work laptop" {
+			t.Errorf("want name %q, got %q", "Work laptop", r.Passkeys[0].Name)
+		}
+	})
+
+	t.Run("rename with an empty name", func(t *testing.T) {
+		err := s.Rename(t.Context(), NameUpdate{UUID: p.UUID, UserUUID: testUser.UUID, Name: "  "})
+		if !errors.Is(err, ErrNameRequired) {
+			t.Errorf("want %q, got %q", ErrNameRequired, err)
+		}
+	})
+
+	t.Run("delete another user's passkey", func(t *testing.T) {
+		err := s.Delete(t.Context(), "0b7e2c4a-5d6f-4a8b-9c1d-2e3f4a5b6c7d", p.UUID)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("want %q, got %q", ErrNotFound, err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := s.Delete(t.Context(), testUser.UUID, p.UUID); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(r.Passkeys) != 0 {
+			t.Errorf("want no passkeys, got %d", len(r.Passkeys))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passkey
+
+import (
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ webauthn.User = &webAuthnUser{}
+
+// webAuthnUser exposes a user.User and their passkeys to the WebAuthn library.
+//
+// The user handle stored by authenticators is the user's UUID, which allows looking
+// them up when logging in without entering an email address.
+type webAuthnUser struct {
+	user     user.User
+	passkeys []Passkey
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(u.user.UUID)
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.DisplayName
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.passkeys))
+
+	for i, p := range u.passkeys {
+		credentials[i] = p.Credential
+	}
+
+	return credentials
+}
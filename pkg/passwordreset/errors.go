@@ -0,0 +1,16 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import "errors"
+
+var (
+	ErrHmacKeyRequired     = errors.New("password reset: hmac key is required")
+	ErrNotFound            = errors.New("password reset: not found")
+	ErrNotifierRequired    = errors.New("password reset: notifier is required")
+	ErrResetURLRequired    = errors.New("password reset: reset URL required")
+	ErrTokenRequired       = errors.New("password reset: token required")
+	ErrTokenHashRequired   = errors.New("password reset: token hash required")
+	ErrUserServiceRequired = errors.New("password reset: user service is required")
+)
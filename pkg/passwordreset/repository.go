@@ -0,0 +1,25 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import (
+	"context"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// Repository provides access to password reset tokens.
+type Repository interface {
+	// PasswordResetTokenAdd saves a new Token, and deletes all other tokens issued to the same user.
+	PasswordResetTokenAdd(ctx context.Context, t Token) error
+
+	// PasswordResetTokenGetByHash returns the Token corresponding to a given hash, if it has not expired.
+	PasswordResetTokenGetByHash(ctx context.Context, hash string) (Token, error)
+
+	// PasswordResetTokenConsume atomically deletes all tokens issued to the owner of a given token, and
+	// updates their password hash.
+	//
+	// It returns ErrNotFound if the token does not exist or has expired.
+	PasswordResetTokenConsume(ctx context.Context, hash string, passwordHashUpdate user.PasswordHashUpdate) error
+}
@@ -0,0 +1,57 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Tokens []Token
+
+	// UserRepository receives password hash updates.
+	UserRepository *user.FakeRepository
+}
+
+func (r *FakeRepository) PasswordResetTokenAdd(_ context.Context, t Token) error {
+	r.Tokens = slices.DeleteFunc(r.Tokens, func(other Token) bool {
+		return other.UserUUID == t.UserUUID
+	})
+
+	r.Tokens = append(r.Tokens, t)
+
+	return nil
+}
+
+func (r *FakeRepository) PasswordResetTokenGetByHash(_ context.Context, hash string) (Token, error) {
+	now := time.Now().UTC()
+
+	for _, t := range r.Tokens {
+		if t.TokenHash == hash && t.ExpiresAt.After(now) {
+			return t, nil
+		}
+	}
+
+	return Token{}, ErrNotFound
+}
+
+func (r *FakeRepository) PasswordResetTokenConsume(ctx context.Context, hash string, passwordHashUpdate user.PasswordHashUpdate) error {
+	t, err := r.PasswordResetTokenGetByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	r.Tokens = slices.DeleteFunc(r.Tokens, func(other Token) bool {
+		return other.UserUUID == t.UserUUID
+	})
+
+	return r.UserRepository.UserUpdatePasswordHash(ctx, passwordHashUpdate)
+}
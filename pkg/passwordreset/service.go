@@ -0,0 +1,218 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	TokenNBytes int = 32
+	TokenTTL        = 1 * time.Hour
+
+	sendTimeout = 1 * time.Minute
+)
+
+var resetMessageTemplate = template.Must(template.New("reset").Parse(`Hello {{ .DisplayName }},
+
+Someone requested a password reset for your SparkleMuffin account.
+
+To choose a new password, open the following link within {{ .TTL }}:
+
+{{ .ResetURL }}
+
+If you did not request a password reset, you can safely ignore this email: your password will not change.
+`))
+
+// Service handles password reset operations.
+type Service struct {
+	r           Repository
+	userService *user.Service
+	notifier    notification.Notifier
+	hmac        *hash.HMAC
+
+	// pending tracks password reset emails being sent in the background.
+	pending sync.WaitGroup
+}
+
+// NewService initializes and returns a password reset Service.
+func NewService(r Repository, userService *user.Service, notifier notification.Notifier, hmacKey string) (*Service, error) {
+	if userService == nil {
+		return &Service{}, ErrUserServiceRequired
+	}
+	if notifier == nil {
+		return &Service{}, ErrNotifierRequired
+	}
+	if hmacKey == "" {
+		return &Service{}, ErrHmacKeyRequired
+	}
+
+	return &Service{
+		r:           r,
+		userService: userService,
+		notifier:    notifier,
+		hmac:        hash.NewHMAC(hmacKey),
+	}, nil
+}
+
+// Request issues a password reset Token for the user registered with a given email address, and
+// emails them a link to reset their password.
+//
+// resetURL returns the absolute URL of the password reset form for a given clear-text token.
+//
+// No error is returned if no user is registered with this address, and the token is issued
+// and emailed in the background, so as not to disclose which addresses are registered through
+// the outcome or the duration of the request.
+func (s *Service) Request(ctx context.Context, email string, resetURL func(token string) string) error {
+	if resetURL == nil {
+		return ErrResetURLRequired
+	}
+
+	u, err := s.userService.ByEmail(ctx, email)
+	if errors.Is(err, user.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.pending.Go(func() {
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+		defer cancel()
+
+		if err := s.issue(sendCtx, u, resetURL); err != nil {
+			log.
+				Error().
+				Err(err).
+				Str("user_uuid", u.UUID).
+				Msg("password reset: failed to send password reset email")
+		}
+	})
+
+	return nil
+}
+
+// Wait blocks until all password reset emails being sent in the background have been
+// processed.
+func (s *Service) Wait() {
+	s.pending.Wait()
+}
+
+// issue issues a password reset Token for a given user, and emails them a link to reset
+// their password.
+func (s *Service) issue(ctx context.Context, u user.User, resetURL func(token string) string) error {
+	clearToken, err := rand.RandomBase64URLString(TokenNBytes)
+	if err != nil {
+		return err
+	}
+
+	tokenHash, err := s.hmac.Hash(clearToken)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	t := Token{
+		UserUUID:  u.UUID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(TokenTTL),
+		CreatedAt: now,
+	}
+
+	if err := s.r.PasswordResetTokenAdd(ctx, t); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+
+	data := struct {
+		DisplayName string
+		ResetURL    string
+		TTL         string
+	}{
+		DisplayName: u.DisplayName,
+		ResetURL:    resetURL(clearToken),
+		TTL:         "1 hour",
+	}
+
+	if err := resetMessageTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+
+	message := notification.Message{
+		To:      u.Email,
+		Subject: "Reset your SparkleMuffin password",
+		Body:    body.String(),
+	}
+
+	return s.notifier.Notify(ctx, message)
+}
+
+// ByToken returns the password reset Token corresponding to a given clear-text token,
+// if it is still valid.
+func (s *Service) ByToken(ctx context.Context, token string) (Token, error) {
+	if token == "" {
+		return Token{}, ErrTokenRequired
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if tokenHash == "" {
+		return Token{}, ErrTokenHashRequired
+	}
+
+	return s.r.PasswordResetTokenGetByHash(ctx, tokenHash)
+}
+
+// Reset sets a new password for the owner of a valid password reset Token, and returns
+// their UUID.
+//
+// The token, and any other token issued to the same user, cannot be used again.
+func (s *Service) Reset(ctx context.Context, reset Reset) (string, error) {
+	t, err := s.ByToken(ctx, reset.Token)
+	if err != nil {
+		return "", err
+	}
+
+	if reset.NewPassword != reset.NewPasswordConfirmation {
+		return "", user.ErrPasswordConfirmationMismatch
+	}
+
+	u := user.User{
+		UUID:     t.UserUUID,
+		Password: reset.NewPassword,
+	}
+
+	if err := s.userService.ValidateForPasswordHashUpdate(&u); err != nil {
+		return "", err
+	}
+
+	passwordHashUpdate := user.PasswordHashUpdate{
+		UserUUID:     u.UUID,
+		PasswordHash: u.PasswordHash,
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := s.r.PasswordResetTokenConsume(ctx, t.TokenHash, passwordHashUpdate); err != nil {
+		return "", err
+	}
+
+	return t.UserUUID, nil
+}
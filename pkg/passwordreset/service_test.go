@@ -0,0 +1,293 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testHmacKey = "test-hmac-key"
+)
+
+var (
+	testResetURLRegex = regexp.MustCompile(`https://sparklemuffin\.test/password-reset/([A-Za-z0-9_=-]+)`)
+)
+
+func testResetURL(token string) string {
+	return "https://sparklemuffin.test/password-reset/" + token
+}
+
+// blockingNotifier blocks sending messages until released.
+type blockingNotifier struct {
+	release chan struct{}
+	sent    int
+}
+
+func (n *blockingNotifier) Notify(ctx context.Context, _ notification.Message) error {
+	select {
+	case <-n.release:
+		n.sent++
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newTestService(t *testing.T, users ...user.User) (*Service, *FakeRepository, *notification.FakeNotifier) {
+	t.Helper()
+
+	ur := &user.FakeRepository{Users: users}
+	r := &FakeRepository{UserRepository: ur}
+	notifier := &notification.FakeNotifier{}
+
+	s, err := NewService(r, user.NewService(ur, user.FakePasswordHasher(t)), notifier, testHmacKey)
+	if err != nil {
+		t.Fatalf("failed to create service: %q", err)
+	}
+
+	return s, r, notifier
+}
+
+func TestServiceRequest(t *testing.T) {
+	testUser := user.User{
+		UUID:        "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:       "jane.doe@example.org",
+		DisplayName: "Jane Doe",
+	}
+
+	t.Run("registered email", func(t *testing.T) {
+		s, r, notifier := newTestService(t, testUser)
+
+		if err := s.Request(t.Context(), " Jane.Doe@Example.org ", testResetURL); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		s.Wait()
+
+		if len(r.Tokens) != 1 {
+			t.Fatalf("want 1 token, got %d", len(r.Tokens))
+		}
+		if len(notifier.Messages) != 1 {
+			t.Fatalf("want 1 message, got %d", len(notifier.Messages))
+		}
+
+		message := notifier.Messages[0]
+		if message.To != testUser.Email {
+			t.Errorf("want recipient %q, got %q", testUser.Email, message.To)
+		}
+
+		matches := testResetURLRegex.FindStringSubmatch(message.Body)
+		if matches == nil {
+			t.Fatalf("want a reset link, got:\n%s", message.Body)
+		}
+
+		clearToken := matches[1]
+		if r.Tokens[0].TokenHash == clearToken {
+			t.Error("want the token to be stored as a hash")
+		}
+
+		got, err := s.ByToken(t.Context(), clearToken)
+		if err != nil {
+			t.Fatalf("want the emailed token to be valid, got %q", err)
+		}
+		if got.UserUUID != testUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testUser.UUID, got.UserUUID)
+		}
+	})
+
+	t.Run("new request replaces previous tokens", func(t *testing.T) {
+		s, r, notifier := newTestService(t, testUser)
+
+		for range 2 {
+			if err := s.Request(t.Context(), testUser.Email, testResetURL); err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			s.Wait()
+		}
+
+		if len(r.Tokens) != 1 {
+			t.Fatalf("want 1 token, got %d", len(r.Tokens))
+		}
+
+		firstToken := testResetURLRegex.FindStringSubmatch(notifier.Messages[0].Body)[1]
+		if _, err := s.ByToken(t.Context(), firstToken); !errors.Is(err, ErrNotFound) {
+			t.Errorf("want error %q, got %q", ErrNotFound, err)
+		}
+	})
+
+	t.Run("email sent in the background", func(t *testing.T) {
+		ur := &user.FakeRepository{Users: []user.User{testUser}}
+		r := &FakeRepository{UserRepository: ur}
+		notifier := &blockingNotifier{release: make(chan struct{})}
+
+		s, err := NewService(r, user.NewService(ur, user.FakePasswordHasher(t)), notifier, testHmacKey)
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		requestErr := make(chan error, 1)
+		go func() {
+			requestErr <- s.Request(t.Context(), testUser.Email, testResetURL)
+		}()
+
+		// the request completes while the email is still being sent
+		select {
+		case err := <-requestErr:
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("want the request to complete before the email is sent")
+		}
+
+		close(notifier.release)
+		s.Wait()
+
+		if len(r.Tokens) != 1 {
+			t.Errorf("want 1 token, got %d", len(r.Tokens))
+		}
+		if notifier.sent != 1 {
+			t.Errorf("want 1 message, got %d", notifier.sent)
+		}
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		s, r, notifier := newTestService(t, testUser)
+
+		if err := s.Request(t.Context(), "unknown@example.org", testResetURL); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		s.Wait()
+
+		if len(r.Tokens) != 0 {
+			t.Errorf("want no token, got %d", len(r.Tokens))
+		}
+		if len(notifier.Messages) != 0 {
+			t.Errorf("want no message, got %d", len(notifier.Messages))
+		}
+	})
+}
+
+func TestServiceReset(t *testing.T) {
+	testUser := user.User{
+		UUID:         "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:        "jane.doe@example.org",
+		DisplayName:  "Jane Doe",
+		PasswordHash: "$2a$10$previous",
+	}
+
+	issueToken := func(t *testing.T, s *Service, notifier *notification.FakeNotifier) string {
+		t.Helper()
+
+		if err := s.Request(t.Context(), testUser.Email, testResetURL); err != nil {
+			t.Fatalf("failed to request password reset: %q", err)
+		}
+
+		s.Wait()
+
+		return testResetURLRegex.FindStringSubmatch(notifier.Messages[len(notifier.Messages)-1].Body)[1]
+	}
+
+	t.Run("reset password", func(t *testing.T) {
+		s, r, notifier := newTestService(t, testUser)
+		clearToken := issueToken(t, s, notifier)
+
+		reset := Reset{
+			Token:                   clearToken,
+			NewPassword:             "correct horse battery staple",
+			NewPasswordConfirmation: "correct horse battery staple",
+		}
+
+		userUUID, err := s.Reset(t.Context(), reset)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if userUUID != testUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testUser.UUID, userUUID)
+		}
+
+		got := r.UserRepository.Users[0]
+		if err := user.FakePasswordHasher(t).Compare(got.PasswordHash, reset.NewPassword); err != nil {
+			t.Errorf("want the password to be updated, got %q", err)
+		}
+
+		if _, err := s.Reset(t.Context(), reset); !errors.Is(err, ErrNotFound) {
+			t.Errorf("want the token to be single-use, got %q", err)
+		}
+	})
+
+	cases := []struct {
+		tname   string
+		reset   func(clearToken string) Reset
+		wantErr error
+	}{
+		{
+			tname: "missing token",
+			reset: func(_ string) Reset {
+				return Reset{NewPassword: "newpassword", NewPasswordConfirmation: "newpassword"}
+			},
+			wantErr: ErrTokenRequired,
+		},
+		{
+			tname: "unknown token",
+			reset: func(_ string) Reset {
+				return Reset{Token: "unknown", NewPassword: "newpassword", NewPasswordConfirmation: "newpassword"}
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			tname: "confirmation mismatch",
+			reset: func(clearToken string) Reset {
+				return Reset{Token: clearToken, NewPassword: "newpassword", NewPasswordConfirmation: "otherpassword"}
+			},
+			wantErr: user.ErrPasswordConfirmationMismatch,
+		},
+		{
+			tname: "password too short",
+			reset: func(clearToken string) Reset {
+				return Reset{Token: clearToken, NewPassword: "short", NewPasswordConfirmation: "short"}
+			},
+			wantErr: user.ErrPasswordTooShort,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s, r, notifier := newTestService(t, testUser)
+			clearToken := issueToken(t, s, notifier)
+
+			_, err := s.Reset(t.Context(), tc.reset(clearToken))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if r.UserRepository.Users[0].PasswordHash != testUser.PasswordHash {
+				t.Error("want the password to be unchanged")
+			}
+		})
+	}
+
+	t.Run("expired token", func(t *testing.T) {
+		s, r, notifier := newTestService(t, testUser)
+		clearToken := issueToken(t, s, notifier)
+
+		r.Tokens[0].ExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+		reset := Reset{Token: clearToken, NewPassword: "newpassword", NewPasswordConfirmation: "newpassword"}
+
+		if _, err := s.Reset(t.Context(), reset); !errors.Is(err, ErrNotFound) {
+			t.Errorf("want error %q, got %q", ErrNotFound, err)
+		}
+	})
+}
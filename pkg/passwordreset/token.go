@@ -0,0 +1,27 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package passwordreset
+
+import "time"
+
+// Token represents a single-use, time-limited password reset token.
+type Token struct {
+	UserUUID string
+
+	// Token is the clear-text token sent to the user; it is never persisted.
+	Token string
+
+	// TokenHash is the HMAC hash of the token, used to look it up.
+	TokenHash string
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Reset represents a password change requested with a password reset Token.
+type Reset struct {
+	Token                   string
+	NewPassword             string
+	NewPasswordConfirmation string
+}
@@ -0,0 +1,14 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import "errors"
+
+var (
+	ErrBookmarkLimitReached     = errors.New("quota: bookmark limit reached")
+	ErrImportFileTooLarge       = errors.New("quota: import file too large")
+	ErrLimitInvalid             = errors.New("quota: limits must be positive or zero")
+	ErrSubscriptionLimitReached = errors.New("quota: subscription limit reached")
+	ErrUserUUIDRequired         = errors.New("quota: user UUID required")
+)
@@ -0,0 +1,103 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits represents the maximum amount of data a user may store.
+//
+// A zero value means the corresponding resource is unlimited.
+type Limits struct {
+	MaxBookmarks     int64
+	MaxSubscriptions int64
+
+	// MaxImportFileSize is the maximum size of an import file, in bytes.
+	MaxImportFileSize int64
+}
+
+// Validate ensures all limits are positive or zero.
+func (l *Limits) Validate() error {
+	if l.MaxBookmarks < 0 || l.MaxSubscriptions < 0 || l.MaxImportFileSize < 0 {
+		return ErrLimitInvalid
+	}
+
+	return nil
+}
+
+// CheckBookmarkCount ensures a user currently storing the given number of bookmarks
+// may store n additional bookmarks.
+//
+// A zero maxBookmarks means the number of bookmarks is unlimited.
+func CheckBookmarkCount(maxBookmarks int64, current int64, n int64) error {
+	if maxBookmarks == 0 || current+n <= maxBookmarks {
+		return nil
+	}
+
+	return fmt.Errorf("%w (limit: %d, current: %d, requested: %d)", ErrBookmarkLimitReached, maxBookmarks, current, n)
+}
+
+// CheckSubscriptionCount ensures a user currently storing the given number of feed
+// subscriptions may store n additional subscriptions.
+//
+// A zero maxSubscriptions means the number of subscriptions is unlimited.
+func CheckSubscriptionCount(maxSubscriptions int64, current int64, n int64) error {
+	if maxSubscriptions == 0 || current+n <= maxSubscriptions {
+		return nil
+	}
+
+	return fmt.Errorf("%w (limit: %d, current: %d, requested: %d)", ErrSubscriptionLimitReached, maxSubscriptions, current, n)
+}
+
+// Override represents per-user limits, set by an administrator.
+//
+// A nil value means the corresponding instance-wide limit applies.
+type Override struct {
+	UserUUID string
+
+	MaxBookmarks      *int64
+	MaxSubscriptions  *int64
+	MaxImportFileSize *int64
+
+	UpdatedAt time.Time
+}
+
+// IsEmpty returns whether the Override does not change any instance-wide limit.
+func (o *Override) IsEmpty() bool {
+	return o.MaxBookmarks == nil && o.MaxSubscriptions == nil && o.MaxImportFileSize == nil
+}
+
+// ValidateForUpdate ensures mandatory fields are set and all limits are positive or zero.
+func (o *Override) ValidateForUpdate() error {
+	if o.UserUUID == "" {
+		return ErrUserUUIDRequired
+	}
+
+	for _, limit := range []*int64{o.MaxBookmarks, o.MaxSubscriptions, o.MaxImportFileSize} {
+		if limit != nil && *limit < 0 {
+			return ErrLimitInvalid
+		}
+	}
+
+	return nil
+}
+
+// apply returns the Limits resulting from applying the Override to instance-wide Limits.
+func (o *Override) apply(defaults Limits) Limits {
+	limits := defaults
+
+	if o.MaxBookmarks != nil {
+		limits.MaxBookmarks = *o.MaxBookmarks
+	}
+	if o.MaxSubscriptions != nil {
+		limits.MaxSubscriptions = *o.MaxSubscriptions
+	}
+	if o.MaxImportFileSize != nil {
+		limits.MaxImportFileSize = *o.MaxImportFileSize
+	}
+
+	return limits
+}
@@ -0,0 +1,24 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import "context"
+
+// Repository provides access to per-user quota overrides and storage usage.
+type Repository interface {
+	// QuotaOverrideDelete removes a user's quota Override, if any.
+	QuotaOverrideDelete(ctx context.Context, userUUID string) error
+
+	// QuotaOverrideGetByUserUUID returns a user's quota Override.
+	//
+	// An empty Override is returned if no per-user limits have been set.
+	QuotaOverrideGetByUserUUID(ctx context.Context, userUUID string) (Override, error)
+
+	// QuotaOverrideUpsert creates or updates a user's quota Override.
+	QuotaOverrideUpsert(ctx context.Context, override Override) error
+
+	// QuotaUsageGetByUserUUID returns the number of bookmarks and feed subscriptions
+	// stored by a user.
+	QuotaUsageGetByUserUUID(ctx context.Context, userUUID string) (Usage, error)
+}
@@ -0,0 +1,40 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import "context"
+
+var _ Repository = &FakeRepository{}
+
+type FakeRepository struct {
+	Overrides map[string]Override
+	Usages    map[string]Usage
+}
+
+func (r *FakeRepository) QuotaOverrideDelete(_ context.Context, userUUID string) error {
+	delete(r.Overrides, userUUID)
+	return nil
+}
+
+func (r *FakeRepository) QuotaOverrideGetByUserUUID(_ context.Context, userUUID string) (Override, error) {
+	override, ok := r.Overrides[userUUID]
+	if !ok {
+		return Override{UserUUID: userUUID}, nil
+	}
+
+	return override, nil
+}
+
+func (r *FakeRepository) QuotaOverrideUpsert(_ context.Context, override Override) error {
+	if r.Overrides == nil {
+		r.Overrides = map[string]Override{}
+	}
+
+	r.Overrides[override.UserUUID] = override
+	return nil
+}
+
+func (r *FakeRepository) QuotaUsageGetByUserUUID(_ context.Context, userUUID string) (Usage, error) {
+	return r.Usages[userUUID], nil
+}
@@ -0,0 +1,132 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Service handles per-user storage quotas.
+type Service struct {
+	r        Repository
+	defaults Limits
+}
+
+// NewService initializes and returns a quota Service, enforcing the given instance-wide
+// Limits unless overridden for a given user.
+func NewService(r Repository, defaults Limits) (*Service, error) {
+	if err := defaults.Validate(); err != nil {
+		return &Service{}, err
+	}
+
+	return &Service{
+		r:        r,
+		defaults: defaults,
+	}, nil
+}
+
+// Defaults returns the instance-wide Limits.
+func (s *Service) Defaults() Limits {
+	return s.defaults
+}
+
+// LimitsByUserUUID returns the Limits that apply to a given user.
+func (s *Service) LimitsByUserUUID(ctx context.Context, userUUID string) (Limits, error) {
+	override, err := s.OverrideByUserUUID(ctx, userUUID)
+	if err != nil {
+		return Limits{}, err
+	}
+
+	return override.apply(s.defaults), nil
+}
+
+// OverrideByUserUUID returns the per-user limits set for a given user.
+func (s *Service) OverrideByUserUUID(ctx context.Context, userUUID string) (Override, error) {
+	if userUUID == "" {
+		return Override{}, ErrUserUUIDRequired
+	}
+
+	return s.r.QuotaOverrideGetByUserUUID(ctx, userUUID)
+}
+
+// SummaryByUserUUID returns a user's current Usage, along with the Limits that apply to them.
+func (s *Service) SummaryByUserUUID(ctx context.Context, userUUID string) (Summary, error) {
+	override, err := s.OverrideByUserUUID(ctx, userUUID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	usage, err := s.r.QuotaUsageGetByUserUUID(ctx, userUUID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		Usage:    usage,
+		Limits:   override.apply(s.defaults),
+		Override: override,
+	}
+
+	return summary, nil
+}
+
+// UpdateOverride sets the per-user limits for a given user.
+//
+// Clearing all per-user limits restores the instance-wide Limits.
+func (s *Service) UpdateOverride(ctx context.Context, override Override) error {
+	if err := override.ValidateForUpdate(); err != nil {
+		return err
+	}
+
+	if override.IsEmpty() {
+		return s.r.QuotaOverrideDelete(ctx, override.UserUUID)
+	}
+
+	override.UpdatedAt = time.Now().UTC()
+
+	return s.r.QuotaOverrideUpsert(ctx, override)
+}
+
+// CheckSubscriptions ensures a given user may store n additional feed subscriptions.
+//
+// This allows rejecting a subscription before fetching the feed; the limit is then
+// enforced when the subscription is stored, as concurrent requests may add subscriptions
+// in between.
+func (s *Service) CheckSubscriptions(ctx context.Context, userUUID string, n int64) error {
+	limits, err := s.LimitsByUserUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxSubscriptions == 0 {
+		return nil
+	}
+
+	usage, err := s.r.QuotaUsageGetByUserUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	return CheckSubscriptionCount(limits.MaxSubscriptions, usage.Subscriptions, n)
+}
+
+// CheckImportFileSize ensures a given user may import a file of the given size, in bytes.
+func (s *Service) CheckImportFileSize(ctx context.Context, userUUID string, size int64) error {
+	limits, err := s.LimitsByUserUUID(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxImportFileSize == 0 {
+		return nil
+	}
+
+	if size > limits.MaxImportFileSize {
+		return fmt.Errorf("%w (limit: %d bytes, size: %d bytes)", ErrImportFileTooLarge, limits.MaxImportFileSize, size)
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"errors"
+	"testing"
+)
+
+const testUserUUID = "1f5c2a3e-8b4d-4c6e-9f0a-1b2c3d4e5f60"
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestNewService(t *testing.T) {
+	cases := []struct {
+		tname    string
+		defaults Limits
+		wantErr  error
+	}{
+		{
+			tname: "unlimited",
+		},
+		{
+			tname: "limited",
+			defaults: Limits{
+				MaxBookmarks:      1000,
+				MaxSubscriptions:  100,
+				MaxImportFileSize: 1 << 20,
+			},
+		},
+		{
+			tname:    "negative limit",
+			defaults: Limits{MaxSubscriptions: -1},
+			wantErr:  ErrLimitInvalid,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			_, err := NewService(&FakeRepository{}, tc.defaults)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServiceCheck(t *testing.T) {
+	defaults := Limits{
+		MaxBookmarks:      10,
+		MaxSubscriptions:  5,
+		MaxImportFileSize: 1024,
+	}
+
+	// bookmark limits are enforced by the repository when storing bookmarks
+	checkBookmarks := func(n int64) func(s *Service) error {
+		return func(s *Service) error {
+			limits, err := s.LimitsByUserUUID(t.Context(), testUserUUID)
+			if err != nil {
+				return err
+			}
+
+			usage, err := s.r.QuotaUsageGetByUserUUID(t.Context(), testUserUUID)
+			if err != nil {
+				return err
+			}
+
+			return CheckBookmarkCount(limits.MaxBookmarks, usage.Bookmarks, n)
+		}
+	}
+
+	cases := []struct {
+		tname     string
+		overrides map[string]Override
+		usage     Usage
+		check     func(s *Service) error
+		wantErr   error
+	}{
+		// Bookmarks
+		{
+			tname: "bookmarks below limit",
+			usage: Usage{Bookmarks: 9},
+			check: checkBookmarks(1),
+		},
+		{
+			tname:   "bookmarks above limit",
+			usage:   Usage{Bookmarks: 9},
+			check:   checkBookmarks(2),
+			wantErr: ErrBookmarkLimitReached,
+		},
+		{
+			tname: "bookmarks above instance limit, raised for user",
+			overrides: map[string]Override{
+				testUserUUID: {UserUUID: testUserUUID, MaxBookmarks: int64Ptr(100)},
+			},
+			usage: Usage{Bookmarks: 50},
+			check: checkBookmarks(1),
+		},
+		{
+			tname: "bookmarks unlimited for user",
+			overrides: map[string]Override{
+				testUserUUID: {UserUUID: testUserUUID, MaxBookmarks: int64Ptr(0)},
+			},
+			usage: Usage{Bookmarks: 50000},
+			check: checkBookmarks(1),
+		},
+
+		// Subscriptions
+		{
+			tname: "subscriptions below limit",
+			usage: Usage{Subscriptions: 4},
+			check: func(s *Service) error { return s.CheckSubscriptions(t.Context(), testUserUUID, 1) },
+		},
+		{
+			tname:   "subscriptions above limit",
+			usage:   Usage{Subscriptions: 5},
+			check:   func(s *Service) error { return s.CheckSubscriptions(t.Context(), testUserUUID, 1) },
+			wantErr: ErrSubscriptionLimitReached,
+		},
+		{
+			tname: "subscriptions lowered for user",
+			overrides: map[string]Override{
+				testUserUUID: {UserUUID: testUserUUID, MaxSubscriptions: int64Ptr(2)},
+			},
+			usage:   Usage{Subscriptions: 2},
+			check:   func(s *Service) error { return s.CheckSubscriptions(t.Context(), testUserUUID, 1) },
+			wantErr: ErrSubscriptionLimitReached,
+		},
+
+		// Import file size
+		{
+			tname: "import file size below limit",
+			check: func(s *Service) error { return s.CheckImportFileSize(t.Context(), testUserUUID, 1024) },
+		},
+		{
+			tname:   "import file size above limit",
+			check:   func(s *Service) error { return s.CheckImportFileSize(t.Context(), testUserUUID, 1025) },
+			wantErr: ErrImportFileTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Overrides: tc.overrides,
+				Usages: map[string]Usage{
+					testUserUUID: tc.usage,
+				},
+			}
+
+			s, err := NewService(r, defaults)
+			if err != nil {
+				t.Fatalf("failed to create service: %q", err)
+			}
+
+			err = tc.check(s)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+		})
+	}
+}
+
+func TestServiceUpdateOverride(t *testing.T) {
+	defaults := Limits{
+		MaxBookmarks:     10,
+		MaxSubscriptions: 5,
+	}
+
+	t.Run("set and clear per-user limits", func(t *testing.T) {
+		r := &FakeRepository{}
+
+		s, err := NewService(r, defaults)
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		override := Override{
+			UserUUID:     testUserUUID,
+			MaxBookmarks: int64Ptr(20),
+		}
+
+		if err := s.UpdateOverride(t.Context(), override); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		summary, err := s.SummaryByUserUUID(t.Context(), testUserUUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		wantLimits := Limits{MaxBookmarks: 20, MaxSubscriptions: 5}
+		if summary.Limits != wantLimits {
+			t.Errorf("want limits %+v, got %+v", wantLimits, summary.Limits)
+		}
+		if summary.Override.UpdatedAt.IsZero() {
+			t.Error("want override UpdatedAt to be set")
+		}
+
+		if err := s.UpdateOverride(t.Context(), Override{UserUUID: testUserUUID}); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(r.Overrides) != 0 {
+			t.Errorf("want override to be removed, got %+v", r.Overrides)
+		}
+
+		limits, err := s.LimitsByUserUUID(t.Context(), testUserUUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if limits != defaults {
+			t.Errorf("want limits %+v, got %+v", defaults, limits)
+		}
+	})
+
+	t.Run("missing user UUID", func(t *testing.T) {
+		s, err := NewService(&FakeRepository{}, defaults)
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		err = s.UpdateOverride(t.Context(), Override{MaxBookmarks: int64Ptr(1)})
+		if !errors.Is(err, ErrUserUUIDRequired) {
+			t.Fatalf("want error %q, got %v", ErrUserUUIDRequired, err)
+		}
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		s, err := NewService(&FakeRepository{}, defaults)
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		err = s.UpdateOverride(t.Context(), Override{UserUUID: testUserUUID, MaxBookmarks: int64Ptr(-1)})
+		if !errors.Is(err, ErrLimitInvalid) {
+			t.Fatalf("want error %q, got %v", ErrLimitInvalid, err)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package quota
+
+// Usage represents the amount of data stored by a user.
+type Usage struct {
+	Bookmarks     int64
+	Subscriptions int64
+}
+
+// Summary represents a user's current Usage, along with the Limits that apply to them.
+type Summary struct {
+	Usage    Usage
+	Limits   Limits
+	Override Override
+}
@@ -0,0 +1,14 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+// Config holds the registration settings of the instance.
+type Config struct {
+	// Mode determines who may create an account.
+	Mode Mode
+
+	// EmailConfirmation requires new users to confirm their email address before their
+	// account is created.
+	EmailConfirmation bool
+}
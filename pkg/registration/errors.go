@@ -0,0 +1,27 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrConfirmURLRequired        = errors.New("registration: confirmation URL required")
+	ErrHmacKeyRequired           = errors.New("registration: hmac key is required")
+	ErrInvitationInvalid         = errors.New("registration: invalid, expired or exhausted invitation")
+	ErrInvitationRequired        = errors.New("registration: an invitation is required")
+	ErrInvitationsDisabled       = errors.New("registration: invitations are disabled")
+	ErrInvitationUserRequired    = errors.New("registration: invitation creator required")
+	ErrModeInvalid               = errors.New("registration: invalid mode")
+	ErrNotFound                  = errors.New("registration: not found")
+	ErrNotifierRequired          = errors.New("registration: notifier is required for email confirmation")
+	ErrRegistrationClosed        = errors.New("registration: registration is closed")
+	ErrTokenRequired             = errors.New("registration: token required")
+	ErrUserServiceRequired       = errors.New("registration: user service is required")
+	ErrUUIDRequired              = errors.New("registration: UUID required")
+	ErrInvitationMaxUsesInvalid  = fmt.Errorf("registration: invitation usage limit must be between 1 and %d", InvitationMaxUsesLimit)
+	ErrInvitationValidityInvalid = fmt.Errorf("registration: invitation validity must be between 1 and %d days", InvitationValidityDaysLimit)
+)
@@ -0,0 +1,129 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// InvitationMaxUsesLimit is the maximum number of accounts that can be created with
+	// a single Invitation.
+	InvitationMaxUsesLimit = 100
+
+	// InvitationValidityDaysLimit is the maximum number of days an Invitation remains valid.
+	InvitationValidityDaysLimit = 30
+)
+
+// Invitation represents a link allowing people to create an account on an instance with
+// invite-only registration.
+type Invitation struct {
+	UUID string
+
+	// CreatedByUUID identifies the user who issued the Invitation.
+	CreatedByUUID string
+
+	// TokenHash is the HMAC hash of the clear-text token embedded in the invitation link,
+	// which is only displayed once, upon creation.
+	TokenHash string
+
+	// MaxUses is the maximum number of accounts that can be created with this Invitation.
+	MaxUses int
+
+	// UseCount is the number of accounts created with this Invitation so far.
+	UseCount int
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// newInvitation initializes and returns a new Invitation with a random UUID.
+func newInvitation(createdByUUID string, tokenHash string, maxUses int, validityDays int) (Invitation, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	now := time.Now().UTC()
+
+	return Invitation{
+		UUID:          generatedUUID.String(),
+		CreatedByUUID: createdByUUID,
+		TokenHash:     tokenHash,
+		MaxUses:       maxUses,
+		ExpiresAt:     now.AddDate(0, 0, validityDays),
+		CreatedAt:     now,
+	}, nil
+}
+
+// Expired returns whether the Invitation has expired.
+func (i Invitation) Expired() bool {
+	return !i.ExpiresAt.After(time.Now().UTC())
+}
+
+// Exhausted returns whether the Invitation has reached its usage limit.
+func (i Invitation) Exhausted() bool {
+	return i.UseCount >= i.MaxUses
+}
+
+// Usable returns whether the Invitation can still be used to create an account.
+func (i Invitation) Usable() bool {
+	return !i.Expired() && !i.Exhausted()
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when adding an Invitation.
+func (i *Invitation) ValidateForAddition() error {
+	fns := []func() error{
+		i.requireUUID,
+		i.requireCreatedByUUID,
+		i.requireTokenHash,
+		i.ensureMaxUsesIsValid,
+		i.ensureValidityIsValid,
+	}
+
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Invitation) ensureMaxUsesIsValid() error {
+	if i.MaxUses < 1 || i.MaxUses > InvitationMaxUsesLimit {
+		return ErrInvitationMaxUsesInvalid
+	}
+	return nil
+}
+
+func (i *Invitation) ensureValidityIsValid() error {
+	if !i.ExpiresAt.After(i.CreatedAt) || i.ExpiresAt.After(i.CreatedAt.AddDate(0, 0, InvitationValidityDaysLimit)) {
+		return ErrInvitationValidityInvalid
+	}
+	return nil
+}
+
+func (i *Invitation) requireCreatedByUUID() error {
+	if i.CreatedByUUID == "" {
+		return ErrInvitationUserRequired
+	}
+	return nil
+}
+
+func (i *Invitation) requireTokenHash() error {
+	if i.TokenHash == "" {
+		return ErrTokenRequired
+	}
+	return nil
+}
+
+func (i *Invitation) requireUUID() error {
+	if i.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"slices"
+	"strings"
+)
+
+// Mode determines who may create an account on the instance.
+type Mode string
+
+const (
+	// ModeClosed only lets administrators create accounts.
+	ModeClosed Mode = "closed"
+
+	// ModeInviteOnly lets anyone holding a valid invitation create an account.
+	ModeInviteOnly Mode = "invite-only"
+
+	// ModeOpen lets anyone create an account.
+	ModeOpen Mode = "open"
+)
+
+// Modes lists all registration modes.
+var Modes = []Mode{
+	ModeClosed,
+	ModeInviteOnly,
+	ModeOpen,
+}
+
+// ParseMode returns the registration Mode corresponding to a given string.
+func ParseMode(s string) (Mode, error) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(s)))
+
+	if !slices.Contains(Modes, mode) {
+		return "", ErrModeInvalid
+	}
+
+	return mode, nil
+}
@@ -0,0 +1,45 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"context"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// Repository provides access to invitations and pending registrations.
+type Repository interface {
+	// InvitationAdd saves a new Invitation.
+	InvitationAdd(ctx context.Context, invitation Invitation) error
+
+	// InvitationDeleteByUUID deletes an Invitation issued by a given user.
+	InvitationDeleteByUUID(ctx context.Context, userUUID string, invitationUUID string) error
+
+	// InvitationGetByTokenHash returns the Invitation corresponding to a given hash, if it
+	// can still be used.
+	InvitationGetByTokenHash(ctx context.Context, hash string) (Invitation, error)
+
+	// InvitationGetByUserUUID returns all invitations issued by a given user.
+	InvitationGetByUserUUID(ctx context.Context, userUUID string) ([]Invitation, error)
+
+	// RegistrationAdd atomically uses the Invitation corresponding to invitationHash, if set,
+	// and saves a new User.
+	//
+	// It returns ErrInvitationInvalid if the invitation has expired or is exhausted.
+	RegistrationAdd(ctx context.Context, invitationHash string, u user.User) error
+
+	// PendingRegistrationAdd atomically uses the Invitation corresponding to invitationHash,
+	// if set, and saves a PendingRegistration, replacing pending registrations for the same
+	// email address.
+	//
+	// It returns ErrInvitationInvalid if the invitation has expired or is exhausted.
+	PendingRegistrationAdd(ctx context.Context, invitationHash string, p PendingRegistration) error
+
+	// PendingRegistrationConfirm atomically deletes the PendingRegistration corresponding to a
+	// given token hash, creates the corresponding User, and returns their UUID.
+	//
+	// It returns ErrNotFound if the pending registration does not exist or has expired.
+	PendingRegistrationConfirm(ctx context.Context, hash string) (string, error)
+}
@@ -0,0 +1,128 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Invitations          []Invitation
+	PendingRegistrations []PendingRegistration
+
+	// UserRepository receives the accounts created upon registration.
+	UserRepository *user.FakeRepository
+}
+
+func (r *FakeRepository) InvitationAdd(_ context.Context, invitation Invitation) error {
+	r.Invitations = append(r.Invitations, invitation)
+	return nil
+}
+
+func (r *FakeRepository) InvitationDeleteByUUID(_ context.Context, userUUID string, invitationUUID string) error {
+	for index, invitation := range r.Invitations {
+		if invitation.CreatedByUUID == userUUID && invitation.UUID == invitationUUID {
+			r.Invitations = slices.Delete(r.Invitations, index, index+1)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) InvitationGetByTokenHash(_ context.Context, hash string) (Invitation, error) {
+	for _, invitation := range r.Invitations {
+		if invitation.TokenHash == hash && invitation.Usable() {
+			return invitation, nil
+		}
+	}
+
+	return Invitation{}, ErrNotFound
+}
+
+func (r *FakeRepository) InvitationGetByUserUUID(_ context.Context, userUUID string) ([]Invitation, error) {
+	var invitations []Invitation
+
+	for _, invitation := range r.Invitations {
+		if invitation.CreatedByUUID == userUUID {
+			invitations = append(invitations, invitation)
+		}
+	}
+
+	return invitations, nil
+}
+
+func (r *FakeRepository) RegistrationAdd(ctx context.Context, invitationHash string, u user.User) error {
+	if err := r.invitationUse(invitationHash); err != nil {
+		return err
+	}
+
+	return r.UserRepository.UserAdd(ctx, u)
+}
+
+func (r *FakeRepository) PendingRegistrationAdd(_ context.Context, invitationHash string, p PendingRegistration) error {
+	if err := r.invitationUse(invitationHash); err != nil {
+		return err
+	}
+
+	r.PendingRegistrations = slices.DeleteFunc(r.PendingRegistrations, func(other PendingRegistration) bool {
+		return other.Email == p.Email
+	})
+
+	r.PendingRegistrations = append(r.PendingRegistrations, p)
+
+	return nil
+}
+
+func (r *FakeRepository) PendingRegistrationConfirm(ctx context.Context, hash string) (string, error) {
+	now := time.Now().UTC()
+
+	for index, p := range r.PendingRegistrations {
+		if p.TokenHash != hash || !p.ExpiresAt.After(now) {
+			continue
+		}
+
+		r.PendingRegistrations = slices.Delete(r.PendingRegistrations, index, index+1)
+
+		u := user.User{
+			UUID:         p.UserUUID,
+			Email:        p.Email,
+			NickName:     p.NickName,
+			DisplayName:  p.DisplayName,
+			PasswordHash: p.PasswordHash,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		if err := r.UserRepository.UserAdd(ctx, u); err != nil {
+			return "", err
+		}
+
+		return p.UserUUID, nil
+	}
+
+	return "", ErrNotFound
+}
+
+func (r *FakeRepository) invitationUse(hash string) error {
+	if hash == "" {
+		return nil
+	}
+
+	for index, invitation := range r.Invitations {
+		if invitation.TokenHash == hash && invitation.Usable() {
+			r.Invitations[index].UseCount++
+			return nil
+		}
+	}
+
+	return ErrInvitationInvalid
+}
@@ -0,0 +1,289 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"text/template"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	TokenNBytes            int = 32
+	PendingRegistrationTTL     = 24 * time.Hour
+)
+
+var confirmationMessageTemplate = template.Must(template.New("confirmation").Parse(`Hello {{ .DisplayName }},
+
+Welcome to SparkleMuffin!
+
+To confirm your email address and create your account, open the following link within {{ .TTL }}:
+
+{{ .ConfirmURL }}
+
+If you did not sign up, you can safely ignore this email: no account will be created.
+`))
+
+// Service handles self-service registration and invitations.
+type Service struct {
+	r           Repository
+	userService *user.Service
+	notifier    notification.Notifier
+	config      Config
+	hmac        *hash.HMAC
+}
+
+// NewService initializes and returns a registration Service.
+//
+// notifier may be nil, unless email confirmation is enabled.
+func NewService(r Repository, userService *user.Service, notifier notification.Notifier, config Config, hmacKey string) (*Service, error) {
+	if userService == nil {
+		return &Service{}, ErrUserServiceRequired
+	}
+	if _, err := ParseMode(string(config.Mode)); err != nil {
+		return &Service{}, err
+	}
+	if config.EmailConfirmation && notifier == nil {
+		return &Service{}, ErrNotifierRequired
+	}
+	if hmacKey == "" {
+		return &Service{}, ErrHmacKeyRequired
+	}
+
+	return &Service{
+		r:           r,
+		userService: userService,
+		notifier:    notifier,
+		config:      config,
+		hmac:        hash.NewHMAC(hmacKey),
+	}, nil
+}
+
+// Enabled returns whether people may create their own account.
+func (s *Service) Enabled() bool {
+	return s.config.Mode != ModeClosed
+}
+
+// InvitationsEnabled returns whether users may issue invitations.
+func (s *Service) InvitationsEnabled() bool {
+	return s.config.Mode == ModeInviteOnly
+}
+
+// EmailConfirmationRequired returns whether new users must confirm their email address
+// before their account is created.
+func (s *Service) EmailConfirmationRequired() bool {
+	return s.config.EmailConfirmation
+}
+
+// InvitationAdd issues a new Invitation on behalf of a given user, and returns it along with
+// the clear-text token to share with invitees.
+func (s *Service) InvitationAdd(ctx context.Context, userUUID string, maxUses int, validityDays int) (Invitation, string, error) {
+	if !s.InvitationsEnabled() {
+		return Invitation{}, "", ErrInvitationsDisabled
+	}
+	if validityDays < 1 || validityDays > InvitationValidityDaysLimit {
+		return Invitation{}, "", ErrInvitationValidityInvalid
+	}
+
+	clearToken, err := rand.RandomBase64URLString(TokenNBytes)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	tokenHash, err := s.hmac.Hash(clearToken)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	invitation, err := newInvitation(userUUID, tokenHash, maxUses, validityDays)
+	if err != nil {
+		return Invitation{}, "", err
+	}
+
+	if err := invitation.ValidateForAddition(); err != nil {
+		return Invitation{}, "", err
+	}
+
+	if err := s.r.InvitationAdd(ctx, invitation); err != nil {
+		return Invitation{}, "", err
+	}
+
+	return invitation, clearToken, nil
+}
+
+// InvitationByToken returns the Invitation corresponding to a given clear-text token, if it
+// can still be used.
+func (s *Service) InvitationByToken(ctx context.Context, token string) (Invitation, error) {
+	if token == "" {
+		return Invitation{}, ErrTokenRequired
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	invitation, err := s.r.InvitationGetByTokenHash(ctx, tokenHash)
+	if errors.Is(err, ErrNotFound) {
+		return Invitation{}, ErrInvitationInvalid
+	}
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	return invitation, nil
+}
+
+// InvitationDelete revokes an Invitation issued by a given user.
+func (s *Service) InvitationDelete(ctx context.Context, userUUID string, invitationUUID string) error {
+	if userUUID == "" {
+		return ErrInvitationUserRequired
+	}
+	if invitationUUID == "" {
+		return ErrUUIDRequired
+	}
+
+	return s.r.InvitationDeleteByUUID(ctx, userUUID, invitationUUID)
+}
+
+// InvitationsByUserUUID returns all invitations issued by a given user.
+func (s *Service) InvitationsByUserUUID(ctx context.Context, userUUID string) ([]Invitation, error) {
+	if userUUID == "" {
+		return []Invitation{}, ErrInvitationUserRequired
+	}
+
+	return s.r.InvitationGetByUserUUID(ctx, userUUID)
+}
+
+// Register creates an account with the information submitted by a new user, and returns
+// the UUID of this account.
+//
+// If email confirmation is enabled, the account is only created once the user opens the
+// link emailed to them; confirmURL returns the absolute URL of this link for a given
+// clear-text token.
+//
+// In invite-only mode, the Invitation is used as soon as the information is submitted.
+func (s *Service) Register(ctx context.Context, signup Signup, confirmURL func(token string) string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrRegistrationClosed
+	}
+	if s.config.EmailConfirmation && confirmURL == nil {
+		return "", ErrConfirmURLRequired
+	}
+
+	var invitationHash string
+
+	if s.InvitationsEnabled() {
+		if signup.InvitationToken == "" {
+			return "", ErrInvitationRequired
+		}
+
+		invitation, err := s.InvitationByToken(ctx, signup.InvitationToken)
+		if err != nil {
+			return "", err
+		}
+
+		invitationHash = invitation.TokenHash
+	}
+
+	if signup.Password != signup.PasswordConfirmation {
+		return "", user.ErrPasswordConfirmationMismatch
+	}
+
+	u, err := user.NewUser(signup.Email, signup.NickName, signup.DisplayName, signup.Password)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.userService.ValidateForAddition(ctx, &u); err != nil {
+		return "", err
+	}
+
+	if !s.config.EmailConfirmation {
+		if err := s.r.RegistrationAdd(ctx, invitationHash, u); err != nil {
+			return "", err
+		}
+
+		return u.UUID, nil
+	}
+
+	clearToken, err := rand.RandomBase64URLString(TokenNBytes)
+	if err != nil {
+		return "", err
+	}
+
+	tokenHash, err := s.hmac.Hash(clearToken)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+
+	pendingRegistration := PendingRegistration{
+		UserUUID:     u.UUID,
+		Email:        u.Email,
+		NickName:     u.NickName,
+		DisplayName:  u.DisplayName,
+		PasswordHash: u.PasswordHash,
+		TokenHash:    tokenHash,
+		ExpiresAt:    now.Add(PendingRegistrationTTL),
+		CreatedAt:    now,
+	}
+
+	if err := s.r.PendingRegistrationAdd(ctx, invitationHash, pendingRegistration); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+
+	data := struct {
+		DisplayName string
+		ConfirmURL  string
+		TTL         string
+	}{
+		DisplayName: u.DisplayName,
+		ConfirmURL:  confirmURL(clearToken),
+		TTL:         "24 hours",
+	}
+
+	if err := confirmationMessageTemplate.Execute(&body, data); err != nil {
+		return "", err
+	}
+
+	message := notification.Message{
+		To:      u.Email,
+		Subject: "Confirm your SparkleMuffin account",
+		Body:    body.String(),
+	}
+
+	if err := s.notifier.Notify(ctx, message); err != nil {
+		return "", err
+	}
+
+	return u.UUID, nil
+}
+
+// Confirm creates the account corresponding to a given clear-text confirmation token, and
+// returns its UUID.
+//
+// The token cannot be used again.
+func (s *Service) Confirm(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrTokenRequired
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return "", err
+	}
+
+	return s.r.PendingRegistrationConfirm(ctx, tokenHash)
+}
@@ -0,0 +1,432 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/pkg/notification"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testHmacKey = "test-hmac-key"
+)
+
+var (
+	testConfirmURLRegex = regexp.MustCompile(`https://sparklemuffin\.test/register/confirm/([A-Za-z0-9_=-]+)`)
+)
+
+func testConfirmURL(token string) string {
+	return "https://sparklemuffin.test/register/confirm/" + token
+}
+
+func newTestService(t *testing.T, config Config) (*Service, *FakeRepository, *notification.FakeNotifier) {
+	t.Helper()
+
+	ur := &user.FakeRepository{}
+	r := &FakeRepository{UserRepository: ur}
+	notifier := &notification.FakeNotifier{}
+
+	s, err := NewService(r, user.NewService(ur, user.FakePasswordHasher(t)), notifier, config, testHmacKey)
+	if err != nil {
+		t.Fatalf("failed to create service: %q", err)
+	}
+
+	return s, r, notifier
+}
+
+func newTestSignup(invitationToken string) Signup {
+	return Signup{
+		InvitationToken:      invitationToken,
+		Email:                "Jane.Doe@Example.org",
+		NickName:             "jane",
+		DisplayName:          "Jane Doe",
+		Password:             "correct horse battery staple",
+		PasswordConfirmation: "correct horse battery staple",
+	}
+}
+
+func TestNewService(t *testing.T) {
+	userService := user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t))
+
+	cases := []struct {
+		tname       string
+		userService *user.Service
+		notifier    notification.Notifier
+		config      Config
+		hmacKey     string
+		wantErr     error
+	}{
+		{
+			tname:       "closed",
+			userService: userService,
+			config:      Config{Mode: ModeClosed},
+			hmacKey:     testHmacKey,
+		},
+		{
+			tname:       "open with email confirmation",
+			userService: userService,
+			notifier:    &notification.FakeNotifier{},
+			config:      Config{Mode: ModeOpen, EmailConfirmation: true},
+			hmacKey:     testHmacKey,
+		},
+		{
+			tname:   "missing user service",
+			config:  Config{Mode: ModeOpen},
+			hmacKey: testHmacKey,
+			wantErr: ErrUserServiceRequired,
+		},
+		{
+			tname:       "invalid mode",
+			userService: userService,
+			config:      Config{Mode: "public"},
+			hmacKey:     testHmacKey,
+			wantErr:     ErrModeInvalid,
+		},
+		{
+			tname:       "email confirmation without notifier",
+			userService: userService,
+			config:      Config{Mode: ModeOpen, EmailConfirmation: true},
+			hmacKey:     testHmacKey,
+			wantErr:     ErrNotifierRequired,
+		},
+		{
+			tname:       "missing HMAC key",
+			userService: userService,
+			config:      Config{Mode: ModeOpen},
+			wantErr:     ErrHmacKeyRequired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			_, err := NewService(&FakeRepository{}, tc.userService, tc.notifier, tc.config, tc.hmacKey)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Mode
+		wantErr error
+	}{
+		{input: "closed", want: ModeClosed},
+		{input: " Invite-Only ", want: ModeInviteOnly},
+		{input: "open", want: ModeOpen},
+		{input: "", wantErr: ErrModeInvalid},
+		{input: "public", wantErr: ErrModeInvalid},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseMode(tc.input)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("want mode %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceInvitationAdd(t *testing.T) {
+	const userUUID = "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b"
+
+	t.Run("invite-only", func(t *testing.T) {
+		s, r, _ := newTestService(t, Config{Mode: ModeInviteOnly})
+
+		invitation, clearToken, err := s.InvitationAdd(t.Context(), userUUID, 3, 7)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(r.Invitations) != 1 {
+			t.Fatalf("want 1 invitation, got %d", len(r.Invitations))
+		}
+		if invitation.TokenHash == clearToken {
+			t.Error("want the token to be stored as a hash")
+		}
+		if invitation.MaxUses != 3 {
+			t.Errorf("want 3 uses, got %d", invitation.MaxUses)
+		}
+
+		got, err := s.InvitationByToken(t.Context(), clearToken)
+		if err != nil {
+			t.Fatalf("want the token to be valid, got %q", err)
+		}
+		if got.UUID != invitation.UUID {
+			t.Errorf("want invitation %q, got %q", invitation.UUID, got.UUID)
+		}
+	})
+
+	cases := []struct {
+		tname        string
+		mode         Mode
+		userUUID     string
+		maxUses      int
+		validityDays int
+		wantErr      error
+	}{
+		{
+			tname:        "open",
+			mode:         ModeOpen,
+			userUUID:     userUUID,
+			maxUses:      1,
+			validityDays: 1,
+			wantErr:      ErrInvitationsDisabled,
+		},
+		{
+			tname:        "missing user",
+			mode:         ModeInviteOnly,
+			maxUses:      1,
+			validityDays: 1,
+			wantErr:      ErrInvitationUserRequired,
+		},
+		{
+			tname:        "no uses",
+			mode:         ModeInviteOnly,
+			userUUID:     userUUID,
+			validityDays: 1,
+			wantErr:      ErrInvitationMaxUsesInvalid,
+		},
+		{
+			tname:        "too many uses",
+			mode:         ModeInviteOnly,
+			userUUID:     userUUID,
+			maxUses:      InvitationMaxUsesLimit + 1,
+			validityDays: 1,
+			wantErr:      ErrInvitationMaxUsesInvalid,
+		},
+		{
+			tname:    "no validity",
+			mode:     ModeInviteOnly,
+			userUUID: userUUID,
+			maxUses:  1,
+			wantErr:  ErrInvitationValidityInvalid,
+		},
+		{
+			tname:        "validity too long",
+			mode:         ModeInviteOnly,
+			userUUID:     userUUID,
+			maxUses:      1,
+			validityDays: InvitationValidityDaysLimit + 1,
+			wantErr:      ErrInvitationValidityInvalid,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s, r, _ := newTestService(t, Config{Mode: tc.mode})
+
+			_, _, err := s.InvitationAdd(t.Context(), tc.userUUID, tc.maxUses, tc.validityDays)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if len(r.Invitations) != 0 {
+				t.Errorf("want no invitation, got %d", len(r.Invitations))
+			}
+		})
+	}
+}
+
+func TestServiceRegister(t *testing.T) {
+	const inviterUUID = "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b"
+
+	t.Run("closed", func(t *testing.T) {
+		s, _, _ := newTestService(t, Config{Mode: ModeClosed})
+
+		_, err := s.Register(t.Context(), newTestSignup(""), testConfirmURL)
+		if !errors.Is(err, ErrRegistrationClosed) {
+			t.Fatalf("want error %q, got %q", ErrRegistrationClosed, err)
+		}
+	})
+
+	t.Run("open", func(t *testing.T) {
+		s, r, notifier := newTestService(t, Config{Mode: ModeOpen})
+
+		userUUID, err := s.Register(t.Context(), newTestSignup(""), testConfirmURL)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		got, err := r.UserRepository.UserGetByEmail(t.Context(), "jane.doe@example.org")
+		if err != nil {
+			t.Fatalf("want the account to be created, got %q", err)
+		}
+		if got.UUID != userUUID {
+			t.Errorf("want user UUID %q, got %q", userUUID, got.UUID)
+		}
+		if got.IsAdmin {
+			t.Error("want a user without administration privileges")
+		}
+		if got.Password != "" || got.PasswordHash == "" {
+			t.Error("want the password to be stored as a hash")
+		}
+		if len(notifier.Messages) != 0 {
+			t.Errorf("want no message, got %d", len(notifier.Messages))
+		}
+	})
+
+	t.Run("open with invalid information", func(t *testing.T) {
+		s, r, _ := newTestService(t, Config{Mode: ModeOpen})
+
+		signup := newTestSignup("")
+		signup.NickName = "not a valid nick"
+
+		_, err := s.Register(t.Context(), signup, testConfirmURL)
+		if !errors.Is(err, user.ErrNickNameInvalid) {
+			t.Fatalf("want error %q, got %q", user.ErrNickNameInvalid, err)
+		}
+		if len(r.UserRepository.Users) != 0 {
+			t.Errorf("want no user, got %d", len(r.UserRepository.Users))
+		}
+	})
+
+	t.Run("password confirmation mismatch", func(t *testing.T) {
+		s, _, _ := newTestService(t, Config{Mode: ModeOpen})
+
+		signup := newTestSignup("")
+		signup.PasswordConfirmation = "incorrect horse battery staple"
+
+		_, err := s.Register(t.Context(), signup, testConfirmURL)
+		if !errors.Is(err, user.ErrPasswordConfirmationMismatch) {
+			t.Fatalf("want error %q, got %q", user.ErrPasswordConfirmationMismatch, err)
+		}
+	})
+
+	t.Run("invite-only without invitation", func(t *testing.T) {
+		s, _, _ := newTestService(t, Config{Mode: ModeInviteOnly})
+
+		_, err := s.Register(t.Context(), newTestSignup(""), testConfirmURL)
+		if !errors.Is(err, ErrInvitationRequired) {
+			t.Fatalf("want error %q, got %q", ErrInvitationRequired, err)
+		}
+	})
+
+	t.Run("invite-only with unknown invitation", func(t *testing.T) {
+		s, _, _ := newTestService(t, Config{Mode: ModeInviteOnly})
+
+		_, err := s.Register(t.Context(), newTestSignup("unknown"), testConfirmURL)
+		if !errors.Is(err, ErrInvitationInvalid) {
+			t.Fatalf("want error %q, got %q", ErrInvitationInvalid, err)
+		}
+	})
+
+	t.Run("invite-only uses the invitation", func(t *testing.T) {
+		s, r, _ := newTestService(t, Config{Mode: ModeInviteOnly})
+
+		_, clearToken, err := s.InvitationAdd(t.Context(), inviterUUID, 1, 1)
+		if err != nil {
+			t.Fatalf("failed to create invitation: %q", err)
+		}
+
+		// invalid information must not use the invitation
+		invalidSignup := newTestSignup(clearToken)
+		invalidSignup.Password = "short"
+		invalidSignup.PasswordConfirmation = "short"
+
+		if _, err := s.Register(t.Context(), invalidSignup, testConfirmURL); !errors.Is(err, user.ErrPasswordTooShort) {
+			t.Fatalf("want error %q, got %q", user.ErrPasswordTooShort, err)
+		}
+		if r.Invitations[0].UseCount != 0 {
+			t.Fatalf("want the invitation to be unused, got %d uses", r.Invitations[0].UseCount)
+		}
+
+		if _, err := s.Register(t.Context(), newTestSignup(clearToken), testConfirmURL); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if r.Invitations[0].UseCount != 1 {
+			t.Errorf("want 1 use, got %d", r.Invitations[0].UseCount)
+		}
+
+		otherSignup := newTestSignup(clearToken)
+		otherSignup.Email = "john.doe@example.org"
+		otherSignup.NickName = "john"
+
+		_, err = s.Register(t.Context(), otherSignup, testConfirmURL)
+		if !errors.Is(err, ErrInvitationInvalid) {
+			t.Fatalf("want error %q, got %q", ErrInvitationInvalid, err)
+		}
+		if len(r.UserRepository.Users) != 1 {
+			t.Errorf("want 1 user, got %d", len(r.UserRepository.Users))
+		}
+	})
+
+	t.Run("email confirmation", func(t *testing.T) {
+		s, r, notifier := newTestService(t, Config{Mode: ModeOpen, EmailConfirmation: true})
+
+		userUUID, err := s.Register(t.Context(), newTestSignup(""), testConfirmURL)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(r.UserRepository.Users) != 0 {
+			t.Fatalf("want no user before confirmation, got %d", len(r.UserRepository.Users))
+		}
+		if len(r.PendingRegistrations) != 1 {
+			t.Fatalf("want 1 pending registration, got %d", len(r.PendingRegistrations))
+		}
+		if len(notifier.Messages) != 1 {
+			t.Fatalf("want 1 message, got %d", len(notifier.Messages))
+		}
+
+		message := notifier.Messages[0]
+		if message.To != "jane.doe@example.org" {
+			t.Errorf("want recipient %q, got %q", "jane.doe@example.org", message.To)
+		}
+
+		matches := testConfirmURLRegex.FindStringSubmatch(message.Body)
+		if matches == nil {
+			t.Fatalf("want a confirmation link, got:\n%s", message.Body)
+		}
+
+		clearToken := matches[1]
+		if r.PendingRegistrations[0].TokenHash == clearToken {
+			t.Error("want the token to be stored as a hash")
+		}
+
+		confirmedUUID, err := s.Confirm(t.Context(), clearToken)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if confirmedUUID != userUUID {
+			t.Errorf("want user UUID %q, got %q", userUUID, confirmedUUID)
+		}
+
+		got, err := r.UserRepository.UserGetByUUID(t.Context(), userUUID)
+		if err != nil {
+			t.Fatalf("want the account to be created, got %q", err)
+		}
+		if got.PasswordHash == "" {
+			t.Error("want the password hash to be set")
+		}
+
+		if _, err := s.Confirm(t.Context(), clearToken); !errors.Is(err, ErrNotFound) {
+			t.Errorf("want error %q, got %q", ErrNotFound, err)
+		}
+	})
+}
+
+func TestServiceConfirm(t *testing.T) {
+	s, _, _ := newTestService(t, Config{Mode: ModeOpen})
+
+	if _, err := s.Confirm(t.Context(), ""); !errors.Is(err, ErrTokenRequired) {
+		t.Errorf("want error %q, got %q", ErrTokenRequired, err)
+	}
+
+	if _, err := s.Confirm(t.Context(), "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %q, got %q", ErrNotFound, err)
+	}
+}
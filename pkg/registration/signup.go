@@ -0,0 +1,36 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package registration
+
+import "time"
+
+// Signup represents the information submitted by a person creating their own account.
+type Signup struct {
+	// InvitationToken is the clear-text token of the Invitation the person received, if any.
+	InvitationToken string
+
+	Email                string
+	NickName             string
+	DisplayName          string
+	Password             string
+	PasswordConfirmation string
+}
+
+// PendingRegistration represents an account awaiting the confirmation of its email address.
+//
+// The account is only created once the address is confirmed; until then, the password is
+// only stored as a hash.
+type PendingRegistration struct {
+	UserUUID     string
+	Email        string
+	NickName     string
+	DisplayName  string
+	PasswordHash string
+
+	// TokenHash is the HMAC hash of the confirmation token sent to the user.
+	TokenHash string
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
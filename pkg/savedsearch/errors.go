@@ -0,0 +1,20 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import "errors"
+
+var (
+	ErrAlreadyRegistered  = errors.New("saved search: already registered")
+	ErrDomainInvalid      = errors.New("saved search: invalid domain")
+	ErrNameRequired       = errors.New("saved search: name required")
+	ErrNotFound           = errors.New("saved search: not found")
+	ErrPublicNotSupported = errors.New("saved search: only bookmark searches can be published")
+	ErrQueryRequired      = errors.New("saved search: query required")
+	ErrSlugInvalid        = errors.New("saved search: invalid slug")
+	ErrSlugRequired       = errors.New("saved search: slug required")
+	ErrUserUUIDRequired   = errors.New("saved search: UserUUID required")
+	ErrUUIDInvalid        = errors.New("saved search: invalid UUID")
+	ErrUUIDRequired       = errors.New("saved search: UUID required")
+)
@@ -0,0 +1,31 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import "context"
+
+// Repository provides access to user saved searches.
+type Repository interface {
+	// SavedSearchAdd adds a new SavedSearch.
+	SavedSearchAdd(ctx context.Context, s SavedSearch) error
+
+	// SavedSearchDelete deletes a given SavedSearch.
+	SavedSearchDelete(ctx context.Context, userUUID string, searchUUID string) error
+
+	// SavedSearchGetByDomain returns all saved searches for a given user and Domain, sorted by name.
+	SavedSearchGetByDomain(ctx context.Context, userUUID string, domain Domain) ([]SavedSearch, error)
+
+	// SavedSearchGetBySlug returns the SavedSearch with a given slug, for a given user and Domain.
+	SavedSearchGetBySlug(ctx context.Context, userUUID string, domain Domain, slug string) (SavedSearch, error)
+
+	// SavedSearchGetByUUID returns a given SavedSearch.
+	SavedSearchGetByUUID(ctx context.Context, userUUID string, searchUUID string) (SavedSearch, error)
+
+	// SavedSearchIsRegistered returns whether another SavedSearch with the same Domain and name
+	// or slug is registered for the same user.
+	SavedSearchIsRegistered(ctx context.Context, s SavedSearch) (bool, error)
+
+	// SavedSearchUpdate updates an existing SavedSearch.
+	SavedSearchUpdate(ctx context.Context, s SavedSearch) error
+}
@@ -0,0 +1,93 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+var _ Repository = &FakeRepository{}
+
+type FakeRepository struct {
+	SavedSearches []SavedSearch
+}
+
+func (r *FakeRepository) SavedSearchAdd(_ context.Context, s SavedSearch) error {
+	r.SavedSearches = append(r.SavedSearches, s)
+	return nil
+}
+
+func (r *FakeRepository) SavedSearchDelete(_ context.Context, userUUID string, searchUUID string) error {
+	for index, s := range r.SavedSearches {
+		if s.UserUUID == userUUID && s.UUID == searchUUID {
+			r.SavedSearches = slices.Delete(r.SavedSearches, index, index+1)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) SavedSearchGetByDomain(_ context.Context, userUUID string, domain Domain) ([]SavedSearch, error) {
+	var searches []SavedSearch
+
+	for _, s := range r.SavedSearches {
+		if s.UserUUID == userUUID && s.Domain == domain {
+			searches = append(searches, s)
+		}
+	}
+
+	slices.SortFunc(searches, func(a, b SavedSearch) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return searches, nil
+}
+
+func (r *FakeRepository) SavedSearchGetBySlug(_ context.Context, userUUID string, domain Domain, slug string) (SavedSearch, error) {
+	for _, s := range r.SavedSearches {
+		if s.UserUUID == userUUID && s.Domain == domain && s.Slug == slug {
+			return s, nil
+		}
+	}
+
+	return SavedSearch{}, ErrNotFound
+}
+
+func (r *FakeRepository) SavedSearchGetByUUID(_ context.Context, userUUID string, searchUUID string) (SavedSearch, error) {
+	for _, s := range r.SavedSearches {
+		if s.UserUUID == userUUID && s.UUID == searchUUID {
+			return s, nil
+		}
+	}
+
+	return SavedSearch{}, ErrNotFound
+}
+
+func (r *FakeRepository) SavedSearchIsRegistered(_ context.Context, s SavedSearch) (bool, error) {
+	for _, existing := range r.SavedSearches {
+		if existing.UserUUID != s.UserUUID || existing.Domain != s.Domain || existing.UUID == s.UUID {
+			continue
+		}
+
+		if existing.Name == s.Name || existing.Slug == s.Slug {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *FakeRepository) SavedSearchUpdate(_ context.Context, s SavedSearch) error {
+	for index, existing := range r.SavedSearches {
+		if existing.UserUUID == s.UserUUID && existing.UUID == s.UUID {
+			r.SavedSearches[index] = s
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
@@ -0,0 +1,205 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+
+	bookmarkquerying "github.com/virtualtam/sparklemuffin/pkg/bookmark/querying"
+	feedquerying "github.com/virtualtam/sparklemuffin/pkg/feed/querying"
+	"github.com/virtualtam/sparklemuffin/pkg/search"
+)
+
+// Domain represents the kind of items a SavedSearch applies to.
+type Domain string
+
+const (
+	// DomainBookmarks indicates the SavedSearch applies to the user's bookmarks.
+	DomainBookmarks Domain = "bookmarks"
+
+	// DomainFeeds indicates the SavedSearch applies to the entries of the user's feed subscriptions.
+	DomainFeeds Domain = "feeds"
+)
+
+var (
+	// AllDomains lists all supported domains.
+	AllDomains = []Domain{
+		DomainBookmarks,
+		DomainFeeds,
+	}
+)
+
+// SavedSearch represents a named search query that a user can run again, e.g. from the
+// navigation menu.
+type SavedSearch struct {
+	UUID     string
+	UserUUID string
+
+	Domain Domain
+	Name   string
+	Slug   string
+
+	// Query holds the search terms and operators, using the search query language.
+	Query string
+
+	// Public indicates whether a bookmark search is published as a public page and Atom feed.
+	Public bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewSavedSearch initializes and returns a new SavedSearch with a random UUID.
+func NewSavedSearch(userUUID string, domain Domain) (SavedSearch, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	now := time.Now().UTC()
+
+	return SavedSearch{
+		UUID:      generatedUUID.String(),
+		UserUUID:  userUUID,
+		Domain:    domain,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (s *SavedSearch) Normalize() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Query = strings.TrimSpace(s.Query)
+	s.slugify()
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when adding a SavedSearch.
+func (s *SavedSearch) ValidateForAddition() error {
+	fns := []func() error{
+		s.requireUUID,
+		s.validateUUID,
+		s.requireUserUUID,
+		s.ensureDomainIsKnown,
+		s.requireName,
+		s.requireSlug,
+		s.validateSlug,
+		s.requireQuery,
+		s.ensureQueryIsValid,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+// ValidateForUpdate ensures mandatory fields are properly set when updating a SavedSearch.
+func (s *SavedSearch) ValidateForUpdate() error {
+	return s.ValidateForAddition()
+}
+
+// ValidateForDeletion ensures mandatory fields are properly set when deleting a SavedSearch.
+func (s *SavedSearch) ValidateForDeletion() error {
+	fns := []func() error{
+		s.requireUUID,
+		s.validateUUID,
+		s.requireUserUUID,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+// searchFilters returns the search operators supported by this SavedSearch's domain and visibility.
+func (s *SavedSearch) searchFilters() []search.Filter {
+	switch {
+	case s.Domain == DomainFeeds:
+		return feedquerying.EntrySearchFilters
+	case s.Public:
+		return bookmarkquerying.PublicBookmarkSearchFilters
+	default:
+		return bookmarkquerying.BookmarkSearchFilters
+	}
+}
+
+func (s *SavedSearch) slugify() {
+	s.Slug = slug.Make(strings.ToLower(s.Name))
+}
+
+func (s *SavedSearch) ensureDomainIsKnown() error {
+	if !slices.Contains(AllDomains, s.Domain) {
+		return ErrDomainInvalid
+	}
+	return nil
+}
+
+func (s *SavedSearch) ensureQueryIsValid() error {
+	if s.Public && s.Domain != DomainBookmarks {
+		return ErrPublicNotSupported
+	}
+
+	_, err := search.Parse(s.Query, s.searchFilters()...)
+	return err
+}
+
+func (s *SavedSearch) requireName() error {
+	if s.Name == "" {
+		return ErrNameRequired
+	}
+	return nil
+}
+
+func (s *SavedSearch) requireQuery() error {
+	if s.Query == "" {
+		return ErrQueryRequired
+	}
+	return nil
+}
+
+func (s *SavedSearch) requireSlug() error {
+	if s.Slug == "" {
+		return ErrSlugRequired
+	}
+	return nil
+}
+
+func (s *SavedSearch) requireUserUUID() error {
+	if s.UserUUID == "" {
+		return ErrUserUUIDRequired
+	}
+	return nil
+}
+
+func (s *SavedSearch) requireUUID() error {
+	if s.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
+
+func (s *SavedSearch) validateSlug() error {
+	if !slug.IsSlug(s.Slug) {
+		return ErrSlugInvalid
+	}
+	return nil
+}
+
+func (s *SavedSearch) validateUUID() error {
+	if err := uuid.Validate(s.UUID); err != nil {
+		return ErrUUIDInvalid
+	}
+	return nil
+}
+
+func runValidationFuncs(fns []func() error) error {
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
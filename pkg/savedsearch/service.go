@@ -0,0 +1,134 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import (
+	"context"
+	"time"
+)
+
+// Service handles operations related to user saved searches.
+type Service struct {
+	r Repository
+}
+
+// NewService initializes and returns a SavedSearch Service.
+func NewService(r Repository) *Service {
+	return &Service{
+		r: r,
+	}
+}
+
+// Add saves a new search for a given user.
+//
+// A search.QueryError is returned if the query is malformed, or uses search operators
+// that are not supported by the search's Domain.
+func (s *Service) Add(ctx context.Context, search SavedSearch) (SavedSearch, error) {
+	newSearch, err := NewSavedSearch(search.UserUUID, search.Domain)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	newSearch.Name = search.Name
+	newSearch.Query = search.Query
+	newSearch.Public = search.Public
+
+	newSearch.Normalize()
+
+	if err := newSearch.ValidateForAddition(); err != nil {
+		return SavedSearch{}, err
+	}
+
+	if err := s.ensureSearchIsNotRegistered(ctx, newSearch); err != nil {
+		return SavedSearch{}, err
+	}
+
+	if err := s.r.SavedSearchAdd(ctx, newSearch); err != nil {
+		return SavedSearch{}, err
+	}
+
+	return newSearch, nil
+}
+
+// ByDomain returns all saved searches for a given user and Domain.
+func (s *Service) ByDomain(ctx context.Context, userUUID string, domain Domain) ([]SavedSearch, error) {
+	return s.r.SavedSearchGetByDomain(ctx, userUUID, domain)
+}
+
+// ByUUID returns a given SavedSearch.
+func (s *Service) ByUUID(ctx context.Context, userUUID string, searchUUID string) (SavedSearch, error) {
+	search := SavedSearch{
+		UUID:     searchUUID,
+		UserUUID: userUUID,
+	}
+
+	if err := search.ValidateForDeletion(); err != nil {
+		return SavedSearch{}, err
+	}
+
+	return s.r.SavedSearchGetByUUID(ctx, userUUID, searchUUID)
+}
+
+// PublicBookmarkSearchBySlug returns a given published bookmark search.
+//
+// ErrNotFound is returned if the search exists but has not been published.
+func (s *Service) PublicBookmarkSearchBySlug(ctx context.Context, userUUID string, slug string) (SavedSearch, error) {
+	search, err := s.r.SavedSearchGetBySlug(ctx, userUUID, DomainBookmarks, slug)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	if !search.Public {
+		return SavedSearch{}, ErrNotFound
+	}
+
+	return search, nil
+}
+
+// Delete deletes a given SavedSearch.
+func (s *Service) Delete(ctx context.Context, userUUID string, searchUUID string) error {
+	if _, err := s.ByUUID(ctx, userUUID, searchUUID); err != nil {
+		return err
+	}
+
+	return s.r.SavedSearchDelete(ctx, userUUID, searchUUID)
+}
+
+// Update updates an existing SavedSearch's name, query and visibility.
+func (s *Service) Update(ctx context.Context, search SavedSearch) error {
+	existing, err := s.ByUUID(ctx, search.UserUUID, search.UUID)
+	if err != nil {
+		return err
+	}
+
+	existing.Name = search.Name
+	existing.Query = search.Query
+	existing.Public = search.Public
+	existing.UpdatedAt = time.Now().UTC()
+
+	existing.Normalize()
+
+	if err := existing.ValidateForUpdate(); err != nil {
+		return err
+	}
+
+	if err := s.ensureSearchIsNotRegistered(ctx, existing); err != nil {
+		return err
+	}
+
+	return s.r.SavedSearchUpdate(ctx, existing)
+}
+
+func (s *Service) ensureSearchIsNotRegistered(ctx context.Context, search SavedSearch) error {
+	registered, err := s.r.SavedSearchIsRegistered(ctx, search)
+	if err != nil {
+		return err
+	}
+
+	if registered {
+		return ErrAlreadyRegistered
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package savedsearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/pkg/search"
+)
+
+const (
+	testUserUUID = "179206c8-2965-47a7-ba04-bf0a6a0b8d11"
+)
+
+func TestServiceAdd(t *testing.T) {
+	existingSearch := SavedSearch{
+		UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+		UserUUID: testUserUUID,
+		Domain:   DomainBookmarks,
+		Name:     "Go testing",
+		Slug:     "go-testing",
+		Query:    "tag:go tag:testing",
+	}
+
+	cases := []struct {
+		tname    string
+		search   SavedSearch
+		wantSlug string
+		wantErr  error
+	}{
+		// nominal cases
+		{
+			tname: "bookmark search",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "  Private Go links  ",
+				Query:    "  tag:go is:private ",
+			},
+			wantSlug: "private-go-links",
+		},
+		{
+			tname: "public bookmark search",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "PostgreSQL",
+				Query:    "tag:postgresql -tag:video",
+				Public:   true,
+			},
+			wantSlug: "postgresql",
+		},
+		{
+			tname: "feed search with the same name as a bookmark search",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainFeeds,
+				Name:     "Go testing",
+				Query:    "category:dev golang testing is:unread",
+			},
+			wantSlug: "go-testing",
+		},
+
+		// error cases
+		{
+			tname: "unknown domain",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   "videos",
+				Name:     "Talks",
+				Query:    "conference",
+			},
+			wantErr: ErrDomainInvalid,
+		},
+		{
+			tname: "missing name",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Query:    "tag:go",
+			},
+			wantErr: ErrNameRequired,
+		},
+		{
+			tname: "missing query",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Everything",
+				Query:    "   ",
+			},
+			wantErr: ErrQueryRequired,
+		},
+		{
+			tname: "malformed query",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Unfinished",
+				Query:    `"error handling`,
+			},
+			wantErr: search.ErrQueryInvalid,
+		},
+		{
+			tname: "operator not supported by the domain",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainFeeds,
+				Name:     "Tagged",
+				Query:    "tag:go",
+			},
+			wantErr: search.ErrQueryInvalid,
+		},
+		{
+			tname: "public search restricted to private bookmarks",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Secrets",
+				Query:    "is:private",
+				Public:   true,
+			},
+			wantErr: search.ErrQueryInvalid,
+		},
+		{
+			tname: "public feed search",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainFeeds,
+				Name:     "News",
+				Query:    "category:news",
+				Public:   true,
+			},
+			wantErr: ErrPublicNotSupported,
+		},
+		{
+			tname: "duplicate slug",
+			search: SavedSearch{
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Go Testing!",
+				Query:    "golang testing",
+			},
+			wantErr: ErrAlreadyRegistered,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				SavedSearches: []SavedSearch{existingSearch},
+			}
+			s := NewService(r)
+
+			got, err := s.Add(t.Context(), tc.search)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				if len(r.SavedSearches) != 1 {
+					t.Errorf("want no saved search to be added, got %d", len(r.SavedSearches)-1)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.UUID == "" {
+				t.Error("want a generated UUID")
+			}
+			if got.Slug != tc.wantSlug {
+				t.Errorf("want slug %q, got %q", tc.wantSlug, got.Slug)
+			}
+			if len(r.SavedSearches) != 2 {
+				t.Fatalf("want 2 saved searches, got %d", len(r.SavedSearches))
+			}
+		})
+	}
+}
+
+func TestServiceUpdate(t *testing.T) {
+	existingSearches := []SavedSearch{
+		{
+			UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+			UserUUID: testUserUUID,
+			Domain:   DomainBookmarks,
+			Name:     "Go testing",
+			Slug:     "go-testing",
+			Query:    "tag:go tag:testing",
+		},
+		{
+			UUID:     "0d4b8f6a-7e2c-4f3b-8a1d-5c6e9f0a1b2c",
+			UserUUID: testUserUUID,
+			Domain:   DomainBookmarks,
+			Name:     "Databases",
+			Slug:     "databases",
+			Query:    "tag:database",
+		},
+	}
+
+	cases := []struct {
+		tname   string
+		search  SavedSearch
+		wantErr error
+	}{
+		{
+			tname: "rename and publish",
+			search: SavedSearch{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testUserUUID,
+				Name:     "Testing in Go",
+				Query:    "tag:go tag:testing",
+				Public:   true,
+			},
+		},
+		{
+			tname: "keep the same name",
+			search: SavedSearch{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testUserUUID,
+				Name:     "Go testing",
+				Query:    "tag:go tag:testing after:2024-01-01",
+			},
+		},
+		{
+			tname: "name registered to another search",
+			search: SavedSearch{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testUserUUID,
+				Name:     "Databases",
+				Query:    "tag:go",
+			},
+			wantErr: ErrAlreadyRegistered,
+		},
+		{
+			tname: "not found",
+			search: SavedSearch{
+				UUID:     "3c7e1a9b-2d4f-4e6a-8b0c-9d1e2f3a4b5c",
+				UserUUID: testUserUUID,
+				Name:     "Missing",
+				Query:    "tag:go",
+			},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				SavedSearches: append([]SavedSearch{}, existingSearches...),
+			}
+			s := NewService(r)
+
+			err := s.Update(t.Context(), tc.search)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			got, err := r.SavedSearchGetByUUID(t.Context(), tc.search.UserUUID, tc.search.UUID)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.Name != tc.search.Name {
+				t.Errorf("want name %q, got %q", tc.search.Name, got.Name)
+			}
+			if got.Query != tc.search.Query {
+				t.Errorf("want query %q, got %q", tc.search.Query, got.Query)
+			}
+			if got.Public != tc.search.Public {
+				t.Errorf("want public %t, got %t", tc.search.Public, got.Public)
+			}
+			if got.Domain != DomainBookmarks {
+				t.Errorf("want the domain to be preserved, got %q", got.Domain)
+			}
+		})
+	}
+}
+
+func TestServicePublicBookmarkSearchBySlug(t *testing.T) {
+	r := &FakeRepository{
+		SavedSearches: []SavedSearch{
+			{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Go testing",
+				Slug:     "go-testing",
+				Query:    "tag:go tag:testing",
+				Public:   true,
+			},
+			{
+				UUID:     "0d4b8f6a-7e2c-4f3b-8a1d-5c6e9f0a1b2c",
+				UserUUID: testUserUUID,
+				Domain:   DomainBookmarks,
+				Name:     "Databases",
+				Slug:     "databases",
+				Query:    "tag:database",
+			},
+		},
+	}
+	s := NewService(r)
+
+	cases := []struct {
+		tname   string
+		slug    string
+		wantErr error
+	}{
+		{
+			tname: "public search",
+			slug:  "go-testing",
+		},
+		{
+			tname:   "private search",
+			slug:    "databases",
+			wantErr: ErrNotFound,
+		},
+		{
+			tname:   "unknown search",
+			slug:    "unknown",
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got, err := s.PublicBookmarkSearchBySlug(t.Context(), testUserUUID, tc.slug)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.Slug != tc.slug {
+				t.Errorf("want slug %q, got %q", tc.slug, got.Slug)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package search
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrQueryInvalid = errors.New("search: invalid query")
+)
+
+// A QueryError describes why a search query could not be parsed.
+//
+// It wraps ErrQueryInvalid, and its Reason is meant to be displayed to the user.
+type QueryError struct {
+	Reason string
+}
+
+func newQueryError(format string, a ...any) error {
+	return &QueryError{Reason: fmt.Sprintf(format, a...)}
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrQueryInvalid, e.Reason)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrQueryInvalid
+}
@@ -0,0 +1,220 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package search
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	dateLayout = "2006-01-02"
+)
+
+var (
+	siteRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+	isFilters = []Filter{FilterPrivate, FilterPublic, FilterRead, FilterUnread}
+
+	operatorNames = []string{
+		string(FilterTag),
+		string(FilterSite),
+		string(FilterBefore),
+		string(FilterAfter),
+		string(FilterFeed),
+		string(FilterCategory),
+		"is",
+	}
+)
+
+// Parse parses a search query, e.g. `golang tag:dev -tag:video after:2024-01-01`.
+//
+// Operators that are not part of the allowed filters are rejected, as well as malformed
+// values; tokens that are not operators make up the free text terms of the Query, including
+// tokens that merely look like one, e.g. `TODO:` or `https://example.com`.
+func Parse(input string, allowed ...Filter) (Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var (
+		query Query
+		terms []string
+	)
+
+	for _, token := range tokens {
+		negated := strings.HasPrefix(token, "-")
+
+		name, value, found := strings.Cut(strings.TrimPrefix(token, "-"), ":")
+		name = strings.ToLower(name)
+
+		if !found || !slices.Contains(operatorNames, name) || strings.HasPrefix(value, "//") {
+			// free text, including URLs such as https://example.com
+			terms = append(terms, token)
+			continue
+		}
+
+		if err := query.apply(name, unquote(value), negated, allowed); err != nil {
+			return Query{}, err
+		}
+	}
+
+	query.Terms = strings.Join(terms, " ")
+
+	if err := query.validate(); err != nil {
+		return Query{}, err
+	}
+
+	return query, nil
+}
+
+// tokenize splits a search query on whitespace, keeping quoted strings together.
+func tokenize(input string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+	)
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if quoted {
+		return []string{}, newQueryError("missing closing quote")
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func (q *Query) apply(name string, value string, negated bool, allowed []Filter) error {
+	filter := Filter(name)
+
+	if name == "is" {
+		filter = Filter("is:" + strings.ToLower(value))
+		if value != "" && !slices.Contains(isFilters, filter) {
+			return newQueryError("unknown value %q for is:, expected one of: private, public, read, unread", value)
+		}
+	}
+
+	if value == "" {
+		return newQueryError("missing value for %s:", name)
+	}
+
+	if !slices.Contains(allowed, filter) {
+		return newQueryError("%s cannot be used in this search", operatorLabel(filter))
+	}
+
+	if negated && filter != FilterTag {
+		return newQueryError("-%s cannot be negated, only tags can be excluded", operatorLabel(filter))
+	}
+
+	switch filter {
+	case FilterTag:
+		if negated {
+			q.ExcludedTags = append(q.ExcludedTags, value)
+		} else {
+			q.Tags = append(q.Tags, value)
+		}
+
+	case FilterSite:
+		site := strings.TrimPrefix(strings.ToLower(value), "www.")
+		if !siteRegexp.MatchString(site) {
+			return newQueryError("invalid site %q, expected a domain name such as example.com", value)
+		}
+		q.Sites = append(q.Sites, site)
+
+	case FilterBefore, FilterAfter:
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return newQueryError("invalid date %q for %s:, expected YYYY-MM-DD", value, name)
+		}
+
+		target := &q.After
+		if filter == FilterBefore {
+			target = &q.Before
+		}
+		if !target.IsZero() {
+			return newQueryError("%s: can only be used once", name)
+		}
+		*target = date
+
+	case FilterFeed:
+		q.Feeds = append(q.Feeds, strings.ToLower(value))
+
+	case FilterCategory:
+		q.Categories = append(q.Categories, strings.ToLower(value))
+
+	case FilterPrivate:
+		q.Private = true
+
+	case FilterPublic:
+		q.Public = true
+
+	case FilterRead:
+		q.Read = true
+
+	case FilterUnread:
+		q.Unread = true
+	}
+
+	return nil
+}
+
+func (q *Query) validate() error {
+	if q.Private && q.Public {
+		return newQueryError("is:private and is:public cannot be combined")
+	}
+
+	if q.Read && q.Unread {
+		return newQueryError("is:read and is:unread cannot be combined")
+	}
+
+	if !q.After.IsZero() && !q.Before.IsZero() && !q.After.Before(q.Before) {
+		return newQueryError("the after: date must be earlier than the before: date")
+	}
+
+	for _, tag := range q.Tags {
+		if slices.Contains(q.ExcludedTags, tag) {
+			return newQueryError("tag %q cannot be both included and excluded", tag)
+		}
+	}
+
+	return nil
+}
+
+func operatorLabel(filter Filter) string {
+	if strings.HasPrefix(string(filter), "is:") {
+		return string(filter)
+	}
+
+	return string(filter) + ":"
+}
@@ -0,0 +1,248 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	allFilters = []Filter{
+		FilterTag,
+		FilterSite,
+		FilterBefore,
+		FilterAfter,
+		FilterFeed,
+		FilterCategory,
+		FilterPrivate,
+		FilterPublic,
+		FilterRead,
+		FilterUnread,
+	}
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		tname   string
+		input   string
+		allowed []Filter
+		want    Query
+	}{
+		{
+			tname:   "empty",
+			input:   "",
+			allowed: allFilters,
+		},
+		{
+			tname:   "free text",
+			input:   `  golang "error handling"  -video `,
+			allowed: allFilters,
+			want: Query{
+				Terms: `golang "error handling" -video`,
+			},
+		},
+		{
+			tname:   "URL as free text",
+			input:   "https://example.com/path",
+			allowed: allFilters,
+			want: Query{
+				Terms: "https://example.com/path",
+			},
+		},
+		{
+			tname:   "unknown operators as free text",
+			input:   "TODO: fix error: timeout author:me",
+			allowed: allFilters,
+			want: Query{
+				Terms: "TODO: fix error: timeout author:me",
+			},
+		},
+		{
+			tname:   "URL scheme as free text",
+			input:   "https: mailto:jane@example.com tag:go",
+			allowed: allFilters,
+			want: Query{
+				Terms: "https: mailto:jane@example.com",
+				Tags:  []string{"go"},
+			},
+		},
+		{
+			tname:   "tags",
+			input:   "tag:dev TAG:go -tag:video",
+			allowed: allFilters,
+			want: Query{
+				Tags:         []string{"dev", "go"},
+				ExcludedTags: []string{"video"},
+			},
+		},
+		{
+			tname:   "sites",
+			input:   "site:Example.com site:www.go.dev",
+			allowed: allFilters,
+			want: Query{
+				Sites: []string{"example.com", "go.dev"},
+			},
+		},
+		{
+			tname:   "dates",
+			input:   "after:2024-01-01 before:2024-02-01",
+			allowed: allFilters,
+			want: Query{
+				After:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Before: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			tname:   "feeds and categories",
+			input:   `feed:"Hacker News" category:Dev`,
+			allowed: allFilters,
+			want: Query{
+				Feeds:      []string{"hacker news"},
+				Categories: []string{"dev"},
+			},
+		},
+		{
+			tname:   "status",
+			input:   "is:private is:Unread",
+			allowed: allFilters,
+			want: Query{
+				Private: true,
+				Unread:  true,
+			},
+		},
+		{
+			tname:   "mixed",
+			input:   "postgresql tag:database is:public tuning",
+			allowed: allFilters,
+			want: Query{
+				Terms:  "postgresql tuning",
+				Tags:   []string{"database"},
+				Public: true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			got, err := Parse(tc.input, tc.allowed...)
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		tname      string
+		input      string
+		allowed    []Filter
+		wantReason string
+	}{
+		{
+			tname:      "missing closing quote",
+			input:      `tag:go "error handling`,
+			allowed:    allFilters,
+			wantReason: "missing closing quote",
+		},
+		{
+			tname:      "missing value",
+			input:      "tag:",
+			allowed:    allFilters,
+			wantReason: "missing value for tag:",
+		},
+		{
+			tname:      "unknown is: value",
+			input:      "is:starred",
+			allowed:    allFilters,
+			wantReason: `unknown value "starred" for is:, expected one of: private, public, read, unread`,
+		},
+		{
+			tname:      "operator not allowed",
+			input:      "feed:lwn",
+			allowed:    []Filter{FilterTag},
+			wantReason: "feed: cannot be used in this search",
+		},
+		{
+			tname:      "is: value not allowed",
+			input:      "is:unread",
+			allowed:    []Filter{FilterPrivate, FilterPublic},
+			wantReason: "is:unread cannot be used in this search",
+		},
+		{
+			tname:      "negated operator",
+			input:      "-site:example.com",
+			allowed:    allFilters,
+			wantReason: "-site: cannot be negated, only tags can be excluded",
+		},
+		{
+			tname:      "invalid site",
+			input:      "site:https:example.com",
+			allowed:    allFilters,
+			wantReason: `invalid site "https:example.com", expected a domain name such as example.com`,
+		},
+		{
+			tname:      "invalid date",
+			input:      "before:yesterday",
+			allowed:    allFilters,
+			wantReason: `invalid date "yesterday" for before:, expected YYYY-MM-DD`,
+		},
+		{
+			tname:      "duplicate date",
+			input:      "after:2024-01-01 after:2024-02-01",
+			allowed:    allFilters,
+			wantReason: "after: can only be used once",
+		},
+		{
+			tname:      "empty date range",
+			input:      "after:2024-02-01 before:2024-02-01",
+			allowed:    allFilters,
+			wantReason: "the after: date must be earlier than the before: date",
+		},
+		{
+			tname:      "conflicting visibility",
+			input:      "is:private is:public",
+			allowed:    allFilters,
+			wantReason: "is:private and is:public cannot be combined",
+		},
+		{
+			tname:      "conflicting read status",
+			input:      "is:read is:unread",
+			allowed:    allFilters,
+			wantReason: "is:read and is:unread cannot be combined",
+		},
+		{
+			tname:      "tag included and excluded",
+			input:      "tag:go -tag:go",
+			allowed:    allFilters,
+			wantReason: `tag "go" cannot be both included and excluded`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			_, err := Parse(tc.input, tc.allowed...)
+
+			if !errors.Is(err, ErrQueryInvalid) {
+				t.Fatalf("want error %q, got %v", ErrQueryInvalid, err)
+			}
+
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("want a QueryError, got %T", err)
+			}
+
+			if queryErr.Reason != tc.wantReason {
+				t.Errorf("want reason %q, got %q", tc.wantReason, queryErr.Reason)
+			}
+		})
+	}
+}
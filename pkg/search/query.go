@@ -0,0 +1,76 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package search
+
+import (
+	"strings"
+	"time"
+)
+
+// A Filter identifies a search operator, e.g. tag: or site:, or a value of the is: operator.
+type Filter string
+
+const (
+	// FilterTag restricts results to bookmarks with a given tag, or without it when negated.
+	FilterTag Filter = "tag"
+
+	// FilterSite restricts results to URLs of a given domain and its subdomains.
+	FilterSite Filter = "site"
+
+	// FilterBefore restricts results to items dated before a given day.
+	FilterBefore Filter = "before"
+
+	// FilterAfter restricts results to items dated on or after a given day.
+	FilterAfter Filter = "after"
+
+	// FilterFeed restricts results to entries of a given feed.
+	FilterFeed Filter = "feed"
+
+	// FilterCategory restricts results to entries of feeds in a given category.
+	FilterCategory Filter = "category"
+
+	// FilterPrivate restricts results to private bookmarks.
+	FilterPrivate Filter = "is:private"
+
+	// FilterPublic restricts results to public bookmarks.
+	FilterPublic Filter = "is:public"
+
+	// FilterRead restricts results to read feed entries.
+	FilterRead Filter = "is:read"
+
+	// FilterUnread restricts results to unread feed entries.
+	FilterUnread Filter = "is:unread"
+)
+
+// A Query represents a parsed search query.
+//
+// Each set field restricts the results further; values of the same operator are combined
+// with AND for tags, and with OR for sites, feeds and categories.
+type Query struct {
+	// Terms holds the free text part of the query, in PostgreSQL's web search syntax:
+	// "quoted phrases", -excluded words and OR are supported.
+	Terms string
+
+	Tags         []string
+	ExcludedTags []string
+	Sites        []string
+	Feeds        []string
+	Categories   []string
+
+	Private bool
+	Public  bool
+	Read    bool
+	Unread  bool
+
+	// Before is the first day excluded from the results, if set.
+	Before time.Time
+
+	// After is the first day included in the results, if set.
+	After time.Time
+}
+
+// HasTerms returns whether the query contains free text to match.
+func (q Query) HasTerms() bool {
+	return strings.TrimSpace(q.Terms) != ""
+}
@@ -10,4 +10,5 @@ var (
 	ErrNotFound                  = errors.New("session: not found")
 	ErrRememberTokenRequired     = errors.New("session: remember token required")
 	ErrRememberTokenHashRequired = errors.New("session: remember token hash required")
+	ErrUUIDRequired              = errors.New("session: UUID required")
 )
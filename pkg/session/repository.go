@@ -16,10 +16,26 @@ type Repository interface {
 	// given remember token hash.
 	SessionGetByRememberTokenHash(ctx context.Context, hash string) (Session, error)
 
+	// SessionGetByUserUUID returns all active Sessions belonging to a given user, most
+	// recently used first.
+	SessionGetByUserUUID(ctx context.Context, userUUID string) ([]Session, error)
+
+	// SessionUpdateLastSeen records the last activity of a Session.
+	SessionUpdateLastSeen(ctx context.Context, update LastSeenUpdate) error
+
 	// SessionDeleteByRememberTokenHash deletes the Session corresponding to a
 	// given remember token hash.
 	SessionDeleteByRememberTokenHash(ctx context.Context, hash string) error
 
+	// SessionDeleteByUUID deletes a Session belonging to a given user.
+	//
+	// It returns ErrNotFound if the user has no such Session.
+	SessionDeleteByUUID(ctx context.Context, userUUID string, sessionUUID string) error
+
 	// SessionDeleteByUserUUID deletes all Sessions belonging to a given user.
 	SessionDeleteByUserUUID(ctx context.Context, userUUID string) error
+
+	// SessionDeleteByUserUUIDExcept deletes all Sessions belonging to a given user, except
+	// the Session with the given UUID.
+	SessionDeleteByUserUUIDExcept(ctx context.Context, userUUID string, sessionUUID string) error
 }
@@ -31,6 +31,38 @@ func (r *FakeRepository) SessionGetByRememberTokenHash(_ context.Context, hash s
 	return Session{}, ErrNotFound
 }
 
+func (r *FakeRepository) SessionGetByUserUUID(_ context.Context, userUUID string) ([]Session, error) {
+	var sessions []Session
+
+	for _, s := range r.Sessions {
+		if s.UserUUID == userUUID && s.RememberTokenExpiresAt.After(time.Now()) {
+			sessions = append(sessions, s)
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (r *FakeRepository) SessionUpdateLastSeen(_ context.Context, update LastSeenUpdate) error {
+	for index, s := range r.Sessions {
+		if s.UUID != update.UUID {
+			continue
+		}
+
+		r.Sessions[index].ClientIP = update.ClientIP
+		r.Sessions[index].UserAgent = update.UserAgent
+		r.Sessions[index].LastSeenAt = update.LastSeenAt
+
+		return nil
+	}
+
+	return ErrNotFound
+}
+
 func (r *FakeRepository) SessionDeleteByRememberTokenHash(_ context.Context, hash string) error {
 	r.Sessions = slices.DeleteFunc(r.Sessions, func(s Session) bool {
 		return s.RememberTokenHash == hash
@@ -38,6 +70,20 @@ func (r *FakeRepository) SessionDeleteByRememberTokenHash(_ context.Context, has
 	return nil
 }
 
+func (r *FakeRepository) SessionDeleteByUUID(_ context.Context, userUUID string, sessionUUID string) error {
+	nSessions := len(r.Sessions)
+
+	r.Sessions = slices.DeleteFunc(r.Sessions, func(s Session) bool {
+		return s.UserUUID == userUUID && s.UUID == sessionUUID
+	})
+
+	if len(r.Sessions) == nSessions {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *FakeRepository) SessionDeleteByUserUUID(_ context.Context, userUUID string) error {
 	if r.SessionDeleteByUserUUIDErr != nil {
 		return r.SessionDeleteByUserUUIDErr
@@ -48,3 +94,10 @@ func (r *FakeRepository) SessionDeleteByUserUUID(_ context.Context, userUUID str
 	})
 	return nil
 }
+
+func (r *FakeRepository) SessionDeleteByUserUUIDExcept(_ context.Context, userUUID string, sessionUUID string) error {
+	r.Sessions = slices.DeleteFunc(r.Sessions, func(s Session) bool {
+		return s.UserUUID == userUUID && s.UUID != sessionUUID
+	})
+	return nil
+}
@@ -5,6 +5,9 @@ package session
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/virtualtam/sparklemuffin/internal/hash"
 	"github.com/virtualtam/sparklemuffin/pkg/user"
@@ -30,6 +33,8 @@ func NewService(r Repository, hmacKey string) (*Service, error) {
 }
 
 // Add saves a new Session.
+//
+// The Session is given a UUID and its creation time is recorded if they are not set.
 func (s *Service) Add(ctx context.Context, session Session) error {
 	err := s.runValidationFuncs(
 		&session,
@@ -37,6 +42,9 @@ func (s *Service) Add(ctx context.Context, session Session) error {
 		s.requireRememberToken,
 		s.hashRememberToken,
 		s.requireRememberTokenHash,
+		s.ensureUUID,
+		s.ensureTimestamps,
+		s.normalizeUserAgent,
 	)
 	if err != nil {
 		return err
@@ -62,6 +70,78 @@ func (s *Service) ByRememberToken(ctx context.Context, rememberToken string) (Se
 	return s.r.SessionGetByRememberTokenHash(ctx, session.RememberTokenHash)
 }
 
+// ByUserUUID returns all active Sessions belonging to a given user, most recently used first.
+func (s *Service) ByUserUUID(ctx context.Context, userUUID string) ([]Session, error) {
+	session := Session{UserUUID: userUUID}
+
+	if err := s.requireUserUUID(&session); err != nil {
+		return []Session{}, err
+	}
+
+	return s.r.SessionGetByUserUUID(ctx, session.UserUUID)
+}
+
+// UpdateLastSeen records a request made with an existing Session, by a client identified by
+// its IP address and user agent.
+//
+// The last activity is only saved if the client changed, or if it has not been updated for
+// LastSeenUpdateInterval.
+func (s *Service) UpdateLastSeen(ctx context.Context, session Session, clientIP string, userAgent string) error {
+	if session.UUID == "" {
+		return ErrUUIDRequired
+	}
+
+	userAgent = truncateUserAgent(userAgent)
+	now := time.Now().UTC()
+
+	if session.ClientIP == clientIP &&
+		session.UserAgent == userAgent &&
+		now.Sub(session.LastSeenAt) < LastSeenUpdateInterval {
+		return nil
+	}
+
+	update := LastSeenUpdate{
+		UUID:       session.UUID,
+		ClientIP:   clientIP,
+		UserAgent:  userAgent,
+		LastSeenAt: now,
+	}
+
+	return s.r.SessionUpdateLastSeen(ctx, update)
+}
+
+// DeleteByUUID revokes a Session belonging to a given user.
+func (s *Service) DeleteByUUID(ctx context.Context, userUUID string, sessionUUID string) error {
+	session := Session{UUID: sessionUUID, UserUUID: userUUID}
+
+	err := s.runValidationFuncs(
+		&session,
+		s.requireUserUUID,
+		s.requireUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.r.SessionDeleteByUUID(ctx, session.UserUUID, session.UUID)
+}
+
+// DeleteOthers revokes all Sessions belonging to a given user, except the current Session.
+func (s *Service) DeleteOthers(ctx context.Context, userUUID string, currentSessionUUID string) error {
+	session := Session{UUID: currentSessionUUID, UserUUID: userUUID}
+
+	err := s.runValidationFuncs(
+		&session,
+		s.requireUserUUID,
+		s.requireUUID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.r.SessionDeleteByUserUUIDExcept(ctx, session.UserUUID, session.UUID)
+}
+
 // DeleteByRememberToken deletes the Session corresponding to a given
 // RememberToken.
 func (s *Service) DeleteByRememberToken(ctx context.Context, rememberToken string) error {
@@ -92,6 +172,34 @@ func (s *Service) DeleteByUserUUID(ctx context.Context, userUUID string) error {
 	return s.r.SessionDeleteByUserUUID(ctx, session.UserUUID)
 }
 
+func (s *Service) ensureTimestamps(session *Session) error {
+	now := time.Now().UTC()
+
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastSeenAt.IsZero() {
+		session.LastSeenAt = session.CreatedAt
+	}
+
+	return nil
+}
+
+func (s *Service) ensureUUID(session *Session) error {
+	if session.UUID != "" {
+		return nil
+	}
+
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	session.UUID = generatedUUID.String()
+
+	return nil
+}
+
 func (s *Service) hashRememberToken(session *Session) error {
 	if session.RememberToken == "" {
 		return nil
@@ -107,6 +215,11 @@ func (s *Service) hashRememberToken(session *Session) error {
 	return nil
 }
 
+func (s *Service) normalizeUserAgent(session *Session) error {
+	session.UserAgent = truncateUserAgent(session.UserAgent)
+	return nil
+}
+
 func (s *Service) requireRememberToken(session *Session) error {
 	if session.RememberToken == "" {
 		return ErrRememberTokenRequired
@@ -121,6 +234,13 @@ func (s *Service) requireRememberTokenHash(session *Session) error {
 	return nil
 }
 
+func (s *Service) requireUUID(session *Session) error {
+	if session.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
+
 func (s *Service) requireUserUUID(session *Session) error {
 	if session.UserUUID == "" {
 		return user.ErrUUIDRequired
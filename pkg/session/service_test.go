@@ -5,6 +5,7 @@ package session
 
 import (
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -291,3 +292,285 @@ func TestServiceDeleteByUserUUID(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAddRecordsMetadata(t *testing.T) {
+	r := &FakeRepository{}
+	s, err := NewService(r, "ugotcookies")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess := Session{
+		UserUUID:               "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b",
+		RememberToken:          "tdk_BrK5adfbUapWUIeQO1VPMkGCtaQFjvF4A0KHy2g=",
+		RememberTokenExpiresAt: time.Now().UTC().Add(1 * time.Hour),
+		ClientIP:               "192.0.2.1",
+		UserAgent:              strings.Repeat("a", UserAgentMaxLength+10),
+	}
+
+	if err := s.Add(t.Context(), sess); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	got := r.Sessions[0]
+
+	if got.UUID == "" {
+		t.Error("want a UUID to be generated")
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("want the creation time to be recorded")
+	}
+	if !got.LastSeenAt.Equal(got.CreatedAt) {
+		t.Errorf("want last seen time %q, got %q", got.CreatedAt, got.LastSeenAt)
+	}
+	if got.ClientIP != "192.0.2.1" {
+		t.Errorf("want client IP %q, got %q", "192.0.2.1", got.ClientIP)
+	}
+	if len(got.UserAgent) != UserAgentMaxLength {
+		t.Errorf("want user agent to be truncated to %d characters, got %d", UserAgentMaxLength, len(got.UserAgent))
+	}
+}
+
+func TestServiceByUserUUID(t *testing.T) {
+	now := time.Now().UTC()
+
+	r := &FakeRepository{
+		Sessions: []Session{
+			{
+				UUID:                   "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c",
+				UserUUID:               "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b",
+				RememberTokenExpiresAt: now.Add(1 * time.Hour),
+				LastSeenAt:             now.Add(-2 * time.Hour),
+			},
+			{
+				UUID:                   "7a1e3c5d-8b9f-4a2c-b3d4-e5f6a7b8c9d0",
+				UserUUID:               "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b",
+				RememberTokenExpiresAt: now.Add(1 * time.Hour),
+				LastSeenAt:             now.Add(-1 * time.Minute),
+			},
+			{
+				UUID:                   "9c2f4e6a-0b1d-4c3e-a5f7-b8c9d0e1f2a3",
+				UserUUID:               "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b",
+				RememberTokenExpiresAt: now.Add(-1 * time.Hour),
+			},
+			{
+				UUID:                   "b4d6f8a0-2c3e-4e5f-a7b9-c0d1e2f3a4b5",
+				UserUUID:               "0695b57a-1ab9-401d-b2db-a4430b7059ec",
+				RememberTokenExpiresAt: now.Add(1 * time.Hour),
+			},
+		},
+	}
+	s, err := NewService(r, "ugotcookies")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ByUserUUID(t.Context(), ""); !errors.Is(err, user.ErrUUIDRequired) {
+		t.Fatalf("want error %q, got %q", user.ErrUUIDRequired, err)
+	}
+
+	got, err := s.ByUserUUID(t.Context(), "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	wantUUIDs := []string{
+		"7a1e3c5d-8b9f-4a2c-b3d4-e5f6a7b8c9d0",
+		"5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c",
+	}
+
+	if len(got) != len(wantUUIDs) {
+		t.Fatalf("want %d sessions, got %d", len(wantUUIDs), len(got))
+	}
+	for index, wantUUID := range wantUUIDs {
+		if got[index].UUID != wantUUID {
+			t.Errorf("want session %d to be %q, got %q", index, wantUUID, got[index].UUID)
+		}
+	}
+}
+
+func TestServiceUpdateLastSeen(t *testing.T) {
+	now := time.Now().UTC()
+
+	cases := []struct {
+		tname       string
+		session     Session
+		clientIP    string
+		userAgent   string
+		wantUpdated bool
+		wantErr     error
+	}{
+		{
+			tname:   "empty UUID",
+			wantErr: ErrUUIDRequired,
+		},
+		{
+			tname: "recently seen with the same client",
+			session: Session{
+				ClientIP:   "192.0.2.1",
+				UserAgent:  "Firefox",
+				LastSeenAt: now.Add(-1 * time.Minute),
+			},
+			clientIP:  "192.0.2.1",
+			userAgent: "Firefox",
+		},
+		{
+			tname: "not seen recently",
+			session: Session{
+				ClientIP:   "192.0.2.1",
+				UserAgent:  "Firefox",
+				LastSeenAt: now.Add(-LastSeenUpdateInterval),
+			},
+			clientIP:    "192.0.2.1",
+			userAgent:   "Firefox",
+			wantUpdated: true,
+		},
+		{
+			tname: "client IP changed",
+			session: Session{
+				ClientIP:   "192.0.2.1",
+				UserAgent:  "Firefox",
+				LastSeenAt: now.Add(-1 * time.Minute),
+			},
+			clientIP:    "198.51.100.7",
+			userAgent:   "Firefox",
+			wantUpdated: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			if tc.wantErr == nil {
+				tc.session.UUID = "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c"
+			}
+
+			r := &FakeRepository{Sessions: []Session{tc.session}}
+			s, err := NewService(r, "ugotcookies")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.UpdateLastSeen(t.Context(), tc.session, tc.clientIP, tc.userAgent)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			got := r.Sessions[0]
+			updated := !got.LastSeenAt.Equal(tc.session.LastSeenAt)
+
+			if updated != tc.wantUpdated {
+				t.Fatalf("want updated %t, got %t", tc.wantUpdated, updated)
+			}
+			if got.ClientIP != tc.clientIP {
+				t.Errorf("want client IP %q, got %q", tc.clientIP, got.ClientIP)
+			}
+		})
+	}
+}
+
+func TestServiceDeleteByUUID(t *testing.T) {
+	const (
+		userUUID      = "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b"
+		otherUserUUID = "0695b57a-1ab9-401d-b2db-a4430b7059ec"
+		sessionUUID   = "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c"
+	)
+
+	cases := []struct {
+		tname         string
+		userUUID      string
+		sessionUUID   string
+		wantErr       error
+		wantRemaining int
+	}{
+		{
+			tname:         "empty user UUID",
+			sessionUUID:   sessionUUID,
+			wantErr:       user.ErrUUIDRequired,
+			wantRemaining: 1,
+		},
+		{
+			tname:         "empty session UUID",
+			userUUID:      userUUID,
+			wantErr:       ErrUUIDRequired,
+			wantRemaining: 1,
+		},
+		{
+			tname:         "session belonging to another user",
+			userUUID:      otherUserUUID,
+			sessionUUID:   sessionUUID,
+			wantErr:       ErrNotFound,
+			wantRemaining: 1,
+		},
+		{
+			tname:       "revoke session",
+			userUUID:    userUUID,
+			sessionUUID: sessionUUID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Sessions: []Session{{UUID: sessionUUID, UserUUID: userUUID}},
+			}
+			s, err := NewService(r, "ugotcookies")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = s.DeleteByUUID(t.Context(), tc.userUUID, tc.sessionUUID)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if len(r.Sessions) != tc.wantRemaining {
+				t.Errorf("want %d remaining sessions, got %d", tc.wantRemaining, len(r.Sessions))
+			}
+		})
+	}
+}
+
+func TestServiceDeleteOthers(t *testing.T) {
+	r := &FakeRepository{
+		Sessions: []Session{
+			{UUID: "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c", UserUUID: "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b"},
+			{UUID: "7a1e3c5d-8b9f-4a2c-b3d4-e5f6a7b8c9d0", UserUUID: "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b"},
+			{UUID: "b4d6f8a0-2c3e-4e5f-a7b9-c0d1e2f3a4b5", UserUUID: "0695b57a-1ab9-401d-b2db-a4430b7059ec"},
+		},
+	}
+	s, err := NewService(r, "ugotcookies")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteOthers(t.Context(), "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b", ""); !errors.Is(err, ErrUUIDRequired) {
+		t.Fatalf("want error %q, got %q", ErrUUIDRequired, err)
+	}
+
+	if err := s.DeleteOthers(t.Context(), "bf4d9fe9-25e0-4a36-b992-69c5cb611f0b", "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c"); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(r.Sessions) != 2 {
+		t.Fatalf("want 2 remaining sessions, got %d", len(r.Sessions))
+	}
+	if r.Sessions[0].UUID != "5d0c2b0e-6f4a-4f59-9b5a-6d8e7f0a1b2c" {
+		t.Errorf("want the current session to be kept, got %q", r.Sessions[0].UUID)
+	}
+	if r.Sessions[1].UserUUID != "0695b57a-1ab9-401d-b2db-a4430b7059ec" {
+		t.Errorf("want other users' sessions to be kept, got %q", r.Sessions[1].UserUUID)
+	}
+}
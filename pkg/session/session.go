@@ -3,12 +3,54 @@
 
 package session
 
-import "time"
+import (
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// LastSeenUpdateInterval is the minimum interval between two updates of a Session's
+	// last activity, to avoid writing to the database on every request.
+	LastSeenUpdateInterval = 5 * time.Minute
+
+	// UserAgentMaxLength is the maximum length of the user agent saved for a Session.
+	UserAgentMaxLength = 512
+)
 
 // Session represents a Web User session.
 type Session struct {
+	UUID                   string
 	UserUUID               string
 	RememberToken          string
 	RememberTokenHash      string
 	RememberTokenExpiresAt time.Time
+
+	// ClientIP and UserAgent identify the client that last used the Session.
+	ClientIP  string
+	UserAgent string
+
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// LastSeenUpdate represents a request made with an existing Session.
+type LastSeenUpdate struct {
+	UUID       string
+	ClientIP   string
+	UserAgent  string
+	LastSeenAt time.Time
+}
+
+// truncateUserAgent shortens overly long user agents, without splitting multi-byte characters.
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= UserAgentMaxLength {
+		return userAgent
+	}
+
+	truncated := userAgent[:UserAgentMaxLength]
+	for !utf8.ValidString(truncated) {
+		truncated = truncated[:len(truncated)-1]
+	}
+
+	return truncated
 }
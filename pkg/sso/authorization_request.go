@@ -0,0 +1,30 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import "time"
+
+const (
+	// AuthorizationRequestTTL is the time a user has to log in with the identity provider.
+	AuthorizationRequestTTL = 10 * time.Minute
+
+	stateNBytes = 32
+	nonceNBytes = 32
+)
+
+// AuthorizationRequest represents a pending OpenID Connect authorization code flow.
+//
+// The PKCE code verifier and ID token nonce are kept server-side, so that each request
+// can only be completed once.
+type AuthorizationRequest struct {
+	// StateHash is the HMAC hash of the clear-text state passed to the identity provider,
+	// used to look the request up.
+	StateHash string
+
+	CodeVerifier string
+	Nonce        string
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
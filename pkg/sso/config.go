@@ -0,0 +1,61 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+const (
+	// DefaultGroupsClaim is the ID token claim listing the groups a user belongs to.
+	DefaultGroupsClaim = "groups"
+
+	// DefaultProviderName is the name of the identity provider displayed on the login page.
+	DefaultProviderName = "single sign-on"
+)
+
+var (
+	// DefaultScopes are the OAuth2 scopes requested to the identity provider.
+	DefaultScopes = []string{"openid", "email", "profile"}
+)
+
+// Config holds the settings to authenticate users with an OpenID Connect identity provider.
+type Config struct {
+	// IssuerURL is the URL of the identity provider, used for OpenID Connect discovery.
+	IssuerURL string
+
+	// ClientID and ClientSecret are the credentials of SparkleMuffin as an OAuth2 client.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are the OAuth2 scopes requested to the identity provider.
+	Scopes []string
+
+	// ProviderName is the name of the identity provider displayed on the login page.
+	ProviderName string
+
+	// Provisioning enables the creation of a user account on first login, for users with
+	// no existing account matching their email address.
+	Provisioning bool
+
+	// AdminGroup is the group granting administration privileges; if set, privileges are
+	// granted or revoked on each login, depending on the groups listed in GroupsClaim.
+	AdminGroup string
+
+	// GroupsClaim is the ID token claim listing the groups a user belongs to.
+	GroupsClaim string
+}
+
+// Enabled returns whether an identity provider is configured.
+func (c Config) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+func (c *Config) setDefaults() {
+	if len(c.Scopes) == 0 {
+		c.Scopes = DefaultScopes
+	}
+	if c.ProviderName == "" {
+		c.ProviderName = DefaultProviderName
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = DefaultGroupsClaim
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import "errors"
+
+var (
+	ErrAuthorizationRequestInvalid = errors.New("sso: invalid or expired authorization request")
+	ErrClientIDRequired            = errors.New("sso: client ID is required")
+	ErrEmailNotVerified            = errors.New("sso: email address not verified by the identity provider")
+	ErrHmacKeyRequired             = errors.New("sso: hmac key is required")
+	ErrIDTokenInvalid              = errors.New("sso: invalid ID token")
+	ErrIssuerURLRequired           = errors.New("sso: issuer URL is required")
+	ErrNotFound                    = errors.New("sso: not found")
+	ErrRedirectURLRequired         = errors.New("sso: redirect URL is required")
+	ErrUserNotProvisioned          = errors.New("sso: no matching user account")
+	ErrUserServiceRequired         = errors.New("sso: user service is required")
+)
@@ -0,0 +1,54 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import (
+	"slices"
+	"time"
+)
+
+// Identity links a user account to a subject of an OpenID Connect identity provider.
+type Identity struct {
+	Issuer   string
+	Subject  string
+	UserUUID string
+
+	CreatedAt time.Time
+}
+
+// Claims holds the ID token claims used to identify and provision users.
+type Claims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+
+	// Groups is read from the configured groups claim.
+	Groups []string `json:"-"`
+}
+
+// IsMemberOf returns whether the user belongs to a given group.
+func (c Claims) IsMemberOf(group string) bool {
+	return slices.Contains(c.Groups, group)
+}
+
+// parseGroups reads group names from a raw claim value, which identity providers
+// encode either as a list of strings or as a single string.
+func parseGroups(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, item := range v {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	default:
+		return []string{}
+	}
+}
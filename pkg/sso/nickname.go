@@ -0,0 +1,41 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultNickName = "user"
+)
+
+var (
+	nickNameInvalidCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+)
+
+// nickNameFromClaims derives a valid nickname for a provisioned user from their preferred
+// username, or the local part of their email address.
+func nickNameFromClaims(claims Claims) string {
+	nickName := claims.PreferredUsername
+	if nickName == "" {
+		nickName, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	nickName = nickNameInvalidCharsRegex.ReplaceAllString(nickName, "-")
+	nickName = strings.Trim(nickName, "-_")
+
+	if nickName == "" {
+		return defaultNickName
+	}
+
+	// Nicknames must start with a letter, and be at least 2 characters long.
+	first := nickName[0]
+	if len(nickName) < 2 || !((first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z')) {
+		return defaultNickName + "-" + nickName
+	}
+
+	return nickName
+}
@@ -0,0 +1,27 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import "context"
+
+// Repository provides access to linked identities and pending authorization requests.
+type Repository interface {
+	// IdentityAdd links a user account to an identity provider subject.
+	IdentityAdd(ctx context.Context, identity Identity) error
+
+	// IdentityGetBySubject returns the Identity for a given identity provider subject.
+	IdentityGetBySubject(ctx context.Context, issuer string, subject string) (Identity, error)
+
+	// IdentityIsRegistered returns whether a user account is linked to an identity provider.
+	IdentityIsRegistered(ctx context.Context, userUUID string) (bool, error)
+
+	// AuthorizationRequestAdd saves a pending AuthorizationRequest, and deletes expired requests.
+	AuthorizationRequestAdd(ctx context.Context, request AuthorizationRequest) error
+
+	// AuthorizationRequestConsume deletes and returns a pending AuthorizationRequest.
+	//
+	// It returns ErrNotFound if the request does not exist, has already been consumed or
+	// has expired.
+	AuthorizationRequestConsume(ctx context.Context, stateHash string) (AuthorizationRequest, error)
+}
@@ -0,0 +1,62 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	Identities            []Identity
+	AuthorizationRequests []AuthorizationRequest
+}
+
+func (r *FakeRepository) IdentityAdd(_ context.Context, identity Identity) error {
+	r.Identities = append(r.Identities, identity)
+	return nil
+}
+
+func (r *FakeRepository) IdentityGetBySubject(_ context.Context, issuer string, subject string) (Identity, error) {
+	for _, identity := range r.Identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+
+	return Identity{}, ErrNotFound
+}
+
+func (r *FakeRepository) IdentityIsRegistered(_ context.Context, userUUID string) (bool, error) {
+	return slices.ContainsFunc(r.Identities, func(identity Identity) bool {
+		return identity.UserUUID == userUUID
+	}), nil
+}
+
+func (r *FakeRepository) AuthorizationRequestAdd(_ context.Context, request AuthorizationRequest) error {
+	r.AuthorizationRequests = append(r.AuthorizationRequests, request)
+	return nil
+}
+
+func (r *FakeRepository) AuthorizationRequestConsume(_ context.Context, stateHash string) (AuthorizationRequest, error) {
+	for index, request := range r.AuthorizationRequests {
+		if request.StateHash != stateHash {
+			continue
+		}
+
+		r.AuthorizationRequests = append(r.AuthorizationRequests[:index], r.AuthorizationRequests[index+1:]...)
+
+		if time.Now().UTC().After(request.ExpiresAt) {
+			return AuthorizationRequest{}, ErrNotFound
+		}
+
+		return request, nil
+	}
+
+	return AuthorizationRequest{}, ErrNotFound
+}
@@ -0,0 +1,330 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	provisionedPasswordNBytes = 32
+	nickNameMaxAttempts       = 100
+)
+
+// Service handles user authentication with an OpenID Connect identity provider, using the
+// authorization code flow with PKCE.
+type Service struct {
+	r           Repository
+	userService *user.Service
+	config      Config
+	hmac        *hash.HMAC
+
+	oauth2Config *oauth2.Config
+	verifier     *oidc.IDTokenVerifier
+}
+
+// NewService initializes and returns an OpenID Connect Service.
+//
+// The identity provider's endpoints and signing keys are retrieved with OpenID Connect
+// discovery. Users are sent back to redirectURL once authenticated.
+func NewService(ctx context.Context, r Repository, userService *user.Service, config Config, redirectURL *url.URL, hmacKey string) (*Service, error) {
+	if userService == nil {
+		return &Service{}, ErrUserServiceRequired
+	}
+	if config.IssuerURL == "" {
+		return &Service{}, ErrIssuerURLRequired
+	}
+	if config.ClientID == "" {
+		return &Service{}, ErrClientIDRequired
+	}
+	if redirectURL == nil {
+		return &Service{}, ErrRedirectURLRequired
+	}
+	if hmacKey == "" {
+		return &Service{}, ErrHmacKeyRequired
+	}
+
+	config.setDefaults()
+
+	provider, err := oidc.NewProvider(ctx, config.IssuerURL)
+	if err != nil {
+		return &Service{}, fmt.Errorf("sso: discovery failed: %w", err)
+	}
+
+	return &Service{
+		r:           r,
+		userService: userService,
+		config:      config,
+		hmac:        hash.NewHMAC(hmacKey),
+		oauth2Config: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  redirectURL.String(),
+			Scopes:       config.Scopes,
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+// ProviderName returns the name of the identity provider, as displayed to users.
+func (s *Service) ProviderName() string {
+	return s.config.ProviderName
+}
+
+// IsLinked returns whether a user account is linked to the identity provider.
+//
+// Accounts provisioned on their first login have a random password, which their owners do
+// not know unless they have reset it.
+func (s *Service) IsLinked(ctx context.Context, userUUID string) (bool, error) {
+	return s.r.IdentityIsRegistered(ctx, userUUID)
+}
+
+// AuthCodeURL starts an authorization request, and returns the identity provider URL to
+// redirect the user to, and the state identifying the request, that must be provided to
+// Authenticate.
+func (s *Service) AuthCodeURL(ctx context.Context) (string, string, error) {
+	state, err := rand.RandomBase64URLString(stateNBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	stateHash, err := s.hmac.Hash(state)
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce, err := rand.RandomBase64URLString(nonceNBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now().UTC()
+
+	request := AuthorizationRequest{
+		StateHash:    stateHash,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(AuthorizationRequestTTL),
+		CreatedAt:    now,
+	}
+
+	if err := s.r.AuthorizationRequestAdd(ctx, request); err != nil {
+		return "", "", err
+	}
+
+	authCodeURL := s.oauth2Config.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(request.CodeVerifier),
+	)
+
+	return authCodeURL, state, nil
+}
+
+// Authenticate completes an authorization request started with AuthCodeURL, and returns
+// the user authenticated by the identity provider.
+//
+// expectedState is the state returned by AuthCodeURL, which must match the state sent back
+// by the identity provider along with the authorization code.
+//
+// Users are looked up by their identity provider subject, then linked by verified email
+// address on their first login; if provisioning is enabled, a new account is created for
+// unknown users.
+func (s *Service) Authenticate(ctx context.Context, expectedState string, state string, code string) (user.User, error) {
+	if expectedState == "" || subtle.ConstantTimeCompare([]byte(expectedState), []byte(state)) != 1 {
+		return user.User{}, ErrAuthorizationRequestInvalid
+	}
+
+	request, err := s.consumeAuthorizationRequest(ctx, state)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	token, err := s.oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(request.CodeVerifier))
+	if err != nil {
+		return user.User{}, fmt.Errorf("sso: failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return user.User{}, fmt.Errorf("%w: missing from token response", ErrIDTokenInvalid)
+	}
+
+	idToken, err := s.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return user.User{}, fmt.Errorf("%w: %w", ErrIDTokenInvalid, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(request.Nonce)) != 1 {
+		return user.User{}, fmt.Errorf("%w: nonce mismatch", ErrIDTokenInvalid)
+	}
+
+	claims, err := s.parseClaims(idToken)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	u, err := s.resolveUser(ctx, idToken.Issuer, claims)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if s.config.AdminGroup != "" {
+		isAdmin := claims.IsMemberOf(s.config.AdminGroup)
+
+		if u.IsAdmin != isAdmin {
+			if err := s.userService.UpdateAdmin(ctx, u.UUID, isAdmin); err != nil {
+				return user.User{}, err
+			}
+
+			u.IsAdmin = isAdmin
+		}
+	}
+
+	return u, nil
+}
+
+// parseClaims extracts user information from a verified ID token.
+func (s *Service) parseClaims(idToken *oidc.IDToken) (Claims, error) {
+	var claims Claims
+	if err := idToken.Claims(&claims); err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrIDTokenInvalid, err)
+	}
+
+	var rawClaims map[string]any
+	if err := idToken.Claims(&rawClaims); err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrIDTokenInvalid, err)
+	}
+
+	claims.Groups = parseGroups(rawClaims[s.config.GroupsClaim])
+
+	return claims, nil
+}
+
+// resolveUser returns the user account linked to an identity provider subject, linking or
+// provisioning it on first login.
+func (s *Service) resolveUser(ctx context.Context, issuer string, claims Claims) (user.User, error) {
+	identity, err := s.r.IdentityGetBySubject(ctx, issuer, claims.Subject)
+	if err == nil {
+		return s.userService.ByUUID(ctx, identity.UserUUID)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return user.User{}, err
+	}
+
+	// Only trust email addresses verified by the identity provider, as they grant access
+	// to existing accounts.
+	if claims.Email == "" || !claims.EmailVerified {
+		return user.User{}, ErrEmailNotVerified
+	}
+
+	u, err := s.userService.ByEmail(ctx, claims.Email)
+	if errors.Is(err, user.ErrNotFound) {
+		if !s.config.Provisioning {
+			return user.User{}, ErrUserNotProvisioned
+		}
+
+		u, err = s.provisionUser(ctx, claims)
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+
+	identity = Identity{
+		Issuer:    issuer,
+		Subject:   claims.Subject,
+		UserUUID:  u.UUID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.r.IdentityAdd(ctx, identity); err != nil {
+		return user.User{}, err
+	}
+
+	return u, nil
+}
+
+// provisionUser creates a new user account from ID token claims.
+//
+// The account is given a random password, which the user can later reset.
+func (s *Service) provisionUser(ctx context.Context, claims Claims) (user.User, error) {
+	nickName, err := s.availableNickName(ctx, nickNameFromClaims(claims))
+	if err != nil {
+		return user.User{}, err
+	}
+
+	displayName := claims.Name
+	if displayName == "" {
+		displayName = nickName
+	}
+
+	password, err := rand.RandomBase64URLString(provisionedPasswordNBytes)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	u, err := user.NewUser(claims.Email, nickName, displayName, password)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if err := s.userService.Add(ctx, u); err != nil {
+		return user.User{}, err
+	}
+
+	return s.userService.ByUUID(ctx, u.UUID)
+}
+
+// availableNickName returns the first nickname not already registered, among nickName and
+// suffixed variants.
+func (s *Service) availableNickName(ctx context.Context, nickName string) (string, error) {
+	candidate := nickName
+
+	for attempt := 2; attempt <= nickNameMaxAttempts; attempt++ {
+		_, err := s.userService.ByNickName(ctx, candidate)
+		if errors.Is(err, user.ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		candidate = nickName + "-" + strconv.Itoa(attempt)
+	}
+
+	return "", user.ErrNickNameAlreadyRegistered
+}
+
+// consumeAuthorizationRequest returns a pending authorization request, which can only be
+// used once.
+func (s *Service) consumeAuthorizationRequest(ctx context.Context, state string) (AuthorizationRequest, error) {
+	stateHash, err := s.hmac.Hash(state)
+	if err != nil {
+		return AuthorizationRequest{}, err
+	}
+
+	request, err := s.r.AuthorizationRequestConsume(ctx, stateHash)
+	if errors.Is(err, ErrNotFound) {
+		return AuthorizationRequest{}, ErrAuthorizationRequestInvalid
+	}
+	if err != nil {
+		return AuthorizationRequest{}, err
+	}
+
+	return request, nil
+}
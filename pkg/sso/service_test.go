@@ -0,0 +1,394 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package sso
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/virtualtam/sparklemuffin/internal/test/ssotest"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testClientID     = "sparklemuffin"
+	testClientSecret = "client-secret"
+	testHmacKey      = "test-hmac-key"
+)
+
+var (
+	testRedirectURL = &url.URL{Scheme: "https", Host: "sparklemuffin.test", Path: "/login/sso/callback"}
+)
+
+func newTestService(t *testing.T, provider *ssotest.Provider, config Config, users ...user.User) (*Service, *FakeRepository, *user.FakeRepository) {
+	t.Helper()
+
+	r := &FakeRepository{}
+	userRepository := &user.FakeRepository{Users: users}
+
+	config.IssuerURL = provider.IssuerURL()
+	config.ClientID = provider.ClientID
+	config.ClientSecret = provider.ClientSecret
+
+	s, err := NewService(t.Context(), r, user.NewService(userRepository, user.FakePasswordHasher(t)), config, testRedirectURL, testHmacKey)
+	if err != nil {
+		t.Fatalf("failed to create service: %q", err)
+	}
+
+	return s, r, userRepository
+}
+
+// login performs an authorization code flow, with the user authenticated by the provider
+// with the given claims.
+func login(t *testing.T, s *Service, provider *ssotest.Provider, claims map[string]any) (user.User, error) {
+	t.Helper()
+
+	authCodeURL, state, err := s.AuthCodeURL(t.Context())
+	if err != nil {
+		t.Fatalf("failed to start authorization request: %q", err)
+	}
+
+	callbackURL := provider.Authorize(t, authCodeURL, claims)
+	query := callbackURL.Query()
+
+	return s.Authenticate(t.Context(), state, query.Get("state"), query.Get("code"))
+}
+
+func TestNewService(t *testing.T) {
+	provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+	userService := user.NewService(&user.FakeRepository{}, user.FakePasswordHasher(t))
+
+	cases := []struct {
+		tname       string
+		userService *user.Service
+		config      Config
+		redirectURL *url.URL
+		hmacKey     string
+		wantErr     error
+	}{
+		{
+			tname:       "missing user service",
+			config:      Config{IssuerURL: provider.IssuerURL(), ClientID: testClientID},
+			redirectURL: testRedirectURL,
+			hmacKey:     testHmacKey,
+			wantErr:     ErrUserServiceRequired,
+		},
+		{
+			tname:       "missing issuer URL",
+			userService: userService,
+			config:      Config{ClientID: testClientID},
+			redirectURL: testRedirectURL,
+			hmacKey:     testHmacKey,
+			wantErr:     ErrIssuerURLRequired,
+		},
+		{
+			tname:       "missing client ID",
+			userService: userService,
+			config:      Config{IssuerURL: provider.IssuerURL()},
+			redirectURL: testRedirectURL,
+			hmacKey:     testHmacKey,
+			wantErr:     ErrClientIDRequired,
+		},
+		{
+			tname:       "missing redirect URL",
+			userService: userService,
+			config:      Config{IssuerURL: provider.IssuerURL(), ClientID: testClientID},
+			hmacKey:     testHmacKey,
+			wantErr:     ErrRedirectURLRequired,
+		},
+		{
+			tname:       "missing HMAC key",
+			userService: userService,
+			config:      Config{IssuerURL: provider.IssuerURL(), ClientID: testClientID},
+			redirectURL: testRedirectURL,
+			wantErr:     ErrHmacKeyRequired,
+		},
+		{
+			tname:       "default settings",
+			userService: userService,
+			config:      Config{IssuerURL: provider.IssuerURL(), ClientID: testClientID},
+			redirectURL: testRedirectURL,
+			hmacKey:     testHmacKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			s, err := NewService(t.Context(), &FakeRepository{}, tc.userService, tc.config, tc.redirectURL, tc.hmacKey)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if s.ProviderName() != DefaultProviderName {
+				t.Errorf("want provider name %q, got %q", DefaultProviderName, s.ProviderName())
+			}
+		})
+	}
+
+	t.Run("discovery failure", func(t *testing.T) {
+		config := Config{IssuerURL: provider.IssuerURL() + "/unknown", ClientID: testClientID}
+
+		if _, err := NewService(t.Context(), &FakeRepository{}, userService, config, testRedirectURL, testHmacKey); err == nil {
+			t.Fatal("want an error, got nil")
+		}
+	})
+}
+
+func TestServiceAuthenticate(t *testing.T) {
+	existingUser := user.User{
+		UUID:        "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email:       "jane.doe@example.org",
+		NickName:    "jane",
+		DisplayName: "Jane Doe",
+	}
+
+	t.Run("link existing account by verified email", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, r, _ := newTestService(t, provider, Config{}, existingUser)
+
+		got, err := login(t, s, provider, map[string]any{
+			"sub":            "jane-subject",
+			"email":          "Jane.Doe@example.org",
+			"email_verified": true,
+		})
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if got.UUID != existingUser.UUID {
+			t.Errorf("want user %q, got %q", existingUser.UUID, got.UUID)
+		}
+
+		if len(r.Identities) != 1 {
+			t.Fatalf("want 1 linked identity, got %d", len(r.Identities))
+		}
+		if r.Identities[0].Issuer != provider.IssuerURL() || r.Identities[0].Subject != "jane-subject" {
+			t.Errorf("unexpected identity: %+v", r.Identities[0])
+		}
+
+		// Once linked, the account is found by subject, even if the email address changes.
+		got, err = login(t, s, provider, map[string]any{
+			"sub":            "jane-subject",
+			"email":          "jane@example.com",
+			"email_verified": false,
+		})
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if got.UUID != existingUser.UUID {
+			t.Errorf("want user %q, got %q", existingUser.UUID, got.UUID)
+		}
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, r, _ := newTestService(t, provider, Config{Provisioning: true}, existingUser)
+
+		_, err := login(t, s, provider, map[string]any{
+			"sub":            "mallory-subject",
+			"email":          existingUser.Email,
+			"email_verified": false,
+		})
+		if !errors.Is(err, ErrEmailNotVerified) {
+			t.Fatalf("want error %q, got %q", ErrEmailNotVerified, err)
+		}
+		if len(r.Identities) != 0 {
+			t.Errorf("want no linked identity, got %d", len(r.Identities))
+		}
+	})
+
+	t.Run("unknown user without provisioning", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, _, userRepository := newTestService(t, provider, Config{}, existingUser)
+
+		_, err := login(t, s, provider, map[string]any{
+			"sub":            "john-subject",
+			"email":          "john.doe@example.org",
+			"email_verified": true,
+		})
+		if !errors.Is(err, ErrUserNotProvisioned) {
+			t.Fatalf("want error %q, got %q", ErrUserNotProvisioned, err)
+		}
+		if len(userRepository.Users) != 1 {
+			t.Errorf("want no user to be created, got %d users", len(userRepository.Users))
+		}
+	})
+
+	t.Run("provision unknown user", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, r, userRepository := newTestService(t, provider, Config{Provisioning: true}, existingUser)
+
+		got, err := login(t, s, provider, map[string]any{
+			"sub":                "john-subject",
+			"email":              "john.doe@example.org",
+			"email_verified":     true,
+			"name":               "John Doe",
+			"preferred_username": "jane",
+		})
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(userRepository.Users) != 2 {
+			t.Fatalf("want 2 users, got %d", len(userRepository.Users))
+		}
+		if got.Email != "john.doe@example.org" {
+			t.Errorf("want email %q, got %q", "john.doe@example.org", got.Email)
+		}
+		if got.NickName != "jane-2" {
+			t.Errorf("want nickname %q, got %q", "jane-2", got.NickName)
+		}
+		if got.DisplayName != "John Doe" {
+			t.Errorf("want display name %q, got %q", "John Doe", got.DisplayName)
+		}
+		if got.PasswordHash == "" {
+			t.Error("want a random password to be set")
+		}
+		if len(r.Identities) != 1 || r.Identities[0].UserUUID != got.UUID {
+			t.Errorf("want the new user to be linked, got %+v", r.Identities)
+		}
+	})
+
+	t.Run("admin group mapping", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, _, userRepository := newTestService(t, provider, Config{AdminGroup: "sparklemuffin-admins"}, existingUser)
+
+		claims := map[string]any{
+			"sub":            "jane-subject",
+			"email":          existingUser.Email,
+			"email_verified": true,
+			"groups":         []string{"staff", "sparklemuffin-admins"},
+		}
+
+		got, err := login(t, s, provider, claims)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if !got.IsAdmin || !userRepository.Users[0].IsAdmin {
+			t.Error("want administration privileges to be granted")
+		}
+
+		claims["groups"] = "staff"
+
+		got, err = login(t, s, provider, claims)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if got.IsAdmin || userRepository.Users[0].IsAdmin {
+			t.Error("want administration privileges to be revoked")
+		}
+	})
+
+	t.Run("custom groups claim", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		config := Config{AdminGroup: "admin", GroupsClaim: "roles"}
+		s, _, _ := newTestService(t, provider, config, existingUser)
+
+		got, err := login(t, s, provider, map[string]any{
+			"sub":            "jane-subject",
+			"email":          existingUser.Email,
+			"email_verified": true,
+			"groups":         []string{"admin"},
+			"roles":          []string{"admin"},
+		})
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if !got.IsAdmin {
+			t.Error("want administration privileges to be granted")
+		}
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, _, _ := newTestService(t, provider, Config{}, existingUser)
+
+		authCodeURL, _, err := s.AuthCodeURL(t.Context())
+		if err != nil {
+			t.Fatalf("failed to start authorization request: %q", err)
+		}
+
+		query := provider.Authorize(t, authCodeURL, map[string]any{"sub": "jane-subject"}).Query()
+
+		_, err = s.Authenticate(t.Context(), "another-state", query.Get("state"), query.Get("code"))
+		if !errors.Is(err, ErrAuthorizationRequestInvalid) {
+			t.Fatalf("want error %q, got %q", ErrAuthorizationRequestInvalid, err)
+		}
+	})
+
+	t.Run("authorization request replay", func(t *testing.T) {
+		provider := ssotest.NewProvider(t, testClientID, testClientSecret)
+		s, _, _ := newTestService(t, provider, Config{}, existingUser)
+
+		authCodeURL, state, err := s.AuthCodeURL(t.Context())
+		if err != nil {
+			t.Fatalf("failed to start authorization request: %q", err)
+		}
+
+		query := provider.Authorize(t, authCodeURL, map[string]any{
+			"sub":            "jane-subject",
+			"email":          existingUser.Email,
+			"email_verified": true,
+		}).Query()
+
+		if _, err := s.Authenticate(t.Context(), state, query.Get("state"), query.Get("code")); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		_, err = s.Authenticate(t.Context(), state, query.Get("state"), query.Get("code"))
+		if !errors.Is(err, ErrAuthorizationRequestInvalid) {
+			t.Fatalf("want error %q, got %q", ErrAuthorizationRequestInvalid, err)
+		}
+	})
+}
+
+func TestNickNameFromClaims(t *testing.T) {
+	cases := []struct {
+		tname  string
+		claims Claims
+		want   string
+	}{
+		{
+			tname:  "preferred username",
+			claims: Claims{PreferredUsername: "jane_doe", Email: "jdoe@example.org"},
+			want:   "jane_doe",
+		},
+		{
+			tname:  "email local part",
+			claims: Claims{Email: "jane.doe@example.org"},
+			want:   "jane-doe",
+		},
+		{
+			tname:  "leading digit",
+			claims: Claims{PreferredUsername: "1337"},
+			want:   "user-1337",
+		},
+		{
+			tname:  "single character",
+			claims: Claims{PreferredUsername: "j"},
+			want:   "user-j",
+		},
+		{
+			tname:  "no usable characters",
+			claims: Claims{PreferredUsername: "ジェーン"},
+			want:   "user",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			if got := nickNameFromClaims(tc.claims); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ChallengeTTL is the time a user has to provide their second factor after
+	// successfully entering their password.
+	ChallengeTTL = 5 * time.Minute
+)
+
+// IssueChallenge returns a signed, time-limited login challenge, proving that a user has
+// successfully authenticated with their password and must now provide their second factor.
+func (s *Service) IssueChallenge(userUUID string) (string, error) {
+	if userUUID == "" {
+		return "", ErrUserUUIDRequired
+	}
+
+	expiresAt := time.Now().UTC().Add(ChallengeTTL).Unix()
+	payload := userUUID + "." + strconv.FormatInt(expiresAt, 10)
+
+	signature, err := s.hmac.Hash(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return payload + "." + signature, nil
+}
+
+// ChallengeUserUUID verifies a login challenge issued by IssueChallenge, and returns the
+// UUID of the corresponding user.
+func (s *Service) ChallengeUserUUID(challenge string) (string, error) {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return "", ErrChallengeInvalid
+	}
+
+	userUUID, expiresAtStr, signature := parts[0], parts[1], parts[2]
+
+	expectedSignature, err := s.hmac.Hash(userUUID + "." + expiresAtStr)
+	if err != nil {
+		return "", err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(expectedSignature)) != 1 {
+		return "", ErrChallengeInvalid
+	}
+
+	expiresAt, err := strconv.ParseInt(expiresAtStr, 10, 64)
+	if err != nil {
+		return "", ErrChallengeInvalid
+	}
+
+	if time.Now().UTC().Unix() >= expiresAt {
+		return "", ErrChallengeInvalid
+	}
+
+	return userUUID, nil
+}
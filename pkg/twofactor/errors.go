@@ -0,0 +1,18 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import "errors"
+
+var (
+	ErrAlreadyEnabled   = errors.New("two-factor: already enabled")
+	ErrChallengeInvalid = errors.New("two-factor: invalid or expired login challenge")
+	ErrCodeAlreadyUsed  = errors.New("two-factor: code already used")
+	ErrCodeInvalid      = errors.New("two-factor: invalid code")
+	ErrCodeRequired     = errors.New("two-factor: code required")
+	ErrHmacKeyRequired  = errors.New("two-factor: hmac key is required")
+	ErrNotEnabled       = errors.New("two-factor: not enabled")
+	ErrNotFound         = errors.New("two-factor: not found")
+	ErrUserUUIDRequired = errors.New("two-factor: user UUID required")
+)
@@ -0,0 +1,56 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+)
+
+const (
+	// RecoveryCodeCount is the number of recovery codes issued when enabling two-factor authentication.
+	RecoveryCodeCount int = 10
+
+	recoveryCodeNBytes = 5
+)
+
+var (
+	recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// RecoveryCode represents a single-use code that can be used instead of a TOTP code,
+// e.g. when the user has lost access to their authenticator application.
+type RecoveryCode struct {
+	UserUUID string
+
+	// CodeHash is the HMAC hash of the normalized recovery code; the clear-text code
+	// is only displayed once to the user.
+	CodeHash string
+
+	CreatedAt time.Time
+}
+
+// newRecoveryCode generates a random recovery code, formatted as two groups of 4 characters.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeNBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+
+	return code[:4] + "-" + code[4:], nil
+}
+
+// normalizeRecoveryCode removes formatting characters and normalizes case, so that users may
+// type recovery codes in a forgiving way.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+
+	return code
+}
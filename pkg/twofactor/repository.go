@@ -0,0 +1,39 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import "context"
+
+// Repository provides access to two-factor authentication enrollments.
+type Repository interface {
+	// TwoFactorTOTPGetByUserUUID returns the TOTP enrollment for a given user.
+	TwoFactorTOTPGetByUserUUID(ctx context.Context, userUUID string) (TOTP, error)
+
+	// TwoFactorTOTPSetup saves a new, unconfirmed TOTP enrollment, replacing any unconfirmed
+	// enrollment for the same user.
+	//
+	// It returns ErrAlreadyEnabled if the user has already enabled TOTP.
+	TwoFactorTOTPSetup(ctx context.Context, t TOTP) error
+
+	// TwoFactorTOTPEnable confirms a TOTP enrollment, and replaces the user's recovery codes.
+	//
+	// It returns ErrNotFound if there is no unconfirmed enrollment for this user.
+	TwoFactorTOTPEnable(ctx context.Context, enable TOTPEnable, recoveryCodes []RecoveryCode) error
+
+	// TwoFactorTOTPLastUsedStepUpdate records the time step of an accepted TOTP code.
+	//
+	// It returns ErrCodeAlreadyUsed if a code for this step, or a later one, has already been accepted.
+	TwoFactorTOTPLastUsedStepUpdate(ctx context.Context, userUUID string, step int64) error
+
+	// TwoFactorRecoveryCodeConsume marks a recovery code as used.
+	//
+	// It returns ErrNotFound if the code does not exist or has already been used.
+	TwoFactorRecoveryCodeConsume(ctx context.Context, userUUID string, codeHash string) error
+
+	// TwoFactorRecoveryCodeCountUnused returns the number of recovery codes a user has not used yet.
+	TwoFactorRecoveryCodeCountUnused(ctx context.Context, userUUID string) (int, error)
+
+	// TwoFactorDelete deletes the TOTP enrollment and recovery codes of a given user.
+	TwoFactorDelete(ctx context.Context, userUUID string) error
+}
@@ -0,0 +1,118 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"context"
+	"slices"
+)
+
+var _ Repository = &FakeRepository{}
+
+// FakeRepository provides an in-memory Repository for testing.
+type FakeRepository struct {
+	TOTPs         []TOTP
+	RecoveryCodes []RecoveryCode
+}
+
+func (r *FakeRepository) TwoFactorTOTPGetByUserUUID(_ context.Context, userUUID string) (TOTP, error) {
+	for _, t := range r.TOTPs {
+		if t.UserUUID == userUUID {
+			return t, nil
+		}
+	}
+
+	return TOTP{}, ErrNotFound
+}
+
+func (r *FakeRepository) TwoFactorTOTPSetup(_ context.Context, t TOTP) error {
+	for i, existing := range r.TOTPs {
+		if existing.UserUUID != t.UserUUID {
+			continue
+		}
+
+		if existing.Enabled {
+			return ErrAlreadyEnabled
+		}
+
+		r.TOTPs[i] = t
+		return nil
+	}
+
+	r.TOTPs = append(r.TOTPs, t)
+
+	return nil
+}
+
+func (r *FakeRepository) TwoFactorTOTPEnable(_ context.Context, enable TOTPEnable, recoveryCodes []RecoveryCode) error {
+	for i, t := range r.TOTPs {
+		if t.UserUUID != enable.UserUUID || t.Enabled {
+			continue
+		}
+
+		r.TOTPs[i].Enabled = true
+		r.TOTPs[i].LastUsedStep = enable.LastUsedStep
+		r.TOTPs[i].UpdatedAt = enable.UpdatedAt
+
+		r.RecoveryCodes = slices.DeleteFunc(r.RecoveryCodes, func(c RecoveryCode) bool {
+			return c.UserUUID == enable.UserUUID
+		})
+		r.RecoveryCodes = append(r.RecoveryCodes, recoveryCodes...)
+
+		return nil
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) TwoFactorTOTPLastUsedStepUpdate(_ context.Context, userUUID string, step int64) error {
+	for i, t := range r.TOTPs {
+		if t.UserUUID != userUUID {
+			continue
+		}
+
+		if t.LastUsedStep >= step {
+			return ErrCodeAlreadyUsed
+		}
+
+		r.TOTPs[i].LastUsedStep = step
+		return nil
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) TwoFactorRecoveryCodeConsume(_ context.Context, userUUID string, codeHash string) error {
+	for i, c := range r.RecoveryCodes {
+		if c.UserUUID == userUUID && c.CodeHash == codeHash {
+			r.RecoveryCodes = slices.Delete(r.RecoveryCodes, i, i+1)
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) TwoFactorRecoveryCodeCountUnused(_ context.Context, userUUID string) (int, error) {
+	count := 0
+
+	for _, c := range r.RecoveryCodes {
+		if c.UserUUID == userUUID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *FakeRepository) TwoFactorDelete(_ context.Context, userUUID string) error {
+	r.TOTPs = slices.DeleteFunc(r.TOTPs, func(t TOTP) bool {
+		return t.UserUUID == userUUID
+	})
+	r.RecoveryCodes = slices.DeleteFunc(r.RecoveryCodes, func(c RecoveryCode) bool {
+		return c.UserUUID == userUUID
+	})
+
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// Service handles two-factor authentication operations.
+type Service struct {
+	r    Repository
+	hmac *hash.HMAC
+}
+
+// NewService initializes and returns a two-factor authentication Service.
+func NewService(r Repository, hmacKey string) (*Service, error) {
+	if hmacKey == "" {
+		return &Service{}, ErrHmacKeyRequired
+	}
+
+	return &Service{
+		r:    r,
+		hmac: hash.NewHMAC(hmacKey),
+	}, nil
+}
+
+// ByUserUUID returns the TOTP enrollment for a given user.
+func (s *Service) ByUserUUID(ctx context.Context, userUUID string) (TOTP, error) {
+	if userUUID == "" {
+		return TOTP{}, ErrUserUUIDRequired
+	}
+
+	return s.r.TwoFactorTOTPGetByUserUUID(ctx, userUUID)
+}
+
+// IsEnabled returns whether a given user has enabled two-factor authentication.
+func (s *Service) IsEnabled(ctx context.Context, userUUID string) (bool, error) {
+	t, err := s.ByUserUUID(ctx, userUUID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return t.Enabled, nil
+}
+
+// RecoveryCodesRemaining returns the number of recovery codes a user has not used yet.
+func (s *Service) RecoveryCodesRemaining(ctx context.Context, userUUID string) (int, error) {
+	if userUUID == "" {
+		return 0, ErrUserUUIDRequired
+	}
+
+	return s.r.TwoFactorRecoveryCodeCountUnused(ctx, userUUID)
+}
+
+// Setup starts the TOTP enrollment of a user, generating a new secret.
+//
+// Two-factor authentication is only enabled once the user has confirmed the enrollment
+// with a valid code.
+func (s *Service) Setup(ctx context.Context, u user.User) (TOTP, error) {
+	if u.UUID == "" {
+		return TOTP{}, ErrUserUUIDRequired
+	}
+
+	t, err := newTOTP(u.UUID, u.Email)
+	if err != nil {
+		return TOTP{}, err
+	}
+
+	if err := s.r.TwoFactorTOTPSetup(ctx, t); err != nil {
+		return TOTP{}, err
+	}
+
+	return t, nil
+}
+
+// Confirm enables two-factor authentication for a user that has started their TOTP
+// enrollment, provided code is valid.
+//
+// It returns clear-text recovery codes, that must be displayed to the user only once.
+func (s *Service) Confirm(ctx context.Context, userUUID string, code string) ([]string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return []string{}, ErrCodeRequired
+	}
+
+	t, err := s.ByUserUUID(ctx, userUUID)
+	if err != nil {
+		return []string{}, err
+	}
+
+	if t.Enabled {
+		return []string{}, ErrAlreadyEnabled
+	}
+
+	step, err := t.matchStep(code, time.Now().UTC())
+	if err != nil {
+		return []string{}, err
+	}
+
+	now := time.Now().UTC()
+
+	codes := make([]string, RecoveryCodeCount)
+	recoveryCodes := make([]RecoveryCode, RecoveryCodeCount)
+
+	for i := range RecoveryCodeCount {
+		recoveryCode, err := newRecoveryCode()
+		if err != nil {
+			return []string{}, err
+		}
+
+		codeHash, err := s.hmac.Hash(normalizeRecoveryCode(recoveryCode))
+		if err != nil {
+			return []string{}, err
+		}
+
+		codes[i] = recoveryCode
+		recoveryCodes[i] = RecoveryCode{
+			UserUUID:  userUUID,
+			CodeHash:  codeHash,
+			CreatedAt: now,
+		}
+	}
+
+	enable := TOTPEnable{
+		UserUUID:     userUUID,
+		LastUsedStep: step,
+		UpdatedAt:    now,
+	}
+
+	if err := s.r.TwoFactorTOTPEnable(ctx, enable, recoveryCodes); err != nil {
+		return []string{}, err
+	}
+
+	return codes, nil
+}
+
+// Verify checks the second factor provided by a user when logging in, which may either
+// be a TOTP code or an unused recovery code.
+//
+// A given code can only be accepted once.
+func (s *Service) Verify(ctx context.Context, userUUID string, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrCodeRequired
+	}
+
+	t, err := s.ByUserUUID(ctx, userUUID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+
+	if !t.Enabled {
+		return ErrNotEnabled
+	}
+
+	if isTOTPCode(code) {
+		step, err := t.matchStep(code, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		err = s.r.TwoFactorTOTPLastUsedStepUpdate(ctx, userUUID, step)
+		if errors.Is(err, ErrCodeAlreadyUsed) {
+			return ErrCodeInvalid
+		}
+
+		return err
+	}
+
+	codeHash, err := s.hmac.Hash(normalizeRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+
+	err = s.r.TwoFactorRecoveryCodeConsume(ctx, userUUID, codeHash)
+	if errors.Is(err, ErrNotFound) {
+		return ErrCodeInvalid
+	}
+
+	return err
+}
+
+// Disable disables two-factor authentication for a given user, deleting their TOTP enrollment
+// and recovery codes.
+func (s *Service) Disable(ctx context.Context, userUUID string) error {
+	if userUUID == "" {
+		return ErrUserUUIDRequired
+	}
+
+	return s.r.TwoFactorDelete(ctx, userUUID)
+}
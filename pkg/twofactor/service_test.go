@@ -0,0 +1,281 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	testHmacKey = "test-hmac-key"
+)
+
+var (
+	testUser = user.User{
+		UUID:  "f6a9e2b4-3c1d-4e5f-8a7b-9c0d1e2f3a4b",
+		Email: "jane.doe@example.org",
+	}
+)
+
+func newTestService(t *testing.T) (*Service, *FakeRepository) {
+	t.Helper()
+
+	r := &FakeRepository{}
+
+	s, err := NewService(r, testHmacKey)
+	if err != nil {
+		t.Fatalf("failed to create service: %q", err)
+	}
+
+	return s, r
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := totp.GenerateCodeCustom(secret, time.Now().UTC(), totpValidateOpts)
+	if err != nil {
+		t.Fatalf("failed to generate code: %q", err)
+	}
+
+	return code
+}
+
+func TestNewService(t *testing.T) {
+	_, err := NewService(&FakeRepository{}, "")
+	if !errors.Is(err, ErrHmacKeyRequired) {
+		t.Errorf("want %q, got %q", ErrHmacKeyRequired, err)
+	}
+}
+
+func TestServiceSetupAndConfirm(t *testing.T) {
+	t.Run("enrollment is disabled until confirmed", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		totpSetup, err := s.Setup(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if totpSetup.Secret == "" {
+			t.Error("want a secret to be generated")
+		}
+
+		enabled, err := s.IsEnabled(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if enabled {
+			t.Error("want two-factor authentication to be disabled before confirmation")
+		}
+
+		uri := totpSetup.ProvisioningURI(testUser.Email)
+		if !strings.HasPrefix(uri, "otpauth://totp/SparkleMuffin:jane.doe@example.org?") {
+			t.Errorf("unexpected provisioning URI: %q", uri)
+		}
+		if !strings.Contains(uri, "secret="+totpSetup.Secret) {
+			t.Errorf("want the provisioning URI to contain the secret, got %q", uri)
+		}
+	})
+
+	t.Run("invalid confirmation code", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		if _, err := s.Setup(t.Context(), testUser); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		_, err := s.Confirm(t.Context(), testUser.UUID, "000000")
+		if !errors.Is(err, ErrCodeInvalid) {
+			t.Errorf("want %q, got %q", ErrCodeInvalid, err)
+		}
+	})
+
+	t.Run("valid confirmation code", func(t *testing.T) {
+		s, r := newTestService(t)
+
+		totpSetup, err := s.Setup(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		codes, err := s.Confirm(t.Context(), testUser.UUID, currentCode(t, totpSetup.Secret))
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(codes) != RecoveryCodeCount {
+			t.Errorf("want %d recovery codes, got %d", RecoveryCodeCount, len(codes))
+		}
+
+		for _, rc := range r.RecoveryCodes {
+			for _, code := range codes {
+				if rc.CodeHash == code {
+					t.Fatalf("recovery codes must not be stored in clear text")
+				}
+			}
+		}
+
+		enabled, err := s.IsEnabled(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if !enabled {
+			t.Error("want two-factor authentication to be enabled")
+		}
+
+		if _, err := s.Setup(t.Context(), testUser); !errors.Is(err, ErrAlreadyEnabled) {
+			t.Errorf("want %q, got %q", ErrAlreadyEnabled, err)
+		}
+	})
+}
+
+func TestServiceVerify(t *testing.T) {
+	setup := func(t *testing.T) (*Service, TOTP, []string) {
+		t.Helper()
+
+		s, r := newTestService(t)
+
+		totpSetup, err := s.Setup(t.Context(), testUser)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		// confirm with a code from the previous time step, so that the current code
+		// has not been used yet
+		previousStep := time.Now().UTC().Add(-totpPeriod * time.Second)
+		code, err := totp.GenerateCodeCustom(totpSetup.Secret, previousStep, totpValidateOpts)
+		if err != nil {
+			t.Fatalf("failed to generate code: %q", err)
+		}
+
+		codes, err := s.Confirm(t.Context(), testUser.UUID, code)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		return s, r.TOTPs[0], codes
+	}
+
+	t.Run("not enabled", func(t *testing.T) {
+		s, _ := newTestService(t)
+
+		err := s.Verify(t.Context(), testUser.UUID, "123456")
+		if !errors.Is(err, ErrNotEnabled) {
+			t.Errorf("want %q, got %q", ErrNotEnabled, err)
+		}
+	})
+
+	t.Run("empty code", func(t *testing.T) {
+		s, _, _ := setup(t)
+
+		err := s.Verify(t.Context(), testUser.UUID, " ")
+		if !errors.Is(err, ErrCodeRequired) {
+			t.Errorf("want %q, got %q", ErrCodeRequired, err)
+		}
+	})
+
+	t.Run("TOTP code cannot be replayed", func(t *testing.T) {
+		s, enrollment, _ := setup(t)
+		code := currentCode(t, enrollment.Secret)
+
+		if err := s.Verify(t.Context(), testUser.UUID, code); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		err := s.Verify(t.Context(), testUser.UUID, code)
+		if !errors.Is(err, ErrCodeInvalid) {
+			t.Errorf("want %q, got %q", ErrCodeInvalid, err)
+		}
+	})
+
+	t.Run("recovery code can only be used once", func(t *testing.T) {
+		s, _, codes := setup(t)
+
+		// recovery codes are case and dash insensitive
+		code := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+
+		if err := s.Verify(t.Context(), testUser.UUID, code); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		err := s.Verify(t.Context(), testUser.UUID, codes[0])
+		if !errors.Is(err, ErrCodeInvalid) {
+			t.Errorf("want %q, got %q", ErrCodeInvalid, err)
+		}
+
+		remaining, err := s.RecoveryCodesRemaining(t.Context(), testUser.UUID)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if remaining != RecoveryCodeCount-1 {
+			t.Errorf("want %d remaining recovery codes, got %d", RecoveryCodeCount-1, remaining)
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		s, enrollment, _ := setup(t)
+
+		if err := s.Disable(t.Context(), testUser.UUID); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		err := s.Verify(t.Context(), testUser.UUID, currentCode(t, enrollment.Secret))
+		if !errors.Is(err, ErrNotEnabled) {
+			t.Errorf("want %q, got %q", ErrNotEnabled, err)
+		}
+	})
+}
+
+func TestServiceChallenge(t *testing.T) {
+	s, _ := newTestService(t)
+
+	challenge, err := s.IssueChallenge(testUser.UUID)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	t.Run("valid challenge", func(t *testing.T) {
+		got, err := s.ChallengeUserUUID(challenge)
+		if err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+		if got != testUser.UUID {
+			t.Errorf("want user UUID %q, got %q", testUser.UUID, got)
+		}
+	})
+
+	t.Run("tampered challenge", func(t *testing.T) {
+		parts := strings.Split(challenge, ".")
+		tampered := "00000000-0000-4000-8000-000000000000." + parts[1] + "." + parts[2]
+
+		if _, err := s.ChallengeUserUUID(tampered); !errors.Is(err, ErrChallengeInvalid) {
+			t.Errorf("want %q, got %q", ErrChallengeInvalid, err)
+		}
+	})
+
+	t.Run("challenge signed with another key", func(t *testing.T) {
+		other, err := NewService(&FakeRepository{}, "another-hmac-key")
+		if err != nil {
+			t.Fatalf("failed to create service: %q", err)
+		}
+
+		if _, err := other.ChallengeUserUUID(challenge); !errors.Is(err, ErrChallengeInvalid) {
+			t.Errorf("want %q, got %q", ErrChallengeInvalid, err)
+		}
+	})
+
+	t.Run("malformed challenge", func(t *testing.T) {
+		if _, err := s.ChallengeUserUUID("garbage"); !errors.Is(err, ErrChallengeInvalid) {
+			t.Errorf("want %q, got %q", ErrChallengeInvalid, err)
+		}
+	})
+}
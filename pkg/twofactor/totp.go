@@ -0,0 +1,133 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package twofactor
+
+import (
+	"crypto/subtle"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// Issuer is the name displayed by authenticator applications for SparkleMuffin accounts.
+	Issuer string = "SparkleMuffin"
+
+	totpDigits     = otp.DigitsSix
+	totpPeriod     = 30
+	totpSecretSize = 20
+
+	// totpSkew is the number of periods accepted before and after the current one,
+	// to tolerate clock drift between the server and the user's device.
+	totpSkew = 1
+)
+
+var (
+	totpValidateOpts = totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    totpDigits,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+)
+
+// TOTP represents a user's enrollment in RFC 6238 Time-Based One-Time Password
+// authentication.
+type TOTP struct {
+	UserUUID string
+
+	// Secret is the Base32-encoded secret shared with the user's authenticator application.
+	Secret string
+
+	// Enabled is false until the user has confirmed their enrollment with a valid code.
+	Enabled bool
+
+	// LastUsedStep is the time step of the last accepted code, used to prevent
+	// a code from being used more than once.
+	LastUsedStep int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// newTOTP generates a new, unconfirmed TOTP enrollment with a random secret.
+func newTOTP(userUUID string, accountName string) (TOTP, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      Issuer,
+		AccountName: accountName,
+		Period:      totpPeriod,
+		SecretSize:  totpSecretSize,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return TOTP{}, err
+	}
+
+	now := time.Now().UTC()
+
+	return TOTP{
+		UserUUID:  userUUID,
+		Secret:    key.Secret(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// ProvisioningURI returns the otpauth:// URI used to configure an authenticator application,
+// usually by scanning it as a QR code.
+func (t TOTP) ProvisioningURI(accountName string) string {
+	v := url.Values{}
+	v.Set("secret", t.Secret)
+	v.Set("issuer", Issuer)
+	v.Set("algorithm", otp.AlgorithmSHA1.String())
+	v.Set("digits", totpDigits.String())
+	v.Set("period", strconv.Itoa(totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + Issuer + ":" + accountName,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
+
+// matchStep returns the time step for which a code is valid, within the accepted clock skew.
+func (t TOTP) matchStep(code string, now time.Time) (int64, error) {
+	currentStep := now.Unix() / totpPeriod
+
+	for step := currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		expected, err := totp.GenerateCodeCustom(t.Secret, time.Unix(step*totpPeriod, 0), totpValidateOpts)
+		if err != nil {
+			return 0, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+
+	return 0, ErrCodeInvalid
+}
+
+// isTOTPCode returns whether a code has the format of a TOTP code, rather than a recovery code.
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits.Length() {
+		return false
+	}
+
+	return strings.Trim(code, "0123456789") == ""
+}
+
+// TOTPEnable represents the confirmation of a TOTP enrollment.
+type TOTPEnable struct {
+	UserUUID     string
+	LastUsedStep int64
+	UpdatedAt    time.Time
+}
@@ -14,6 +14,7 @@ var (
 	ErrEmailAlreadyRegistered       = errors.New("user: email already registered")
 	ErrEmailRequired                = errors.New("user: email required")
 	ErrNickNameAlreadyRegistered    = errors.New("user: nickname already registered")
+	ErrNickNameConfirmationMismatch = errors.New("user: nickname confirmation does not match")
 	ErrNickNameInvalid              = errors.New("user: invalid nickname")
 	ErrNickNameRequired             = errors.New("user: nickname required")
 	ErrPasswordConfirmationMismatch = errors.New("user: new password and confirmation do not match")
@@ -21,5 +22,6 @@ var (
 	ErrPasswordIncorrect            = errors.New("user: incorrect password")
 	ErrPasswordRequired             = errors.New("user: password required")
 	ErrPasswordTooShort             = fmt.Errorf("user: password too short (minimum: %d characters)", MinPasswordLength)
+	ErrReauthenticationRequired     = errors.New("user: recent login required")
 	ErrUUIDRequired                 = errors.New("user: UUID required")
 )
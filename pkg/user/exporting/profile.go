@@ -0,0 +1,55 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package exporting
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+// JsonProfile represents a user's account information, as included in a data archive.
+type JsonProfile struct {
+	UUID        string `json:"uuid"`
+	Email       string `json:"email"`
+	NickName    string `json:"nick_name"`
+	DisplayName string `json:"display_name"`
+	IsAdmin     bool   `json:"is_admin"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newJsonProfile(u user.User) JsonProfile {
+	return JsonProfile{
+		UUID:        u.UUID,
+		Email:       u.Email,
+		NickName:    u.NickName,
+		DisplayName: u.DisplayName,
+		IsAdmin:     u.IsAdmin,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}
+}
+
+// JsonPreferences represents a user's feed preferences, as included in a data archive.
+type JsonPreferences struct {
+	ShowEntries        feed.EntryVisibility `json:"show_entries"`
+	ShowEntrySummaries bool                 `json:"show_entry_summaries"`
+	DigestFrequency    feed.DigestFrequency `json:"digest_frequency"`
+	DigestMarkAsRead   bool                 `json:"digest_mark_as_read"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newJsonPreferences(p feed.Preferences) JsonPreferences {
+	return JsonPreferences{
+		ShowEntries:        p.ShowEntries,
+		ShowEntrySummaries: p.ShowEntrySummaries,
+		DigestFrequency:    p.DigestFrequency,
+		DigestMarkAsRead:   p.DigestMarkAsRead,
+		UpdatedAt:          p.UpdatedAt,
+	}
+}
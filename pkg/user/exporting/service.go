@@ -0,0 +1,186 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package exporting
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/virtualtam/netscape-go/v2"
+	"github.com/virtualtam/opml-go"
+
+	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+const (
+	// ArchiveProfileFilename is the name of the file containing account information.
+	ArchiveProfileFilename = "profile.json"
+
+	// ArchiveBookmarksJSONFilename is the name of the file containing bookmarks, as a JSON document.
+	ArchiveBookmarksJSONFilename = "bookmarks.json"
+
+	// ArchiveBookmarksNetscapeFilename is the name of the file containing bookmarks, as a Netscape Bookmark File.
+	ArchiveBookmarksNetscapeFilename = "bookmarks.htm"
+
+	// ArchiveFeedSubscriptionsFilename is the name of the file containing feed subscriptions, as an OPML document.
+	ArchiveFeedSubscriptionsFilename = "feeds.opml"
+
+	// ArchiveFeedEntryStatusesFilename is the name of the file containing the read status of feed entries.
+	ArchiveFeedEntryStatusesFilename = "feed_entries.json"
+
+	// ArchiveFeedPreferencesFilename is the name of the file containing feed preferences.
+	ArchiveFeedPreferencesFilename = "feed_preferences.json"
+)
+
+// Service handles personal data export operations.
+type Service struct {
+	bookmarkExportingService *bookmarkexporting.Service
+	feedExportingService     *feedexporting.Service
+	feedService              *feed.Service
+}
+
+// NewService initializes and returns a new Service.
+func NewService(
+	bookmarkExportingService *bookmarkexporting.Service,
+	feedExportingService *feedexporting.Service,
+	feedService *feed.Service,
+) *Service {
+	return &Service{
+		bookmarkExportingService: bookmarkExportingService,
+		feedExportingService:     feedExportingService,
+		feedService:              feedService,
+	}
+}
+
+// ExportAsZipArchive exports all of a given user's personal data as a Zip archive.
+//
+// The archive contains the user's account information, bookmarks (JSON and Netscape
+// Bookmark File), feed subscriptions (OPML), feed entry read statuses and feed preferences.
+func (s *Service) ExportAsZipArchive(ctx context.Context, u user.User) ([]byte, error) {
+	files, err := s.archiveFiles(ctx, u)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	now := time.Now().UTC()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		header := &zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: now,
+		}
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return []byte{}, fmt.Errorf("failed to add %q to archive: %w", file.name, err)
+		}
+
+		if _, err := fw.Write(file.data); err != nil {
+			return []byte{}, fmt.Errorf("failed to write %q to archive: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return []byte{}, fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+func (s *Service) archiveFiles(ctx context.Context, u user.User) ([]archiveFile, error) {
+	profile, err := marshalJSON(newJsonProfile(u))
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	bookmarksJSONDocument, err := s.bookmarkExportingService.ExportAsJSONDocument(ctx, u.UUID, bookmarkexporting.VisibilityAll)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	bookmarksJSON, err := marshalJSON(bookmarksJSONDocument)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	bookmarksNetscapeDocument, err := s.bookmarkExportingService.ExportAsNetscapeDocument(ctx, u.UUID, bookmarkexporting.VisibilityAll)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	bookmarksNetscape, err := netscape.Marshal(bookmarksNetscapeDocument)
+	if err != nil {
+		return []archiveFile{}, fmt.Errorf("failed to marshal Netscape document: %w", err)
+	}
+
+	feedsOPMLDocument, err := s.feedExportingService.ExportAsOPMLDocument(ctx, u)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	feedsOPML, err := opml.Marshal(feedsOPMLDocument)
+	if err != nil {
+		return []archiveFile{}, fmt.Errorf("failed to marshal OPML document: %w", err)
+	}
+
+	entryStatuses, err := s.feedExportingService.ExportEntryStatuses(ctx, u.UUID)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	if entryStatuses == nil {
+		entryStatuses = []feedexporting.EntryStatus{}
+	}
+
+	feedEntries, err := marshalJSON(entryStatuses)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	preferences, err := s.feedService.PreferencesByUserUUID(ctx, u.UUID)
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	feedPreferences, err := marshalJSON(newJsonPreferences(preferences))
+	if err != nil {
+		return []archiveFile{}, err
+	}
+
+	files := []archiveFile{
+		{name: ArchiveProfileFilename, data: profile},
+		{name: ArchiveBookmarksJSONFilename, data: bookmarksJSON},
+		{name: ArchiveBookmarksNetscapeFilename, data: bookmarksNetscape},
+		{name: ArchiveFeedSubscriptionsFilename, data: feedsOPML},
+		{name: ArchiveFeedEntryStatusesFilename, data: feedEntries},
+		{name: ArchiveFeedPreferencesFilename, data: feedPreferences},
+	}
+
+	return files, nil
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	marshaled, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to marshal JSON document: %w", err)
+	}
+
+	return marshaled, nil
+}
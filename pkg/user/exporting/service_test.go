@@ -0,0 +1,213 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package exporting
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+	bookmarkexporting "github.com/virtualtam/sparklemuffin/pkg/bookmark/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/feed"
+	feedexporting "github.com/virtualtam/sparklemuffin/pkg/feed/exporting"
+	"github.com/virtualtam/sparklemuffin/pkg/user"
+)
+
+var _ bookmarkexporting.Repository = &fakeBookmarkRepository{}
+
+type fakeBookmarkRepository struct {
+	bookmarks []bookmark.Bookmark
+}
+
+func (r *fakeBookmarkRepository) BookmarkGetAll(_ context.Context, userUUID string) ([]bookmark.Bookmark, error) {
+	var bookmarks []bookmark.Bookmark
+
+	for _, b := range r.bookmarks {
+		if b.UserUUID == userUUID {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	return bookmarks, nil
+}
+
+func (r *fakeBookmarkRepository) BookmarkGetAllPrivate(_ context.Context, _ string) ([]bookmark.Bookmark, error) {
+	return []bookmark.Bookmark{}, nil
+}
+
+func (r *fakeBookmarkRepository) BookmarkGetAllPublic(_ context.Context, _ string) ([]bookmark.Bookmark, error) {
+	return []bookmark.Bookmark{}, nil
+}
+
+func (r *fakeBookmarkRepository) BookmarkGetByUIDs(_ context.Context, _ string, _ []string) ([]bookmark.Bookmark, error) {
+	return []bookmark.Bookmark{}, nil
+}
+
+var _ feedexporting.Repository = &fakeFeedRepository{}
+
+type fakeFeedRepository struct {
+	entryStatuses []feedexporting.EntryStatus
+}
+
+func (r *fakeFeedRepository) FeedCategorySubscriptionsGetAll(_ context.Context, _ string) ([]feedexporting.CategorySubscriptions, error) {
+	return []feedexporting.CategorySubscriptions{}, nil
+}
+
+func (r *fakeFeedRepository) FeedEntryStatusesGetAll(_ context.Context, _ string) ([]feedexporting.EntryStatus, error) {
+	return r.entryStatuses, nil
+}
+
+func TestServiceExportAsZipArchive(t *testing.T) {
+	fake := faker.New()
+
+	testUser := user.FakeUser(t, &fake)
+	otherUser := user.FakeUser(t, &fake)
+
+	bookmarkRepository := &fakeBookmarkRepository{
+		bookmarks: []bookmark.Bookmark{
+			{
+				UserUUID: testUser.UUID,
+				URL:      "https://example.tld",
+				Title:    "Example",
+				Private:  true,
+			},
+			{
+				UserUUID: otherUser.UUID,
+				URL:      "https://other.tld",
+				Title:    "Other user's bookmark",
+			},
+		},
+	}
+	feedRepository := &fakeFeedRepository{
+		entryStatuses: []feedexporting.EntryStatus{
+			{
+				FeedURL: "https://example.tld/feed.xml",
+				URL:     "https://example.tld/posts/1",
+				Title:   "First post",
+				Read:    true,
+			},
+		},
+	}
+	preferencesRepository := &feed.FakeRepository{
+		Preferences: map[string]feed.Preferences{
+			testUser.UUID: {
+				UserUUID:        testUser.UUID,
+				ShowEntries:     feed.EntryVisibilityUnread,
+				DigestFrequency: feed.DigestFrequencyWeekly,
+			},
+		},
+	}
+
+	s := NewService(
+		bookmarkexporting.NewService(bookmarkRepository),
+		feedexporting.NewService(feedRepository),
+		feed.NewService(preferencesRepository, nil, nil, nil),
+	)
+
+	archive, err := s.ExportAsZipArchive(t.Context(), testUser)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("failed to open archive: %q", err)
+	}
+
+	files := make(map[string][]byte, len(zr.File))
+
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			t.Fatalf("failed to open %q: %q", zf.Name, err)
+		}
+
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %q: %q", zf.Name, err)
+		}
+
+		files[zf.Name] = data
+	}
+
+	wantFilenames := []string{
+		ArchiveProfileFilename,
+		ArchiveBookmarksJSONFilename,
+		ArchiveBookmarksNetscapeFilename,
+		ArchiveFeedSubscriptionsFilename,
+		ArchiveFeedEntryStatusesFilename,
+		ArchiveFeedPreferencesFilename,
+	}
+
+	for _, filename := range wantFilenames {
+		if _, ok := files[filename]; !ok {
+			t.Errorf("want archive to contain %q", filename)
+		}
+	}
+
+	if len(files) != len(wantFilenames) {
+		t.Errorf("want %d files, got %d", len(wantFilenames), len(files))
+	}
+
+	var profile JsonProfile
+	if err := json.Unmarshal(files[ArchiveProfileFilename], &profile); err != nil {
+		t.Fatalf("failed to unmarshal profile: %q", err)
+	}
+
+	if profile.UUID != testUser.UUID {
+		t.Errorf("want profile UUID %q, got %q", testUser.UUID, profile.UUID)
+	}
+	if profile.Email != testUser.Email {
+		t.Errorf("want profile Email %q, got %q", testUser.Email, profile.Email)
+	}
+	if strings.Contains(string(files[ArchiveProfileFilename]), "password") {
+		t.Error("want profile not to contain password information")
+	}
+
+	var bookmarksDocument bookmarkexporting.JsonDocument
+	if err := json.Unmarshal(files[ArchiveBookmarksJSONFilename], &bookmarksDocument); err != nil {
+		t.Fatalf("failed to unmarshal bookmarks: %q", err)
+	}
+
+	if len(bookmarksDocument.Bookmarks) != 1 {
+		t.Fatalf("want 1 bookmark, got %d", len(bookmarksDocument.Bookmarks))
+	}
+	if bookmarksDocument.Bookmarks[0].URL != "https://example.tld" {
+		t.Errorf("want bookmark URL %q, got %q", "https://example.tld", bookmarksDocument.Bookmarks[0].URL)
+	}
+
+	if !strings.Contains(string(files[ArchiveBookmarksNetscapeFilename]), "https://example.tld") {
+		t.Error("want Netscape bookmark file to contain the user's bookmark")
+	}
+
+	var entryStatuses []feedexporting.EntryStatus
+	if err := json.Unmarshal(files[ArchiveFeedEntryStatusesFilename], &entryStatuses); err != nil {
+		t.Fatalf("failed to unmarshal feed entries: %q", err)
+	}
+
+	if !slices.Equal(entryStatuses, feedRepository.entryStatuses) {
+		t.Errorf("want feed entries %v, got %v", feedRepository.entryStatuses, entryStatuses)
+	}
+
+	var preferences JsonPreferences
+	if err := json.Unmarshal(files[ArchiveFeedPreferencesFilename], &preferences); err != nil {
+		t.Fatalf("failed to unmarshal feed preferences: %q", err)
+	}
+
+	if preferences.ShowEntries != feed.EntryVisibilityUnread {
+		t.Errorf("want ShowEntries %q, got %q", feed.EntryVisibilityUnread, preferences.ShowEntries)
+	}
+	if preferences.DigestFrequency != feed.DigestFrequencyWeekly {
+		t.Errorf("want DigestFrequency %q, got %q", feed.DigestFrequencyWeekly, preferences.DigestFrequency)
+	}
+}
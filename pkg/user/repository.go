@@ -51,6 +51,9 @@ type Repository interface {
 	// UserUpdate updates an existing user.
 	UserUpdate(ctx context.Context, u User) error
 
+	// UserUpdateAdmin updates an existing user's administration privileges.
+	UserUpdateAdmin(ctx context.Context, adminUpdate AdminUpdate) error
+
 	// UserUpdateInfo updates an existing user's account information.
 	UserUpdateInfo(ctx context.Context, info InfoUpdate) error
 
@@ -127,6 +127,18 @@ func (r *FakeRepository) UserUpdate(_ context.Context, user User) error {
 	return ErrNotFound
 }
 
+func (r *FakeRepository) UserUpdateAdmin(_ context.Context, adminUpdate AdminUpdate) error {
+	for index, existingUser := range r.Users {
+		if existingUser.UUID == adminUpdate.UserUUID {
+			r.Users[index].IsAdmin = adminUpdate.IsAdmin
+			r.Users[index].UpdatedAt = adminUpdate.UpdatedAt
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
 func (r *FakeRepository) UserUpdateInfo(_ context.Context, info InfoUpdate) error {
 	for index, existingUser := range r.Users {
 		if existingUser.UUID == info.UserUUID {
@@ -8,31 +8,50 @@ import (
 	"errors"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/rs/zerolog/log"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
 )
 
 // Service handles operations for the user domain.
 type Service struct {
-	r Repository
+	r              Repository
+	passwordHasher *hash.PasswordHasher
 }
 
 // NewService initializes and returns a User Service.
-func NewService(r Repository) *Service {
+func NewService(r Repository, passwordHasher *hash.PasswordHasher) *Service {
 	return &Service{
-		r: r,
+		r:              r,
+		passwordHasher: passwordHasher,
 	}
 }
 
 // Add adds a new User.
 func (s *Service) Add(ctx context.Context, user User) error {
 	user.Normalize()
-	if err := user.ValidateForAddition(ctx, s.r); err != nil {
+	if err := user.ValidateForAddition(ctx, s.r, s.passwordHasher); err != nil {
 		return err
 	}
 
 	return s.r.UserAdd(ctx, user)
 }
 
+// ValidateForAddition normalizes a new User and ensures it can be added, without saving it.
+//
+// The User's clear-text password is hashed, then cleared.
+func (s *Service) ValidateForAddition(ctx context.Context, user *User) error {
+	user.Normalize()
+	return user.ValidateForAddition(ctx, s.r, s.passwordHasher)
+}
+
+// ValidateForPasswordHashUpdate ensures a User's new password can be saved, without saving it.
+//
+// The User's clear-text password is hashed, then cleared.
+func (s *Service) ValidateForPasswordHashUpdate(user *User) error {
+	return user.ValidateForPasswordHashUpdate(s.passwordHasher)
+}
+
 // All returns a list of all users.
 func (s *Service) All(ctx context.Context) ([]User, error) {
 	return s.r.UserGetAll(ctx)
@@ -46,20 +65,26 @@ func (s *Service) Authenticate(ctx context.Context, email, password string) (Use
 		return User{}, err
 	}
 
-	err = bcrypt.CompareHashAndPassword(
-		[]byte(user.PasswordHash),
-		[]byte(password),
-	)
-
-	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-		return User{}, ErrPasswordIncorrect
-	} else if err != nil {
+	if err := s.comparePassword(user.PasswordHash, password); err != nil {
 		return User{}, err
 	}
 
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		// The password is known to be correct at this point: upgrade its hash to
+		// the configured algorithm and parameters.
+		if err := s.rehashPassword(ctx, &user, password); err != nil {
+			log.Warn().Err(err).Str("user_uuid", user.UUID).Msg("user: failed to rehash password")
+		}
+	}
+
 	return user, nil
 }
 
+// ByEmail returns the user registered with a given email address.
+func (s *Service) ByEmail(ctx context.Context, email string) (User, error) {
+	return s.getUserByEmail(ctx, email)
+}
+
 // ByNickName returns the user corresponding to a given NickName.
 func (s *Service) ByNickName(ctx context.Context, nick string) (User, error) {
 	user := User{NickName: nick}
@@ -97,18 +122,78 @@ func (s *Service) DeleteByUUID(ctx context.Context, userUUID string) error {
 	return s.r.UserDeleteByUUID(ctx, userUUID)
 }
 
+// DeleteAccount deletes an authenticated user's own account and all related data,
+// once they have confirmed their current password and nickname.
+//
+// Users who may not know their password can leave it empty, if they have logged in within
+// the AccountDeletionReauthenticationWindow.
+func (s *Service) DeleteAccount(ctx context.Context, deletion AccountDeletion) error {
+	user := User{
+		UUID:     deletion.UserUUID,
+		NickName: deletion.NickNameConfirmation,
+		Password: deletion.CurrentPassword,
+	}
+	user.normalizeNickName()
+
+	passwordless := deletion.PasswordOptional && user.Password == ""
+
+	if err := user.requireUUID(); err != nil {
+		return err
+	}
+	if passwordless {
+		if time.Since(deletion.AuthenticatedAt) > AccountDeletionReauthenticationWindow {
+			return ErrReauthenticationRequired
+		}
+	} else if err := user.requirePassword(); err != nil {
+		return err
+	}
+
+	existingUser, err := s.ByUUID(ctx, user.UUID)
+	if err != nil {
+		return err
+	}
+
+	if user.NickName != existingUser.NickName {
+		return ErrNickNameConfirmationMismatch
+	}
+
+	if !passwordless {
+		if err := s.comparePassword(existingUser.PasswordHash, user.Password); err != nil {
+			return err
+		}
+	}
+
+	return s.r.UserDeleteByUUID(ctx, user.UUID)
+}
+
 // Update updates an existing user.
 func (s *Service) Update(ctx context.Context, user User) error {
 	user.Normalize()
 	user.UpdatedAt = time.Now().UTC()
 
-	if err := user.ValidateForUpdate(ctx, s.r); err != nil {
+	if err := user.ValidateForUpdate(ctx, s.r, s.passwordHasher); err != nil {
 		return err
 	}
 
 	return s.r.UserUpdate(ctx, user)
 }
 
+// UpdateAdmin grants or revokes administration privileges for an existing user.
+func (s *Service) UpdateAdmin(ctx context.Context, userUUID string, isAdmin bool) error {
+	user := User{UUID: userUUID}
+	if err := user.requireUUID(); err != nil {
+		return err
+	}
+
+	adminUpdate := AdminUpdate{
+		UserUUID:  userUUID,
+		IsAdmin:   isAdmin,
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	return s.r.UserUpdateAdmin(ctx, adminUpdate)
+}
+
 // UpdateInfo updates an existing user's account information.
 func (s *Service) UpdateInfo(ctx context.Context, info InfoUpdate) error {
 	user := User{
@@ -148,14 +233,7 @@ func (s *Service) UpdatePassword(ctx context.Context, passwordUpdate PasswordUpd
 		return err
 	}
 
-	err = bcrypt.CompareHashAndPassword(
-		[]byte(existingUser.PasswordHash),
-		[]byte(user.Password),
-	)
-	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-		return ErrPasswordIncorrect
-	}
-	if err != nil {
+	if err := s.comparePassword(existingUser.PasswordHash, user.Password); err != nil {
 		return err
 	}
 
@@ -170,7 +248,7 @@ func (s *Service) UpdatePassword(ctx context.Context, passwordUpdate PasswordUpd
 		Password: passwordUpdate.NewPassword,
 	}
 
-	if err := user.ValidateForPasswordHashUpdate(); err != nil {
+	if err := user.ValidateForPasswordHashUpdate(s.passwordHasher); err != nil {
 		return err
 	}
 
@@ -193,3 +271,37 @@ func (s *Service) getUserByEmail(ctx context.Context, email string) (User, error
 
 	return s.r.UserGetByEmail(ctx, user.Email)
 }
+
+// comparePassword checks a clear-text password against a hash.
+func (s *Service) comparePassword(passwordHash, password string) error {
+	err := s.passwordHasher.Compare(passwordHash, password)
+	if errors.Is(err, hash.ErrPasswordMismatch) {
+		return ErrPasswordIncorrect
+	}
+
+	return err
+}
+
+// rehashPassword hashes a User's verified clear-text password with the configured
+// algorithm, and saves the new hash.
+func (s *Service) rehashPassword(ctx context.Context, user *User, password string) error {
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	passwordHashUpdate := PasswordHashUpdate{
+		UserUUID:     user.UUID,
+		PasswordHash: passwordHash,
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := s.r.UserUpdatePasswordHash(ctx, passwordHashUpdate); err != nil {
+		return err
+	}
+
+	user.PasswordHash = passwordHashUpdate.PasswordHash
+	user.UpdatedAt = passwordHashUpdate.UpdatedAt
+
+	return nil
+}
@@ -5,10 +5,13 @@ package user
 
 import (
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/jaswdr/faker/v2"
-	"golang.org/x/crypto/bcrypt"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
 )
 
 func TestServiceAdd(t *testing.T) {
@@ -141,7 +144,7 @@ func TestServiceAdd(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			err := s.Add(t.Context(), tc.user)
 
@@ -200,7 +203,7 @@ func TestServiceAll(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{Users: tc.repositoryUsers}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			got, err := s.All(t.Context())
 
@@ -265,7 +268,7 @@ func TestServiceByNickName(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{Users: tc.repositoryUsers}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			got, err := s.ByNickName(t.Context(), tc.nick)
 
@@ -321,7 +324,7 @@ func TestServiceByUUID(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.tname, func(t *testing.T) {
 			r := &FakeRepository{Users: tc.repositoryUsers}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			got, err := s.ByUUID(t.Context(), tc.userUUID)
 
@@ -418,7 +421,16 @@ func TestServiceAuthenticate(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			// Use the algorithm and cost of the test hashes, so they are not upgraded on login.
+			passwordHasher, err := hash.NewPasswordHasher(hash.PasswordHasherConfig{
+				Algorithm:  hash.PasswordAlgorithmBcrypt,
+				BcryptCost: 10,
+			})
+			if err != nil {
+				t.Fatalf("failed to create password hasher: %q", err)
+			}
+
+			s := NewService(r, passwordHasher)
 
 			got, err := s.Authenticate(t.Context(), tc.email, tc.password)
 
@@ -441,6 +453,96 @@ func TestServiceAuthenticate(t *testing.T) {
 	}
 }
 
+func TestServiceAuthenticateRehash(t *testing.T) {
+	const (
+		userUUID = "6f0c2a4e-8b1d-4c3e-9a5f-7d2b1e0c4a6f"
+		email    = "found@domain.tld"
+		password = "test"
+		// bcrypt hash of "test", with a cost of 10
+		bcryptHash = "$2b$10$J0z6wKdvrPMmbUgg.uhhROv0Zp4bFQ19GnTshpsazLpK2l5fOnEmy"
+	)
+
+	passwordHasher := FakePasswordHasher(t)
+
+	currentHash, err := passwordHasher.Hash(password)
+	if err != nil {
+		t.Fatalf("failed to hash password: %q", err)
+	}
+
+	cases := []struct {
+		tname        string
+		passwordHash string
+		password     string
+		wantErr      error
+		wantRehash   bool
+	}{
+		{
+			tname:        "legacy bcrypt hash",
+			passwordHash: bcryptHash,
+			password:     password,
+			wantRehash:   true,
+		},
+		{
+			tname:        "legacy bcrypt hash, wrong password",
+			passwordHash: bcryptHash,
+			password:     "nottest",
+			wantErr:      ErrPasswordIncorrect,
+		},
+		{
+			tname:        "current hash",
+			passwordHash: currentHash,
+			password:     password,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Users: []User{
+					{
+						UUID:         userUUID,
+						Email:        email,
+						PasswordHash: tc.passwordHash,
+					},
+				},
+			}
+			s := NewService(r, passwordHasher)
+
+			got, err := s.Authenticate(t.Context(), email, tc.password)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			savedHash := r.Users[0].PasswordHash
+
+			if !tc.wantRehash {
+				if savedHash != tc.passwordHash {
+					t.Errorf("want password hash to be unchanged, got %q", savedHash)
+				}
+				return
+			}
+
+			if savedHash == tc.passwordHash {
+				t.Fatal("want password hash to be upgraded")
+			}
+			if got.PasswordHash != savedHash {
+				t.Errorf("want returned password hash %q, got %q", savedHash, got.PasswordHash)
+			}
+			if passwordHasher.NeedsRehash(savedHash) {
+				t.Errorf("want upgraded password hash to use current parameters, got %q", savedHash)
+			}
+			if err := passwordHasher.Compare(savedHash, tc.password); err != nil {
+				t.Errorf("want upgraded password hash to match password, got %q", err)
+			}
+		})
+	}
+}
+
 func TestServiceDeleteByUUID(t *testing.T) {
 	cases := []struct {
 		tname           string
@@ -471,7 +573,7 @@ func TestServiceDeleteByUUID(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			err := s.DeleteByUUID(t.Context(), tc.userUUID)
 
@@ -492,18 +594,233 @@ func TestServiceDeleteByUUID(t *testing.T) {
 	}
 }
 
+func TestServiceDeleteAccount(t *testing.T) {
+	fake := faker.New()
+
+	existingUser := FakeUser(t, &fake)
+	currentPassword := existingUser.Password
+	if err := existingUser.hashPassword(FakePasswordHasher(t))(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		tname           string
+		repositoryUsers []User
+		deletion        AccountDeletion
+		wantErr         error
+	}{
+		// Nominal cases.
+		{
+			tname:           "account deletion",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				CurrentPassword:      currentPassword,
+				NickNameConfirmation: existingUser.NickName,
+			},
+		},
+		{
+			tname:           "account deletion (nickname confirmation with whitespace)",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				CurrentPassword:      currentPassword,
+				NickNameConfirmation: "  " + existingUser.NickName + "  ",
+			},
+		},
+
+		{
+			tname:           "account deletion without password after a recent login",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				NickNameConfirmation: existingUser.NickName,
+				PasswordOptional:     true,
+				AuthenticatedAt:      time.Now().UTC().Add(-time.Minute),
+			},
+		},
+
+		// Error cases.
+		{
+			tname:   "empty deletion",
+			wantErr: ErrUUIDRequired,
+		},
+		{
+			tname:           "password optional, login too old",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				NickNameConfirmation: existingUser.NickName,
+				PasswordOptional:     true,
+				AuthenticatedAt:      time.Now().UTC().Add(-AccountDeletionReauthenticationWindow - time.Minute),
+			},
+			wantErr: ErrReauthenticationRequired,
+		},
+		{
+			tname:           "password optional, incorrect password",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				CurrentPassword:      "isitnottest?",
+				NickNameConfirmation: existingUser.NickName,
+				PasswordOptional:     true,
+				AuthenticatedAt:      time.Now().UTC(),
+			},
+			wantErr: ErrPasswordIncorrect,
+		},
+		{
+			tname:           "password optional, nickname confirmation mismatch",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				NickNameConfirmation: "someone-else",
+				PasswordOptional:     true,
+				AuthenticatedAt:      time.Now().UTC(),
+			},
+			wantErr: ErrNickNameConfirmationMismatch,
+		},
+		{
+			tname: "empty password",
+			deletion: AccountDeletion{
+				UserUUID: existingUser.UUID,
+			},
+			wantErr: ErrPasswordRequired,
+		},
+		{
+			tname: "user not found",
+			deletion: AccountDeletion{
+				UserUUID:        existingUser.UUID,
+				CurrentPassword: currentPassword,
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			tname:           "nickname confirmation mismatch",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				CurrentPassword:      currentPassword,
+				NickNameConfirmation: "someone-else",
+			},
+			wantErr: ErrNickNameConfirmationMismatch,
+		},
+		{
+			tname:           "incorrect password",
+			repositoryUsers: []User{existingUser},
+			deletion: AccountDeletion{
+				UserUUID:             existingUser.UUID,
+				CurrentPassword:      "isitnottest?",
+				NickNameConfirmation: existingUser.NickName,
+			},
+			wantErr: ErrPasswordIncorrect,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Users: slices.Clone(tc.repositoryUsers),
+			}
+			s := NewService(r, FakePasswordHasher(t))
+
+			err := s.DeleteAccount(t.Context(), tc.deletion)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if len(r.Users) != 0 {
+				t.Errorf("want user to be deleted, %d user(s) remaining", len(r.Users))
+			}
+		})
+	}
+}
+
+func TestServiceUpdateAdmin(t *testing.T) {
+	cases := []struct {
+		tname           string
+		repositoryUsers []User
+		userUUID        string
+		isAdmin         bool
+		wantErr         error
+	}{
+		{
+			tname:   "empty UUID",
+			wantErr: ErrUUIDRequired,
+		},
+		{
+			tname:    "unknown UUID",
+			userUUID: "b52cd2d5-89f7-4489-b023-722896ca3f98",
+			wantErr:  ErrNotFound,
+		},
+		{
+			tname: "grant administration privileges",
+			repositoryUsers: []User{
+				{UUID: "ebd1bec1-e15f-4502-ae97-a631f7d7df91"},
+			},
+			userUUID: "ebd1bec1-e15f-4502-ae97-a631f7d7df91",
+			isAdmin:  true,
+		},
+		{
+			tname: "revoke administration privileges",
+			repositoryUsers: []User{
+				{UUID: "ebd1bec1-e15f-4502-ae97-a631f7d7df91", IsAdmin: true},
+			},
+			userUUID: "ebd1bec1-e15f-4502-ae97-a631f7d7df91",
+			isAdmin:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{
+				Users: tc.repositoryUsers,
+			}
+			s := NewService(r, FakePasswordHasher(t))
+
+			err := s.UpdateAdmin(t.Context(), tc.userUUID, tc.isAdmin)
+
+			if tc.wantErr != nil {
+				if errors.Is(err, tc.wantErr) {
+					return
+				}
+				if err == nil {
+					t.Fatalf("want error %q, got nil", tc.wantErr)
+				}
+				t.Fatalf("want error %q, got %q", tc.wantErr, err)
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if r.Users[0].IsAdmin != tc.isAdmin {
+				t.Errorf("want IsAdmin %t, got %t", tc.isAdmin, r.Users[0].IsAdmin)
+			}
+		})
+	}
+}
+
 func TestServiceUpdate(t *testing.T) {
 	fake := faker.New()
 	existingUser := FakeUser(t, &fake)
 
 	newPassword := FakePassword(t, &fake)
-	newPasswordHashBytes, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	newPasswordHash, err := FakePasswordHasher(t).Hash(newPassword)
 	if err != nil {
 		t.Fatalf("failed to generate new password hash: %v", err)
 	}
 
-	newPasswordHash := string(newPasswordHashBytes)
-
 	shortPassword := FakePassword(t, &fake)[:MinPasswordLength-1]
 
 	cases := []struct {
@@ -620,7 +937,7 @@ func TestServiceUpdate(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			err := s.Update(t.Context(), tc.user)
 
@@ -804,7 +1121,7 @@ func TestServiceUpdateInfo(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			err := s.UpdateInfo(t.Context(), tc.info)
 
@@ -830,7 +1147,7 @@ func TestServiceUpdatePassword(t *testing.T) {
 
 	existingUser := FakeUser(t, &fake)
 	currentPassword := existingUser.Password
-	if err := existingUser.hashPassword(); err != nil {
+	if err := existingUser.hashPassword(FakePasswordHasher(t))(); err != nil {
 		t.Fatal(err)
 	}
 
@@ -929,7 +1246,7 @@ func TestServiceUpdatePassword(t *testing.T) {
 			r := &FakeRepository{
 				Users: tc.repositoryUsers,
 			}
-			s := NewService(r)
+			s := NewService(r, FakePasswordHasher(t))
 
 			err := s.UpdatePassword(t.Context(), tc.passwordUpdate)
 
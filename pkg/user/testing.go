@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/jaswdr/faker/v2"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
 )
 
 // GenerateFakeUser generates a new user for testing.
@@ -34,3 +36,22 @@ func FakePassword(t *testing.T, fake *faker.Faker) string {
 	pattern := strings.Repeat("*", fake.IntBetween(MinPasswordLength, 2*MinPasswordLength))
 	return fake.Asciify(pattern)
 }
+
+// FakePasswordHasher returns a PasswordHasher using low-cost Argon2id parameters, for testing.
+func FakePasswordHasher(t *testing.T) *hash.PasswordHasher {
+	t.Helper()
+
+	passwordHasher, err := hash.NewPasswordHasher(hash.PasswordHasherConfig{
+		Algorithm: hash.PasswordAlgorithmArgon2id,
+		Argon2id: hash.Argon2idParams{
+			Memory:      64,
+			Iterations:  1,
+			Parallelism: 1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create password hasher: %q", err)
+	}
+
+	return passwordHasher
+}
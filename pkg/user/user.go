@@ -10,7 +10,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
+
+	"github.com/virtualtam/sparklemuffin/internal/hash"
 )
 
 // User represents a registered user.
@@ -88,7 +89,7 @@ func (u *User) Normalize() {
 }
 
 // ValidateForAddition ensures mandatory fields are properly set when adding a new User.
-func (u *User) ValidateForAddition(ctx context.Context, r ValidationRepository) error {
+func (u *User) ValidateForAddition(ctx context.Context, r ValidationRepository, passwordHasher *hash.PasswordHasher) error {
 	fns := []func() error{
 		u.requireEmail,
 		u.ensureEmailIsNotRegistered(ctx, r),
@@ -98,7 +99,7 @@ func (u *User) ValidateForAddition(ctx context.Context, r ValidationRepository)
 		u.requireDisplayName,
 		u.requirePassword,
 		u.requirePasswordLength,
-		u.hashPassword,
+		u.hashPassword(passwordHasher),
 		u.requirePasswordHash,
 		u.requireUUID,
 	}
@@ -113,7 +114,7 @@ func (u *User) ValidateForAddition(ctx context.Context, r ValidationRepository)
 }
 
 // ValidateForUpdate ensures mandatory fields are properly set when updating an existing User.
-func (u *User) ValidateForUpdate(ctx context.Context, r ValidationRepository) error {
+func (u *User) ValidateForUpdate(ctx context.Context, r ValidationRepository, passwordHasher *hash.PasswordHasher) error {
 	fns := []func() error{
 		u.requireUUID,
 		u.requireEmail,
@@ -124,7 +125,7 @@ func (u *User) ValidateForUpdate(ctx context.Context, r ValidationRepository) er
 		u.requireDisplayName,
 		u.requirePassword,
 		u.requirePasswordLength,
-		u.hashPassword,
+		u.hashPassword(passwordHasher),
 		u.requirePasswordHash,
 	}
 
@@ -160,12 +161,12 @@ func (u *User) ValidateForInfoUpdate(ctx context.Context, r ValidationRepository
 
 // ValidateForPasswordHashUpdate ensures mandatory fields are properly set when updating an existing User's
 // password hash.
-func (u *User) ValidateForPasswordHashUpdate() error {
+func (u *User) ValidateForPasswordHashUpdate(passwordHasher *hash.PasswordHasher) error {
 	fns := []func() error{
 		u.requireUUID,
 		u.requirePassword,
 		u.requirePasswordLength,
-		u.hashPassword,
+		u.hashPassword(passwordHasher),
 		u.requirePasswordHash,
 	}
 
@@ -310,18 +311,27 @@ func (u *User) ensureNickNameIsValid() error {
 	return nil
 }
 
-func (u *User) hashPassword() error {
-	h, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return err
-	}
+func (u *User) hashPassword(passwordHasher *hash.PasswordHasher) func() error {
+	return func() error {
+		h, err := passwordHasher.Hash(u.Password)
+		if err != nil {
+			return err
+		}
 
-	u.PasswordHash = string(h)
+		u.PasswordHash = h
 
-	// Clear the clear-text password as soon as it is hashed.
-	u.Password = ""
+		// Clear the clear-text password as soon as it is hashed.
+		u.Password = ""
 
-	return nil
+		return nil
+	}
+}
+
+// AdminUpdate represents a change of administration privileges for a user.
+type AdminUpdate struct {
+	UserUUID  string
+	IsAdmin   bool
+	UpdatedAt time.Time
 }
 
 // InfoUpdate represents an account information update for an authenticated
@@ -334,6 +344,27 @@ type InfoUpdate struct {
 	UpdatedAt   time.Time
 }
 
+// AccountDeletionReauthenticationWindow is the period after logging in during which users
+// who may not know their password can delete their account without entering it.
+const AccountDeletionReauthenticationWindow = 10 * time.Minute
+
+// AccountDeletion represents the deletion of an authenticated user's own
+// account, confirmed by their current password and nickname.
+//
+// Users whose password may be unknown to them, e.g. accounts provisioned through single
+// sign-on, can confirm with their nickname only, provided they have logged in recently.
+type AccountDeletion struct {
+	UserUUID             string
+	CurrentPassword      string
+	NickNameConfirmation string
+
+	// PasswordOptional is set if the user may not know their password.
+	PasswordOptional bool
+
+	// AuthenticatedAt is the time the user last logged in.
+	AuthenticatedAt time.Time
+}
+
 // PasswordUpdate represents a password change for an authenticated user.
 type PasswordUpdate struct {
 	UserUUID                string
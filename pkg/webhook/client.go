@@ -0,0 +1,115 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+)
+
+const (
+	// HeaderDelivery holds the UUID of the Delivery, which stays the same across retries.
+	HeaderDelivery = "X-SparkleMuffin-Delivery"
+
+	// HeaderEvent holds the Event that triggered the Delivery.
+	HeaderEvent = "X-SparkleMuffin-Event"
+
+	// HeaderSignature holds the HMAC-SHA256 signature of the request body,
+	// formatted as "sha256=<hex digest>".
+	HeaderSignature = "X-SparkleMuffin-Signature"
+
+	signaturePrefix = "sha256="
+
+	// responseBodyMaxBytes bounds how much of the response body is read before closing it.
+	responseBodyMaxBytes = 64 * 1024
+)
+
+// A Client performs outgoing HTTP requests to deliver webhook payloads.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient initializes and returns a Client using httpClient to perform requests as-is.
+//
+// This client must only be used when HTTP/HTTPS traffic goes through a proxy, or for testing.
+func NewClient(httpClient *http.Client, userAgent string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		userAgent:  userAgent,
+	}
+}
+
+// NewSafeClient initializes and returns a Client backed by an SSRF-guarded http.Client.
+//
+// It MUST NOT be used when HTTP/HTTPS traffic goes through a proxy (otherwise all requests will be blocked).
+func NewSafeClient(userAgent string, timeout time.Duration) (*Client, error) {
+	transport, err := httpsafe.NewSafeTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			// Do not follow redirects: the signature is bound to the configured URL.
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return NewClient(httpClient, userAgent), nil
+}
+
+// Send posts a PendingDelivery's payload to its Webhook URL, and returns the response status code.
+//
+// An error is returned if the request fails or the response status code is not 2xx.
+func (c *Client) Send(ctx context.Context, pd PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pd.URL, bytes.NewReader(pd.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("webhook: failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(HeaderDelivery, pd.UUID)
+	req.Header.Set(HeaderEvent, string(pd.Event))
+	req.Header.Set(HeaderSignature, Sign(pd.Secret, pd.Payload))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, responseBodyMaxBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook: unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of a payload, as sent in the HeaderSignature header.
+//
+// Receivers can authenticate deliveries by computing the HMAC-SHA256 of the raw request
+// body with the Webhook secret, and comparing it with the header value in constant time.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
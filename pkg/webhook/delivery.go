@@ -0,0 +1,127 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxDeliveryAttempts is the number of attempts after which a Delivery is marked as failed.
+	MaxDeliveryAttempts = 8
+
+	deliveryRetryBaseDelay = 1 * time.Minute
+	deliveryRetryMaxDelay  = 6 * time.Hour
+
+	// deliveryErrorMaxLength bounds the error message persisted for a failed attempt.
+	deliveryErrorMaxLength = 512
+)
+
+// DeliveryStatus represents the state of a Delivery in the queue.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending indicates the Delivery is waiting for its next attempt.
+	DeliveryStatusPending DeliveryStatus = "PENDING"
+
+	// DeliveryStatusSucceeded indicates the remote endpoint acknowledged the Delivery.
+	DeliveryStatusSucceeded DeliveryStatus = "SUCCEEDED"
+
+	// DeliveryStatusFailed indicates the Delivery was abandoned after MaxDeliveryAttempts.
+	DeliveryStatusFailed DeliveryStatus = "FAILED"
+)
+
+// Delivery represents a Payload queued for delivery to a Webhook.
+type Delivery struct {
+	UUID        string
+	WebhookUUID string
+
+	Event   Event
+	Payload []byte
+
+	Status             DeliveryStatus
+	Attempts           int
+	ResponseStatusCode int
+	Error              string
+
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// PendingDelivery holds a Delivery along with the Webhook data required to send it.
+type PendingDelivery struct {
+	Delivery
+
+	URL    string
+	Secret string
+}
+
+// newDelivery initializes and returns a new pending Delivery for a given Payload.
+func newDelivery(webhookUUID string, payload Payload) (Delivery, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return Delivery{
+		UUID:          generatedUUID.String(),
+		WebhookUUID:   webhookUUID,
+		Event:         payload.Event,
+		Payload:       encoded,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: payload.CreatedAt,
+		CreatedAt:     payload.CreatedAt,
+		UpdatedAt:     payload.CreatedAt,
+	}, nil
+}
+
+// recordAttempt updates the Delivery with the outcome of a delivery attempt,
+// and schedules the next attempt with an exponential backoff if needed.
+func (d *Delivery) recordAttempt(now time.Time, statusCode int, attemptErr error) {
+	d.Attempts++
+	d.ResponseStatusCode = statusCode
+	d.UpdatedAt = now
+
+	if attemptErr == nil {
+		d.Status = DeliveryStatusSucceeded
+		d.Error = ""
+		return
+	}
+
+	d.Error = attemptErr.Error()
+	if len(d.Error) > deliveryErrorMaxLength {
+		d.Error = d.Error[:deliveryErrorMaxLength]
+	}
+
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+
+	d.NextAttemptAt = now.Add(retryDelay(d.Attempts))
+}
+
+// retryDelay returns the delay to wait for after a given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := deliveryRetryBaseDelay
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+
+		if delay >= deliveryRetryMaxDelay {
+			return deliveryRetryMaxDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,25 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import "errors"
+
+var (
+	ErrNotFound                    = errors.New("webhook: not found")
+	ErrEventsRequired              = errors.New("webhook: at least one event required")
+	ErrEventUnknown                = errors.New("webhook: unknown event")
+	ErrFilterConflict              = errors.New("webhook: cannot filter on both a category and a subscription")
+	ErrFeedCategoryUUIDInvalid     = errors.New("webhook: invalid feed category UUID")
+	ErrFeedSubscriptionUUIDInvalid = errors.New("webhook: invalid feed subscription UUID")
+	ErrSecretRequired              = errors.New("webhook: secret required")
+	ErrURLBlocked                  = errors.New("webhook: URL resolves to a blocked destination address")
+	ErrURLInvalid                  = errors.New("webhook: invalid URL")
+	ErrURLNoHost                   = errors.New("webhook: missing URL host")
+	ErrURLNoScheme                 = errors.New("webhook: missing URL scheme")
+	ErrURLRequired                 = errors.New("webhook: URL required")
+	ErrURLUnsupportedScheme        = errors.New("webhook: unsupported URL scheme")
+	ErrUserUUIDRequired            = errors.New("webhook: UserUUID required")
+	ErrUUIDInvalid                 = errors.New("webhook: invalid UUID")
+	ErrUUIDRequired                = errors.New("webhook: UUID required")
+)
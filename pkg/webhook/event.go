@@ -0,0 +1,118 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+// Event represents a type of notification a Webhook can subscribe to.
+type Event string
+
+const (
+	// EventBookmarkAdded is emitted when a user adds a new bookmark.
+	EventBookmarkAdded Event = "bookmark.added"
+
+	// EventBookmarkDeleted is emitted when a user deletes a bookmark.
+	EventBookmarkDeleted Event = "bookmark.deleted"
+
+	// EventBookmarkUpdated is emitted when a user edits a bookmark.
+	EventBookmarkUpdated Event = "bookmark.updated"
+
+	// EventFeedEntryCreated is emitted when a new entry is synchronized for one of the user's
+	// feed subscriptions.
+	EventFeedEntryCreated Event = "feed.entry.created"
+)
+
+var (
+	// AllEvents lists all supported events.
+	AllEvents = []Event{
+		EventBookmarkAdded,
+		EventBookmarkUpdated,
+		EventBookmarkDeleted,
+		EventFeedEntryCreated,
+	}
+)
+
+// Payload represents the JSON document sent to a Webhook's URL.
+type Payload struct {
+	Event     Event     `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Bookmark  *BookmarkPayload  `json:"bookmark,omitempty"`
+	FeedEntry *FeedEntryPayload `json:"feed_entry,omitempty"`
+}
+
+// BookmarkPayload holds the bookmark data sent with bookmark events.
+type BookmarkPayload struct {
+	UID         string    `json:"uid"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Private     bool      `json:"private"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+}
+
+func newBookmarkPayload(b bookmark.Bookmark) *BookmarkPayload {
+	return &BookmarkPayload{
+		UID:         b.UID,
+		URL:         b.URL,
+		Title:       b.Title,
+		Description: b.Description,
+		Private:     b.Private,
+		Tags:        b.Tags,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
+
+// FeedEntry represents a newly created feed entry, as seen from a user's subscription.
+type FeedEntry struct {
+	UID         string
+	URL         string
+	Title       string
+	Summary     string
+	PublishedAt time.Time
+	CreatedAt   time.Time
+
+	FeedTitle string
+	FeedURL   string
+
+	SubscriptionUUID string
+	CategoryUUID     string
+	CategoryName     string
+}
+
+// FeedEntryPayload holds the feed entry data sent with feed entry events.
+type FeedEntryPayload struct {
+	UID         string    `json:"uid"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+
+	FeedTitle string `json:"feed_title"`
+	FeedURL   string `json:"feed_url"`
+
+	SubscriptionUUID string `json:"subscription_uuid"`
+	CategoryName     string `json:"category_name"`
+}
+
+func newFeedEntryPayload(e FeedEntry) *FeedEntryPayload {
+	return &FeedEntryPayload{
+		UID:              e.UID,
+		URL:              e.URL,
+		Title:            e.Title,
+		Summary:          e.Summary,
+		PublishedAt:      e.PublishedAt,
+		FeedTitle:        e.FeedTitle,
+		FeedURL:          e.FeedURL,
+		SubscriptionUUID: e.SubscriptionUUID,
+		CategoryName:     e.CategoryName,
+	}
+}
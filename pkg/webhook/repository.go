@@ -0,0 +1,58 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// Repository provides access to user webhooks and their delivery queue.
+type Repository interface {
+	// WebhookAdd adds a new Webhook.
+	WebhookAdd(ctx context.Context, w Webhook) error
+
+	// WebhookDelete deletes a given Webhook and its deliveries.
+	WebhookDelete(ctx context.Context, userUUID string, webhookUUID string) error
+
+	// WebhookGetAll returns all webhooks for a given user.
+	WebhookGetAll(ctx context.Context, userUUID string) ([]Webhook, error)
+
+	// WebhookGetByUUID returns a given Webhook.
+	WebhookGetByUUID(ctx context.Context, userUUID string, webhookUUID string) (Webhook, error)
+
+	// WebhookGetManyByEvent returns all active webhooks subscribed to a given Event, for all users.
+	WebhookGetManyByEvent(ctx context.Context, event Event) ([]Webhook, error)
+
+	// WebhookGetManyByUserAndEvent returns all active webhooks subscribed to a given Event, for a given user.
+	WebhookGetManyByUserAndEvent(ctx context.Context, userUUID string, event Event) ([]Webhook, error)
+
+	// WebhookUpdate updates an existing Webhook.
+	WebhookUpdate(ctx context.Context, w Webhook) error
+
+	// WebhookFeedEntryQueuePending queues deliveries for at most n feed entries that have not been
+	// considered for delivery yet, and marks these entries as considered, within a single transaction.
+	//
+	// For each Webhook, newDeliveries is called with the pending entries of the subscriptions of the
+	// Webhook's user, matching the Webhook's category or subscription filter. It returns the number
+	// of entries that have been considered.
+	//
+	// This method must exclude entries created before the corresponding subscription,
+	// to avoid notifying about the existing entries of a newly subscribed feed.
+	WebhookFeedEntryQueuePending(ctx context.Context, webhooks []Webhook, n uint, newDeliveries func(w Webhook, entries []FeedEntry) ([]Delivery, error)) (int64, error)
+
+	// WebhookDeliveryAddMany queues a collection of new deliveries.
+	WebhookDeliveryAddMany(ctx context.Context, deliveries []Delivery) error
+
+	// WebhookDeliveryGetNByWebhook returns the n most recent deliveries for a given Webhook.
+	WebhookDeliveryGetNByWebhook(ctx context.Context, userUUID string, webhookUUID string, n uint) ([]Delivery, error)
+
+	// WebhookDeliveryGetNPending returns at most n pending deliveries due for an attempt before a given time.
+	//
+	// This method must only return deliveries for active webhooks.
+	WebhookDeliveryGetNPending(ctx context.Context, n uint, before time.Time) ([]PendingDelivery, error)
+
+	// WebhookDeliveryUpdate saves the outcome of a delivery attempt.
+	WebhookDeliveryUpdate(ctx context.Context, d Delivery) error
+}
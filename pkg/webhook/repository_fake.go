@@ -0,0 +1,206 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+)
+
+var _ Repository = &FakeRepository{}
+
+type FakeRepository struct {
+	Webhooks    []Webhook
+	Deliveries  []Delivery
+	FeedEntries []FeedEntry
+
+	// QueuedFeedEntryUIDs holds the UIDs of the feed entries that have been considered for delivery.
+	QueuedFeedEntryUIDs []string
+}
+
+func (r *FakeRepository) WebhookAdd(_ context.Context, w Webhook) error {
+	r.Webhooks = append(r.Webhooks, w)
+	return nil
+}
+
+func (r *FakeRepository) WebhookDelete(_ context.Context, userUUID string, webhookUUID string) error {
+	for index, w := range r.Webhooks {
+		if w.UserUUID == userUUID && w.UUID == webhookUUID {
+			r.Webhooks = slices.Delete(r.Webhooks, index, index+1)
+
+			r.Deliveries = slices.DeleteFunc(r.Deliveries, func(d Delivery) bool {
+				return d.WebhookUUID == webhookUUID
+			})
+
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) WebhookGetAll(_ context.Context, userUUID string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	for _, w := range r.Webhooks {
+		if w.UserUUID == userUUID {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (r *FakeRepository) WebhookGetByUUID(_ context.Context, userUUID string, webhookUUID string) (Webhook, error) {
+	for _, w := range r.Webhooks {
+		if w.UserUUID == userUUID && w.UUID == webhookUUID {
+			return w, nil
+		}
+	}
+
+	return Webhook{}, ErrNotFound
+}
+
+func (r *FakeRepository) WebhookGetManyByEvent(_ context.Context, event Event) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	for _, w := range r.Webhooks {
+		if w.Active && w.HasEvent(event) {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (r *FakeRepository) WebhookGetManyByUserAndEvent(_ context.Context, userUUID string, event Event) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	for _, w := range r.Webhooks {
+		if w.UserUUID == userUUID && w.Active && w.HasEvent(event) {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (r *FakeRepository) WebhookUpdate(_ context.Context, w Webhook) error {
+	for index, existing := range r.Webhooks {
+		if existing.UserUUID == w.UserUUID && existing.UUID == w.UUID {
+			r.Webhooks[index] = w
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+func (r *FakeRepository) WebhookFeedEntryQueuePending(_ context.Context, webhooks []Webhook, n uint, newDeliveries func(w Webhook, entries []FeedEntry) ([]Delivery, error)) (int64, error) {
+	var pending []FeedEntry
+
+	for _, entry := range r.FeedEntries {
+		if uint(len(pending)) == n {
+			break
+		}
+
+		if !slices.Contains(r.QueuedFeedEntryUIDs, entry.UID) {
+			pending = append(pending, entry)
+		}
+	}
+
+	for _, w := range webhooks {
+		var entries []FeedEntry
+
+		for _, entry := range pending {
+			if w.FeedCategoryUUID != "" && entry.CategoryUUID != w.FeedCategoryUUID {
+				continue
+			}
+
+			if w.FeedSubscriptionUUID != "" && entry.SubscriptionUUID != w.FeedSubscriptionUUID {
+				continue
+			}
+
+			entries = append(entries, entry)
+		}
+
+		if len(entries) == 0 {
+			continue
+		}
+
+		deliveries, err := newDeliveries(w, entries)
+		if err != nil {
+			return 0, err
+		}
+
+		r.Deliveries = append(r.Deliveries, deliveries...)
+	}
+
+	for _, entry := range pending {
+		r.QueuedFeedEntryUIDs = append(r.QueuedFeedEntryUIDs, entry.UID)
+	}
+
+	return int64(len(pending)), nil
+}
+
+func (r *FakeRepository) WebhookDeliveryAddMany(_ context.Context, deliveries []Delivery) error {
+	r.Deliveries = append(r.Deliveries, deliveries...)
+	return nil
+}
+
+func (r *FakeRepository) WebhookDeliveryGetNByWebhook(_ context.Context, _ string, webhookUUID string, n uint) ([]Delivery, error) {
+	var deliveries []Delivery
+
+	for _, d := range r.Deliveries {
+		if d.WebhookUUID == webhookUUID {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	if uint(len(deliveries)) > n {
+		deliveries = deliveries[:n]
+	}
+
+	return deliveries, nil
+}
+
+func (r *FakeRepository) WebhookDeliveryGetNPending(_ context.Context, n uint, before time.Time) ([]PendingDelivery, error) {
+	var pending []PendingDelivery
+
+	for _, d := range r.Deliveries {
+		if uint(len(pending)) >= n {
+			break
+		}
+
+		if d.Status != DeliveryStatusPending || d.NextAttemptAt.After(before) {
+			continue
+		}
+
+		for _, w := range r.Webhooks {
+			if w.UUID == d.WebhookUUID && w.Active {
+				pending = append(pending, PendingDelivery{Delivery: d, URL: w.URL, Secret: w.Secret})
+				break
+			}
+		}
+	}
+
+	return pending, nil
+}
+
+func (r *FakeRepository) WebhookDeliveryUpdate(_ context.Context, d Delivery) error {
+	for index, existing := range r.Deliveries {
+		if existing.UUID == d.UUID {
+			r.Deliveries[index] = d
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
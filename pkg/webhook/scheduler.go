@@ -0,0 +1,66 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	defaultDispatchInterval = 1 * time.Minute
+	defaultTaskTimeout      = 5 * time.Minute
+)
+
+// A Scheduler periodically dispatches webhook deliveries.
+type Scheduler struct {
+	s           *Service
+	locker      sync.Locker
+	interval    time.Duration
+	taskTimeout time.Duration
+}
+
+// NewScheduler initializes and returns a Scheduler.
+func NewScheduler(service *Service, locker sync.Locker) *Scheduler {
+	return &Scheduler{
+		s:           service,
+		locker:      locker,
+		interval:    defaultDispatchInterval,
+		taskTimeout: defaultTaskTimeout,
+	}
+}
+
+// Run periodically dispatches webhook deliveries.
+func (sc *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(sc.interval)
+	log.Info().
+		Dur("interval", sc.interval).
+		Msg("webhooks: delivery scheduler started")
+
+	for {
+		<-ticker.C
+
+		go func() {
+			jobID := ksuid.New().String()
+
+			sc.locker.Lock()
+			defer sc.locker.Unlock()
+
+			taskCtx, cancel := context.WithTimeout(ctx, sc.taskTimeout)
+			defer cancel()
+
+			if err := sc.s.Dispatch(taskCtx, jobID); err != nil {
+				log.
+					Error().
+					Err(err).
+					Str("job_id", jobID).
+					Msg("webhooks: failed to dispatch deliveries")
+			}
+		}()
+	}
+}
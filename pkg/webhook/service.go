@@ -0,0 +1,324 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	deliveriesToSend   uint = 50
+	deliveryLogSize    uint = 50
+	feedEntriesToQueue uint = 500
+
+	nWorkers int = 5
+)
+
+// Service handles operations related to user webhooks.
+type Service struct {
+	r Repository
+
+	client *Client
+
+	// validateURL may be nil (destination-blocking check skipped); pass
+	// httpsafe.ValidateURL for production use.
+	validateURL func(ctx context.Context, rawURL string) error
+}
+
+// NewService initializes and returns a Webhook Service.
+func NewService(r Repository, client *Client, validateURL func(ctx context.Context, rawURL string) error) *Service {
+	return &Service{
+		r:           r,
+		client:      client,
+		validateURL: validateURL,
+	}
+}
+
+// Add creates a new Webhook for a given user, with a randomly generated secret.
+func (s *Service) Add(ctx context.Context, w Webhook) (Webhook, error) {
+	newWebhook, err := NewWebhook(w.UserUUID)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	newWebhook.URL = w.URL
+	newWebhook.Events = w.Events
+	newWebhook.FeedCategoryUUID = w.FeedCategoryUUID
+	newWebhook.FeedSubscriptionUUID = w.FeedSubscriptionUUID
+
+	newWebhook.Normalize()
+
+	if err := newWebhook.ValidateForAddition(); err != nil {
+		return Webhook{}, err
+	}
+
+	if err := s.ensureURLIsNotBlocked(ctx, newWebhook.URL); err != nil {
+		return Webhook{}, err
+	}
+
+	if err := s.r.WebhookAdd(ctx, newWebhook); err != nil {
+		return Webhook{}, err
+	}
+
+	return newWebhook, nil
+}
+
+// All returns all webhooks for a given user.
+func (s *Service) All(ctx context.Context, userUUID string) ([]Webhook, error) {
+	return s.r.WebhookGetAll(ctx, userUUID)
+}
+
+// ByUUID returns a given Webhook.
+func (s *Service) ByUUID(ctx context.Context, userUUID string, webhookUUID string) (Webhook, error) {
+	w := Webhook{
+		UUID:     webhookUUID,
+		UserUUID: userUUID,
+	}
+
+	if err := w.ValidateForDeletion(); err != nil {
+		return Webhook{}, err
+	}
+
+	return s.r.WebhookGetByUUID(ctx, userUUID, webhookUUID)
+}
+
+// Delete deletes a given Webhook and its delivery log.
+func (s *Service) Delete(ctx context.Context, userUUID string, webhookUUID string) error {
+	w := Webhook{
+		UUID:     webhookUUID,
+		UserUUID: userUUID,
+	}
+
+	if err := w.ValidateForDeletion(); err != nil {
+		return err
+	}
+
+	if _, err := s.r.WebhookGetByUUID(ctx, userUUID, webhookUUID); err != nil {
+		return err
+	}
+
+	return s.r.WebhookDelete(ctx, userUUID, webhookUUID)
+}
+
+// Deliveries returns the most recent deliveries for a given Webhook.
+func (s *Service) Deliveries(ctx context.Context, userUUID string, webhookUUID string) ([]Delivery, error) {
+	if _, err := s.ByUUID(ctx, userUUID, webhookUUID); err != nil {
+		return []Delivery{}, err
+	}
+
+	return s.r.WebhookDeliveryGetNByWebhook(ctx, userUUID, webhookUUID, deliveryLogSize)
+}
+
+// RegenerateSecret replaces the secret used to sign a given Webhook's payloads.
+func (s *Service) RegenerateSecret(ctx context.Context, userUUID string, webhookUUID string) (Webhook, error) {
+	w, err := s.ByUUID(ctx, userUUID, webhookUUID)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	secret, err := rand.RandomBase64URLString(secretLength)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	w.Secret = secret
+	w.UpdatedAt = time.Now().UTC()
+
+	if err := s.r.WebhookUpdate(ctx, w); err != nil {
+		return Webhook{}, err
+	}
+
+	return w, nil
+}
+
+// Update updates an existing Webhook's settings.
+func (s *Service) Update(ctx context.Context, w Webhook) error {
+	existing, err := s.ByUUID(ctx, w.UserUUID, w.UUID)
+	if err != nil {
+		return err
+	}
+
+	existing.URL = w.URL
+	existing.Events = w.Events
+	existing.FeedCategoryUUID = w.FeedCategoryUUID
+	existing.FeedSubscriptionUUID = w.FeedSubscriptionUUID
+	existing.Active = w.Active
+	existing.UpdatedAt = time.Now().UTC()
+
+	existing.Normalize()
+
+	if err := existing.ValidateForUpdate(); err != nil {
+		return err
+	}
+
+	if err := s.ensureURLIsNotBlocked(ctx, existing.URL); err != nil {
+		return err
+	}
+
+	return s.r.WebhookUpdate(ctx, existing)
+}
+
+// NotifyBookmark queues deliveries of a bookmark Event for all the user's webhooks subscribed to it.
+func (s *Service) NotifyBookmark(ctx context.Context, event Event, b bookmark.Bookmark) error {
+	webhooks, err := s.r.WebhookGetManyByUserAndEvent(ctx, b.UserUUID, event)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload := Payload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Bookmark:  newBookmarkPayload(b),
+	}
+
+	deliveries := make([]Delivery, 0, len(webhooks))
+
+	for _, w := range webhooks {
+		d, err := newDelivery(w.UUID, payload)
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return s.r.WebhookDeliveryAddMany(ctx, deliveries)
+}
+
+// Dispatch queues deliveries for newly created feed entries, then attempts to send
+// all pending deliveries that are due.
+func (s *Service) Dispatch(ctx context.Context, jobID string) error {
+	now := time.Now().UTC()
+
+	if err := s.queueFeedEntries(ctx); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("webhooks: failed to queue feed entry deliveries")
+		return err
+	}
+
+	pendingDeliveries, err := s.r.WebhookDeliveryGetNPending(ctx, deliveriesToSend, now)
+	if err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("webhooks: failed to list pending deliveries")
+		return err
+	}
+
+	if len(pendingDeliveries) == 0 {
+		log.Debug().Str("job_id", jobID).Msg("webhooks: nothing to deliver")
+		return nil
+	}
+
+	workerPool := pool.New().WithErrors().WithMaxGoroutines(nWorkers)
+
+	for _, pd := range pendingDeliveries {
+		workerPool.Go(func() error {
+			return s.send(ctx, pd, jobID)
+		})
+	}
+
+	if err := workerPool.Wait(); err != nil {
+		log.
+			Error().
+			Err(err).
+			Str("job_id", jobID).
+			Msg("webhooks: failed to record some delivery attempts")
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) ensureURLIsNotBlocked(ctx context.Context, rawURL string) error {
+	if s.validateURL == nil {
+		return nil
+	}
+
+	if err := s.validateURL(ctx, rawURL); errors.Is(err, httpsafe.ErrIPBlocked) {
+		return ErrURLBlocked
+	}
+
+	return nil
+}
+
+// queueFeedEntries queues deliveries for feed entries that have not been considered for delivery yet.
+//
+// Entries are tracked individually rather than by creation date, as feed synchronization
+// transactions may commit their entries long after these have been created.
+func (s *Service) queueFeedEntries(ctx context.Context) error {
+	webhooks, err := s.r.WebhookGetManyByEvent(ctx, EventFeedEntryCreated)
+	if err != nil {
+		return err
+	}
+
+	newDeliveries := func(w Webhook, entries []FeedEntry) ([]Delivery, error) {
+		deliveries := make([]Delivery, 0, len(entries))
+
+		for _, entry := range entries {
+			payload := Payload{
+				Event:     EventFeedEntryCreated,
+				CreatedAt: entry.CreatedAt,
+				FeedEntry: newFeedEntryPayload(entry),
+			}
+
+			d, err := newDelivery(w.UUID, payload)
+			if err != nil {
+				return []Delivery{}, err
+			}
+
+			deliveries = append(deliveries, d)
+		}
+
+		return deliveries, nil
+	}
+
+	for {
+		nQueued, err := s.r.WebhookFeedEntryQueuePending(ctx, webhooks, feedEntriesToQueue, newDeliveries)
+		if err != nil {
+			return err
+		}
+
+		if nQueued < int64(feedEntriesToQueue) {
+			return nil
+		}
+	}
+}
+
+func (s *Service) send(ctx context.Context, pd PendingDelivery, jobID string) error {
+	statusCode, sendErr := s.client.Send(ctx, pd)
+
+	pd.recordAttempt(time.Now().UTC(), statusCode, sendErr)
+
+	if sendErr != nil {
+		log.
+			Warn().
+			Err(sendErr).
+			Str("delivery_uuid", pd.UUID).
+			Str("webhook_uuid", pd.WebhookUUID).
+			Int("attempts", pd.Attempts).
+			Str("status", string(pd.Status)).
+			Str("job_id", jobID).
+			Msg("webhooks: delivery attempt failed")
+	}
+
+	return s.r.WebhookDeliveryUpdate(ctx, pd.Delivery)
+}
@@ -0,0 +1,424 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/virtualtam/sparklemuffin/internal/http/httpsafe"
+	"github.com/virtualtam/sparklemuffin/pkg/bookmark"
+)
+
+const (
+	testUserUUID = "179206c8-2965-47a7-ba04-bf0a6a0b8d11"
+)
+
+func TestServiceAdd(t *testing.T) {
+	cases := []struct {
+		tname       string
+		webhook     Webhook
+		validateURL func(ctx context.Context, rawURL string) error
+		wantErr     error
+	}{
+		// nominal cases
+		{
+			tname: "bookmark events",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "  https://hooks.example.com/sparklemuffin  ",
+				Events:   []Event{EventBookmarkUpdated, EventBookmarkAdded, EventBookmarkAdded},
+			},
+		},
+		{
+			tname: "feed entry events filtered by category",
+			webhook: Webhook{
+				UserUUID:         testUserUUID,
+				URL:              "https://hooks.example.com/sparklemuffin",
+				Events:           []Event{EventFeedEntryCreated},
+				FeedCategoryUUID: "6e2c7a5e-4a57-4f5e-9d2c-0c7d1b2b3f40",
+			},
+		},
+
+		// error cases
+		{
+			tname: "missing URL",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				Events:   []Event{EventBookmarkAdded},
+			},
+			wantErr: ErrURLRequired,
+		},
+		{
+			tname: "unsupported URL scheme",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "ftp://hooks.example.com",
+				Events:   []Event{EventBookmarkAdded},
+			},
+			wantErr: ErrURLUnsupportedScheme,
+		},
+		{
+			tname: "missing URL host",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "https://",
+				Events:   []Event{EventBookmarkAdded},
+			},
+			wantErr: ErrURLNoHost,
+		},
+		{
+			tname: "no events",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "https://hooks.example.com",
+			},
+			wantErr: ErrEventsRequired,
+		},
+		{
+			tname: "unknown event",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "https://hooks.example.com",
+				Events:   []Event{"bookmark.exploded"},
+			},
+			wantErr: ErrEventUnknown,
+		},
+		{
+			tname: "category and subscription filters",
+			webhook: Webhook{
+				UserUUID:             testUserUUID,
+				URL:                  "https://hooks.example.com",
+				Events:               []Event{EventFeedEntryCreated},
+				FeedCategoryUUID:     "6e2c7a5e-4a57-4f5e-9d2c-0c7d1b2b3f40",
+				FeedSubscriptionUUID: "9b1f8e0c-2f8a-4f7e-8a7e-3c1f0f0b7d52",
+			},
+			wantErr: ErrFilterConflict,
+		},
+		{
+			tname: "invalid subscription filter",
+			webhook: Webhook{
+				UserUUID:             testUserUUID,
+				URL:                  "https://hooks.example.com",
+				Events:               []Event{EventFeedEntryCreated},
+				FeedSubscriptionUUID: "not-a-uuid",
+			},
+			wantErr: ErrFeedSubscriptionUUIDInvalid,
+		},
+		{
+			tname: "blocked destination",
+			webhook: Webhook{
+				UserUUID: testUserUUID,
+				URL:      "http://127.0.0.1:8080/hook",
+				Events:   []Event{EventBookmarkAdded},
+			},
+			validateURL: func(_ context.Context, _ string) error {
+				return httpsafe.ErrIPBlocked
+			},
+			wantErr: ErrURLBlocked,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tname, func(t *testing.T) {
+			r := &FakeRepository{}
+			s := NewService(r, nil, tc.validateURL)
+
+			got, err := s.Add(t.Context(), tc.webhook)
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("want error %q, got %q", tc.wantErr, err)
+				}
+				if len(r.Webhooks) != 0 {
+					t.Errorf("want no webhook to be saved, got %d", len(r.Webhooks))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("want no error, got %q", err)
+			}
+
+			if got.UUID == "" {
+				t.Error("want a generated UUID")
+			}
+			if got.Secret == "" {
+				t.Error("want a generated secret")
+			}
+			if !got.Active {
+				t.Error("want the webhook to be active")
+			}
+			if got.URL != "https://hooks.example.com/sparklemuffin" {
+				t.Errorf("want a normalized URL, got %q", got.URL)
+			}
+			for i := 1; i < len(got.Events); i++ {
+				if got.Events[i-1] >= got.Events[i] {
+					t.Errorf("want sorted, deduplicated events, got %v", got.Events)
+				}
+			}
+			if len(r.Webhooks) != 1 {
+				t.Fatalf("want 1 saved webhook, got %d", len(r.Webhooks))
+			}
+		})
+	}
+}
+
+func TestServiceNotifyBookmark(t *testing.T) {
+	r := &FakeRepository{
+		Webhooks: []Webhook{
+			{
+				UUID:     "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10",
+				UserUUID: testUserUUID,
+				Events:   []Event{EventBookmarkAdded},
+				Active:   true,
+			},
+			{
+				UUID:     "0d4b8f6a-7e2c-4f3b-8a1d-5c6e9f0a1b2c",
+				UserUUID: testUserUUID,
+				Events:   []Event{EventBookmarkAdded},
+				Active:   false,
+			},
+			{
+				UUID:     "3c7e1a9b-2d4f-4e6a-8b0c-9d1e2f3a4b5c",
+				UserUUID: testUserUUID,
+				Events:   []Event{EventBookmarkDeleted},
+				Active:   true,
+			},
+			{
+				UUID:     "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d",
+				UserUUID: "7c1b5e0a-1f3d-4b2a-9e8c-6d5a4b3c2e1f",
+				Events:   []Event{EventBookmarkAdded},
+				Active:   true,
+			},
+		},
+	}
+	s := NewService(r, nil, nil)
+
+	b := bookmark.Bookmark{
+		UID:      "2JZ3R0D9Z4rXDPEQcSPhPJ2ebSA",
+		UserUUID: testUserUUID,
+		URL:      "https://example.com",
+		Title:    "Example",
+		Tags:     []string{"example"},
+	}
+
+	if err := s.NotifyBookmark(t.Context(), EventBookmarkAdded, b); err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if len(r.Deliveries) != 1 {
+		t.Fatalf("want 1 delivery, got %d", len(r.Deliveries))
+	}
+
+	d := r.Deliveries[0]
+	if d.WebhookUUID != "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10" {
+		t.Errorf("want delivery for the active, subscribed webhook, got %q", d.WebhookUUID)
+	}
+	if d.Status != DeliveryStatusPending {
+		t.Errorf("want status %q, got %q", DeliveryStatusPending, d.Status)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %q", err)
+	}
+	if payload.Event != EventBookmarkAdded {
+		t.Errorf("want event %q, got %q", EventBookmarkAdded, payload.Event)
+	}
+	if payload.Bookmark == nil || payload.Bookmark.UID != b.UID {
+		t.Errorf("want bookmark payload for %q, got %+v", b.UID, payload.Bookmark)
+	}
+}
+
+func TestServiceDispatch(t *testing.T) {
+	webhookUUID := "8f2a3c1e-4a1b-4b0e-9b53-1e4d6a7c2b10"
+	categoryUUID := "6e2c7a5e-4a57-4f5e-9d2c-0c7d1b2b3f40"
+	secret := "s3cr3t"
+	now := time.Now().UTC()
+
+	t.Run("queues new feed entries and delivers signed payloads", func(t *testing.T) {
+		var received []*http.Request
+		var receivedBodies [][]byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Errorf("failed to read request body: %q", err)
+			}
+			received = append(received, req)
+			receivedBodies = append(receivedBodies, body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		r := &FakeRepository{
+			Webhooks: []Webhook{
+				{
+					UUID:             webhookUUID,
+					UserUUID:         testUserUUID,
+					URL:              server.URL,
+					Secret:           secret,
+					Events:           []Event{EventFeedEntryCreated},
+					FeedCategoryUUID: categoryUUID,
+					Active:           true,
+				},
+			},
+			FeedEntries: []FeedEntry{
+				{
+					UID:          "entry-already-checked",
+					CategoryUUID: categoryUUID,
+					CreatedAt:    now.Add(-2 * time.Hour),
+				},
+				{
+					// Committed by a feed synchronization that took longer than usual.
+					UID:          "entry-new",
+					Title:        "New entry",
+					CategoryUUID: categoryUUID,
+					CreatedAt:    now.Add(-3 * time.Hour),
+				},
+				{
+					UID:          "entry-other-category",
+					CategoryUUID: "9b1f8e0c-2f8a-4f7e-8a7e-3c1f0f0b7d52",
+					CreatedAt:    now.Add(-30 * time.Minute),
+				},
+			},
+			QueuedFeedEntryUIDs: []string{"entry-already-checked"},
+		}
+
+		s := NewService(r, NewClient(server.Client(), "sparklemuffin/test"), nil)
+
+		if err := s.Dispatch(t.Context(), "test-job"); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		if len(received) != 1 {
+			t.Fatalf("want 1 request, got %d", len(received))
+		}
+
+		req := received[0]
+		if got := req.Header.Get(HeaderEvent); got != string(EventFeedEntryCreated) {
+			t.Errorf("want event header %q, got %q", EventFeedEntryCreated, got)
+		}
+		if got, want := req.Header.Get(HeaderSignature), Sign(secret, receivedBodies[0]); got != want {
+			t.Errorf("want signature %q, got %q", want, got)
+		}
+
+		var payload Payload
+		if err := json.Unmarshal(receivedBodies[0], &payload); err != nil {
+			t.Fatalf("failed to decode payload: %q", err)
+		}
+		if payload.FeedEntry == nil || payload.FeedEntry.UID != "entry-new" {
+			t.Errorf("want payload for entry-new, got %+v", payload.FeedEntry)
+		}
+
+		if len(r.Deliveries) != 1 {
+			t.Fatalf("want 1 delivery, got %d", len(r.Deliveries))
+		}
+		if r.Deliveries[0].Status != DeliveryStatusSucceeded {
+			t.Errorf("want status %q, got %q", DeliveryStatusSucceeded, r.Deliveries[0].Status)
+		}
+		if r.Deliveries[0].ResponseStatusCode != http.StatusNoContent {
+			t.Errorf("want response status code %d, got %d", http.StatusNoContent, r.Deliveries[0].ResponseStatusCode)
+		}
+		for _, entry := range r.FeedEntries {
+			if !slices.Contains(r.QueuedFeedEntryUIDs, entry.UID) {
+				t.Errorf("want entry %q to be marked as queued", entry.UID)
+			}
+		}
+	})
+
+	t.Run("failed delivery is rescheduled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		r := &FakeRepository{
+			Webhooks: []Webhook{
+				{
+					UUID:     webhookUUID,
+					UserUUID: testUserUUID,
+					URL:      server.URL,
+					Secret:   secret,
+					Events:   []Event{EventBookmarkDeleted},
+					Active:   true,
+				},
+			},
+			Deliveries: []Delivery{
+				{
+					UUID:          "c0ffee00-0000-4000-8000-000000000001",
+					WebhookUUID:   webhookUUID,
+					Event:         EventBookmarkDeleted,
+					Payload:       []byte(`{}`),
+					Status:        DeliveryStatusPending,
+					NextAttemptAt: now.Add(-1 * time.Minute),
+				},
+			},
+		}
+
+		s := NewService(r, NewClient(server.Client(), "sparklemuffin/test"), nil)
+
+		if err := s.Dispatch(t.Context(), "test-job"); err != nil {
+			t.Fatalf("want no error, got %q", err)
+		}
+
+		d := r.Deliveries[0]
+		if d.Status != DeliveryStatusPending {
+			t.Errorf("want status %q, got %q", DeliveryStatusPending, d.Status)
+		}
+		if d.Attempts != 1 {
+			t.Errorf("want 1 attempt, got %d", d.Attempts)
+		}
+		if d.ResponseStatusCode != http.StatusServiceUnavailable {
+			t.Errorf("want response status code %d, got %d", http.StatusServiceUnavailable, d.ResponseStatusCode)
+		}
+		if d.Error == "" {
+			t.Error("want the error to be recorded")
+		}
+		if !d.NextAttemptAt.After(now) {
+			t.Errorf("want the next attempt to be scheduled in the future, got %s", d.NextAttemptAt)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 1 * time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 3, want: 4 * time.Minute},
+		{attempts: 7, want: 64 * time.Minute},
+		{attempts: 20, want: deliveryRetryMaxDelay},
+	}
+
+	for _, tc := range cases {
+		if got := retryDelay(tc.attempts); got != tc.want {
+			t.Errorf("retryDelay(%d): want %s, got %s", tc.attempts, tc.want, got)
+		}
+	}
+}
+
+func TestDeliveryRecordAttempt_MaxAttempts(t *testing.T) {
+	now := time.Now().UTC()
+	d := Delivery{
+		Status:   DeliveryStatusPending,
+		Attempts: MaxDeliveryAttempts - 1,
+	}
+
+	d.recordAttempt(now, 0, errors.New("connection refused"))
+
+	if d.Status != DeliveryStatusFailed {
+		t.Errorf("want status %q, got %q", DeliveryStatusFailed, d.Status)
+	}
+}
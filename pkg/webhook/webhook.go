@@ -0,0 +1,244 @@
+// Copyright VirtualTam 2022, 2026
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/virtualtam/sparklemuffin/internal/rand"
+)
+
+const (
+	secretLength = 32
+)
+
+var (
+	allowedURLSchemes = []string{"http", "https"}
+)
+
+// Webhook represents a user's subscription to outgoing event notifications,
+// delivered as signed JSON payloads to a remote HTTP endpoint.
+type Webhook struct {
+	UUID     string
+	UserUUID string
+
+	URL    string
+	Secret string
+	Events []Event
+
+	// FeedCategoryUUID optionally restricts EventFeedEntryCreated notifications
+	// to entries from subscriptions of a given feed.Category.
+	FeedCategoryUUID string
+
+	// FeedSubscriptionUUID optionally restricts EventFeedEntryCreated notifications
+	// to entries of a given feed.Subscription.
+	FeedSubscriptionUUID string
+
+	Active bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWebhook initializes and returns a new Webhook with a random UUID and secret.
+func NewWebhook(userUUID string) (Webhook, error) {
+	generatedUUID, err := uuid.NewRandom()
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	secret, err := rand.RandomBase64URLString(secretLength)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	now := time.Now().UTC()
+
+	return Webhook{
+		UUID:      generatedUUID.String(),
+		UserUUID:  userUUID,
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// HasEvent returns whether this Webhook is subscribed to a given Event.
+func (w *Webhook) HasEvent(event Event) bool {
+	return slices.Contains(w.Events, event)
+}
+
+// Normalize sanitizes and normalizes all fields.
+func (w *Webhook) Normalize() {
+	w.normalizeURL()
+	w.normalizeEvents()
+	w.normalizeFilters()
+}
+
+// ValidateForAddition ensures mandatory fields are properly set when adding a Webhook.
+func (w *Webhook) ValidateForAddition() error {
+	fns := []func() error{
+		w.requireUUID,
+		w.validateUUID,
+		w.requireUserUUID,
+		w.requireSecret,
+		w.requireURL,
+		w.ensureURLIsValid,
+		w.requireEvents,
+		w.ensureEventsAreKnown,
+		w.ensureFiltersAreValid,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+// ValidateForUpdate ensures mandatory fields are properly set when updating a Webhook.
+func (w *Webhook) ValidateForUpdate() error {
+	fns := []func() error{
+		w.requireUUID,
+		w.validateUUID,
+		w.requireUserUUID,
+		w.requireURL,
+		w.ensureURLIsValid,
+		w.requireEvents,
+		w.ensureEventsAreKnown,
+		w.ensureFiltersAreValid,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+// ValidateForDeletion ensures mandatory fields are properly set when deleting a Webhook.
+func (w *Webhook) ValidateForDeletion() error {
+	fns := []func() error{
+		w.requireUUID,
+		w.validateUUID,
+		w.requireUserUUID,
+	}
+
+	return runValidationFuncs(fns)
+}
+
+func (w *Webhook) normalizeEvents() {
+	slices.Sort(w.Events)
+	w.Events = slices.Compact(w.Events)
+}
+
+func (w *Webhook) normalizeFilters() {
+	w.FeedCategoryUUID = strings.TrimSpace(w.FeedCategoryUUID)
+	w.FeedSubscriptionUUID = strings.TrimSpace(w.FeedSubscriptionUUID)
+}
+
+func (w *Webhook) normalizeURL() {
+	w.URL = strings.TrimSpace(w.URL)
+}
+
+func (w *Webhook) ensureEventsAreKnown() error {
+	for _, event := range w.Events {
+		if !slices.Contains(AllEvents, event) {
+			return ErrEventUnknown
+		}
+	}
+
+	return nil
+}
+
+func (w *Webhook) ensureFiltersAreValid() error {
+	if w.FeedCategoryUUID != "" && w.FeedSubscriptionUUID != "" {
+		return ErrFilterConflict
+	}
+
+	if w.FeedCategoryUUID != "" {
+		if err := uuid.Validate(w.FeedCategoryUUID); err != nil {
+			return ErrFeedCategoryUUIDInvalid
+		}
+	}
+
+	if w.FeedSubscriptionUUID != "" {
+		if err := uuid.Validate(w.FeedSubscriptionUUID); err != nil {
+			return ErrFeedSubscriptionUUIDInvalid
+		}
+	}
+
+	return nil
+}
+
+func (w *Webhook) ensureURLIsValid() error {
+	parsedURL, err := url.Parse(w.URL)
+	if err != nil {
+		return ErrURLInvalid
+	}
+
+	if parsedURL.Scheme == "" {
+		return ErrURLNoScheme
+	}
+
+	if !slices.Contains(allowedURLSchemes, parsedURL.Scheme) {
+		return ErrURLUnsupportedScheme
+	}
+
+	if parsedURL.Host == "" {
+		return ErrURLNoHost
+	}
+
+	return nil
+}
+
+func (w *Webhook) requireEvents() error {
+	if len(w.Events) == 0 {
+		return ErrEventsRequired
+	}
+	return nil
+}
+
+func (w *Webhook) requireSecret() error {
+	if w.Secret == "" {
+		return ErrSecretRequired
+	}
+	return nil
+}
+
+func (w *Webhook) requireURL() error {
+	if w.URL == "" {
+		return ErrURLRequired
+	}
+	return nil
+}
+
+func (w *Webhook) requireUserUUID() error {
+	if w.UserUUID == "" {
+		return ErrUserUUIDRequired
+	}
+	return nil
+}
+
+func (w *Webhook) requireUUID() error {
+	if w.UUID == "" {
+		return ErrUUIDRequired
+	}
+	return nil
+}
+
+func (w *Webhook) validateUUID() error {
+	if err := uuid.Validate(w.UUID); err != nil {
+		return ErrUUIDInvalid
+	}
+	return nil
+}
+
+func runValidationFuncs(fns []func() error) error {
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}